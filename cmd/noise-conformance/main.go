@@ -0,0 +1,69 @@
+// Command noise-conformance runs the protocol conformance suite against an
+// implementation listening at a target address, and exports the golden vectors.
+//
+//	noise-conformance -target tcp://127.0.0.1:3000
+//	noise-conformance -export vectors.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/conformance"
+)
+
+func main() {
+	// glog defaults to logging to a file, override this flag to log to console.
+	flag.Set("logtostderr", "true")
+	flag.Set("stderrthreshold", "ERROR")
+
+	targetFlag := flag.String("target", "", "address of the implementation to check")
+	hostFlag := flag.String("host", "127.0.0.1", "host to run checks from")
+	portFlag := flag.Uint("port", 3100, "port to run checks from, with the following ports used by peers looked up")
+	timeoutFlag := flag.Duration("timeout", conformance.DefaultTimeout, "how long each check waits on the target")
+	exportFlag := flag.String("export", "", "path to export the golden vectors to")
+	flag.Parse()
+
+	if len(*exportFlag) > 0 {
+		vectors, err := conformance.Generate()
+		if err == nil {
+			err = conformance.WriteVectors(*exportFlag, vectors)
+		}
+
+		if err != nil {
+			glog.Fatal(err)
+		}
+	}
+
+	if len(*targetFlag) == 0 {
+		if len(*exportFlag) == 0 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		return
+	}
+
+	suite := &conformance.Suite{
+		Target:  *targetFlag,
+		Host:    *hostFlag,
+		Port:    uint16(*portFlag),
+		Timeout: *timeoutFlag,
+	}
+
+	failed := false
+
+	for _, result := range suite.Run() {
+		if result.Passed() {
+			fmt.Printf("PASS %s\n", result.Name)
+		} else {
+			fmt.Printf("FAIL %s: %s\n", result.Name, result.Err)
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/hd"
+	"github.com/perlin-network/noise/crypto/mnemonic"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/pkg/errors"
+)
+
+// Config configures a node.
+type Config struct {
+	Protocol string `json:"protocol"`
+	Host     string `json:"host"`
+	Port     uint16 `json:"port"`
+
+	// KeyFile holds the Ed25519 private key of the node either hex-encoded, as a PEM
+	// block, or as a JSON Web Key, and is created with a random key should it not exist.
+	KeyFile string `json:"key_file"`
+
+	// Mnemonic is a BIP39 mnemonic the node's key is derived from in place of KeyFile,
+	// with Identity selecting which of the identities derived from it to run as.
+	Mnemonic string `json:"mnemonic"`
+	Identity uint32 `json:"identity"`
+
+	// Peers are the seed addresses bootstrapped off of.
+	Peers []string `json:"peers"`
+
+	// Seeds are seeds bootstrapped off of alongside Peers, labelled with weights and
+	// regions. Seeds of Regions are preferred in order, nearest first.
+	Seeds   []network.Seed `json:"seeds"`
+	Regions []string       `json:"regions"`
+
+	// MinSeeds is the number of seeds which must be connected to on startup.
+	MinSeeds int `json:"min_seeds"`
+
+	// Zone labels the datacenter or availability zone the node runs in. Broadcasts
+	// prefer peers across zones, or within the node's own zone should ZoneLocality be set.
+	Zone         string `json:"zone"`
+	ZoneLocality bool   `json:"zone_locality"`
+
+	MinPeers int `json:"min_peers"`
+	MaxPeers int `json:"max_peers"`
+
+	// AdminAddress serves the admin API, BridgeAddress the HTTP/JSON bridge, and
+	// MetricsAddress the latency histograms of messages. Empty if disabled.
+	AdminAddress   string `json:"admin_address"`
+	BridgeAddress  string `json:"bridge_address"`
+	MetricsAddress string `json:"metrics_address"`
+
+	// BridgeToken is the bearer token requests to the HTTP/JSON bridge must present,
+	// and BridgeTypes the message types which may be published through it.
+	BridgeToken string   `json:"bridge_token"`
+	BridgeTypes []string `json:"bridge_types"`
+
+	UPnP      bool `json:"upnp"`
+	Reconnect bool `json:"reconnect"`
+
+	// Protect enables the default message protection pipeline.
+	Protect bool `json:"protect"`
+}
+
+// DefaultConfig returns the configuration of a node run without a configuration file.
+func DefaultConfig() *Config {
+	return &Config{
+		Protocol: "tcp",
+		Host:     "localhost",
+		Port:     3000,
+		KeyFile:  "noise.key",
+		MinSeeds: 1,
+		MinPeers: 8,
+		MaxPeers: 64,
+		Protect:  true,
+	}
+}
+
+// BootstrapSeeds returns the seeds bootstrapped off of, being Seeds followed by Peers
+// with neither a weight nor region.
+func (config *Config) BootstrapSeeds() []network.Seed {
+	seeds := append([]network.Seed(nil), config.Seeds...)
+	for _, address := range config.Peers {
+		seeds = append(seeds, network.Seed{Address: address})
+	}
+	return seeds
+}
+
+// LoadConfig loads a JSON configuration file over the default configuration.
+func LoadConfig(path string) (*Config, error) {
+	config := DefaultConfig()
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config file %s", path)
+	}
+
+	if err := json.Unmarshal(raw, config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse config file %s", path)
+	}
+
+	return config, nil
+}
+
+// LoadKeys loads the private key of a node from a key file, creating the file with a
+// random key should it not exist.
+func LoadKeys(path string) (*crypto.KeyPair, error) {
+	raw, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		keys := ed25519.RandomKeyPair()
+
+		if err := ioutil.WriteFile(path, []byte(keys.PrivateKeyHex()+"\n"), 0600); err != nil {
+			return nil, errors.Wrapf(err, "failed to write key file %s", path)
+		}
+
+		return keys, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read key file %s", path)
+	}
+
+	keys, err := ed25519.ParseKeyPair(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid key file %s", path)
+	}
+
+	return keys, nil
+}
+
+// DeriveKeys derives the private key of a node from a BIP39 mnemonic, such that the
+// node's identity may be recovered from the mnemonic alone.
+func DeriveKeys(words string, identity uint32) (*crypto.KeyPair, error) {
+	seed, err := mnemonic.Seed(words, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid mnemonic")
+	}
+
+	return hd.DeriveKeyPair(seed, hd.IdentityPath(identity))
+}
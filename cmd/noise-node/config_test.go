@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "noise-node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "node.json")
+	if err := ioutil.WriteFile(path, []byte(`{"port": 4000, "peers": ["tcp://127.0.0.1:3000"], "seeds": [{"address": "tcp://127.0.0.1:3001", "weight": 2, "region": "eu"}], "regions": ["eu"], "zone": "eu-west-1a", "zone_locality": true}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Port != 4000 || len(config.Peers) != 1 || config.Protocol != "tcp" || config.Zone != "eu-west-1a" || !config.ZoneLocality {
+		t.Fatalf("unexpected config %+v", config)
+	}
+
+	seeds := config.BootstrapSeeds()
+	if len(seeds) != 2 || seeds[0].Region != "eu" || seeds[0].Weight != 2 || seeds[1].Address != "tcp://127.0.0.1:3000" {
+		t.Fatalf("unexpected bootstrap seeds %+v", seeds)
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "noise-node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "noise.key")
+
+	created, err := LoadKeys(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadKeys(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.PublicKeyHex() != loaded.PublicKeyHex() {
+		t.Fatal("expected keys to be loaded from the created key file")
+	}
+}
+
+func TestDeriveKeys(t *testing.T) {
+	words := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+
+	first, err := DeriveKeys(words, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recovered, err := DeriveKeys(words, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := DeriveKeys(words, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.PrivateKeyHex() != recovered.PrivateKeyHex() || first.PublicKeyHex() == second.PublicKeyHex() {
+		t.Fatal("expected identities to be recoverable and distinct per index")
+	}
+
+	if _, err := DeriveKeys("legal winner", 0); err == nil {
+		t.Fatal("expected an invalid mnemonic to be rejected")
+	}
+}
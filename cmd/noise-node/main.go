@@ -0,0 +1,213 @@
+// Command noise-node runs a bootstrap and relay node, configured by a JSON file and
+// flags, for operators to run without writing Go code.
+//
+//	noise-node -config node.json -port 3000 -peers tcp://seed.example.com:3000
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/admin"
+	"github.com/perlin-network/noise/network/backoff"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/httpbridge"
+	"github.com/perlin-network/noise/network/metrics"
+	"github.com/perlin-network/noise/network/nat"
+	"github.com/perlin-network/noise/network/protection"
+	"github.com/perlin-network/noise/network/pubsub"
+	"github.com/perlin-network/noise/peer"
+)
+
+func main() {
+	// glog defaults to logging to a file, override this flag to log to console.
+	flag.Set("logtostderr", "true")
+
+	configFlag := flag.String("config", "", "path to a JSON config file")
+	portFlag := flag.Int("port", 0, "port to listen on, overriding the config")
+	hostFlag := flag.String("host", "", "host to listen on, overriding the config")
+	peersFlag := flag.String("peers", "", "comma-separated seed addresses, overriding the config")
+	adminFlag := flag.String("admin", "", "address to serve the admin API on, overriding the config")
+	metricsFlag := flag.String("metrics", "", "address to serve latency metrics on, overriding the config")
+	flag.Parse()
+
+	config := DefaultConfig()
+
+	if len(*configFlag) > 0 {
+		var err error
+
+		config, err = LoadConfig(*configFlag)
+		if err != nil {
+			glog.Fatal(err)
+		}
+	}
+
+	if *portFlag > 0 {
+		config.Port = uint16(*portFlag)
+	}
+
+	if len(*hostFlag) > 0 {
+		config.Host = *hostFlag
+	}
+
+	if len(*peersFlag) > 0 {
+		config.Peers = strings.Split(*peersFlag, ",")
+	}
+
+	if len(*adminFlag) > 0 {
+		config.AdminAddress = *adminFlag
+	}
+
+	if len(*metricsFlag) > 0 {
+		config.MetricsAddress = *metricsFlag
+	}
+
+	if err := run(config); err != nil {
+		glog.Fatal(err)
+	}
+
+	glog.Flush()
+}
+
+func run(config *Config) error {
+	var keys *crypto.KeyPair
+	var err error
+
+	if len(config.Mnemonic) > 0 {
+		keys, err = DeriveKeys(config.Mnemonic, config.Identity)
+	} else {
+		keys, err = LoadKeys(config.KeyFile)
+	}
+
+	if err != nil {
+		return err
+	}
+	defer keys.Close()
+
+	if err := keys.Lock(); err != nil {
+		glog.Warningf("Failed to lock private key memory [err=%s]", err)
+	}
+
+	glog.Infof("Public Key: %s", keys.PublicKeyHex())
+	glog.Infof("Peer ID: %s", peer.CreateID("", keys.PublicKey))
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(keys)
+	builder.SetAddress(network.FormatAddress(config.Protocol, config.Host, config.Port))
+
+	if len(config.Zone) > 0 {
+		preference := network.ZoneDiversity
+		if config.ZoneLocality {
+			preference = network.ZoneLocality
+		}
+
+		builder.SetZone(config.Zone, preference)
+	}
+
+	if config.UPnP {
+		if err := nat.RegisterPlugin(builder); err != nil {
+			return err
+		}
+	}
+
+	if config.Reconnect {
+		if err := builder.AddPlugin(&backoff.Plugin{Pacer: backoff.DefaultPacer()}); err != nil {
+			return err
+		}
+	}
+
+	if config.Protect {
+		protection.Register(builder)
+	}
+
+	// Keep latency histograms of sending and handling messages.
+	latencies := metrics.New()
+	if err := builder.AddPlugin(latencies); err != nil {
+		return err
+	}
+
+	if err := builder.AddPlugin(&discovery.Plugin{
+		MinPeers: config.MinPeers,
+		MaxPeers: config.MaxPeers,
+	}); err != nil {
+		return err
+	}
+
+	// Relay publications to all topics across the network.
+	if err := builder.AddPlugin(pubsub.New()); err != nil {
+		return err
+	}
+
+	net, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	if err := net.Start(); err != nil {
+		return err
+	}
+
+	if len(config.AdminAddress) > 0 {
+		go func() {
+			glog.Infof("Serving admin API on %s.", config.AdminAddress)
+
+			if err := admin.ListenAndServe(config.AdminAddress, net); err != nil {
+				glog.Error(err)
+			}
+		}()
+	}
+
+	if len(config.MetricsAddress) > 0 {
+		go func() {
+			glog.Infof("Serving metrics on %s.", config.MetricsAddress)
+
+			if err := http.ListenAndServe(config.MetricsAddress, latencies); err != nil {
+				glog.Error(err)
+			}
+		}()
+	}
+
+	if len(config.BridgeAddress) > 0 {
+		go func() {
+			glog.Infof("Serving HTTP/JSON bridge on %s.", config.BridgeAddress)
+
+			if err := httpbridge.ListenAndServe(config.BridgeAddress, net, httpbridge.Options{Token: config.BridgeToken, Types: config.BridgeTypes}); err != nil {
+				glog.Error(err)
+			}
+		}()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if seeds := config.BootstrapSeeds(); len(seeds) > 0 {
+		go func() {
+			options := network.BootstrapOptions{MinSeeds: config.MinSeeds, Regions: config.Regions}
+
+			if err := net.BootstrapSeeds(ctx, options, seeds...); err != nil {
+				glog.Warning(err)
+			}
+		}()
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	<-signals
+
+	glog.Info("Shutting down.")
+
+	cancel()
+	net.Close()
+
+	return nil
+}
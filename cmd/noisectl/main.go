@@ -0,0 +1,113 @@
+// Command noisectl inspects a running network.
+//
+//	noisectl crawl -seeds tcp://seed.example.com:3000 -out snapshot.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/crawler"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: noisectl <command> [flags]\n\ncommands:\n  crawl\twalk the network from seed nodes, and output a JSON snapshot of it\n")
+	os.Exit(2)
+}
+
+func main() {
+	// glog defaults to logging to a file, override this flag to log to console.
+	flag.Set("logtostderr", "true")
+	flag.Set("stderrthreshold", "ERROR")
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "crawl":
+		err = crawl(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		glog.Fatal(err)
+	}
+
+	glog.Flush()
+}
+
+func crawl(args []string) error {
+	flags := flag.NewFlagSet("crawl", flag.ExitOnError)
+
+	seedsFlag := flags.String("seeds", "", "comma-separated seed addresses to crawl from")
+	protocolFlag := flags.String("protocol", "tcp", "protocol to crawl over")
+	hostFlag := flags.String("host", "localhost", "host peers dial the crawler back at")
+	portFlag := flags.Uint("port", 3200, "port peers dial the crawler back at")
+	concurrencyFlag := flags.Int("concurrency", crawler.DefaultConcurrency, "number of peers crawled at once")
+	timeoutFlag := flags.Duration("timeout", crawler.DefaultTimeout, "how long each peer is waited on")
+	maxPeersFlag := flags.Int("max-peers", 0, "number of peers crawled at most; unlimited if zero")
+	durationFlag := flags.Duration("duration", 5*time.Minute, "how long the crawl may take at most")
+	outFlag := flags.String("out", "", "path to write the snapshot to; stdout if empty")
+	flags.Parse(args)
+
+	if len(*seedsFlag) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress(*protocolFlag, *hostFlag, uint16(*portFlag)))
+
+	net, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	if err := net.Start(); err != nil {
+		return err
+	}
+	defer net.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *durationFlag)
+	defer cancel()
+
+	snapshot, err := crawler.Crawl(ctx, net, crawler.Options{
+		Seeds:       strings.Split(*seedsFlag, ","),
+		Concurrency: *concurrencyFlag,
+		Timeout:     *timeoutFlag,
+		MaxPeers:    *maxPeersFlag,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+
+	if len(*outFlag) > 0 {
+		if out, err = os.Create(*outFlag); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	glog.Infof("Crawled %d peers, %d of which were reachable.", len(snapshot.Peers), snapshot.Reachable())
+
+	if fingerprints := snapshot.ConfigFingerprints(); len(fingerprints) > 1 {
+		glog.Warningf("Peers are running %d distinct configs: %v", len(fingerprints), fingerprints)
+	}
+
+	return snapshot.WriteJSON(out)
+}
@@ -0,0 +1,19 @@
+// Package conformance is a protocol conformance kit, for ports of noise to other
+// languages to verify that they are compatible with this implementation.
+//
+// The kit comes in two halves. Golden vectors of signed envelopes, DHT lookups and
+// message transcripts are published to testdata/vectors.json, for implementations to
+// check their encodings against byte for byte in their own test suites. The vectors
+// are regenerated with:
+//
+//	go test ./conformance -update
+//
+// A Suite additionally runs checks over the wire against a running implementation,
+// which must run discovery and answer echoes. It is run with the noise-conformance
+// command:
+//
+//	go run github.com/perlin-network/noise/cmd/noise-conformance -target tcp://127.0.0.1:3000
+//
+// Messages are framed as per Frame, and sent over smux streams multiplexed atop each
+// connection. Implementations must hence speak smux v1 to pass the suite.
+package conformance
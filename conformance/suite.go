@@ -0,0 +1,299 @@
+package conformance
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultTimeout is how long each check waits on the implementation under test by default.
+	DefaultTimeout = 5 * time.Second
+
+	// lookupHelpers is the number of peers bootstrapped to the implementation under
+	// test for it to return in lookups.
+	lookupHelpers = 3
+)
+
+// Suite runs conformance checks over the wire against an implementation listening at
+// a target address.
+type Suite struct {
+	// Target is the address of the implementation under test.
+	Target string
+
+	// Host and Port are the address checks are run from. Peers looked up through the
+	// target listen on the ports following Port.
+	Host string
+	Port uint16
+
+	// Timeout is how long each check waits on the target. DefaultTimeout if zero.
+	Timeout time.Duration
+}
+
+// Result is the outcome of a check. Err is nil should the check have passed.
+type Result struct {
+	Name string
+	Err  error
+}
+
+// Passed returns true should the check have passed.
+func (r Result) Passed() bool {
+	return r.Err == nil
+}
+
+func (s *Suite) timeout() time.Duration {
+	if s.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return s.Timeout
+}
+
+// buildNode builds and starts a node for the suite to check the target from.
+func (s *Suite) buildNode(port uint16, plugins ...network.PluginInterface) (*network.Network, error) {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", s.Host, port))
+
+	for _, plugin := range plugins {
+		builder.AddPlugin(plugin)
+	}
+
+	node, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := node.Start(); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Run runs every check against the target, in the order of the golden transcripts.
+func (s *Suite) Run() []Result {
+	pongs := &pongRecorder{pongs: make(chan receivedPong, 16)}
+
+	node, err := s.buildNode(s.Port, pongs)
+	if err != nil {
+		return []Result{{Name: "setup", Err: err}}
+	}
+	defer node.Close()
+
+	checks := []struct {
+		name  string
+		check func(node *network.Network) error
+	}{
+		{"handshake", func(node *network.Network) error { return s.checkHandshake(node, pongs) }},
+		{"lookup", s.checkLookup},
+		{"echo", s.checkEcho},
+		{"invalid_signature", s.checkInvalidSignature},
+	}
+
+	results := make([]Result, 0, len(checks))
+
+	for _, c := range checks {
+		results = append(results, Result{Name: c.name, Err: c.check(node)})
+	}
+
+	return results
+}
+
+// pongRecorder records the pongs a node receives.
+type pongRecorder struct {
+	*network.Plugin
+
+	pongs chan receivedPong
+}
+
+type receivedPong struct {
+	pong *protobuf.Pong
+
+	// address is the address of the connection the pong was received over, and
+	// claimed the address the sender claims.
+	address string
+	claimed string
+}
+
+func (r *pongRecorder) Receive(ctx *network.PluginContext) error {
+	if pong, ok := ctx.Message().(*protobuf.Pong); ok {
+		select {
+		case r.pongs <- receivedPong{pong: pong, address: ctx.Client().Address(), claimed: ctx.Sender().Address}:
+		default:
+		}
+	}
+	return nil
+}
+
+// checkHandshake pings the target, which must respond with a pong echoing the ping.
+func (s *Suite) checkHandshake(node *network.Network, pongs *pongRecorder) error {
+	client, err := node.Client(s.Target)
+	if err != nil {
+		return errors.Wrap(err, "failed to dial target")
+	}
+
+	timestamp := time.Now().UnixNano()
+
+	if _, err := client.Tell(&protobuf.Ping{Timestamp: timestamp}); err != nil {
+		return errors.Wrap(err, "failed to ping target")
+	}
+
+	timeout := time.After(s.timeout())
+
+	for {
+		select {
+		case received := <-pongs.pongs:
+			if received.address != client.Address() || received.pong.PingTimestamp != timestamp {
+				continue
+			}
+
+			target, err := network.ToUnifiedAddress(s.Target)
+			if err != nil {
+				return err
+			}
+
+			if claimed, err := network.ToUnifiedAddress(received.claimed); err != nil || claimed != target {
+				return errors.Errorf("pong claims address %q instead of %q", received.claimed, target)
+			}
+
+			return nil
+		case <-timeout:
+			return errors.New("target never responded to ping with a pong")
+		}
+	}
+}
+
+// checkLookup bootstraps peers to the target, and looks them up through the target,
+// which must respond with the peers closest to each lookup's target.
+func (s *Suite) checkLookup(node *network.Network) error {
+	var helpers []*network.Network
+
+	defer func() {
+		for _, helper := range helpers {
+			helper.Close()
+		}
+	}()
+
+	for i := 1; i <= lookupHelpers; i++ {
+		helper, err := s.buildNode(s.Port+uint16(i), new(discovery.Plugin))
+		if err != nil {
+			return errors.Wrap(err, "failed to start peer to look up")
+		}
+		helpers = append(helpers, helper)
+
+		if err := helper.Bootstrap(s.Target); err != nil {
+			return errors.Wrap(err, "failed to bootstrap peer to target")
+		}
+	}
+
+	client, err := node.Client(s.Target)
+	if err != nil {
+		return errors.Wrap(err, "failed to dial target")
+	}
+
+	deadline := time.Now().Add(s.timeout())
+
+	for _, helper := range helpers {
+		for {
+			err := s.lookup(client, helper.ID)
+			if err == nil {
+				break
+			}
+
+			if time.Now().After(deadline) {
+				return err
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	return nil
+}
+
+// lookup looks up a peer through the target, which must respond with the peer first
+// and the rest of its peers sorted by their distance to the peer.
+func (s *Suite) lookup(client *network.PeerClient, id peer.ID) error {
+	target := protobuf.ID(id)
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.LookupNodeRequest{Target: &target})
+	request.SetTimeout(s.timeout())
+
+	response, err := client.Request(request)
+	if err != nil {
+		return errors.Wrap(err, "target never responded to lookup")
+	}
+
+	res, ok := response.(*protobuf.LookupNodeResponse)
+	if !ok {
+		return errors.Errorf("expected lookup node response, got %T", response)
+	}
+
+	if len(res.Peers) > dht.BucketSize {
+		return errors.Errorf("expected at most %d peers in lookup response, got %d", dht.BucketSize, len(res.Peers))
+	}
+
+	if len(res.Peers) == 0 || !peer.ID(*res.Peers[0]).Equals(id) {
+		return errors.Errorf("expected lookup of %s to return the peer first", id.Address)
+	}
+
+	for i := 1; i < len(res.Peers); i++ {
+		if peer.CompareDistance(id, peer.ID(*res.Peers[i-1]), peer.ID(*res.Peers[i])) > 0 {
+			return errors.Errorf("expected lookup of %s to return peers from closest to furthest", id.Address)
+		}
+	}
+
+	return nil
+}
+
+// checkEcho echoes the target, which must reply echoing our timestamp.
+func (s *Suite) checkEcho(node *network.Network) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout())
+	defer cancel()
+
+	_, err := node.Ping(ctx, s.Target)
+	return err
+}
+
+// checkInvalidSignature sends the target an echo with an invalid signature, which
+// must be dropped without reply.
+func (s *Suite) checkInvalidSignature(node *network.Network) error {
+	client, err := node.Client(s.Target)
+	if err != nil {
+		return errors.Wrap(err, "failed to dial target")
+	}
+
+	signed, err := node.PrepareMessage(&protobuf.Echo{Timestamp: time.Now().UnixNano()})
+	if err != nil {
+		return err
+	}
+
+	signed.Signature[0] ^= 1
+	signed.RequestNonce = ^uint64(0)
+
+	reply := make(chan proto.Message, 1)
+	client.Requests.Store(signed.RequestNonce, reply)
+	defer client.Requests.Delete(signed.RequestNonce)
+
+	if err := node.Write(client.Address(), signed); err != nil {
+		return errors.Wrap(err, "failed to send echo")
+	}
+
+	select {
+	case <-reply:
+		return errors.New("target replied to an echo with an invalid signature")
+	case <-time.After(s.timeout() / 2):
+		return nil
+	}
+}
@@ -0,0 +1,26 @@
+package conformance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func TestSuite(t *testing.T) {
+	suite := &Suite{Host: "127.0.0.1", Port: 13214, Timeout: 2 * time.Second}
+
+	target, err := suite.buildNode(13213, new(discovery.Plugin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+
+	suite.Target = target.Address
+
+	for _, result := range suite.Run() {
+		if !result.Passed() {
+			t.Errorf("check %s failed: %s", result.Name, result.Err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package conformance
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+const (
+	// Initiator is the party to a transcript which dialed the connection.
+	Initiator = "initiator"
+
+	// Responder is the party to a transcript which accepted the connection.
+	Responder = "responder"
+)
+
+// Transcript is the sequence of messages exchanged between two peers in an exchange.
+type Transcript struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// Step is a message sent by one party to a transcript.
+type Step struct {
+	// From is the party sending the message, being either Initiator or Responder.
+	From string `json:"from"`
+
+	TypeURL string `json:"type_url"`
+
+	// Reply is whether the message is a reply, carrying the request nonce of the
+	// message prior. Requests carry a non-zero request nonce.
+	Reply bool `json:"reply,omitempty"`
+
+	// Optional is whether the message may not be sent.
+	Optional bool `json:"optional,omitempty"`
+
+	// Expect describes what the message's fields must satisfy.
+	Expect string `json:"expect,omitempty"`
+}
+
+// typeURL returns the type URL a message is packed into an envelope under.
+func typeURL(message proto.Message) string {
+	return "type.googleapis.com/" + proto.MessageName(message)
+}
+
+// Transcripts returns the golden transcripts of the exchanges checked by Suite.
+func Transcripts() []Transcript {
+	return []Transcript{
+		{
+			Name: "handshake",
+			Steps: []Step{
+				{
+					From:    Initiator,
+					TypeURL: typeURL(&protobuf.Ping{}),
+					Expect:  "timestamp is the initiator's clock in nanoseconds since the Unix epoch",
+				},
+				{
+					From:     Responder,
+					TypeURL:  typeURL(&protobuf.IdentityChallenge{}),
+					Optional: true,
+					Expect:   "sent over a new connection dialed back to the initiator's claimed address, with a random nonce",
+				},
+				{
+					From:     Initiator,
+					TypeURL:  typeURL(&protobuf.IdentityResponse{}),
+					Optional: true,
+					Expect:   "nonce echoes the challenge's nonce, signed by the initiator's key pair",
+				},
+				{
+					From:    Responder,
+					TypeURL: typeURL(&protobuf.Pong{}),
+					Expect:  "ping_timestamp echoes the ping's timestamp, and the sender's address is the one dialed",
+				},
+			},
+		},
+		{
+			Name: "lookup",
+			Steps: []Step{
+				{
+					From:    Initiator,
+					TypeURL: typeURL(&protobuf.LookupNodeRequest{}),
+				},
+				{
+					From:    Responder,
+					TypeURL: typeURL(&protobuf.LookupNodeResponse{}),
+					Reply:   true,
+					Expect:  "peers are the verified peers closest to the target by XOR distance, from closest to furthest",
+				},
+			},
+		},
+		{
+			Name: "echo",
+			Steps: []Step{
+				{
+					From:    Initiator,
+					TypeURL: typeURL(&protobuf.Echo{}),
+				},
+				{
+					From:    Responder,
+					TypeURL: typeURL(&protobuf.EchoReply{}),
+					Reply:   true,
+					Expect:  "echo_timestamp echoes the echo's timestamp",
+				},
+			},
+		},
+		{
+			Name: "invalid_signature",
+			Steps: []Step{
+				{
+					From:    Initiator,
+					TypeURL: typeURL(&protobuf.Echo{}),
+					Expect:  "signature is invalid, such that the echo must be dropped without reply",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,424 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/hashing/blake2b"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// vectorAddress is the address the senders of envelope vectors claim.
+const vectorAddress = "tcp://127.0.0.1:3000"
+
+// Vectors are the golden vectors implementations are to reproduce.
+type Vectors struct {
+	Envelopes   []EnvelopeVector `json:"envelopes"`
+	DHT         []DHTVector      `json:"dht"`
+	Transcripts []Transcript     `json:"transcripts"`
+}
+
+// EnvelopeVector is a message envelope along with each of its intermediate encodings.
+// All byte fields are hex-encoded.
+type EnvelopeVector struct {
+	Name string `json:"name"`
+
+	// Seed is the ed25519 seed of the key pair the envelope was signed with.
+	Seed      string `json:"seed"`
+	PublicKey string `json:"public_key"`
+	Address   string `json:"address"`
+
+	// ID is the random identifier the sender assigned the envelope.
+	ID string `json:"id"`
+
+	// TypeURL and Payload are the type URL and serialized message of the envelope's Any.
+	TypeURL string `json:"type_url"`
+	Payload string `json:"payload"`
+
+	// LamportTimestamp is the sender's logical clock at the time of sending.
+	LamportTimestamp uint64 `json:"lamport_timestamp"`
+
+	// Domain is the signing domain of the envelope's message type.
+	Domain string `json:"domain"`
+
+	// SigningPayload is the serialization of the sender, ID, type URL, Lamport timestamp
+	// and payload tagged with the domain, and Digest its blake2b-256 digest which the signature is of.
+	SigningPayload string `json:"signing_payload"`
+	Digest         string `json:"digest"`
+	Signature      string `json:"signature"`
+
+	// Envelope is the serialized protobuf.Message, and Frame the envelope as written
+	// to a stream.
+	Envelope string `json:"envelope"`
+	Frame    string `json:"frame"`
+
+	// Valid is whether receivers must accept the envelope.
+	Valid bool `json:"valid"`
+}
+
+// DHTVector is a lookup of the peers closest to a target by XOR distance. All IDs are
+// hex-encoded public keys.
+type DHTVector struct {
+	Name   string   `json:"name"`
+	Target string   `json:"target"`
+	Peers  []string `json:"peers"`
+	Count  int      `json:"count"`
+
+	// Closest are the count peers closest to the target, from closest to furthest.
+	Closest []string `json:"closest"`
+
+	// Buckets are the indices of the routing table buckets each peer falls into within
+	// the target's routing table, being the length of their common prefix.
+	Buckets []int `json:"buckets"`
+}
+
+// Frame prefixes a serialized envelope with its size as an unsigned varint, padded
+// with zeros to a fixed 10 bytes.
+func Frame(envelope []byte) []byte {
+	frame := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(envelope))
+	binary.PutUvarint(frame, uint64(len(envelope)))
+
+	return append(frame, envelope...)
+}
+
+// SigningPayload serializes the sender, ID, type URL, Lamport timestamp and payload of
+// an envelope into the bytes its signature is of: the domain followed by a zero byte,
+// then the sender's address and public key, the ID and the type URL, each prefixed with
+// its length as a little-endian uint32, then the Lamport timestamp as a little-endian
+// uint64, followed by the payload.
+func SigningPayload(domain string, sender *protobuf.ID, id []byte, typeURL string, lamport uint64, payload []byte) []byte {
+	var buffer bytes.Buffer
+
+	binary.Write(&buffer, binary.LittleEndian, uint32(len(sender.Address)))
+	buffer.WriteString(sender.Address)
+
+	binary.Write(&buffer, binary.LittleEndian, uint32(len(sender.PublicKey)))
+	buffer.Write(sender.PublicKey)
+
+	binary.Write(&buffer, binary.LittleEndian, uint32(len(id)))
+	buffer.Write(id)
+
+	binary.Write(&buffer, binary.LittleEndian, uint32(len(typeURL)))
+	buffer.WriteString(typeURL)
+
+	binary.Write(&buffer, binary.LittleEndian, lamport)
+
+	buffer.Write(payload)
+
+	return crypto.DomainSeparate(domain, buffer.Bytes())
+}
+
+// seed returns a deterministic ed25519 seed.
+func seed(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+// Generate generates the golden vectors.
+func Generate() (*Vectors, error) {
+	envelopes, err := generateEnvelopes()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Vectors{
+		Envelopes:   envelopes,
+		DHT:         generateDHT(),
+		Transcripts: Transcripts(),
+	}, nil
+}
+
+func generateEnvelopes() ([]EnvelopeVector, error) {
+	target := protobuf.ID(peer.CreateID(vectorAddress, ed25519.KeyPairFromSeed(seed(2)).PublicKey))
+	impostor := ed25519.KeyPairFromSeed(seed(3)).PublicKey
+
+	cases := []struct {
+		name    string
+		message proto.Message
+		tamper  func(msg *protobuf.Message)
+	}{
+		{name: "ping", message: &protobuf.Ping{Timestamp: 1}},
+		{name: "lookup_node_request", message: &protobuf.LookupNodeRequest{Target: &target}},
+		{name: "bytes", message: &protobuf.Bytes{Data: []byte("hello")}},
+		{name: "tampered_signature", message: &protobuf.Ping{Timestamp: 1}, tamper: func(msg *protobuf.Message) {
+			msg.Signature[0] ^= 1
+		}},
+		{name: "tampered_payload", message: &protobuf.Ping{Timestamp: 1}, tamper: func(msg *protobuf.Message) {
+			msg.Message.Value, _ = proto.Marshal(&protobuf.Ping{Timestamp: 2})
+		}},
+		{name: "tampered_id", message: &protobuf.Ping{Timestamp: 1}, tamper: func(msg *protobuf.Message) {
+			msg.Id[0] ^= 1
+		}},
+		{name: "impersonated_sender", message: &protobuf.Ping{Timestamp: 1}, tamper: func(msg *protobuf.Message) {
+			msg.Sender.PublicKey = impostor
+		}},
+		{name: "cross_domain", message: &protobuf.Ping{Timestamp: 1}, tamper: func(msg *protobuf.Message) {
+			msg.Message.TypeUrl = "type.googleapis.com/protobuf.Bytes"
+		}},
+	}
+
+	vectors := make([]EnvelopeVector, 0, len(cases))
+
+	for _, c := range cases {
+		vector, err := generateEnvelope(c.name, c.message, c.tamper)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate envelope vector %s", c.name)
+		}
+		vectors = append(vectors, vector)
+	}
+
+	return vectors, nil
+}
+
+// generateEnvelope signs a message with a network, such that vectors are of what this
+// implementation actually sends.
+func generateEnvelope(name string, message proto.Message, tamper func(msg *protobuf.Message)) (EnvelopeVector, error) {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.KeyPairFromSeed(seed(1)))
+	builder.SetAddress(vectorAddress)
+
+	net, err := builder.Build()
+	if err != nil {
+		return EnvelopeVector{}, err
+	}
+
+	// Draw message IDs from a fixed stream, such that vectors are reproducible.
+	net.Entropy = bytes.NewReader(seed(4))
+
+	msg, err := net.PrepareMessage(message)
+	if err != nil {
+		return EnvelopeVector{}, err
+	}
+
+	if tamper != nil {
+		tamper(msg)
+	}
+
+	envelope, err := proto.Marshal(msg)
+	if err != nil {
+		return EnvelopeVector{}, err
+	}
+
+	domain := network.EnvelopeDomain(msg.Message)
+	signingPayload := SigningPayload(domain, msg.Sender, msg.Id, msg.Message.TypeUrl, msg.LamportTimestamp, msg.Message.Value)
+
+	return EnvelopeVector{
+		Name:             name,
+		Seed:             hex.EncodeToString(seed(1)),
+		PublicKey:        hex.EncodeToString(msg.Sender.PublicKey),
+		Address:          msg.Sender.Address,
+		ID:               hex.EncodeToString(msg.Id),
+		TypeURL:          msg.Message.TypeUrl,
+		Payload:          hex.EncodeToString(msg.Message.Value),
+		LamportTimestamp: msg.LamportTimestamp,
+		Domain:           domain,
+		SigningPayload:   hex.EncodeToString(signingPayload),
+		Digest:           hex.EncodeToString(blake2b.New().HashBytes(signingPayload)),
+		Signature:        hex.EncodeToString(msg.Signature),
+		Envelope:         hex.EncodeToString(envelope),
+		Frame:            hex.EncodeToString(Frame(envelope)),
+		Valid:            tamper == nil,
+	}, nil
+}
+
+func generateDHT() []DHTVector {
+	var peers []peer.ID
+	for i := 0; i < 16; i++ {
+		peers = append(peers, peer.CreateID(vectorAddress, ed25519.KeyPairFromSeed(seed(byte(0x10+i))).PublicKey))
+	}
+
+	target := peer.CreateID(vectorAddress, ed25519.KeyPairFromSeed(seed(1)).PublicKey)
+
+	return []DHTVector{
+		dhtVector("closest_3_of_16", target, peers, 3),
+		dhtVector("closest_to_member", peers[5], peers, 4),
+		dhtVector("count_exceeds_peers", target, peers[:4], 8),
+	}
+}
+
+func dhtVector(name string, target peer.ID, peers []peer.ID, count int) DHTVector {
+	vector := DHTVector{
+		Name:   name,
+		Target: target.PublicKeyHex(),
+		Count:  count,
+	}
+
+	for _, id := range peers {
+		vector.Peers = append(vector.Peers, id.PublicKeyHex())
+		vector.Buckets = append(vector.Buckets, peer.CommonPrefixLen(target, id))
+	}
+
+	for _, id := range peer.Closest(target, peers, count) {
+		vector.Closest = append(vector.Closest, id.PublicKeyHex())
+	}
+
+	return vector
+}
+
+// LoadVectors reads golden vectors from a JSON file.
+func LoadVectors(path string) (*Vectors, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := new(Vectors)
+	if err := json.Unmarshal(data, vectors); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode vectors %s", path)
+	}
+
+	return vectors, nil
+}
+
+// WriteVectors writes golden vectors to a JSON file.
+func WriteVectors(path string, vectors *Vectors) error {
+	data, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// VerifyEnvelope checks an envelope vector's encodings against one another, and that
+// its signature is only valid should the vector be.
+func VerifyEnvelope(vector EnvelopeVector) error {
+	decoded := make(map[string][]byte)
+
+	for field, value := range map[string]string{
+		"public_key":      vector.PublicKey,
+		"id":              vector.ID,
+		"payload":         vector.Payload,
+		"signing_payload": vector.SigningPayload,
+		"digest":          vector.Digest,
+		"signature":       vector.Signature,
+		"envelope":        vector.Envelope,
+		"frame":           vector.Frame,
+	} {
+		bytes, err := hex.DecodeString(value)
+		if err != nil {
+			return errors.Wrapf(err, "%s: invalid %s", vector.Name, field)
+		}
+		decoded[field] = bytes
+	}
+
+	if !bytes.Equal(Frame(decoded["envelope"]), decoded["frame"]) {
+		return errors.Errorf("%s: frame does not match envelope", vector.Name)
+	}
+
+	msg := new(protobuf.Message)
+	if err := proto.Unmarshal(decoded["envelope"], msg); err != nil {
+		return errors.Wrapf(err, "%s: failed to decode envelope", vector.Name)
+	}
+
+	switch {
+	case msg.Message == nil || msg.Sender == nil:
+		return errors.Errorf("%s: envelope has no message or sender", vector.Name)
+	case msg.Message.TypeUrl != vector.TypeURL || !bytes.Equal(msg.Message.Value, decoded["payload"]):
+		return errors.Errorf("%s: envelope message does not match payload", vector.Name)
+	case msg.Sender.Address != vector.Address || !bytes.Equal(msg.Sender.PublicKey, decoded["public_key"]):
+		return errors.Errorf("%s: envelope sender does not match", vector.Name)
+	case !bytes.Equal(msg.Id, decoded["id"]):
+		return errors.Errorf("%s: envelope ID does not match", vector.Name)
+	case msg.LamportTimestamp != vector.LamportTimestamp:
+		return errors.Errorf("%s: envelope Lamport timestamp does not match", vector.Name)
+	case !bytes.Equal(msg.Signature, decoded["signature"]):
+		return errors.Errorf("%s: envelope signature does not match", vector.Name)
+	}
+
+	if domain := network.EnvelopeDomain(msg.Message); domain != vector.Domain {
+		return errors.Errorf("%s: expected domain %s, got %s", vector.Name, domain, vector.Domain)
+	}
+
+	if !bytes.Equal(SigningPayload(vector.Domain, msg.Sender, msg.Id, msg.Message.TypeUrl, msg.LamportTimestamp, msg.Message.Value), decoded["signing_payload"]) {
+		return errors.Errorf("%s: signing payload does not match envelope", vector.Name)
+	}
+
+	if !bytes.Equal(blake2b.New().HashBytes(decoded["signing_payload"]), decoded["digest"]) {
+		return errors.Errorf("%s: digest does not match signing payload", vector.Name)
+	}
+
+	if valid := ed25519.New().Verify(decoded["public_key"], decoded["digest"], decoded["signature"]); valid != vector.Valid {
+		return errors.Errorf("%s: expected signature validity to be %t, got %t", vector.Name, vector.Valid, valid)
+	}
+
+	if vector.Valid && msg.Version != network.EnvelopeVersion {
+		return errors.Errorf("%s: expected envelope version %d, got %d", vector.Name, network.EnvelopeVersion, msg.Version)
+	}
+
+	return nil
+}
+
+// VerifyDHT checks a DHT vector's closest peers and buckets.
+func VerifyDHT(vector DHTVector) error {
+	decode := func(s string) (peer.ID, error) {
+		publicKey, err := hex.DecodeString(s)
+		if err != nil {
+			return peer.ID{}, errors.Wrapf(err, "%s: invalid ID", vector.Name)
+		}
+		return peer.CreateID(vectorAddress, publicKey), nil
+	}
+
+	target, err := decode(vector.Target)
+	if err != nil {
+		return err
+	}
+
+	if len(vector.Buckets) != len(vector.Peers) {
+		return errors.Errorf("%s: expected a bucket for each of %d peers, got %d", vector.Name, len(vector.Peers), len(vector.Buckets))
+	}
+
+	peers := make([]peer.ID, 0, len(vector.Peers))
+
+	for i, s := range vector.Peers {
+		id, err := decode(s)
+		if err != nil {
+			return err
+		}
+
+		if bucket := peer.CommonPrefixLen(target, id); bucket != vector.Buckets[i] {
+			return errors.Errorf("%s: expected peer %s in bucket %d, got %d", vector.Name, s, vector.Buckets[i], bucket)
+		}
+
+		peers = append(peers, id)
+	}
+
+	closest := peer.Closest(target, peers, vector.Count)
+	if len(closest) != len(vector.Closest) {
+		return errors.Errorf("%s: expected %d closest peers, got %d", vector.Name, len(vector.Closest), len(closest))
+	}
+
+	for i, id := range closest {
+		if id.PublicKeyHex() != vector.Closest[i] {
+			return errors.Errorf("%s: expected closest peer %d to be %s, got %s", vector.Name, i, vector.Closest[i], id.PublicKeyHex())
+		}
+	}
+
+	return nil
+}
+
+// Verify checks all golden vectors.
+func (v *Vectors) Verify() error {
+	for _, vector := range v.Envelopes {
+		if err := VerifyEnvelope(vector); err != nil {
+			return err
+		}
+	}
+
+	for _, vector := range v.DHT {
+		if err := VerifyDHT(vector); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden vectors")
+
+var vectorsPath = filepath.Join("testdata", "vectors.json")
+
+func TestVectors(t *testing.T) {
+	vectors, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := WriteVectors(vectorsPath, vectors); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := vectors.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	golden, err := ioutil.ReadFile(vectorsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(bytes.TrimSpace(golden), generated) {
+		t.Fatal("golden vectors are out of date, regenerate them with -update")
+	}
+}
+
+func TestVerifyTamperedVectors(t *testing.T) {
+	vectors, err := LoadVectors(vectorsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	envelope := vectors.Envelopes[0]
+	envelope.Valid = !envelope.Valid
+
+	if err := VerifyEnvelope(envelope); err == nil {
+		t.Fatal("expected envelope vector with the wrong validity to fail verification")
+	}
+
+	lookup := vectors.DHT[0]
+	lookup.Closest[0], lookup.Closest[1] = lookup.Closest[1], lookup.Closest[0]
+
+	if err := VerifyDHT(lookup); err == nil {
+		t.Fatal("expected DHT vector with misordered peers to fail verification")
+	}
+}
@@ -0,0 +1,23 @@
+package crypto
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DomainSeparate tags a message with the domain it is to be signed within, such that a
+// signature produced within one domain cannot be replayed within another. The domain
+// is prepended to the message, terminated by a NUL byte, and so must not contain one.
+// Applications signing messages of their own should pick domains outside of noise/.
+func DomainSeparate(domain string, message []byte) []byte {
+	if bytes.IndexByte([]byte(domain), 0) >= 0 {
+		panic(fmt.Sprintf("domain %q contains a NUL byte", domain))
+	}
+
+	tagged := make([]byte, 0, len(domain)+1+len(message))
+	tagged = append(tagged, domain...)
+	tagged = append(tagged, 0)
+	tagged = append(tagged, message...)
+
+	return tagged
+}
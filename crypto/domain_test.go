@@ -0,0 +1,31 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDomainSeparate(t *testing.T) {
+	message := []byte("message")
+
+	tagged := DomainSeparate("app/vote", message)
+	if !bytes.Equal(tagged, []byte("app/vote\x00message")) {
+		t.Fatalf("unexpected tagged message %q", tagged)
+	}
+
+	if bytes.Equal(DomainSeparate("app/vote", message), DomainSeparate("app/votes", message)) {
+		t.Fatal("expected messages tagged with different domains to differ")
+	}
+
+	// The terminator keeps one domain's tag from being mistaken for another's.
+	if bytes.Equal(DomainSeparate("app", []byte("/vote\x00message")), tagged) {
+		t.Fatal("expected a domain not to be extensible by the message")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a domain containing a NUL byte to be rejected")
+		}
+	}()
+	DomainSeparate("app\x00vote", message)
+}
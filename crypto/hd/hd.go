@@ -0,0 +1,156 @@
+// Package hd derives a hierarchy of Ed25519 key pairs from a single master seed as
+// specified by SLIP-0010, such that many node identities may be run off of and
+// recovered from one seed.
+package hd
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/pkg/errors"
+)
+
+// HardenedOffset is added to the index of a hardened child key. Ed25519 only
+// supports hardened derivation.
+const HardenedOffset uint32 = 0x80000000
+
+// masterSecret keys the HMAC deriving a master key from a seed.
+var masterSecret = []byte("ed25519 seed")
+
+// Key is an extended private key, being able to derive child keys.
+type Key struct {
+	Secret    []byte
+	ChainCode []byte
+}
+
+// NewMasterKey derives the master key of a hierarchy from a seed of 16 to 64 bytes.
+func NewMasterKey(seed []byte) (*Key, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errors.Errorf("seed must be 16 to 64 bytes, got %d bytes", len(seed))
+	}
+
+	return newKey(masterSecret, seed), nil
+}
+
+func newKey(secret []byte, data []byte) *Key {
+	mac := hmac.New(sha512.New, secret)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	return &Key{Secret: sum[:32], ChainCode: sum[32:]}
+}
+
+// Child derives the hardened child key at an index. The index must be at least
+// HardenedOffset.
+func (k *Key) Child(index uint32) (*Key, error) {
+	if index < HardenedOffset {
+		return nil, errors.Errorf("ed25519 only supports hardened derivation, got index %d", index)
+	}
+
+	data := make([]byte, 1+32+4)
+	copy(data[1:], k.Secret)
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	child := newKey(k.ChainCode, data)
+	crypto.Zero(data)
+
+	return child, nil
+}
+
+// Derive derives the descendant key along a path of child indices. Intermediate keys
+// are zeroed once their children are derived.
+func (k *Key) Derive(path ...uint32) (*Key, error) {
+	key := k
+
+	for _, index := range path {
+		child, err := key.Child(index)
+
+		if key != k {
+			key.Zero()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		key = child
+	}
+
+	return key, nil
+}
+
+// Zero zeroes the key's secret and chain code.
+func (k *Key) Zero() {
+	crypto.Zero(k.Secret)
+	crypto.Zero(k.ChainCode)
+}
+
+// KeyPair returns the Ed25519 key pair of the key.
+func (k *Key) KeyPair() *crypto.KeyPair {
+	return ed25519.KeyPairFromSeed(k.Secret)
+}
+
+// ParsePath parses a derivation path such as m/0'/1', in which every index must
+// be hardened by a trailing ' or H.
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, errors.Errorf("derivation path %q must start with m", path)
+	}
+
+	indices := make([]uint32, 0, len(segments)-1)
+
+	for _, segment := range segments[1:] {
+		trimmed := strings.TrimRight(segment, "'H")
+		if len(segment)-len(trimmed) != 1 {
+			return nil, errors.Errorf("derivation path %q has an unhardened index %q", path, segment)
+		}
+
+		index, err := strconv.ParseUint(trimmed, 10, 31)
+		if err != nil {
+			return nil, errors.Wrapf(err, "derivation path %q has an invalid index %q", path, segment)
+		}
+
+		indices = append(indices, uint32(index)+HardenedOffset)
+	}
+
+	return indices, nil
+}
+
+// IdentityPath returns the derivation path of the node identity at an index, such
+// that m/0'/0' is the first identity derived from a seed.
+func IdentityPath(index uint32) string {
+	return fmt.Sprintf("m/0'/%d'", index)
+}
+
+// DeriveKeyPair derives the Ed25519 key pair at a derivation path from a seed.
+func DeriveKeyPair(seed []byte, path string) (*crypto.KeyPair, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	defer master.Zero()
+
+	key, err := master.Derive(indices...)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != master {
+		defer key.Zero()
+	}
+
+	return key.KeyPair(), nil
+}
@@ -0,0 +1,77 @@
+package hd
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Vectors taken from the SLIP-0010 specification, test vector 1 for ed25519.
+func TestVectors(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(master.Secret) != "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7" {
+		t.Fatalf("unexpected master key %x", master.Secret)
+	}
+
+	if hex.EncodeToString(master.ChainCode) != "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb" {
+		t.Fatalf("unexpected master chain code %x", master.ChainCode)
+	}
+
+	path, err := ParsePath("m/0'/1H")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := master.Derive(path...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(key.Secret) != "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2" {
+		t.Fatalf("unexpected key %x at m/0'/1'", key.Secret)
+	}
+}
+
+func TestDeriveKeyPair(t *testing.T) {
+	seed := make([]byte, 64)
+
+	first, err := DeriveKeyPair(seed, IdentityPath(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := DeriveKeyPair(seed, IdentityPath(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := DeriveKeyPair(seed, IdentityPath(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.PrivateKeyHex() != again.PrivateKeyHex() {
+		t.Fatal("expected derivation to be deterministic")
+	}
+
+	if first.PublicKeyHex() == second.PublicKeyHex() {
+		t.Fatal("expected identities at different indices to differ")
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	for _, path := range []string{"0'/1'", "m/0", "m/0''", "m/x'", "m/2147483648'"} {
+		if _, err := ParsePath(path); err == nil {
+			t.Fatalf("expected path %q to be invalid", path)
+		}
+	}
+
+	if indices, err := ParsePath("m"); err != nil || len(indices) != 0 {
+		t.Fatalf("expected m to be the master key, got %v (err=%v)", indices, err)
+	}
+}
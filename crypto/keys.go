@@ -27,6 +27,14 @@ func (k *KeyPair) Sign(sp SignaturePolicy, hp HashPolicy, message []byte) ([]byt
 	return signature, nil
 }
 
+// Clone returns a copy of the key pair, which may be closed independently of it.
+func (k *KeyPair) Clone() *KeyPair {
+	return &KeyPair{
+		PrivateKey: append([]byte(nil), k.PrivateKey...),
+		PublicKey:  append([]byte(nil), k.PublicKey...),
+	}
+}
+
 func (k *KeyPair) PrivateKeyHex() string {
 	return hex.EncodeToString(k.PrivateKey)
 }
@@ -0,0 +1,49 @@
+package crypto
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// ErrMemoryLockUnsupported is returned should memory not be lockable on this platform.
+var ErrMemoryLockUnsupported = errors.New("locking memory is not supported on this platform")
+
+// Equal compares two byte slices, such as keys or IDs, in constant time with respect
+// to their contents.
+func Equal(a []byte, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Zero overwrites a byte slice with zeroes.
+func Zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// Equal compares the private keys of two key pairs in constant time.
+func (k *KeyPair) Equal(other *KeyPair) bool {
+	return Equal(k.PrivateKey, other.PrivateKey)
+}
+
+// Lock locks the memory holding the private key so that it is never swapped to disk.
+// Returns ErrMemoryLockUnsupported on platforms without mlock.
+func (k *KeyPair) Lock() error {
+	if len(k.PrivateKey) == 0 {
+		return nil
+	}
+	return lockMemory(k.PrivateKey)
+}
+
+// Close zeroes the private key and unlocks its memory. The key pair may no longer
+// sign messages once closed. Networks sign with copies of the key pairs they are built
+// with, which they close once they are closed.
+func (k *KeyPair) Close() error {
+	Zero(k.PrivateKey)
+
+	if len(k.PrivateKey) > 0 {
+		unlockMemory(k.PrivateKey)
+	}
+
+	return nil
+}
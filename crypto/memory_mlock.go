@@ -0,0 +1,14 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package crypto
+
+import "syscall"
+
+func lockMemory(b []byte) error {
+	return syscall.Mlock(b)
+}
+
+func unlockMemory(b []byte) error {
+	return syscall.Munlock(b)
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package crypto
+
+func lockMemory(b []byte) error {
+	return ErrMemoryLockUnsupported
+}
+
+func unlockMemory(b []byte) error {
+	return nil
+}
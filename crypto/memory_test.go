@@ -0,0 +1,35 @@
+package crypto
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	if !Equal([]byte{1, 2, 3}, []byte{1, 2, 3}) {
+		t.Fatal("expected equal slices to be equal")
+	}
+
+	if Equal([]byte{1, 2, 3}, []byte{1, 2, 4}) || Equal([]byte{1, 2}, []byte{1, 2, 3}) {
+		t.Fatal("expected differing slices to not be equal")
+	}
+}
+
+func TestClose(t *testing.T) {
+	k := &KeyPair{PrivateKey: []byte{1, 2, 3, 4}, PublicKey: []byte{5, 6}}
+
+	if err := k.Lock(); err != nil && err != ErrMemoryLockUnsupported {
+		t.Logf("failed to lock memory: %v", err)
+	}
+
+	if err := k.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, b := range k.PrivateKey {
+		if b != 0 {
+			t.Fatal("expected private key to be zeroed")
+		}
+	}
+
+	if k.PublicKey[0] != 5 {
+		t.Fatal("expected public key to be left intact")
+	}
+}
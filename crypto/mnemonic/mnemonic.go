@@ -0,0 +1,162 @@
+// Package mnemonic encodes entropy as a BIP39 mnemonic sentence, and stretches
+// mnemonics into seeds from which node identities may be deterministically derived.
+package mnemonic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// seedIterations is the number of PBKDF2 rounds a mnemonic is stretched by.
+	seedIterations = 2048
+
+	// SeedSize is the size in bytes of a seed stretched from a mnemonic.
+	SeedSize = 64
+)
+
+var (
+	// ErrEntropySize is returned should entropy not be between 128 and 256 bits in
+	// multiples of 32 bits.
+	ErrEntropySize = errors.New("entropy must be 128 to 256 bits in multiples of 32 bits")
+
+	// ErrInvalidMnemonic is returned should a mnemonic have an invalid word or word count.
+	ErrInvalidMnemonic = errors.New("mnemonic has an invalid word or word count")
+
+	// ErrChecksum is returned should a mnemonic's checksum not match its entropy.
+	ErrChecksum = errors.New("mnemonic checksum is incorrect")
+)
+
+var wordIndices = func() map[string]int {
+	indices := make(map[string]int, len(english))
+	for i, word := range english {
+		indices[word] = i
+	}
+	return indices
+}()
+
+func validEntropySize(bits int) bool {
+	return bits >= 128 && bits <= 256 && bits%32 == 0
+}
+
+// NewEntropy returns a number of bits of random entropy.
+func NewEntropy(bits int) ([]byte, error) {
+	if !validEntropySize(bits) {
+		return nil, ErrEntropySize
+	}
+
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, errors.Wrap(err, "failed to read entropy")
+	}
+
+	return entropy, nil
+}
+
+// New encodes entropy into a mnemonic sentence, with every 11 bits of entropy and
+// its checksum being represented by a word.
+func New(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if !validEntropySize(bits) {
+		return "", ErrEntropySize
+	}
+
+	checksumBits := uint(bits / 32)
+
+	// Append the leading bits of the entropy's SHA-256 digest as a checksum.
+	digest := sha256.Sum256(entropy)
+
+	value := new(big.Int).SetBytes(entropy)
+	value.Lsh(value, checksumBits)
+	value.Or(value, big.NewInt(int64(digest[0]>>(8-checksumBits))))
+
+	words := make([]string, (bits+int(checksumBits))/11)
+
+	mask := big.NewInt(2047)
+	index := new(big.Int)
+
+	for i := len(words) - 1; i >= 0; i-- {
+		index.And(value, mask)
+		value.Rsh(value, 11)
+
+		words[i] = english[index.Int64()]
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// Generate returns a new random mnemonic encoding a number of bits of entropy.
+func Generate(bits int) (string, error) {
+	entropy, err := NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+
+	return New(entropy)
+}
+
+// Entropy decodes the entropy a mnemonic encodes, verifying its checksum.
+func Entropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, ErrInvalidMnemonic
+	}
+
+	value := new(big.Int)
+
+	for _, word := range words {
+		index, exists := wordIndices[word]
+		if !exists {
+			return nil, ErrInvalidMnemonic
+		}
+
+		value.Lsh(value, 11)
+		value.Or(value, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(len(words) * 11 / 33)
+
+	checksum := new(big.Int).And(value, big.NewInt(int64(1)<<checksumBits-1))
+	value.Rsh(value, checksumBits)
+
+	// Left-pad the entropy should it have leading zero bytes.
+	entropy := make([]byte, len(words)*11*32/33/8)
+	raw := value.Bytes()
+	copy(entropy[len(entropy)-len(raw):], raw)
+
+	digest := sha256.Sum256(entropy)
+	if int64(digest[0]>>(8-checksumBits)) != checksum.Int64() {
+		return nil, ErrChecksum
+	}
+
+	return entropy, nil
+}
+
+// Validate returns true should a mnemonic be well-formed with a correct checksum.
+func Validate(mnemonic string) bool {
+	_, err := Entropy(mnemonic)
+	return err == nil
+}
+
+// Seed verifies a mnemonic, and stretches it alongside an optional passphrase into a
+// 64-byte seed.
+func Seed(mnemonic string, passphrase string) ([]byte, error) {
+	if _, err := Entropy(mnemonic); err != nil {
+		return nil, err
+	}
+
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+
+	password := norm.NFKD.String(mnemonic)
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+
+	return pbkdf2.Key([]byte(password), []byte(salt), seedIterations, SeedSize, sha512.New), nil
+}
@@ -0,0 +1,99 @@
+package mnemonic
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Vectors taken from https://github.com/trezor/python-mnemonic/blob/master/vectors.json.
+var vectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		entropy:  "00000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		entropy:  "0000000000000000000000000000000000000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		seed:     "bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for _, vector := range vectors {
+		entropy, _ := hex.DecodeString(vector.entropy)
+
+		mnemonic, err := New(entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if mnemonic != vector.mnemonic {
+			t.Fatalf("expected mnemonic %q, got %q", vector.mnemonic, mnemonic)
+		}
+
+		decoded, err := Entropy(mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(decoded, entropy) {
+			t.Fatalf("expected entropy %x, got %x", entropy, decoded)
+		}
+
+		seed, err := Seed(mnemonic, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if hex.EncodeToString(seed) != vector.seed {
+			t.Fatalf("expected seed %s, got %x", vector.seed, seed)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	for _, bits := range []int{128, 160, 192, 224, 256} {
+		mnemonic, err := Generate(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entropy, err := Entropy(mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(entropy) != bits/8 {
+			t.Fatalf("expected %d bits of entropy, got %d", bits, len(entropy)*8)
+		}
+	}
+
+	if _, err := Generate(100); err != ErrEntropySize {
+		t.Fatalf("expected an entropy size error, got %v", err)
+	}
+}
+
+func TestInvalidMnemonic(t *testing.T) {
+	if _, err := Entropy("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon yellow"); err != ErrChecksum {
+		t.Fatalf("expected a checksum error, got %v", err)
+	}
+
+	if _, err := Entropy("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon peerless"); err != ErrInvalidMnemonic {
+		t.Fatalf("expected an invalid mnemonic error, got %v", err)
+	}
+
+	if Validate("abandon about") {
+		t.Fatal("expected a two-word mnemonic to be invalid")
+	}
+}
@@ -11,3 +11,9 @@ type SignaturePolicy interface {
 	Sign(privateKey []byte, message []byte) []byte
 	Verify(publicKey []byte, message []byte, signature []byte) bool
 }
+
+// Signer signs messages on behalf of a node, such as a KeyPair held in memory or a
+// remote signer holding the node's private key out of process.
+type Signer interface {
+	Sign(sp SignaturePolicy, hp HashPolicy, message []byte) ([]byte, error)
+}
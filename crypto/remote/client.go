@@ -0,0 +1,203 @@
+package remote
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// Client is a crypto.Signer which has a remote signer sign messages on its behalf.
+// Should the connection to the signer break, it is re-established upon the next
+// request.
+type Client struct {
+	// Timeout is how long the signer has to respond to a request.
+	Timeout time.Duration
+
+	connect func() (net.Conn, error)
+
+	publicKey []byte
+
+	mutex  sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	nonce  uint64
+}
+
+var _ crypto.Signer = (*Client)(nil)
+
+// Dial connects to a remote signer listening on an address, authenticating with keys
+// and only accepting a signer authenticating with signerKey.
+func Dial(address string, keys *crypto.KeyPair, signerKey []byte) (*Client, error) {
+	config, err := newTLSConfig(keys, signerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(func() (net.Conn, error) {
+		conn, err := net.DialTimeout("tcp", address, DefaultTimeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to dial signer at %s", address)
+		}
+
+		return handshake(conn, config, true, DefaultTimeout)
+	})
+}
+
+// Accept waits for a remote signer to connect to a listener, authenticating with keys
+// and only accepting a signer authenticating with signerKey. Connections from any
+// other party are dropped. Should the signer disconnect, requests block until it
+// connects again.
+func Accept(listener net.Listener, keys *crypto.KeyPair, signerKey []byte) (*Client, error) {
+	config, err := newTLSConfig(keys, signerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(func() (net.Conn, error) {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to accept signer")
+			}
+
+			if secured, err := handshake(conn, config, false, DefaultTimeout); err == nil {
+				return secured, nil
+			}
+		}
+	})
+}
+
+func newClient(connect func() (net.Conn, error)) (*Client, error) {
+	client := &Client{Timeout: DefaultTimeout, connect: connect}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if err := client.reconnect(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// reconnect (re-)establishes a connection to the signer, and learns of the public
+// key it signs on behalf of.
+func (c *Client) reconnect() error {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+
+	conn, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+	hello := new(protobuf.SignerHello)
+
+	conn.SetReadDeadline(time.Now().Add(c.Timeout))
+
+	if err := readFrame(reader, hello); err != nil {
+		conn.Close()
+		return errors.Wrap(err, "failed to receive hello from signer")
+	}
+
+	// The signer must never change the key it signs on behalf of.
+	if c.publicKey != nil && !crypto.Equal(c.publicKey, hello.PublicKey) {
+		conn.Close()
+		return errors.New("signer changed the public key it signs on behalf of")
+	}
+
+	c.publicKey = hello.PublicKey
+	c.conn, c.reader = conn, reader
+
+	return nil
+}
+
+// PublicKey returns the public key the signer signs on behalf of.
+func (c *Client) PublicKey() []byte {
+	return c.publicKey
+}
+
+// Sign implements crypto.Signer, having the signer sign a message and verifying the
+// signature it returns.
+func (c *Client) Sign(sp crypto.SignaturePolicy, hp crypto.HashPolicy, message []byte) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	signature, err := c.request(message)
+
+	// Retry once over a new connection should the current one have broken.
+	if _, refused := err.(refusedError); err != nil && !refused {
+		if err = c.reconnect(); err == nil {
+			signature, err = c.request(message)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !crypto.Verify(sp, hp, c.publicKey, message, signature) {
+		return nil, errors.New("signer returned an invalid signature")
+	}
+
+	return signature, nil
+}
+
+type refusedError string
+
+func (e refusedError) Error() string {
+	return "signer refused to sign: " + string(e)
+}
+
+func (c *Client) request(message []byte) ([]byte, error) {
+	if c.conn == nil {
+		return nil, errors.New("not connected to signer")
+	}
+
+	c.nonce++
+
+	c.conn.SetDeadline(time.Now().Add(c.Timeout))
+	defer c.conn.SetDeadline(time.Time{})
+
+	if err := writeFrame(c.conn, &protobuf.SignRequest{Nonce: c.nonce, Message: message}); err != nil {
+		return nil, errors.Wrap(err, "failed to send sign request")
+	}
+
+	response := new(protobuf.SignResponse)
+	if err := readFrame(c.reader, response); err != nil {
+		return nil, errors.Wrap(err, "failed to receive sign response")
+	}
+
+	if response.Nonce != c.nonce {
+		return nil, errors.Errorf("expected response to request %d, got %d", c.nonce, response.Nonce)
+	}
+
+	if len(response.Error) > 0 {
+		return nil, refusedError(response.Error)
+	}
+
+	return response.Signature, nil
+}
+
+// Close closes the connection to the signer.
+func (c *Client) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+
+	return err
+}
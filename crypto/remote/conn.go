@@ -0,0 +1,147 @@
+// Package remote delegates signing to a remote signer process, such that a node's
+// private key never lives on the node exposed to the network.
+//
+// A node and its signer connect over mutually authenticated TLS, with each side
+// pinning the Ed25519 public key the other side authenticates itself with. Either
+// side may dial the other: a node may dial a listening signer, or a signer may dial
+// out to a listening node such that the signer exposes no ports at all.
+package remote
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxFrameSize is the largest request or response exchanged with a signer.
+	maxFrameSize = 4e+6
+
+	// DefaultTimeout is how long a signer has to respond to a request by default.
+	DefaultTimeout = 5 * time.Second
+)
+
+// newTLSConfig creates a TLS configuration authenticating with keys, which only
+// accepts peers authenticating with one of the pinned public keys.
+func newTLSConfig(keys *crypto.KeyPair, pinned ...[]byte) (*tls.Config, error) {
+	if len(keys.PrivateKey) != ed25519.PrivateKeySize {
+		return nil, errors.Errorf("connection private key must be %d bytes", ed25519.PrivateKeySize)
+	}
+
+	if len(pinned) == 0 {
+		return nil, errors.New("at least one peer public key must be pinned")
+	}
+
+	privateKey := ed25519.PrivateKey(keys.PrivateKey)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create certificate")
+	}
+
+	verify := func(certificates [][]byte, _ [][]*x509.Certificate) error {
+		if len(certificates) == 0 {
+			return errors.New("peer presented no certificate")
+		}
+
+		certificate, err := x509.ParseCertificate(certificates[0])
+		if err != nil {
+			return errors.Wrap(err, "peer presented an invalid certificate")
+		}
+
+		publicKey, ok := certificate.PublicKey.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("peer presented a non-ed25519 certificate")
+		}
+
+		for _, key := range pinned {
+			if crypto.Equal(publicKey, key) {
+				return nil
+			}
+		}
+
+		return errors.New("peer public key is not pinned")
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: privateKey}},
+		MinVersion:   tls.VersionTLS13,
+
+		// Peers are authenticated by their pinned public keys rather than by a CA.
+		InsecureSkipVerify:    true,
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: verify,
+	}, nil
+}
+
+// handshake secures a connection, acting as the TLS client should we have dialed it.
+func handshake(conn net.Conn, config *tls.Config, dialed bool, timeout time.Duration) (net.Conn, error) {
+	var secured *tls.Conn
+
+	if dialed {
+		secured = tls.Client(conn, config)
+	} else {
+		secured = tls.Server(conn, config)
+	}
+
+	secured.SetDeadline(time.Now().Add(timeout))
+
+	if err := secured.Handshake(); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to authenticate connection")
+	}
+
+	secured.SetDeadline(time.Time{})
+
+	return secured, nil
+}
+
+// writeFrame writes a length-prefixed message onto a connection.
+func writeFrame(conn net.Conn, message proto.Message) error {
+	bytes, err := proto.Marshal(message)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+
+	buffer := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buffer, uint64(len(bytes)))
+
+	_, err = conn.Write(append(buffer[:n], bytes...))
+	return err
+}
+
+// readFrame reads a length-prefixed message from a connection.
+func readFrame(reader *bufio.Reader, message proto.Message) error {
+	size, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return err
+	}
+
+	if size > maxFrameSize {
+		return errors.Errorf("frame of %d bytes is too large", size)
+	}
+
+	buffer := make([]byte, size)
+	if _, err := io.ReadFull(reader, buffer); err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(buffer, message)
+}
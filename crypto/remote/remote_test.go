@@ -0,0 +1,147 @@
+package remote
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/hashing/blake2b"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/pkg/errors"
+)
+
+type fixture struct {
+	validator  *crypto.KeyPair
+	signerConn *crypto.KeyPair
+	nodeConn   *crypto.KeyPair
+	server     *Server
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := &fixture{
+		validator:  ed25519.RandomKeyPair(),
+		signerConn: ed25519.RandomKeyPair(),
+		nodeConn:   ed25519.RandomKeyPair(),
+	}
+
+	server, err := NewServer(f.validator, ed25519.New(), blake2b.New(), f.signerConn, f.nodeConn.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.server = server
+
+	return f
+}
+
+func listen(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return listener
+}
+
+func TestDialSigner(t *testing.T) {
+	f := newFixture(t)
+
+	listener := listen(t)
+	defer listener.Close()
+
+	go f.server.Serve(listener)
+
+	client, err := Dial(listener.Addr().String(), f.nodeConn, f.signerConn.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if !bytes.Equal(client.PublicKey(), f.validator.PublicKey) {
+		t.Fatal("expected signer to announce the validator's public key")
+	}
+
+	message := []byte("vote")
+
+	signature, err := client.Sign(ed25519.New(), blake2b.New(), message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !crypto.Verify(ed25519.New(), blake2b.New(), f.validator.PublicKey, message, signature) {
+		t.Fatal("expected signature to verify against the validator's public key")
+	}
+
+	// Signing should recover from a broken connection.
+	client.conn.Close()
+
+	if _, err := client.Sign(ed25519.New(), blake2b.New(), message); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignerDialsNode(t *testing.T) {
+	f := newFixture(t)
+
+	listener := listen(t)
+	defer listener.Close()
+
+	go f.server.DialAndServe(listener.Addr().String())
+
+	client, err := Accept(listener, f.nodeConn, f.signerConn.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err := client.Sign(ed25519.New(), blake2b.New(), []byte("vote")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	f := newFixture(t)
+
+	f.server.Authorize = func(message []byte) error {
+		if bytes.Equal(message, []byte("double vote")) {
+			return errors.New("conflicting vote")
+		}
+		return nil
+	}
+
+	listener := listen(t)
+	defer listener.Close()
+
+	go f.server.Serve(listener)
+
+	client, err := Dial(listener.Addr().String(), f.nodeConn, f.signerConn.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err := client.Sign(ed25519.New(), blake2b.New(), []byte("double vote")); err == nil {
+		t.Fatal("expected signer to refuse an unauthorized message")
+	}
+
+	if _, err := client.Sign(ed25519.New(), blake2b.New(), []byte("vote")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnpinnedNodeRejected(t *testing.T) {
+	f := newFixture(t)
+
+	listener := listen(t)
+	defer listener.Close()
+
+	go f.server.Serve(listener)
+
+	if _, err := Dial(listener.Addr().String(), ed25519.RandomKeyPair(), f.signerConn.PublicKey); err == nil {
+		t.Fatal("expected signer to reject a node whose key is not pinned")
+	}
+
+	if _, err := Dial(listener.Addr().String(), f.nodeConn, ed25519.RandomKeyPair().PublicKey); err == nil {
+		t.Fatal("expected node to reject a signer whose key is not pinned")
+	}
+}
@@ -0,0 +1,117 @@
+package remote
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// Server is a remote signer, signing messages on behalf of nodes with a key pair the
+// nodes never hold.
+type Server struct {
+	keys *crypto.KeyPair
+
+	signaturePolicy crypto.SignaturePolicy
+	hashPolicy      crypto.HashPolicy
+
+	// Authorize vetoes messages from being signed, such as to refuse signing two
+	// conflicting votes. Messages are authorized and signed one at a time. Nil if all
+	// messages are signed.
+	Authorize func(message []byte) error
+
+	config *tls.Config
+
+	mutex sync.Mutex
+}
+
+// NewServer creates a remote signer signing with keys under a signature and hash
+// policy. It authenticates connections with connKeys, and only serves nodes which
+// authenticate with one of the nodes' public keys.
+func NewServer(keys *crypto.KeyPair, sp crypto.SignaturePolicy, hp crypto.HashPolicy, connKeys *crypto.KeyPair, nodes ...[]byte) (*Server, error) {
+	config, err := newTLSConfig(connKeys, nodes...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{keys: keys, signaturePolicy: sp, hashPolicy: hp, config: config}, nil
+}
+
+// Serve serves nodes connecting to a listener until it is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			if err := s.ServeConn(conn, false); err != nil {
+				glog.Warningf("Stopped serving node %s [err=%s]", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// DialAndServe dials out to a node listening on an address, and serves it until the
+// connection breaks.
+func (s *Server) DialAndServe(address string) error {
+	conn, err := net.DialTimeout("tcp", address, DefaultTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to dial node at %s", address)
+	}
+
+	return s.ServeConn(conn, true)
+}
+
+// ServeConn authenticates and serves a node over a connection until it breaks.
+func (s *Server) ServeConn(conn net.Conn, dialed bool) error {
+	conn, err := handshake(conn, s.config, dialed, DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := writeFrame(conn, &protobuf.SignerHello{PublicKey: s.keys.PublicKey}); err != nil {
+		return errors.Wrap(err, "failed to send hello")
+	}
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		request := new(protobuf.SignRequest)
+		if err := readFrame(reader, request); err != nil {
+			return err
+		}
+
+		response := &protobuf.SignResponse{Nonce: request.Nonce}
+
+		if signature, err := s.sign(request.Message); err != nil {
+			response.Error = err.Error()
+		} else {
+			response.Signature = signature
+		}
+
+		if err := writeFrame(conn, response); err != nil {
+			return errors.Wrap(err, "failed to send sign response")
+		}
+	}
+}
+
+func (s *Server) sign(message []byte) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Authorize != nil {
+		if err := s.Authorize(message); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.keys.Sign(s.signaturePolicy, s.hashPolicy, message)
+}
@@ -42,3 +42,13 @@ func RandomKeyPair() *crypto.KeyPair {
 		PrivateKey: privateKey,
 	}
 }
+
+// KeyPairFromSeed deterministically derives a key pair from a 32-byte seed.
+func KeyPairFromSeed(seed []byte) *crypto.KeyPair {
+	privateKey := ed25519lib.NewKeyFromSeed(seed)
+
+	return &crypto.KeyPair{
+		PublicKey:  []byte(privateKey.Public().(ed25519lib.PublicKey)),
+		PrivateKey: []byte(privateKey),
+	}
+}
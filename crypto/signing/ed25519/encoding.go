@@ -0,0 +1,134 @@
+package ed25519
+
+import (
+	"bytes"
+	stded25519 "crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/pkg/errors"
+)
+
+const (
+	pemPrivateKeyType = "PRIVATE KEY"
+	pemPublicKeyType  = "PUBLIC KEY"
+)
+
+// JWK is an Ed25519 key encoded as a JSON Web Key as specified by RFC 8037.
+type JWK struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	D       string `json:"d,omitempty"`
+}
+
+// MarshalPEM encodes the private key of a key pair as a PKCS #8 PEM block.
+func MarshalPEM(kp *crypto.KeyPair) ([]byte, error) {
+	if len(kp.PrivateKey) != stded25519.PrivateKeySize {
+		return nil, errors.Errorf("private key must be %d bytes", stded25519.PrivateKeySize)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(stded25519.PrivateKey(kp.PrivateKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal private key")
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemPrivateKeyType, Bytes: der}), nil
+}
+
+// MarshalPublicPEM encodes the public key of a key pair as a PKIX PEM block.
+func MarshalPublicPEM(kp *crypto.KeyPair) ([]byte, error) {
+	if len(kp.PublicKey) != stded25519.PublicKeySize {
+		return nil, errors.Errorf("public key must be %d bytes", stded25519.PublicKeySize)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(stded25519.PublicKey(kp.PublicKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal public key")
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemPublicKeyType, Bytes: der}), nil
+}
+
+// ParsePEM decodes a key pair from a PKCS #8 PEM block.
+func ParsePEM(data []byte) (*crypto.KeyPair, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemPrivateKeyType {
+		return nil, errors.New("no PEM private key block found")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key")
+	}
+
+	privateKey, ok := key.(stded25519.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("expected an ed25519 private key, got %T", key)
+	}
+
+	return crypto.FromPrivateKeyBytes(New(), []byte(privateKey))
+}
+
+// MarshalJWK encodes a key pair as a JSON Web Key.
+func MarshalJWK(kp *crypto.KeyPair) ([]byte, error) {
+	if len(kp.PrivateKey) != stded25519.PrivateKeySize {
+		return nil, errors.Errorf("private key must be %d bytes", stded25519.PrivateKeySize)
+	}
+
+	return json.Marshal(JWK{
+		KeyType: "OKP",
+		Curve:   "Ed25519",
+		X:       base64.RawURLEncoding.EncodeToString(kp.PublicKey),
+		D:       base64.RawURLEncoding.EncodeToString(stded25519.PrivateKey(kp.PrivateKey).Seed()),
+	})
+}
+
+// ParseJWK decodes a key pair from a JSON Web Key, verifying that its public key
+// matches its private key.
+func ParseJWK(data []byte) (*crypto.KeyPair, error) {
+	var jwk JWK
+
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, errors.Wrap(err, "failed to parse JWK")
+	}
+
+	if jwk.KeyType != "OKP" || jwk.Curve != "Ed25519" {
+		return nil, errors.Errorf("expected an OKP Ed25519 JWK, got kty=%s crv=%s", jwk.KeyType, jwk.Curve)
+	}
+
+	seed, err := base64.RawURLEncoding.DecodeString(jwk.D)
+	if err != nil || len(seed) != stded25519.SeedSize {
+		return nil, errors.New("JWK has an invalid or missing private key")
+	}
+
+	kp := KeyPairFromSeed(seed)
+
+	if len(jwk.X) > 0 {
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || !bytes.Equal(x, kp.PublicKey) {
+			return nil, errors.New("JWK public key does not match its private key")
+		}
+	}
+
+	return kp, nil
+}
+
+// ParseKeyPair decodes a key pair encoded either as a PEM block, a JSON Web Key, or
+// a hex-encoded private key.
+func ParseKeyPair(data []byte) (*crypto.KeyPair, error) {
+	trimmed := strings.TrimSpace(string(data))
+
+	switch {
+	case strings.HasPrefix(trimmed, "-----BEGIN"):
+		return ParsePEM(data)
+	case strings.HasPrefix(trimmed, "{"):
+		return ParseJWK(data)
+	default:
+		return crypto.FromPrivateKey(New(), trimmed)
+	}
+}
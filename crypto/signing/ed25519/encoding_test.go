@@ -0,0 +1,84 @@
+package ed25519
+
+import (
+	"bytes"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPEM(t *testing.T) {
+	kp := RandomKeyPair()
+
+	encoded, err := MarshalPEM(kp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := ParseKeyPair(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(kp, decoded) {
+		t.Fatal("expected PEM round trip to preserve the key pair")
+	}
+
+	public, err := MarshalPublicPEM(kp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParsePEM(public); err == nil {
+		t.Fatal("expected a public key PEM block to not parse as a private key")
+	}
+}
+
+func TestJWK(t *testing.T) {
+	kp := RandomKeyPair()
+
+	encoded, err := MarshalJWK(kp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := ParseKeyPair(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(kp, decoded) {
+		t.Fatal("expected JWK round trip to preserve the key pair")
+	}
+
+	other := RandomKeyPair()
+
+	mismatched, _ := MarshalJWK(other)
+	mismatched = bytes.Replace(mismatched, []byte(encodeX(other.PublicKey)), []byte(encodeX(kp.PublicKey)), 1)
+
+	if _, err := ParseJWK(mismatched); err == nil {
+		t.Fatal("expected a JWK with a mismatched public key to be rejected")
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	kp := RandomKeyPair()
+
+	decoded, err := ParseKeyPair([]byte(kp.PrivateKeyHex() + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(kp, decoded) {
+		t.Fatal("expected hex round trip to preserve the key pair")
+	}
+
+	if _, err := ParseKeyPair([]byte(strings.Repeat("zz", 64))); err == nil {
+		t.Fatal("expected invalid hex to be rejected")
+	}
+}
+
+func encodeX(publicKey []byte) string {
+	return base64.RawURLEncoding.EncodeToString(publicKey)
+}
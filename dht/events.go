@@ -0,0 +1,88 @@
+package dht
+
+import (
+	"sync"
+
+	"github.com/perlin-network/noise/peer"
+)
+
+// eventBufferSize is the number of events a subscriber buffers before dropping events.
+const eventBufferSize = 256
+
+// EventType denotes a change to the routing table.
+type EventType int
+
+const (
+	// EventPeerAdded is emitted when a peer enters a bucket.
+	EventPeerAdded EventType = iota
+
+	// EventPeerVerified is emitted when a peer within a bucket is heard from directly
+	// for the first time.
+	EventPeerVerified
+
+	// EventPeerRemoved is emitted when a peer leaves a bucket, be it through removal,
+	// eviction or pruning.
+	EventPeerRemoved
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventPeerAdded:
+		return "added"
+	case EventPeerVerified:
+		return "verified"
+	case EventPeerRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a change to a bucket within the routing table.
+type Event struct {
+	Type   EventType
+	Peer   peer.ID
+	Bucket int
+}
+
+type subscribers struct {
+	mutex sync.RWMutex
+	chans map[chan Event]struct{}
+}
+
+// Subscribe returns a channel of changes made to the routing table, alongside a function
+// which unsubscribes from further changes. Events are dropped should the channel not be
+// drained fast enough.
+func (t *RoutingTable) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventBufferSize)
+
+	t.subscribers.mutex.Lock()
+	if t.subscribers.chans == nil {
+		t.subscribers.chans = make(map[chan Event]struct{})
+	}
+	t.subscribers.chans[ch] = struct{}{}
+	t.subscribers.mutex.Unlock()
+
+	return ch, func() {
+		t.subscribers.mutex.Lock()
+		defer t.subscribers.mutex.Unlock()
+
+		if _, exists := t.subscribers.chans[ch]; exists {
+			delete(t.subscribers.chans, ch)
+			close(ch)
+		}
+	}
+}
+
+// emit notifies all subscribers of a change to a bucket.
+func (t *RoutingTable) emit(ty EventType, target peer.ID, bucketID int) {
+	t.subscribers.mutex.RLock()
+	defer t.subscribers.mutex.RUnlock()
+
+	for ch := range t.subscribers.chans {
+		select {
+		case ch <- Event{Type: ty, Peer: target, Bucket: bucketID}:
+		default:
+		}
+	}
+}
@@ -2,10 +2,11 @@ package dht
 
 import (
 	"container/list"
-	"sort"
 	"sync"
+	"time"
 
 	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/types/clock"
 )
 
 // BucketSize defines the NodeID, Key, and routing table data structures.
@@ -17,6 +18,26 @@ type RoutingTable struct {
 	self peer.ID
 
 	buckets []*Bucket
+
+	// Subscribers to changes made to the routing table.
+	subscribers subscribers
+
+	// Liveness of peers by their hex-encoded public keys.
+	entries      map[string]*entry
+	entriesMutex sync.Mutex
+
+	clock clock.Clock
+
+	// protected returns true should a peer be protected from eviction and pruning.
+	protected func(peer.ID) bool
+}
+
+// entry tracks whether a peer within the routing table has been verified to be live,
+// as opposed to only having been heard of from other peers.
+type entry struct {
+	verified bool
+	added    time.Time
+	lastSeen time.Time
 }
 
 // Bucket holds a list of contacts of this node
@@ -33,12 +54,27 @@ func NewBucket() *Bucket {
 	}
 }
 
+// Peers returns all peers within the bucket, ordered from most to least recently seen.
+func (b *Bucket) Peers() (peers []peer.ID) {
+	b.mutex.RLock()
+
+	for e := b.Front(); e != nil; e = e.Next() {
+		peers = append(peers, e.Value.(peer.ID))
+	}
+
+	b.mutex.RUnlock()
+
+	return
+}
+
 // CreateRoutingTable is a Factory method of RoutingTable
 // , contains empty buckets
 func CreateRoutingTable(id peer.ID) *RoutingTable {
 	table := &RoutingTable{
 		self:    id,
 		buckets: make([]*Bucket, len(id.PublicKey)*8),
+		entries: make(map[string]*entry),
+		clock:   clock.Real,
 	}
 	for i := 0; i < len(id.PublicKey)*8; i++ {
 		table.buckets[i] = NewBucket()
@@ -54,13 +90,45 @@ func (t *RoutingTable) Self() peer.ID {
 	return t.self
 }
 
-// Update moves a peer to the front of a bucket int he routing table.
+// SetClock sets the clock used to track the liveness of peers.
+func (t *RoutingTable) SetClock(c clock.Clock) {
+	t.entriesMutex.Lock()
+	t.clock = clock.Or(c)
+	t.entriesMutex.Unlock()
+}
+
+// SetProtector sets the function reporting whether a peer is protected from being
+// evicted or pruned from the routing table.
+func (t *RoutingTable) SetProtector(protected func(peer.ID) bool) {
+	t.entriesMutex.Lock()
+	t.protected = protected
+	t.entriesMutex.Unlock()
+}
+
+// IsProtected returns true should a peer be protected from eviction and pruning.
+func (t *RoutingTable) IsProtected(target peer.ID) bool {
+	t.entriesMutex.Lock()
+	defer t.entriesMutex.Unlock()
+
+	return t.isProtected(target)
+}
+
+// isProtected returns true should a peer be protected. Must be called with the entries
+// mutex held.
+func (t *RoutingTable) isProtected(target peer.ID) bool {
+	return t.protected != nil && t.protected(target)
+}
+
+// Update marks a peer which has been heard from directly as verified, and moves it to
+// the front of a bucket in the routing table. Should the peer's address have changed,
+// its address is updated. Should the bucket be full, the least recently seen
+// unverified peer is evicted to make room.
 func (t *RoutingTable) Update(target peer.ID) {
 	if len(t.self.PublicKey) != len(target.PublicKey) {
 		return
 	}
 
-	bucketID := target.Xor(t.self).PrefixLen()
+	bucketID := t.BucketIndex(target)
 	bucket := t.Bucket(bucketID)
 
 	var element *list.Element
@@ -75,18 +143,173 @@ func (t *RoutingTable) Update(target peer.ID) {
 		}
 	}
 
+	inserted := true
+
 	if element == nil {
-		// Populate bucket if its not full.
-		if bucket.Len() <= BucketSize {
+		// Populate bucket if its not full, or make room by evicting an unverified peer.
+		if bucket.Len() <= BucketSize || t.evictUnverified(bucket, bucketID) {
 			bucket.PushFront(target)
+			t.emit(EventPeerAdded, target, bucketID)
+		} else {
+			inserted = false
 		}
 	} else {
+		element.Value = target
 		bucket.MoveToFront(element)
 	}
 
+	if inserted {
+		t.entriesMutex.Lock()
+		e := t.entry(target)
+		verified := e.verified
+		e.verified = true
+		e.lastSeen = t.clock.Now()
+		t.entriesMutex.Unlock()
+
+		if !verified {
+			t.emit(EventPeerVerified, target, bucketID)
+		}
+	}
+
 	bucket.mutex.Unlock()
 }
 
+// AddUnverified adds a peer which has only been heard of from other peers to the back
+// of a bucket in the routing table, should it not already exist and should the bucket
+// not be full. Returns true if the peer was added.
+func (t *RoutingTable) AddUnverified(target peer.ID) bool {
+	if len(t.self.PublicKey) != len(target.PublicKey) {
+		return false
+	}
+
+	bucketID := t.BucketIndex(target)
+	bucket := t.Bucket(bucketID)
+
+	bucket.mutex.Lock()
+	defer bucket.mutex.Unlock()
+
+	for e := bucket.Front(); e != nil; e = e.Next() {
+		if e.Value.(peer.ID).Equals(target) {
+			return false
+		}
+	}
+
+	if bucket.Len() > BucketSize {
+		return false
+	}
+
+	bucket.PushBack(target)
+
+	t.entriesMutex.Lock()
+	t.entry(target)
+	t.entriesMutex.Unlock()
+
+	t.emit(EventPeerAdded, target, bucketID)
+
+	return true
+}
+
+// IsVerified returns true should a peer within the routing table have been heard
+// from directly.
+func (t *RoutingTable) IsVerified(target peer.ID) bool {
+	t.entriesMutex.Lock()
+	defer t.entriesMutex.Unlock()
+
+	e, exists := t.entries[target.PublicKeyHex()]
+	return exists && e.verified
+}
+
+// PruneUnverified removes all peers which have remained unverified for longer than
+// maxAge, and returns them.
+func (t *RoutingTable) PruneUnverified(maxAge time.Duration) (pruned []peer.ID) {
+	var stale []string
+
+	t.entriesMutex.Lock()
+	now := t.clock.Now()
+	for key, e := range t.entries {
+		if !e.verified && now.Sub(e.added) > maxAge {
+			stale = append(stale, key)
+		}
+	}
+	t.entriesMutex.Unlock()
+
+	if len(stale) == 0 {
+		return
+	}
+
+	keys := make(map[string]struct{}, len(stale))
+	for _, key := range stale {
+		keys[key] = struct{}{}
+	}
+
+	for bucketID, bucket := range t.buckets {
+		bucket.mutex.Lock()
+
+		for e := bucket.Front(); e != nil; {
+			next := e.Next()
+			id := e.Value.(peer.ID)
+
+			if _, exists := keys[id.PublicKeyHex()]; exists && !t.IsVerified(id) && !t.IsProtected(id) {
+				bucket.Remove(e)
+
+				t.entriesMutex.Lock()
+				delete(t.entries, id.PublicKeyHex())
+				t.entriesMutex.Unlock()
+
+				pruned = append(pruned, id)
+
+				t.emit(EventPeerRemoved, id, bucketID)
+			}
+
+			e = next
+		}
+
+		bucket.mutex.Unlock()
+	}
+
+	return
+}
+
+// entry returns the liveness of a peer, creating it should it not exist. Must be called
+// with the entries mutex held.
+func (t *RoutingTable) entry(target peer.ID) *entry {
+	key := target.PublicKeyHex()
+
+	e, exists := t.entries[key]
+	if !exists {
+		e = &entry{added: t.clock.Now()}
+		t.entries[key] = e
+	}
+
+	return e
+}
+
+// evictUnverified removes the least recently added unverified peer from a bucket.
+// Must be called with the bucket mutex held. Returns true if a peer was evicted.
+func (t *RoutingTable) evictUnverified(bucket *Bucket, bucketID int) bool {
+	t.entriesMutex.Lock()
+	defer t.entriesMutex.Unlock()
+
+	for e := bucket.Back(); e != nil; e = e.Prev() {
+		id := e.Value.(peer.ID)
+		key := id.PublicKeyHex()
+
+		if t.isProtected(id) {
+			continue
+		}
+
+		if entry, exists := t.entries[key]; !exists || !entry.verified {
+			bucket.Remove(e)
+			delete(t.entries, key)
+
+			t.emit(EventPeerRemoved, id, bucketID)
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetPeers returns an unique list of all peers within the routing network (excluding yourself).
 func (t *RoutingTable) GetPeers() (peers []peer.ID) {
 	visited := make(map[string]struct{})
@@ -133,7 +356,7 @@ func (t *RoutingTable) GetPeerAddresses() (peers []string) {
 
 // RemovePeer removes a peer from the routing table. O(bucket_size).
 func (t *RoutingTable) RemovePeer(target peer.ID) bool {
-	bucketID := target.Xor(t.self).PrefixLen()
+	bucketID := t.BucketIndex(target)
 	bucket := t.Bucket(bucketID)
 
 	bucket.mutex.Lock()
@@ -142,7 +365,13 @@ func (t *RoutingTable) RemovePeer(target peer.ID) bool {
 		if e.Value.(peer.ID).Equals(target) {
 			bucket.Remove(e)
 
+			t.entriesMutex.Lock()
+			delete(t.entries, target.PublicKeyHex())
+			t.entriesMutex.Unlock()
+
 			bucket.mutex.Unlock()
+
+			t.emit(EventPeerRemoved, e.Value.(peer.ID), bucketID)
 			return true
 		}
 	}
@@ -154,7 +383,7 @@ func (t *RoutingTable) RemovePeer(target peer.ID) bool {
 
 // PeerExists check if a peer exists in the routing table. O(bucket_size).
 func (t *RoutingTable) PeerExists(target peer.ID) bool {
-	bucketID := target.Xor(t.self).PrefixLen()
+	bucketID := t.BucketIndex(target)
 	bucket := t.Bucket(bucketID)
 
 	bucket.mutex.Lock()
@@ -176,7 +405,7 @@ func (t *RoutingTable) FindClosestPeers(target peer.ID, count int) (peers []peer
 		return []peer.ID{}
 	}
 
-	bucketID := target.Xor(t.self).PrefixLen()
+	bucketID := t.BucketIndex(target)
 	bucket := t.Bucket(bucketID)
 
 	bucket.mutex.RLock()
@@ -208,11 +437,25 @@ func (t *RoutingTable) FindClosestPeers(target peer.ID, count int) (peers []peer
 	}
 
 	// Sort peers by XOR distance.
-	sort.Slice(peers, func(i, j int) bool {
-		left := peers[i].Xor(target)
-		right := peers[j].Xor(target)
-		return left.Less(right)
-	})
+	peer.SortByDistance(target, peers)
+
+	if len(peers) > count {
+		peers = peers[:count]
+	}
+
+	return peers
+}
+
+// ClosestPeers returns the count peers within the routing table closest to a target
+// by XOR distance, excluding this node.
+func (t *RoutingTable) ClosestPeers(target peer.ID, count int) []peer.ID {
+	var peers []peer.ID
+
+	for _, id := range t.FindClosestPeers(target, count+1) {
+		if !id.Equals(t.self) {
+			peers = append(peers, id)
+		}
+	}
 
 	if len(peers) > count {
 		peers = peers[:count]
@@ -221,6 +464,17 @@ func (t *RoutingTable) FindClosestPeers(target peer.ID, count int) (peers []peer
 	return peers
 }
 
+// BucketIndex returns the index of the bucket a peer belongs to within the routing
+// table, being the length of the prefix it shares with this node.
+func (t *RoutingTable) BucketIndex(target peer.ID) int {
+	return target.Xor(t.self).PrefixLen()
+}
+
+// NumBuckets returns the number of buckets within the routing table.
+func (t *RoutingTable) NumBuckets() int {
+	return len(t.buckets)
+}
+
 // Bucket returns a specific Bucket by id
 func (t *RoutingTable) Bucket(id int) *Bucket {
 	if id >= 0 && id < len(t.buckets) {
@@ -228,3 +482,39 @@ func (t *RoutingTable) Bucket(id int) *Bucket {
 	}
 	return nil
 }
+
+// EstimateSize estimates the number of nodes within the network, ourselves included,
+// from the density of the routing table. Buckets closer to us than the first bucket
+// which is not full are assumed to hold all nodes sharing their prefix with us, which
+// occupy a known share of the keyspace.
+func (t *RoutingTable) EstimateSize() int {
+	first := len(t.buckets)
+	nodes := 1
+
+	for i, bucket := range t.buckets {
+		bucket.mutex.RLock()
+		count := bucket.Len()
+		bucket.mutex.RUnlock()
+
+		// Do not count ourselves.
+		if i == len(t.buckets)-1 && t.PeerExists(t.self) {
+			count--
+		}
+
+		if first == len(t.buckets) && count < BucketSize {
+			first = i
+		}
+
+		if i >= first {
+			nodes += count
+		}
+	}
+
+	// Nodes sharing a prefix of the first non-full bucket's length with us occupy
+	// 1/2^first of the keyspace.
+	if first > 62 {
+		first = 62
+	}
+
+	return nodes << uint(first)
+}
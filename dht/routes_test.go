@@ -9,9 +9,11 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/types/clock"
 )
 
 func MustReadRand(size int) []byte {
@@ -194,3 +196,159 @@ func TestRoutingTable(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestUpdateAddress(t *testing.T) {
+	id1 := peer.CreateID("0000", MustReadRand(32))
+	id2 := peer.CreateID("0001", MustReadRand(32))
+
+	routingTable := CreateRoutingTable(id1)
+	routingTable.Update(id2)
+
+	moved := peer.CreateID("0002", id2.PublicKey)
+	routingTable.Update(moved)
+
+	addresses := routingTable.GetPeerAddresses()
+	if len(addresses) != 1 || addresses[0] != "0002" {
+		t.Fatalf("expected peer address to be updated, got %v", addresses)
+	}
+}
+
+func TestPruneUnverified(t *testing.T) {
+	mock := clock.NewMock(time.Unix(0, 0))
+
+	id1 := peer.CreateID("0000", MustReadRand(32))
+	id2 := peer.CreateID("0001", MustReadRand(32))
+	id3 := peer.CreateID("0002", MustReadRand(32))
+
+	routingTable := CreateRoutingTable(id1)
+	routingTable.SetClock(mock)
+
+	if !routingTable.AddUnverified(id2) || !routingTable.AddUnverified(id3) {
+		t.Fatal("expected unverified peers to be added")
+	}
+
+	if routingTable.AddUnverified(id2) {
+		t.Fatal("expected existing peer to not be re-added")
+	}
+
+	routingTable.Update(id3)
+
+	if routingTable.IsVerified(id2) || !routingTable.IsVerified(id3) {
+		t.Fatal("expected only peers heard from directly to be verified")
+	}
+
+	mock.Add(2 * time.Minute)
+
+	pruned := routingTable.PruneUnverified(1 * time.Minute)
+	if len(pruned) != 1 || !pruned[0].Equals(id2) {
+		t.Fatalf("expected unverified peer to be pruned, got %v", pruned)
+	}
+
+	if routingTable.PeerExists(id2) || !routingTable.PeerExists(id3) {
+		t.Fatal("expected only unverified peer to be removed")
+	}
+}
+
+func TestPruneUnverifiedSkipsProtected(t *testing.T) {
+	mock := clock.NewMock(time.Unix(0, 0))
+
+	id1 := peer.CreateID("0000", MustReadRand(32))
+	id2 := peer.CreateID("0001", MustReadRand(32))
+
+	routingTable := CreateRoutingTable(id1)
+	routingTable.SetClock(mock)
+	routingTable.SetProtector(func(id peer.ID) bool { return id.Equals(id2) })
+
+	routingTable.AddUnverified(id2)
+
+	mock.Add(2 * time.Minute)
+
+	if pruned := routingTable.PruneUnverified(1 * time.Minute); len(pruned) != 0 {
+		t.Fatalf("expected protected peer to not be pruned, got %v", pruned)
+	}
+
+	if !routingTable.PeerExists(id2) {
+		t.Fatal("expected protected peer to remain in the routing table")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	id1 := peer.CreateID("0000", MustReadRand(32))
+	id2 := peer.CreateID("0001", MustReadRand(32))
+
+	routingTable := CreateRoutingTable(id1)
+
+	events, unsubscribe := routingTable.Subscribe()
+	defer unsubscribe()
+
+	routingTable.AddUnverified(id2)
+	routingTable.Update(id2)
+	routingTable.Update(id2)
+	routingTable.RemovePeer(id2)
+
+	bucketID := id2.Xor(id1).PrefixLen()
+
+	for _, expected := range []EventType{EventPeerAdded, EventPeerVerified, EventPeerRemoved} {
+		select {
+		case event := <-events:
+			if event.Type != expected || !event.Peer.Equals(id2) || event.Bucket != bucketID {
+				t.Fatalf("expected %s event for peer in bucket %d, got %s event %+v", expected, bucketID, event.Type, event)
+			}
+		default:
+			t.Fatalf("expected %s event", expected)
+		}
+	}
+
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestClosestPeersExcludesSelf(t *testing.T) {
+	self := peer.CreateID("0000", MustReadRand(32))
+	routingTable := CreateRoutingTable(self)
+
+	for i := 0; i < 4; i++ {
+		routingTable.Update(peer.CreateID("000"+string('1'+byte(i)), MustReadRand(32)))
+	}
+
+	closest := routingTable.ClosestPeers(self, 3)
+	if len(closest) != 3 {
+		t.Fatalf("expected 3 peers, got %d", len(closest))
+	}
+
+	for _, id := range closest {
+		if id.Equals(self) {
+			t.Fatal("expected self to be excluded")
+		}
+		if routingTable.BucketIndex(id) != peer.CommonPrefixLen(id, self) {
+			t.Fatal("expected bucket index to be the common prefix length")
+		}
+	}
+}
+
+func TestEstimateSize(t *testing.T) {
+	routes := CreateRoutingTable(peer.CreateID("0000", MustReadRand(32)))
+
+	if size := routes.EstimateSize(); size != 1 {
+		t.Fatalf("expected a lone node to estimate a network of 1, got %d", size)
+	}
+
+	for i := 0; i < 4; i++ {
+		routes.Update(peer.CreateID(hex.EncodeToString(MustReadRand(4)), MustReadRand(32)))
+	}
+
+	if size := routes.EstimateSize(); size != 5 {
+		t.Fatalf("expected a table which is not full to be counted exactly, got %d", size)
+	}
+
+	for i := 0; i < 4096; i++ {
+		routes.Update(peer.CreateID(hex.EncodeToString(MustReadRand(4)), MustReadRand(32)))
+	}
+
+	if size := routes.EstimateSize(); size < 1024 || size > 16384 {
+		t.Fatalf("expected a network of 4101 nodes to be estimated within a factor of 4, got %d", size)
+	}
+}
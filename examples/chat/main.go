@@ -19,7 +19,7 @@ type ChatPlugin struct{ *network.Plugin }
 func (state *ChatPlugin) Receive(ctx *network.PluginContext) error {
 	switch msg := ctx.Message().(type) {
 	case *messages.ChatMessage:
-		glog.Infof("<%s> %s", ctx.Client().ID.Address, msg.Message)
+		glog.Infof("<%s> %s", ctx.Client().ID().Address, msg.Message)
 	}
 
 	return nil
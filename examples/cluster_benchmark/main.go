@@ -18,6 +18,7 @@ import (
 	"github.com/perlin-network/noise/network/backoff"
 	"github.com/perlin-network/noise/network/builders"
 	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/schedule"
 )
 
 const MESSAGE_THRESHOLD uint64 = 2000
@@ -127,14 +128,14 @@ func main() {
 		net.Bootstrap(peers...)
 	}
 
-	go func() {
-		for range time.Tick(1 * time.Second) {
-			currentNumMessages := atomic.SwapUint64(&numMessages, 0)
-			glog.Infof("Got %d messages, %d peers", currentNumMessages, atomic.LoadInt64(&numPeers))
-		}
-	}()
+	net.Scheduler().Repeat(schedule.Every(1*time.Second), func() {
+		currentNumMessages := atomic.SwapUint64(&numMessages, 0)
+		glog.Infof("Got %d messages, %d peers", currentNumMessages, atomic.LoadInt64(&numPeers))
+	})
 
-	for range time.Tick(300 * time.Millisecond) {
+	net.Scheduler().Repeat(schedule.Every(300*time.Millisecond), func() {
 		sendBroadcast(net)
-	}
+	})
+
+	select {}
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/perlin-network/noise/examples/local_benchmark/messages"
 	"github.com/perlin-network/noise/network"
 	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/schedule"
 	"log"
 	"net/http"
 	"os"
@@ -78,10 +79,12 @@ func main() {
 
 	fmt.Println("Waiting for sender on kcp://localhost:3001.")
 
-	// Run loop every 1 second.
-	for range time.Tick(1 * time.Second) {
+	// Run every 1 second.
+	net.Scheduler().Repeat(schedule.Every(1*time.Second), func() {
 		fmt.Printf("Got %d messages.\n", state.counter)
 
 		state.counter = 0
-	}
+	})
+
+	select {}
 }
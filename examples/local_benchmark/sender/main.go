@@ -72,7 +72,7 @@ func main() {
 	}
 
 	for {
-		err = client.Tell(&messages.BasicMessage{})
+		_, err = client.Tell(&messages.BasicMessage{})
 		if err != nil {
 			panic(err)
 		}
@@ -47,7 +47,7 @@ type ExampleServerPlugin struct {
 }
 
 func (state *ExampleServerPlugin) PeerConnect(client *network.PeerClient) {
-	glog.Infof("New connection from %s.", client.Address)
+	glog.Infof("New connection from %s.", client.Address())
 
 	go state.handleClient(client)
 }
@@ -64,7 +64,7 @@ func (state *ExampleServerPlugin) handleClient(client *network.PeerClient) {
 			break
 		}
 
-		glog.Infof("New incoming stream from %s.", client.Address)
+		glog.Infof("New incoming stream from %s.", client.Address())
 
 		go func() {
 			defer stream.Close()
@@ -82,7 +82,7 @@ func (state *ExampleServerPlugin) handleClient(client *network.PeerClient) {
 }
 
 func (state *ExampleServerPlugin) PeerDisconnect(client *network.PeerClient) {
-	glog.Infof("Lost connection with %s.", client.Address)
+	glog.Infof("Lost connection with %s.", client.Address())
 }
 
 type ProxyServerPlugin struct {
@@ -91,7 +91,7 @@ type ProxyServerPlugin struct {
 }
 
 func (state *ProxyServerPlugin) PeerConnect(client *network.PeerClient) {
-	glog.Infof("Connected to proxy destination %s.", client.Address)
+	glog.Infof("Connected to proxy destination %s.", client.Address())
 
 	go state.startProxying(client)
 }
@@ -115,7 +115,7 @@ func (state *ProxyServerPlugin) startProxying(client *network.PeerClient) {
 			glog.Fatal(err)
 		}
 
-		glog.Infof("Proxying data from %s to %s.", conn.RemoteAddr().String(), client.Address)
+		glog.Infof("Proxying data from %s to %s.", conn.RemoteAddr().String(), client.Address())
 
 		go func() {
 			defer conn.Close()
@@ -133,7 +133,7 @@ func (state *ProxyServerPlugin) startProxying(client *network.PeerClient) {
 }
 
 func (state *ProxyServerPlugin) PeerDisconnect(client *network.PeerClient) {
-	glog.Infof("Lost connection with proxy destination %s.", client.Address)
+	glog.Infof("Lost connection with proxy destination %s.", client.Address())
 }
 
 // An example showcasing how to use streams in Noise by creating a sample proxying server.
@@ -0,0 +1,15 @@
+// Package interop holds the integration tests verifying that nodes of this fork
+// handshake, discover and message with nodes of the upstream perlin-network/noise
+// implementation, so that they may join existing networks.
+//
+// The tests are run against an in-process stand-in for an upstream node, and, should
+// NOISE_UPSTREAM_ADDRESSES be set, against upstream nodes which run the discovery
+// plugin and are already listening, e.g. the upstream getting_started example:
+//
+//	go run github.com/perlin-network/noise/examples/getting_started -host 127.0.0.1 -port 3000
+//	NOISE_UPSTREAM_ADDRESSES=tcp://127.0.0.1:3000 go test ./interop
+//
+// Peers running upstream do not stamp envelopes with versions, and are hence spoken
+// to using MinEnvelopeVersion. Neither do they answer identity challenges, and are
+// hence not challenged.
+package interop
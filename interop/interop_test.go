@@ -0,0 +1,133 @@
+package interop
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/networktest"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+const handshakeTimeout = 5 * time.Second
+
+// upstreamAddresses returns the addresses of the upstream nodes to test against.
+func upstreamAddresses(t *testing.T) []string {
+	addresses := os.Getenv("NOISE_UPSTREAM_ADDRESSES")
+	if len(addresses) == 0 {
+		t.Skip("NOISE_UPSTREAM_ADDRESSES is not set")
+	}
+
+	return strings.Split(addresses, ",")
+}
+
+func buildNode(t *testing.T) (*network.Network, *discovery.Plugin) {
+	plugin := &discovery.Plugin{
+		// Upstream nodes may be run behind Docker networks or NATs.
+		AllowUnverifiedAddresses: true,
+	}
+
+	return networktest.NewNode(t, plugin), plugin
+}
+
+// identified waits for the upstream peer at address to identify itself.
+func identified(t *testing.T, node *network.Network, address string) *network.PeerClient {
+	deadline := time.Now().Add(handshakeTimeout)
+
+	for time.Now().Before(deadline) {
+		if value, ok := node.Peers.Load(address); ok {
+			if client := value.(*network.PeerClient); client.ID() != nil {
+				return client
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	t.Fatalf("upstream peer %s never identified itself", address)
+	return nil
+}
+
+func TestHandshake(t *testing.T) {
+	addresses := upstreamAddresses(t)
+
+	node, _ := buildNode(t)
+	defer node.Close()
+
+	node.Bootstrap(addresses...)
+
+	for _, address := range addresses {
+		client := identified(t, node, address)
+
+		if client.EnvelopeVersion() != network.MinEnvelopeVersion {
+			t.Fatalf("expected upstream peer %s to speak envelope version %d, got %d", address, network.MinEnvelopeVersion, client.EnvelopeVersion())
+		}
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	addresses := upstreamAddresses(t)
+
+	node, plugin := buildNode(t)
+	defer node.Close()
+
+	node.Bootstrap(addresses...)
+
+	for _, address := range addresses {
+		identified(t, node, address)
+	}
+
+	// Upstream nodes should have replied to our ping, and be within our routing table.
+	deadline := time.Now().Add(handshakeTimeout)
+
+	for len(plugin.Routes.GetPeerAddresses()) < len(addresses) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d upstream peers in the routing table, got %v", len(addresses), plugin.Routes.GetPeerAddresses())
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// Upstream nodes should be able to find us by our ID.
+	found := false
+
+	for _, id := range discovery.FindNode(node, node.ID, 3, 8) {
+		if id.Address == addresses[0] {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected lookup through upstream peers to return %s", addresses[0])
+	}
+}
+
+func TestMessaging(t *testing.T) {
+	addresses := upstreamAddresses(t)
+
+	node, _ := buildNode(t)
+	defer node.Close()
+
+	node.Bootstrap(addresses[0])
+
+	client := identified(t, node, addresses[0])
+
+	target := protobuf.ID(node.ID)
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.LookupNodeRequest{Target: &target})
+	request.SetTimeout(handshakeTimeout)
+
+	response, err := client.Request(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := response.(*protobuf.LookupNodeResponse); !ok {
+		t.Fatalf("expected a lookup response from upstream peer, got %T", response)
+	}
+}
@@ -0,0 +1,279 @@
+package interop
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/hashing/blake2b"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/xtaci/smux"
+)
+
+// upstreamPeer stands in for a node running upstream, such that interop may be tested
+// without upstream nodes at hand. Like upstream, it neither stamps envelopes with
+// versions nor understands identity challenges, dials back to the peers connecting to
+// it, answers pings with pongs, and only verifies signatures made as upstream makes
+// them.
+type upstreamPeer struct {
+	t *testing.T
+
+	keys *crypto.KeyPair
+	id   protobuf.ID
+
+	listener net.Listener
+
+	mutex    sync.Mutex
+	sessions map[string]*smux.Session
+	nonces   map[string]uint64
+
+	// verified delivers the messages received whose signatures upstream verifies, and
+	// unverified those whose signatures it does not.
+	verified   chan *protobuf.Message
+	unverified chan *protobuf.Message
+}
+
+func startUpstreamPeer(t *testing.T, port uint16) *upstreamPeer {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := ed25519.RandomKeyPair()
+
+	p := &upstreamPeer{
+		t:          t,
+		keys:       keys,
+		id:         protobuf.ID{Address: network.FormatAddress("tcp", "127.0.0.1", port), PublicKey: keys.PublicKey},
+		listener:   listener,
+		sessions:   make(map[string]*smux.Session),
+		nonces:     make(map[string]uint64),
+		verified:   make(chan *protobuf.Message, 64),
+		unverified: make(chan *protobuf.Message, 64),
+	}
+
+	go p.accept()
+
+	return p
+}
+
+func (p *upstreamPeer) close() {
+	p.listener.Close()
+
+	p.mutex.Lock()
+	for _, session := range p.sessions {
+		session.Close()
+	}
+	p.mutex.Unlock()
+}
+
+func (p *upstreamPeer) accept() {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		session, err := smux.Server(conn, smux.DefaultConfig())
+		if err != nil {
+			conn.Close()
+			continue
+		}
+
+		go p.serve(session)
+	}
+}
+
+func (p *upstreamPeer) serve(session *smux.Session) {
+	for {
+		stream, err := session.AcceptStream()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer stream.Close()
+
+			msg, err := readUpstreamMessage(stream)
+			if err != nil {
+				return
+			}
+
+			// Upstream signs the sender and payload of envelopes, and nothing else.
+			if !crypto.Verify(ed25519.New(), blake2b.New(), msg.Sender.PublicKey, upstreamPayload(msg.Sender, msg.Message.Value), msg.Signature) {
+				p.unverified <- msg
+				return
+			}
+
+			p.verified <- msg
+
+			// Upstream ignores messages of types it does not know, such as challenges.
+			if ptypes.Is(msg.Message, (*protobuf.Ping)(nil)) {
+				if err := p.tell(msg.Sender.Address, &protobuf.Pong{}); err != nil {
+					p.t.Logf("upstream stand-in failed to answer ping [err=%s]", err)
+				}
+			}
+		}()
+	}
+}
+
+// tell sends a message over the session dialed to an address, dialing it should it not
+// be dialed yet, as upstream dials back to the peers connecting to it.
+func (p *upstreamPeer) tell(address string, message proto.Message) error {
+	p.mutex.Lock()
+	session, exists := p.sessions[address]
+	if !exists {
+		addrInfo, err := network.ParseAddress(address)
+		if err != nil {
+			p.mutex.Unlock()
+			return err
+		}
+
+		conn, err := net.Dial("tcp", addrInfo.HostPort())
+		if err != nil {
+			p.mutex.Unlock()
+			return err
+		}
+
+		if session, err = smux.Client(conn, smux.DefaultConfig()); err != nil {
+			p.mutex.Unlock()
+			return err
+		}
+
+		p.sessions[address] = session
+	}
+
+	// Upstream numbers the messages it sends over each session, such that they are
+	// handled in order.
+	p.nonces[address]++
+	nonce := p.nonces[address]
+	p.mutex.Unlock()
+
+	raw, err := ptypes.MarshalAny(message)
+	if err != nil {
+		return err
+	}
+
+	signature, err := p.keys.Sign(ed25519.New(), blake2b.New(), upstreamPayload(&p.id, raw.Value))
+	if err != nil {
+		return err
+	}
+
+	stream, err := session.OpenStream()
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	bytes, err := proto.Marshal(&protobuf.Message{Message: raw, Sender: &p.id, Signature: signature, MessageNonce: nonce})
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, binary.MaxVarintLen64)
+	binary.PutUvarint(header, uint64(len(bytes)))
+
+	_, err = stream.Write(append(header, bytes...))
+	return err
+}
+
+// upstreamPayload returns what upstream signs of an envelope.
+func upstreamPayload(sender *protobuf.ID, value []byte) []byte {
+	payload := make([]byte, 4+len(sender.Address)+4+len(sender.PublicKey)+len(value))
+
+	binary.LittleEndian.PutUint32(payload, uint32(len(sender.Address)))
+	pos := 4 + copy(payload[4:], sender.Address)
+
+	binary.LittleEndian.PutUint32(payload[pos:], uint32(len(sender.PublicKey)))
+	pos += 4 + copy(payload[pos+4:], sender.PublicKey)
+
+	copy(payload[pos:], value)
+
+	return payload
+}
+
+func readUpstreamMessage(stream net.Conn) (*protobuf.Message, error) {
+	stream.SetReadDeadline(time.Now().Add(handshakeTimeout))
+
+	reader := bufio.NewReader(stream)
+
+	header := make([]byte, binary.MaxVarintLen64)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+
+	size, _ := binary.Uvarint(header)
+
+	bytes := make([]byte, size)
+	if _, err := io.ReadFull(reader, bytes); err != nil {
+		return nil, err
+	}
+
+	msg := new(protobuf.Message)
+	if err := proto.Unmarshal(bytes, msg); err != nil {
+		return nil, err
+	}
+
+	if msg.Message == nil || msg.Sender == nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return msg, nil
+}
+
+func TestUpstreamHandshake(t *testing.T) {
+	upstream := startUpstreamPeer(t, 13233)
+	defer upstream.close()
+
+	node, _ := buildNode(t)
+	defer node.Close()
+
+	node.Bootstrap(upstream.id.Address)
+
+	// The upstream peer identifies itself once it dials back to us, without being
+	// challenged to, as it would not answer.
+	client := identified(t, node, upstream.id.Address)
+
+	if client.EnvelopeVersion() != network.MinEnvelopeVersion {
+		t.Fatalf("expected the upstream peer to speak envelope version %d, got %d", network.MinEnvelopeVersion, client.EnvelopeVersion())
+	}
+}
+
+func TestUpstreamSignatures(t *testing.T) {
+	upstream := startUpstreamPeer(t, 13235)
+	defer upstream.close()
+
+	node, _ := buildNode(t)
+	defer node.Close()
+
+	node.Bootstrap(upstream.id.Address)
+
+	client := identified(t, node, upstream.id.Address)
+
+	if _, err := client.Tell(&protobuf.LookupNodeRequest{Target: &upstream.id}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Our ping and request are signed as upstream verifies signatures, rather than at
+	// our own envelope version.
+	for received := 0; received < 2; {
+		select {
+		case <-upstream.verified:
+			received++
+		case msg := <-upstream.unverified:
+			t.Fatalf("expected upstream to verify our %s signed at version %d", msg.Message.TypeUrl, msg.Version)
+		case <-time.After(handshakeTimeout):
+			t.Fatal("timed out waiting for upstream to receive our messages")
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package mobile is a reduced, callback-based facade over a noise node whose API only
+// uses types supported by gomobile, so that Android and iOS bindings may be generated
+// for light clients with:
+//
+//	gomobile bind github.com/perlin-network/noise/mobile
+package mobile
+
+import (
+	"context"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// MessageHandler is called with data sent to the node by peers.
+type MessageHandler interface {
+	// OnMessage is called with the hex-encoded public key and address of the sender,
+	// and the data it sent.
+	OnMessage(sender string, address string, data []byte)
+}
+
+// PeerHandler is called upon peers connecting to and disconnecting from the node.
+type PeerHandler interface {
+	OnPeerConnected(address string)
+	OnPeerDisconnected(address string)
+}
+
+// Node is a noise node.
+type Node struct {
+	net    *network.Network
+	plugin *plugin
+}
+
+// NewNode creates a node listening on a host and port over TCP. Should privateKey be
+// empty, a random Ed25519 keypair is generated; otherwise it is a hex-encoded Ed25519
+// private key.
+func NewNode(host string, port int, privateKey string) (*Node, error) {
+	var keys *crypto.KeyPair
+
+	if len(privateKey) == 0 {
+		keys = ed25519.RandomKeyPair()
+	} else {
+		var err error
+
+		keys, err = crypto.FromPrivateKey(ed25519.New(), privateKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid private key")
+		}
+	}
+
+	if port <= 0 || port > 65535 {
+		return nil, errors.Errorf("invalid port %d", port)
+	}
+
+	plugin := new(plugin)
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(keys)
+	builder.SetAddress(network.FormatAddress("tcp", host, uint16(port)))
+
+	if err := builder.AddPlugin(new(discovery.Plugin)); err != nil {
+		return nil, err
+	}
+
+	if err := builder.AddPlugin(plugin); err != nil {
+		return nil, err
+	}
+
+	net, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Node{net: net, plugin: plugin}, nil
+}
+
+// SetMessageHandler sets the handler called with data sent to the node. Should be set
+// before the node is started.
+func (n *Node) SetMessageHandler(handler MessageHandler) {
+	n.plugin.messages = handler
+}
+
+// SetPeerHandler sets the handler called upon peers connecting and disconnecting.
+// Should be set before the node is started.
+func (n *Node) SetPeerHandler(handler PeerHandler) {
+	n.plugin.peers = handler
+}
+
+// Start starts listening for peers, returning once the node is listening or has
+// failed to listen.
+func (n *Node) Start() error {
+	return n.net.Start()
+}
+
+// Stop stops the node.
+func (n *Node) Stop() {
+	n.net.Close()
+}
+
+// Address returns the address the node listens on.
+func (n *Node) Address() string {
+	return n.net.Address
+}
+
+// PublicKey returns the hex-encoded public key of the node.
+func (n *Node) PublicKey() string {
+	return n.net.ID.PublicKeyHex()
+}
+
+// Bootstrap connects to a peer by its address.
+func (n *Node) Bootstrap(address string) error {
+	return n.net.BootstrapWithOptions(context.Background(), network.BootstrapOptions{MaxAttempts: 1}, address)
+}
+
+// Send sends data to a peer by its address.
+func (n *Node) Send(address string, data []byte) error {
+	client, err := n.net.Client(address)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Tell(&protobuf.Datagram{Data: data})
+	return err
+}
+
+// Broadcast sends data to all connected peers, and returns the number of peers it was
+// sent to.
+func (n *Node) Broadcast(data []byte) int {
+	return len(n.net.Broadcast(&protobuf.Datagram{Data: data}))
+}
+
+// PeerCount returns the number of connected peers.
+func (n *Node) PeerCount() int {
+	return n.net.Stats().ConnectedPeers
+}
+
+// plugin delivers datagrams and peer events to the handlers of a node.
+type plugin struct {
+	*network.Plugin
+
+	messages MessageHandler
+	peers    PeerHandler
+}
+
+func (p *plugin) Receive(ctx *network.PluginContext) error {
+	datagram, ok := ctx.Message().(*protobuf.Datagram)
+	if !ok || p.messages == nil {
+		return nil
+	}
+
+	p.messages.OnMessage(ctx.Sender().PublicKeyHex(), ctx.Client().Address(), datagram.Data)
+
+	return nil
+}
+
+func (p *plugin) PeerConnect(client *network.PeerClient) {
+	if p.peers != nil {
+		p.peers.OnPeerConnected(client.Address())
+	}
+}
+
+func (p *plugin) PeerDisconnect(client *network.PeerClient) {
+	if p.peers != nil {
+		p.peers.OnPeerDisconnected(client.Address())
+	}
+}
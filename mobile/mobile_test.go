@@ -0,0 +1,74 @@
+package mobile
+
+import (
+	"testing"
+	"time"
+)
+
+type message struct {
+	sender string
+	data   []byte
+}
+
+type handler struct {
+	messages  chan message
+	connected chan string
+}
+
+func (h *handler) OnMessage(sender string, address string, data []byte) {
+	h.messages <- message{sender: sender, data: data}
+}
+
+func (h *handler) OnPeerConnected(address string) {
+	h.connected <- address
+}
+
+func (h *handler) OnPeerDisconnected(address string) {}
+
+func TestNode(t *testing.T) {
+	alice, err := NewNode("127.0.0.1", 13090, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bob, err := NewNode("127.0.0.1", 13091, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &handler{messages: make(chan message, 1), connected: make(chan string, 4)}
+	bob.SetMessageHandler(h)
+	bob.SetPeerHandler(h)
+
+	alice.Start()
+	bob.Start()
+
+	defer alice.Stop()
+	defer bob.Stop()
+
+	if err := alice.Bootstrap(bob.Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := alice.Send(bob.Address(), []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-h.messages:
+		if msg.sender != alice.PublicKey() || string(msg.data) != "hello" {
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+
+	select {
+	case address := <-h.connected:
+		if address != alice.Address() {
+			t.Fatalf("expected %s to connect, got %s", alice.Address(), address)
+		}
+	default:
+		t.Fatal("expected peer connected event")
+	}
+}
@@ -76,8 +76,13 @@ func ParseAddress(address string) (*AddressInfo, error) {
 	}, nil
 }
 
-// ToUnifiedHost resolves a domain host.
+// ToUnifiedHost resolves a domain host. Onion hosts are left as is, as they may only
+// be resolved by Tor.
 func ToUnifiedHost(host string) (string, error) {
+	if IsOnionHost(host) {
+		return strings.ToLower(host), nil
+	}
+
 	unifiedHost, err := domainLookupCache.Get(host, func() (interface{}, error) {
 		if net.ParseIP(host) == nil {
 			// Probably a domain name is provided.
@@ -122,3 +127,13 @@ func ToUnifiedAddress(address string) (string, error) {
 
 	return info.String(), nil
 }
+
+// portString returns the port of an address as a string.
+func portString(info *AddressInfo) string {
+	return strconv.Itoa(int(info.Port))
+}
+
+// IsOnionHost returns true should a host be a Tor onion service address.
+func IsOnionHost(host string) bool {
+	return strings.HasSuffix(strings.ToLower(host), ".onion")
+}
@@ -39,3 +39,16 @@ func BenchmarkParseAddress(b *testing.B) {
 		}
 	}
 }
+
+func TestToUnifiedAddressOnion(t *testing.T) {
+	onion := "tor://ExampleOnionAddressabcdefghijklmnopqrstuvwxyz234567abcdefghij.onion:3000"
+
+	address, err := ToUnifiedAddress(onion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if address != strings.ToLower(onion) {
+		t.Fatalf("expected onion address to be left unresolved, got %s", address)
+	}
+}
@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/topology"
+)
+
+// gossipTimeout is how long peers are waited on for their neighbor lists.
+const gossipTimeout = 3 * time.Second
+
+// NewHandler returns an HTTP handler exposing administrative endpoints of a network.
+//
+// GET /topology?format=json|dot|graphml dumps the peer graph as seen by this node.
+// Should gossip=true be set, the neighbor lists of all peers in the routing table
+// are included.
+//
+// GET /stats dumps the connection history of all peers as JSON.
+//
+// GET /summaries dumps the summaries peers piggybacked onto their pings and pongs as
+// JSON, by their addresses.
+//
+// GET /messages dumps the message types registered into the network as JSON.
+//
+// GET /fingerprints dumps this node's config fingerprint and those of its peers as
+// JSON, for config drift across a fleet to be spotted.
+//
+// GET /deprecations dumps the message types this node deprecated along with the peers
+// still sending them, and the deprecations advertised by its peers, as JSON.
+func NewHandler(net *network.Network) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/topology", func(w http.ResponseWriter, r *http.Request) {
+		var graph *topology.Graph
+
+		if r.URL.Query().Get("gossip") == "true" {
+			graph = topology.Collect(net, gossipTimeout)
+		} else {
+			graph = topology.Snapshot(net)
+		}
+
+		var err error
+
+		switch r.URL.Query().Get("format") {
+		case "", "json":
+			w.Header().Set("Content-Type", "application/json")
+			err = graph.WriteJSON(w)
+		case "dot":
+			w.Header().Set("Content-Type", "text/vnd.graphviz")
+			err = graph.WriteDOT(w)
+		case "graphml":
+			w.Header().Set("Content-Type", "application/graphml+xml")
+			err = graph.WriteGraphML(w)
+		default:
+			http.Error(w, "unknown format; expected json, dot or graphml", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			glog.Error(err)
+		}
+	})
+
+	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(net.Stats()); err != nil {
+			glog.Error(err)
+		}
+	})
+
+	mux.HandleFunc("/summaries", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(net.NeighborSummaries()); err != nil {
+			glog.Error(err)
+		}
+	})
+
+	mux.HandleFunc("/messages", func(w http.ResponseWriter, r *http.Request) {
+		var types []network.MessageType
+		if net.Messages != nil {
+			types = net.Messages.Types()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(types); err != nil {
+			glog.Error(err)
+		}
+	})
+
+	mux.HandleFunc("/fingerprints", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		fingerprints := struct {
+			Self  string            `json:"self"`
+			Peers map[string]string `json:"peers"`
+		}{
+			Self:  hex.EncodeToString(net.ConfigFingerprint),
+			Peers: net.NeighborFingerprints(),
+		}
+
+		if err := encoder.Encode(fingerprints); err != nil {
+			glog.Error(err)
+		}
+	})
+
+	mux.HandleFunc("/deprecations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		deprecations := struct {
+			Self  []network.DeprecationUsage       `json:"self"`
+			Peers map[string][]network.Deprecation `json:"peers"`
+		}{
+			Self:  net.DeprecationUsage(),
+			Peers: net.NeighborDeprecations(),
+		}
+
+		if err := encoder.Encode(deprecations); err != nil {
+			glog.Error(err)
+		}
+	})
+
+	return mux
+}
+
+// ListenAndServe serves the administrative endpoints of a network over HTTP on an address.
+func ListenAndServe(address string, net *network.Network) error {
+	return http.ListenAndServe(address, NewHandler(net))
+}
@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+type memorySink struct {
+	records []*Record
+}
+
+func (s *memorySink) Write(record *Record) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func createMessage(id uint64) *protobuf.Message {
+	sender := protobuf.ID(peer.CreateID("tcp://127.0.0.1:3000", []byte("sender")))
+
+	messageID := make([]byte, 8)
+	binary.LittleEndian.PutUint64(messageID, id)
+
+	return &protobuf.Message{
+		Message: &any.Any{TypeUrl: "type.googleapis.com/protobuf.Bytes", Value: []byte("hello")},
+		Sender:  &sender,
+		Id:      messageID,
+	}
+}
+
+func TestPluginRecords(t *testing.T) {
+	sink := new(memorySink)
+	plugin := New(sink)
+
+	plugin.Inbound(new(network.PeerClient), createMessage(1))
+	plugin.Outbound("tcp://127.0.0.1:3001", createMessage(2))
+
+	if len(sink.records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(sink.records))
+	}
+
+	if sink.records[0].Direction != Inbound || sink.records[1].Direction != Outbound {
+		t.Fatal("records have the wrong direction")
+	}
+
+	if sink.records[0].Payload != nil {
+		t.Fatal("expected payload to not be recorded")
+	}
+
+	if sink.records[0].ID == sink.records[1].ID {
+		t.Fatal("expected distinct messages to have distinct IDs")
+	}
+
+	plugin.Payloads = true
+	plugin.Inbound(new(network.PeerClient), createMessage(3))
+
+	if string(sink.records[2].Payload) != "hello" {
+		t.Fatal("expected payload to be recorded")
+	}
+
+	plugin.SampleRate = 0
+	plugin.Inbound(new(network.PeerClient), createMessage(4))
+
+	if len(sink.records) != 3 {
+		t.Fatal("expected no records to be sampled")
+	}
+}
+
+func TestRotatingSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "audit.log")
+
+	sink, err := NewRotatingSink(path, 256, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := uint64(0); i < 10; i++ {
+		if err := sink.Write(NewRecord(Inbound, "tcp://127.0.0.1:3001", createMessage(i), false, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"audit.log", "audit.log.1", "audit.log.2"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "audit.log.3")); !os.IsNotExist(err) {
+		t.Fatal("expected at most 2 rotated files to be kept")
+	}
+}
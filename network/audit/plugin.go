@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"math/rand"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+)
+
+// Plugin records every inbound and outbound message envelope to a set of sinks.
+type Plugin struct {
+	*network.Plugin
+
+	// Sinks which audit records are written to.
+	Sinks []Sink
+
+	// SampleRate is the fraction of envelopes in [0, 1] which are recorded.
+	SampleRate float64
+
+	// Payloads denotes whether message payloads are recorded, or only their metadata.
+	Payloads bool
+
+	clock clock.Clock
+}
+
+var (
+	// PluginID to reference audit plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// New creates an audit plugin recording the metadata of all envelopes to a set of sinks.
+func New(sinks ...Sink) *Plugin {
+	return &Plugin{
+		Sinks:      sinks,
+		SampleRate: 1,
+	}
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.clock = net.Clock
+}
+
+// Inbound implements the plugin callback
+func (p *Plugin) Inbound(client *network.PeerClient, msg *protobuf.Message) {
+	p.record(Inbound, client.Address(), msg)
+}
+
+// Outbound implements the plugin callback
+func (p *Plugin) Outbound(address string, msg *protobuf.Message) {
+	p.record(Outbound, address, msg)
+}
+
+// Cleanup implements the plugin callback
+func (p *Plugin) Cleanup(net *network.Network) {
+	for _, sink := range p.Sinks {
+		if err := sink.Close(); err != nil {
+			glog.Error(err)
+		}
+	}
+}
+
+func (p *Plugin) record(direction Direction, address string, msg *protobuf.Message) {
+	if p.SampleRate < 1 && rand.Float64() >= p.SampleRate {
+		return
+	}
+
+	record := NewRecord(direction, address, msg, p.Payloads, p.clock)
+
+	for _, sink := range p.Sinks {
+		if err := sink.Write(record); err != nil {
+			glog.Errorf("failed to write audit record: %s", err)
+		}
+	}
+}
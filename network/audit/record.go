@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+)
+
+// Direction denotes whether an envelope was received or sent.
+type Direction string
+
+const (
+	Inbound  Direction = "inbound"
+	Outbound Direction = "outbound"
+)
+
+// Record is an audit log entry describing a single message envelope.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Direction Direction `json:"direction"`
+
+	// Address of the remote peer the envelope was received from or sent to.
+	Peer string `json:"peer"`
+
+	// Peer ID, in its canonical base58 encoding, and address of the sender of the
+	// envelope.
+	Sender        string `json:"sender"`
+	SenderAddress string `json:"sender_address"`
+
+	ID               string `json:"id"`
+	Nonce            uint64 `json:"nonce"`
+	LamportTimestamp uint64 `json:"lamport_timestamp,omitempty"`
+	RequestNonce     uint64 `json:"request_nonce,omitempty"`
+
+	Type string `json:"type"`
+	Size int    `json:"size"`
+
+	// Payload is the raw serialized message, and is only recorded should payloads be audited.
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// NewRecord creates an audit record describing a message envelope, timestamped by a
+// clock. The real clock is used should it be nil.
+func NewRecord(direction Direction, address string, msg *protobuf.Message, payload bool, c clock.Clock) *Record {
+	record := &Record{
+		Time:             clock.Or(c).Now(),
+		Direction:        direction,
+		Peer:             address,
+		ID:               network.NewMessageID(msg).String(),
+		Nonce:            msg.MessageNonce,
+		LamportTimestamp: msg.LamportTimestamp,
+		RequestNonce:     msg.RequestNonce,
+	}
+
+	if msg.Sender != nil {
+		record.Sender = peer.ID(*msg.Sender).String()
+		record.SenderAddress = msg.Sender.Address
+	}
+
+	if msg.Message != nil {
+		record.Type = msg.Message.TypeUrl
+		record.Size = len(msg.Message.Value)
+
+		if payload {
+			record.Payload = msg.Message.Value
+		}
+	}
+
+	return record
+}
@@ -0,0 +1,190 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Sink persists audit records.
+type Sink interface {
+	Write(record *Record) error
+	Close() error
+}
+
+// FileSink appends audit records as newline-delimited JSON to a file.
+type FileSink struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// NewFileSink opens or creates a file which audit records are appended to.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open audit log %s", path)
+	}
+
+	return &FileSink{file: file}, nil
+}
+
+// Write implements Sink.
+func (s *FileSink) Write(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit record")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close implements Sink.
+func (s *FileSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.file.Close()
+}
+
+// RotatingSink appends audit records as newline-delimited JSON to a file, rotating
+// the file once it grows beyond a maximum size. Rotated files are suffixed with .1
+// (most recent) up to .N (oldest), after which they are removed.
+type RotatingSink struct {
+	mutex sync.Mutex
+
+	path     string
+	maxBytes int64
+	maxFiles int
+
+	file *os.File
+	size int64
+}
+
+// NewRotatingSink opens or creates a file which audit records are appended to, keeping
+// at most maxFiles rotated files of roughly maxBytes each.
+func NewRotatingSink(path string, maxBytes int64, maxFiles int) (*RotatingSink, error) {
+	s := &RotatingSink{
+		path:     path,
+		maxBytes: maxBytes,
+		maxFiles: maxFiles,
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *RotatingSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open audit log %s", s.path)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrapf(err, "failed to stat audit log %s", s.path)
+	}
+
+	s.file = file
+	s.size = info.Size()
+
+	return nil
+}
+
+func (s *RotatingSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	// Shift rotated files along, dropping the oldest.
+	os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxFiles))
+
+	for i := s.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+	}
+
+	if s.maxFiles > 0 {
+		if err := os.Rename(s.path, s.path+".1"); err != nil {
+			return errors.Wrapf(err, "failed to rotate audit log %s", s.path)
+		}
+	} else if err := os.Remove(s.path); err != nil {
+		return errors.Wrapf(err, "failed to rotate audit log %s", s.path)
+	}
+
+	return s.open()
+}
+
+// Write implements Sink.
+func (s *RotatingSink) Write(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit record")
+	}
+	line = append(line, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.size > 0 && s.size+int64(len(line)) > s.maxBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := s.file.Write(line)
+	s.size += int64(written)
+
+	return err
+}
+
+// Close implements Sink.
+func (s *RotatingSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.file.Close()
+}
+
+// Producer publishes messages to a Kafka topic. It is satisfied by thin wrappers
+// around any Kafka client library.
+type Producer interface {
+	Produce(topic string, key []byte, value []byte) error
+	Close() error
+}
+
+// KafkaSink publishes audit records as JSON to a Kafka topic, keyed by the
+// sender's public key so that records from a single peer stay ordered.
+type KafkaSink struct {
+	producer Producer
+	topic    string
+}
+
+// NewKafkaSink creates a sink publishing audit records to a topic through a producer.
+func NewKafkaSink(producer Producer, topic string) *KafkaSink {
+	return &KafkaSink{producer: producer, topic: topic}
+}
+
+// Write implements Sink.
+func (s *KafkaSink) Write(record *Record) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit record")
+	}
+
+	return s.producer.Produce(s.topic, []byte(record.Sender), value)
+}
+
+// Close implements Sink.
+func (s *KafkaSink) Close() error {
+	return s.producer.Close()
+}
@@ -2,18 +2,32 @@ package backoff
 
 import (
 	"math"
+	"math/rand"
 	"time"
 )
 
-// Backoff keeps track of connection retry attempts and calculates the delay between each one.
+// Backoff is a policy for retrying connection attempts with exponentially increasing,
+// capped and optionally jittered delays, alongside the number of attempts made so far.
 type Backoff struct {
-	attempt, MaxAttempts float64
+	attempt float64
+
+	// MaxAttempts is the number of attempts allowed before giving up. Attempts are
+	// retried indefinitely should it be zero or negative.
+	MaxAttempts float64
 
 	// Increment factor for each time step.
 	Factor float64
 
 	// Min and max intervals allowed for backoff intervals.
 	MinInterval, MaxInterval time.Duration
+
+	// Jitter in [0, 1] randomly spreads each interval by up to the given fraction in
+	// either direction, so that peers reconnecting at once do not do so in lockstep.
+	Jitter float64
+
+	// ResetOnSuccess denotes whether the number of attempts is reset after a
+	// successful attempt, rather than carried over to the next round of retries.
+	ResetOnSuccess bool
 }
 
 const (
@@ -27,24 +41,40 @@ const (
 // DefaultBackoff creates a default configuration for Backoff.
 func DefaultBackoff() *Backoff {
 	return &Backoff{
-		attempt:     0,
-		MaxAttempts: defaultMaxAttempts,
-		Factor:      defaultFactor,
-		MinInterval: defaultMinInterval,
-		MaxInterval: defaultMaxInterval,
+		attempt:        0,
+		MaxAttempts:    defaultMaxAttempts,
+		Factor:         defaultFactor,
+		MinInterval:    defaultMinInterval,
+		MaxInterval:    defaultMaxInterval,
+		ResetOnSuccess: true,
 	}
 }
 
-// NextDuration returns the duration and increases the number of attempts
+// Clone returns a copy of the policy with its number of attempts reset.
+func (b *Backoff) Clone() *Backoff {
+	clone := *b
+	clone.attempt = 0
+	return &clone
+}
+
+// Attempts returns the number of attempts made so far.
+func (b *Backoff) Attempts() int {
+	return int(b.attempt)
+}
+
+// NextDuration returns the jittered duration and increases the number of attempts
 func (b *Backoff) NextDuration() time.Duration {
-	dur := b.ForAttempt(b.attempt)
+	dur := b.jitter(b.ForAttempt(b.attempt))
 	b.attempt++
 	return dur
 }
 
 // TimeoutExceeded returns true if the backoff total duration has been exceeded
 func (b *Backoff) TimeoutExceeded() bool {
-	return b.attempt >= math.Max(0, b.MaxAttempts)
+	if b.MaxAttempts <= 0 {
+		return false
+	}
+	return b.attempt >= b.MaxAttempts
 }
 
 // ForAttempt calculates the approprate exponential duration given an attempt count
@@ -67,7 +97,7 @@ func (b *Backoff) ForAttempt(attempt float64) time.Duration {
 	}
 
 	// Calculate the new duration
-	durf := float64(min) * math.Pow(b.Factor, attempt)
+	durf := float64(min) * math.Pow(factor, attempt)
 
 	// Check for overflow
 	if durf > maxInt64 {
@@ -85,6 +115,31 @@ func (b *Backoff) ForAttempt(attempt float64) time.Duration {
 	return dur
 }
 
+// jitter randomly spreads a duration by up to the jitter fraction, capped to the max interval.
+func (b *Backoff) jitter(dur time.Duration) time.Duration {
+	if b.Jitter <= 0 {
+		return dur
+	}
+
+	jitter := math.Min(b.Jitter, 1)
+
+	dur = time.Duration(float64(dur) * (1 - jitter + 2*jitter*rand.Float64()))
+
+	if b.MaxInterval > 0 && dur > b.MaxInterval {
+		return b.MaxInterval
+	}
+
+	return dur
+}
+
+// Success records a successful attempt, resetting the number of attempts should the
+// policy reset on success.
+func (b *Backoff) Success() {
+	if b.ResetOnSuccess {
+		b.Reset()
+	}
+}
+
 // Resets the attempt number for Backoff.
 func (b *Backoff) Reset() {
 	b.attempt = 0
@@ -57,3 +57,41 @@ func TestEdgeCases(t *testing.T) {
 	b.MaxInterval = 1 * time.Millisecond
 	assertEquals(t, b.NextDuration(), 1*time.Millisecond)
 }
+
+func TestInfiniteAttempts(t *testing.T) {
+	b := createTestBackoff()
+	b.MaxAttempts = 0
+
+	for i := 0; i < 100; i++ {
+		b.NextDuration()
+	}
+
+	assertEquals(t, b.TimeoutExceeded(), false)
+	assertEquals(t, b.NextDuration(), b.MaxInterval)
+}
+
+func TestJitter(t *testing.T) {
+	b := createTestBackoff()
+	b.Jitter = 0.5
+
+	for i := 0; i < 100; i++ {
+		b.Reset()
+
+		if dur := b.NextDuration(); dur < 50*time.Millisecond || dur > 150*time.Millisecond {
+			t.Fatalf("expected jittered duration within 50ms and 150ms, got %s", dur)
+		}
+	}
+}
+
+func TestResetOnSuccess(t *testing.T) {
+	b := createTestBackoff()
+
+	b.NextDuration()
+	b.Success()
+	assertEquals(t, b.Attempts(), 0)
+
+	b.ResetOnSuccess = false
+	b.NextDuration()
+	b.Success()
+	assertEquals(t, b.Attempts(), 1)
+}
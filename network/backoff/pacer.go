@@ -0,0 +1,119 @@
+package backoff
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+)
+
+// Pacer paces reconnection attempts across all peers, such that a large set of peers
+// disconnecting at once (e.g. should a relay restart) are not all redialed at once.
+// A pacer may be shared by the plugins of several networks.
+type Pacer struct {
+	// MaxConcurrent is the most reconnection attempts allowed in flight at once.
+	// Unlimited should it be zero or negative.
+	MaxConcurrent int
+
+	// Interval is the least time between the starts of consecutive reconnection
+	// attempts. Attempts are not spaced out should it be zero.
+	Interval time.Duration
+
+	// Jitter in [0, 1] randomly spreads each interval by up to the given fraction in
+	// either direction.
+	Jitter float64
+
+	mutex sync.Mutex
+	slots chan struct{}
+	next  time.Time
+
+	inFlight int
+}
+
+// DefaultPacer creates a default configuration for Pacer.
+func DefaultPacer() *Pacer {
+	return &Pacer{
+		MaxConcurrent: 8,
+		Interval:      50 * time.Millisecond,
+		Jitter:        0.5,
+	}
+}
+
+// Acquire blocks until a reconnection attempt may be started, and returns a function
+// to be called once the attempt is over. A nil pacer does not pace attempts.
+func (p *Pacer) Acquire(clk clock.Clock) (release func()) {
+	if p == nil {
+		return func() {}
+	}
+
+	clk = clock.Or(clk)
+
+	p.mutex.Lock()
+	if p.slots == nil && p.MaxConcurrent > 0 {
+		p.slots = make(chan struct{}, p.MaxConcurrent)
+	}
+	slots := p.slots
+	p.mutex.Unlock()
+
+	if slots != nil {
+		slots <- struct{}{}
+	}
+
+	p.mutex.Lock()
+
+	now := clk.Now()
+
+	start := p.next
+	if start.Before(now) {
+		start = now
+	}
+	p.next = start.Add(p.jitter(p.Interval))
+
+	p.inFlight++
+
+	p.mutex.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		clk.Sleep(wait)
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			p.inFlight--
+			p.mutex.Unlock()
+
+			if slots != nil {
+				<-slots
+			}
+		})
+	}
+}
+
+// InFlight returns the number of reconnection attempts started or waiting to be
+// started which have yet to be released.
+func (p *Pacer) InFlight() int {
+	if p == nil {
+		return 0
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.inFlight
+}
+
+// jitter randomly spreads an interval by up to the jitter fraction.
+func (p *Pacer) jitter(interval time.Duration) time.Duration {
+	if interval <= 0 || p.Jitter <= 0 {
+		return interval
+	}
+
+	jitter := math.Min(p.Jitter, 1)
+
+	return time.Duration(float64(interval) * (1 - jitter + 2*jitter*rand.Float64()))
+}
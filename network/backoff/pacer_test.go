@@ -0,0 +1,92 @@
+package backoff
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPacerLimitsConcurrentAttempts(t *testing.T) {
+	pacer := &Pacer{MaxConcurrent: 2}
+
+	var (
+		wg      sync.WaitGroup
+		mutex   sync.Mutex
+		active  int
+		maximum int
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			release := pacer.Acquire(nil)
+			defer release()
+
+			mutex.Lock()
+			active++
+			if active > maximum {
+				maximum = active
+			}
+			mutex.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mutex.Lock()
+			active--
+			mutex.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	assertEquals(t, 2, maximum)
+	assertEquals(t, 0, pacer.InFlight())
+}
+
+func TestPacerSpacesAttempts(t *testing.T) {
+	interval := 20 * time.Millisecond
+
+	pacer := &Pacer{Interval: interval}
+
+	var (
+		wg     sync.WaitGroup
+		mutex  sync.Mutex
+		starts []time.Time
+	)
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			release := pacer.Acquire(nil)
+			defer release()
+
+			mutex.Lock()
+			starts = append(starts, time.Now())
+			mutex.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	if elapsed := starts[len(starts)-1].Sub(starts[0]); elapsed < 4*interval-5*time.Millisecond {
+		t.Fatalf("expected attempts to be spaced %s apart, but 5 started within %s", interval, elapsed)
+	}
+}
+
+func TestNilPacer(t *testing.T) {
+	var pacer *Pacer
+
+	release := pacer.Acquire(nil)
+	release()
+
+	assertEquals(t, 0, pacer.InFlight())
+}
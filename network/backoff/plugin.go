@@ -6,21 +6,57 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/perlin-network/noise/network"
-	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
 )
 
+// PeerStats are the reconnection metrics of a peer.
+type PeerStats struct {
+	// Active is true should the peer currently be being reconnected to.
+	Active bool
+
+	// Attempts is the total number of reconnection attempts made.
+	Attempts int
+
+	// Reconnects is the number of times the peer was successfully reconnected to.
+	Reconnects int
+
+	// GiveUps is the number of times reconnecting was given up on after exhausting
+	// all attempts allowed by the policy.
+	GiveUps int
+
+	LastAttempt time.Time
+}
+
+type peerState struct {
+	backoff *Backoff
+	stats   PeerStats
+}
+
 type Plugin struct {
 	*network.Plugin
 
+	// Policy is the backoff policy each peer is reconnected to with. Nil if the
+	// default policy.
+	Policy *Backoff
+
+	// InitialDelay is how long to wait after a peer disconnects before attempting to
+	// reconnect to it. Zero if the default delay.
+	InitialDelay time.Duration
+
+	// Pacer paces reconnection attempts across all peers. Nil if attempts are not paced.
+	Pacer *Pacer
+
 	net *network.Network
-	// map[string]*Backoff
-	backoffs sync.Map
+
+	// peers holds the reconnection state of peers by their addresses rather than as
+	// values attached to them, as it outlives their connections.
+	mutex sync.Mutex
+	peers map[string]*peerState
 }
 
 var (
-	PluginID        = (*Plugin)(nil)
-	initialDelay    = 5 * time.Second
-	limitIterations = 100
+	PluginID     = (*Plugin)(nil)
+	initialDelay = 5 * time.Second
 )
 
 // Startup implements the plugin callback
@@ -30,60 +66,139 @@ func (p *Plugin) Startup(net *network.Network) {
 
 // PeerDisconnect implements the plugin callback
 func (p *Plugin) PeerDisconnect(client *network.PeerClient) {
-	addr := client.Address
+	addr := client.Address()
 
 	go p.startBackoff(addr)
 }
 
+// Stats returns the reconnection metrics of a peer by its address.
+func (p *Plugin) Stats(addr string) (PeerStats, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	state, exists := p.peers[addr]
+	if !exists {
+		return PeerStats{}, false
+	}
+
+	return state.stats, true
+}
+
+// activate marks a peer as being reconnected to, returning its state or false should
+// it already be being reconnected to.
+func (p *Plugin) activate(addr string) (*peerState, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.peers == nil {
+		p.peers = make(map[string]*peerState)
+	}
+
+	state, exists := p.peers[addr]
+	if !exists {
+		policy := p.Policy
+		if policy == nil {
+			policy = DefaultBackoff()
+		}
+
+		state = &peerState{backoff: policy.Clone()}
+		p.peers[addr] = state
+	}
+
+	if state.stats.Active {
+		return nil, false
+	}
+
+	state.stats.Active = true
+
+	// Start a fresh round of retries should the previous round have been given up on.
+	if state.backoff.TimeoutExceeded() {
+		state.backoff.Reset()
+	}
+
+	return state, true
+}
+
 // startBackoff uses an exponentially increasing timer to try to reconnect to a given address
 func (p *Plugin) startBackoff(addr string) {
-	time.Sleep(initialDelay)
+	clk := clock.Or(p.net.Clock)
+
+	delay := p.InitialDelay
+	if delay <= 0 {
+		delay = initialDelay
+	}
 
-	if _, exists := p.backoffs.Load(addr); exists {
+	clk.Sleep(delay)
+
+	state, activated := p.activate(addr)
+	if !activated {
 		// don't activate if backoff is already active
 		glog.Infof("backoff skipped for addr %s, already active\n", addr)
 		return
 	}
-	// reset the backoff counter
-	p.backoffs.Store(addr, DefaultBackoff())
-	startTime := time.Now()
-	for i := 0; i < limitIterations; i++ {
-		s, active := p.backoffs.Load(addr)
-		if !active {
-			break
-		}
-		b := s.(*Backoff)
-		if b.TimeoutExceeded() {
+
+	startTime := clk.Now()
+
+	for {
+		p.mutex.Lock()
+
+		if state.backoff.TimeoutExceeded() {
+			state.stats.Active = false
+			state.stats.GiveUps++
+			p.mutex.Unlock()
+
 			// check if the backoff expired
-			glog.Infof("backoff ended for addr %s, timed out after %s\n", addr, time.Now().Sub(startTime))
-			break
+			glog.Infof("backoff ended for addr %s, timed out after %s\n", addr, clk.Since(startTime))
+			return
 		}
+
 		// sleep for a bit before connecting
-		d := b.NextDuration()
-		glog.Infof("backoff reconnecting to %s in %s iteration %d", addr, d, i+1)
-		time.Sleep(d)
-		if p.checkConnected(addr) {
-			// check that the connection is still empty before dialing
+		d := state.backoff.NextDuration()
+		attempt := state.backoff.Attempts()
+
+		p.mutex.Unlock()
+
+		glog.Infof("backoff reconnecting to %s in %s iteration %d", addr, d, attempt)
+		clk.Sleep(d)
+
+		release := p.Pacer.Acquire(clk)
+
+		p.mutex.Lock()
+		state.stats.Attempts++
+		state.stats.LastAttempt = clk.Now()
+		p.mutex.Unlock()
+
+		connected := p.checkConnected(addr) || p.reconnect(addr)
+		release()
+
+		if connected {
 			break
 		}
-		// dial the client and see if it is successful
-		c, err := p.net.Client(addr)
-		if err != nil {
-			continue
-		}
-		if !p.checkConnected(addr) {
-			// check if successfully connected
-			continue
-		}
-		if err := c.Tell(&protobuf.Ping{}); err != nil {
-			// ping failed, not really connected
-			continue
-		}
-		// success
-		break
 	}
-	// clean up this backoff
-	p.backoffs.Delete(addr)
+
+	p.mutex.Lock()
+	state.stats.Active = false
+	state.stats.Reconnects++
+	state.backoff.Success()
+	p.mutex.Unlock()
+}
+
+// reconnect dials an address, and pings it to check that the connection is alive.
+func (p *Plugin) reconnect(addr string) bool {
+	// dial the client and see if it is successful
+	c, err := p.net.Client(addr)
+	if err != nil {
+		return false
+	}
+	if !p.checkConnected(addr) {
+		// check if successfully connected
+		return false
+	}
+	if _, err := c.Tell(p.net.NewPing()); err != nil {
+		// ping failed, not really connected
+		return false
+	}
+	return true
 }
 
 // checkConnected is a helper function to check if the address is connected to the node
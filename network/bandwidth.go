@@ -0,0 +1,116 @@
+package network
+
+import (
+	"net"
+
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/perlin-network/noise/types/ratelimit"
+)
+
+// BandwidthLimits are caps in bytes per second on traffic sent (egress) and received
+// (ingress) by a node, both globally and per connection. A limit of zero is unlimited.
+type BandwidthLimits struct {
+	Ingress float64
+	Egress  float64
+
+	IngressPerPeer float64
+	EgressPerPeer  float64
+}
+
+// Throttle shapes the traffic of connections w.r.t. a set of bandwidth limits.
+// A nil *Throttle leaves connections untouched.
+type Throttle struct {
+	limits BandwidthLimits
+
+	ingress *ratelimit.Bucket
+	egress  *ratelimit.Bucket
+
+	clock clock.Clock
+}
+
+// NewThrottle creates a new throttle enforcing a set of bandwidth limits, as measured
+// by a clock. The real clock is used should it be nil.
+func NewThrottle(limits BandwidthLimits, c clock.Clock) *Throttle {
+	return &Throttle{
+		limits:  limits,
+		ingress: newBandwidthBucket(limits.Ingress, c),
+		egress:  newBandwidthBucket(limits.Egress, c),
+		clock:   c,
+	}
+}
+
+// newBandwidthBucket creates a bucket allowing bursts of up to a seconds worth of
+// traffic, or nil should the rate be unlimited.
+func newBandwidthBucket(rate float64, c clock.Clock) *ratelimit.Bucket {
+	if rate <= 0 {
+		return nil
+	}
+	return ratelimit.NewBucket(rate, rate, c)
+}
+
+// Limits returns the bandwidth limits enforced by the throttle.
+func (t *Throttle) Limits() BandwidthLimits {
+	if t == nil {
+		return BandwidthLimits{}
+	}
+	return t.limits
+}
+
+// reserve returns a bucket for traffic other than a share of the egress limit reserved
+// for priority messages to pass through, or nil should either be unlimited.
+func (t *Throttle) reserve(share float64) *ratelimit.Bucket {
+	if t == nil || share <= 0 {
+		return nil
+	}
+
+	return newBandwidthBucket(t.limits.Egress*(1-share), t.clock)
+}
+
+// Wrap returns a connection whose reads and writes are throttled.
+func (t *Throttle) Wrap(conn net.Conn) net.Conn {
+	if t == nil {
+		return conn
+	}
+
+	return &throttledConn{
+		Conn:        conn,
+		throttle:    t,
+		peerIngress: newBandwidthBucket(t.limits.IngressPerPeer, t.clock),
+		peerEgress:  newBandwidthBucket(t.limits.EgressPerPeer, t.clock),
+	}
+}
+
+type throttledConn struct {
+	net.Conn
+
+	throttle *Throttle
+
+	peerIngress *ratelimit.Bucket
+	peerEgress  *ratelimit.Bucket
+}
+
+// wait blocks until n bytes may pass through all given buckets.
+func wait(n int, buckets ...*ratelimit.Bucket) {
+	for _, bucket := range buckets {
+		if bucket != nil {
+			bucket.Wait(float64(n))
+		}
+	}
+}
+
+// Read reads from the underlying connection, and then blocks until the bytes read
+// are paid off so that the remote peer is slowed down by back-pressure.
+func (c *throttledConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		wait(n, c.throttle.ingress, c.peerIngress)
+	}
+	return n, err
+}
+
+// Write blocks until the bytes to be written are paid off, and then writes them to
+// the underlying connection.
+func (c *throttledConn) Write(b []byte) (int, error) {
+	wait(len(b), c.throttle.egress, c.peerEgress)
+	return c.Conn.Write(b)
+}
@@ -0,0 +1,67 @@
+package network
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+)
+
+func TestThrottleEgress(t *testing.T) {
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+
+	throttle := NewThrottle(BandwidthLimits{EgressPerPeer: 1024}, nil)
+	conn := throttle.Wrap(local)
+
+	go io.Copy(ioutil.Discard, remote)
+
+	start := time.Now()
+
+	// The first 1024 bytes are burst; the next 512 bytes should take ~500ms.
+	for i := 0; i < 3; i++ {
+		if _, err := conn.Write(make([]byte, 512)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("expected writes to be throttled, took %s", elapsed)
+	}
+}
+
+func TestNilThrottle(t *testing.T) {
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+
+	var throttle *Throttle
+
+	if throttle.Wrap(local) != local {
+		t.Fatal("expected nil throttle to leave connections untouched")
+	}
+}
+
+func TestThrottleReserve(t *testing.T) {
+	mock := clock.NewMock(time.Now())
+
+	throttle := NewThrottle(BandwidthLimits{Egress: 1000}, mock)
+
+	bulk := throttle.reserve(0.25)
+	if !bulk.Allow(750) || bulk.Allow(1) {
+		t.Fatal("expected bulk traffic to be limited to the share of egress not reserved")
+	}
+
+	if throttle.reserve(0) != nil || (*Throttle)(nil).reserve(0.25) != nil {
+		t.Fatal("expected no bucket should no bandwidth be reserved or limited")
+	}
+
+	lane := &PriorityLane{Share: 1}
+	if share := lane.share(); share != MaxPriorityShare {
+		t.Fatalf("expected the share to be capped to %f, got %f", MaxPriorityShare, share)
+	}
+}
@@ -0,0 +1,273 @@
+package network
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/lru"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BatchEnvelopeVersion is the lowest envelope version supporting batch-signed messages.
+	BatchEnvelopeVersion uint32 = 3
+
+	// MaxBatchSize is the largest number of messages which may be signed as a batch.
+	MaxBatchSize = 1024
+
+	// verifiedBatchesCapacity is the number of batch signatures remembered to have been
+	// verified, sparing each message of a batch from having it verified again.
+	verifiedBatchesCapacity = 4096
+)
+
+var (
+	// batchLeafPrefix and batchNodePrefix domain-separate the leaves and inner nodes
+	// of a batch's merkle tree.
+	batchLeafPrefix = []byte{0}
+	batchNodePrefix = []byte{1}
+
+	// batchRootPrefix separated the signed merkle roots of batches from the payloads of
+	// messages signed alone, before signatures were domain-separated.
+	batchRootPrefix = []byte("noise/batch:")
+)
+
+// verifiedBatches remembers the batch signatures which were verified.
+type verifiedBatches struct {
+	once  sync.Once
+	cache *lru.Cache
+}
+
+func (v *verifiedBatches) get() *lru.Cache {
+	v.once.Do(func() {
+		v.cache = lru.NewCache(verifiedBatchesCapacity)
+	})
+	return v.cache
+}
+
+// PrepareBatch marshals messages into *protobuf.Messages, and signs them at once by
+// signing the merkle root of their payloads. Each message carries a proof of its
+// inclusion within the batch, such that it may be verified on its own, sparing the
+// signer from signing each message such as on high-rate gossip topics.
+func (n *Network) PrepareBatch(messages ...proto.Message) ([]*protobuf.Message, error) {
+	if len(messages) > MaxBatchSize {
+		return nil, errors.Errorf("batch of %d messages exceeds the maximum of %d", len(messages), MaxBatchSize)
+	}
+
+	// A batch of one is no cheaper to sign than the message alone.
+	if len(messages) == 1 {
+		msg, err := n.PrepareMessage(messages[0])
+		if err != nil {
+			return nil, err
+		}
+		return []*protobuf.Message{msg}, nil
+	}
+
+	raws := make([]*any.Any, len(messages))
+	ids := make([][]byte, len(messages))
+	lamports := make([]uint64, len(messages))
+	leaves := make([][]byte, len(messages))
+
+	for i, message := range messages {
+		if message == nil {
+			return nil, errors.New("message is null")
+		}
+
+		raw, err := ptypes.MarshalAny(message)
+		if err != nil {
+			return nil, err
+		}
+
+		if ids[i], err = n.newEnvelopeID(); err != nil {
+			return nil, err
+		}
+
+		if n.LogicalClock != nil {
+			lamports[i] = n.LogicalClock.Increment()
+		}
+
+		raws[i] = raw
+		leaves[i] = n.batchLeaf(ids[i], raw, lamports[i], EnvelopeVersion)
+	}
+
+	tree := n.batchTree(leaves)
+	root := tree[len(tree)-1][0]
+
+	id := protobuf.ID(n.ID)
+
+	signature, err := n.Sign(n.batchPayload(&id, root, EnvelopeVersion))
+	if err != nil {
+		return nil, err
+	}
+
+	prepared := make([]*protobuf.Message, len(messages))
+
+	for i, raw := range raws {
+		msg := &protobuf.Message{}
+		msg.Message = raw
+		msg.Sender = &id
+		msg.Signature = signature
+		msg.Id = ids[i]
+		msg.BatchIndex = uint32(i)
+		msg.BatchProof = batchProof(tree, i)
+		msg.Version = EnvelopeVersion
+		msg.MaxVersion = EnvelopeVersion
+		msg.Capabilities = uint64(n.Capabilities)
+		msg.LamportTimestamp = lamports[i]
+
+		prepared[i] = msg
+	}
+
+	return prepared, nil
+}
+
+// batchLeaf hashes the payload of a message into a leaf of a batch's merkle tree,
+// prefixed with its ID and type URL, and Lamport timestamp, should the envelopes'
+// version cover them.
+func (n *Network) batchLeaf(id []byte, raw *any.Any, lamport uint64, version uint32) []byte {
+	payload := raw.Value
+	if version >= DomainEnvelopeVersion {
+		payload = envelopeBody(id, raw, lamport, version)
+	}
+
+	return n.HashPolicy.HashBytes(append(append([]byte{}, batchLeafPrefix...), payload...))
+}
+
+// batchNode hashes two nodes of a batch's merkle tree into their parent.
+func (n *Network) batchNode(left []byte, right []byte) []byte {
+	node := make([]byte, 0, len(batchNodePrefix)+len(left)+len(right))
+	node = append(node, batchNodePrefix...)
+	node = append(node, left...)
+	node = append(node, right...)
+
+	return n.HashPolicy.HashBytes(node)
+}
+
+// batchTree builds the merkle tree of a batch's leaves, level by level up to the root.
+// The last node of a level with an odd number of nodes is paired with itself.
+func (n *Network) batchTree(leaves [][]byte) [][][]byte {
+	tree := [][][]byte{leaves}
+
+	for level := leaves; len(level) > 1; {
+		var parents [][]byte
+
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+
+			parents = append(parents, n.batchNode(level[i], right))
+		}
+
+		tree = append(tree, parents)
+		level = parents
+	}
+
+	return tree
+}
+
+// batchProof returns the siblings of a leaf by its index on the path up to the root of
+// a batch's merkle tree.
+func batchProof(tree [][][]byte, index int) [][]byte {
+	var proof [][]byte
+
+	for _, level := range tree[:len(tree)-1] {
+		sibling := index ^ 1
+		if sibling >= len(level) {
+			sibling = index
+		}
+
+		proof = append(proof, level[sibling])
+		index /= 2
+	}
+
+	return proof
+}
+
+// batchPayload returns what is signed of the merkle root of a batch of messages sent by
+// a sender, tagged with the batch domain should the envelopes' version separate domains.
+func (n *Network) batchPayload(sender *protobuf.ID, root []byte, version uint32) []byte {
+	if version < DomainEnvelopeVersion {
+		return n.signedMessage(sender, append(batchRootPrefix, root...))
+	}
+
+	return crypto.DomainSeparate(DomainBatch, n.signedMessage(sender, root))
+}
+
+// batchRoot returns the merkle root of a batch a message whose envelope was encoded in
+// a version claims to be included within by its index and proof.
+func (n *Network) batchRoot(id []byte, raw *any.Any, lamport uint64, version uint32, index uint32, proof [][]byte) []byte {
+	node := n.batchLeaf(id, raw, lamport, version)
+
+	for _, sibling := range proof {
+		if index%2 == 0 {
+			node = n.batchNode(node, sibling)
+		} else {
+			node = n.batchNode(sibling, node)
+		}
+		index /= 2
+	}
+
+	return node
+}
+
+// verifySignature verifies the signature of a message whose envelope was encoded in a
+// version, which is over the message's payload should it be signed alone, or over the
+// merkle root of its batch otherwise. Batch signatures are only verified once.
+// Envelopes of versions which separate domains must carry signatures tagged with their
+// domain, lest untagged signatures be replayed within other domains.
+func (n *Network) verifySignature(msg *protobuf.Message, version uint32) bool {
+	if len(msg.BatchProof) == 0 {
+		return n.verifyEnvelope(msg.Sender, n.envelopePayload(msg.Sender, msg.Id, msg.Message, msg.LamportTimestamp, version), msg.Signature)
+	}
+
+	if len(msg.BatchProof) > 32 {
+		return false
+	}
+
+	root := n.batchRoot(msg.Id, msg.Message, msg.LamportTimestamp, version, msg.BatchIndex, msg.BatchProof)
+
+	payload := n.batchPayload(msg.Sender, root, version)
+
+	key := string(msg.Sender.PublicKey) + string(payload) + string(msg.Signature)
+	if n.verifiedBatches.get().Contains(key) {
+		return true
+	}
+
+	if !n.verifyEnvelope(msg.Sender, payload, msg.Signature) {
+		return false
+	}
+
+	n.verifiedBatches.get().Get(key, func() (interface{}, error) {
+		return struct{}{}, nil
+	})
+
+	return true
+}
+
+// resign returns a copy of a message signed alone in an older envelope version, such as
+// to be sent to a peer which supports neither batch-signed messages nor domain-separated
+// signatures.
+func (n *Network) resign(msg *protobuf.Message, version uint32) (*protobuf.Message, error) {
+	signature, err := n.Sign(n.envelopePayload(msg.Sender, msg.Id, msg.Message, msg.LamportTimestamp, version))
+	if err != nil {
+		return nil, err
+	}
+
+	resigned := proto.Clone(msg).(*protobuf.Message)
+	resigned.Signature = signature
+	resigned.Version = version
+	resigned.BatchIndex = 0
+	resigned.BatchProof = nil
+
+	// Message IDs are only signed by envelopes which separate domains.
+	if version < DomainEnvelopeVersion {
+		resigned.Id = nil
+	}
+
+	return resigned, nil
+}
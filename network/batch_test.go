@@ -0,0 +1,118 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/crypto/hashing/blake2b"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/logical"
+)
+
+func createSigningTestNetwork() *Network {
+	keys := ed25519.RandomKeyPair()
+
+	return &Network{
+		ID:              peer.CreateID("tcp://127.0.0.1:3000", keys.PublicKey),
+		Keys:            keys,
+		SignaturePolicy: ed25519.New(),
+		HashPolicy:      blake2b.New(),
+	}
+}
+
+func TestPrepareBatch(t *testing.T) {
+	n := createSigningTestNetwork()
+
+	var messages []proto.Message
+	for i := 0; i < 5; i++ {
+		messages = append(messages, &protobuf.Datagram{Data: []byte{byte(i)}})
+	}
+
+	signed, err := n.PrepareBatch(messages...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(signed) != len(messages) {
+		t.Fatalf("expected %d messages to be signed, got %d", len(messages), len(signed))
+	}
+
+	for i, msg := range signed {
+		if len(msg.BatchProof) == 0 || !proto.Equal(msg.Sender, signed[0].Sender) {
+			t.Fatalf("expected message %d to carry an inclusion proof", i)
+		}
+
+		if !n.verifySignature(msg, EnvelopeVersion) {
+			t.Fatalf("expected message %d to be verified against its batch", i)
+		}
+	}
+
+	tampered := proto.Clone(signed[2]).(*protobuf.Message)
+	tampered.Message.Value = []byte("forged")
+	if n.verifySignature(tampered, EnvelopeVersion) {
+		t.Fatal("expected a tampered message not to be verified")
+	}
+
+	misplaced := proto.Clone(signed[2]).(*protobuf.Message)
+	misplaced.BatchIndex = 3
+	if n.verifySignature(misplaced, EnvelopeVersion) {
+		t.Fatal("expected a message claiming the wrong index not to be verified")
+	}
+
+	resigned, err := n.resign(signed[4], BatchEnvelopeVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resigned.BatchProof) > 0 || len(signed[4].BatchProof) == 0 || !n.verifySignature(resigned, resigned.Version) {
+		t.Fatal("expected a resigned copy to be signed alone")
+	}
+
+	if single, err := n.PrepareBatch(messages[0]); err != nil || len(single) != 1 || len(single[0].BatchProof) > 0 {
+		t.Fatalf("expected a batch of one to be signed alone, got %v [err=%v]", single, err)
+	}
+}
+
+func TestSignedLamportTimestamp(t *testing.T) {
+	n := createSigningTestNetwork()
+	n.LogicalClock = logical.NewLamport()
+
+	single, err := n.PrepareMessage(&protobuf.Datagram{Data: []byte("single")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := n.PrepareBatch(&protobuf.Datagram{Data: []byte("first")}, &protobuf.Datagram{Data: []byte("second")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, msg := range append([]*protobuf.Message{single}, batch...) {
+		if msg.LamportTimestamp == 0 || !n.verifySignature(msg, EnvelopeVersion) {
+			t.Fatalf("expected message %d to be stamped and verified", i)
+		}
+
+		// Lamport timestamps are signed, such that they may not be tampered with to
+		// reorder messages.
+		tampered := proto.Clone(msg).(*protobuf.Message)
+		tampered.LamportTimestamp += 10
+
+		if n.verifySignature(tampered, EnvelopeVersion) {
+			t.Fatalf("expected message %d with a tampered Lamport timestamp not to be verified", i)
+		}
+
+		// Envelopes predating signed Lamport timestamps are still verified regardless.
+		resigned, err := n.resign(msg, DomainEnvelopeVersion)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resigned.LamportTimestamp += 10
+
+		if !n.verifySignature(resigned, DomainEnvelopeVersion) {
+			t.Fatalf("expected message %d signed at version %d to be verified", i, DomainEnvelopeVersion)
+		}
+	}
+}
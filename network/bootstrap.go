@@ -0,0 +1,345 @@
+package network
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultBootstrapInitialBackoff = 1 * time.Second
+	defaultBootstrapMaxBackoff     = 30 * time.Second
+)
+
+// BootstrapOptions configure how a network bootstraps off of seed addresses.
+type BootstrapOptions struct {
+	// MinSeeds is the number of seeds which must be connected to for bootstrapping
+	// to succeed. Defaults to 1, and is capped to the number of seeds.
+	MinSeeds int
+
+	// MaxAttempts is the number of rounds through all seeds before giving up. Zero
+	// if rounds are retried until the context is done.
+	MaxAttempts int
+
+	// InitialBackoff is the delay between the first and second rounds, doubling
+	// each round up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Regions are the regions seeds are preferred from by BootstrapSeeds, nearest
+	// first, such as our own region followed by neighbouring ones. Seeds of unlisted
+	// regions are fallen back to last.
+	Regions []string
+}
+
+// Seed is a seed address labelled with a weight and region, for bootstrapping to
+// prefer nearby and high-weight seeds.
+type Seed struct {
+	Address string `json:"address"`
+
+	// Weight is how likely the seed is to be tried before others of the same priority.
+	// Seeds without a positive weight are weighted 1.
+	Weight float64 `json:"weight,omitempty"`
+
+	// Region is the region the seed is deployed in, such as "us-east". Empty if
+	// unknown.
+	Region string `json:"region,omitempty"`
+}
+
+// BootstrapState is the progress of bootstrapping.
+type BootstrapState int
+
+const (
+	BootstrapPending BootstrapState = iota
+	BootstrapInProgress
+	BootstrapSucceeded
+	BootstrapFailed
+)
+
+func (s BootstrapState) String() string {
+	switch s {
+	case BootstrapPending:
+		return "pending"
+	case BootstrapInProgress:
+		return "in progress"
+	case BootstrapSucceeded:
+		return "succeeded"
+	case BootstrapFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// BootstrapStatus reports the progress of bootstrapping.
+type BootstrapStatus struct {
+	State BootstrapState
+
+	// Seeds which were successfully connected to.
+	Connected []string
+
+	// Attempts is the number of rounds made through all seeds.
+	Attempts int
+
+	// Err is the reason bootstrapping failed, if it did.
+	Err error
+}
+
+// bootstrapTracker tracks the progress of bootstrapping, and signals its completion.
+type bootstrapTracker struct {
+	mutex  sync.Mutex
+	status BootstrapStatus
+	done   chan struct{}
+}
+
+func (t *bootstrapTracker) doneChan() chan struct{} {
+	if t.done == nil {
+		t.done = make(chan struct{})
+	}
+	return t.done
+}
+
+func (t *bootstrapTracker) update(f func(status *BootstrapStatus)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	f(&t.status)
+
+	if t.status.State == BootstrapSucceeded || t.status.State == BootstrapFailed {
+		done := t.doneChan()
+
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+	}
+}
+
+// BootstrapStatus returns the progress of the most recent attempt at bootstrapping.
+func (n *Network) BootstrapStatus() BootstrapStatus {
+	n.bootstrap.mutex.Lock()
+	defer n.bootstrap.mutex.Unlock()
+
+	status := n.bootstrap.status
+	status.Connected = append([]string(nil), status.Connected...)
+
+	return status
+}
+
+// Bootstrapped returns a channel which is closed once bootstrapping first either
+// succeeds or fails, for applications to gate their startup on.
+func (n *Network) Bootstrapped() <-chan struct{} {
+	n.bootstrap.mutex.Lock()
+	defer n.bootstrap.mutex.Unlock()
+
+	return n.bootstrap.doneChan()
+}
+
+// Bootstrap connects to and pings every given seed address once, returning an error
+// should any seed not be connected to.
+func (n *Network) Bootstrap(addresses ...string) error {
+	return n.BootstrapWithOptions(context.Background(), BootstrapOptions{
+		MinSeeds:    len(addresses),
+		MaxAttempts: 1,
+	}, addresses...)
+}
+
+// BootstrapWithOptions connects to and pings seed addresses in rounds until at least
+// MinSeeds seeds are connected to. Each round starts from the next seed in rotation and
+// skips seeds already connected to, with rounds being backed off exponentially.
+func (n *Network) BootstrapWithOptions(ctx context.Context, options BootstrapOptions, addresses ...string) error {
+	addresses = FilterPeers(n.Address, addresses)
+
+	offset := 0
+	if len(addresses) > 0 {
+		offset = rand.Intn(len(addresses))
+	}
+
+	return n.bootstrapRounds(ctx, options, len(addresses), func() []string {
+		rotated := make([]string, 0, len(addresses))
+		for i := range addresses {
+			rotated = append(rotated, addresses[(offset+i)%len(addresses)])
+		}
+		offset++
+
+		return rotated
+	})
+}
+
+// BootstrapSeeds bootstraps as BootstrapWithOptions off of seeds labelled with weights
+// and regions. Each round tries seeds of the regions listed in options in order,
+// followed by seeds of all other regions, with seeds of the same priority being tried
+// in a random order biased by their weights.
+func (n *Network) BootstrapSeeds(ctx context.Context, options BootstrapOptions, seeds ...Seed) error {
+	addresses := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		addresses = append(addresses, seed.Address)
+	}
+
+	return n.bootstrapRounds(ctx, options, len(FilterPeers(n.Address, addresses)), func() []string {
+		return FilterPeers(n.Address, prioritizeSeeds(seeds, options.Regions))
+	})
+}
+
+// bootstrapRounds connects to and pings seeds in the order returned by order each
+// round, until at least MinSeeds out of total seeds are connected to.
+func (n *Network) bootstrapRounds(ctx context.Context, options BootstrapOptions, total int, order func() []string) error {
+	if err := n.BlockUntilListening(); err != nil {
+		return err
+	}
+
+	minSeeds := options.MinSeeds
+	if minSeeds <= 0 {
+		minSeeds = 1
+	}
+	if minSeeds > total {
+		minSeeds = total
+	}
+
+	backoff := options.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultBootstrapInitialBackoff
+	}
+
+	maxBackoff := options.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultBootstrapMaxBackoff
+	}
+
+	n.bootstrap.update(func(status *BootstrapStatus) {
+		*status = BootstrapStatus{State: BootstrapInProgress}
+	})
+
+	connected := make(map[string]struct{})
+
+	var err error
+
+	for attempt := 1; len(connected) < minSeeds; attempt++ {
+		for _, address := range order() {
+			if len(connected) >= minSeeds {
+				break
+			}
+
+			if _, seen := connected[address]; seen {
+				continue
+			}
+
+			if err := n.bootstrapSeed(address); err != nil {
+				glog.Error(err)
+				continue
+			}
+
+			connected[address] = struct{}{}
+
+			n.bootstrap.update(func(status *BootstrapStatus) {
+				status.Connected = append(status.Connected, address)
+			})
+		}
+
+		n.bootstrap.update(func(status *BootstrapStatus) {
+			status.Attempts = attempt
+		})
+
+		if len(connected) >= minSeeds {
+			break
+		}
+
+		if options.MaxAttempts > 0 && attempt >= options.MaxAttempts {
+			err = errors.Errorf("only connected to %d / %d seeds after %d attempts", len(connected), minSeeds, attempt)
+			break
+		}
+
+		select {
+		case <-n.clock().After(backoff):
+		case <-ctx.Done():
+			err = errors.Wrapf(ctx.Err(), "only connected to %d / %d seeds", len(connected), minSeeds)
+		}
+
+		if err != nil {
+			break
+		}
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	n.bootstrap.update(func(status *BootstrapStatus) {
+		if err != nil {
+			status.State = BootstrapFailed
+			status.Err = err
+		} else {
+			status.State = BootstrapSucceeded
+		}
+	})
+
+	return err
+}
+
+// prioritizeSeeds orders the addresses of seeds by the position of their region within
+// regions, with seeds of unlisted regions being last. Seeds of the same priority are
+// ordered at random, each seed's likelihood of coming first being proportional to its
+// weight.
+func prioritizeSeeds(seeds []Seed, regions []string) []string {
+	priorities := make(map[string]int, len(regions))
+	for i, region := range regions {
+		if _, exists := priorities[region]; !exists {
+			priorities[region] = i
+		}
+	}
+
+	type candidate struct {
+		address  string
+		priority int
+		key      float64
+	}
+
+	candidates := make([]candidate, 0, len(seeds))
+
+	for _, seed := range seeds {
+		priority, listed := priorities[seed.Region]
+		if !listed {
+			priority = len(regions)
+		}
+
+		weight := seed.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+
+		// Weighted random sampling without replacement (Efraimidis-Spirakis).
+		candidates = append(candidates, candidate{address: seed.Address, priority: priority, key: math.Pow(rand.Float64(), 1/weight)})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		return candidates[i].key > candidates[j].key
+	})
+
+	addresses := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		addresses[i] = candidate.address
+	}
+
+	return addresses
+}
+
+// bootstrapSeed connects to and pings a seed address.
+func (n *Network) bootstrapSeed(address string) error {
+	client, err := n.Client(address)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Tell(n.NewPing())
+	return err
+}
@@ -0,0 +1,87 @@
+package network_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func TestBootstrapWithOptions(t *testing.T) {
+	alice := buildNode(t, 13050, new(discovery.Plugin))
+	bob := buildNode(t, 13051, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	unreachable := network.FormatAddress("tcp", "127.0.0.1", 13059)
+
+	options := network.BootstrapOptions{MinSeeds: 2, MaxAttempts: 2, InitialBackoff: 10 * time.Millisecond}
+	if err := alice.BootstrapWithOptions(context.Background(), options, bob.Address, unreachable); err == nil {
+		t.Fatal("expected bootstrapping to fail with an unreachable seed")
+	}
+
+	select {
+	case <-alice.Bootstrapped():
+	default:
+		t.Fatal("expected bootstrapping to have completed")
+	}
+
+	status := alice.BootstrapStatus()
+	if status.State != network.BootstrapFailed || status.Attempts != 2 {
+		t.Fatalf("expected bootstrapping to fail after 2 attempts, got %s after %d", status.State, status.Attempts)
+	}
+
+	if len(status.Connected) != 1 || status.Connected[0] != bob.Address {
+		t.Fatalf("expected to be connected to only %s, got %v", bob.Address, status.Connected)
+	}
+
+	options.MinSeeds = 1
+	if err := alice.BootstrapWithOptions(context.Background(), options, unreachable, bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := alice.BootstrapStatus(); status.State != network.BootstrapSucceeded {
+		t.Fatalf("expected bootstrapping to succeed, got %s", status.State)
+	}
+}
+
+func TestBootstrapSeeds(t *testing.T) {
+	alice := buildNode(t, 13221, new(discovery.Plugin))
+	bob := buildNode(t, 13222, new(discovery.Plugin))
+	carol := buildNode(t, 13223, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	unreachable := network.FormatAddress("tcp", "127.0.0.1", 13224)
+
+	seeds := []network.Seed{
+		{Address: bob.Address, Region: "eu", Weight: 100},
+		{Address: carol.Address, Region: "us"},
+		{Address: unreachable, Region: "ap"},
+	}
+
+	// Seeds of the nearest region are preferred regardless of weight.
+	options := network.BootstrapOptions{MaxAttempts: 1, Regions: []string{"us", "eu"}}
+	if err := alice.BootstrapSeeds(context.Background(), options, seeds...); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := alice.BootstrapStatus(); len(status.Connected) != 1 || status.Connected[0] != carol.Address {
+		t.Fatalf("expected to be connected to only %s, got %v", carol.Address, status.Connected)
+	}
+
+	// Seeds of other regions are fallen back to should the nearest region's be unreachable.
+	options.Regions = []string{"ap"}
+	if err := alice.BootstrapSeeds(context.Background(), options, seeds...); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := alice.BootstrapStatus(); len(status.Connected) != 1 || status.Connected[0] == unreachable {
+		t.Fatalf("expected to fall back to a reachable seed, got %v", status.Connected)
+	}
+}
@@ -1,29 +1,98 @@
 package builders
 
 import (
+	"io/ioutil"
+	"os"
 	"reflect"
+	"time"
 
 	"sync"
 
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
 	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/crypto/hashing/blake2b"
 	"github.com/perlin-network/noise/crypto/signing/ed25519"
 	"github.com/perlin-network/noise/network"
 	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/peerstore"
 	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/perlin-network/noise/types/logical"
 	"github.com/pkg/errors"
 )
 
 // NetworkBuilder is a Address->processors struct
 type NetworkBuilder struct {
-	keys    *crypto.KeyPair
-	address string
+	keys   *crypto.KeyPair
+	signer crypto.Signer
+
+	signEnvelope   network.EnvelopeSignFunc
+	verifyEnvelope network.EnvelopeVerifyFunc
+	address        string
 
 	plugins     *network.PluginList
 	pluginCount int
 
 	signaturePolicy crypto.SignaturePolicy
 	hashPolicy      crypto.HashPolicy
+
+	logicalClock *logical.Lamport
+
+	gater network.ConnectionGater
+
+	resourceLimits *network.ResourceLimits
+
+	maxStreams        int
+	maxStreamsPerPeer int
+
+	priority      []proto.Message
+	priorityShare float64
+
+	capabilities network.Capability
+
+	filters    []network.MessageFilter
+	preFilters []network.PreFilter
+
+	bandwidthLimits *network.BandwidthLimits
+
+	clock clock.Clock
+
+	quarantineBase time.Duration
+	quarantineMax  time.Duration
+
+	workers *network.WorkerPool
+
+	multipath network.MultipathMode
+
+	resolveDuplicates bool
+
+	networkID     string
+	signNetworkID bool
+
+	summarize       network.SummaryFunc
+	summaryInterval time.Duration
+	probeSize       int
+
+	messages     *network.MessageRegistry
+	deprecations []network.Deprecation
+
+	ticketLifetime time.Duration
+
+	unknownHandler network.UnknownHandler
+	timeEstimator  network.TimeEstimator
+
+	zone           string
+	zonePreference network.ZonePreference
+
+	handshakeTimeout time.Duration
+	readTimeout      time.Duration
+
+	maxHeaderSize  int
+	maxMessageSize int
+
+	peerstoreBackend  peerstore.Backend
+	peerstoreCapacity int
 }
 
 // NewNetworkBuilder lets you configure a network to build
@@ -31,12 +100,73 @@ func NewNetworkBuilder() *NetworkBuilder {
 	return &NetworkBuilder{
 		signaturePolicy: ed25519.New(),
 		hashPolicy:      blake2b.New(),
+
+		quarantineBase: network.DefaultQuarantineBase,
+		quarantineMax:  network.DefaultQuarantineMax,
+
+		handshakeTimeout: network.DefaultHandshakeTimeout,
+		readTimeout:      network.DefaultReadTimeout,
 	}
 }
 
 // SetKeys pair created from crypto.KeyPair
 func (builder *NetworkBuilder) SetKeys(pair *crypto.KeyPair) {
 	builder.keys = pair
+	builder.signer = nil
+	builder.signEnvelope, builder.verifyEnvelope = nil, nil
+}
+
+// SetSigner delegates signing to a signer, such as a remote signer, which signs on
+// behalf of a public key whose private key the network never holds.
+func (builder *NetworkBuilder) SetSigner(publicKey []byte, signer crypto.Signer) {
+	builder.keys = &crypto.KeyPair{PublicKey: publicKey}
+	builder.signer = signer
+	builder.signEnvelope, builder.verifyEnvelope = nil, nil
+}
+
+// SetEnvelopeSigning signs and verifies the envelopes of messages, and all else the
+// network signs and verifies such as storage records, with callbacks in place of a key
+// pair, such as with threshold signatures or keys attested by a TEE, on behalf of a
+// public key whose private key the network never holds. A nil verify verifies
+// signatures with the signature policy.
+func (builder *NetworkBuilder) SetEnvelopeSigning(publicKey []byte, sign network.EnvelopeSignFunc, verify network.EnvelopeVerifyFunc) {
+	builder.keys = &crypto.KeyPair{PublicKey: publicKey}
+	builder.signer = nil
+	builder.signEnvelope, builder.verifyEnvelope = sign, verify
+}
+
+// SetKeysFromFile loads the network's Ed25519 key pair from a file holding either a
+// PEM block, a JSON Web Key, or a hex-encoded private key.
+func (builder *NetworkBuilder) SetKeysFromFile(path string) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read key file %s", path)
+	}
+
+	pair, err := ed25519.ParseKeyPair(raw)
+	if err != nil {
+		return errors.Wrapf(err, "invalid key file %s", path)
+	}
+
+	builder.SetKeys(pair)
+	return nil
+}
+
+// SetKeysFromEnv loads the network's Ed25519 key pair from an environment variable
+// holding either a PEM block, a JSON Web Key, or a hex-encoded private key.
+func (builder *NetworkBuilder) SetKeysFromEnv(name string) error {
+	raw, exists := os.LookupEnv(name)
+	if !exists {
+		return errors.Errorf("environment variable %s is not set", name)
+	}
+
+	pair, err := ed25519.ParseKeyPair([]byte(raw))
+	if err != nil {
+		return errors.Wrapf(err, "invalid key in environment variable %s", name)
+	}
+
+	builder.SetKeys(pair)
+	return nil
 }
 
 // SetAddress sets the host address for the network.
@@ -54,6 +184,209 @@ func (builder *NetworkBuilder) SetHashPolicy(policy crypto.HashPolicy) {
 	builder.hashPolicy = policy
 }
 
+// SetLogicalClock sets the Lamport clock used to timestamp messages sent by the network.
+func (builder *NetworkBuilder) SetLogicalClock(clock *logical.Lamport) {
+	builder.logicalClock = clock
+}
+
+// SetConnectionGater sets the gater used to veto incoming and outgoing connections.
+func (builder *NetworkBuilder) SetConnectionGater(gater network.ConnectionGater) {
+	builder.gater = gater
+}
+
+// SetResourceLimits sets the global and per-peer budgets on streams, buffered bytes and
+// goroutines enforced by the network.
+func (builder *NetworkBuilder) SetResourceLimits(limits network.ResourceLimits) {
+	builder.resourceLimits = &limits
+}
+
+// SetStreamConcurrency bounds the number of incoming streams processed at once overall
+// and per peer, with capacity shared round-robin across peers. Zero values denote no limit.
+func (builder *NetworkBuilder) SetStreamConcurrency(maxStreams int, maxStreamsPerPeer int) {
+	builder.maxStreams = maxStreams
+	builder.maxStreamsPerPeer = maxStreamsPerPeer
+}
+
+// RegisterMessageType registers the type of message into the network's message
+// registry, failing should another type be registered by its name.
+func (builder *NetworkBuilder) RegisterMessageType(message proto.Message) error {
+	if builder.messages == nil {
+		builder.messages = network.NewMessageRegistry()
+	}
+	return builder.messages.Register(message)
+}
+
+// DeprecateMessageType advertises the type of message to peers as deprecated after a
+// version of the application, and counts the peers still sending it.
+func (builder *NetworkBuilder) DeprecateMessageType(message proto.Message, after string, reason string) {
+	builder.deprecations = append(builder.deprecations, network.Deprecation{
+		Type:   proto.MessageName(message),
+		After:  after,
+		Reason: reason,
+	})
+}
+
+// AddPriorityMessageType sends messages of the type of message, such as consensus
+// votes, over a priority lane ahead of all other messages.
+func (builder *NetworkBuilder) AddPriorityMessageType(message proto.Message) {
+	builder.priority = append(builder.priority, message)
+}
+
+// SetPriorityShare reserves a share of the egress bandwidth limit for messages sent
+// over the priority lane, which all other messages may not use.
+func (builder *NetworkBuilder) SetPriorityShare(share float64) {
+	builder.priorityShare = share
+}
+
+// SetCapabilities sets the services the network advertises to its peers.
+func (builder *NetworkBuilder) SetCapabilities(capabilities network.Capability) {
+	builder.capabilities = capabilities
+}
+
+// SetBandwidthLimits sets the global and per-peer caps on traffic sent and received
+// by the network.
+func (builder *NetworkBuilder) SetBandwidthLimits(limits network.BandwidthLimits) {
+	builder.bandwidthLimits = &limits
+}
+
+// SetClock sets the source of time used by the network for timeouts and timestamps.
+func (builder *NetworkBuilder) SetClock(clock clock.Clock) {
+	builder.clock = clock
+}
+
+// Clock returns the source of time set for the network. Nil if the system clock.
+func (builder *NetworkBuilder) Clock() clock.Clock {
+	return builder.clock
+}
+
+// SetDialQuarantine sets the period addresses are quarantined for after their first
+// failed dial, doubling with each consecutive failure up to max. A zero base disables
+// quarantining.
+func (builder *NetworkBuilder) SetDialQuarantine(base time.Duration, max time.Duration) {
+	builder.quarantineBase = base
+	builder.quarantineMax = max
+}
+
+// SetHandshakeTimeout sets how long accepted connections have to identify themselves
+// before being dropped. A zero timeout lets connections linger unidentified.
+func (builder *NetworkBuilder) SetHandshakeTimeout(timeout time.Duration) {
+	builder.handshakeTimeout = timeout
+}
+
+// SetReadTimeout sets how long accepted streams have to deliver their message in full
+// before being reaped. A zero timeout lets streams linger idle.
+func (builder *NetworkBuilder) SetReadTimeout(timeout time.Duration) {
+	builder.readTimeout = timeout
+}
+
+// SetMessageSizeLimits caps the size of received messages' envelopes excluding their
+// payloads, and of received messages overall. Zero values denote the defaults.
+func (builder *NetworkBuilder) SetMessageSizeLimits(maxHeaderSize int, maxMessageSize int) {
+	builder.maxHeaderSize = maxHeaderSize
+	builder.maxMessageSize = maxMessageSize
+}
+
+// SetPeerstoreBackend sets the backend the connection histories of peers, and the
+// records of the storage plugin, are persisted to. Nil if held only in memory.
+func (builder *NetworkBuilder) SetPeerstoreBackend(backend peerstore.Backend) {
+	builder.peerstoreBackend = backend
+}
+
+// SetPeerstoreCapacity sets the maximum number of peers of which connection histories
+// are held in memory. Zero denotes peerstore.DefaultCapacity.
+func (builder *NetworkBuilder) SetPeerstoreCapacity(capacity int) {
+	builder.peerstoreCapacity = capacity
+}
+
+// SetWorkerPool sets the pool of workers sending messages for the network, so that
+// several networks in one process may share a single pool. The networks still each
+// require an address of their own to listen on.
+func (builder *NetworkBuilder) SetWorkerPool(workers *network.WorkerPool) {
+	builder.workers = workers
+}
+
+// SetMultipathMode sets how messages to peers are spread across the additional paths
+// added to them.
+func (builder *NetworkBuilder) SetMultipathMode(mode network.MultipathMode) {
+	builder.multipath = mode
+}
+
+// SetResolveDuplicates sets whether peers share a single connection with the network,
+// keeping the connection dialed by whichever side has the lower ID.
+func (builder *NetworkBuilder) SetResolveDuplicates(resolve bool) {
+	builder.resolveDuplicates = resolve
+}
+
+// SetNetworkID sets the ID namespacing the network, such that nodes of networks by
+// other IDs refuse to connect to it.
+func (builder *NetworkBuilder) SetNetworkID(id string) {
+	builder.networkID = id
+}
+
+// SetSignNetworkID sets whether the network ID is mixed into message signatures.
+func (builder *NetworkBuilder) SetSignNetworkID(sign bool) {
+	builder.signNetworkID = sign
+}
+
+// SetSummaryFunc sets the callback providing the application state summarized onto
+// the network's pings and pongs. Pings and pongs carry no summary should it be nil.
+func (builder *NetworkBuilder) SetSummaryFunc(summarize network.SummaryFunc) {
+	builder.summarize = summarize
+}
+
+// SetSummaryInterval sets how often the network pushes its summary to peers in
+// heartbeat pings. It defaults to network.DefaultSummaryInterval.
+func (builder *NetworkBuilder) SetSummaryInterval(interval time.Duration) {
+	builder.summaryInterval = interval
+}
+
+// SetProbeSize sets the number of bytes echoed to peers upon connecting to them to
+// probe the round-trip time and bandwidth of their links. Links are not probed should
+// it be zero.
+func (builder *NetworkBuilder) SetProbeSize(size int) {
+	builder.probeSize = size
+}
+
+// SetTicketLifetime sets how long session tickets issued to verified peers remain
+// valid, sparing them from being challenged upon reconnecting. No tickets are issued
+// should it be zero.
+func (builder *NetworkBuilder) SetTicketLifetime(lifetime time.Duration) {
+	builder.ticketLifetime = lifetime
+}
+
+// SetZone labels the network with the datacenter or availability zone it runs in, and
+// sets whether peers in other zones or in the same zone are preferred when
+// broadcasting. Peers learn the zone on pings and pongs.
+func (builder *NetworkBuilder) SetZone(zone string, preference network.ZonePreference) {
+	builder.zone = zone
+	builder.zonePreference = preference
+}
+
+// SetTimeEstimator sets the estimator of the offset of the time agreed upon by peers
+// relative to the network's clock. Network time is the network's clock should it be nil.
+func (builder *NetworkBuilder) SetTimeEstimator(estimator network.TimeEstimator) {
+	builder.timeEstimator = estimator
+}
+
+// SetUnknownHandler sets a handler for messages of types unknown to the network, which
+// are otherwise dropped.
+func (builder *NetworkBuilder) SetUnknownHandler(handler network.UnknownHandler) {
+	builder.unknownHandler = handler
+}
+
+// AddMessageFilter registers a filter which inspects and drops incoming messages before
+// they are dispatched. Filters are run in the order they are added.
+func (builder *NetworkBuilder) AddMessageFilter(filter network.MessageFilter) {
+	builder.filters = append(builder.filters, filter)
+}
+
+// AddPreFilter registers a filter which cheaply inspects and drops incoming messages
+// by their envelopes before they are unmarshalled and verified. Pre-filters are run
+// in the order they are added.
+func (builder *NetworkBuilder) AddPreFilter(filter network.PreFilter) {
+	builder.preFilters = append(builder.preFilters, filter)
+}
+
 // AddPluginWithPriority register a new plugin onto the network with a set priority.
 func (builder *NetworkBuilder) AddPluginWithPriority(priority int, plugin network.PluginInterface) error {
 	// Initialize plugin list if not exist.
@@ -95,6 +428,16 @@ func (builder *NetworkBuilder) Build() (*network.Network, error) {
 		builder.plugins.SortByPriority()
 	}
 
+	if builder.messages == nil {
+		builder.messages = network.NewMessageRegistry()
+	}
+
+	for _, message := range builder.priority {
+		if err := builder.messages.Register(message); err != nil {
+			return nil, err
+		}
+	}
+
 	unifiedAddress, err := network.ToUnifiedAddress(builder.address)
 	if err != nil {
 		return nil, err
@@ -102,17 +445,27 @@ func (builder *NetworkBuilder) Build() (*network.Network, error) {
 
 	id := peer.CreateID(unifiedAddress, builder.keys.PublicKey)
 
+	// Sign with a copy of the keys, such that they are zeroed once the network is closed
+	// while those given to us remain usable, such as to restart the network with.
+	keys := builder.keys.Clone()
+	if err := keys.Lock(); err != nil && err != crypto.ErrMemoryLockUnsupported {
+		glog.Warningf("Failed to lock private key memory [err=%s]", err)
+	}
+
 	net := &network.Network{
-		ID:      id,
-		Keys:    builder.keys,
-		Address: unifiedAddress,
+		ID:     id,
+		Keys:   keys,
+		Signer: builder.signer,
+
+		SignEnvelope:   builder.signEnvelope,
+		VerifyEnvelope: builder.verifyEnvelope,
+		Address:        unifiedAddress,
 
 		Plugins: builder.plugins,
 
 		Peers: new(sync.Map),
 
 		Connections: new(sync.Map),
-		SendQueue:   make(chan *network.Packet, 4096),
 		RecvQueue:   make(chan *protobuf.Message, 4096),
 
 		Listening: make(chan struct{}),
@@ -120,9 +473,84 @@ func (builder *NetworkBuilder) Build() (*network.Network, error) {
 		SignaturePolicy: builder.signaturePolicy,
 		HashPolicy:      builder.hashPolicy,
 
+		LogicalClock: builder.logicalClock,
+
+		Gater: builder.gater,
+
+		Capabilities: builder.capabilities,
+
+		Messages: builder.messages,
+
+		Filters:    builder.filters,
+		PreFilters: builder.preFilters,
+
+		Peerstore: peerstore.New(peerstore.DefaultFlapWindow, builder.clock),
+
+		Clock: clock.Or(builder.clock),
+
+		Workers: builder.workers,
+
+		Multipath:         builder.multipath,
+		ResolveDuplicates: builder.resolveDuplicates,
+
+		NetworkID:     builder.networkID,
+		SignNetworkID: builder.signNetworkID,
+
+		Summarize:       builder.summarize,
+		SummaryInterval: builder.summaryInterval,
+		ProbeSize:       builder.probeSize,
+
+		TicketLifetime: builder.ticketLifetime,
+
+		Zone:           builder.zone,
+		ZonePreference: builder.zonePreference,
+
+		Deprecations:   builder.deprecations,
+		UnknownHandler: builder.unknownHandler,
+		TimeEstimator:  builder.timeEstimator,
+
+		HandshakeTimeout: builder.handshakeTimeout,
+		ReadTimeout:      builder.readTimeout,
+
+		MaxHeaderSize:  builder.maxHeaderSize,
+		MaxMessageSize: builder.maxMessageSize,
+
 		Kill: make(chan struct{}),
 	}
 
+	if builder.peerstoreBackend != nil {
+		net.Peerstore.SetBackend(builder.peerstoreBackend)
+	}
+	net.Peerstore.SetCapacity(builder.peerstoreCapacity)
+
+	if builder.resourceLimits != nil {
+		net.Resources = network.NewResourceManager(*builder.resourceLimits)
+	}
+
+	if len(builder.priority) > 0 {
+		net.Priority = network.NewPriorityLane(network.DefaultPriorityWorkers, builder.priority...)
+		net.Priority.Share = builder.priorityShare
+	}
+
+	if builder.maxStreams > 0 || builder.maxStreamsPerPeer > 0 {
+		net.Streams = network.NewStreamScheduler(builder.maxStreams, builder.maxStreamsPerPeer)
+	}
+
+	if builder.quarantineBase > 0 {
+		net.Quarantine = network.NewDialQuarantine(builder.quarantineBase, builder.quarantineMax, builder.clock)
+	}
+
+	if builder.bandwidthLimits != nil {
+		net.Bandwidth = network.NewThrottle(*builder.bandwidthLimits, builder.clock)
+	}
+
+	// Message types are to be registered before the network is built, unless the
+	// registry is explicitly unlocked.
+	net.Messages.Freeze()
+
+	net.ConfigFingerprint = net.EffectiveConfig().Fingerprint()
+	glog.Infof("Built network with config fingerprint %x.", net.ConfigFingerprint)
+
 	net.Init()
 
 	return net, nil
@@ -2,9 +2,13 @@ package builders
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 
+	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/crypto/signing/ed25519"
 	"github.com/perlin-network/noise/network"
 	"github.com/perlin-network/noise/network/discovery"
@@ -105,4 +109,111 @@ func TestPeers(t *testing.T) {
 	}
 }
 
+func TestSharedWorkerPool(t *testing.T) {
+	workers := network.NewWorkerPool(2, nil)
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := NewNetworkBuilder()
+		builder.SetKeys(ed25519.RandomKeyPair())
+		builder.SetAddress(network.FormatAddress("mem", host, 373+uint16(i)))
+		builder.SetWorkerPool(workers)
+		builder.AddPlugin(new(discovery.Plugin))
+
+		net, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if net.Workers != workers || net.SendQueue != workers.Queue {
+			t.Fatal("expected network to send messages through the shared worker pool")
+		}
+
+		go net.Listen()
+		net.BlockUntilListening()
+		defer net.Close()
+
+		nodes = append(nodes, net)
+	}
+
+	if err := nodes[1].BootstrapWithOptions(context.Background(), network.BootstrapOptions{MaxAttempts: 1}, nodes[0].Address); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // Broadcast functions are tested through examples.
+
+func TestSetKeysFromEnv(t *testing.T) {
+	encoded, err := ed25519.MarshalJWK(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("NOISE_TEST_KEY", string(encoded))
+	defer os.Unsetenv("NOISE_TEST_KEY")
+
+	builder := NewNetworkBuilder()
+	if err := builder.SetKeysFromEnv("NOISE_TEST_KEY"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(builder.keys.PrivateKey, keys.PrivateKey) {
+		t.Fatal("expected keys to be loaded from the environment")
+	}
+
+	if err := builder.SetKeysFromEnv("NOISE_TEST_KEY_UNSET"); err == nil {
+		t.Fatal("expected an unset environment variable to be rejected")
+	}
+}
+
+func TestSetKeysFromFile(t *testing.T) {
+	encoded, err := ed25519.MarshalPEM(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ioutil.TempFile("", "noise-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	file.Write(encoded)
+	file.Close()
+
+	builder := NewNetworkBuilder()
+	if err := builder.SetKeysFromFile(file.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(builder.keys.PrivateKey, keys.PrivateKey) {
+		t.Fatal("expected keys to be loaded from the file")
+	}
+}
+
+func TestSetSigner(t *testing.T) {
+	signer := ed25519.RandomKeyPair()
+
+	builder := NewNetworkBuilder()
+	builder.SetSigner(signer.PublicKey, signer)
+	builder.SetAddress(fmt.Sprintf("%s://%s:%d", protocol, host, port))
+
+	net, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(net.Keys.PrivateKey) != 0 {
+		t.Fatal("expected the network to not hold the signer's private key")
+	}
+
+	signature, err := net.Sign([]byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !crypto.Verify(net.SignaturePolicy, net.HashPolicy, signer.PublicKey, []byte("message"), signature) {
+		t.Fatal("expected the network to sign with its signer")
+	}
+}
@@ -0,0 +1,106 @@
+package network
+
+import (
+	"math/rand"
+	"strings"
+	"sync/atomic"
+)
+
+// Capability is a bitmask of services a peer provides, advertised alongside every
+// message a peer sends.
+type Capability uint64
+
+const (
+	// CapabilityRelay marks a peer as relaying traffic on behalf of other peers.
+	CapabilityRelay Capability = 1 << iota
+
+	// CapabilityArchival marks a peer as keeping the full history of the data it stores.
+	CapabilityArchival
+
+	// CapabilityStateProvider marks a peer as serving state snapshots to syncing peers.
+	CapabilityStateProvider
+)
+
+var capabilityNames = []struct {
+	bit  Capability
+	name string
+}{
+	{CapabilityRelay, "relay"},
+	{CapabilityArchival, "archival"},
+	{CapabilityStateProvider, "state-provider"},
+}
+
+// Has returns true should all bits of capability be set.
+func (c Capability) Has(capability Capability) bool {
+	return c&capability == capability
+}
+
+// String returns the names of all known capabilities set, separated by a '|'.
+func (c Capability) String() string {
+	var names []string
+
+	for _, known := range capabilityNames {
+		if c.Has(known.bit) {
+			names = append(names, known.name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, "|")
+}
+
+// observeCapabilities records the capabilities a peer last advertised.
+func (c *PeerClient) observeCapabilities(capabilities uint64) {
+	atomic.StoreUint64(&c.capabilities, capabilities)
+}
+
+// Capabilities returns the capabilities the peer last advertised. Until the peer has
+// been heard from, no capabilities are assumed.
+func (c *PeerClient) Capabilities() Capability {
+	return Capability(atomic.LoadUint64(&c.capabilities))
+}
+
+// HasCapability returns true should the peer have advertised all bits of capability.
+func (c *PeerClient) HasCapability(capability Capability) bool {
+	return c.Capabilities().Has(capability)
+}
+
+// PeersWithCapability returns all connected peers which advertised all bits of capability.
+func (n *Network) PeersWithCapability(capability Capability) []*PeerClient {
+	var clients []*PeerClient
+
+	n.Peers.Range(func(key, value interface{}) bool {
+		if client := value.(*PeerClient); client.HasCapability(capability) {
+			clients = append(clients, client)
+		}
+		return true
+	})
+
+	return clients
+}
+
+// CapabilitySelector selects K peers at random out of those which advertised all bits
+// of Capability, such that requests are only routed to peers which serve them. A
+// non-positive K selects all such peers.
+type CapabilitySelector struct {
+	Capability Capability
+	K          int
+}
+
+// Select implements Selector.
+func (s CapabilitySelector) Select(net *Network) []*PeerClient {
+	clients := net.PeersWithCapability(s.Capability)
+
+	rand.Shuffle(len(clients), func(i, j int) {
+		clients[i], clients[j] = clients[j], clients[i]
+	})
+
+	if s.K > 0 && len(clients) > s.K {
+		clients = clients[:s.K]
+	}
+
+	return clients
+}
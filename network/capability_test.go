@@ -0,0 +1,65 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func TestCapabilityString(t *testing.T) {
+	if s := (network.CapabilityRelay | network.CapabilityStateProvider).String(); s != "relay|state-provider" {
+		t.Fatalf("unexpected capability string %q", s)
+	}
+
+	if s := network.Capability(0).String(); s != "none" {
+		t.Fatalf("unexpected capability string %q", s)
+	}
+}
+
+func TestPeersWithCapability(t *testing.T) {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", 13180))
+	builder.AddPlugin(new(discovery.Plugin))
+	builder.SetCapabilities(network.CapabilityRelay | network.CapabilityStateProvider)
+
+	alice, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go alice.Listen()
+	alice.BlockUntilListening()
+
+	bob := buildNode(t, 13181, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	bob.Bootstrap(alice.Address)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for len(bob.PeersWithCapability(network.CapabilityStateProvider)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for alice's capabilities")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	providers := network.CapabilitySelector{Capability: network.CapabilityStateProvider}.Select(bob)
+	if len(providers) != 1 || providers[0].Address() != alice.Address {
+		t.Fatalf("expected alice to be selected as a state provider, got %d peers", len(providers))
+	}
+
+	if peers := bob.PeersWithCapability(network.CapabilityArchival); len(peers) != 0 {
+		t.Fatalf("expected no archival peers, got %d", len(peers))
+	}
+
+	if peers := alice.PeersWithCapability(network.CapabilityRelay); len(peers) != 0 {
+		t.Fatalf("expected bob to advertise no capabilities, got %d relays", len(peers))
+	}
+}
@@ -0,0 +1,108 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func createFrame(t *testing.T, message *any.Any) *Frame {
+	sender := protobuf.ID(peer.CreateID("tcp://127.0.0.1:3000", []byte("sender")))
+
+	return &Frame{
+		Time:      time.Now(),
+		Direction: Inbound,
+		Peer:      "tcp://127.0.0.1:3000",
+		Message:   &protobuf.Message{Message: message, Sender: &sender, MessageNonce: 1},
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	ping, err := ptypes.MarshalAny(&protobuf.Ping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	writer := NewJSONWriter(&buffer)
+
+	if err := writer.WriteFrame(createFrame(t, ping)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.WriteFrame(createFrame(t, &any.Any{TypeUrl: "type.googleapis.com/unknown", Value: []byte{1}})); err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := json.NewDecoder(&buffer)
+
+	var known, unknown jsonFrame
+	if err := decoder.Decode(&known); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoder.Decode(&unknown); err != nil {
+		t.Fatal(err)
+	}
+
+	if known.Envelope == nil || known.Raw != nil {
+		t.Fatal("expected envelope of registered type to be decoded")
+	}
+
+	if unknown.Envelope != nil || unknown.Raw == nil {
+		t.Fatal("expected envelope of unregistered type to be captured raw")
+	}
+}
+
+func TestPCAPNGWriter(t *testing.T) {
+	var buffer bytes.Buffer
+
+	writer, err := NewPCAPNGWriter(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.WriteFrame(createFrame(t, &any.Any{TypeUrl: "type.googleapis.com/unknown", Value: []byte{1, 2, 3}})); err != nil {
+		t.Fatal(err)
+	}
+
+	// Walk all blocks, checking that their leading and trailing lengths agree.
+	data := buffer.Bytes()
+
+	var types []uint32
+
+	for len(data) > 0 {
+		if len(data) < 12 {
+			t.Fatal("truncated block")
+		}
+
+		length := binary.LittleEndian.Uint32(data[4:8])
+		if length%4 != 0 || int(length) > len(data) {
+			t.Fatalf("invalid block length %d", length)
+		}
+
+		if binary.LittleEndian.Uint32(data[length-4:length]) != length {
+			t.Fatal("block lengths do not agree")
+		}
+
+		types = append(types, binary.LittleEndian.Uint32(data[0:4]))
+		data = data[length:]
+	}
+
+	expected := []uint32{pcapngSectionHeaderBlock, pcapngInterfaceBlock, pcapngEnhancedPacketBlock}
+	if len(types) != len(expected) {
+		t.Fatalf("expected %d blocks, got %d", len(expected), len(types))
+	}
+
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Fatalf("expected block %d to be of type %x, got %x", i, expected[i], types[i])
+		}
+	}
+}
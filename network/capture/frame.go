@@ -0,0 +1,32 @@
+package capture
+
+import (
+	"time"
+
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// Direction denotes whether a frame was received or sent.
+type Direction string
+
+const (
+	Inbound  Direction = "inbound"
+	Outbound Direction = "outbound"
+)
+
+// Frame is a decoded message envelope captured off the wire.
+type Frame struct {
+	Time      time.Time
+	Direction Direction
+
+	// Address of the remote peer the frame was received from or sent to.
+	Peer string
+
+	Message *protobuf.Message
+}
+
+// Writer persists captured frames in a particular file format.
+type Writer interface {
+	WriteFrame(frame *Frame) error
+	Close() error
+}
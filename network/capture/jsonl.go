@@ -0,0 +1,82 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/pkg/errors"
+)
+
+type jsonFrame struct {
+	Time      time.Time `json:"time"`
+	Direction Direction `json:"direction"`
+	Peer      string    `json:"peer"`
+	Sender    string    `json:"sender,omitempty"`
+	ID        string    `json:"id"`
+
+	// Envelope is the envelope rendered as JSON should its message type be registered.
+	Envelope json.RawMessage `json:"envelope,omitempty"`
+
+	// Raw is the serialized envelope should its message type not be registered.
+	Raw []byte `json:"raw,omitempty"`
+}
+
+// JSONWriter writes captured frames as newline-delimited JSON.
+type JSONWriter struct {
+	mutex     sync.Mutex
+	writer    io.Writer
+	marshaler jsonpb.Marshaler
+}
+
+// NewJSONWriter creates a writer writing captured frames as newline-delimited JSON to w.
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{writer: w}
+}
+
+// WriteFrame implements Writer.
+func (w *JSONWriter) WriteFrame(frame *Frame) error {
+	entry := jsonFrame{
+		Time:      frame.Time,
+		Direction: frame.Direction,
+		Peer:      frame.Peer,
+		ID:        network.NewMessageID(frame.Message).String(),
+	}
+
+	if frame.Message.Sender != nil {
+		entry.Sender = peer.ID(*frame.Message.Sender).String()
+	}
+
+	var envelope bytes.Buffer
+
+	if err := w.marshaler.Marshal(&envelope, frame.Message); err == nil {
+		entry.Envelope = envelope.Bytes()
+	} else if entry.Raw, err = proto.Marshal(frame.Message); err != nil {
+		return errors.Wrap(err, "failed to marshal captured frame")
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal captured frame")
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err = w.writer.Write(append(line, '\n'))
+	return err
+}
+
+// Close implements Writer, closing the underlying writer should it be closeable.
+func (w *JSONWriter) Close() error {
+	if closer, ok := w.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package capture
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	pcapngSectionHeaderBlock    = 0x0A0D0D0A
+	pcapngInterfaceBlock        = 0x00000001
+	pcapngEnhancedPacketBlock   = 0x00000006
+	pcapngByteOrderMagic        = 0x1A2B3C4D
+	pcapngOptionEnd             = 0
+	pcapngOptionComment         = 1
+	pcapngInterfaceTimestampRes = 9
+
+	// LinkTypeUser0 is the link type frames are captured as. Wireshark may be configured
+	// to decode it as protobuf-encoded noise envelopes under Preferences > Protocols > DLT_USER.
+	LinkTypeUser0 = 147
+)
+
+// PCAPNGWriter writes captured frames as a PCAPNG capture. Each packet holds a
+// protobuf-encoded envelope, and is annotated with its direction, remote peer and sender.
+type PCAPNGWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewPCAPNGWriter creates a writer writing captured frames as PCAPNG to w, writing out
+// the section and interface headers immediately.
+func NewPCAPNGWriter(w io.Writer) (*PCAPNGWriter, error) {
+	writer := &PCAPNGWriter{writer: w}
+
+	// Section header: byte-order magic, version 1.0, and an unspecified section length.
+	section := make([]byte, 16)
+	binary.LittleEndian.PutUint32(section[0:4], pcapngByteOrderMagic)
+	binary.LittleEndian.PutUint16(section[4:6], 1)
+	binary.LittleEndian.PutUint16(section[6:8], 0)
+	binary.LittleEndian.PutUint64(section[8:16], ^uint64(0))
+
+	if err := writer.writeBlock(pcapngSectionHeaderBlock, section); err != nil {
+		return nil, err
+	}
+
+	// Interface description: link type, no snapshot length, and microsecond timestamps.
+	iface := make([]byte, 8)
+	binary.LittleEndian.PutUint16(iface[0:2], LinkTypeUser0)
+	binary.LittleEndian.PutUint32(iface[4:8], 0)
+	iface = appendOption(iface, pcapngInterfaceTimestampRes, []byte{6})
+	iface = appendOption(iface, pcapngOptionEnd, nil)
+
+	if err := writer.writeBlock(pcapngInterfaceBlock, iface); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// appendOption appends a 32-bit aligned block option.
+func appendOption(body []byte, code uint16, value []byte) []byte {
+	header := make([]byte, 4)
+	binary.LittleEndian.PutUint16(header[0:2], code)
+	binary.LittleEndian.PutUint16(header[2:4], uint16(len(value)))
+
+	body = append(body, header...)
+	body = append(body, value...)
+
+	return append(body, make([]byte, padding(len(value)))...)
+}
+
+func padding(n int) int {
+	return (4 - n%4) % 4
+}
+
+// writeBlock writes a block of a given type wrapped with its total length.
+func (w *PCAPNGWriter) writeBlock(blockType uint32, body []byte) error {
+	length := uint32(12 + len(body))
+
+	block := make([]byte, 0, length)
+	block = appendUint32(block, blockType)
+	block = appendUint32(block, length)
+	block = append(block, body...)
+	block = appendUint32(block, length)
+
+	_, err := w.writer.Write(block)
+	return err
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, v)
+	return append(b, buf...)
+}
+
+// WriteFrame implements Writer.
+func (w *PCAPNGWriter) WriteFrame(frame *Frame) error {
+	data, err := proto.Marshal(frame.Message)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal captured frame")
+	}
+
+	timestamp := uint64(frame.Time.UnixNano() / 1000)
+
+	body := make([]byte, 0, 20+len(data)+padding(len(data)))
+	body = appendUint32(body, 0)
+	body = appendUint32(body, uint32(timestamp>>32))
+	body = appendUint32(body, uint32(timestamp))
+	body = appendUint32(body, uint32(len(data)))
+	body = appendUint32(body, uint32(len(data)))
+	body = append(body, data...)
+	body = append(body, make([]byte, padding(len(data)))...)
+
+	comment := fmt.Sprintf("%s peer=%s", frame.Direction, frame.Peer)
+	if frame.Message.Sender != nil {
+		comment += fmt.Sprintf(" sender=%s", peer.ID(*frame.Message.Sender).String())
+	}
+
+	body = appendOption(body, pcapngOptionComment, []byte(comment))
+	body = appendOption(body, pcapngOptionEnd, nil)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.writeBlock(pcapngEnhancedPacketBlock, body)
+}
+
+// Close implements Writer, closing the underlying writer should it be closeable.
+func (w *PCAPNGWriter) Close() error {
+	if closer, ok := w.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
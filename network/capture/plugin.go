@@ -0,0 +1,96 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+// Plugin captures every decoded inbound and outbound message envelope for debugging.
+type Plugin struct {
+	*network.Plugin
+
+	writer Writer
+	clock  clock.Clock
+	net    *network.Network
+}
+
+var (
+	// PluginID to reference capture plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// New creates a capture plugin writing frames to a writer.
+func New(writer Writer) *Plugin {
+	return &Plugin{writer: writer}
+}
+
+// Create creates a file which frames are captured to; as PCAPNG should its extension be
+// .pcapng, or as newline-delimited JSON otherwise.
+func Create(path string) (*Plugin, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create capture file %s", path)
+	}
+
+	if filepath.Ext(path) != ".pcapng" {
+		return New(NewJSONWriter(file)), nil
+	}
+
+	writer, err := NewPCAPNGWriter(file)
+	if err != nil {
+		file.Close()
+		return nil, errors.Wrapf(err, "failed to write capture file %s", path)
+	}
+
+	return New(writer), nil
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.clock = net.Clock
+	p.net = net
+}
+
+// Inbound implements the plugin callback
+func (p *Plugin) Inbound(client *network.PeerClient, msg *protobuf.Message) {
+	// Capture envelopes in the version they were signed in rather than upgraded, such
+	// that recordings of them verify upon replay.
+	if p.net != nil {
+		if signed, err := p.net.SignedEnvelope(msg); err == nil {
+			msg = signed
+		}
+	}
+
+	p.capture(Inbound, client.Address(), msg)
+}
+
+// Outbound implements the plugin callback
+func (p *Plugin) Outbound(address string, msg *protobuf.Message) {
+	p.capture(Outbound, address, msg)
+}
+
+// Cleanup implements the plugin callback
+func (p *Plugin) Cleanup(net *network.Network) {
+	if err := p.writer.Close(); err != nil {
+		glog.Error(err)
+	}
+}
+
+func (p *Plugin) capture(direction Direction, address string, msg *protobuf.Message) {
+	frame := &Frame{
+		Time:      clock.Or(p.clock).Now(),
+		Direction: direction,
+		Peer:      address,
+		Message:   msg,
+	}
+
+	if err := p.writer.WriteFrame(frame); err != nil {
+		glog.Errorf("failed to capture frame: %s", err)
+	}
+}
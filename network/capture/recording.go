@@ -0,0 +1,123 @@
+package capture
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// maxRecordingLineSize caps the size of a single recorded envelope when read back.
+const maxRecordingLineSize = 16 * 1024 * 1024
+
+type recordedFrame struct {
+	Time time.Time `json:"time"`
+	Peer string    `json:"peer"`
+
+	// Envelope is the serialized envelope, kept as is such that its signature may be
+	// verified upon replay.
+	Envelope []byte `json:"envelope"`
+}
+
+// RecordingWriter records inbound frames as newline-delimited JSON, losslessly such
+// that they may be replayed into a fresh network. Outbound frames are skipped.
+type RecordingWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewRecordingWriter creates a writer recording inbound frames to w.
+func NewRecordingWriter(w io.Writer) *RecordingWriter {
+	return &RecordingWriter{writer: w}
+}
+
+// WriteFrame implements Writer.
+func (w *RecordingWriter) WriteFrame(frame *Frame) error {
+	if frame.Direction != Inbound {
+		return nil
+	}
+
+	// Marshal a copy, as marshalling caches the envelope's size within it while the
+	// network may still be reading it.
+	envelope, err := proto.Marshal(proto.Clone(frame.Message))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal recorded frame")
+	}
+
+	line, err := json.Marshal(recordedFrame{Time: frame.Time, Peer: frame.Peer, Envelope: envelope})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal recorded frame")
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err = w.writer.Write(append(line, '\n'))
+	return err
+}
+
+// Close implements Writer, closing the underlying writer should it be closeable.
+func (w *RecordingWriter) Close() error {
+	if closer, ok := w.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Record creates a file which all inbound envelopes are recorded to for replay.
+func Record(path string) (*Plugin, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create recording %s", path)
+	}
+
+	return New(NewRecordingWriter(file)), nil
+}
+
+// ReadRecording reads back the envelopes recorded by a RecordingWriter.
+func ReadRecording(r io.Reader) ([]network.ReplayEnvelope, error) {
+	var envelopes []network.ReplayEnvelope
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxRecordingLineSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		var frame recordedFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, errors.Wrapf(err, "invalid recorded frame on line %d", line)
+		}
+
+		msg := new(protobuf.Message)
+		if err := proto.Unmarshal(frame.Envelope, msg); err != nil {
+			return nil, errors.Wrapf(err, "invalid recorded envelope on line %d", line)
+		}
+
+		envelopes = append(envelopes, network.ReplayEnvelope{Time: frame.Time, Address: frame.Peer, Message: msg})
+	}
+
+	return envelopes, scanner.Err()
+}
+
+// Replay replays a recording made by Record into a network.
+func Replay(ctx context.Context, net *network.Network, path string, options network.ReplayOptions) (network.ReplayStats, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return network.ReplayStats{}, errors.Wrapf(err, "failed to open recording %s", path)
+	}
+	defer file.Close()
+
+	envelopes, err := ReadRecording(file)
+	if err != nil {
+		return network.ReplayStats{}, errors.Wrapf(err, "failed to read recording %s", path)
+	}
+
+	return net.Replay(ctx, envelopes, options)
+}
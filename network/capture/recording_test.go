@@ -0,0 +1,130 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// pingRecorder records the timestamps of pings received, in order.
+type pingRecorder struct {
+	*network.Plugin
+
+	mutex      sync.Mutex
+	timestamps []int64
+}
+
+func (p *pingRecorder) Receive(ctx *network.PluginContext) error {
+	if ping, ok := ctx.Message().(*protobuf.Ping); ok {
+		p.mutex.Lock()
+		p.timestamps = append(p.timestamps, ping.Timestamp)
+		p.mutex.Unlock()
+	}
+	return nil
+}
+
+func (p *pingRecorder) received() []int64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]int64(nil), p.timestamps...)
+}
+
+func buildMemoryNode(t *testing.T, port uint16, plugins ...network.PluginInterface) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+
+	for _, plugin := range plugins {
+		builder.AddPlugin(plugin)
+	}
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var recording bytes.Buffer
+
+	original := new(pingRecorder)
+
+	alice := buildMemoryNode(t, 100, New(NewRecordingWriter(&recording)), original)
+	bob := buildMemoryNode(t, 101)
+
+	client, err := bob.Client(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := int64(1); i <= 5; i++ {
+		if _, err := client.Tell(&protobuf.Ping{Timestamp: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for deadline := time.Now().Add(3 * time.Second); len(original.received()) < 5; {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 5 pings to be received, got %d", len(original.received()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	alice.Close()
+	bob.Close()
+
+	envelopes, err := ReadRecording(&recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(envelopes) != 5 {
+		t.Fatalf("expected 5 envelopes to be recorded, got %d", len(envelopes))
+	}
+
+	replayed := new(pingRecorder)
+
+	fresh := buildMemoryNode(t, 102, replayed)
+	defer fresh.Close()
+
+	stats, err := fresh.Replay(context.Background(), envelopes, network.ReplayOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Delivered != 5 || stats.Dropped != 0 {
+		t.Fatalf("expected all 5 envelopes to be delivered, got %+v", stats)
+	}
+
+	if len(replayed.received()) != 5 {
+		t.Fatalf("expected 5 pings to be handled once replayed, got %d", len(replayed.received()))
+	}
+
+	// Pings must be handled in the order they were received in, regardless of the
+	// order in which they were originally handled.
+	for i, timestamp := range replayed.received() {
+		var ping protobuf.Ping
+		if err := ptypes.UnmarshalAny(envelopes[i].Message.Message, &ping); err != nil {
+			t.Fatal(err)
+		}
+
+		if timestamp != ping.Timestamp {
+			t.Fatalf("expected ping %d to be replayed in the order it was received, got %v", i, replayed.received())
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package network
 
 import (
+	"context"
 	"net"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
 	"github.com/perlin-network/noise/network/rpc"
 	"github.com/perlin-network/noise/peer"
 	"github.com/perlin-network/noise/protobuf"
@@ -17,8 +19,12 @@ import (
 type PeerClient struct {
 	Network *Network
 
-	ID      *peer.ID
-	Address string
+	// identity guards the ID and address of the peer, as the ID is only set once the
+	// peer identifies itself, and both change should it resume its connection from
+	// another address.
+	identity sync.RWMutex
+	id       *peer.ID
+	address  string
 
 	Requests     *sync.Map
 	RequestNonce uint64
@@ -30,6 +36,58 @@ type PeerClient struct {
 
 	jobs chan func()
 
+	// done is closed once the client is closed, stopping jobs from being submitted or
+	// executed. The jobs channel itself is never closed, as submitters may race Close.
+	done chan struct{}
+
+	// observed is the net.Addr the peer was last observed connecting to us from.
+	observed atomic.Value
+
+	// connInfo describes the connection the peer was last observed connecting over.
+	connInfo atomic.Value
+
+	// envelopeVersion is the highest envelope version the peer supports.
+	envelopeVersion uint32
+
+	// capabilities is the bitmask of services the peer last advertised.
+	capabilities uint64
+
+	handshake handshake
+
+	// paths are the additional paths to the peer.
+	paths multipath
+
+	// slowdown tracks the pauses to sending between us and the peer.
+	slowdown slowdown
+
+	// values are the values plugins attached to the peer.
+	values Values
+
+	// summary is the Summary the peer last piggybacked onto its pings or pongs.
+	summary atomic.Value
+
+	// fingerprint is the config fingerprint the peer last piggybacked onto its pings
+	// or pongs.
+	fingerprint atomic.Value
+
+	// deprecations are the Deprecations the peer last piggybacked onto its pings or
+	// pongs.
+	deprecations atomic.Value
+
+	// zone is the zone the peer last labelled itself with on its pings or pongs.
+	zone atomic.Value
+
+	// probe is the LinkProbe last measured of the link to the peer.
+	probe atomic.Value
+
+	// identified is set once the peer identified itself over an accepted connection,
+	// beyond which connections it identifies itself over are additional paths.
+	identified uint32
+
+	// connections is the number of accepted connections the peer identified itself over
+	// which are open.
+	connections int32
+
 	closed uint32 // for atomic ops
 }
 
@@ -40,6 +98,13 @@ type StreamState struct {
 	closed        bool
 	readDeadline  time.Time
 	writeDeadline time.Time
+
+	// compression is the codec negotiated for writes. COMPRESSION_NONE if uncompressed.
+	compression protobuf.Compression
+
+	// decompression is the codec negotiated for the peer's writes. COMPRESSION_NONE if
+	// the peer may only send uncompressed data.
+	decompression protobuf.Compression
 }
 
 // createPeerClient creates a stub peer client.
@@ -51,7 +116,7 @@ func createPeerClient(network *Network, address string) (*PeerClient, error) {
 
 	client := &PeerClient{
 		Network:      network,
-		Address:      address,
+		address:      address,
 		Requests:     new(sync.Map),
 		RequestNonce: 0,
 
@@ -64,33 +129,73 @@ func createPeerClient(network *Network, address string) (*PeerClient, error) {
 		},
 
 		jobs: make(chan func(), 128),
+		done: make(chan struct{}),
 	}
 
 	return client, nil
 }
 
+// ID returns the ID of the peer. Nil until the peer identifies itself.
+func (c *PeerClient) ID() *peer.ID {
+	c.identity.RLock()
+	defer c.identity.RUnlock()
+
+	return c.id
+}
+
+// Address returns the address the peer is connected to at.
+func (c *PeerClient) Address() string {
+	c.identity.RLock()
+	defer c.identity.RUnlock()
+
+	return c.address
+}
+
+// setID sets the ID of the peer once it identifies itself.
+func (c *PeerClient) setID(id *peer.ID) {
+	c.identity.Lock()
+	defer c.identity.Unlock()
+
+	c.id = id
+}
+
+// migrate sets the ID of the peer, and the address it is connected to at, once it
+// resumes its connection from another address.
+func (c *PeerClient) migrate(id *peer.ID, address string) {
+	c.identity.Lock()
+	defer c.identity.Unlock()
+
+	c.id = id
+	c.address = address
+}
+
 func (c *PeerClient) Init() {
+	c.Network.Peerstore.Connected(c.Address())
+
 	// Execute 'peer connect' callback for all registered plugins.
 	c.Network.Plugins.Each(func(plugin PluginInterface) {
 		plugin.PeerConnect(c)
 	})
-	go c.executeJobs()
+	c.Network.spawn(GoroutineJobs, c.executeJobs)
 }
 
+// Submit queues a job to be executed in order with the peer's other jobs. The job is
+// dropped should the client be closed.
 func (c *PeerClient) Submit(job func()) {
-	// FIXME: This is a hack to prevent closed c.jobs from panicking the program.
-	defer func() {
-		if err := recover(); err != nil {
-
-		}
-	}()
-
-	c.jobs <- job
+	select {
+	case c.jobs <- job:
+	case <-c.done:
+	}
 }
 
 func (c *PeerClient) executeJobs() {
-	for job := range c.jobs {
-		job()
+	for {
+		select {
+		case job := <-c.jobs:
+			job()
+		case <-c.done:
+			return
+		}
 	}
 }
 
@@ -105,48 +210,78 @@ func (c *PeerClient) Close() error {
 	c.stream.closed = true
 	c.stream.Unlock()
 
-	if c.jobs != nil {
-		close(c.jobs)
+	if c.done != nil {
+		close(c.done)
 	}
 
+	c.paths.close()
+
+	c.Network.Peerstore.Disconnected(c.Address())
+	c.Network.forgetDeprecated(c.Address())
+
 	// Handle 'on peer disconnect' callback for plugins.
 	c.Network.Plugins.Each(func(plugin PluginInterface) {
 		plugin.PeerDisconnect(c)
 	})
 
+	// Drop the values plugins attached to the peer once they are done with them.
+	c.values.reset()
+
 	// Remove entries from node's network.
-	if c.ID != nil {
+	if c.ID() != nil {
 		// close out connections
-		if conn, ok := c.Network.Connections.Load(c.ID.Address); ok {
+		if conn, ok := c.Network.Connections.Load(c.ID().Address); ok {
 			if state, ok := conn.(*ConnState); ok && state != nil {
 				state.session.Close()
 			}
 		}
 
-		c.Network.Peers.Delete(c.ID.Address)
-		c.Network.Connections.Delete(c.ID.Address)
+		c.Network.Peers.Delete(c.ID().Address)
+		c.Network.Connections.Delete(c.ID().Address)
 	}
 
 	return nil
 }
 
-// Write asynchronously emit a message to a given peer.
-func (c *PeerClient) Tell(message proto.Message) error {
+// Tell asynchronously emit a message to a given peer. Returns the ID of the sent message.
+func (c *PeerClient) Tell(message proto.Message) (MessageID, error) {
 	signed, err := c.Network.PrepareMessage(message)
 	if err != nil {
-		return errors.Wrap(err, "failed to sign message")
+		return MessageID{}, errors.Wrap(err, "failed to sign message")
 	}
 
-	err = c.Network.Write(c.Address, signed)
+	sent, err := c.Network.send(c.Address(), signed)
 	if err != nil {
-		return errors.Wrapf(err, "failed to send message to %s", c.Address)
+		return MessageID{}, errors.Wrapf(err, "failed to send message to %s", c.Address())
 	}
 
-	return nil
+	return NewMessageID(sent), nil
+}
+
+// TellAny sends a message already marshalled into an Any to the peer, such as to forward
+// a message of a type unknown to the network.
+func (c *PeerClient) TellAny(message *any.Any) (MessageID, error) {
+	signed, err := c.Network.PrepareAny(message)
+	if err != nil {
+		return MessageID{}, errors.Wrap(err, "failed to sign message")
+	}
+
+	sent, err := c.Network.send(c.Address(), signed)
+	if err != nil {
+		return MessageID{}, errors.Wrapf(err, "failed to send message to %s", c.Address())
+	}
+
+	return NewMessageID(sent), nil
 }
 
 // Request requests for a response for a request sent to a given peer.
 func (c *PeerClient) Request(req *rpc.Request) (proto.Message, error) {
+	return c.RequestWithContext(context.Background(), req)
+}
+
+// RequestWithContext requests for a response for a request sent to a given peer, giving
+// up early should the context be cancelled.
+func (c *PeerClient) RequestWithContext(ctx context.Context, req *rpc.Request) (proto.Message, error) {
 	signed, err := c.Network.PrepareMessage(req.Message)
 	if err != nil {
 		return nil, err
@@ -154,26 +289,28 @@ func (c *PeerClient) Request(req *rpc.Request) (proto.Message, error) {
 
 	signed.RequestNonce = atomic.AddUint64(&c.RequestNonce, 1)
 
-	err = c.Network.Write(c.Address, signed)
-	if err != nil {
-		return nil, err
-	}
-
 	// Start tracking the request.
 	channel := make(chan proto.Message, 1)
 	c.Requests.Store(signed.RequestNonce, channel)
 
-	// Stop tracking the request.
-	defer close(channel)
+	// Stop tracking the request. The channel is left open, as a reply may be in the
+	// midst of being delivered to it.
 	defer c.Requests.Delete(signed.RequestNonce)
 
+	err = c.Network.Write(c.Address(), signed)
+	if err != nil {
+		return nil, err
+	}
+
 	select {
 	case res := <-channel:
 		return res, nil
-	case <-time.After(req.Timeout):
+	case <-c.Network.clock().After(req.Timeout):
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 
-	return nil, errors.New("request timed out")
+	return nil, errors.Wrapf(ErrRequestTimeout, "request to %s", c.Address())
 }
 
 // Reply is equivalent to Write() with an appended nonce to signal a reply.
@@ -186,7 +323,7 @@ func (c *PeerClient) Reply(nonce uint64, message proto.Message) error {
 	// Set the nonce.
 	signed.RequestNonce = nonce
 
-	err = c.Network.Write(c.Address, signed)
+	err = c.Network.Write(c.Address(), signed)
 	if err != nil {
 		return err
 	}
@@ -219,17 +356,17 @@ func (c *PeerClient) Read(out []byte) (int, error) {
 		c.stream.Unlock()
 
 		if closed {
-			return n, errors.New("closed")
+			return n, ErrSessionClosed
 		}
 
-		if !readDeadline.IsZero() && time.Now().After(readDeadline) {
-			return n, errors.New("read deadline exceeded")
+		if !readDeadline.IsZero() && c.Network.clock().Now().After(readDeadline) {
+			return n, errors.Wrap(ErrDeadlineExceeded, "read")
 		}
 
 		if n == 0 {
 			select {
 			case <-c.stream.buffered:
-			case <-time.After(1 * time.Second):
+			case <-c.Network.clock().After(1 * time.Second):
 			}
 		} else {
 			return n, nil
@@ -240,14 +377,34 @@ func (c *PeerClient) Read(out []byte) (int, error) {
 // Write implements net.Conn and sends packets of bytes over a stream.
 func (c *PeerClient) Write(data []byte) (int, error) {
 	c.stream.Lock()
+	closed := c.stream.closed
 	writeDeadline := c.stream.writeDeadline
+	compression := c.stream.compression
 	c.stream.Unlock()
 
-	if !writeDeadline.IsZero() && time.Now().After(writeDeadline) {
-		return 0, errors.New("write deadline exceeded")
+	if closed {
+		return 0, ErrSessionClosed
+	}
+
+	if !writeDeadline.IsZero() && c.Network.clock().Now().After(writeDeadline) {
+		return 0, errors.Wrap(ErrDeadlineExceeded, "write")
+	}
+
+	packet := &protobuf.Bytes{Data: data}
+
+	// Only send compressed data should it actually be smaller.
+	if compression != protobuf.Compression_COMPRESSION_NONE {
+		compressed, err := compress(compression, data)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(compressed) < len(data) {
+			packet = &protobuf.Bytes{Data: compressed, Compression: compression}
+		}
 	}
 
-	err := c.Tell(&protobuf.Bytes{Data: data})
+	_, err := c.Tell(packet)
 	if err != nil {
 		return 0, err
 	}
@@ -265,7 +422,7 @@ func (c *PeerClient) LocalAddr() net.Addr {
 
 // RemoteAddr implements net.Conn.
 func (c *PeerClient) RemoteAddr() net.Addr {
-	addr, err := ParseAddress(c.Address)
+	addr, err := ParseAddress(c.Address())
 	if err != nil {
 		panic(err) // should never happen
 	}
@@ -301,7 +458,7 @@ func (c *PeerClient) SetWriteDeadline(t time.Time) error {
 func (c *PeerClient) IncomingReady() bool {
 	select {
 	case <-c.incomingReady:
-	case <-time.After(1 * time.Second):
+	case <-c.Network.clock().After(1 * time.Second):
 		return false
 	}
 
@@ -312,7 +469,7 @@ func (c *PeerClient) IncomingReady() bool {
 func (c *PeerClient) OutgoingReady() bool {
 	select {
 	case <-c.outgoingReady:
-	case <-time.After(1 * time.Second):
+	case <-c.Network.clock().After(1 * time.Second):
 		return false
 	}
 
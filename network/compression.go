@@ -0,0 +1,155 @@
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// supportedCompressions are the codecs we may decompress, in order of preference.
+var supportedCompressions = []protobuf.Compression{protobuf.Compression_COMPRESSION_DEFLATE}
+
+func supportsCompression(codec protobuf.Compression) bool {
+	for _, supported := range supportedCompressions {
+		if supported == codec {
+			return true
+		}
+	}
+	return false
+}
+
+// compress compresses data with a codec.
+func compress(codec protobuf.Compression, data []byte) ([]byte, error) {
+	switch codec {
+	case protobuf.Compression_COMPRESSION_NONE:
+		return data, nil
+	case protobuf.Compression_COMPRESSION_DEFLATE:
+		var buf bytes.Buffer
+
+		writer, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	return nil, errors.Errorf("unsupported compression %s", codec)
+}
+
+// decompress decompresses data compressed with a codec, failing should it decompress
+// to more than limit bytes, such that peers may not send us decompression bombs.
+func decompress(codec protobuf.Compression, data []byte, limit uint64) ([]byte, error) {
+	switch codec {
+	case protobuf.Compression_COMPRESSION_NONE:
+		return data, nil
+	case protobuf.Compression_COMPRESSION_DEFLATE:
+		reader := flate.NewReader(bytes.NewReader(data))
+		defer reader.Close()
+
+		decompressed, err := ioutil.ReadAll(io.LimitReader(reader, int64(limit)+1))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress stream packet")
+		}
+
+		if uint64(len(decompressed)) > limit {
+			return nil, errors.Wrap(ErrMessageTooLarge, "decompressed stream packet")
+		}
+
+		return decompressed, nil
+	}
+
+	return nil, errors.Errorf("unsupported compression %s", codec)
+}
+
+// decompressPacket returns the data of a stream packet the peer sent, decompressed
+// with the codec negotiated for the peer's writes. Packets compressed otherwise, such as
+// by peers which never negotiated compression, are rejected. Packets decompress to at
+// most the network's message size limit.
+func (c *PeerClient) decompressPacket(packet *protobuf.Bytes) ([]byte, error) {
+	c.stream.Lock()
+	negotiated := c.stream.decompression
+	c.stream.Unlock()
+
+	if packet.Compression != protobuf.Compression_COMPRESSION_NONE && packet.Compression != negotiated {
+		return nil, errors.Errorf("stream packet is compressed with %s, which was not negotiated", packet.Compression)
+	}
+
+	return decompress(packet.Compression, packet.Data, c.Network.maxMessageSize())
+}
+
+// selectCompression selects the first of the codecs a peer supports which we also support.
+func selectCompression(codecs []protobuf.Compression) protobuf.Compression {
+	for _, codec := range codecs {
+		if codec != protobuf.Compression_COMPRESSION_NONE && supportsCompression(codec) {
+			return codec
+		}
+	}
+	return protobuf.Compression_COMPRESSION_NONE
+}
+
+// NegotiateCompression asks the peer to select one of a set of codecs to compress the
+// data we stream to it with, defaulting to all supported codecs. Writes to the stream
+// are compressed with the selected codec from then on. COMPRESSION_NONE is returned
+// should the peer support none of the codecs.
+func (c *PeerClient) NegotiateCompression(codecs ...protobuf.Compression) (protobuf.Compression, error) {
+	if len(codecs) == 0 {
+		codecs = supportedCompressions
+	}
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.StreamCompressionRequest{Supported: codecs})
+	request.SetTimeout(5 * time.Second)
+
+	response, err := c.Request(request)
+	if err != nil {
+		return protobuf.Compression_COMPRESSION_NONE, errors.Wrapf(err, "failed to negotiate compression with %s", c.Address())
+	}
+
+	selected := protobuf.Compression_COMPRESSION_NONE
+
+	if response, ok := response.(*protobuf.StreamCompressionResponse); ok && supportsCompression(response.Selected) {
+		selected = response.Selected
+	}
+
+	c.stream.Lock()
+	c.stream.compression = selected
+	c.stream.Unlock()
+
+	return selected, nil
+}
+
+// acceptCompression selects one of the codecs a peer supports to compress the data it
+// streams to us with, such that its stream packets compressed with the codec may be
+// decompressed from then on.
+func (c *PeerClient) acceptCompression(codecs []protobuf.Compression) protobuf.Compression {
+	selected := selectCompression(codecs)
+
+	c.stream.Lock()
+	c.stream.decompression = selected
+	c.stream.Unlock()
+
+	return selected
+}
+
+// Compression returns the codec writes to the stream are compressed with.
+func (c *PeerClient) Compression() protobuf.Compression {
+	c.stream.Lock()
+	defer c.stream.Unlock()
+
+	return c.stream.compression
+}
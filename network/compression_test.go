@@ -0,0 +1,111 @@
+package network_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestStreamCompression(t *testing.T) {
+	alice := buildNode(t, 13130, new(discovery.Plugin))
+	bob := buildNode(t, 13131, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	alice.Bootstrap(bob.Address)
+
+	time.Sleep(200 * time.Millisecond)
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selected, err := client.NegotiateCompression()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if selected != protobuf.Compression_COMPRESSION_DEFLATE || client.Compression() != selected {
+		t.Fatalf("expected deflate to be negotiated, got %s", selected)
+	}
+
+	value, exists := bob.Peers.Load(alice.Address)
+	if !exists {
+		t.Fatal("expected bob to be connected to alice")
+	}
+
+	payload := bytes.Repeat([]byte("snapshot "), 4096)
+
+	if _, err := client.Write(payload); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := value.(*network.PeerClient)
+	reader.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	received := make([]byte, len(payload))
+	if _, err := io.ReadFull(reader, received); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(received, payload) {
+		t.Fatal("expected decompressed stream data to match what was written")
+	}
+}
+
+func TestStreamCompressionUnnegotiated(t *testing.T) {
+	alice := buildNode(t, 13236, new(discovery.Plugin))
+	bob := buildNode(t, 13237, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	alice.Bootstrap(bob.Address)
+
+	time.Sleep(200 * time.Millisecond)
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var compressed bytes.Buffer
+
+	writer, _ := flate.NewWriter(&compressed, flate.DefaultCompression)
+	writer.Write(bytes.Repeat([]byte("bomb "), 4096))
+	writer.Close()
+
+	// Packets compressed without compression having been negotiated are dropped.
+	if _, err := client.Tell(&protobuf.Bytes{Data: compressed.Bytes(), Compression: protobuf.Compression_COMPRESSION_DEFLATE}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Write([]byte("plain")); err != nil {
+		t.Fatal(err)
+	}
+
+	value, exists := bob.Peers.Load(alice.Address)
+	if !exists {
+		t.Fatal("expected bob to be connected to alice")
+	}
+
+	reader := value.(*network.PeerClient)
+	reader.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	received := make([]byte, len("plain"))
+	if _, err := io.ReadFull(reader, received); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(received) != "plain" {
+		t.Fatalf("expected the unnegotiated compressed packet to be dropped, read %q", received)
+	}
+}
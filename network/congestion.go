@@ -0,0 +1,168 @@
+package network
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultCongestionWindow is the period over which messages dropped sending to a
+	// peer are counted as recent.
+	DefaultCongestionWindow = 10 * time.Second
+
+	// DefaultCongestionQueueDepth is the number of messages queued to a peer at which
+	// the peer is considered congested.
+	DefaultCongestionQueueDepth = 64
+)
+
+// CongestionState is a snapshot of how congested sending to a peer is, which
+// applications may use to adapt the rate at which they gossip to the peer.
+type CongestionState struct {
+	// QueueDepth is the number of messages queued or being sent to the peer.
+	QueueDepth int `json:"queue_depth"`
+
+	// OpenStreams is the number of streams open on the session to the peer.
+	OpenStreams int `json:"open_streams"`
+
+	// WindowUtilization is the estimated fraction of the peer's session receive
+	// window occupied by messages being sent to it, from 0 to 1.
+	WindowUtilization float64 `json:"window_utilization"`
+
+	// Drops is the number of messages which failed to be sent to the peer.
+	Drops uint64 `json:"drops"`
+
+	// RecentDrops is the number of messages which failed to be sent to the peer within
+	// the last DefaultCongestionWindow.
+	RecentDrops uint64 `json:"recent_drops"`
+
+	// Congested is true should the queue to the peer be at least
+	// DefaultCongestionQueueDepth deep, or should messages have recently been dropped.
+	Congested bool `json:"congested"`
+}
+
+// receiveWindow is the size of the buffer sessions receive into, which is the same
+// for all peers.
+var receiveWindow = float64(muxConfig().MaxReceiveBuffer)
+
+// congestion tracks the messages queued to a peer and those dropped.
+type congestion struct {
+	queued   int64
+	inflight int64 // bytes
+	drops    uint64
+
+	mutex       sync.Mutex
+	recentStart time.Time
+	recent      uint64
+	congested   bool
+}
+
+// recentDrops returns the drops counted within the window which started last. The
+// congestion must be locked.
+func (c *congestion) recentDrops(now time.Time) uint64 {
+	if now.Sub(c.recentStart) >= DefaultCongestionWindow {
+		return 0
+	}
+	return c.recent
+}
+
+// state snapshots the congestion of sending over a session.
+func (c *congestion) state(now time.Time, state *ConnState) CongestionState {
+	congestion := CongestionState{
+		QueueDepth: int(atomic.LoadInt64(&c.queued)),
+		Drops:      atomic.LoadUint64(&c.drops),
+	}
+
+	if state.session != nil {
+		congestion.OpenStreams = state.session.NumStreams()
+	}
+
+	if utilization := float64(atomic.LoadInt64(&c.inflight)) / receiveWindow; utilization < 1 {
+		congestion.WindowUtilization = utilization
+	} else {
+		congestion.WindowUtilization = 1
+	}
+
+	c.mutex.Lock()
+	congestion.RecentDrops = c.recentDrops(now)
+	c.mutex.Unlock()
+
+	congestion.Congested = congestion.QueueDepth >= DefaultCongestionQueueDepth || congestion.RecentDrops > 0
+
+	return congestion
+}
+
+// queue records a message of a size being queued.
+func (c *congestion) queue(size int64) {
+	atomic.AddInt64(&c.queued, 1)
+	atomic.AddInt64(&c.inflight, size)
+}
+
+// queueCongestion records a message of a size being queued over a session to an
+// address.
+func (n *Network) queueCongestion(address string, state *ConnState, size int64) {
+	state.congestion.queue(size)
+	n.notifyCongestion(address, state, false)
+}
+
+// settleCongestion records a message queued over a session to an address being sent,
+// or being dropped should err be non-nil.
+func (n *Network) settleCongestion(address string, state *ConnState, size int64, err error) {
+	c := &state.congestion
+
+	atomic.AddInt64(&c.queued, -1)
+	atomic.AddInt64(&c.inflight, -size)
+
+	if err != nil {
+		now := n.clock().Now()
+
+		atomic.AddUint64(&c.drops, 1)
+
+		c.mutex.Lock()
+		if now.Sub(c.recentStart) >= DefaultCongestionWindow {
+			c.recentStart, c.recent = now, 0
+		}
+		c.recent++
+		c.mutex.Unlock()
+	}
+
+	n.notifyCongestion(address, state, err != nil)
+}
+
+// notifyCongestion notifies plugins should a message to an address have been dropped,
+// or should the address have become or ceased to be congested.
+func (n *Network) notifyCongestion(address string, state *ConnState, dropped bool) {
+	c := &state.congestion
+
+	congestion := c.state(n.clock().Now(), state)
+
+	c.mutex.Lock()
+	changed := congestion.Congested != c.congested
+	c.congested = congestion.Congested
+	c.mutex.Unlock()
+
+	if !dropped && !changed {
+		return
+	}
+
+	n.Plugins.Each(func(plugin PluginInterface) {
+		plugin.Congestion(address, congestion)
+	})
+}
+
+// CongestionState returns how congested sending to a peer by its address is. The
+// second returning parameter is false should there be no connection to the peer.
+func (n *Network) CongestionState(address string) (CongestionState, bool) {
+	state, exists := n.Connections.Load(address)
+	if !exists {
+		return CongestionState{}, false
+	}
+
+	return state.(*ConnState).congestion.state(n.clock().Now(), state.(*ConnState)), true
+}
+
+// CongestionState returns how congested sending to the peer is.
+func (c *PeerClient) CongestionState() CongestionState {
+	state, _ := c.Network.CongestionState(c.Address())
+	return state
+}
@@ -0,0 +1,59 @@
+package network
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type congestionPlugin struct {
+	*Plugin
+	states []CongestionState
+}
+
+func (p *congestionPlugin) Congestion(address string, state CongestionState) {
+	p.states = append(p.states, state)
+}
+
+func TestCongestionState(t *testing.T) {
+	plugin := new(congestionPlugin)
+
+	n := &Network{Plugins: NewPluginList(), Connections: new(sync.Map)}
+	n.Plugins.Put(0, plugin)
+
+	state := new(ConnState)
+	n.Connections.Store("tcp://127.0.0.1:3000", state)
+
+	for i := 0; i < DefaultCongestionQueueDepth; i++ {
+		n.queueCongestion("tcp://127.0.0.1:3000", state, 1024)
+	}
+
+	congestion, exists := n.CongestionState("tcp://127.0.0.1:3000")
+	if !exists {
+		t.Fatal("expected congestion state of a connected peer")
+	}
+
+	if congestion.QueueDepth != DefaultCongestionQueueDepth || !congestion.Congested || congestion.WindowUtilization <= 0 {
+		t.Fatalf("expected peer to be congested, got %+v", congestion)
+	}
+
+	if len(plugin.states) != 1 || !plugin.states[0].Congested {
+		t.Fatalf("expected plugins to be notified of congestion once, got %+v", plugin.states)
+	}
+
+	// Settling messages out of a congested queue notifies plugins once.
+	n.settleCongestion("tcp://127.0.0.1:3000", state, 1024, nil)
+	n.settleCongestion("tcp://127.0.0.1:3000", state, 1024, nil)
+
+	if len(plugin.states) != 2 || plugin.states[1].Congested {
+		t.Fatalf("expected plugins to be notified of congestion easing once, got %+v", plugin.states)
+	}
+
+	// Dropped messages always notify plugins, and mark the peer as congested.
+	n.settleCongestion("tcp://127.0.0.1:3000", state, 1024, errors.New("dropped"))
+
+	if len(plugin.states) != 3 || !plugin.states[2].Congested || plugin.states[2].Drops != 1 || plugin.states[2].RecentDrops != 1 {
+		t.Fatalf("expected plugins to be notified of the drop, got %+v", plugin.states)
+	}
+}
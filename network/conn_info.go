@@ -0,0 +1,81 @@
+package network
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+// ConnInfo describes the connection a peer was observed connecting to us over.
+type ConnInfo struct {
+	LocalAddr  net.Addr
+	RemoteAddr net.Addr
+
+	// Transport is the protocol scheme the connection was made over, such as tcp.
+	Transport string
+
+	// Cipher is the name of the cipher suite the connection is encrypted with. Empty
+	// should the connection not be encrypted by its transport.
+	Cipher string
+
+	ConnectedAt time.Time
+}
+
+// newConnInfo describes a connection accepted from a peer at an address.
+func newConnInfo(conn net.Conn, address string, connectedAt time.Time) ConnInfo {
+	info := ConnInfo{
+		LocalAddr:   conn.LocalAddr(),
+		RemoteAddr:  conn.RemoteAddr(),
+		ConnectedAt: connectedAt,
+	}
+
+	if addr, err := ParseAddress(address); err == nil {
+		info.Transport = addr.Protocol
+	}
+
+	if conn, ok := conn.(interface{ ConnectionState() tls.ConnectionState }); ok {
+		info.Cipher = tls.CipherSuiteName(conn.ConnectionState().CipherSuite)
+	}
+
+	return info
+}
+
+// ConnInfo returns the connection the peer was last observed connecting to us over.
+// The second returning parameter is false should it never have connected to us.
+func (c *PeerClient) ConnInfo() (ConnInfo, bool) {
+	info, ok := c.connInfo.Load().(ConnInfo)
+	return info, ok
+}
+
+// SocketLocalAddr returns our end of the connection the peer last connected to us
+// over. Unlike LocalAddr, it is the address of the underlying socket.
+func (c *PeerClient) SocketLocalAddr() net.Addr {
+	info, _ := c.ConnInfo()
+	return info.LocalAddr
+}
+
+// SocketRemoteAddr returns the peer's end of the connection it last connected to us
+// over. Unlike RemoteAddr, it is the address of the underlying socket.
+func (c *PeerClient) SocketRemoteAddr() net.Addr {
+	info, _ := c.ConnInfo()
+	return info.RemoteAddr
+}
+
+// Transport returns the protocol scheme the peer last connected to us over.
+func (c *PeerClient) Transport() string {
+	info, _ := c.ConnInfo()
+	return info.Transport
+}
+
+// Cipher returns the name of the cipher suite the peer's connection is encrypted
+// with. Empty should it not be encrypted.
+func (c *PeerClient) Cipher() string {
+	info, _ := c.ConnInfo()
+	return info.Cipher
+}
+
+// ConnectedAt returns when the peer last connected to us. Zero should it never have.
+func (c *PeerClient) ConnectedAt() time.Time {
+	info, _ := c.ConnInfo()
+	return info.ConnectedAt
+}
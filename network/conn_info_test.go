@@ -0,0 +1,38 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestConnInfo(t *testing.T) {
+	client := &PeerClient{}
+
+	if _, ok := client.ConnInfo(); ok {
+		t.Fatal("expected peer which never connected to have no connection info")
+	}
+
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+
+	connectedAt := time.Unix(1000, 0)
+	client.connInfo.Store(newConnInfo(local, "tcp://127.0.0.1:3000", connectedAt))
+
+	if client.Transport() != "tcp" {
+		t.Fatalf("expected transport tcp, got %q", client.Transport())
+	}
+
+	if client.Cipher() != "" {
+		t.Fatalf("expected unencrypted connection to have no cipher, got %q", client.Cipher())
+	}
+
+	if client.SocketLocalAddr() != local.LocalAddr() || client.SocketRemoteAddr() != local.RemoteAddr() {
+		t.Fatal("expected socket addresses of the connection")
+	}
+
+	if !client.ConnectedAt().Equal(connectedAt) {
+		t.Fatalf("expected connection to start at %s, got %s", connectedAt, client.ConnectedAt())
+	}
+}
@@ -0,0 +1,299 @@
+// Package crawler walks a network from its seed nodes by asking each peer reached for
+// its neighbors, collecting the reachable peers, the versions they speak and the
+// latencies to them into a snapshot, such as to build network explorers with.
+package crawler
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultConcurrency is the number of peers crawled at once by default.
+	DefaultConcurrency = 16
+
+	// DefaultTimeout is how long each peer is waited on by default.
+	DefaultTimeout = 3 * time.Second
+)
+
+// Options configure a crawl.
+type Options struct {
+	// Seeds are the addresses the crawl starts from.
+	Seeds []string
+
+	// Concurrency is the number of peers crawled at once. DefaultConcurrency if zero.
+	Concurrency int
+
+	// Timeout is how long each peer is waited on. DefaultTimeout if zero.
+	Timeout time.Duration
+
+	// MaxPeers is the number of peers crawled at most. Unlimited if zero.
+	MaxPeers int
+}
+
+// Peer is what was learnt of a peer while crawling it.
+type Peer struct {
+	Address string `json:"address"`
+
+	// PublicKey is the public key of the peer in its canonical base58 encoding. Empty
+	// if unreachable.
+	PublicKey string `json:"public_key,omitempty"`
+
+	Reachable bool `json:"reachable"`
+
+	// EnvelopeVersion is the highest envelope version the peer supports, and
+	// Capabilities the services it advertised.
+	EnvelopeVersion uint32 `json:"envelope_version,omitempty"`
+	Capabilities    uint64 `json:"capabilities,omitempty"`
+
+	// RTT is the round-trip time measured to the peer.
+	RTT time.Duration `json:"rtt,omitempty"`
+
+	// Summary is the summary the peer piggybacked onto its pong. Nil if it shares none.
+	Summary *network.Summary `json:"summary,omitempty"`
+
+	// ConfigFingerprint is the hex-encoded config fingerprint the peer piggybacked onto
+	// its pong. Empty if it shares none.
+	ConfigFingerprint string `json:"config_fingerprint,omitempty"`
+
+	// Neighbors are the addresses of the peers closest to the peer.
+	Neighbors []string `json:"neighbors,omitempty"`
+
+	// Error describes why the peer could not be crawled. Empty if crawled.
+	Error string `json:"error,omitempty"`
+}
+
+// Snapshot is the outcome of a crawl.
+type Snapshot struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+
+	Seeds []string `json:"seeds"`
+
+	// Peers are all peers crawled, sorted by address.
+	Peers []Peer `json:"peers"`
+}
+
+// Reachable returns the number of peers crawled which were reachable.
+func (s *Snapshot) Reachable() (count int) {
+	for _, peer := range s.Peers {
+		if peer.Reachable {
+			count++
+		}
+	}
+	return
+}
+
+// ConfigFingerprints counts the reachable peers crawled by their config fingerprints,
+// such that more than one fingerprint reveals config drift.
+func (s *Snapshot) ConfigFingerprints() map[string]int {
+	fingerprints := make(map[string]int)
+	for _, peer := range s.Peers {
+		if peer.Reachable && len(peer.ConfigFingerprint) > 0 {
+			fingerprints[peer.ConfigFingerprint]++
+		}
+	}
+	return fingerprints
+}
+
+// WriteJSON writes the snapshot as indented JSON.
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(s)
+}
+
+type crawl struct {
+	net     *network.Network
+	options Options
+
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	mutex   sync.Mutex
+	visited map[string]struct{}
+	peers   []Peer
+}
+
+// Crawl walks the network from the seeds in options by asking each peer reached for
+// the peers closest to itself, until no new peers are learnt of or ctx is done. Peers
+// are only disconnected from once crawled should net not have been connected to them.
+func Crawl(ctx context.Context, net *network.Network, options Options) (*Snapshot, error) {
+	if len(options.Seeds) == 0 {
+		return nil, errors.New("no seeds to crawl from")
+	}
+
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultConcurrency
+	}
+
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+
+	c := &crawl{
+		net:     net,
+		options: options,
+		slots:   make(chan struct{}, options.Concurrency),
+		visited: make(map[string]struct{}),
+	}
+
+	snapshot := &Snapshot{StartedAt: clock.Or(net.Clock).Now(), Seeds: options.Seeds}
+
+	for _, seed := range options.Seeds {
+		c.enqueue(ctx, seed)
+	}
+
+	c.wg.Wait()
+
+	sort.Slice(c.peers, func(i, j int) bool {
+		return c.peers[i].Address < c.peers[j].Address
+	})
+
+	snapshot.Peers = c.peers
+	snapshot.FinishedAt = clock.Or(net.Clock).Now()
+
+	return snapshot, nil
+}
+
+// enqueue crawls an address in the background, should it not have been crawled yet.
+func (c *crawl) enqueue(ctx context.Context, address string) {
+	address, err := network.ToUnifiedAddress(address)
+	if err != nil || address == c.net.Address {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, visited := c.visited[address]; visited {
+		return
+	}
+
+	if c.options.MaxPeers > 0 && len(c.visited) >= c.options.MaxPeers {
+		return
+	}
+
+	c.visited[address] = struct{}{}
+	c.wg.Add(1)
+
+	go func() {
+		defer c.wg.Done()
+
+		select {
+		case c.slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		info := c.visit(ctx, address)
+		<-c.slots
+
+		c.mutex.Lock()
+		c.peers = append(c.peers, info)
+		c.mutex.Unlock()
+
+		for _, neighbor := range info.Neighbors {
+			c.enqueue(ctx, neighbor)
+		}
+	}()
+}
+
+// visit crawls a single peer.
+func (c *crawl) visit(ctx context.Context, address string) Peer {
+	info := Peer{Address: address}
+
+	_, connected := c.net.Peers.Load(address)
+
+	client, err := c.net.Client(address)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+
+	if !connected {
+		defer client.Close()
+	}
+
+	// Ask for the peer's summary and config fingerprint, which it piggybacks onto its
+	// pong.
+	client.Tell(c.net.NewPing())
+
+	pingCtx, cancel := context.WithTimeout(ctx, c.options.Timeout)
+	sample, err := c.net.Ping(pingCtx, address)
+	cancel()
+
+	if err != nil {
+		info.Error = errors.Wrap(err, "peer did not reply to echo").Error()
+		return info
+	}
+
+	if client.ID() == nil {
+		info.Error = "peer did not identify itself"
+		return info
+	}
+
+	info.Reachable = true
+	info.PublicKey = client.ID().String()
+	info.EnvelopeVersion = client.EnvelopeVersion()
+	info.Capabilities = uint64(client.Capabilities())
+	info.RTT = sample.RTT
+
+	neighbors, err := lookupNeighbors(client, *client.ID(), c.options.Timeout)
+	if err != nil {
+		info.Error = err.Error()
+	}
+
+	for _, neighbor := range neighbors {
+		info.Neighbors = append(info.Neighbors, neighbor.Address)
+	}
+
+	if summary, ok := client.Summary(); ok {
+		info.Summary = &summary
+	}
+
+	if fingerprint := client.ConfigFingerprint(); fingerprint != nil {
+		info.ConfigFingerprint = hex.EncodeToString(fingerprint)
+	}
+
+	return info
+}
+
+// lookupNeighbors asks a peer for the peers closest to itself.
+func lookupNeighbors(client *network.PeerClient, id peer.ID, timeout time.Duration) ([]peer.ID, error) {
+	target := protobuf.ID(id)
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.LookupNodeRequest{Target: &target})
+	request.SetTimeout(timeout)
+
+	response, err := client.Request(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "peer did not answer neighbor lookup")
+	}
+
+	var peers []peer.ID
+
+	if response, ok := response.(*protobuf.LookupNodeResponse); ok {
+		for _, neighbor := range response.Peers {
+			if !id.Equals(peer.ID(*neighbor)) {
+				peers = append(peers, peer.ID(*neighbor))
+			}
+		}
+	}
+
+	return peers, nil
+}
@@ -0,0 +1,98 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func buildNode(t *testing.T, port uint16, plugins ...network.PluginInterface) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+
+	for _, plugin := range plugins {
+		builder.AddPlugin(plugin)
+	}
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestCrawl(t *testing.T) {
+	alicePlugin, bobPlugin, carolPlugin := new(discovery.Plugin), new(discovery.Plugin), new(discovery.Plugin)
+
+	alice := buildNode(t, 350, alicePlugin)
+	bob := buildNode(t, 351, bobPlugin)
+	carol := buildNode(t, 352, carolPlugin)
+	crawler := buildNode(t, 353)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+	defer crawler.Close()
+
+	// Alice is only linked to carol through bob.
+	alice.Bootstrap(bob.Address)
+	carol.Bootstrap(bob.Address)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !alicePlugin.Routes.PeerExists(bob.ID) || !bobPlugin.Routes.PeerExists(alice.ID) || !bobPlugin.Routes.PeerExists(carol.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for routing tables to be populated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	snapshot, err := Crawl(context.Background(), crawler, Options{Seeds: []string{alice.Address}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reachable := snapshot.Reachable(); reachable != 3 {
+		t.Fatalf("expected 3 reachable peers, got %d: %+v", reachable, snapshot.Peers)
+	}
+
+	for _, peer := range snapshot.Peers {
+		if peer.EnvelopeVersion != network.EnvelopeVersion || peer.RTT <= 0 {
+			t.Fatalf("expected the version and latency of %s to be collected, got %+v", peer.Address, peer)
+		}
+	}
+
+	if fingerprints := snapshot.ConfigFingerprints(); len(fingerprints) != 1 {
+		t.Fatalf("expected peers configured alike to share a config fingerprint, got %v", fingerprints)
+	}
+
+	if _, connected := crawler.Peers.Load(alice.Address); connected {
+		t.Fatal("expected peers to be disconnected from once crawled")
+	}
+
+	var buffer bytes.Buffer
+	if err := snapshot.WriteJSON(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Snapshot
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Peers) != len(snapshot.Peers) {
+		t.Fatalf("expected %d peers in the JSON snapshot, got %d", len(snapshot.Peers), len(decoded.Peers))
+	}
+}
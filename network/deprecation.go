@@ -0,0 +1,202 @@
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// Deprecation marks a message type as deprecated after a version of the application.
+// Deprecations are advertised to peers on pings and pongs, and peers still sending
+// deprecated types are counted, such that operators may plan network upgrades.
+type Deprecation struct {
+	// Type is the fully-qualified protobuf name of the message type, such as protobuf.Ping.
+	Type string `json:"type"`
+
+	// After is the version of the application after which the type is deprecated.
+	After string `json:"after"`
+
+	// Reason explains the deprecation, such as naming the type replacing it.
+	Reason string `json:"reason,omitempty"`
+}
+
+// DeprecationUsage counts the messages of a deprecated type received from peers.
+type DeprecationUsage struct {
+	Deprecation
+
+	// Messages is the number of messages of the type received.
+	Messages uint64 `json:"messages"`
+
+	// Peers are when each connected peer still sending the type last sent it, by their
+	// addresses. Peers are forgotten once they disconnect, such that peers churning do
+	// not pile up.
+	Peers map[string]time.Time `json:"peers"`
+}
+
+// deprecations counts the messages of deprecated types received from peers.
+type deprecations struct {
+	sync.Mutex
+	usage map[string]*DeprecationUsage
+}
+
+// deprecation returns the deprecation of a message type by its name, should it be
+// deprecated.
+func (n *Network) deprecation(name string) (Deprecation, bool) {
+	for _, deprecation := range n.Deprecations {
+		if deprecation.Type == name {
+			return deprecation, true
+		}
+	}
+	return Deprecation{}, false
+}
+
+// observeDeprecated counts a message received from a peer should its type be deprecated.
+func (n *Network) observeDeprecated(client *PeerClient, message proto.Message) {
+	if len(n.Deprecations) == 0 {
+		return
+	}
+
+	deprecation, deprecated := n.deprecation(proto.MessageName(message))
+	if !deprecated {
+		return
+	}
+
+	n.deprecated.Lock()
+	defer n.deprecated.Unlock()
+
+	if n.deprecated.usage == nil {
+		n.deprecated.usage = make(map[string]*DeprecationUsage)
+	}
+
+	usage, exists := n.deprecated.usage[deprecation.Type]
+	if !exists {
+		usage = &DeprecationUsage{Deprecation: deprecation, Peers: make(map[string]time.Time)}
+		n.deprecated.usage[deprecation.Type] = usage
+	}
+
+	if _, seen := usage.Peers[client.Address()]; !seen {
+		glog.Infof("Peer %s still sends %s, which is deprecated after %s", client.Address(), deprecation.Type, deprecation.After)
+	}
+
+	usage.Messages++
+	usage.Peers[client.Address()] = n.clock().Now()
+}
+
+// forgetDeprecated forgets when a peer by its address last sent each deprecated type,
+// as it disconnected.
+func (n *Network) forgetDeprecated(address string) {
+	n.deprecated.Lock()
+	defer n.deprecated.Unlock()
+
+	for _, usage := range n.deprecated.usage {
+		delete(usage.Peers, address)
+	}
+}
+
+// migrateDeprecated moves when a peer last sent each deprecated type from an address to
+// the address it has moved to.
+func (n *Network) migrateDeprecated(from string, to string) {
+	n.deprecated.Lock()
+	defer n.deprecated.Unlock()
+
+	for _, usage := range n.deprecated.usage {
+		if last, exists := usage.Peers[from]; exists {
+			delete(usage.Peers, from)
+			usage.Peers[to] = last
+		}
+	}
+}
+
+// DeprecationUsage returns how much each deprecated message type is still being sent
+// by peers, sorted by the types' names. Types no peer sent are included with no usage.
+func (n *Network) DeprecationUsage() []DeprecationUsage {
+	n.deprecated.Lock()
+	defer n.deprecated.Unlock()
+
+	usages := make([]DeprecationUsage, 0, len(n.Deprecations))
+
+	for _, deprecation := range n.Deprecations {
+		usage := DeprecationUsage{Deprecation: deprecation, Peers: make(map[string]time.Time)}
+
+		if observed, exists := n.deprecated.usage[deprecation.Type]; exists {
+			usage.Messages = observed.Messages
+			for address, last := range observed.Peers {
+				usage.Peers[address] = last
+			}
+		}
+
+		usages = append(usages, usage)
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Type < usages[j].Type
+	})
+
+	return usages
+}
+
+// advertisedDeprecations returns the network's deprecations to piggyback onto pings
+// and pongs. Nil if the network deprecated no types.
+func (n *Network) advertisedDeprecations() []*protobuf.Deprecation {
+	if len(n.Deprecations) == 0 {
+		return nil
+	}
+
+	advertised := make([]*protobuf.Deprecation, 0, len(n.Deprecations))
+	for _, deprecation := range n.Deprecations {
+		advertised = append(advertised, &protobuf.Deprecation{
+			Type:   deprecation.Type,
+			After:  deprecation.After,
+			Reason: deprecation.Reason,
+		})
+	}
+
+	return advertised
+}
+
+// observeDeprecations records the deprecations the peer piggybacked onto a ping or pong.
+func (c *PeerClient) observeDeprecations(advertised []*protobuf.Deprecation) {
+	if len(advertised) == 0 {
+		return
+	}
+
+	deprecations := make([]Deprecation, 0, len(advertised))
+	for _, deprecation := range advertised {
+		deprecations = append(deprecations, Deprecation{
+			Type:   deprecation.Type,
+			After:  deprecation.After,
+			Reason: deprecation.Reason,
+		})
+	}
+
+	c.deprecations.Store(deprecations)
+}
+
+// Deprecations returns the message types the peer last advertised as deprecated on its
+// pings or pongs. Nil if the peer advertised none.
+func (c *PeerClient) Deprecations() []Deprecation {
+	deprecations, _ := c.deprecations.Load().([]Deprecation)
+	return deprecations
+}
+
+// NeighborDeprecations returns the deprecations advertised by all connected peers which
+// advertised any, by their addresses.
+func (n *Network) NeighborDeprecations() map[string][]Deprecation {
+	deprecations := make(map[string][]Deprecation)
+
+	n.Peers.Range(func(key, value interface{}) bool {
+		client := value.(*PeerClient)
+
+		if advertised := client.Deprecations(); advertised != nil {
+			deprecations[client.Address()] = advertised
+		}
+
+		return true
+	})
+
+	return deprecations
+}
@@ -0,0 +1,72 @@
+package network_test
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestDeprecations(t *testing.T) {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", 364))
+	builder.DeprecateMessageType(new(protobuf.Ping), "1.4", "superseded by echoes")
+	builder.AddPlugin(new(discovery.Plugin))
+
+	alice, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := alice.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer alice.Close()
+
+	bob := buildTicketingNode(t, 365)
+	defer bob.Close()
+
+	if usage := alice.DeprecationUsage(); len(usage) != 1 || usage[0].Type != "protobuf.Ping" || usage[0].Messages != 0 {
+		t.Fatalf("expected protobuf.Ping to be deprecated and unused, got %+v", usage)
+	}
+
+	if err := bob.Bootstrap(alice.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, "bob to be counted sending pings", func() bool {
+		_, counted := alice.DeprecationUsage()[0].Peers[bob.Address]
+		return counted
+	})
+
+	waitFor(t, "alice's deprecations to be advertised", func() bool {
+		return len(bob.NeighborDeprecations()[alice.Address]) > 0
+	})
+
+	advertised := bob.NeighborDeprecations()[alice.Address]
+	if len(advertised) != 1 || advertised[0].Type != "protobuf.Ping" || advertised[0].After != "1.4" {
+		t.Fatalf("expected alice to advertise protobuf.Ping deprecated after 1.4, got %+v", advertised)
+	}
+
+	if deprecations := alice.NeighborDeprecations(); len(deprecations) != 0 {
+		t.Fatalf("expected bob to advertise no deprecations, got %+v", deprecations)
+	}
+
+	client, connected := alice.Peers.Load(bob.Address)
+	if !connected {
+		t.Fatal("expected bob to be connected to alice")
+	}
+	client.(*network.PeerClient).Close()
+
+	waitFor(t, "bob to be forgotten once disconnected", func() bool {
+		_, counted := alice.DeprecationUsage()[0].Peers[bob.Address]
+		return !counted
+	})
+
+	if usage := alice.DeprecationUsage()[0]; usage.Messages == 0 {
+		t.Fatalf("expected messages bob sent to remain counted, got %+v", usage)
+	}
+}
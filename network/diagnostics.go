@@ -0,0 +1,93 @@
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Diagnostics is a dump of the goroutines and resources a network holds, for leaks to
+// be spotted.
+type Diagnostics struct {
+	// Goroutines counts the goroutines spawned by the network which are running, by
+	// their labels.
+	Goroutines map[string]int
+
+	Peers       int
+	Connections int
+
+	// Streams is the number of streams open over the sessions dialed to peers.
+	Streams int
+
+	// Resources is the usage of resources reserved for reading and handling messages.
+	Resources ResourceUsage
+
+	Taps int
+}
+
+// Diagnostics returns a dump of the goroutines and resources the network holds.
+func (n *Network) Diagnostics() Diagnostics {
+	diagnostics := Diagnostics{
+		Goroutines: n.goroutines.snapshot(),
+		Resources:  n.Resources.Usage(),
+	}
+
+	if n.Peers != nil {
+		n.Peers.Range(func(key, value interface{}) bool {
+			diagnostics.Peers++
+			return true
+		})
+	}
+
+	if n.Connections != nil {
+		n.Connections.Range(func(key, value interface{}) bool {
+			diagnostics.Connections++
+
+			if state, ok := value.(*ConnState); ok && state.session != nil {
+				diagnostics.Streams += state.session.NumStreams()
+			}
+
+			return true
+		})
+	}
+
+	n.tapsMutex.RLock()
+	diagnostics.Taps = len(n.taps)
+	n.tapsMutex.RUnlock()
+
+	return diagnostics
+}
+
+// TotalGoroutines returns the number of goroutines spawned by the network which are running.
+func (d Diagnostics) TotalGoroutines() int {
+	total := 0
+	for _, count := range d.Goroutines {
+		total += count
+	}
+	return total
+}
+
+// String renders the diagnostics, with goroutines sorted by their labels.
+func (d Diagnostics) String() string {
+	var buffer bytes.Buffer
+
+	fmt.Fprintf(&buffer, "goroutines: %d\n", d.TotalGoroutines())
+
+	labels := make([]string, 0, len(d.Goroutines))
+	for label := range d.Goroutines {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		fmt.Fprintf(&buffer, "  %s: %d\n", label, d.Goroutines[label])
+	}
+
+	fmt.Fprintf(&buffer, "peers: %d\n", d.Peers)
+	fmt.Fprintf(&buffer, "connections: %d\n", d.Connections)
+	fmt.Fprintf(&buffer, "streams: %d\n", d.Streams)
+	fmt.Fprintf(&buffer, "resources: %d streams, %d buffered bytes, %d goroutines\n", d.Resources.Streams, d.Resources.BufferedBytes, d.Resources.Goroutines)
+	fmt.Fprintf(&buffer, "taps: %d\n", d.Taps)
+
+	return buffer.String()
+}
@@ -0,0 +1,130 @@
+package network
+
+import (
+	"crypto/rand"
+	"net"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+	"github.com/xtaci/smux"
+)
+
+// challengeNonceSize is the size of the nonce a peer dialed back to must sign.
+const challengeNonceSize = 32
+
+// ChallengeEnvelopeVersion is the lowest envelope version supported by peers which
+// answer identity challenges. Peers supporting only older versions, such as those
+// running upstream, are trusted to answer at their address once dialed back to, as
+// upstream does.
+const ChallengeEnvelopeVersion uint32 = 3
+
+// verifyDialBack challenges the node answering at a peer's claimed address, over the
+// session dialed back to it, to prove that it holds the peer's private key. Peers
+// hence may not claim the addresses of others. Peers which are not dialed back are
+// challenged over the session they dialed instead, which proves they hold their key
+// and belong to the network, though not that they answer at their address.
+func (n *Network) verifyDialBack(session *smux.Session, claimed peer.ID) error {
+	nonce := make([]byte, challengeNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Wrap(err, "failed to generate challenge nonce")
+	}
+
+	challenge, err := n.PrepareMessage(&protobuf.IdentityChallenge{Nonce: nonce, NetworkId: n.NetworkID})
+	if err != nil {
+		return err
+	}
+
+	stream, err := session.OpenStream()
+	if err != nil {
+		return errors.Wrapf(err, "failed to open challenge stream to %s", claimed.Address)
+	}
+	defer stream.Close()
+
+	if err := sendMessage(stream, challenge); err != nil {
+		return errors.Wrapf(err, "failed to challenge %s", claimed.Address)
+	}
+
+	msg, err := n.receiveMessage(stream)
+	if err != nil {
+		return errors.Wrapf(ErrIdentityUnverified, "no answer to challenge from %s: %v", claimed.Address, err)
+	}
+
+	var response protobuf.IdentityResponse
+	if err := ptypes.UnmarshalAny(msg.Message, &response); err != nil {
+		return errors.Wrapf(ErrIdentityUnverified, "invalid answer to challenge from %s: %v", claimed.Address, err)
+	}
+
+	if !crypto.Equal(msg.Sender.PublicKey, claimed.PublicKey) {
+		return errors.Wrapf(ErrIdentityUnverified, "node at %s is not peer %s", claimed.Address, claimed)
+	}
+
+	if !crypto.Equal(response.Nonce, nonce) {
+		return errors.Wrapf(ErrIdentityUnverified, "peer %s answered with the wrong nonce", claimed.Format())
+	}
+
+	if response.NetworkId != n.NetworkID {
+		return errors.Wrapf(ErrNetworkMismatch, "peer %s belongs to network %q", claimed.Format(), response.NetworkId)
+	}
+
+	return nil
+}
+
+// inboundClient returns the client of a peer connecting over a transport it is not
+// dialed back over, which is replied to over the session it dialed. Returns true should
+// the client have been created for the session, rather than the peer having been
+// connected to already.
+func (n *Network) inboundClient(address string, session *smux.Session) (*PeerClient, bool, error) {
+	address, err := ToUnifiedAddress(address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if address == n.Address {
+		return nil, false, ErrDialSelf
+	}
+
+	client, err := createPeerClient(n, address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if existing, exists := n.Peers.LoadOrStore(address, client); exists {
+		existing := existing.(*PeerClient)
+
+		if !existing.OutgoingReady() {
+			return nil, false, errors.Wrapf(ErrHandshakeTimeout, "peer %s failed to connect", address)
+		}
+
+		return existing, false, nil
+	}
+
+	n.Connections.Store(address, &ConnState{session: session})
+
+	close(client.outgoingReady)
+	client.Init()
+
+	return client, true, nil
+}
+
+// answerChallenge answers an identity challenge over the stream it was received on.
+func (n *Network) answerChallenge(stream net.Conn, msg *protobuf.Message) error {
+	var challenge protobuf.IdentityChallenge
+	if err := ptypes.UnmarshalAny(msg.Message, &challenge); err != nil {
+		return errors.Wrap(ErrInvalidMessage, err.Error())
+	}
+
+	// Refuse to be connected to by peers of other networks.
+	if challenge.NetworkId != n.NetworkID {
+		return errors.Wrapf(ErrNetworkMismatch, "challenged by a peer of network %q", challenge.NetworkId)
+	}
+
+	response, err := n.PrepareMessage(&protobuf.IdentityResponse{Nonce: challenge.Nonce, NetworkId: n.NetworkID})
+	if err != nil {
+		return err
+	}
+
+	return sendMessage(stream, response)
+}
@@ -0,0 +1,76 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestDialBackRejectsImpostor(t *testing.T) {
+	victim := buildNode(t, 13193, new(discovery.Plugin))
+	target := buildNode(t, 13194, new(discovery.Plugin))
+
+	defer victim.Close()
+	defer target.Close()
+
+	// The impostor claims the victim's address, which it does not listen on.
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(victim.Address)
+
+	impostor, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	impostor.Init()
+
+	client, err := impostor.Client(target.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(&protobuf.Ping{}); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	if c, exists := target.Peers.Load(victim.Address); exists {
+		if id := c.(*network.PeerClient).ID(); id != nil && id.Equals(impostor.ID) {
+			t.Fatal("expected impostor to not be identified as the peer at the victim's address")
+		}
+	}
+
+	plugin, _ := target.Plugins.Get(discovery.PluginID)
+	if plugin.(*discovery.Plugin).Routes.PeerExists(impostor.ID) {
+		t.Fatal("expected impostor to not be added to the routing table")
+	}
+}
+
+func TestDialBackAcceptsPeer(t *testing.T) {
+	alice := buildNode(t, 13195, new(discovery.Plugin))
+	bob := buildNode(t, 13196, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	if err := alice.Bootstrap(bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	c, exists := bob.Peers.Load(alice.Address)
+	if !exists {
+		t.Fatal("expected bob to be connected to alice")
+	}
+
+	if id := c.(*network.PeerClient).ID(); id == nil || !id.Equals(alice.ID) {
+		t.Fatalf("expected alice to be identified, got %v", id)
+	}
+}
@@ -0,0 +1,135 @@
+package discovery
+
+import (
+	"crypto/rand"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+)
+
+func (state *Plugin) maintenanceInterval() time.Duration {
+	if state.MaintenanceInterval <= 0 {
+		return defaultMaintenanceInterval
+	}
+	return state.MaintenanceInterval
+}
+
+// connectedPeers returns all peers the network is connected to.
+func connectedPeers(net *network.Network) (clients []*network.PeerClient) {
+	net.Peers.Range(func(key, value interface{}) bool {
+		clients = append(clients, value.(*network.PeerClient))
+		return true
+	})
+	return
+}
+
+// Maintain performs a single round of maintenance, looking up and connecting to new
+// peers should there be fewer than MinPeers connected, and disconnecting from the
+// furthest peers should there be more than MaxPeers connected.
+func (state *Plugin) Maintain(net *network.Network) {
+	connected := connectedPeers(net)
+
+	if state.MinPeers > 0 && len(connected) < state.MinPeers {
+		state.fill(net, state.MinPeers-len(connected))
+	}
+
+	if state.MaxPeers > 0 && len(connected) > state.MaxPeers {
+		state.trim(net, connected, len(connected)-state.MaxPeers)
+	}
+}
+
+// fill looks up peers close to both ourselves and a random target, and connects to at
+// most count peers within the routing table we are not yet connected to.
+func (state *Plugin) fill(net *network.Network, count int) {
+	targets := []peer.ID{net.ID}
+
+	random := make([]byte, len(net.ID.PublicKey))
+	if _, err := rand.Read(random); err == nil {
+		targets = append(targets, peer.ID{PublicKey: random})
+	}
+
+	for _, target := range targets {
+		for _, id := range FindNode(net, target, dht.BucketSize, 8) {
+			state.Routes.AddUnverified(id)
+		}
+	}
+
+	for _, id := range state.Routes.GetPeers() {
+		if count <= 0 {
+			break
+		}
+
+		if _, connected := net.Peers.Load(id.Address); connected {
+			continue
+		}
+
+		client, err := net.Client(id.Address)
+		if err != nil {
+			continue
+		}
+
+		if _, err := client.Tell(net.NewPing()); err != nil {
+			continue
+		}
+
+		count--
+	}
+}
+
+// trim disconnects from count unprotected peers, preferring peers with the lightest
+// tags, then peers outside of the routing table, and then peers furthest from ourselves.
+func (state *Plugin) trim(net *network.Network, connected []*network.PeerClient, count int) {
+	var outside []*network.PeerClient
+	var inside []peer.ID
+
+	clients := make(map[string]*network.PeerClient)
+
+	for _, client := range connected {
+		if client.ID() != nil && net.IsProtected(*client.ID()) {
+			continue
+		}
+
+		if client.ID() == nil || !state.Routes.PeerExists(*client.ID()) {
+			outside = append(outside, client)
+			continue
+		}
+
+		inside = append(inside, *client.ID())
+		clients[client.ID().PublicKeyHex()] = client
+	}
+
+	// Furthest peers come last when sorted by distance.
+	peer.SortByDistance(net.ID, inside)
+
+	for i := len(inside) - 1; i >= 0; i-- {
+		outside = append(outside, clients[inside[i].PublicKeyHex()])
+	}
+
+	sort.SliceStable(outside, func(i, j int) bool {
+		return weight(net, outside[i]) < weight(net, outside[j])
+	})
+
+	for _, client := range outside {
+		if count <= 0 {
+			break
+		}
+
+		glog.Infof("Disconnecting from peer %s to stay within %d peers.", client.Address(), state.MaxPeers)
+
+		client.Close()
+		count--
+	}
+}
+
+// weight returns the sum of the weights of the tags on a peer.
+func weight(net *network.Network, client *network.PeerClient) int {
+	if client.ID() == nil {
+		return 0
+	}
+
+	return net.PeerWeight(*client.ID())
+}
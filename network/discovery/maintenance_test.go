@@ -0,0 +1,38 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/networktest"
+)
+
+func buildNode(t *testing.T, plugin *Plugin) *network.Network {
+	return networktest.NewNode(t, plugin)
+}
+
+func TestMaintainTrims(t *testing.T) {
+	plugin := &Plugin{MaxPeers: 1}
+	node := buildNode(t, plugin)
+	defer node.Close()
+
+	for i := 1; i <= 3; i++ {
+		other := buildNode(t, new(Plugin))
+		defer other.Close()
+
+		node.Bootstrap(other.Address)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	if connected := len(connectedPeers(node)); connected != 3 {
+		t.Fatalf("expected 3 connected peers before maintenance, got %d", connected)
+	}
+
+	plugin.Maintain(node)
+
+	if connected := len(connectedPeers(node)); connected != 1 {
+		t.Fatalf("expected 1 connected peer after maintenance, got %d", connected)
+	}
+}
@@ -2,12 +2,14 @@ package discovery
 
 import (
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/perlin-network/noise/dht"
 	"github.com/perlin-network/noise/network"
 	"github.com/perlin-network/noise/peer"
 	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/schedule"
 )
 
 type Plugin struct {
@@ -17,19 +19,101 @@ type Plugin struct {
 	DisablePong   bool
 	DisableLookup bool
 
+	// AllowUnverifiedAddresses lets peers into the routing table whose claimed address
+	// does not match the address they were observed connecting from. Should only be
+	// enabled should peers be behind proxies or multi-homed.
+	AllowUnverifiedAddresses bool
+
+	// PruneInterval is how often peers which have remained unverified for longer
+	// than UnverifiedTTL are pruned from the routing table. Zero if the defaults.
+	PruneInterval time.Duration
+	UnverifiedTTL time.Duration
+
+	// MinPeers and MaxPeers bound the number of connected peers, which is checked
+	// every MaintenanceInterval. Lookups are performed while below MinPeers, and the
+	// furthest peers are disconnected while above MaxPeers. Zero if unbounded.
+	MinPeers            int
+	MaxPeers            int
+	MaintenanceInterval time.Duration
+
+	// PersistInterval is how often the routing table is persisted to the backend of
+	// the network's peer store, should it have one, such that it is restored upon
+	// restarting. Zero if the default.
+	PersistInterval time.Duration
+
 	Routes *dht.RoutingTable
+
+	// Periodic tasks scheduled onto the network.
+	tasks []*schedule.Task
+
+	// persisted holds the addresses of the peers within the routing table last
+	// persisted by their IDs.
+	persisted map[string]string
 }
 
 var PluginID = (*Plugin)(nil)
 
+const (
+	defaultPruneInterval = 1 * time.Minute
+	defaultUnverifiedTTL = 1 * time.Minute
+
+	defaultMaintenanceInterval = 30 * time.Second
+)
+
 func (state *Plugin) Startup(net *network.Network) {
+
+	if err := net.RegisterMessages(new(protobuf.LookupNodeRequest), new(protobuf.LookupNodeResponse)); err != nil {
+		glog.Warningf("discovery: failed to register message types [err=%s]", err)
+	}
 	// Create routing table.
 	state.Routes = dht.CreateRoutingTable(net.ID)
+	state.Routes.SetClock(net.Clock)
+	state.Routes.SetProtector(net.IsProtected)
+
+	// Estimate the size of the network from the density of the routing table.
+	if net.SizeEstimator == nil {
+		net.SizeEstimator = state.Routes
+	}
+
+	state.tasks = append(state.tasks, net.Scheduler().Repeat(schedule.Every(state.pruneInterval()), state.pruneUnverified))
+
+	if backend := net.Peerstore.Backend(); backend != nil {
+		state.restoreRoutes(net, backend)
+
+		state.tasks = append(state.tasks, net.Scheduler().Repeat(schedule.Every(state.persistInterval()), func() {
+			state.persistRoutes(net, backend)
+		}))
+	}
+
+	if state.MinPeers > 0 || state.MaxPeers > 0 {
+		state.tasks = append(state.tasks, net.Scheduler().Repeat(schedule.Every(state.maintenanceInterval()), func() {
+			state.Maintain(net)
+		}))
+	}
+}
+
+func (state *Plugin) pruneInterval() time.Duration {
+	if state.PruneInterval <= 0 {
+		return defaultPruneInterval
+	}
+	return state.PruneInterval
+}
+
+// pruneUnverified prunes peers which were heard of from other peers, but never heard
+// from directly.
+func (state *Plugin) pruneUnverified() {
+	ttl := state.UnverifiedTTL
+	if ttl <= 0 {
+		ttl = defaultUnverifiedTTL
+	}
+
+	for _, peerID := range state.Routes.PruneUnverified(ttl) {
+		glog.Infof("Pruned unverified peer %s from the routing table.", peerID.Format())
+	}
 }
 
 func (state *Plugin) Receive(ctx *network.PluginContext) error {
-	// Update routing for every incoming message.
-	state.Routes.Update(ctx.Sender())
+	client := ctx.Client()
 
 	// Handle RPC.
 	switch msg := ctx.Message().(type) {
@@ -38,22 +122,39 @@ func (state *Plugin) Receive(ctx *network.PluginContext) error {
 			break
 		}
 
+		// Drop duplicate pings, such that peers may not flood us into responding.
+		if err := client.ReceivePing(); err != nil {
+			glog.Warningf("Dropped ping from %s [err=%s]", client.Address(), err)
+			return nil
+		}
+
 		// Send pong to peer.
-		err := ctx.Reply(&protobuf.Pong{})
+		err := ctx.Reply(ctx.Network().NewPong(msg))
 
 		if err != nil {
 			return err
 		}
+
+		state.verifyHandshake(client)
 	case *protobuf.Pong:
-		if state.DisablePong {
+		// Drop unsolicited pongs, such that peers may not trigger lookups at will.
+		if err := client.ReceivePong(); err != nil {
+			glog.Warningf("Dropped pong from %s [err=%s]", client.Address(), err)
+			return nil
+		}
+
+		state.verifyHandshake(client)
+
+		if state.DisablePong || client.HandshakeState() != network.HandshakeDone {
 			break
 		}
 
 		peers := FindNode(ctx.Network(), ctx.Sender(), dht.BucketSize, 8)
 
-		// Update routing table w/ closest peers to self.
+		// Update routing table w/ closest peers to self. Peers are only verified once
+		// they are heard from directly.
 		for _, peerID := range peers {
-			state.Routes.Update(peerID)
+			state.Routes.AddUnverified(peerID)
 		}
 
 		glog.Infof("bootstrapped w/ peer(s): %s.", strings.Join(state.Routes.GetPeerAddresses(), ", "))
@@ -65,8 +166,12 @@ func (state *Plugin) Receive(ctx *network.PluginContext) error {
 		// Prepare response.
 		response := &protobuf.LookupNodeResponse{}
 
-		// Respond back with closest peers to a provided target.
+		// Respond back with closest verified peers to a provided target.
 		for _, peerID := range state.Routes.FindClosestPeers(peer.ID(*msg.Target), dht.BucketSize) {
+			if !state.Routes.IsVerified(peerID) {
+				continue
+			}
+
 			id := protobuf.ID(peerID)
 			response.Peers = append(response.Peers, &id)
 		}
@@ -79,20 +184,45 @@ func (state *Plugin) Receive(ctx *network.PluginContext) error {
 		glog.Infof("connected peers: %s.", strings.Join(state.Routes.GetPeerAddresses(), ", "))
 	}
 
+	// Update routing for every incoming message from peers which have completed their
+	// handshake, to prevent the routing table from being poisoned with victim addresses.
+	if client.HandshakeState() == network.HandshakeDone {
+		state.Routes.Update(ctx.Sender())
+	}
+
 	return nil
 }
 
+// verifyHandshake completes the handshake with a peer which has exchanged a ping and
+// pong with us, should its claimed address be verified.
+func (state *Plugin) verifyHandshake(client *network.PeerClient) {
+	if err := client.VerifyHandshake(state.AllowUnverifiedAddresses); err != nil {
+		glog.Warningf("Did not add peer %s to the routing table [err=%s]", client.Address(), err)
+	}
+}
+
 func (state *Plugin) Cleanup(net *network.Network) {
-	// TODO: Save routing table?
+	// The routing table is persisted periodically rather than here, as peers are
+	// removed from it as they disconnect once the network is closed.
+	for _, task := range state.tasks {
+		task.Cancel()
+	}
 }
 
 func (state *Plugin) PeerDisconnect(client *network.PeerClient) {
-	// Delete peer if in routing table.
-	if client.ID != nil {
-		if state.Routes.PeerExists(*client.ID) {
-			state.Routes.RemovePeer(*client.ID)
+	// Delete peer if in routing table, unless it is protected.
+	if client.ID() != nil && !client.Network.IsProtected(*client.ID()) {
+		if state.Routes.PeerExists(*client.ID()) {
+			state.Routes.RemovePeer(*client.ID())
 
-			glog.Infof("Peer %s has disconnected from %s.", client.ID.Address, client.Network.ID.Address)
+			glog.Infof("Peer %s has disconnected from %s.", client.ID().Format(), client.Network.ID.Format())
 		}
 	}
 }
+
+// PeerMigrate updates the address of a peer in the routing table.
+func (state *Plugin) PeerMigrate(client *network.PeerClient, from string) {
+	if client.ID() != nil && state.Routes.PeerExists(*client.ID()) {
+		state.Routes.Update(*client.ID())
+	}
+}
@@ -0,0 +1,17 @@
+package discovery
+
+import (
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+)
+
+// Closest implements network.Router.
+func (state *Plugin) Closest(target peer.ID, count int) []peer.ID {
+	return state.Routes.FindClosestPeers(target, count)
+}
+
+// Lookup implements network.Router.
+func (state *Plugin) Lookup(net *network.Network, target peer.ID, count int) []peer.ID {
+	return peer.Closest(target, FindNode(net, target, dht.BucketSize, 8), count)
+}
@@ -0,0 +1,82 @@
+package discovery
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/peerstore"
+)
+
+const defaultPersistInterval = 1 * time.Minute
+
+func (state *Plugin) persistInterval() time.Duration {
+	if state.PersistInterval <= 0 {
+		return defaultPersistInterval
+	}
+	return state.PersistInterval
+}
+
+// restoreRoutes adds the peers within the routing table persisted to a backend by a
+// prior process to the routing table as unverified, such that maintenance reconnects
+// to them, and such that they are pruned should they not be heard from.
+func (state *Plugin) restoreRoutes(net *network.Network, backend peerstore.Backend) {
+	state.persisted = make(map[string]string)
+
+	err := backend.Iterate(peerstore.RoutesBucket, func(key string, value []byte) error {
+		id, err := peer.Parse(key + "@" + string(value))
+		if err != nil || id.Equals(net.ID) {
+			return nil
+		}
+
+		state.persisted[key] = id.Address
+		state.Routes.AddUnverified(id)
+
+		return nil
+	})
+
+	if err != nil {
+		glog.Warningf("Failed to restore the routing table from the peer store backend [err=%s]", err)
+	}
+}
+
+// persistRoutes writes the peers within the routing table to a backend, and deletes
+// those which have since left it. The routing table is not persisted once the network
+// is closed, as peers are removed from it as they disconnect.
+func (state *Plugin) persistRoutes(net *network.Network, backend peerstore.Backend) {
+	select {
+	case <-net.Kill:
+		return
+	default:
+	}
+
+	current := make(map[string]string)
+	for _, id := range state.Routes.GetPeers() {
+		current[id.String()] = id.Address
+	}
+
+	for key, address := range current {
+		if state.persisted[key] == address {
+			continue
+		}
+
+		if err := backend.Put(peerstore.RoutesBucket, key, []byte(address)); err != nil {
+			glog.Warningf("Failed to persist peer %s@%s of the routing table [err=%s]", key, address, err)
+			delete(current, key)
+		}
+	}
+
+	for key := range state.persisted {
+		if _, exists := current[key]; exists {
+			continue
+		}
+
+		if err := backend.Delete(peerstore.RoutesBucket, key); err != nil {
+			glog.Warningf("Failed to forget peer %s of the routing table [err=%s]", key, err)
+			current[key] = state.persisted[key]
+		}
+	}
+
+	state.persisted = current
+}
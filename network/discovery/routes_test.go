@@ -0,0 +1,70 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/peerstore"
+)
+
+func buildPersistedNode(t *testing.T, port uint16, plugin *Plugin, backend peerstore.Backend) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", port))
+	builder.SetPeerstoreBackend(backend)
+	builder.AddPlugin(plugin)
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go node.Listen()
+	node.BlockUntilListening()
+
+	return node
+}
+
+func TestRoutesPersist(t *testing.T) {
+	seed := buildNode(t, new(Plugin))
+	defer seed.Close()
+
+	backend := peerstore.NewMemoryBackend()
+
+	member := buildPersistedNode(t, 13243, &Plugin{PersistInterval: 50 * time.Millisecond}, backend)
+
+	if err := member.Bootstrap(seed.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+
+	for {
+		if address, exists, _ := backend.Get(peerstore.RoutesBucket, seed.ID.String()); exists && string(address) == seed.Address {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the routing table to be persisted")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	member.Close()
+
+	// Closing the network does not forget the peers persisted, despite them being
+	// removed from the routing table as they disconnect.
+	time.Sleep(100 * time.Millisecond)
+
+	restarted := new(Plugin)
+	fresh := buildPersistedNode(t, 13244, restarted, backend)
+	defer fresh.Close()
+
+	if !restarted.Routes.PeerExists(seed.ID) || restarted.Routes.IsVerified(seed.ID) {
+		t.Fatal("expected the persisted routing table to be restored as unverified")
+	}
+}
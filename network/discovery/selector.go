@@ -0,0 +1,71 @@
+package discovery
+
+import (
+	"github.com/perlin-network/noise/network"
+)
+
+// BucketSelector selects up to K connected peers from the routing table spread evenly
+// across its buckets, such that peers at every XOR distance from this node are
+// represented. Peers are selected from each bucket w.r.t. the network's ZonePreference.
+// Peers in the routing table we are not connected to are never dialed.
+type BucketSelector struct {
+	K int
+}
+
+// Select implements network.Selector.
+func (s BucketSelector) Select(net *network.Network) (clients []*network.PeerClient) {
+	plugin, exists := net.Plugin(PluginID)
+
+	// Discovery plugin was not registered. Fail.
+	if !exists {
+		return
+	}
+
+	routes := plugin.(*Plugin).Routes
+
+	var strata [][]*network.PeerClient
+
+	for i := 0; i < routes.NumBuckets(); i++ {
+		var stratum []*network.PeerClient
+
+		for _, id := range routes.Bucket(i).Peers() {
+			if id.Equals(routes.Self()) {
+				continue
+			}
+
+			if client, connected := net.Peers.Load(id.Address); connected {
+				stratum = append(stratum, client.(*network.PeerClient))
+			}
+		}
+
+		if len(stratum) > 0 {
+			stratum = net.SelectByZone(stratum, len(stratum))
+			strata = append(strata, stratum)
+		}
+	}
+
+	// Pick peers round-robin across all non-empty buckets.
+	for round := 0; len(clients) < s.K; round++ {
+		progressed := false
+
+		for _, stratum := range strata {
+			if round >= len(stratum) {
+				continue
+			}
+
+			progressed = true
+
+			clients = append(clients, stratum[round])
+
+			if len(clients) >= s.K {
+				break
+			}
+		}
+
+		if !progressed {
+			break
+		}
+	}
+
+	return
+}
@@ -0,0 +1,36 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+)
+
+func TestBucketSelectorSkipsDisconnected(t *testing.T) {
+	plugin := new(Plugin)
+	node := buildNode(t, plugin)
+	defer node.Close()
+
+	other := buildNode(t, new(Plugin))
+	defer other.Close()
+
+	node.Bootstrap(other.Address)
+
+	time.Sleep(500 * time.Millisecond)
+
+	// A peer we learnt of but are not connected to.
+	stranger := peer.CreateID(network.FormatAddress("tcp", "127.0.0.1", 13247), ed25519.RandomKeyPair().PublicKey)
+	plugin.Routes.Update(stranger)
+
+	clients := BucketSelector{K: 8}.Select(node)
+	if len(clients) != 1 || clients[0].Address() != other.Address {
+		t.Fatalf("expected only the connected peer to be selected, got %d peers", len(clients))
+	}
+
+	if _, dialed := node.Peers.Load(stranger.Address); dialed {
+		t.Fatal("expected the selector not to dial peers we are not connected to")
+	}
+}
@@ -0,0 +1,166 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+// SnapshotVersion is the version of the snapshot format exported by this node.
+const SnapshotVersion = 1
+
+// SnapshotPeer is a peer known to a node at the time its snapshot was taken.
+type SnapshotPeer struct {
+	// ID is the peer's ID in its canonical encoding.
+	ID      string `json:"id"`
+	Address string `json:"address"`
+
+	// Verified is true should the peer have been heard from directly.
+	Verified bool `json:"verified"`
+
+	// Score is the fraction of time the peer has been connected since first seen.
+	Score float64 `json:"score"`
+
+	// Weight is the sum of the weights of the peer's tags.
+	Weight int `json:"weight,omitempty"`
+}
+
+// Snapshot is the set of peers known to a node, which an operator may hand to new
+// nodes such that they bootstrap quickly.
+type Snapshot struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Peers     []SnapshotPeer `json:"peers"`
+}
+
+// Snapshot returns all peers within the routing table, sorted by descending score.
+func (state *Plugin) Snapshot(net *network.Network) *Snapshot {
+	now := clock.Or(net.Clock).Now()
+
+	snapshot := &Snapshot{Version: SnapshotVersion, CreatedAt: now}
+
+	for _, id := range state.Routes.GetPeers() {
+		entry := SnapshotPeer{
+			ID:       id.String(),
+			Address:  id.Address,
+			Verified: state.Routes.IsVerified(id),
+			Weight:   net.Peerstore.Weight(id.PublicKeyHex()),
+		}
+
+		if stats, exists := net.Peerstore.Get(id.Address); exists {
+			entry.Score = stats.UptimeRatio(now)
+		}
+
+		snapshot.Peers = append(snapshot.Peers, entry)
+	}
+
+	sortSnapshotPeers(snapshot.Peers)
+
+	return snapshot
+}
+
+func sortSnapshotPeers(peers []SnapshotPeer) {
+	sort.SliceStable(peers, func(i, j int) bool {
+		if peers[i].Score != peers[j].Score {
+			return peers[i].Score > peers[j].Score
+		}
+		return peers[i].Weight > peers[j].Weight
+	})
+}
+
+// ExportSnapshot writes a snapshot of all peers within the routing table to a file
+// as JSON. The file is replaced atomically.
+func (state *Plugin) ExportSnapshot(net *network.Network, path string) error {
+	bytes, err := json.MarshalIndent(state.Snapshot(net), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode snapshot")
+	}
+
+	temp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create snapshot file")
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(bytes); err != nil {
+		temp.Close()
+		return errors.Wrap(err, "failed to write snapshot file")
+	}
+
+	if err := temp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write snapshot file")
+	}
+
+	return errors.Wrap(os.Rename(temp.Name(), path), "failed to replace snapshot file")
+}
+
+// LoadSnapshot reads a snapshot exported by ExportSnapshot.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read snapshot file")
+	}
+
+	snapshot := new(Snapshot)
+	if err := json.Unmarshal(bytes, snapshot); err != nil {
+		return nil, errors.Wrap(err, "failed to decode snapshot")
+	}
+
+	if snapshot.Version != SnapshotVersion {
+		return nil, errors.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+
+	return snapshot, nil
+}
+
+// ImportSnapshot adds all peers of a snapshot to the routing table as unverified,
+// and bootstraps to up to k of them with the highest scores, succeeding should any
+// one of them be connected to. Peers which do not parse, or which are ourselves,
+// are skipped. Returns the number of peers added.
+func (state *Plugin) ImportSnapshot(net *network.Network, snapshot *Snapshot, k int) (int, error) {
+	peers := append([]SnapshotPeer(nil), snapshot.Peers...)
+	sortSnapshotPeers(peers)
+
+	added := 0
+	var addresses []string
+
+	for _, entry := range peers {
+		id, err := peer.Parse(entry.ID + "@" + entry.Address)
+		if err != nil || id.Equals(net.ID) {
+			continue
+		}
+
+		if state.Routes.AddUnverified(id) {
+			added++
+		}
+
+		if len(addresses) < k {
+			addresses = append(addresses, id.Address)
+		}
+	}
+
+	if len(addresses) == 0 {
+		return added, nil
+	}
+
+	return added, net.BootstrapWithOptions(context.Background(), network.BootstrapOptions{MinSeeds: 1, MaxAttempts: 1}, addresses...)
+}
+
+// ImportSnapshotFile loads a snapshot from a file, and imports it as ImportSnapshot.
+func (state *Plugin) ImportSnapshotFile(net *network.Network, path string, k int) (int, error) {
+	snapshot, err := LoadSnapshot(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return state.ImportSnapshot(net, snapshot, k)
+}
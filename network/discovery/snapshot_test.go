@@ -0,0 +1,62 @@
+package discovery
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSnapshotExportImport(t *testing.T) {
+	seed := buildNode(t, new(Plugin))
+	defer seed.Close()
+
+	exporter := new(Plugin)
+	member := buildNode(t, exporter)
+	defer member.Close()
+
+	if err := member.Bootstrap(seed.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "peers.json")
+
+	if err := exporter.ExportSnapshot(member, path); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snapshot.Peers) != 1 || snapshot.Peers[0].ID != seed.ID.String() || !snapshot.Peers[0].Verified {
+		t.Fatalf("expected snapshot of the seed, got %+v", snapshot.Peers)
+	}
+
+	importer := new(Plugin)
+	fresh := buildNode(t, importer)
+	defer fresh.Close()
+
+	added, err := importer.ImportSnapshotFile(fresh, path, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if added != 1 || !importer.Routes.PeerExists(seed.ID) {
+		t.Fatalf("expected seed to be imported, added %d", added)
+	}
+
+	if _, exists := fresh.Peers.Load(seed.Address); !exists {
+		t.Fatal("expected new node to bootstrap to the imported seed")
+	}
+}
@@ -0,0 +1,126 @@
+package network
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/xtaci/smux"
+)
+
+// retireTimeout is how long a yielded connection is left open for the messages in
+// flight over it to be written.
+const retireTimeout = 3 * time.Second
+
+// yield sends to a peer over the connection it dialed us over in place of the one we
+// dialed it over, should the peer have the lower ID, such that a single connection
+// remains between us. Ours is closed once the messages in flight over it are written.
+func (n *Network) yield(client *PeerClient, incoming *smux.Session) {
+	state, exists := n.Connections.Load(client.Address())
+
+	n.Connections.Store(client.Address(), &ConnState{session: incoming})
+
+	if exists {
+		session := state.(*ConnState).session
+		n.spawn(GoroutineRetire, func() { n.retire(session) })
+	}
+
+	glog.Infof("Yielded our connection to peer %s, which has the lower ID.", client.ID().Format())
+}
+
+// retire closes a session once its streams are closed, or once retireTimeout elapses.
+func (n *Network) retire(session *smux.Session) {
+	defer session.Close()
+
+	deadline := n.clock().Now().Add(retireTimeout)
+
+	for session.NumStreams() > 0 && n.clock().Now().Before(deadline) {
+		select {
+		case <-n.clock().After(10 * time.Millisecond):
+		case <-n.Kill:
+			return
+		}
+	}
+}
+
+// serveOutgoing serves the messages a peer sends over the session we dialed it over,
+// which it does once it yielded its own connection to us, or should it not dial us
+// back, in which case it also challenges us to prove our identity over the session. The peer is disconnected
+// once the session is closed, unless it remains connected over other connections.
+func (n *Network) serveOutgoing(client *PeerClient, session *smux.Session) {
+	recvWindow := NewRecvWindow(recvWindowSize)
+
+	var identify sync.Once
+
+	closed := make(chan struct{})
+
+	defer func() {
+		close(closed)
+
+		if atomic.AddInt32(&client.connections, -1) > 0 {
+			return
+		}
+
+		client.Close()
+		session.Close()
+	}()
+
+	for {
+		stream, err := session.AcceptStream()
+		if err != nil {
+			return
+		}
+
+		// The stream is released under the address it was reserved under, as the peer
+		// may migrate while it is being read from.
+		address := client.Address()
+		if err := n.Resources.ReserveStream(address); err != nil {
+			glog.Warningf("Dropped stream from %s [err=%s]", address, err)
+			stream.Close()
+			continue
+		}
+
+		n.spawn(GoroutineIngest, func() {
+			defer n.Resources.ReleaseStream(address)
+			defer stream.Close()
+
+			msg, err := n.receiveMessage(stream)
+			if err != nil {
+				return
+			}
+
+			if ptypes.Is(msg.Message, (*protobuf.IdentityChallenge)(nil)) {
+				if err := n.answerChallenge(stream, msg); err != nil {
+					glog.Warningf("Failed to answer identity challenge from %s [err=%s]", client.Address(), err)
+				}
+				return
+			}
+
+			// Only the peer answers at the address we dialed.
+			if address, err := ToUnifiedAddress(msg.Sender.Address); err != nil || address != client.Address() {
+				glog.Warningf("Dropped message from %s sent over our connection to %s", msg.Sender.Address, client.Address())
+				return
+			}
+
+			// Identify the peer should it not have identified itself over a connection
+			// it dialed yet.
+			identify.Do(func() {
+				if atomic.CompareAndSwapUint32(&client.identified, 0, 1) {
+					client.setID((*peer.ID)(msg.Sender))
+					close(client.incomingReady)
+				}
+			})
+
+			<-client.incomingReady
+
+			if err := n.deliver(client, msg, recvWindow, client.Address(), closed); err != nil {
+				glog.Error(err)
+				session.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package network_test
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// countingTransport counts the connections dialed over it which remain open.
+type countingTransport struct {
+	network.MemoryTransport
+	open int32
+}
+
+type countedConn struct {
+	net.Conn
+	transport *countingTransport
+	once      sync.Once
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(func() { atomic.AddInt32(&c.transport.open, -1) })
+	return c.Conn.Close()
+}
+
+func (t *countingTransport) Dial(addr *network.AddressInfo) (net.Conn, error) {
+	conn, err := t.MemoryTransport.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	atomic.AddInt32(&t.open, 1)
+	return &countedConn{Conn: conn, transport: t}, nil
+}
+
+var dialed = &countingTransport{}
+
+func init() {
+	network.RegisterTransport("counted", dialed)
+}
+
+func buildDeduplicatingNode(t *testing.T, port uint16) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("counted", "127.0.0.1", port))
+	builder.SetResolveDuplicates(true)
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestResolveDuplicates(t *testing.T) {
+	alice := buildDeduplicatingNode(t, 340)
+	bob := buildDeduplicatingNode(t, 341)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	aliceDatagrams, closeAlice := alice.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeAlice()
+
+	bobDatagrams, closeBob := bob.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeBob()
+
+	// Have both sides dial each other simultaneously.
+	var wg sync.WaitGroup
+	var aliceClient, bobClient *network.PeerClient
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+
+		var err error
+		if aliceClient, err = alice.Client(bob.Address); err != nil {
+			t.Error(err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+
+		var err error
+		if bobClient, err = bob.Client(alice.Address); err != nil {
+			t.Error(err)
+		}
+	}()
+	wg.Wait()
+
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	exchange := func() {
+		if _, err := aliceClient.Tell(&protobuf.Datagram{Data: []byte("alice")}); err != nil {
+			t.Fatal(err)
+		}
+		receiveDatagrams(t, bobDatagrams, 1)
+
+		if _, err := bobClient.Tell(&protobuf.Datagram{Data: []byte("bob")}); err != nil {
+			t.Fatal(err)
+		}
+		receiveDatagrams(t, aliceDatagrams, 1)
+	}
+
+	exchange()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&dialed.open) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a single connection to remain between alice and bob, got %d", atomic.LoadInt32(&dialed.open))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Both sides keep talking over the connection which survived.
+	exchange()
+
+	if _, exists := alice.Peers.Load(bob.Address); !exists {
+		t.Fatal("expected alice to remain connected to bob")
+	}
+
+	if _, exists := bob.Peers.Load(alice.Address); !exists {
+		t.Fatal("expected bob to remain connected to alice")
+	}
+}
@@ -0,0 +1,93 @@
+package network
+
+import (
+	"context"
+	"time"
+
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// DefaultPingTimeout is how long Ping waits on a peer to reply by default.
+const DefaultPingTimeout = 3 * time.Second
+
+// LatencySample is the latency to a peer measured by a single echo.
+type LatencySample struct {
+	// RTT is the time from sending the echo to receiving its reply.
+	RTT time.Duration
+
+	// OneWay is the estimated one-way delay, being half the round-trip time spent
+	// outside of the peer.
+	OneWay time.Duration
+
+	// Offset is the estimated offset of the peer's clock relative to ours, which is
+	// positive should the peer's clock be ahead.
+	Offset time.Duration
+}
+
+// newLatencySample estimates latency from the four timestamps of an echo: when it was
+// sent, received by the peer, replied to by the peer, and when its reply was received.
+func newLatencySample(sent, received, replied, now time.Time) LatencySample {
+	rtt := now.Sub(sent)
+	oneWay := (rtt - replied.Sub(received)) / 2
+	if oneWay < 0 {
+		oneWay = 0
+	}
+
+	return LatencySample{
+		RTT:    rtt,
+		OneWay: oneWay,
+		Offset: (received.Sub(sent) + replied.Sub(now)) / 2,
+	}
+}
+
+// Ping measures the latency to a peer by its address with an echo, which is answered
+// by the peer's network regardless of its plugins. The round-trip time is recorded in
+// the peer store.
+func (n *Network) Ping(ctx context.Context, address string) (LatencySample, error) {
+	client, err := n.Client(address)
+	if err != nil {
+		return LatencySample{}, err
+	}
+
+	sample, err := n.echo(ctx, client, nil)
+	if err != nil {
+		return LatencySample{}, err
+	}
+
+	n.Peerstore.RecordRTT(client.Address(), sample.RTT)
+
+	return sample, nil
+}
+
+// echo sends an echo carrying padding to a peer, which the peer echoes back, and
+// measures the latency of it being replied to.
+func (n *Network) echo(ctx context.Context, client *PeerClient, padding []byte) (LatencySample, error) {
+	sent := n.clock().Now()
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.Echo{Timestamp: sent.UnixNano(), Padding: padding})
+	request.SetTimeout(DefaultPingTimeout)
+
+	response, err := client.RequestWithContext(ctx, request)
+	if err != nil {
+		return LatencySample{}, err
+	}
+
+	now := n.clock().Now()
+
+	reply, ok := response.(*protobuf.EchoReply)
+	if !ok || reply.EchoTimestamp != sent.UnixNano() || len(reply.Padding) != len(padding) {
+		return LatencySample{}, errors.Wrapf(ErrInvalidMessage, "unexpected reply to echo from %s", client.Address())
+	}
+
+	return newLatencySample(sent, time.Unix(0, reply.ReceivedTimestamp), time.Unix(0, reply.Timestamp), now), nil
+}
+
+// RTT returns the moving average of the round-trip times measured to the peer. Zero
+// if never measured.
+func (c *PeerClient) RTT() time.Duration {
+	stats, _ := c.Network.Peerstore.Get(c.Address())
+	return stats.RTT
+}
@@ -0,0 +1,54 @@
+package network_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func TestPing(t *testing.T) {
+	alice := buildNode(t, 13209, new(discovery.Plugin))
+	bob := buildNode(t, 13210, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	alice.Bootstrap(bob.Address)
+
+	sample, err := alice.Ping(context.Background(), bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sample.RTT <= 0 || sample.OneWay < 0 || sample.OneWay > sample.RTT {
+		t.Fatalf("unexpected latency sample %+v", sample)
+	}
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.RTT() != sample.RTT {
+		t.Fatalf("expected round-trip time %s to be recorded, got %s", sample.RTT, client.RTT())
+	}
+
+	if clients := (network.LatencySelector{K: 1}).Select(alice); len(clients) != 1 || clients[0].Address() != bob.Address {
+		t.Fatal("expected the measured peer to be selected")
+	}
+}
+
+func TestPingCancelled(t *testing.T) {
+	alice := buildNode(t, 13211, new(discovery.Plugin))
+	defer alice.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := alice.Ping(ctx, "tcp://127.0.0.1:13212"); err == nil {
+		t.Fatal("expected ping to an unreachable peer to fail")
+	}
+}
@@ -0,0 +1,125 @@
+package network
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+const (
+	// EnvelopeVersion is the highest envelope version messages are encoded in.
+	EnvelopeVersion uint32 = 5
+
+	// PriorityEnvelopeVersion is the lowest envelope version supporting priority messages.
+	PriorityEnvelopeVersion uint32 = 2
+
+	// MinEnvelopeVersion is the lowest envelope version messages may be encoded in.
+	// Version 0 are envelopes from peers which predate envelope versioning.
+	MinEnvelopeVersion uint32 = 0
+)
+
+// EnvelopeMigration converts envelopes between one version and the next.
+type EnvelopeMigration struct {
+	// Up converts an envelope from the version the migration is registered at to the
+	// next version.
+	Up func(msg *protobuf.Message) error
+
+	// Down converts an envelope from the next version back to the version the
+	// migration is registered at.
+	Down func(msg *protobuf.Message) error
+}
+
+var (
+	envelopeMigrations      = make(map[uint32]EnvelopeMigration)
+	envelopeMigrationsMutex sync.RWMutex
+)
+
+// RegisterEnvelopeMigration registers the migration converting envelopes between
+// version from and version from + 1. Nil conversions leave envelopes as is.
+func RegisterEnvelopeMigration(from uint32, migration EnvelopeMigration) {
+	envelopeMigrationsMutex.Lock()
+	envelopeMigrations[from] = migration
+	envelopeMigrationsMutex.Unlock()
+}
+
+// upgradeEnvelope migrates a received envelope up to the current envelope version.
+func upgradeEnvelope(msg *protobuf.Message) error {
+	if msg.Version > EnvelopeVersion {
+		return errors.Errorf("envelope version %d is newer than supported version %d", msg.Version, EnvelopeVersion)
+	}
+
+	envelopeMigrationsMutex.RLock()
+	defer envelopeMigrationsMutex.RUnlock()
+
+	for msg.Version < EnvelopeVersion {
+		if up := envelopeMigrations[msg.Version].Up; up != nil {
+			if err := up(msg); err != nil {
+				return errors.Wrapf(err, "failed to migrate envelope from version %d", msg.Version)
+			}
+		}
+
+		msg.Version++
+	}
+
+	return nil
+}
+
+// downgradeEnvelope returns a copy of an envelope migrated down to a given version, or
+// the envelope itself should it be no newer than said version.
+func downgradeEnvelope(msg *protobuf.Message, version uint32) (*protobuf.Message, error) {
+	if msg.Version <= version {
+		return msg, nil
+	}
+
+	envelopeMigrationsMutex.RLock()
+	defer envelopeMigrationsMutex.RUnlock()
+
+	// Avoid deeply copying the envelope should no migration change its encoding, as
+	// only its version then differs.
+	changed := false
+	for v := version; v < msg.Version; v++ {
+		if envelopeMigrations[v].Down != nil {
+			changed = true
+		}
+	}
+
+	if !changed {
+		relabelled := *msg
+		relabelled.Version = version
+
+		return &relabelled, nil
+	}
+
+	downgraded := proto.Clone(msg).(*protobuf.Message)
+
+	for downgraded.Version > version {
+		if down := envelopeMigrations[downgraded.Version-1].Down; down != nil {
+			if err := down(downgraded); err != nil {
+				return nil, errors.Wrapf(err, "failed to migrate envelope to version %d", downgraded.Version-1)
+			}
+		}
+
+		downgraded.Version--
+	}
+
+	return downgraded, nil
+}
+
+// observeEnvelopeVersion records the highest envelope version a peer supports.
+func (c *PeerClient) observeEnvelopeVersion(version uint32) {
+	atomic.StoreUint32(&c.envelopeVersion, version)
+}
+
+// EnvelopeVersion returns the envelope version negotiated with the peer, being the
+// highest version supported by both ourselves and the peer. Until the peer has been
+// heard from, the lowest supported version is assumed.
+func (c *PeerClient) EnvelopeVersion() uint32 {
+	version := atomic.LoadUint32(&c.envelopeVersion)
+	if version > EnvelopeVersion {
+		version = EnvelopeVersion
+	}
+	return version
+}
@@ -0,0 +1,134 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestEnvelopeMigrations(t *testing.T) {
+	RegisterEnvelopeMigration(0, EnvelopeMigration{
+		Up: func(msg *protobuf.Message) error {
+			msg.LamportTimestamp++
+			return nil
+		},
+		Down: func(msg *protobuf.Message) error {
+			msg.LamportTimestamp--
+			return nil
+		},
+	})
+	defer RegisterEnvelopeMigration(0, EnvelopeMigration{})
+
+	msg := &protobuf.Message{Version: EnvelopeVersion, LamportTimestamp: 2}
+
+	downgraded, err := downgradeEnvelope(msg, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if downgraded == msg || downgraded.Version != 0 || downgraded.LamportTimestamp != 1 {
+		t.Fatalf("expected a downgraded copy of the envelope, got %+v", downgraded)
+	}
+
+	if err := upgradeEnvelope(downgraded); err != nil {
+		t.Fatal(err)
+	}
+
+	if downgraded.Version != EnvelopeVersion || downgraded.LamportTimestamp != 2 {
+		t.Fatalf("expected envelope to be upgraded, got %+v", downgraded)
+	}
+
+	// Envelopes no migration changes are still labelled with the version they are
+	// downgraded to.
+	relabelled, err := downgradeEnvelope(msg, EnvelopeVersion-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if relabelled == msg || relabelled.Version != EnvelopeVersion-1 || msg.Version != EnvelopeVersion {
+		t.Fatalf("expected a copy of the envelope labelled with version %d, got %+v", EnvelopeVersion-1, relabelled)
+	}
+
+	if err := upgradeEnvelope(&protobuf.Message{Version: EnvelopeVersion + 1}); err == nil {
+		t.Fatal("expected envelopes newer than supported to be rejected")
+	}
+}
+
+func TestEnvelopeVersionNegotiation(t *testing.T) {
+	client := new(PeerClient)
+
+	if version := client.EnvelopeVersion(); version != MinEnvelopeVersion {
+		t.Fatalf("expected lowest version before hearing from peer, got %d", version)
+	}
+
+	client.observeEnvelopeVersion(EnvelopeVersion + 5)
+
+	if version := client.EnvelopeVersion(); version != EnvelopeVersion {
+		t.Fatalf("expected version %d to be negotiated, got %d", EnvelopeVersion, version)
+	}
+}
+
+func TestDomainSeparatedEnvelopes(t *testing.T) {
+	n := createSigningTestNetwork()
+
+	msg, err := n.PrepareMessage(&protobuf.Ping{Timestamp: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !n.verifySignature(msg, msg.Version) {
+		t.Fatal("expected a ping to be verified")
+	}
+
+	// A signature produced within the handshake domain may not be replayed within the
+	// message domain, even though the payload is the same.
+	replayed := proto.Clone(msg).(*protobuf.Message)
+	replayed.Message.TypeUrl = "type.googleapis.com/protobuf.Datagram"
+
+	if n.verifySignature(replayed, replayed.Version) {
+		t.Fatal("expected a handshake signature not to be verified as that of a message")
+	}
+
+	// Nor may it be passed off as a signature of an older envelope version.
+	if n.verifySignature(msg, DomainEnvelopeVersion-1) {
+		t.Fatal("expected a domain-separated signature not to be verified without its domain")
+	}
+
+	legacy, err := n.resign(msg, MinEnvelopeVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if legacy.Version != MinEnvelopeVersion || !n.verifySignature(legacy, legacy.Version) {
+		t.Fatal("expected a message resigned for legacy peers to be verified without a domain")
+	}
+
+	// Untagged signatures may not be passed off as those of envelopes which separate
+	// domains.
+	if n.verifySignature(legacy, EnvelopeVersion) {
+		t.Fatal("expected a message resigned for legacy peers not to be verified once upgraded")
+	}
+
+	upgraded := proto.Clone(legacy).(*protobuf.Message)
+	if err := upgradeEnvelope(upgraded); err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := n.SignedEnvelope(upgraded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if signed.Version >= DomainEnvelopeVersion || !n.verifySignature(signed, signed.Version) {
+		t.Fatalf("expected an upgraded message to be encoded in a version its signature is of, got %d", signed.Version)
+	}
+
+	// Nor may a message be passed off as one of another type within the same domain.
+	retyped := proto.Clone(msg).(*protobuf.Message)
+	retyped.Message.TypeUrl = "type.googleapis.com/protobuf.Pong"
+
+	if n.verifySignature(retyped, retyped.Version) {
+		t.Fatal("expected a signature not to be verified as that of a message of another type")
+	}
+}
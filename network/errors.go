@@ -0,0 +1,76 @@
+package network
+
+import "github.com/pkg/errors"
+
+// Errors returned by the network are wrapped with context, and may be branched on by
+// comparing against errors.Cause(err).
+var (
+	// ErrSessionClosed is returned when reading from or writing to a closed stream.
+	ErrSessionClosed = errors.New("session closed")
+
+	// ErrPeerNotFound is returned should no connection to a peer exist.
+	ErrPeerNotFound = errors.New("peer not found")
+
+	// ErrHandshakeTimeout is returned should a peer not finish connecting in time.
+	ErrHandshakeTimeout = errors.New("handshake timed out")
+
+	// ErrMessageTooLarge is returned should a message exceed the maximum message size.
+	ErrMessageTooLarge = errors.New("message too large")
+
+	// ErrRequestTimeout is returned should a peer not respond to a request in time.
+	ErrRequestTimeout = errors.New("request timed out")
+
+	// ErrDeadlineExceeded is returned when reading from or writing to a stream past
+	// its deadline.
+	ErrDeadlineExceeded = errors.New("deadline exceeded")
+
+	// ErrDialSelf is returned when dialing our own address.
+	ErrDialSelf = errors.New("peer should not dial itself")
+
+	// ErrConnectionRejected is returned should a connection be rejected by the
+	// connection gater.
+	ErrConnectionRejected = errors.New("connection rejected by connection gater")
+
+	// ErrQueueFull is returned should a message not be queued for sending or
+	// receiving as the queue is full.
+	ErrQueueFull = errors.New("queue full")
+
+	// ErrInvalidMessage is returned should a received message be malformed.
+	ErrInvalidMessage = errors.New("invalid message")
+
+	// ErrPeerBanned is returned by filters dropping messages from banned peers. As
+	// banned peers may well keep flooding us, such drops are only logged verbosely.
+	ErrPeerBanned = errors.New("peer is banned")
+
+	// ErrInvalidSignature is returned should a received message have an invalid signature.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrHandshakeOutOfOrder is returned should a peer send a handshake message that is
+	// unsolicited, duplicated or otherwise out of order.
+	ErrHandshakeOutOfOrder = errors.New("handshake message out of order")
+
+	// ErrIdentityUnverified is returned should the node answering at a peer's claimed
+	// address fail to prove that it holds the peer's private key.
+	ErrIdentityUnverified = errors.New("identity could not be verified")
+
+	// ErrNetworkMismatch is returned should a peer belong to a network by another ID.
+	ErrNetworkMismatch = errors.New("network ID mismatch")
+
+	// ErrUnknownMessageType is returned should a message type not be known to protobuf.
+	ErrUnknownMessageType = errors.New("unknown message type")
+
+	// ErrMessageTypeCollision is returned should a message type be registered by the
+	// name of another.
+	ErrMessageTypeCollision = errors.New("message type collision")
+
+	// ErrRegistryFrozen is returned should a message type be registered into a frozen
+	// MessageRegistry.
+	ErrRegistryFrozen = errors.New("message registry frozen")
+
+	// ErrConnectionReset is returned by connections reset by a FaultTransport.
+	ErrConnectionReset = errors.New("connection reset")
+
+	// ErrKeysClosed is returned should a network sign once its keys were zeroed upon it
+	// being closed.
+	ErrKeysClosed = errors.New("keys closed")
+)
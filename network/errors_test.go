@@ -0,0 +1,28 @@
+package network_test
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+func TestErrorKinds(t *testing.T) {
+	alice := buildNode(t, 13190, new(discovery.Plugin))
+	defer alice.Close()
+
+	if _, err := alice.Client(alice.Address); errors.Cause(err) != network.ErrDialSelf {
+		t.Fatalf("expected dialing ourselves to fail with ErrDialSelf, got %v", err)
+	}
+
+	msg, err := alice.PrepareMessage(&protobuf.Ping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := alice.Write("tcp://127.0.0.1:13191", msg); errors.Cause(err) != network.ErrPeerNotFound {
+		t.Fatalf("expected writing to an unknown peer to fail with ErrPeerNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package network
+
+import (
+	"net"
+
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// MessageFilter inspects verified messages received from a peer before they are
+// dispatched, and drops them by returning an error.
+type MessageFilter interface {
+	FilterMessage(client *PeerClient, msg *protobuf.Message) error
+}
+
+// Envelope is the header of a received message prior to its contents being
+// unmarshalled and its signature verified. Its sender is hence unverified.
+type Envelope struct {
+	// Network is the network the message was received on.
+	Network *Network
+
+	RemoteAddr net.Addr
+	Sender     *protobuf.ID
+
+	// TypeURL is the type of the contents of the message.
+	TypeURL string
+
+	// Size is the size of the serialized message in bytes.
+	Size int
+}
+
+// PreFilter cheaply inspects the envelopes of incoming messages before they are
+// unmarshalled and verified, and drops them by returning an error.
+type PreFilter interface {
+	PreFilterMessage(envelope *Envelope) error
+}
+
+// PreFilterFunc adapts a function into a PreFilter.
+type PreFilterFunc func(envelope *Envelope) error
+
+// PreFilterMessage implements PreFilter.
+func (f PreFilterFunc) PreFilterMessage(envelope *Envelope) error {
+	return f(envelope)
+}
+
+// filterMessage runs a message through all of the networks filters.
+func (n *Network) filterMessage(client *PeerClient, msg *protobuf.Message) error {
+	for _, filter := range n.Filters {
+		if err := filter.FilterMessage(client, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// preFilterMessage runs the envelope of a message through all of the networks pre-filters.
+func (n *Network) preFilterMessage(envelope *Envelope) error {
+	for _, filter := range n.PreFilters {
+		if err := filter.PreFilterMessage(envelope); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
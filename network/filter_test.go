@@ -0,0 +1,45 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/pkg/errors"
+)
+
+func TestPreFilter(t *testing.T) {
+	alice := buildNode(t, 13110, new(discovery.Plugin))
+	bob := buildNode(t, 13111, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	envelopes := make(chan *network.Envelope, 16)
+
+	bob.PreFilters = append(bob.PreFilters, network.PreFilterFunc(func(envelope *network.Envelope) error {
+		envelopes <- envelope
+		return errors.New("dropped")
+	}))
+
+	pings, closePings := bob.Tap(network.TapFilter{Types: []string{"protobuf.Ping"}, Inbound: true})
+	defer closePings()
+
+	alice.Bootstrap(bob.Address)
+
+	select {
+	case envelope := <-envelopes:
+		if envelope.Sender.Address != alice.Address || envelope.TypeURL != "type.googleapis.com/protobuf.Ping" || envelope.Size <= 0 {
+			t.Fatalf("unexpected envelope %+v", envelope)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for pre-filtered envelope")
+	}
+
+	select {
+	case <-pings:
+		t.Fatal("expected pre-filtered message to never be dispatched")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,172 @@
+package network
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EffectiveConfig is the configuration of a network which is expected to be uniform
+// across a fleet of nodes, excluding settings particular to each node such as its keys
+// and host.
+type EffectiveConfig struct {
+	Protocol        string `json:"protocol"`
+	EnvelopeVersion uint32 `json:"envelope_version"`
+
+	NetworkID     string `json:"network_id"`
+	SignNetworkID bool   `json:"sign_network_id"`
+
+	SignaturePolicy string `json:"signature_policy"`
+	HashPolicy      string `json:"hash_policy"`
+
+	// DetachedVerification is true should envelopes be verified by a callback in place
+	// of the signature policy.
+	DetachedVerification bool `json:"detached_verification"`
+
+	// Plugins, Filters and PreFilters are the types of those registered, in order.
+	Plugins    []string `json:"plugins"`
+	Filters    []string `json:"filters"`
+	PreFilters []string `json:"pre_filters"`
+
+	// MessageTypes are the names of the message types registered, sorted.
+	MessageTypes []string `json:"message_types"`
+
+	Capabilities      Capability    `json:"capabilities"`
+	LogicalClock      bool          `json:"logical_clock"`
+	Multipath         MultipathMode `json:"multipath"`
+	ResolveDuplicates bool          `json:"resolve_duplicates"`
+
+	Bandwidth BandwidthLimits `json:"bandwidth"`
+	Resources ResourceLimits  `json:"resources"`
+
+	ProbeSize      int           `json:"probe_size"`
+	TicketLifetime time.Duration `json:"ticket_lifetime"`
+
+	HandshakeTimeout time.Duration `json:"handshake_timeout"`
+	ReadTimeout      time.Duration `json:"read_timeout"`
+
+	MaxMessageSize int `json:"max_message_size"`
+	MaxHeaderSize  int `json:"max_header_size"`
+}
+
+// EffectiveConfig returns the configuration of the network expected to be uniform
+// across a fleet of nodes.
+func (n *Network) EffectiveConfig() EffectiveConfig {
+	config := EffectiveConfig{
+		EnvelopeVersion: EnvelopeVersion,
+
+		NetworkID:     n.NetworkID,
+		SignNetworkID: n.SignNetworkID,
+
+		SignaturePolicy: typeName(n.SignaturePolicy),
+		HashPolicy:      typeName(n.HashPolicy),
+
+		DetachedVerification: n.VerifyEnvelope != nil,
+
+		Plugins:    []string{},
+		Filters:    []string{},
+		PreFilters: []string{},
+
+		MessageTypes: []string{},
+
+		Capabilities:      n.Capabilities,
+		LogicalClock:      n.LogicalClock != nil,
+		Multipath:         n.Multipath,
+		ResolveDuplicates: n.ResolveDuplicates,
+
+		Bandwidth: n.Bandwidth.Limits(),
+		Resources: n.Resources.Limits(),
+
+		ProbeSize:      n.ProbeSize,
+		TicketLifetime: n.TicketLifetime,
+
+		HandshakeTimeout: n.HandshakeTimeout,
+		ReadTimeout:      n.ReadTimeout,
+
+		MaxMessageSize: n.MaxMessageSize,
+		MaxHeaderSize:  n.MaxHeaderSize,
+	}
+
+	if info, err := ParseAddress(n.Address); err == nil {
+		config.Protocol = info.Protocol
+	}
+
+	if n.Plugins != nil {
+		n.Plugins.Each(func(plugin PluginInterface) {
+			config.Plugins = append(config.Plugins, typeName(plugin))
+		})
+	}
+
+	for _, filter := range n.Filters {
+		config.Filters = append(config.Filters, typeName(filter))
+	}
+
+	for _, filter := range n.PreFilters {
+		config.PreFilters = append(config.PreFilters, typeName(filter))
+	}
+
+	if n.Messages != nil {
+		for _, typ := range n.Messages.Types() {
+			config.MessageTypes = append(config.MessageTypes, typ.Name)
+		}
+	}
+
+	return config
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical JSON encoding of the config,
+// such that nodes configured alike share the same fingerprint.
+func (c EffectiveConfig) Fingerprint() []byte {
+	// Struct fields are encoded in a fixed order, making the encoding canonical.
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		panic(err)
+	}
+
+	fingerprint := sha256.Sum256(encoded)
+	return fingerprint[:]
+}
+
+// observeConfigFingerprint records a config fingerprint the peer piggybacked onto a ping
+// or pong.
+func (c *PeerClient) observeConfigFingerprint(fingerprint []byte) {
+	if len(fingerprint) == 0 {
+		return
+	}
+	c.fingerprint.Store(fingerprint)
+}
+
+// ConfigFingerprint returns the config fingerprint the peer last piggybacked onto its
+// pings or pongs. Nil if the peer shared none.
+func (c *PeerClient) ConfigFingerprint() []byte {
+	fingerprint, _ := c.fingerprint.Load().([]byte)
+	return fingerprint
+}
+
+// NeighborFingerprints returns the hex-encoded config fingerprints of all connected
+// peers which shared one, by their addresses.
+func (n *Network) NeighborFingerprints() map[string]string {
+	fingerprints := make(map[string]string)
+
+	n.Peers.Range(func(key, value interface{}) bool {
+		client := value.(*PeerClient)
+
+		if fingerprint := client.ConfigFingerprint(); fingerprint != nil {
+			fingerprints[client.Address()] = hex.EncodeToString(fingerprint)
+		}
+
+		return true
+	})
+
+	return fingerprints
+}
+
+// typeName returns the name of the type of a value, or an empty string should it be nil.
+func typeName(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%T", value)
+}
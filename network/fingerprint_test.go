@@ -0,0 +1,67 @@
+package network_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func buildFingerprintedNode(t *testing.T, port uint16, networkID string) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+	builder.SetNetworkID(networkID)
+	builder.AddPlugin(new(discovery.Plugin))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestConfigFingerprint(t *testing.T) {
+	alice := buildFingerprintedNode(t, 358, "")
+	bob := buildFingerprintedNode(t, 359, "")
+	carol := buildFingerprintedNode(t, 360, "testnet")
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	if len(alice.ConfigFingerprint) == 0 || !bytes.Equal(alice.ConfigFingerprint, bob.ConfigFingerprint) {
+		t.Fatalf("expected nodes configured alike to share a fingerprint, got %x and %x", alice.ConfigFingerprint, bob.ConfigFingerprint)
+	}
+
+	if bytes.Equal(alice.ConfigFingerprint, carol.ConfigFingerprint) {
+		t.Fatal("expected nodes configured differently to have different fingerprints")
+	}
+
+	if err := alice.Bootstrap(bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+
+	for alice.NeighborFingerprints()[bob.Address] == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for bob's fingerprint")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if fingerprint := alice.NeighborFingerprints()[bob.Address]; fingerprint != hex.EncodeToString(bob.ConfigFingerprint) {
+		t.Fatalf("expected bob's fingerprint %x, got %s", bob.ConfigFingerprint, fingerprint)
+	}
+}
@@ -0,0 +1,45 @@
+package network
+
+import (
+	"net"
+
+	"github.com/perlin-network/noise/peer"
+)
+
+// ConnectionGater vetoes connections at each stage of their lifecycle. Each callback
+// returns false should the connection be rejected.
+type ConnectionGater interface {
+	// InterceptDial is called before dialing a peer's address.
+	InterceptDial(address string) bool
+
+	// InterceptAccept is called once an incoming connection is accepted, before
+	// any messages are read from it.
+	InterceptAccept(remote net.Addr) bool
+
+	// InterceptSecured is called once a peer on an incoming connection has identified
+	// itself through its first verified message.
+	InterceptSecured(id peer.ID, remote net.Addr) bool
+}
+
+// ConnectionGaterFuncs is a ConnectionGater built from a set of optional callbacks.
+// Nil callbacks allow all connections through.
+type ConnectionGaterFuncs struct {
+	Dial    func(address string) bool
+	Accept  func(remote net.Addr) bool
+	Secured func(id peer.ID, remote net.Addr) bool
+}
+
+// InterceptDial implements ConnectionGater.
+func (g ConnectionGaterFuncs) InterceptDial(address string) bool {
+	return g.Dial == nil || g.Dial(address)
+}
+
+// InterceptAccept implements ConnectionGater.
+func (g ConnectionGaterFuncs) InterceptAccept(remote net.Addr) bool {
+	return g.Accept == nil || g.Accept(remote)
+}
+
+// InterceptSecured implements ConnectionGater.
+func (g ConnectionGaterFuncs) InterceptSecured(id peer.ID, remote net.Addr) bool {
+	return g.Secured == nil || g.Secured(id, remote)
+}
@@ -0,0 +1,58 @@
+package network_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/networktest"
+	"github.com/perlin-network/noise/peer"
+)
+
+func buildGatedNode(t *testing.T, gater network.ConnectionGater) *network.Network {
+	builder := networktest.NewBuilder()
+	builder.SetConnectionGater(gater)
+
+	return networktest.Start(t, builder)
+}
+
+func TestConnectionGaterDial(t *testing.T) {
+	target := buildGatedNode(t, nil)
+	defer target.Close()
+
+	node := buildGatedNode(t, network.ConnectionGaterFuncs{
+		Dial: func(address string) bool { return address != target.Address },
+	})
+	defer node.Close()
+
+	if _, err := node.Client(target.Address); err == nil {
+		t.Fatal("expected dial to be rejected by the connection gater")
+	}
+}
+
+func TestConnectionGaterSecured(t *testing.T) {
+	node := buildGatedNode(t, nil)
+	defer node.Close()
+
+	target := buildGatedNode(t, network.ConnectionGaterFuncs{
+		Secured: func(id peer.ID, remote net.Addr) bool { return !id.Equals(node.ID) },
+	})
+	defer target.Close()
+
+	node.Bootstrap(target.Address)
+
+	time.Sleep(500 * time.Millisecond)
+
+	if _, exists := target.Peers.Load(node.Address); exists {
+		t.Fatal("expected peer to be rejected by the connection gater")
+	}
+}
+
+func TestConnectionGaterFuncsDefaults(t *testing.T) {
+	var gater network.ConnectionGaterFuncs
+
+	if !gater.InterceptDial("tcp://127.0.0.1:3000") || !gater.InterceptAccept(nil) || !gater.InterceptSecured(peer.ID{}, nil) {
+		t.Fatal("expected nil callbacks to allow all connections")
+	}
+}
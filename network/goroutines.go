@@ -0,0 +1,77 @@
+package network
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+)
+
+// GoroutineLabelKey is the pprof label key under which goroutines spawned by a network
+// are labelled, such that they may be told apart within goroutine profiles.
+const GoroutineLabelKey = "noise"
+
+// Labels of the goroutines a network spawns, under which they are counted by
+// Diagnostics.
+const (
+	GoroutineListen           = "listen"
+	GoroutineAccept           = "accept"
+	GoroutineIngest           = "ingest"
+	GoroutineHandshakeTimeout = "handshake_timeout"
+	GoroutineRecvQueue        = "recv_queue"
+	GoroutineJobs             = "jobs"
+	GoroutineHandler          = "handler"
+	GoroutineRequest          = "request"
+	GoroutineScheduler        = "scheduler"
+	GoroutineTask             = "task"
+	GoroutineReplay           = "replay"
+	GoroutineMigrations       = "migrations"
+	GoroutineOutgoing         = "outgoing"
+	GoroutineRetire           = "retire"
+	GoroutineProbe            = "probe"
+	GoroutineSlowDown         = "slowdown"
+)
+
+// goroutineTracker counts the goroutines spawned by a network which are running.
+type goroutineTracker struct {
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+func (t *goroutineTracker) add(label string, delta int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.counts == nil {
+		t.counts = make(map[string]int)
+	}
+
+	if t.counts[label] += delta; t.counts[label] == 0 {
+		delete(t.counts, label)
+	}
+}
+
+func (t *goroutineTracker) snapshot() map[string]int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	counts := make(map[string]int, len(t.counts))
+	for label, count := range t.counts {
+		counts[label] = count
+	}
+
+	return counts
+}
+
+// spawn runs f in a new goroutine, which is counted and labelled under a label for as
+// long as it runs.
+func (n *Network) spawn(label string, f func()) {
+	n.goroutines.add(label, 1)
+
+	go func() {
+		defer n.goroutines.add(label, -1)
+
+		pprof.Do(context.Background(), pprof.Labels(GoroutineLabelKey, label), func(context.Context) {
+			f()
+		})
+	}()
+}
@@ -0,0 +1,266 @@
+// Package grpcbridge bridges messages between a noise network and gRPC services.
+//
+// Selected message types received from peers are forwarded as unary calls to a gRPC
+// service, with the response being replied back to the peer should the message have
+// been a request. In the other direction, unary calls to selected gRPC methods are
+// relayed as requests to peers, with their responses being returned to the caller, and
+// gRPC service implementations may send and make requests to peers through the bridge.
+//
+// The bridge does not depend on any gRPC library. A *grpc.ClientConn is adapted to an
+// Invoker with:
+//
+//	grpcbridge.InvokerFunc(func(ctx context.Context, method string, args, reply interface{}) error {
+//		return conn.Invoke(ctx, method, args, reply)
+//	})
+//
+// and calls to methods exposed to peers are served by a *grpc.Server with:
+//
+//	grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+//		method, _ := grpc.MethodFromServerStream(stream)
+//		return bridge.ServeStream(method, stream)
+//	})
+package grpcbridge
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/pkg/errors"
+)
+
+// DefaultTimeout is the default deadline for forwarded calls and requests to peers.
+const DefaultTimeout = 5 * time.Second
+
+// Invoker makes unary calls to a gRPC service.
+type Invoker interface {
+	Invoke(ctx context.Context, method string, args interface{}, reply interface{}) error
+}
+
+// InvokerFunc adapts a function to an Invoker.
+type InvokerFunc func(ctx context.Context, method string, args interface{}, reply interface{}) error
+
+// Invoke implements Invoker.
+func (f InvokerFunc) Invoke(ctx context.Context, method string, args interface{}, reply interface{}) error {
+	return f(ctx, method, args, reply)
+}
+
+// Route forwards a message type received from peers to a gRPC method.
+type Route struct {
+	// Method is the full gRPC method name, e.g. "/chat.Chat/Send".
+	Method string
+
+	// NewReply creates the message the method responds with.
+	NewReply func() proto.Message
+
+	// Timeout of the call. Zero if the default timeout.
+	Timeout time.Duration
+}
+
+// Export relays unary calls to a gRPC method as requests to a peer.
+type Export struct {
+	// Address of the peer calls are relayed to.
+	Address string
+
+	// NewRequest creates the message the method is called with.
+	NewRequest func() proto.Message
+}
+
+// ServerStream is the subset of grpc.ServerStream calls are served over.
+type ServerStream interface {
+	Context() context.Context
+	RecvMsg(m interface{}) error
+	SendMsg(m interface{}) error
+}
+
+// Plugin bridges messages between peers and gRPC services.
+type Plugin struct {
+	*network.Plugin
+
+	Invoker Invoker
+
+	// Timeout of requests made to peers. Zero if the default timeout.
+	Timeout time.Duration
+
+	net *network.Network
+
+	mutex   sync.RWMutex
+	routes  map[string]Route
+	exports map[string]Export
+}
+
+var (
+	// PluginID to reference gRPC bridge plugin
+	PluginID = (*Plugin)(nil)
+
+	// ErrNoRoute is returned should a message type not be routed to a gRPC method.
+	ErrNoRoute = errors.New("message type is not routed to a gRPC method")
+
+	// ErrNotExported is returned should a gRPC method not be exposed to peers.
+	ErrNotExported = errors.New("gRPC method is not exposed to peers")
+)
+
+// New creates a bridge forwarding messages through an invoker.
+func New(invoker Invoker) *Plugin {
+	return &Plugin{Invoker: invoker, routes: make(map[string]Route), exports: make(map[string]Export)}
+}
+
+// Route forwards messages of a type, named by its fully-qualified protobuf name, to a
+// gRPC method.
+func (p *Plugin) Route(messageType string, route Route) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.routes == nil {
+		p.routes = make(map[string]Route)
+	}
+
+	p.routes[messageType] = route
+}
+
+// Expose relays unary calls to a gRPC method, named in full, e.g. "/chat.Chat/Send",
+// as requests to a peer.
+func (p *Plugin) Expose(method string, export Export) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.exports == nil {
+		p.exports = make(map[string]Export)
+	}
+
+	p.exports[method] = export
+}
+
+// ServeStream serves a unary call to a gRPC method exposed to peers over a stream, by
+// making a request to the peer the method is exposed to, and sending back its response.
+func (p *Plugin) ServeStream(method string, stream ServerStream) error {
+	p.mutex.RLock()
+	export, exists := p.exports[method]
+	p.mutex.RUnlock()
+
+	if !exists {
+		return errors.Wrap(ErrNotExported, method)
+	}
+
+	if export.NewRequest == nil {
+		return errors.Errorf("export of %s has no NewRequest", method)
+	}
+
+	request := export.NewRequest()
+
+	if err := stream.RecvMsg(request); err != nil {
+		return errors.Wrapf(err, "failed to receive call to %s", method)
+	}
+
+	reply, err := p.Call(stream.Context(), export.Address, request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to relay call to %s to %s", method, export.Address)
+	}
+
+	return stream.SendMsg(reply)
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.net = net
+}
+
+// Receive implements the plugin callback
+func (p *Plugin) Receive(ctx *network.PluginContext) error {
+	p.mutex.RLock()
+	route, exists := p.routes[proto.MessageName(ctx.Message())]
+	p.mutex.RUnlock()
+
+	if !exists {
+		return nil
+	}
+
+	reply, err := p.forward(route, ctx.Message())
+	if err != nil {
+		return errors.Wrapf(err, "failed to forward message from %s to %s", ctx.Client().Address(), route.Method)
+	}
+
+	// Only reply should the peer be awaiting a response.
+	if ctx.IsRequest() {
+		return ctx.Reply(reply)
+	}
+
+	return nil
+}
+
+// Forward makes a call to the gRPC method a message type is routed to, and returns
+// its response.
+func (p *Plugin) Forward(message proto.Message) (proto.Message, error) {
+	p.mutex.RLock()
+	route, exists := p.routes[proto.MessageName(message)]
+	p.mutex.RUnlock()
+
+	if !exists {
+		return nil, ErrNoRoute
+	}
+
+	return p.forward(route, message)
+}
+
+func (p *Plugin) forward(route Route, message proto.Message) (proto.Message, error) {
+	if p.Invoker == nil {
+		return nil, errors.New("no gRPC invoker configured")
+	}
+
+	timeout := route.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	if route.NewReply == nil {
+		return nil, errors.Errorf("route to %s has no NewReply", route.Method)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	reply := route.NewReply()
+
+	if err := p.Invoker.Invoke(ctx, route.Method, message, reply); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// Send sends a message to a peer on behalf of a gRPC service.
+func (p *Plugin) Send(address string, message proto.Message) error {
+	client, err := p.client(address)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Tell(message)
+	return err
+}
+
+// Call sends a request to a peer on behalf of a gRPC service, and returns its response.
+func (p *Plugin) Call(ctx context.Context, address string, message proto.Message) (proto.Message, error) {
+	client, err := p.client(address)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return client.RequestWithContext(ctx, &rpc.Request{Message: message, Timeout: timeout})
+}
+
+func (p *Plugin) client(address string) (*network.PeerClient, error) {
+	if p.net == nil {
+		return nil, errors.New("bridge has not been started by a network")
+	}
+
+	return p.net.Client(address)
+}
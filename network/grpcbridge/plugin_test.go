@@ -0,0 +1,128 @@
+package grpcbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+func TestForward(t *testing.T) {
+	var method string
+
+	plugin := New(InvokerFunc(func(ctx context.Context, m string, args interface{}, reply interface{}) error {
+		method = m
+		reply.(*protobuf.Pong).PingTimestamp = args.(*protobuf.Ping).Timestamp
+		return nil
+	}))
+
+	if _, err := plugin.Forward(&protobuf.Ping{}); err != ErrNoRoute {
+		t.Fatalf("expected unrouted message to not be forwarded, got %v", err)
+	}
+
+	plugin.Route("protobuf.Ping", Route{
+		Method:   "/noise.Health/Ping",
+		NewReply: func() proto.Message { return new(protobuf.Pong) },
+	})
+
+	reply, err := plugin.Forward(&protobuf.Ping{Timestamp: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if method != "/noise.Health/Ping" {
+		t.Fatalf("expected call to /noise.Health/Ping, got %s", method)
+	}
+
+	if pong := reply.(*protobuf.Pong); pong.PingTimestamp != 42 {
+		t.Fatalf("unexpected reply %v", pong)
+	}
+
+	// Routes which do not say what their methods respond with are not called.
+	plugin.Route("protobuf.Pong", Route{Method: "/noise.Health/Pong"})
+
+	if _, err := plugin.Forward(&protobuf.Pong{}); err == nil {
+		t.Fatal("expected message routed without NewReply to fail to be forwarded")
+	}
+}
+
+func buildNode(t *testing.T, port uint16, plugin *Plugin) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+	builder.AddPlugin(plugin)
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+// stream is a unary call to a gRPC method being served.
+type stream struct {
+	request proto.Message
+	reply   interface{}
+}
+
+func (s *stream) Context() context.Context { return context.Background() }
+
+func (s *stream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.request)
+	return nil
+}
+
+func (s *stream) SendMsg(m interface{}) error {
+	s.reply = m
+	return nil
+}
+
+func TestServeStream(t *testing.T) {
+	// Bob forwards pings to its gRPC service, which answers them with pongs.
+	bobBridge := New(InvokerFunc(func(ctx context.Context, m string, args interface{}, reply interface{}) error {
+		reply.(*protobuf.Pong).PingTimestamp = args.(*protobuf.Ping).Timestamp
+		return nil
+	}))
+	bobBridge.Route("protobuf.Ping", Route{
+		Method:   "/noise.Health/Ping",
+		NewReply: func() proto.Message { return new(protobuf.Pong) },
+	})
+
+	aliceBridge := New(nil)
+
+	alice := buildNode(t, 377, aliceBridge)
+	bob := buildNode(t, 378, bobBridge)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	if err := aliceBridge.ServeStream("/noise.Health/Ping", &stream{request: &protobuf.Ping{}}); errors.Cause(err) != ErrNotExported {
+		t.Fatalf("expected call to a method not exposed to peers to fail, got %v", err)
+	}
+
+	// Calls made to alice's gRPC service are relayed to bob.
+	aliceBridge.Expose("/noise.Health/Ping", Export{
+		Address:    bob.Address,
+		NewRequest: func() proto.Message { return new(protobuf.Ping) },
+	})
+
+	call := &stream{request: &protobuf.Ping{Timestamp: 42}}
+
+	if err := aliceBridge.ServeStream("/noise.Health/Ping", call); err != nil {
+		t.Fatal(err)
+	}
+
+	if pong, ok := call.reply.(*protobuf.Pong); !ok || pong.PingTimestamp != 42 {
+		t.Fatalf("unexpected reply %v", call.reply)
+	}
+}
@@ -0,0 +1,147 @@
+package network
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// HandshakeState is the progress of the ping/pong handshake with a peer. A peer is
+// only to be trusted with updating our routing table once its handshake is done.
+type HandshakeState uint32
+
+const (
+	// HandshakeInit is the state of a peer with which no ping has been exchanged.
+	HandshakeInit HandshakeState = iota
+	// HandshakeResponse is the state of a peer which was sent or sent us a ping, and
+	// is awaiting a pong in response.
+	HandshakeResponse
+	// HandshakeVerify is the state of a peer whose ping was responded to, and whose
+	// claimed address is being verified.
+	HandshakeVerify
+	// HandshakeDone is the state of a peer whose address was verified.
+	HandshakeDone
+)
+
+func (s HandshakeState) String() string {
+	switch s {
+	case HandshakeInit:
+		return "init"
+	case HandshakeResponse:
+		return "response"
+	case HandshakeVerify:
+		return "verify"
+	case HandshakeDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
+type handshake struct {
+	sync.Mutex
+
+	state HandshakeState
+
+	// pending is the number of pings sent to the peer which await a pong.
+	pending int
+
+	// pinged is whether the peer has pinged us.
+	pinged bool
+}
+
+// HandshakeState returns the progress of the handshake with the peer.
+func (c *PeerClient) HandshakeState() HandshakeState {
+	c.handshake.Lock()
+	defer c.handshake.Unlock()
+
+	return c.handshake.state
+}
+
+// pingSent records a ping sent to the peer, which the peer may then respond to with
+// a pong.
+func (c *PeerClient) pingSent() {
+	c.handshake.Lock()
+	defer c.handshake.Unlock()
+
+	c.handshake.pending++
+
+	if c.handshake.state == HandshakeInit {
+		c.handshake.state = HandshakeResponse
+	}
+}
+
+// pingUnsent forgets a ping recorded by pingSent which failed to be sent.
+func (c *PeerClient) pingUnsent() {
+	c.handshake.Lock()
+	defer c.handshake.Unlock()
+
+	if c.handshake.pending > 0 {
+		c.handshake.pending--
+	}
+}
+
+// ReceivePing advances the handshake upon a ping from the peer. Pings received
+// after a prior ping yet before the handshake is done are rejected as duplicates.
+func (c *PeerClient) ReceivePing() error {
+	c.handshake.Lock()
+	defer c.handshake.Unlock()
+
+	if c.handshake.pinged && c.handshake.state != HandshakeDone {
+		return errors.Wrapf(ErrHandshakeOutOfOrder, "duplicate ping from %s while handshake is in state %s", c.Address(), c.handshake.state)
+	}
+
+	c.handshake.pinged = true
+
+	if c.handshake.state == HandshakeInit {
+		c.handshake.state = HandshakeResponse
+	}
+
+	return nil
+}
+
+// ReceivePong advances the handshake upon a pong from the peer. Pongs which do not
+// respond to a ping we sent are rejected as unsolicited or duplicated.
+func (c *PeerClient) ReceivePong() error {
+	c.handshake.Lock()
+	defer c.handshake.Unlock()
+
+	if c.handshake.pending == 0 {
+		return errors.Wrapf(ErrHandshakeOutOfOrder, "unsolicited pong from %s", c.Address())
+	}
+
+	c.handshake.pending--
+
+	if c.handshake.state < HandshakeVerify {
+		c.handshake.state = HandshakeVerify
+	}
+
+	return nil
+}
+
+// VerifyHandshake completes the handshake should the peer's claimed address be
+// verified, or should unverified addresses be allowed. It is to be called once a
+// ping was either responded to by the peer, or responded to by us.
+func (c *PeerClient) VerifyHandshake(allowUnverified bool) error {
+	c.handshake.Lock()
+	defer c.handshake.Unlock()
+
+	switch c.handshake.state {
+	case HandshakeDone:
+		return nil
+	case HandshakeInit:
+		return errors.Wrapf(ErrHandshakeOutOfOrder, "no ping was exchanged with %s", c.Address())
+	}
+
+	c.handshake.state = HandshakeVerify
+
+	if !allowUnverified {
+		if err := c.VerifyAddress(); err != nil {
+			return err
+		}
+	}
+
+	c.handshake.state = HandshakeDone
+
+	return nil
+}
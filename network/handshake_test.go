@@ -0,0 +1,70 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestHandshakeInitiator(t *testing.T) {
+	client := &PeerClient{address: "tcp://127.0.0.1:3000"}
+
+	if err := errors.Cause(client.ReceivePong()); err != ErrHandshakeOutOfOrder {
+		t.Fatalf("expected unsolicited pong to be rejected, got %v", err)
+	}
+
+	client.pingSent()
+
+	if state := client.HandshakeState(); state != HandshakeResponse {
+		t.Fatalf("expected handshake to await a response, got %s", state)
+	}
+
+	if err := client.ReceivePong(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := errors.Cause(client.ReceivePong()); err != ErrHandshakeOutOfOrder {
+		t.Fatalf("expected duplicate pong to be rejected, got %v", err)
+	}
+
+	if err := client.VerifyHandshake(false); err == nil {
+		t.Fatal("expected handshake with an unidentified peer to not be verified")
+	}
+
+	if state := client.HandshakeState(); state != HandshakeVerify {
+		t.Fatalf("expected handshake to remain unverified, got %s", state)
+	}
+
+	if err := client.VerifyHandshake(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if state := client.HandshakeState(); state != HandshakeDone {
+		t.Fatalf("expected handshake to be done, got %s", state)
+	}
+}
+
+func TestHandshakeResponder(t *testing.T) {
+	client := &PeerClient{address: "tcp://127.0.0.1:3000"}
+
+	if err := errors.Cause(client.VerifyHandshake(true)); err != ErrHandshakeOutOfOrder {
+		t.Fatalf("expected handshake to not be verified before a ping, got %v", err)
+	}
+
+	if err := client.ReceivePing(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := errors.Cause(client.ReceivePing()); err != ErrHandshakeOutOfOrder {
+		t.Fatalf("expected duplicate ping to be rejected, got %v", err)
+	}
+
+	if err := client.VerifyHandshake(true); err != nil {
+		t.Fatal(err)
+	}
+
+	// Peers may keep pinging us once the handshake is done.
+	if err := client.ReceivePing(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,244 @@
+// Package httpbridge bridges a noise network to HTTP, so that scripts, dashboards
+// and integration tests may publish and subscribe to messages as JSON.
+package httpbridge
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/pkg/errors"
+)
+
+// Event is a message received from a peer, streamed to subscribers.
+type Event struct {
+	// Address of the peer the message was received from.
+	Address string `json:"address"`
+
+	// Sender is the peer ID of the sender in its canonical base58 encoding.
+	Sender string `json:"sender"`
+
+	// Type is the fully-qualified protobuf name of the message.
+	Type string `json:"type"`
+
+	Message json.RawMessage `json:"message"`
+}
+
+// DefaultMaxBodySize is the largest JSON body accepted upon a publish by default.
+const DefaultMaxBodySize = 1 << 20
+
+// Options configures a bridge.
+type Options struct {
+	// Token is the bearer token requests must present in their Authorization header.
+	// Requests are not authenticated should it be empty.
+	Token string
+
+	// Types are the fully-qualified protobuf names of the message types which may be
+	// published. No messages may be published should it be empty.
+	Types []string
+
+	// MaxBodySize is the largest JSON body accepted upon a publish. DefaultMaxBodySize
+	// if zero.
+	MaxBodySize int64
+}
+
+func (o Options) maxBodySize() int64 {
+	if o.MaxBodySize <= 0 {
+		return DefaultMaxBodySize
+	}
+	return o.MaxBodySize
+}
+
+// authorized returns true should a request present the token, or should no token be
+// required.
+func (o Options) authorized(r *http.Request) bool {
+	if len(o.Token) == 0 {
+		return true
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(o.Token)) == 1
+}
+
+// publishable returns true should messages of a type be allowed to be published.
+func (o Options) publishable(name string) bool {
+	for _, typ := range o.Types {
+		if typ == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PublishResult lists the IDs of messages sent upon a publish.
+type PublishResult struct {
+	IDs []string `json:"ids"`
+}
+
+// NewHandler returns an HTTP handler bridging a network to HTTP. All requests must
+// present the bearer token of the options should it be set.
+//
+// POST /publish?type=<name>[&address=<address>...] converts the JSON body to the
+// registered protobuf message type name, and sends it to the given peers, or all
+// peers should none be given. Only types allowed by the options may be published.
+//
+// GET /subscribe[?type=<name>...][&address=<address>...] streams messages received
+// from peers as server-sent events, each holding a JSON-encoded Event.
+func NewHandler(net *network.Network, options Options) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/publish", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "expected POST", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if name := r.URL.Query().Get("type"); !options.publishable(name) {
+			http.Error(w, fmt.Sprintf("messages of type %q may not be published", name), http.StatusForbidden)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, options.maxBodySize())
+
+		message, err := decode(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var ids []network.MessageID
+
+		if addresses := r.URL.Query()["address"]; len(addresses) > 0 {
+			ids = net.BroadcastByAddresses(message, addresses...)
+		} else {
+			ids = net.Broadcast(message)
+		}
+
+		result := PublishResult{IDs: make([]string, 0, len(ids))}
+		for _, id := range ids {
+			result.IDs = append(result.IDs, id.String())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			glog.Error(err)
+		}
+	})
+
+	mux.HandleFunc("/subscribe", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		messages, closeTap := net.Tap(network.TapFilter{
+			Types:     r.URL.Query()["type"],
+			Addresses: r.URL.Query()["address"],
+			Inbound:   true,
+		})
+		defer closeTap()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case tapped, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				event, err := encode(tapped)
+				if err != nil {
+					glog.Warning(err)
+					continue
+				}
+
+				if _, err := fmt.Fprintf(w, "data: %s\n\n", event); err != nil {
+					return
+				}
+
+				flusher.Flush()
+			}
+		}
+	})
+
+	return authenticate(options, mux)
+}
+
+// authenticate rejects requests to a handler which do not present the token of the
+// options.
+func authenticate(options Options, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !options.authorized(r) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// decode converts the JSON body of a request to the protobuf message type it names.
+func decode(r *http.Request) (proto.Message, error) {
+	name := r.URL.Query().Get("type")
+
+	typ := proto.MessageType(name)
+	if typ == nil {
+		return nil, errors.Errorf("unknown message type %q", name)
+	}
+
+	message := reflect.New(typ.Elem()).Interface().(proto.Message)
+
+	if err := jsonpb.Unmarshal(r.Body, message); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s", name)
+	}
+
+	return message, nil
+}
+
+// encode renders a tapped message as a JSON-encoded Event.
+func encode(tapped *network.TappedMessage) ([]byte, error) {
+	var ptr ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(tapped.Message.Message, &ptr); err != nil {
+		return nil, errors.Wrap(err, "failed to decode tapped message")
+	}
+
+	var message bytes.Buffer
+	if err := new(jsonpb.Marshaler).Marshal(&message, ptr.Message); err != nil {
+		return nil, errors.Wrap(err, "failed to encode tapped message")
+	}
+
+	event := Event{
+		Address: tapped.Address,
+		Type:    proto.MessageName(ptr.Message),
+		Message: message.Bytes(),
+	}
+
+	if tapped.Message.Sender != nil {
+		event.Sender = peer.ID(*tapped.Message.Sender).String()
+	}
+
+	return json.Marshal(event)
+}
+
+// ListenAndServe serves the bridge of a network over HTTP on an address.
+func ListenAndServe(address string, net *network.Network, options Options) error {
+	return http.ListenAndServe(address, NewHandler(net, options))
+}
@@ -0,0 +1,139 @@
+package httpbridge
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func buildNode(t *testing.T, port uint16) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", port))
+	builder.AddPlugin(new(discovery.Plugin))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go node.Listen()
+	node.BlockUntilListening()
+
+	return node
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	alice := buildNode(t, 13070)
+	bob := buildNode(t, 13071)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	alice.Bootstrap(bob.Address)
+
+	publisher := httptest.NewServer(NewHandler(alice, Options{Types: []string{"protobuf.Pong"}}))
+	defer publisher.Close()
+
+	subscriber := httptest.NewServer(NewHandler(bob, Options{}))
+	defer subscriber.Close()
+
+	res, err := http.Get(subscriber.URL + "/subscribe?type=protobuf.Pong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	query := url.Values{"type": {"protobuf.Pong"}, "address": {bob.Address}}
+
+	published, err := http.Post(publisher.URL+"/publish?"+query.Encode(), "application/json", strings.NewReader(`{"pingTimestamp": "42"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	published.Body.Close()
+
+	if published.StatusCode != http.StatusOK {
+		t.Fatalf("expected message to be published, got status %d", published.StatusCode)
+	}
+
+	events := make(chan Event, 1)
+
+	go func() {
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				var event Event
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err == nil {
+					events <- event
+					return
+				}
+			}
+		}
+	}()
+
+	select {
+	case event := <-events:
+		if event.Type != "protobuf.Pong" || !strings.Contains(string(event.Message), "42") {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for published message")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	node := buildNode(t, 13248)
+	defer node.Close()
+
+	server := httptest.NewServer(NewHandler(node, Options{Token: "secret", Types: []string{"protobuf.Pong"}, MaxBodySize: 64}))
+	defer server.Close()
+
+	publish := func(token string, typ string, body string) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/publish?type="+typ, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		return res.StatusCode
+	}
+
+	if status := publish("", "protobuf.Pong", "{}"); status != http.StatusUnauthorized {
+		t.Fatalf("expected requests without the token to be unauthorized, got status %d", status)
+	}
+
+	if status := publish("wrong", "protobuf.Pong", "{}"); status != http.StatusUnauthorized {
+		t.Fatalf("expected requests with the wrong token to be unauthorized, got status %d", status)
+	}
+
+	if status := publish("secret", "protobuf.Ping", "{}"); status != http.StatusForbidden {
+		t.Fatalf("expected types not allowed to be published to be forbidden, got status %d", status)
+	}
+
+	if status := publish("secret", "protobuf.Pong", `{"pingTimestamp": "`+strings.Repeat("4", 128)+`"}`); status != http.StatusBadRequest {
+		t.Fatalf("expected bodies exceeding the maximum size to be rejected, got status %d", status)
+	}
+
+	if status := publish("secret", "protobuf.Pong", `{"pingTimestamp": "42"}`); status != http.StatusOK {
+		t.Fatalf("expected an allowed type to be published, got status %d", status)
+	}
+}
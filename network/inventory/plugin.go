@@ -0,0 +1,350 @@
+// Package inventory relays items such as transactions across the network, in the
+// manner of a mempool. Peers announce the hashes of items they have, and items are
+// only fetched from a peer should they be unknown, so that each item is transferred
+// to each peer about once. Items are validated by the application before they are
+// accepted and relayed, and items which fail validation are banned.
+package inventory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/lru"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultKnownCapacity is the number of item hashes remembered as known to each peer.
+	DefaultKnownCapacity = 16384
+
+	// DefaultBannedCapacity is the number of banned item hashes remembered.
+	DefaultBannedCapacity = 65536
+
+	// DefaultTimeout is how long a peer is given to respond to a request for items.
+	DefaultTimeout = 5 * time.Second
+)
+
+// Validator validates an item, returning an error should it be invalid.
+type Validator func(data []byte) error
+
+// Plugin relays items across the network.
+type Plugin struct {
+	*network.Plugin
+
+	// Validate validates items before they are accepted and relayed.
+	Validate Validator
+
+	// Hash hashes items. Nil if SHA-256.
+	Hash func(data []byte) []byte
+
+	// OnAccept is called for every new item accepted from a peer. Nil if unused.
+	OnAccept func(hash []byte, data []byte)
+
+	// KnownCapacity is the number of item hashes remembered as known to each peer,
+	// and Timeout is how long peers are given to respond. Zero if the defaults.
+	KnownCapacity int
+	Timeout       time.Duration
+
+	net *network.Network
+
+	mutex    sync.Mutex
+	items    map[string][]byte
+	inflight map[string]struct{}
+
+	banned *lru.Cache
+}
+
+var (
+	// PluginID to reference inventory plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// namespace namespaces the values the plugin attaches to peers.
+const namespace network.Namespace = "inventory"
+
+// New creates an inventory plugin validating items with a validator.
+func New(validate Validator) *Plugin {
+	return &Plugin{
+		Validate: validate,
+		items:    make(map[string][]byte),
+		inflight: make(map[string]struct{}),
+		banned:   lru.NewCache(DefaultBannedCapacity),
+	}
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.net = net
+
+	if err := net.RegisterMessages(new(protobuf.InventoryAnnouncement), new(protobuf.InventoryRequest), new(protobuf.InventoryItems)); err != nil {
+		glog.Warningf("inventory: failed to register message types [err=%s]", err)
+	}
+}
+
+func (p *Plugin) hash(data []byte) []byte {
+	if p.Hash != nil {
+		return p.Hash(data)
+	}
+
+	hash := sha256.Sum256(data)
+	return hash[:]
+}
+
+func (p *Plugin) timeout() time.Duration {
+	if p.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return p.Timeout
+}
+
+func key(hash []byte) string {
+	return hex.EncodeToString(hash)
+}
+
+// Add validates and adds an item, and announces it to all peers. It returns the hash
+// of the item.
+func (p *Plugin) Add(data []byte) ([]byte, error) {
+	hash := p.hash(data)
+
+	if p.Banned(hash) {
+		return nil, errors.Errorf("item %x is banned", hash)
+	}
+
+	if err := p.Validate(data); err != nil {
+		p.Ban(hash)
+		return nil, errors.Wrapf(err, "item %x is invalid", hash)
+	}
+
+	p.mutex.Lock()
+	p.items[key(hash)] = data
+	p.mutex.Unlock()
+
+	p.announce([][]byte{hash}, "")
+
+	return hash, nil
+}
+
+// Get returns an item by its hash.
+func (p *Plugin) Get(hash []byte) ([]byte, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	data, exists := p.items[key(hash)]
+	return data, exists
+}
+
+// Len returns the number of items held.
+func (p *Plugin) Len() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return len(p.items)
+}
+
+// Remove removes an item by its hash, e.g. once it has been included in a block.
+// Peers are still remembered to know of it.
+func (p *Plugin) Remove(hash []byte) {
+	p.mutex.Lock()
+	delete(p.items, key(hash))
+	p.mutex.Unlock()
+}
+
+// Ban removes an item by its hash, and prevents it from being fetched or added again.
+func (p *Plugin) Ban(hash []byte) {
+	p.Remove(hash)
+
+	p.banned.Get(key(hash), func() (interface{}, error) {
+		return struct{}{}, nil
+	})
+}
+
+// Banned returns true should an item by its hash be banned.
+func (p *Plugin) Banned(hash []byte) bool {
+	return p.banned.Contains(key(hash))
+}
+
+// Known returns true should a peer by its address be known to have an item by its hash.
+func (p *Plugin) Known(address string, hash []byte) bool {
+	if p.net == nil {
+		return false
+	}
+
+	client, exists := p.net.Peers.Load(address)
+	if !exists {
+		return false
+	}
+
+	known, exists := client.(*network.PeerClient).Values().Get(namespace, "known")
+	return exists && known.(*lru.Cache).Contains(key(hash))
+}
+
+// markKnown remembers that a peer has items by their hashes.
+func (p *Plugin) markKnown(client *network.PeerClient, hashes ...[]byte) {
+	known := client.Values().GetOrSet(namespace, "known", func() interface{} {
+		capacity := p.KnownCapacity
+		if capacity <= 0 {
+			capacity = DefaultKnownCapacity
+		}
+
+		return lru.NewCache(capacity)
+	}).(*lru.Cache)
+
+	for _, hash := range hashes {
+		known.Get(key(hash), func() (interface{}, error) {
+			return struct{}{}, nil
+		})
+	}
+}
+
+// announce announces items by their hashes to all peers not known to have them,
+// except for a peer by its address.
+func (p *Plugin) announce(hashes [][]byte, except string) {
+	if p.net == nil {
+		return
+	}
+
+	p.net.Peers.Range(func(k, value interface{}) bool {
+		client := value.(*network.PeerClient)
+
+		if client.Address() == except {
+			return true
+		}
+
+		var unknown [][]byte
+
+		for _, hash := range hashes {
+			if !p.Known(client.Address(), hash) {
+				unknown = append(unknown, hash)
+			}
+		}
+
+		if len(unknown) == 0 {
+			return true
+		}
+
+		p.markKnown(client, unknown...)
+
+		if _, err := client.Tell(&protobuf.InventoryAnnouncement{Hashes: unknown}); err != nil {
+			glog.Warningf("inventory: failed to announce items to %s [err=%s]", client.Address(), err)
+		}
+
+		return true
+	})
+}
+
+// Receive implements the plugin callback
+func (p *Plugin) Receive(ctx *network.PluginContext) error {
+	switch msg := ctx.Message().(type) {
+	case *protobuf.InventoryAnnouncement:
+		p.markKnown(ctx.Client(), msg.Hashes...)
+
+		if wanted := p.want(msg.Hashes); len(wanted) > 0 {
+			return p.fetch(ctx.Client(), wanted)
+		}
+	case *protobuf.InventoryRequest:
+		response := new(protobuf.InventoryItems)
+
+		for _, hash := range msg.Hashes {
+			if data, exists := p.Get(hash); exists {
+				response.Items = append(response.Items, data)
+			}
+		}
+
+		return ctx.Reply(response)
+	}
+
+	return nil
+}
+
+// want returns and marks as in flight the hashes of items we neither have, nor are
+// banned, nor are already being fetched.
+func (p *Plugin) want(hashes [][]byte) (wanted map[string][]byte) {
+	wanted = make(map[string][]byte)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, hash := range hashes {
+		k := key(hash)
+
+		if _, exists := p.items[k]; exists {
+			continue
+		}
+
+		if _, fetching := p.inflight[k]; fetching || p.banned.Contains(k) {
+			continue
+		}
+
+		p.inflight[k] = struct{}{}
+		wanted[k] = hash
+	}
+
+	return
+}
+
+// fetch fetches wanted items from a peer, accepting and relaying those which are valid.
+func (p *Plugin) fetch(client *network.PeerClient, wanted map[string][]byte) error {
+	defer func() {
+		p.mutex.Lock()
+		for k := range wanted {
+			delete(p.inflight, k)
+		}
+		p.mutex.Unlock()
+	}()
+
+	request := new(rpc.Request)
+	request.SetTimeout(p.timeout())
+
+	hashes := make([][]byte, 0, len(wanted))
+	for _, hash := range wanted {
+		hashes = append(hashes, hash)
+	}
+	request.SetMessage(&protobuf.InventoryRequest{Hashes: hashes})
+
+	response, err := client.Request(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch items from %s", client.Address())
+	}
+
+	items, ok := response.(*protobuf.InventoryItems)
+	if !ok {
+		return errors.Errorf("peer %s responded to an inventory request with %T", client.Address(), response)
+	}
+
+	var accepted [][]byte
+
+	for _, data := range items.Items {
+		hash := p.hash(data)
+
+		// Ignore items we did not ask for.
+		if _, requested := wanted[key(hash)]; !requested {
+			continue
+		}
+
+		if err := p.Validate(data); err != nil {
+			glog.Warningf("inventory: banned invalid item %x from %s [err=%s]", hash, client.Address(), err)
+			p.Ban(hash)
+			continue
+		}
+
+		p.mutex.Lock()
+		p.items[key(hash)] = data
+		p.mutex.Unlock()
+
+		if p.OnAccept != nil {
+			p.OnAccept(hash, data)
+		}
+
+		accepted = append(accepted, hash)
+	}
+
+	p.announce(accepted, client.Address())
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package inventory
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/networktest"
+	"github.com/pkg/errors"
+)
+
+func rejectBad(data []byte) error {
+	if bytes.HasPrefix(data, []byte("bad")) {
+		return errors.New("bad item")
+	}
+	return nil
+}
+
+func acceptAll(data []byte) error {
+	return nil
+}
+
+func buildNode(t *testing.T, validate Validator) (*network.Network, *Plugin) {
+	plugin := New(validate)
+	return networktest.NewNode(t, new(discovery.Plugin), plugin), plugin
+}
+
+// eventually polls condition until it holds, or fails the test after a timeout.
+func eventually(t *testing.T, description string, condition func() bool) {
+	deadline := time.Now().Add(3 * time.Second)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", description)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestRelay(t *testing.T) {
+	// Alice relays anything, whereas bob and carol reject bad items.
+	alice, aliceInventory := buildNode(t, acceptAll)
+	bob, bobInventory := buildNode(t, rejectBad)
+	carol, carolInventory := buildNode(t, rejectBad)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	// Alice and carol are only connected through bob.
+	alice.Bootstrap(bob.Address)
+	carol.Bootstrap(bob.Address)
+
+	time.Sleep(200 * time.Millisecond)
+
+	accepted := make(chan []byte, 4)
+	carolInventory.OnAccept = func(hash []byte, data []byte) {
+		accepted <- data
+	}
+
+	hash, err := aliceInventory.Add([]byte("tx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case data := <-accepted:
+		if string(data) != "tx" {
+			t.Fatalf("unexpected item %q", data)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for item to be relayed")
+	}
+
+	if !aliceInventory.Known(bob.Address, hash) {
+		t.Fatal("expected alice to remember that bob knows of the item")
+	}
+
+	eventually(t, "bob to know carol has the item", func() bool {
+		return bobInventory.Known(carol.Address, hash)
+	})
+
+	bad, err := aliceInventory.Add([]byte("bad tx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventually(t, "bob to ban the bad item", func() bool {
+		return bobInventory.Banned(bad)
+	})
+
+	if _, exists := carolInventory.Get(bad); exists {
+		t.Fatal("expected bad item to not be relayed past bob")
+	}
+
+	if _, err := bobInventory.Add([]byte("bad tx")); err == nil {
+		t.Fatal("expected banned item to not be added")
+	}
+}
@@ -0,0 +1,190 @@
+// Package kafka mirrors messages received from peers and pubsub topics to Kafka for
+// archiving and analytics, and optionally injects Kafka records into pubsub topics.
+//
+// The connector does not depend on any Kafka client library; thin wrappers around a
+// client library satisfy Producer and Consumer.
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/pubsub"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+// Producer publishes records to Kafka topics.
+type Producer interface {
+	Produce(topic string, key []byte, value []byte) error
+	Close() error
+}
+
+// Consumer consumes records from Kafka topics, calling a handler for each record
+// until closed.
+type Consumer interface {
+	Consume(topic string, handler func(key []byte, value []byte)) error
+	Close() error
+}
+
+// Record is the JSON encoding of a message received from a peer mirrored to Kafka.
+type Record struct {
+	Time time.Time `json:"time"`
+
+	// Address of the peer the message was received from.
+	Address string `json:"address"`
+
+	// Sender is the peer ID of the sender in its canonical base58 encoding.
+	Sender string `json:"sender"`
+
+	// Type is the fully-qualified protobuf name of the message.
+	Type string `json:"type"`
+
+	Message json.RawMessage `json:"message"`
+}
+
+// Plugin mirrors selected message types and pubsub topics to Kafka topics, and injects
+// records of selected Kafka topics into pubsub topics.
+type Plugin struct {
+	*network.Plugin
+
+	Producer Producer
+
+	// Consumer of records to inject. Nil if no records are injected.
+	Consumer Consumer
+
+	// PubSub the topics are mirrored from and injected into. Nil if only message
+	// types are mirrored.
+	PubSub *pubsub.Plugin
+
+	// MessageTypes maps fully-qualified protobuf names of messages received from peers
+	// to the Kafka topics they are mirrored to as JSON records, keyed by sender.
+	MessageTypes map[string]string
+
+	// Topics maps pubsub topics to the Kafka topics their data is mirrored to, keyed
+	// by origin.
+	Topics map[string]string
+
+	// Inject maps Kafka topics to the pubsub topics their records are published to.
+	Inject map[string]string
+
+	net           *network.Network
+	unsubscribers []func()
+}
+
+var (
+	// PluginID to reference Kafka connector plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// New creates a connector mirroring to Kafka through a producer.
+func New(producer Producer) *Plugin {
+	return &Plugin{
+		Producer:     producer,
+		MessageTypes: make(map[string]string),
+		Topics:       make(map[string]string),
+		Inject:       make(map[string]string),
+	}
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.net = net
+
+	if err := p.start(); err != nil {
+		glog.Error(err)
+	}
+}
+
+func (p *Plugin) start() error {
+	if (len(p.Topics) > 0 || len(p.Inject) > 0) && p.PubSub == nil {
+		return errors.New("kafka connector requires pubsub to mirror or inject topics")
+	}
+
+	for topic, kafkaTopic := range p.Topics {
+		messages, unsubscribe := p.PubSub.Subscribe(topic)
+		p.unsubscribers = append(p.unsubscribers, unsubscribe)
+
+		go p.mirrorTopic(kafkaTopic, messages)
+	}
+
+	if len(p.Inject) > 0 && p.Consumer == nil {
+		return errors.New("kafka connector requires a consumer to inject records")
+	}
+
+	for kafkaTopic, topic := range p.Inject {
+		topic := topic
+
+		err := p.Consumer.Consume(kafkaTopic, func(key []byte, value []byte) {
+			if err := p.PubSub.Publish(topic, value); err != nil {
+				glog.Warningf("Failed to inject Kafka topic %s into topic %s [err=%s]", kafkaTopic, topic, err)
+			}
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to consume Kafka topic %s", kafkaTopic)
+		}
+	}
+
+	return nil
+}
+
+// mirrorTopic mirrors data published to a pubsub topic to a Kafka topic.
+func (p *Plugin) mirrorTopic(kafkaTopic string, messages <-chan *pubsub.Message) {
+	for msg := range messages {
+		if err := p.Producer.Produce(kafkaTopic, []byte(msg.Origin), msg.Data); err != nil {
+			glog.Warningf("Failed to mirror topic %s to Kafka topic %s [err=%s]", msg.Topic, kafkaTopic, err)
+		}
+	}
+}
+
+// Receive implements the plugin callback
+func (p *Plugin) Receive(ctx *network.PluginContext) error {
+	name := proto.MessageName(ctx.Message())
+
+	kafkaTopic, exists := p.MessageTypes[name]
+	if !exists {
+		return nil
+	}
+
+	var message bytes.Buffer
+	if err := new(jsonpb.Marshaler).Marshal(&message, ctx.Message()); err != nil {
+		return errors.Wrapf(err, "failed to encode %s", name)
+	}
+
+	sender := ctx.Sender().String()
+
+	value, err := json.Marshal(Record{
+		Time:    clock.Or(ctx.Network().Clock).Now(),
+		Address: ctx.Client().Address(),
+		Sender:  sender,
+		Type:    name,
+		Message: message.Bytes(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode %s", name)
+	}
+
+	return p.Producer.Produce(kafkaTopic, []byte(sender), value)
+}
+
+// Cleanup implements the plugin callback
+func (p *Plugin) Cleanup(net *network.Network) {
+	for _, unsubscribe := range p.unsubscribers {
+		unsubscribe()
+	}
+
+	if p.Consumer != nil {
+		if err := p.Consumer.Close(); err != nil {
+			glog.Error(err)
+		}
+	}
+
+	if err := p.Producer.Close(); err != nil {
+		glog.Error(err)
+	}
+}
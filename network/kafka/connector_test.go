@@ -0,0 +1,99 @@
+package kafka
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/networktest"
+	"github.com/perlin-network/noise/network/pubsub"
+)
+
+type produced struct {
+	topic string
+	key   []byte
+	value []byte
+}
+
+type mockProducer struct {
+	records chan produced
+}
+
+func (p *mockProducer) Produce(topic string, key []byte, value []byte) error {
+	p.records <- produced{topic: topic, key: key, value: value}
+	return nil
+}
+
+func (p *mockProducer) Close() error {
+	return nil
+}
+
+type mockConsumer struct {
+	handlers map[string]func(key []byte, value []byte)
+}
+
+func (c *mockConsumer) Consume(topic string, handler func(key []byte, value []byte)) error {
+	c.handlers[topic] = handler
+	return nil
+}
+
+func (c *mockConsumer) Close() error {
+	return nil
+}
+
+func buildNode(t *testing.T, plugins ...network.PluginInterface) *network.Network {
+	return networktest.NewNode(t, append([]network.PluginInterface{new(discovery.Plugin)}, plugins...)...)
+}
+
+func TestConnector(t *testing.T) {
+	producer := &mockProducer{records: make(chan produced, 16)}
+	consumer := &mockConsumer{handlers: make(map[string]func([]byte, []byte))}
+
+	alicePubSub := pubsub.New()
+
+	connector := New(producer)
+	connector.Consumer = consumer
+	connector.PubSub = alicePubSub
+	connector.MessageTypes["protobuf.Ping"] = "pings"
+	connector.Inject["commands"] = "commands"
+
+	alice := buildNode(t, alicePubSub, connector)
+
+	bobPubSub := pubsub.New()
+	bob := buildNode(t, bobPubSub)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	commands, unsubscribe := bobPubSub.Subscribe("commands")
+	defer unsubscribe()
+
+	bob.Bootstrap(alice.Address)
+
+	select {
+	case record := <-producer.records:
+		var decoded Record
+		if err := json.Unmarshal(record.value, &decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		if record.topic != "pings" || decoded.Type != "protobuf.Ping" || decoded.Sender != bob.ID.String() {
+			t.Fatalf("unexpected record %+v", decoded)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for ping to be mirrored")
+	}
+
+	consumer.handlers["commands"](nil, []byte("restart"))
+
+	select {
+	case msg := <-commands:
+		if string(msg.Data) != "restart" {
+			t.Fatalf("unexpected injected data %q", msg.Data)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for record to be injected")
+	}
+}
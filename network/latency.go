@@ -0,0 +1,80 @@
+package network
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// Phase is a phase of sending or handling a message whose latency is observed.
+type Phase uint8
+
+const (
+	// PhaseSerialize is the marshaling of a message into an envelope.
+	PhaseSerialize Phase = iota
+	// PhaseSign is the signing of an envelope.
+	PhaseSign
+	// PhaseWrite is the writing of an envelope to a peer's stream.
+	PhaseWrite
+	// PhaseHandle is the execution of all plugins' Receive callbacks on a message.
+	PhaseHandle
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseSerialize:
+		return "serialize"
+	case PhaseSign:
+		return "sign"
+	case PhaseWrite:
+		return "write"
+	case PhaseHandle:
+		return "handle"
+	default:
+		return "unknown"
+	}
+}
+
+// LatencyObserver is implemented by plugins which observe how long each phase of
+// sending and handling messages takes, such as the metrics plugin.
+type LatencyObserver interface {
+	ObserveLatency(phase Phase, messageType string, latency time.Duration)
+}
+
+// latencyObservers returns all registered plugins which implement LatencyObserver.
+func (n *Network) latencyObservers() (observers []LatencyObserver) {
+	if n.Plugins == nil {
+		return
+	}
+
+	n.Plugins.Each(func(plugin PluginInterface) {
+		if observer, ok := plugin.(LatencyObserver); ok {
+			observers = append(observers, observer)
+		}
+	})
+
+	return
+}
+
+// observeLatency reports the latency of a phase on a message of a type since start to
+// all latency observers. Latencies are measured by the system clock.
+func (n *Network) observeLatency(phase Phase, messageType string, start time.Time) {
+	n.observersOnce.Do(func() {
+		n.observers = n.latencyObservers()
+	})
+
+	if len(n.observers) == 0 {
+		return
+	}
+
+	latency := n.clock().Since(start)
+
+	for _, observer := range n.observers {
+		observer.ObserveLatency(phase, messageType, latency)
+	}
+}
+
+// messageType returns the name of a message's type, such as protobuf.Ping.
+func messageType(message proto.Message) string {
+	return proto.MessageName(message)
+}
@@ -0,0 +1,170 @@
+// Package leaktest checks for goroutines leaked by networks within tests.
+//
+// Check may be used on its own, or alongside go.uber.org/goleak by ignoring
+// IgnoredTopFunctions:
+//
+//	for _, f := range leaktest.IgnoredTopFunctions {
+//		options = append(options, goleak.IgnoreTopFunction(f))
+//	}
+//	goleak.VerifyNone(t, options...)
+package leaktest
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+)
+
+// Timeout is how long goroutines are waited on to exit before being reported as leaked.
+var Timeout = 5 * time.Second
+
+// IgnoredTopFunctions are the functions at the top of the stacks of goroutines which
+// are meant to outlive networks, and are hence not leaks.
+var IgnoredTopFunctions = []string{
+	"github.com/golang/glog.(*loggingT).flushDaemon",
+	"github.com/perlin-network/noise/network.(*WorkerPool).work",
+}
+
+// pkgPrefix prefixes the functions within stacks of goroutines spawned by noise.
+const pkgPrefix = "github.com/perlin-network/noise/"
+
+// TB is the subset of testing.TB leaks are reported through.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Check snapshots the goroutines running, and returns a function which reports goroutines
+// spawned by noise since which have not exited within Timeout. It is meant to be
+// deferred at the start of tests:
+//
+//	defer leaktest.Check(t)()
+func Check(t TB) func() {
+	before := make(map[string]struct{})
+	for _, g := range goroutines() {
+		before[g.id] = struct{}{}
+	}
+
+	return func() {
+		t.Helper()
+
+		var leaked []goroutine
+
+		deadline := time.Now().Add(Timeout)
+
+		for {
+			leaked = leaked[:0]
+
+			for _, g := range goroutines() {
+				if _, existed := before[g.id]; !existed && g.leaked() {
+					leaked = append(leaked, g)
+				}
+			}
+
+			if len(leaked) == 0 || time.Now().After(deadline) {
+				break
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		for _, g := range leaked {
+			t.Errorf("leaked goroutine: %s", g.stack)
+		}
+	}
+}
+
+// CheckNetwork reports should any goroutines spawned by a closed network not exit
+// within Timeout, alongside a dump of the network's diagnostics.
+func CheckNetwork(t TB, net *network.Network) {
+	t.Helper()
+
+	deadline := time.Now().Add(Timeout)
+
+	for {
+		diagnostics := net.Diagnostics()
+
+		if diagnostics.TotalGoroutines() == 0 {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Errorf("network %s leaked %d goroutine(s):\n%s", net.Address, diagnostics.TotalGoroutines(), diagnostics)
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+type goroutine struct {
+	id    string
+	top   string
+	stack string
+}
+
+// leaked returns true should the goroutine have been spawned by noise, and not be
+// meant to outlive networks.
+func (g goroutine) leaked() bool {
+	if !strings.Contains(g.stack, pkgPrefix) {
+		return false
+	}
+
+	for _, f := range IgnoredTopFunctions {
+		if g.top == f {
+			return false
+		}
+	}
+
+	return true
+}
+
+// goroutines returns all goroutines running but the caller's.
+func goroutines() []goroutine {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := bytes.Split(buf, []byte("\n\n"))
+
+	parsed := make([]goroutine, 0, len(stacks))
+
+	// The first stack is the caller's.
+	for _, stack := range stacks[1:] {
+		if g, ok := parseGoroutine(string(stack)); ok {
+			parsed = append(parsed, g)
+		}
+	}
+
+	return parsed
+}
+
+// parseGoroutine parses a goroutine's id and top function out of its stack, which
+// starts with a header such as "goroutine 7 [running]:".
+func parseGoroutine(stack string) (goroutine, bool) {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "goroutine ") {
+		return goroutine{}, false
+	}
+
+	fields := strings.Fields(lines[0])
+	if len(fields) < 2 {
+		return goroutine{}, false
+	}
+
+	top := lines[1]
+	if i := strings.LastIndex(top, "("); i > 0 {
+		top = top[:i]
+	}
+
+	return goroutine{id: fields[1], top: top, stack: stack}, true
+}
@@ -0,0 +1,99 @@
+package leaktest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+)
+
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func buildMemoryNode(t *testing.T, port uint16) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestClosedNetworksDoNotLeak(t *testing.T) {
+	defer Check(t)()
+
+	alice := buildMemoryNode(t, 200)
+	bob := buildMemoryNode(t, 201)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if _, err := alice.Ping(ctx, bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	if diagnostics := alice.Diagnostics(); diagnostics.Goroutines[network.GoroutineAccept] == 0 {
+		t.Fatalf("expected goroutines accepting connections to be counted, got:\n%s", diagnostics)
+	}
+
+	alice.Close()
+	bob.Close()
+
+	CheckNetwork(t, alice)
+	CheckNetwork(t, bob)
+}
+
+func TestCheckReportsLeaks(t *testing.T) {
+	defer func(timeout time.Duration) { Timeout = timeout }(Timeout)
+	Timeout = 50 * time.Millisecond
+
+	r := new(recorder)
+	check := Check(r)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() { <-done }()
+
+	check()
+
+	if len(r.errors) != 1 {
+		t.Fatalf("expected 1 leaked goroutine to be reported, got %d: %v", len(r.errors), r.errors)
+	}
+}
+
+func TestParseGoroutine(t *testing.T) {
+	stack := "goroutine 7 [chan receive]:\ngithub.com/perlin-network/noise/network.(*WorkerPool).work(0xc000010000)\n\t/network/workers.go:51 +0x3b"
+
+	g, ok := parseGoroutine(stack)
+	if !ok {
+		t.Fatal("expected stack to be parsed")
+	}
+
+	if g.id != "7" || g.top != "github.com/perlin-network/noise/network.(*WorkerPool).work" {
+		t.Fatalf("unexpected goroutine parsed: id=%q top=%q", g.id, g.top)
+	}
+
+	if g.leaked() {
+		t.Fatal("expected worker pool goroutines to be ignored")
+	}
+}
@@ -0,0 +1,98 @@
+package network_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/networktest"
+	"github.com/pkg/errors"
+)
+
+func TestListenFailure(t *testing.T) {
+	alice := buildNode(t, 13192, new(discovery.Plugin))
+	defer alice.Close()
+
+	// Bob attempts to listen on the same port as alice.
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", 13192))
+	builder.AddPlugin(new(discovery.Plugin))
+
+	bob, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bob.Start(); err == nil {
+		t.Fatal("expected listening on a port already in use to fail")
+	}
+
+	if err := bob.Bootstrap(alice.Address); err == nil {
+		t.Fatal("expected bootstrapping a network which failed to listen to fail")
+	}
+}
+
+func TestListenOnFreePort(t *testing.T) {
+	alice := networktest.NewNode(t, new(discovery.Plugin))
+	defer alice.Close()
+
+	info, err := network.ParseAddress(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Port == 0 || alice.ID.Address != alice.Address {
+		t.Fatalf("expected alice to adopt the port she listens on, got %s with ID address %s", alice.Address, alice.ID.Address)
+	}
+
+	bob := networktest.NewNode(t, new(discovery.Plugin))
+	defer bob.Close()
+
+	if err := bob.Bootstrap(alice.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, "bob to connect to alice", func() bool {
+		_, connected := alice.Peers.Load(bob.Address)
+		return connected
+	})
+}
+
+func TestCloseZeroesKeys(t *testing.T) {
+	keys := ed25519.RandomKeyPair()
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(keys)
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", 372))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	closed := node.Keys
+	node.Close()
+
+	for _, b := range closed.PrivateKey {
+		if b != 0 {
+			t.Fatal("expected the network's private key to be zeroed once it is closed")
+		}
+	}
+
+	if _, err := node.Sign([]byte("hello")); errors.Cause(err) != network.ErrKeysClosed {
+		t.Fatalf("expected signing once closed to fail, got %v", err)
+	}
+
+	// The keys the network was built with remain usable, such as to restart it with.
+	if bytes.Equal(keys.PrivateKey, make([]byte, len(keys.PrivateKey))) {
+		t.Fatal("expected the keys the network was built with to be left intact")
+	}
+}
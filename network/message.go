@@ -1,16 +1,23 @@
 package network
 
 import (
+	"time"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/perlin-network/noise/peer"
 )
 
 // PluginContext provides parameters and helper functions to a Plugin
-// for interacting with/analyzing incoming messages from a select peer.
+// for interacting with/analyzing incoming messages from a select peer. Its fields
+// are only exposed through accessors, such that it may evolve without breaking plugins.
 type PluginContext struct {
-	client  *PeerClient
-	message proto.Message
-	nonce   uint64
+	client     *PeerClient
+	message    proto.Message
+	nonce      uint64
+	id         MessageID
+	lamport    uint64
+	signature  []byte
+	receivedAt time.Time
 }
 
 // Reply sends back a message to an incoming message's incoming stream.
@@ -28,6 +35,12 @@ func (ctx *PluginContext) Client() *PeerClient {
 	return ctx.client
 }
 
+// SenderClient returns the peer client of the message's sender. It is an alias of
+// Client.
+func (ctx *PluginContext) SenderClient() *PeerClient {
+	return ctx.client
+}
+
 // Network returns the entire node's network.
 func (ctx *PluginContext) Network() *Network {
 	return ctx.client.Network
@@ -40,5 +53,42 @@ func (ctx *PluginContext) Self() peer.ID {
 
 // Sender returns the peer's ID.
 func (ctx *PluginContext) Sender() peer.ID {
-	return *ctx.client.ID
+	return *ctx.client.ID()
+}
+
+// MessageID returns the ID of the received message, as assigned by its sender.
+func (ctx *PluginContext) MessageID() MessageID {
+	return ctx.id
+}
+
+// LamportTime returns the sender's logical clock at the time the message was sent.
+// Zero if the sender does not keep a logical clock.
+func (ctx *PluginContext) LamportTime() uint64 {
+	return ctx.lamport
+}
+
+// IsRequest returns true should the sender be awaiting a reply to the message.
+func (ctx *PluginContext) IsRequest() bool {
+	return ctx.nonce > 0
+}
+
+// RawSignature returns the sender's signature over the message envelope.
+func (ctx *PluginContext) RawSignature() []byte {
+	return ctx.signature
+}
+
+// ReceivedAt returns the time the message was received according to the network's
+// clock.
+func (ctx *PluginContext) ReceivedAt() time.Time {
+	return ctx.receivedAt
+}
+
+// Protocol returns the transport protocol the sender is reachable over, such as tcp.
+// Empty should the sender's address be unparseable.
+func (ctx *PluginContext) Protocol() string {
+	info, err := ParseAddress(ctx.client.Address())
+	if err != nil {
+		return ""
+	}
+	return info.Protocol
 }
@@ -0,0 +1,40 @@
+package network
+
+import (
+	"encoding/hex"
+
+	"github.com/perlin-network/noise/protobuf"
+	"golang.org/x/crypto/blake2b"
+)
+
+// MessageID uniquely identifies a single message sent over the network by the hash of
+// its sender and the random ID its sender signed it under, such that it stays the same
+// across the connections it is sent over, yet differs between messages of identical
+// payloads.
+type MessageID [blake2b.Size256]byte
+
+// NewMessageID computes the ID of a message envelope. Envelopes preceding signed
+// message IDs are identified by their sender and payload instead, such that identical
+// messages they carry are deemed the same.
+func NewMessageID(msg *protobuf.Message) MessageID {
+	var sender protobuf.ID
+	if msg.Sender != nil {
+		sender = *msg.Sender
+	}
+
+	if len(msg.Id) > 0 {
+		return MessageID(blake2b.Sum256(serializeMessage(&sender, msg.Id)))
+	}
+
+	var payload []byte
+	if msg.Message != nil {
+		payload = append([]byte(msg.Message.TypeUrl), msg.Message.Value...)
+	}
+
+	return MessageID(blake2b.Sum256(serializeMessage(&sender, payload)))
+}
+
+// String returns the hex-encoded representation of the message ID.
+func (id MessageID) String() string {
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,58 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestMessageID(t *testing.T) {
+	sender := protobuf.ID(peer.CreateID("tcp://127.0.0.1:3000", []byte("12345678901234567890123456789012")))
+	other := protobuf.ID(peer.CreateID("tcp://127.0.0.1:3000", []byte("12345678901234567890123456789011")))
+
+	message := func(sender *protobuf.ID, id string, value string) *protobuf.Message {
+		return &protobuf.Message{
+			Message: &any.Any{TypeUrl: "type.googleapis.com/protobuf.Bytes", Value: []byte(value)},
+			Sender:  sender,
+			Id:      []byte(id),
+		}
+	}
+
+	id := NewMessageID(message(&sender, "a", "hello"))
+
+	if id != NewMessageID(message(&sender, "a", "hello")) {
+		t.Fatal("expected message IDs of identical messages to be equal")
+	}
+
+	if id == NewMessageID(message(&sender, "b", "hello")) {
+		t.Fatal("expected message IDs to differ by the IDs messages were signed under")
+	}
+
+	if id == NewMessageID(message(&other, "a", "hello")) {
+		t.Fatal("expected message IDs to differ by sender")
+	}
+
+	// Message IDs are of the signed fields of envelopes alone.
+	resent := message(&sender, "a", "hello")
+	resent.MessageNonce = 7
+
+	if id != NewMessageID(resent) {
+		t.Fatal("expected message IDs not to differ by nonce")
+	}
+
+	legacy := NewMessageID(message(&sender, "", "hello"))
+
+	if legacy != NewMessageID(message(&sender, "", "hello")) {
+		t.Fatal("expected message IDs of identical legacy messages to be equal")
+	}
+
+	if legacy == NewMessageID(message(&sender, "", "world")) {
+		t.Fatal("expected message IDs of legacy messages to differ by payload")
+	}
+
+	if len(id.String()) != 2*len(id) {
+		t.Fatalf("expected hex-encoded message ID, got %s", id.String())
+	}
+}
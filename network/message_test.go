@@ -0,0 +1,30 @@
+package network
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestPluginContextAccessors(t *testing.T) {
+	client := &PeerClient{address: "kcp://127.0.0.1:3000"}
+	receivedAt := time.Unix(1000, 0)
+
+	ctx := &PluginContext{client: client, signature: []byte("signature"), receivedAt: receivedAt}
+
+	if ctx.SenderClient() != client {
+		t.Fatal("expected sender client to be the peer client")
+	}
+
+	if !bytes.Equal(ctx.RawSignature(), []byte("signature")) {
+		t.Fatalf("unexpected signature %q", ctx.RawSignature())
+	}
+
+	if !ctx.ReceivedAt().Equal(receivedAt) {
+		t.Fatalf("expected message to be received at %s, got %s", receivedAt, ctx.ReceivedAt())
+	}
+
+	if protocol := ctx.Protocol(); protocol != "kcp" {
+		t.Fatalf("expected protocol kcp, got %q", protocol)
+	}
+}
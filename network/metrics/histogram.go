@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Bounds are the upper bounds of the buckets latencies are counted into. Latencies
+// above the last bound are counted into an overflow bucket.
+var Bounds = []time.Duration{
+	10 * time.Microsecond,
+	25 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	1 * time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// Histogram counts latencies into buckets bounded by Bounds.
+type Histogram struct {
+	mutex sync.Mutex
+
+	counts []uint64
+	count  uint64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// NewHistogram creates an empty histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{counts: make([]uint64, len(Bounds)+1)}
+}
+
+// Observe counts a latency.
+func (h *Histogram) Observe(latency time.Duration) {
+	bucket := len(Bounds)
+	for i, bound := range Bounds {
+		if latency <= bound {
+			bucket = i
+			break
+		}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.counts[bucket]++
+
+	if h.count == 0 || latency < h.min {
+		h.min = latency
+	}
+
+	if latency > h.max {
+		h.max = latency
+	}
+
+	h.count++
+	h.sum += latency
+}
+
+// Snapshot is a copy of a histogram at a point in time.
+type Snapshot struct {
+	// Counts are the number of latencies within each bucket, with the last bucket
+	// counting latencies above the last of Bounds.
+	Counts []uint64 `json:"counts"`
+
+	Count uint64        `json:"count"`
+	Sum   time.Duration `json:"sum"`
+	Min   time.Duration `json:"min"`
+	Max   time.Duration `json:"max"`
+}
+
+// Snapshot copies the histogram.
+func (h *Histogram) Snapshot() Snapshot {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return Snapshot{
+		Counts: append([]uint64(nil), h.counts...),
+		Count:  h.count,
+		Sum:    h.sum,
+		Min:    h.min,
+		Max:    h.max,
+	}
+}
+
+// Mean returns the mean latency counted. Zero if none were.
+func (s Snapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Quantile estimates the latency below which a fraction q of latencies fall, being
+// the upper bound of the bucket the quantile falls in, capped by the max latency.
+func (s Snapshot) Quantile(q float64) time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(s.Count))
+	if rank >= s.Count {
+		rank = s.Count - 1
+	}
+
+	seen := uint64(0)
+	for i, count := range s.Counts {
+		seen += count
+		if seen > rank {
+			if i < len(Bounds) && Bounds[i] < s.Max {
+				return Bounds[i]
+			}
+			return s.Max
+		}
+	}
+
+	return s.Max
+}
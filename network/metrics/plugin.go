@@ -0,0 +1,103 @@
+// Package metrics profiles where time is spent sending and handling messages, by
+// keeping latency histograms of each phase per message type.
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+)
+
+// Plugin keeps a latency histogram for every phase of sending and handling messages,
+// per message type.
+type Plugin struct {
+	*network.Plugin
+
+	histograms sync.Map // map[key]*Histogram
+}
+
+var PluginID = (*Plugin)(nil)
+
+type key struct {
+	phase       network.Phase
+	messageType string
+}
+
+// New creates a metrics plugin with no latencies observed.
+func New() *Plugin {
+	return new(Plugin)
+}
+
+// ObserveLatency implements network.LatencyObserver.
+func (p *Plugin) ObserveLatency(phase network.Phase, messageType string, latency time.Duration) {
+	k := key{phase: phase, messageType: messageType}
+
+	histogram, exists := p.histograms.Load(k)
+	if !exists {
+		histogram, _ = p.histograms.LoadOrStore(k, NewHistogram())
+	}
+
+	histogram.(*Histogram).Observe(latency)
+}
+
+// Histogram returns the latencies of a phase on messages of a type, such as
+// protobuf.Ping. The second returning parameter is false should none be observed.
+func (p *Plugin) Histogram(phase network.Phase, messageType string) (Snapshot, bool) {
+	histogram, exists := p.histograms.Load(key{phase: phase, messageType: messageType})
+	if !exists {
+		return Snapshot{}, false
+	}
+
+	return histogram.(*Histogram).Snapshot(), true
+}
+
+// Series is the latency histogram of a phase on messages of a type.
+type Series struct {
+	Phase       string   `json:"phase"`
+	MessageType string   `json:"message_type"`
+	Histogram   Snapshot `json:"histogram"`
+
+	phase network.Phase
+}
+
+// Series returns the latency histograms of all phases on all message types, sorted
+// by message type and phase.
+func (p *Plugin) Series() []Series {
+	var all []Series
+
+	p.histograms.Range(func(k, histogram interface{}) bool {
+		all = append(all, Series{
+			Phase:       k.(key).phase.String(),
+			MessageType: k.(key).messageType,
+			Histogram:   histogram.(*Histogram).Snapshot(),
+			phase:       k.(key).phase,
+		})
+		return true
+	})
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].MessageType != all[j].MessageType {
+			return all[i].MessageType < all[j].MessageType
+		}
+		return all[i].phase < all[j].phase
+	})
+
+	return all
+}
+
+// ServeHTTP dumps the latency histograms of all phases on all message types as JSON.
+func (p *Plugin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(p.Series()); err != nil {
+		glog.Error(err)
+	}
+}
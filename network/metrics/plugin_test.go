@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/networktest"
+)
+
+func TestHistogram(t *testing.T) {
+	histogram := NewHistogram()
+
+	for i := 0; i < 99; i++ {
+		histogram.Observe(20 * time.Microsecond)
+	}
+	histogram.Observe(3 * time.Second)
+
+	snapshot := histogram.Snapshot()
+
+	if snapshot.Count != 100 || snapshot.Min != 20*time.Microsecond || snapshot.Max != 3*time.Second {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	if median := snapshot.Quantile(0.5); median != 25*time.Microsecond {
+		t.Fatalf("expected median to fall in the 25us bucket, got %s", median)
+	}
+
+	if max := snapshot.Quantile(1); max != 3*time.Second {
+		t.Fatalf("expected 100th percentile to be the max, got %s", max)
+	}
+}
+
+func buildNode(t *testing.T, plugins ...network.PluginInterface) *network.Network {
+	return networktest.NewNode(t, plugins...)
+}
+
+func TestPluginObservesPhases(t *testing.T) {
+	metrics := New()
+
+	alice := buildNode(t, new(discovery.Plugin), metrics)
+	bob := buildNode(t, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	if err := alice.Bootstrap(bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	for _, phase := range []network.Phase{network.PhaseSerialize, network.PhaseSign, network.PhaseWrite} {
+		if histogram, exists := metrics.Histogram(phase, "protobuf.Ping"); !exists || histogram.Count == 0 {
+			t.Fatalf("expected %s latency of pings to be observed", phase)
+		}
+	}
+
+	if histogram, exists := metrics.Histogram(network.PhaseHandle, "protobuf.Pong"); !exists || histogram.Count == 0 {
+		t.Fatal("expected handle latency of pongs to be observed")
+	}
+
+	if len(metrics.Series()) == 0 {
+		t.Fatal("expected series of observed latencies")
+	}
+}
@@ -0,0 +1,157 @@
+package network
+
+import (
+	"net"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/peer"
+)
+
+// MigratingConn is implemented by connections of transports which migrate connections
+// across network addresses, such as QUIC, sparing roaming peers from having to resume
+// their connections from their new addresses.
+type MigratingConn interface {
+	net.Conn
+
+	// Migrations delivers the peer's new address each time the connection migrates.
+	// It is closed should the connection be.
+	Migrations() <-chan net.Addr
+}
+
+// resume reattaches the client of a peer connected at another address to the address
+// the peer now claims, should the peer prove to own its key at said address, such that
+// its pending requests, queued jobs and connection history survive its address
+// changing (e.g. should a mobile peer's IP change). Returns false should the peer not
+// be connected at another address, or should it predate identity challenges, in which
+// case it may not prove so.
+func (n *Network) resume(id peer.ID) (*PeerClient, bool, error) {
+	address, err := ToUnifiedAddress(id.Address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if _, exists := n.Peers.Load(address); exists {
+		return nil, false, nil
+	}
+
+	client := n.clientByKey(id.PublicKey, address)
+	if client == nil || client.EnvelopeVersion() < ChallengeEnvelopeVersion {
+		return nil, false, nil
+	}
+
+	session, err := n.Dial(address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := n.verifyDialBack(session, id); err != nil {
+		session.Close()
+		return nil, false, err
+	}
+
+	// Connect to the peer afresh should it have since been dialed at its new address.
+	if _, exists := n.Peers.LoadOrStore(address, client); exists {
+		session.Close()
+		return nil, false, nil
+	}
+
+	from := client.Address()
+
+	stale, _ := n.Connections.Load(from)
+	n.Connections.Store(address, &ConnState{session: session})
+
+	id.Address = address
+
+	client.migrate(&id, address)
+
+	n.Peers.Delete(from)
+	n.Connections.Delete(from)
+
+	if stale != nil {
+		stale.(*ConnState).session.Close()
+	}
+
+	n.Peerstore.Migrate(from, address)
+	n.migrateDeprecated(from, address)
+
+	n.Plugins.Each(func(plugin PluginInterface) {
+		plugin.PeerMigrate(client, from)
+	})
+
+	glog.Infof("Peer %s resumed its connection from %s.", id.Format(), from)
+
+	return client, true, nil
+}
+
+// clientByKey returns the client of an identified peer by its public key, connected at
+// an address other than except. Nil if none.
+func (n *Network) clientByKey(publicKey []byte, except string) (found *PeerClient) {
+	n.Peers.Range(func(key, value interface{}) bool {
+		client := value.(*PeerClient)
+
+		// Skip peers yet to identify themselves, whose IDs are still being set.
+		select {
+		case <-client.incomingReady:
+		default:
+			return true
+		}
+
+		if client.Address() != except && client.ID() != nil && crypto.Equal(client.ID().PublicKey, publicKey) {
+			found = client
+			return false
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// watch watches for a connection accepted from a peer migrating, should its transport
+// migrate connections.
+func (n *Network) watch(client *PeerClient, conn net.Conn, address string, connectedAt time.Time, closed chan struct{}) {
+	if conn, ok := conn.(MigratingConn); ok {
+		n.spawn(GoroutineMigrations, func() { n.watchMigrations(client, conn, address, connectedAt, closed) })
+	}
+}
+
+// watchMigrations observes a peer at its new address each time the connection it was
+// accepted over migrates, until the connection is closed.
+func (n *Network) watchMigrations(client *PeerClient, conn MigratingConn, address string, connectedAt time.Time, closed chan struct{}) {
+	migrations := conn.Migrations()
+
+	for {
+		select {
+		case to, ok := <-migrations:
+			if !ok {
+				return
+			}
+
+			info := newConnInfo(conn, address, connectedAt)
+			info.RemoteAddr = to
+
+			n.observe(client, info)
+		case <-closed:
+			return
+		}
+	}
+}
+
+// observe records the connection a peer was observed connecting to us over, notifying
+// plugins should the peer's address have changed.
+func (n *Network) observe(client *PeerClient, info ConnInfo) {
+	from := client.ObservedAddress()
+
+	client.observed.Store(observedAddr{info.RemoteAddr})
+	client.connInfo.Store(info)
+
+	if from == nil || info.RemoteAddr == nil || from.String() == info.RemoteAddr.String() {
+		return
+	}
+
+	n.Plugins.Each(func(plugin PluginInterface) {
+		plugin.AddressChanged(client, from, info.RemoteAddr)
+	})
+}
@@ -0,0 +1,240 @@
+package network_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func buildMemoryNodeWithKeys(t *testing.T, port uint16, keys *crypto.KeyPair) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(keys)
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func waitForPeer(t *testing.T, node *network.Network, address string) *network.PeerClient {
+	deadline := time.Now().Add(3 * time.Second)
+
+	for {
+		if client, exists := node.Peers.Load(address); exists && client.(*network.PeerClient).ID() != nil {
+			return client.(*network.PeerClient)
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for peer %s to connect", address)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestResumeFromNewAddress(t *testing.T) {
+	keys := ed25519.RandomKeyPair()
+
+	alice := buildMemoryNodeWithKeys(t, 310, ed25519.RandomKeyPair())
+	before := buildMemoryNodeWithKeys(t, 311, keys)
+	after := buildMemoryNodeWithKeys(t, 312, keys)
+
+	defer alice.Close()
+	defer after.Close()
+
+	datagrams, closeDatagrams := alice.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeDatagrams()
+
+	client, err := before.Client(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(&protobuf.Datagram{Data: []byte("before")}); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed := waitForPeer(t, alice, before.Address)
+
+	// The peer's address changes, leaving its previous connection stale.
+	client, err = after.Client(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(&protobuf.Datagram{Data: []byte("after")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if migrated := waitForPeer(t, alice, after.Address); migrated != resumed {
+		t.Fatal("expected the peer's client to be reattached to its new address")
+	}
+
+	if resumed.Address() != after.Address {
+		t.Fatalf("expected the client's address to be %s, got %s", after.Address, resumed.Address())
+	}
+
+	if _, exists := alice.Peers.Load(before.Address); exists {
+		t.Fatal("expected the peer's previous address to be forgotten")
+	}
+
+	if stats, exists := alice.Peerstore.Get(after.Address); !exists || stats.Connections != 1 {
+		t.Fatalf("expected the peer's connection history to be carried over, got %+v", stats)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-datagrams:
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for datagrams")
+		}
+	}
+
+	// The stale connection breaking leaves the peer connected.
+	before.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	if _, exists := alice.Peers.Load(after.Address); !exists {
+		t.Fatal("expected the peer to remain connected once its stale connection closed")
+	}
+}
+
+// migratingTransport accepts connections over the memory transport which migrate
+// once told to.
+type migratingTransport struct {
+	network.MemoryTransport
+
+	accepted chan *migratingConn
+}
+
+type migratingListener struct {
+	net.Listener
+
+	accepted chan *migratingConn
+}
+
+type migratingConn struct {
+	net.Conn
+
+	migrations chan net.Addr
+}
+
+func (t migratingTransport) Listen(addr *network.AddressInfo) (net.Listener, error) {
+	listener, err := t.MemoryTransport.Listen(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &migratingListener{Listener: listener, accepted: t.accepted}, nil
+}
+
+func (l *migratingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	migrating := &migratingConn{Conn: conn, migrations: make(chan net.Addr, 1)}
+
+	select {
+	case l.accepted <- migrating:
+	default:
+	}
+
+	return migrating, nil
+}
+
+func (c *migratingConn) Migrations() <-chan net.Addr {
+	return c.migrations
+}
+
+var migrations = migratingTransport{accepted: make(chan *migratingConn, 16)}
+
+func init() {
+	network.RegisterTransport("migrating", migrations)
+}
+
+type addressRecorder struct {
+	*network.Plugin
+
+	changes chan net.Addr
+}
+
+func (p *addressRecorder) AddressChanged(client *network.PeerClient, from net.Addr, to net.Addr) {
+	p.changes <- to
+}
+
+func TestConnectionMigration(t *testing.T) {
+	recorder := &addressRecorder{changes: make(chan net.Addr, 1)}
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("migrating", "127.0.0.1", 320))
+	builder.AddPlugin(recorder)
+
+	alice, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := alice.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	bob := buildMemoryNodeWithKeys(t, 321, ed25519.RandomKeyPair())
+
+	defer alice.Close()
+	defer bob.Close()
+
+	client, err := bob.Client(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(&protobuf.Datagram{Data: []byte("roaming")}); err != nil {
+		t.Fatal(err)
+	}
+
+	var conn *migratingConn
+
+	select {
+	case conn = <-migrations.accepted:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for connection to be accepted")
+	}
+
+	peer := waitForPeer(t, alice, bob.Address)
+
+	roamed := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 4000}
+	conn.migrations <- roamed
+
+	select {
+	case to := <-recorder.changes:
+		if to.String() != roamed.String() {
+			t.Fatalf("expected peer to have moved to %s, got %s", roamed, to)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the peer's address to change")
+	}
+
+	if observed := peer.ObservedAddress(); observed.String() != roamed.String() {
+		t.Fatalf("expected peer to be observed at %s, got %s", roamed, observed)
+	}
+
+	if _, exists := alice.Peers.Load(bob.Address); !exists {
+		t.Fatal("expected peer to remain connected once its connection migrated")
+	}
+}
@@ -0,0 +1,217 @@
+package network
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// MultipathMode denotes how messages to a peer are spread across the paths to it.
+type MultipathMode int
+
+const (
+	// MultipathFailover sends all messages over the primary path to a peer for as long
+	// as it is healthy, and over the first healthy additional path otherwise.
+	MultipathFailover MultipathMode = iota
+
+	// MultipathStripe sends messages over all healthy paths to a peer in turn.
+	MultipathStripe
+)
+
+// PathInfo describes a path to a peer.
+type PathInfo struct {
+	// Address is the address the path was dialed to.
+	Address string `json:"address"`
+
+	// Primary is true should the path be the connection the peer was first dialed over.
+	Primary bool `json:"primary"`
+
+	// Healthy is true should the path be open and not congested.
+	Healthy bool `json:"healthy"`
+
+	// Sent is the number of messages sent over the path.
+	Sent uint64 `json:"sent"`
+}
+
+// path is a connection to a peer over an address other than its own, such as over
+// another transport.
+type path struct {
+	address string
+	state   *ConnState
+	sent    uint64
+}
+
+// multipath holds the additional paths to a peer.
+type multipath struct {
+	mutex sync.RWMutex
+	paths []*path
+
+	primarySent uint64
+	next        uint64
+}
+
+// healthy returns true should messages be sendable over a session without congestion.
+func (n *Network) healthy(state *ConnState) bool {
+	if state.session == nil || state.session.IsClosed() {
+		return false
+	}
+	return !state.congestion.state(n.clock().Now(), state).Congested
+}
+
+// route selects the session a message to a peer is sent over out of the peer's
+// primary session and additional paths, counting the message against it.
+func (m *multipath) route(n *Network, primary *ConnState) *ConnState {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if len(m.paths) == 0 {
+		atomic.AddUint64(&m.primarySent, 1)
+		return primary
+	}
+
+	states := make([]*ConnState, 0, len(m.paths)+1)
+	counters := make([]*uint64, 0, len(m.paths)+1)
+
+	if n.healthy(primary) {
+		states, counters = append(states, primary), append(counters, &m.primarySent)
+	}
+
+	for _, p := range m.paths {
+		if n.healthy(p.state) {
+			states, counters = append(states, p.state), append(counters, &p.sent)
+		}
+	}
+
+	// Fall back to the primary session should no path be healthy.
+	if len(states) == 0 {
+		atomic.AddUint64(&m.primarySent, 1)
+		return primary
+	}
+
+	i := 0
+	if n.Multipath == MultipathStripe {
+		i = int((atomic.AddUint64(&m.next, 1) - 1) % uint64(len(states)))
+	}
+
+	atomic.AddUint64(counters[i], 1)
+	return states[i]
+}
+
+// find returns the path dialed to an address.
+func (m *multipath) find(address string) (*path, bool) {
+	for _, p := range m.paths {
+		if p.address == address {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// close closes all paths.
+func (m *multipath) close() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, p := range m.paths {
+		p.state.session.Close()
+	}
+	m.paths = nil
+}
+
+// AddPath dials the peer over an additional address, such as over another transport,
+// for messages to be sent over alongside the peer's own address as per the network's
+// MultipathMode. Messages are only ordered w.r.t. those sent over the same path.
+func (c *PeerClient) AddPath(address string) error {
+	address, err := ToUnifiedAddress(address)
+	if err != nil {
+		return err
+	}
+
+	if address == c.Address() {
+		return errors.Errorf("%s is already the primary path to the peer", address)
+	}
+
+	c.paths.mutex.RLock()
+	_, exists := c.paths.find(address)
+	c.paths.mutex.RUnlock()
+
+	if exists {
+		return nil
+	}
+
+	session, err := c.Network.Dial(address)
+	if err != nil {
+		return err
+	}
+
+	state := &ConnState{session: session}
+
+	// Identify ourselves over the path, as the peer drops connections which do not.
+	msg, err := c.Network.PrepareMessage(c.Network.NewPing())
+	if err == nil {
+		_, err = c.Network.write(c.Address(), state, msg)
+	}
+
+	if err != nil {
+		session.Close()
+		return errors.Wrapf(err, "failed to add path %s to %s", address, c.Address())
+	}
+
+	c.paths.mutex.Lock()
+	defer c.paths.mutex.Unlock()
+
+	if _, exists := c.paths.find(address); exists || atomic.LoadUint32(&c.closed) == 1 {
+		session.Close()
+		return nil
+	}
+
+	c.paths.paths = append(c.paths.paths, &path{address: address, state: state})
+
+	return nil
+}
+
+// RemovePath closes the additional path to the peer dialed to an address.
+func (c *PeerClient) RemovePath(address string) {
+	address, err := ToUnifiedAddress(address)
+	if err != nil {
+		return
+	}
+
+	c.paths.mutex.Lock()
+	defer c.paths.mutex.Unlock()
+
+	for i, p := range c.paths.paths {
+		if p.address == address {
+			p.state.session.Close()
+			c.paths.paths = append(c.paths.paths[:i], c.paths.paths[i+1:]...)
+			return
+		}
+	}
+}
+
+// Paths describes the paths to the peer, starting with its primary path.
+func (c *PeerClient) Paths() []PathInfo {
+	info := []PathInfo{{
+		Address: c.Address(),
+		Primary: true,
+		Sent:    atomic.LoadUint64(&c.paths.primarySent),
+	}}
+
+	if state, exists := c.Network.Connections.Load(c.Address()); exists {
+		info[0].Healthy = c.Network.healthy(state.(*ConnState))
+	}
+
+	c.paths.mutex.RLock()
+	defer c.paths.mutex.RUnlock()
+
+	for _, p := range c.paths.paths {
+		info = append(info, PathInfo{
+			Address: p.address,
+			Healthy: c.Network.healthy(p.state),
+			Sent:    atomic.LoadUint64(&p.sent),
+		})
+	}
+
+	return info
+}
@@ -0,0 +1,137 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+var pathFaults = network.NewFaultTransport(network.MemoryTransport{})
+
+func init() {
+	network.RegisterTransport("faultypath", pathFaults)
+}
+
+func buildMultipathNode(t *testing.T, protocol string, port uint16, mode network.MultipathMode) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress(protocol, "127.0.0.1", port))
+	builder.SetMultipathMode(mode)
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func receiveDatagrams(t *testing.T, datagrams <-chan *network.TappedMessage, count int) {
+	for i := 0; i < count; i++ {
+		select {
+		case <-datagrams:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for datagram %d of %d", i+1, count)
+		}
+	}
+}
+
+func TestMultipathStripe(t *testing.T) {
+	alice := buildMultipathNode(t, "mem", 300, network.MultipathStripe)
+	bob := buildMultipathNode(t, "mem", 301, network.MultipathStripe)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	datagrams, closeDatagrams := bob.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeDatagrams()
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AddPath(network.FormatAddress("faultypath", "127.0.0.1", 301)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := client.Tell(&protobuf.Datagram{Data: []byte("striped")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	receiveDatagrams(t, datagrams, 10)
+
+	paths := client.Paths()
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 paths, got %d", len(paths))
+	}
+
+	for _, path := range paths {
+		if path.Sent != 5 {
+			t.Fatalf("expected messages to be striped evenly across paths, got %+v", paths)
+		}
+	}
+}
+
+func TestMultipathFailover(t *testing.T) {
+	alice := buildMultipathNode(t, "mem", 302, network.MultipathFailover)
+	bob := buildMultipathNode(t, "faultypath", 303, network.MultipathFailover)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer pathFaults.Clear()
+
+	datagrams, closeDatagrams := bob.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeDatagrams()
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AddPath(network.FormatAddress("mem", "127.0.0.1", 303)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(&protobuf.Datagram{Data: []byte("primary")}); err != nil {
+		t.Fatal(err)
+	}
+
+	receiveDatagrams(t, datagrams, 1)
+
+	if paths := client.Paths(); paths[0].Sent != 1 || paths[1].Sent != 0 {
+		t.Fatalf("expected messages to be sent over the primary path while healthy, got %+v", paths)
+	}
+
+	// Break the primary path, which was dialed over the faulty transport.
+	pathFaults.Inject(network.Faults{ResetAfter: 1})
+	client.Tell(&protobuf.Datagram{Data: []byte("dropped")})
+
+	deadline := time.Now().Add(3 * time.Second)
+	for client.Paths()[0].Healthy {
+		if time.Now().After(deadline) {
+			t.Fatal("expected primary path to become unhealthy once reset")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := client.Tell(&protobuf.Datagram{Data: []byte("failover")}); err != nil {
+		t.Fatal(err)
+	}
+
+	receiveDatagrams(t, datagrams, 1)
+
+	if paths := client.Paths(); paths[1].Sent != 1 || !paths[1].Healthy {
+		t.Fatalf("expected messages to fail over to the additional path, got %+v", paths)
+	}
+}
@@ -0,0 +1,10 @@
+package nat
+
+// LocalPortMappingInfo denotes a single port being forwarde on the
+// UPnP interface.
+type LocalPortMappingInfo struct {
+	LocalPort      uint16
+	ExternalPort   uint16
+	ExternalIP     string
+	RouterLocation string
+}
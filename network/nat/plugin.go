@@ -54,6 +54,6 @@ func (state *plugin) Cleanup(net *network.Network) {
 // listening socket through any available UPnP interface.
 //
 // The plugin is registered with a priority of -999999, and thus is executed first.
-func RegisterPlugin(builder *builders.NetworkBuilder) {
-	builder.AddPluginWithPriority(-99999, new(plugin))
+func RegisterPlugin(builder *builders.NetworkBuilder) error {
+	return builder.AddPluginWithPriority(-99999, new(plugin))
 }
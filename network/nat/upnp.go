@@ -1,18 +1,12 @@
+//go:build !js
+// +build !js
+
 package nat
 
 import (
 	"github.com/NebulousLabs/go-upnp"
 )
 
-// LocalPortMappingInfo denotes a single port being forwarde on the
-// UPnP interface.
-type LocalPortMappingInfo struct {
-	LocalPort      uint16
-	ExternalPort   uint16
-	ExternalIP     string
-	RouterLocation string
-}
-
 // Close clears a port from remaining open.
 func (m *LocalPortMappingInfo) Close() error {
 	gateway, err := upnp.Load(m.RouterLocation)
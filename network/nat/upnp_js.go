@@ -0,0 +1,20 @@
+//go:build js
+// +build js
+
+package nat
+
+import "github.com/pkg/errors"
+
+// errUnsupported is returned as UPnP is unavailable in browsers.
+var errUnsupported = errors.New("UPnP port forwarding is unsupported in browsers")
+
+// Close clears a port from remaining open.
+func (m *LocalPortMappingInfo) Close() error {
+	return errUnsupported
+}
+
+// ForwardPort accesses the UPnP interface (should it be available) and port-forwards
+// a specified local port.
+func ForwardPort(localPort uint16) (*LocalPortMappingInfo, error) {
+	return nil, errUnsupported
+}
@@ -1,7 +1,7 @@
 package network
 
 import (
-	"math/rand"
+	"io"
 	"net"
 	"runtime"
 	"strconv"
@@ -12,14 +12,23 @@ import (
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
 	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/peerstore"
 	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/schedule"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/perlin-network/noise/types/logical"
+	"github.com/perlin-network/noise/types/ratelimit"
 	"github.com/pkg/errors"
-	"github.com/xtaci/kcp-go"
 	"github.com/xtaci/smux"
 )
 
+// DefaultHandshakeTimeout is the default period accepted connections have to identify
+// themselves before being dropped.
+const DefaultHandshakeTimeout = 10 * time.Second
+
 var packetPool = sync.Pool{
 	New: func() interface{} {
 		return new(Packet)
@@ -36,13 +45,31 @@ type Packet struct {
 	target  *ConnState
 	payload *protobuf.Message
 	result  chan interface{}
+
+	// bulk is the bandwidth the packet is paid off from before being sent, such that a
+	// share of bandwidth is reserved for priority messages. Nil if unlimited.
+	bulk *ratelimit.Bucket
 }
 
 // Network represents the current networking state for this node.
 type Network struct {
-	// Node's keypair.
+	// Node's keypair. Its private key is left empty should Signer or SignEnvelope be set.
 	Keys *crypto.KeyPair
 
+	// Signer signs messages on behalf of the node in place of Keys, such as a remote
+	// signer holding the node's private key. Nil if signed with Keys.
+	Signer crypto.Signer
+
+	// SignEnvelope signs the envelopes of messages sent, and all else signed on behalf
+	// of the node such as storage records, in place of Signer and Keys, such as with
+	// threshold signatures or keys attested by a TEE. Nil if signed with Signer or Keys.
+	SignEnvelope EnvelopeSignFunc
+
+	// VerifyEnvelope verifies the signatures of envelopes received, and all else
+	// verified with Verify, in place of the signature policy. Nil if they are verified
+	// with the signature policy.
+	VerifyEnvelope EnvelopeVerifyFunc
+
 	// Full address to listen on. `protocol://host:port`
 	Address string
 
@@ -60,90 +87,415 @@ type Network struct {
 	SendQueue chan *Packet
 	RecvQueue chan *protobuf.Message
 
+	// Workers send packets queued onto the SendQueue, and may be shared by several
+	// networks in one process. Nil if the network spawns its own workers.
+	Workers *WorkerPool
+
 	// Map of connection addresses (string) <-> *ConnState
 	Connections *sync.Map
 
-	// <-Listening will block a goroutine until this node is listening for peers.
+	// <-Listening will block a goroutine until this node is listening for peers, or
+	// has failed to listen.
 	Listening chan struct{}
+	listenErr error
 
 	SignaturePolicy crypto.SignaturePolicy
 	HashPolicy      crypto.HashPolicy
 
+	// Lamport clock used to timestamp outgoing messages. Nil if disabled.
+	LogicalClock *logical.Lamport
+
+	// Gater vetoes incoming and outgoing connections. Nil if all connections are allowed.
+	Gater ConnectionGater
+
+	// Resources enforces budgets on streams, buffered bytes and goroutines. Nil if unlimited.
+	Resources *ResourceManager
+
+	// Capabilities are the services this node advertises to its peers.
+	Capabilities Capability
+
+	// Priority sends messages of select types ahead of all others. Nil if all messages
+	// are sent in order.
+	Priority *PriorityLane
+
+	// Streams bounds the number of streams processed concurrently overall and per peer,
+	// scheduling them round-robin across peers. Nil if unbounded.
+	Streams *StreamScheduler
+
+	// Messages is the registry of the message types the network handles. It only detects
+	// colliding types, and does not filter received messages by their types. Nil if
+	// message types are not tracked.
+	Messages *MessageRegistry
+
+	// Zone labels the datacenter or availability zone this node runs in, exchanged with
+	// peers on pings and pongs. Empty if unlabelled.
+	Zone string
+
+	// ZonePreference is how BroadcastRandomly, and plugins by SelectByZone, select
+	// peers w.r.t. their zones should this node be labelled with a zone.
+	ZonePreference ZonePreference
+
+	// Deprecations are the message types this node deprecated, advertised to its peers.
+	Deprecations []Deprecation
+
+	// UnknownHandler handles messages of types unknown to the network. Nil if they are
+	// dropped.
+	UnknownHandler UnknownHandler
+
+	// Filters inspect and drop incoming messages before they are dispatched.
+	Filters []MessageFilter
+
+	// PreFilters inspect and drop incoming messages before they are unmarshalled and verified.
+	PreFilters []PreFilter
+
+	// Bandwidth throttles traffic over connections. Nil if unlimited.
+	Bandwidth *Throttle
+
+	// Peerstore tracks the connection history of peers. Nil if disabled.
+	Peerstore *peerstore.Store
+
+	// Clock is the source of time for timeouts and timestamps. Nil if the system clock.
+	Clock clock.Clock
+
+	// Entropy is the source of the random IDs assigned to messages sent, such as to
+	// reproduce them deterministically. It must be safe for concurrent use. Nil if
+	// crypto/rand.
+	Entropy io.Reader
+
+	// Quarantine stops addresses which failed to be dialed from being redialed for a
+	// while. Nil if addresses are never quarantined.
+	Quarantine *DialQuarantine
+
+	// TimeEstimator estimates the offset of the networks clock relative to ours. Nil if
+	// network time is our own.
+	TimeEstimator TimeEstimator
+
+	// SizeEstimator estimates the number of nodes within the network, such as to adapt
+	// gossip fan-out to. Nil if only peers connected to us are known of.
+	SizeEstimator SizeEstimator
+
+	// Multipath denotes how messages to peers are spread across the additional paths
+	// added to them. MultipathFailover by default.
+	Multipath MultipathMode
+
+	// ResolveDuplicates has peers share a single connection with us rather than each
+	// side sending over the connection it dialed, keeping the connection dialed by
+	// whichever side has the lower ID.
+	ResolveDuplicates bool
+
+	// NetworkID namespaces the network, such that nodes of networks by other IDs (e.g.
+	// a testnet and mainnet deployment of the same application) refuse to connect to
+	// it. Empty if unnamespaced.
+	NetworkID string
+
+	// SignNetworkID mixes NetworkID into message signatures, such that messages of
+	// networks by other IDs fail to be verified even should they be relayed.
+	SignNetworkID bool
+
+	// Summarize provides the application state summarized onto our pings and pongs
+	// alongside our peer count. Nil if our pings and pongs carry no summary.
+	Summarize SummaryFunc
+
+	// SummaryInterval is how often our summary is pushed to peers in heartbeat pings,
+	// should we share one. DefaultSummaryInterval if zero.
+	SummaryInterval time.Duration
+
+	// ConfigFingerprint is the fingerprint of the EffectiveConfig of the network as it
+	// was built, piggybacked onto our pings and pongs for operators to detect config
+	// drift across a fleet with. Nil if unshared.
+	ConfigFingerprint []byte
+
+	// ProbeSize is the number of bytes echoed to peers upon connecting to them to probe
+	// the round-trip time and bandwidth of their links. Zero if links are not probed.
+	ProbeSize int
+
+	// TicketLifetime is how long session tickets issued to peers whose identities were
+	// verified remain valid, sparing them from being challenged upon reconnecting.
+	// Zero if no tickets are issued.
+	TicketLifetime time.Duration
+
+	// HandshakeTimeout is how long accepted connections have to identify themselves
+	// before being dropped. Zero if connections may linger unidentified.
+	HandshakeTimeout time.Duration
+
+	// ReadTimeout is how long an accepted stream has to deliver its message in full
+	// before being reaped. Zero if streams may linger idle.
+	ReadTimeout time.Duration
+
+	// MaxMessageSize caps the size of received messages, and MaxHeaderSize the size of
+	// their envelopes excluding their payloads. Zero values denote the defaults.
+	MaxMessageSize int
+	MaxHeaderSize  int
+
 	// <-Kill will begin the server shutdown process
 	Kill chan struct{}
+
+	// Scheduler running delayed and scheduled sends.
+	schedulerOnce sync.Once
+	scheduler     *schedule.Scheduler
+
+	// Number of accepted connections dropped for not identifying themselves in time.
+	handshakeTimeouts uint64
+
+	// Number of accepted streams reaped for not delivering their message in time.
+	streamTimeouts uint64
+
+	// Number of peers which resumed their sessions by presenting a session ticket.
+	resumedSessions uint64
+
+	taps      map[*tap]struct{}
+	tapsMutex sync.RWMutex
+
+	replay replayState
+
+	// tickets are the session tickets we issue to peers, and those issued to us.
+	tickets sessionTickets
+
+	// bulkEgress is the egress bandwidth messages other than priority messages are
+	// paid off from. Nil if unlimited.
+	bulkEgress *ratelimit.Bucket
+
+	// verifiedBatches remembers the batch signatures which were verified.
+	verifiedBatches verifiedBatches
+
+	// deprecated counts the messages of deprecated types received from peers.
+	deprecated deprecations
+
+	goroutines goroutineTracker
+
+	// Plugins observing the latencies of sending and handling messages.
+	observersOnce sync.Once
+	observers     []LatencyObserver
+
+	bootstrap bootstrapTracker
+
+	// keysMutex guards Keys from being zeroed while signing, and keysClosed is set
+	// once they are.
+	keysMutex  sync.RWMutex
+	keysClosed bool
 }
 
 type ConnState struct {
 	session      *smux.Session
 	messageNonce uint64
+
+	// ticket is the session ticket presented in envelopes written over the connection.
+	ticket atomic.Value
+
+	congestion congestion
+}
+
+// clock returns the clock of the network, defaulting to the system clock.
+func (n *Network) clock() clock.Clock {
+	return clock.Or(n.Clock)
 }
 
 // Init starts all network I/O workers.
 func (n *Network) Init() {
-	workerCount := runtime.NumCPU() + 1
-
 	// Spawn worker routines for receiving and handling messages in the application layer.
-	go n.handleRecvQueue()
+	n.spawn(GoroutineRecvQueue, n.handleRecvQueue)
 
-	for i := 0; i < workerCount; i++ {
-		// Spawn worker routines for sending queued messages to the networking layer.
-		go n.handleSendQueue()
+	// Spawn worker routines for sending queued messages to the networking layer, unless
+	// they are shared with other networks.
+	if n.Workers == nil {
+		n.Workers = NewWorkerPool(runtime.NumCPU()+1, n.SendQueue)
+		n.Workers.Clock = n.Clock
 	}
-}
 
-// Send queue worker.
-func (n *Network) handleSendQueue() {
-	for {
-		select {
-		case packet := <-n.SendQueue:
-			stream, err := packet.target.session.OpenStream()
-			if err != nil {
-				packet.result <- err
-				continue
-			}
+	n.SendQueue = n.Workers.Queue
 
-			err = n.sendMessage(stream, packet.payload)
-			if err != nil {
-				packet.result <- err
-				continue
-			}
-
-			err = stream.Close()
-			if err != nil {
-				packet.result <- err
-				continue
-			}
+	n.Workers.Start()
 
-			packet.result <- struct{}{}
+	if n.Priority != nil {
+		if n.Priority.Workers.Clock == nil {
+			n.Priority.Workers.Clock = n.Clock
 		}
+
+		n.Priority.Workers.Start()
+	}
+
+	n.bulkEgress = n.Bandwidth.reserve(n.Priority.share())
+
+	// Push our summary to peers periodically, as it would otherwise only travel on the
+	// pings and pongs exchanged when connecting.
+	if n.Summarize != nil {
+		n.Scheduler().Repeat(schedule.Every(n.summaryInterval()), n.heartbeat)
 	}
 }
 
 func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message) {
+	// Let a replay awaiting the message know once it has been handled.
+	handled, async := n.replay.awaiting(msg), false
+	defer func() {
+		if !async {
+			handled()
+		}
+	}()
+
 	// Check if the client is ready.
 	if !client.IncomingReady() {
 		return
 	}
+
+	client.observeEnvelopeVersion(msg.MaxVersion)
+	client.observeCapabilities(msg.Capabilities)
+
+	n.Plugins.Each(func(plugin PluginInterface) {
+		plugin.Inbound(client, msg)
+	})
+
+	n.tap(false, client.Address(), msg)
+
+	if err := n.filterMessage(client, msg); err != nil {
+		if errors.Cause(err) == ErrPeerBanned {
+			glog.V(2).Infof("Dropped message from %s [err=%s]", client.Address(), err)
+		} else {
+			glog.Warningf("Dropped message from %s [err=%s]", client.Address(), err)
+		}
+		return
+	}
+
 	var ptr ptypes.DynamicAny
 	if err := ptypes.UnmarshalAny(msg.Message, &ptr); err != nil {
+		// Replies of types unknown to us are still awaited by whoever sent the request,
+		// and so are handed to them raw rather than to the unknown handler.
+		if channel, exists := client.Requests.Load(msg.RequestNonce); exists && msg.RequestNonce > 0 {
+			if n.LogicalClock != nil {
+				n.LogicalClock.Witness(msg.LamportTimestamp)
+			}
+
+			select {
+			case channel.(chan proto.Message) <- msg.Message:
+			default:
+			}
+			return
+		}
+
+		if n.UnknownHandler != nil {
+			if n.LogicalClock != nil {
+				n.LogicalClock.Witness(msg.LamportTimestamp)
+			}
+
+			n.UnknownHandler(client, newUnknownMessage(client, msg, n.clock().Now()))
+			return
+		}
+
+		// Any peer may send types we do not know of, and so dropping them is only logged
+		// verbosely lest peers flood the logs.
+		glog.V(2).Infof("Dropped message of unknown type %s from %s [err=%s]", msg.Message.GetTypeUrl(), client.Address(), err)
 		return
 	}
 
+	// Types protobuf knows but the registry does not are most likely types some plugin
+	// forgot to register, and so are warned of once each.
+	if n.Messages != nil {
+		if name := proto.MessageName(ptr.Message); n.Messages.firstUnregistered(name) {
+			glog.Warningf("Received message of type %s which is not registered with the network's message registry", name)
+		}
+	}
+
+	// Advance our logical clock past the sender's.
+	if n.LogicalClock != nil {
+		n.LogicalClock.Witness(msg.LamportTimestamp)
+	}
+
+	// Keep the summaries, config fingerprints, deprecations and zones peers piggyback
+	// onto their pings and pongs.
+	switch message := ptr.Message.(type) {
+	case *protobuf.Ping:
+		client.observeSummary(message.Summary)
+		client.observeConfigFingerprint(message.ConfigFingerprint)
+		client.observeDeprecations(message.Deprecations)
+		client.observeZone(message.Zone)
+	case *protobuf.Pong:
+		client.observeSummary(message.Summary)
+		client.observeConfigFingerprint(message.ConfigFingerprint)
+		client.observeDeprecations(message.Deprecations)
+		client.observeZone(message.Zone)
+	}
+
+	n.observeDeprecated(client, ptr.Message)
+
 	if channel, exists := client.Requests.Load(msg.RequestNonce); exists && msg.RequestNonce > 0 {
-		channel.(chan proto.Message) <- ptr.Message
+		// Drop duplicate replies, as only the first is awaited.
+		select {
+		case channel.(chan proto.Message) <- ptr.Message:
+		default:
+		}
 		return
 	}
 
 	switch ptr.Message.(type) {
 	case *protobuf.Bytes:
-		client.handleBytes(ptr.Message.(*protobuf.Bytes).Data)
+		packet := ptr.Message.(*protobuf.Bytes)
+
+		data, err := client.decompressPacket(packet)
+		if err != nil {
+			glog.Warningf("Dropped stream packet from %s [err=%s]", client.Address(), err)
+			return
+		}
+
+		client.handleBytes(data)
+	case *protobuf.Echo:
+		received := n.clock().Now()
+
+		echo := ptr.Message.(*protobuf.Echo)
+
+		reply := &protobuf.EchoReply{
+			EchoTimestamp:     echo.Timestamp,
+			ReceivedTimestamp: received.UnixNano(),
+			Timestamp:         n.clock().Now().UnixNano(),
+			Padding:           echo.Padding,
+		}
+
+		if err := client.Reply(msg.RequestNonce, reply); err != nil {
+			glog.Warningf("Failed to reply to echo from %s [err=%s]", client.Address(), err)
+		}
+	case *protobuf.SessionTicket:
+		n.holdTicket(client.Address(), ptr.Message.(*protobuf.SessionTicket))
+	case *protobuf.ResumeSession:
+		// Redeemed upon the peer identifying itself.
+	case *protobuf.SlowDown:
+		client.honorSlowDown(time.Duration(ptr.Message.(*protobuf.SlowDown).Delay))
+	case *protobuf.StreamCompressionRequest:
+		selected := client.acceptCompression(ptr.Message.(*protobuf.StreamCompressionRequest).Supported)
+
+		if err := client.Reply(msg.RequestNonce, &protobuf.StreamCompressionResponse{Selected: selected}); err != nil {
+			glog.Warningf("Failed to reply to compression request from %s [err=%s]", client.Address(), err)
+		}
 	default:
 		ctx := contextPool.Get().(*PluginContext)
 		ctx.client = client
 		ctx.message = ptr.Message
 		ctx.nonce = msg.RequestNonce
+		ctx.id = NewMessageID(msg)
+		ctx.lamport = msg.LamportTimestamp
+		ctx.signature = msg.Signature
+		ctx.receivedAt = n.clock().Now()
+
+		// Drop the message should the peer have too many messages being handled. The
+		// goroutine is released under the address it was reserved under, as the peer
+		// may migrate while its message is being handled.
+		address := client.Address()
+		if err := n.Resources.ReserveGoroutine(address); err != nil {
+			glog.Warningf("Dropped message from %s [err=%s]", address, err)
+			contextPool.Put(ctx)
+
+			// Ask the peer to back off until we catch up.
+			if err := client.hintSlowDown(DefaultSlowDownDelay); err != nil {
+				glog.Warningf("Failed to ask %s to slow down [err=%s]", client.Address(), err)
+			}
+			return
+		}
+
+		async = true
+
+		n.spawn(GoroutineHandler, func() {
+			defer handled()
+			defer n.Resources.ReleaseGoroutine(address)
+
+			start := n.clock().Now()
 
-		go func() {
 			// Execute 'on receive message' callback for all plugins.
 			n.Plugins.Each(func(plugin PluginInterface) {
 				err := plugin.Receive(ctx)
@@ -153,12 +505,14 @@ func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message) {
 				}
 			})
 
+			n.observeLatency(PhaseHandle, messageType(ptr.Message), start)
+
 			contextPool.Put(ctx)
-		}()
+		})
 	}
 }
 
-// Receive queue worker.
+// Receive queue worker, which stops once the network is closed.
 func (n *Network) handleRecvQueue() {
 	for {
 		select {
@@ -168,12 +522,38 @@ func (n *Network) handleRecvQueue() {
 
 				client.Submit(func() { n.dispatchMessage(client, msg) })
 			}
+		case <-n.Kill:
+			return
 		}
 	}
 }
 
-// Listen starts listening for peers on a port.
-func (n *Network) Listen() {
+// Listen listens for peers on the network's address, and serves them until the network
+// is closed. It returns an error should the network fail to listen, and nil once the
+// network is closed. Should the network's address be given port 0, it listens on a
+// free port, which its address and ID adopt before plugins start up.
+func (n *Network) Listen() error {
+	addrInfo, err := ParseAddress(n.Address)
+	if err != nil {
+		return n.failListening(err)
+	}
+
+	transport, err := transportFor(addrInfo)
+	if err != nil {
+		return n.failListening(err)
+	}
+
+	listener, err := transport.Listen(addrInfo)
+	if err != nil {
+		return n.failListening(errors.Wrapf(err, "failed to listen on %s", n.Address))
+	}
+
+	if addrInfo.Port == 0 {
+		if err := n.adoptPort(addrInfo, listener.Addr()); err != nil {
+			listener.Close()
+			return n.failListening(err)
+		}
+	}
 
 	// Handle 'network starts listening' callback for plugins.
 	n.Plugins.Each(func(plugin PluginInterface) {
@@ -187,52 +567,31 @@ func (n *Network) Listen() {
 		})
 	}()
 
-	addrInfo, err := ParseAddress(n.Address)
-	if err != nil {
-		glog.Fatal(err)
-	}
-
-	var listener net.Listener
-
-	if addrInfo.Protocol == "kcp" {
-		listener, err = kcp.ListenWithOptions(":"+strconv.Itoa(int(addrInfo.Port)), nil, 10, 3)
-		if err != nil {
-			glog.Fatal(err)
-		}
-	} else if addrInfo.Protocol == "tcp" {
-		listener, err = net.Listen("tcp", ":"+strconv.Itoa(int(addrInfo.Port)))
-	} else {
-		err = errors.New("invalid protocol: " + addrInfo.Protocol)
-	}
-
-	if err != nil {
-		glog.Fatal(err)
-	}
-
 	close(n.Listening)
 
 	glog.Infof("Listening for peers on %s.\n", n.Address)
 
 	// handle server shutdowns
-	go func() {
+	n.spawn(GoroutineListen, func() {
 		select {
 		case <-n.Kill:
 			// cause listener.Accept() to stop blocking so it can continue the loop
 			listener.Close()
 		}
-	}()
+	})
 
 	// Handle new clients.
 	for {
 		if conn, err := listener.Accept(); err == nil {
-			go n.Accept(conn)
+			conn := conn
+			n.spawn(GoroutineAccept, func() { n.Accept(conn) })
 
 		} else {
 			// if the Shutdown flag is set, no need to continue with the for loop
 			select {
 			case <-n.Kill:
 				glog.Infof("Shutting down server on %s.\n", n.Address)
-				return
+				return nil
 			default:
 				// without the default case the select will block.
 			}
@@ -242,6 +601,27 @@ func (n *Network) Listen() {
 	}
 }
 
+// adoptPort sets the port of the network's address and ID to that of the address a
+// listener was bound to.
+func (n *Network) adoptPort(addrInfo *AddressInfo, bound net.Addr) error {
+	_, rawPort, err := net.SplitHostPort(bound.String())
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve the port %s is bound to", n.Address)
+	}
+
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve the port %s is bound to", n.Address)
+	}
+
+	addrInfo.Port = uint16(port)
+
+	n.Address = addrInfo.String()
+	n.ID.Address = n.Address
+
+	return nil
+}
+
 // Client either creates or returns a cached peer client given its host address.
 func (n *Network) Client(address string) (*PeerClient, error) {
 	address, err := ToUnifiedAddress(address)
@@ -250,7 +630,7 @@ func (n *Network) Client(address string) (*PeerClient, error) {
 	}
 
 	if address == n.Address {
-		return nil, errors.New("peer should not dial itself")
+		return nil, ErrDialSelf
 	}
 
 	client, err := createPeerClient(n, address)
@@ -262,7 +642,7 @@ func (n *Network) Client(address string) (*PeerClient, error) {
 		client := client.(*PeerClient)
 
 		if !client.OutgoingReady() {
-			return nil, errors.New("peer failed to connect")
+			return nil, errors.Wrapf(ErrHandshakeTimeout, "peer %s failed to connect", address)
 		}
 
 		return client, nil
@@ -280,67 +660,85 @@ func (n *Network) Client(address string) (*PeerClient, error) {
 			return nil, err
 		}
 
-		n.Connections.Store(address, &ConnState{
-			session: session,
-		})
+		state := &ConnState{session: session}
+
+		// Resume our session with the peer should it have issued us a ticket.
+		n.presentTicket(address, state)
+
+		n.Connections.Store(address, state)
+
+		// Serve messages the peer sends over our connection, should it yield its own or
+		// not dial us back.
+		if n.ResolveDuplicates || repliesInbound(address) {
+			atomic.AddInt32(&client.connections, 1)
+			n.spawn(GoroutineOutgoing, func() { n.serveOutgoing(client, session) })
+		}
 
 		client.Init()
 
+		if n.ProbeSize > 0 {
+			n.spawn(GoroutineProbe, func() { n.probeLink(client) })
+		}
+
 		return client, nil
 	}
 }
 
-// BlockUntilListening blocks until this node is listening for new peers.
-func (n *Network) BlockUntilListening() {
-	<-n.Listening
-}
-
-// Bootstrap with a number of peers and commence a handshake.
-func (n *Network) Bootstrap(addresses ...string) {
-	n.BlockUntilListening()
-
-	addresses = FilterPeers(n.Address, addresses)
+// failListening unblocks all goroutines waiting for the network to listen, having
+// them return err.
+func (n *Network) failListening(err error) error {
+	n.listenErr = err
+	close(n.Listening)
 
-	for _, address := range addresses {
-		client, err := n.Client(address)
+	return err
+}
 
-		if err != nil {
-			glog.Error(err)
-			continue
-		}
+// Start listens for peers in the background, and returns once the network is either
+// listening or has failed to listen.
+func (n *Network) Start() error {
+	n.spawn(GoroutineListen, func() { n.Listen() })
+	return n.BlockUntilListening()
+}
 
-		err = client.Tell(&protobuf.Ping{})
-		if err != nil {
-			continue
-		}
-	}
+// BlockUntilListening blocks until this node is listening for new peers, and returns
+// an error should it have failed to listen.
+func (n *Network) BlockUntilListening() error {
+	<-n.Listening
+	return n.listenErr
 }
 
 // Dial establishes a bidirectional connection to an address, and additionally handshakes with said address.
 func (n *Network) Dial(address string) (*smux.Session, error) {
+	if n.Gater != nil && !n.Gater.InterceptDial(address) {
+		return nil, errors.Wrapf(ErrConnectionRejected, "dial to %s", address)
+	}
+
+	if err := n.Quarantine.Check(address); err != nil {
+		return nil, err
+	}
+
 	addrInfo, err := ParseAddress(address)
 	if err != nil {
 		return nil, err
 	}
 
-	var conn net.Conn
-
-	// Choose scheme.
-	if addrInfo.Protocol == "kcp" {
-		conn, err = kcp.DialWithOptions(addrInfo.HostPort(), nil, 10, 3)
-	} else if addrInfo.Protocol == "tcp" {
-		conn, err = net.Dial("tcp", addrInfo.HostPort())
-	} else {
-		err = errors.New("invalid protocol: " + addrInfo.Protocol)
+	transport, err := transportFor(addrInfo)
+	if err != nil {
+		return nil, err
 	}
 
+	conn, err := transport.Dial(addrInfo)
+
 	// Failed to connect.
 	if err != nil {
+		n.Quarantine.Failure(address)
 		return nil, err
 	}
 
+	n.Quarantine.Success(address)
+
 	// Wrap a session around the outgoing connection.
-	session, err := smux.Client(conn, muxConfig())
+	session, err := smux.Client(n.Bandwidth.Wrap(conn), muxConfig())
 	if err != nil {
 		return nil, err
 	}
@@ -350,47 +748,81 @@ func (n *Network) Dial(address string) (*smux.Session, error) {
 
 // Accept handles peer registration and processes incoming message streams.
 func (n *Network) Accept(conn net.Conn) {
-	const RECV_WINDOW_SIZE = 4096
-
 	var incoming *smux.Session
 	var outgoing *smux.Session
 
 	var client *PeerClient
 	var clientInit sync.Once
 
-	recvWindow := NewRecvWindow(RECV_WINDOW_SIZE)
+	recvWindow := NewRecvWindow(recvWindowSize)
 
 	var err error
 
+	// Peers connecting over transports they may not be dialed back over are replied to
+	// over the connection they dialed.
+	inbound := repliesInbound(n.Address)
+
+	identified := make(chan struct{})
+	closed := make(chan struct{})
+
 	// Cleanup connections when we are done with them.
 	defer func() {
-		if client != nil {
-			client.Close()
-		}
+		close(closed)
 
 		if incoming != nil {
 			incoming.Close()
 		}
 
+		// Wait for the client to be initialized should it be being so, as it is set
+		// by whichever stream initializes it.
+		clientInit.Do(func() {})
+
+		// Leave the peer connected should it remain connected over other paths.
+		if client != nil && atomic.AddInt32(&client.connections, -1) > 0 {
+			return
+		}
+
+		if client != nil {
+			client.Close()
+		}
+
 		if outgoing != nil {
 			outgoing.Close()
 		}
 	}()
 
+	if n.Gater != nil && !n.Gater.InterceptAccept(conn.RemoteAddr()) {
+		conn.Close()
+		return
+	}
+
 	// Wrap a session around the incoming connection.
-	incoming, err = smux.Server(conn, muxConfig())
+	incoming, err = smux.Server(n.Bandwidth.Wrap(conn), muxConfig())
 	if err != nil {
 		glog.Error(err)
 		return
 	}
 
+	connectedAt := n.clock().Now()
+	remote := conn.RemoteAddr().String()
+
+	n.spawn(GoroutineHandshakeTimeout, func() { n.enforceHandshakeTimeout(conn, incoming, identified, closed) })
+
 	for {
 		stream, err := incoming.AcceptStream()
 		if err != nil {
 			break
 		}
 
-		go func() {
+		// Drop the stream should the peer have too many streams open.
+		if err := n.Resources.ReserveStream(remote); err != nil {
+			glog.Warningf("Dropped stream from %s [err=%s]", conn.RemoteAddr(), err)
+			stream.Close()
+			continue
+		}
+
+		n.spawn(GoroutineIngest, func() {
+			defer n.Resources.ReleaseStream(remote)
 			defer stream.Close()
 
 			var err error
@@ -403,48 +835,181 @@ func (n *Network) Accept(conn net.Conn) {
 				return
 			}
 
+			// Answer identity challenges from peers which dialed back to us.
+			if ptypes.Is(msg.Message, (*protobuf.IdentityChallenge)(nil)) {
+				if err := n.answerChallenge(stream, msg); err != nil {
+					glog.Warningf("Failed to answer identity challenge from %s [err=%s]", msg.Sender.Address, err)
+				}
+				return
+			}
+
 			// Initialize client if not exists.
 			clientInit.Do(func() {
-				client, err = n.Client(msg.Sender.Address)
+				if n.Gater != nil && !n.Gater.InterceptSecured(peer.ID(*msg.Sender), conn.RemoteAddr()) {
+					err = errors.Wrapf(ErrConnectionRejected, "connection from %s", msg.Sender.Address)
+					glog.Warning(err)
+					incoming.Close()
+					return
+				}
+
+				var dialed *PeerClient
+				var resumed, created bool
+
+				if inbound {
+					dialed, created, err = n.inboundClient(msg.Sender.Address, incoming)
+				} else {
+					// Reattach the client of a peer which was connected at another address.
+					dialed, resumed, err = n.resume(peer.ID(*msg.Sender))
+					if err == nil && !resumed {
+						dialed, err = n.Client(msg.Sender.Address)
+					}
+				}
 				if err != nil {
 					glog.Error(err)
 					return
 				}
 
-				client.ID = (*peer.ID)(msg.Sender)
-
 				// Load an outgoing connection.
-				if state, established := n.Connections.Load(client.ID.Address); established {
-					outgoing = state.(*ConnState).session
-				} else {
+				state, established := n.Connections.Load(dialed.Address())
+				if !established {
 					err = errors.New("failed to load session")
+					return
+				}
+
+				// Only trust the claimed address once the node answering at it proves to
+				// be the peer. Resumed peers proved so already, peers presenting a ticket
+				// were verified recently, and peers predating challenges may not answer
+				// them.
+				ticketed := n.redeemTicket(dialed.Address(), msg)
+				if ticketed {
+					atomic.AddUint64(&n.resumedSessions, 1)
+				}
+
+				if !resumed && !ticketed && msg.MaxVersion >= ChallengeEnvelopeVersion {
+					challenged := state.(*ConnState).session
+					if inbound {
+						challenged = incoming
+					}
+
+					err = n.verifyDialBack(challenged, peer.ID(*msg.Sender))
+				}
+
+				if err != nil {
+					glog.Warning(err)
+
+					// Drop the client should it have been made solely for this connection.
+					if dialed.ID() == nil && (!inbound || created) {
+						dialed.Close()
+						state.(*ConnState).session.Close()
+
+						n.Peers.Delete(dialed.Address())
+						n.Connections.Delete(dialed.Address())
+					}
+
+					incoming.Close()
+					return
+				}
+
+				client = dialed
+				atomic.AddInt32(&client.connections, 1)
+				close(identified)
+
+				// Connections the peer identifies itself over once identified are
+				// additional paths to it.
+				if !atomic.CompareAndSwapUint32(&client.identified, 0, 1) {
+					<-client.incomingReady
+
+					if resumed {
+						n.observe(client, newConnInfo(conn, msg.Sender.Address, connectedAt))
+						n.watch(client, conn, msg.Sender.Address, connectedAt, closed)
+					}
+
+					return
+				}
+
+				client.setID((*peer.ID)(msg.Sender))
+				n.observe(client, newConnInfo(conn, msg.Sender.Address, connectedAt))
+				n.watch(client, conn, msg.Sender.Address, connectedAt, closed)
+
+				outgoing = state.(*ConnState).session
+
+				// Keep only the connection dialed by whichever of us has the lower ID.
+				if n.ResolveDuplicates && !resumed && !inbound && client.ID().Less(n.ID) {
+					n.yield(client, incoming)
+					outgoing = incoming
 				}
 
 				// Signal that the client is ready.
 				close(client.incomingReady)
-			})
 
-			if err != nil {
-				return
-			}
+				if !resumed {
+					n.issueTicket(client)
+				}
+			})
 
-			// Peer sent message with a completely different ID. Disconnect.
-			if !client.ID.Equals(peer.ID(*msg.Sender)) {
-				glog.Errorf("Message signed by peer %s but client is %s", peer.ID(*msg.Sender), client.ID.Address)
+			if err != nil || client == nil {
 				return
 			}
 
-			err = recvWindow.Input(msg)
-			if err == nil {
-				err = recvWindow.Update(n)
-			}
-
-			if err != nil {
+			if err := n.deliver(client, msg, recvWindow, conn.RemoteAddr().String(), closed); err != nil {
 				glog.Error(err)
 				incoming.Close()
 			}
-		}()
+		})
+
+	}
+}
+
+// deliver dispatches a message received from an identified peer, in order of its nonce
+// unless it is a priority message.
+func (n *Network) deliver(client *PeerClient, msg *protobuf.Message, recvWindow *RecvWindow, remote string, closed chan struct{}) error {
+	// Peer sent message with a completely different ID. Disconnect.
+	if !client.ID().Equals(peer.ID(*msg.Sender)) {
+		glog.Errorf("Message signed by peer %s but client is %s", peer.ID(*msg.Sender).Format(), client.ID().Format())
+		return nil
+	}
+
+	// Dispatch priority messages immediately, as they are not ordered. Whether they
+	// are is decided by their signed type, lest peers flag any message as a priority
+	// to skip ordering and scheduling.
+	if msg.Priority {
+		if !n.Priority.isPriority(msg.Message) {
+			glog.Warningf("Dropped message from %s flagged as a priority while not of a priority type", client.Address())
+			return nil
+		}
+
+		n.dispatchMessage(client, msg)
+		return nil
+	}
+
+	// Wait for our turn should other peers be hogging processing capacity.
+	if !n.Streams.Acquire(remote, closed) {
+		return nil
+	}
+	defer n.Streams.Release(remote)
+
+	if err := recvWindow.Input(msg); err != nil {
+		return err
+	}
+
+	return recvWindow.Update(n)
+}
+
+// enforceHandshakeTimeout drops an accepted connection should it not identify itself
+// within the handshake timeout, as it is likely to be a port scanner.
+func (n *Network) enforceHandshakeTimeout(conn net.Conn, incoming *smux.Session, identified chan struct{}, closed chan struct{}) {
+	if n.HandshakeTimeout <= 0 {
+		return
+	}
+
+	select {
+	case <-identified:
+	case <-closed:
+	case <-n.clock().After(n.HandshakeTimeout):
+		atomic.AddUint64(&n.handshakeTimeouts, 1)
+		glog.Warningf("Dropped connection from %s which did not handshake within %s; probable scanner.", conn.RemoteAddr(), n.HandshakeTimeout)
 
+		incoming.Close()
 	}
 }
 
@@ -456,6 +1021,45 @@ func (n *Network) Plugin(key interface{}) (PluginInterface, bool) {
 	return n.Plugins.Get(key)
 }
 
+// RegisterMessages registers the types of messages a plugin handles into the network's
+// message registry. As plugins are started by the network after it is built, it
+// registers them even should the registry be frozen. It is a no-op should message types
+// not be tracked.
+func (n *Network) RegisterMessages(messages ...proto.Message) error {
+	if n.Messages == nil {
+		return nil
+	}
+
+	for _, message := range messages {
+		if err := n.Messages.register(message, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sign signs a message on behalf of the node with its SignEnvelope, or with its Signer,
+// or with its Keys should neither be set.
+func (n *Network) Sign(message []byte) ([]byte, error) {
+	if n.SignEnvelope != nil {
+		return n.SignEnvelope(message)
+	}
+
+	if n.Signer != nil {
+		return n.Signer.Sign(n.SignaturePolicy, n.HashPolicy, message)
+	}
+
+	n.keysMutex.RLock()
+	defer n.keysMutex.RUnlock()
+
+	if n.keysClosed {
+		return nil, ErrKeysClosed
+	}
+
+	return n.Keys.Sign(n.SignaturePolicy, n.HashPolicy, message)
+}
+
 // PrepareMessage marshals a message into a *protobuf.Message and signs it with this
 // nodes private key. Errors if the message is null.
 func (n *Network) PrepareMessage(message proto.Message) (*protobuf.Message, error) {
@@ -463,131 +1067,294 @@ func (n *Network) PrepareMessage(message proto.Message) (*protobuf.Message, erro
 		return nil, errors.New("message is null")
 	}
 
+	ty := messageType(message)
+	start := n.clock().Now()
+
 	raw, err := ptypes.MarshalAny(message)
 	if err != nil {
 		return nil, err
 	}
 
+	n.observeLatency(PhaseSerialize, ty, start)
+
+	return n.prepareAny(ty, raw)
+}
+
+// PrepareAny signs a message already marshalled into an Any, whose type need not be
+// known to the network, such as to forward messages of unknown types with.
+func (n *Network) PrepareAny(raw *any.Any) (*protobuf.Message, error) {
+	if raw == nil {
+		return nil, errors.New("message is null")
+	}
+
+	return n.prepareAny(raw.TypeUrl, raw)
+}
+
+// prepareAny signs a marshalled message of a type into a *protobuf.Message.
+func (n *Network) prepareAny(ty string, raw *any.Any) (*protobuf.Message, error) {
 	id := protobuf.ID(n.ID)
+	start := n.clock().Now()
 
-	signature, err := n.Keys.Sign(
-		n.SignaturePolicy,
-		n.HashPolicy,
-		serializeMessage(&id, raw.Value),
-	)
+	messageID, err := n.newEnvelopeID()
 	if err != nil {
 		return nil, err
 	}
 
+	var lamport uint64
+	if n.LogicalClock != nil {
+		lamport = n.LogicalClock.Increment()
+	}
+
+	signature, err := n.Sign(n.envelopePayload(&id, messageID, raw, lamport, EnvelopeVersion))
+	if err != nil {
+		return nil, err
+	}
+
+	n.observeLatency(PhaseSign, ty, start)
+
 	msg := &protobuf.Message{}
 	msg.Message = raw
 	msg.Sender = &id
 	msg.Signature = signature
+	msg.Id = messageID
+	msg.Version = EnvelopeVersion
+	msg.MaxVersion = EnvelopeVersion
+	msg.Capabilities = uint64(n.Capabilities)
+	msg.LamportTimestamp = lamport
 
 	return msg, nil
 }
 
+// envelopeVersion returns the envelope version negotiated with a peer by its address.
+func (n *Network) envelopeVersion(address string) uint32 {
+	if client, exists := n.Peers.Load(address); exists {
+		return client.(*PeerClient).EnvelopeVersion()
+	}
+	return MinEnvelopeVersion
+}
+
 // Write asynchronously sends a message to a denoted target address.
 func (n *Network) Write(address string, message *protobuf.Message) error {
-	packet := packetPool.Get().(*Packet)
-	defer packetPool.Put(packet)
+	_, err := n.send(address, message)
+	return err
+}
 
+// send sends a message to an address, returning the envelope as it was written, which
+// is signed anew for peers which do not support domain-separated signatures. Messages
+// to peers which asked us to slow down are held back to be sent once they may be, in
+// which case the envelope is returned as it was given.
+func (n *Network) send(address string, message *protobuf.Message) (*protobuf.Message, error) {
+	if client, exists := n.Peers.Load(address); exists && !ptypes.Is(message.Message, (*protobuf.SlowDown)(nil)) {
+		if held, err := n.holdBack(client.(*PeerClient), message); held {
+			return message, err
+		}
+	}
+
+	return n.sendNow(address, message)
+}
+
+// sendNow sends a message to an address right away, regardless of whether its peer
+// asked us to slow down.
+func (n *Network) sendNow(address string, message *protobuf.Message) (*protobuf.Message, error) {
 	_state, exists := n.Connections.Load(address)
 	if !exists {
-		return errors.New("connection does not exist")
+		return nil, errors.Wrapf(ErrPeerNotFound, "no connection to %s", address)
 	}
 	state := _state.(*ConnState)
 
-	message.MessageNonce = atomic.AddUint64(&state.messageNonce, 1)
+	// Spread messages across the paths to the peer.
+	if client, exists := n.Peers.Load(address); exists {
+		state = client.(*PeerClient).paths.route(n, state)
+	}
+
+	return n.write(address, state, message)
+}
+
+// write sends a message to an address over a session, returning the envelope as it was
+// written.
+func (n *Network) write(address string, state *ConnState, message *protobuf.Message) (sent *protobuf.Message, err error) {
+	packet := packetPool.Get().(*Packet)
+	defer packetPool.Put(packet)
+
+	queue := n.SendQueue
+
+	// Send priority messages over their own lane to peers which support it. They carry
+	// no nonce, as they are not ordered w.r.t. other messages.
+	if n.Priority.isPriority(message.Message) && n.envelopeVersion(address) >= PriorityEnvelopeVersion {
+		message.Priority = true
+		message.MessageNonce = 0
+
+		queue = n.Priority.Workers.Queue
+	} else {
+		message.Priority = false
+		message.MessageNonce = atomic.AddUint64(&state.messageNonce, 1)
+	}
+
+	message.Ticket = state.presentedTicket(n.clock().Now())
+
+	// Sign our own messages anew at the version negotiated with peers which do not
+	// support the version they were signed at, as signatures of newer versions, such as
+	// domain-separated or batch signatures, may not be verified by older peers.
+	if version := n.envelopeVersion(address); version < message.Version && n.isOwnMessage(message) {
+		resigned, err := n.resign(message, version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to send message to %s", address)
+		}
+		message = resigned
+	}
+
+	// Encode the envelope in the version negotiated with the peer.
+	if client, exists := n.Peers.Load(address); exists {
+		downgraded, err := downgradeEnvelope(message, client.(*PeerClient).EnvelopeVersion())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to send message to %s", address)
+		}
+		message = downgraded
+
+		// Record pings before they are sent, such that their pongs are never mistaken
+		// for being unsolicited.
+		if ptypes.Is(message.Message, (*protobuf.Ping)(nil)) {
+			client := client.(*PeerClient)
+
+			client.pingSent()
+			defer func() {
+				if err != nil {
+					client.pingUnsent()
+				}
+			}()
+		}
+	}
 
 	packet.target = state
 	packet.payload = message
 	packet.result = make(chan interface{}, 1)
+	packet.bulk = nil
+
+	if !message.Priority {
+		packet.bulk = n.bulkEgress
+	}
+
+	size := int64(proto.Size(message))
+
+	n.queueCongestion(address, state, size)
+	defer func() {
+		n.settleCongestion(address, state, size, err)
+	}()
 
 	select {
-	case n.SendQueue <- packet:
+	case queue <- packet:
 	default:
-		return errors.New("send queue full")
+		return nil, errors.Wrapf(ErrQueueFull, "failed to send message to %s", address)
 	}
 
 	select {
 	case raw := <-packet.result:
-		switch err := raw.(type) {
+		switch result := raw.(type) {
 		case error:
-			return errors.Wrapf(err, "failed to send message to %s", address)
+			return nil, errors.Wrapf(result, "failed to send message to %s", address)
 		default:
-			return nil
+			// Workers report when they started writing the message.
+			if start, ok := result.(time.Time); ok {
+				name, _ := ptypes.AnyMessageName(message.Message)
+				n.observeLatency(PhaseWrite, name, start)
+			}
+
+			n.Plugins.Each(func(plugin PluginInterface) {
+				plugin.Outbound(address, message)
+			})
+
+			n.tap(true, address, message)
+			return message, nil
 		}
-	case <-time.After(3 * time.Second):
-		return errors.Errorf("worker must be too busy; failed to send message to %s", address)
+	case <-n.clock().After(3 * time.Second):
+		return nil, errors.Wrapf(ErrQueueFull, "worker must be too busy; failed to send message to %s", address)
 	}
 
-	return nil
+	return message, nil
 }
 
-// Broadcast asynchronously broadcasts a message to all peer clients.
-func (n *Network) Broadcast(message proto.Message) {
+// Broadcast asynchronously broadcasts a message to all peer clients. Returns the IDs of
+// all messages which were successfully sent.
+func (n *Network) Broadcast(message proto.Message) (ids []MessageID) {
 	n.Peers.Range(func(key, value interface{}) bool {
 		client := value.(*PeerClient)
 
-		err := client.Tell(message)
+		id, err := client.Tell(message)
 
 		if err != nil {
-			glog.Warningf("Failed to send message to peer %v [err=%s]", client.ID, err)
+			glog.Warningf("Failed to send message to peer %v [err=%s]", client.ID(), err)
+			return true
 		}
 
+		ids = append(ids, id)
+
 		return true
 	})
+
+	return
 }
 
 // BroadcastByAddresses broadcasts a message to a set of peer clients denoted by their addresses.
-func (n *Network) BroadcastByAddresses(message proto.Message, addresses ...string) {
+// Returns the IDs of all messages which were successfully sent.
+func (n *Network) BroadcastByAddresses(message proto.Message, addresses ...string) (ids []MessageID) {
 	signed, err := n.PrepareMessage(message)
 	if err != nil {
 		return
 	}
 
 	for _, address := range addresses {
-		n.Write(address, signed)
+		if sent, err := n.send(address, signed); err == nil {
+			ids = append(ids, NewMessageID(sent))
+		}
 	}
+
+	return
 }
 
 // BroadcastByIDs broadcasts a message to a set of peer clients denoted by their peer IDs.
-func (n *Network) BroadcastByIDs(message proto.Message, ids ...peer.ID) {
+// Returns the IDs of all messages which were successfully sent.
+func (n *Network) BroadcastByIDs(message proto.Message, peerIDs ...peer.ID) (ids []MessageID) {
 	signed, err := n.PrepareMessage(message)
 	if err != nil {
 		return
 	}
 
-	for _, id := range ids {
-		n.Write(id.Address, signed)
+	for _, peerID := range peerIDs {
+		if sent, err := n.send(peerID.Address, signed); err == nil {
+			ids = append(ids, NewMessageID(sent))
+		}
 	}
+
+	return
 }
 
-// BroadcastRandomly asynchronously broadcasts a message to random selected K peers.
-// Does not guarantee broadcasting to exactly K peers.
-func (n *Network) BroadcastRandomly(message proto.Message, K int) {
+// BroadcastBySelector broadcasts a message to the set of peer clients picked by a selector.
+// Returns the IDs of all messages which were successfully sent.
+func (n *Network) BroadcastBySelector(message proto.Message, selector Selector) []MessageID {
 	var addresses []string
 
-	n.Peers.Range(func(key, value interface{}) bool {
-		client := value.(*PeerClient)
-
-		addresses = append(addresses, client.Address)
+	for _, client := range selector.Select(n) {
+		addresses = append(addresses, client.Address())
+	}
 
-		// Limit total amount of addresses in case we have a lot of peers.
-		return len(addresses) <= K*3
-	})
+	return n.BroadcastByAddresses(message, addresses...)
+}
 
-	// Flip a coin and shuffle :).
-	rand.Shuffle(len(addresses), func(i, j int) {
-		addresses[i], addresses[j] = addresses[j], addresses[i]
-	})
+// BroadcastRandomly asynchronously broadcasts a message to random selected K peers,
+// or to as many peers as the estimated size of the network calls for should K be zero
+// or less. Peers are selected w.r.t. the network's ZonePreference should it be labelled
+// with a zone. Does not guarantee broadcasting to exactly K peers. Returns the IDs of all
+// messages which were successfully sent.
+func (n *Network) BroadcastRandomly(message proto.Message, K int) []MessageID {
+	if K <= 0 {
+		K = n.Fanout()
+	}
 
-	if len(addresses) < K {
-		K = len(addresses)
+	if len(n.Zone) > 0 {
+		return n.BroadcastBySelector(message, ZoneSelector{K: K, Preference: n.ZonePreference})
 	}
 
-	n.BroadcastByAddresses(message, addresses[:K]...)
+	return n.BroadcastBySelector(message, RandomSelector{K: K})
 }
 
 // Close shuts down the entire network.
@@ -600,4 +1367,13 @@ func (n *Network) Close() {
 		value.(*PeerClient).Close()
 		return true
 	})
+
+	// Zero our private key, as it is no longer needed to sign messages.
+	n.keysMutex.Lock()
+	defer n.keysMutex.Unlock()
+
+	if n.Keys != nil && !n.keysClosed {
+		n.Keys.Close()
+	}
+	n.keysClosed = true
 }
@@ -0,0 +1,72 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func buildNamespacedNode(t *testing.T, port uint16, networkID string) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+	builder.SetNetworkID(networkID)
+	builder.SetSignNetworkID(true)
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestNetworkID(t *testing.T) {
+	alice := buildNamespacedNode(t, 342, "mainnet")
+	bob := buildNamespacedNode(t, 343, "testnet")
+	carol := buildNamespacedNode(t, 344, "mainnet")
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	bobDatagrams, closeBob := bob.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeBob()
+
+	carolDatagrams, closeCarol := carol.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeCarol()
+
+	tell := func(address string) {
+		client, err := alice.Client(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := client.Tell(&protobuf.Datagram{Data: []byte("hello")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tell(carol.Address)
+	receiveDatagrams(t, carolDatagrams, 1)
+
+	tell(bob.Address)
+
+	select {
+	case <-bobDatagrams:
+		t.Fatal("expected a peer of another network to be refused")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	if client, exists := bob.Peers.Load(alice.Address); exists && client.(*network.PeerClient).ID() != nil {
+		t.Fatal("expected a peer of another network to not be identified")
+	}
+}
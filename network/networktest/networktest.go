@@ -0,0 +1,55 @@
+// Package networktest builds networks for tests, listening on free local ports such
+// that tests neither pick ports by hand nor collide with one another.
+package networktest
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+)
+
+// Address is the address test networks listen on, being a free port on the loopback
+// interface picked once they listen.
+var Address = network.FormatAddress("tcp", "127.0.0.1", 0)
+
+// NewBuilder returns a network builder with random keys, set to listen on Address.
+func NewBuilder() *builders.NetworkBuilder {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(Address)
+
+	return builder
+}
+
+// Start builds a network, and returns it once it is listening. The test fails should
+// the network fail to build or listen. Closing the network is left to the test.
+func Start(t testing.TB, builder *builders.NetworkBuilder) *network.Network {
+	t.Helper()
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+// NewNode starts a network with a set of plugins listening on Address.
+func NewNode(t testing.TB, plugins ...network.PluginInterface) *network.Network {
+	t.Helper()
+
+	builder := NewBuilder()
+	for _, plugin := range plugins {
+		if err := builder.AddPlugin(plugin); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return Start(t, builder)
+}
@@ -1,5 +1,11 @@
 package network
 
+import (
+	"net"
+
+	"github.com/perlin-network/noise/protobuf"
+)
+
 // PluginInterface is used to proxy callbacks to a particular Plugin instance.
 type PluginInterface interface {
 	// Callback for when the network starts listening for peers.
@@ -17,13 +23,36 @@ type PluginInterface interface {
 
 	// Callback for when a peer disconnects from the network.
 	PeerDisconnect(client *PeerClient)
+
+	// Callback for when a peer resumes its connection from a new address, having been
+	// connected at the address from.
+	PeerMigrate(client *PeerClient, from string)
+
+	// Callback for when a peer is observed at a new network address, such as should its
+	// connection have migrated.
+	AddressChanged(client *PeerClient, from net.Addr, to net.Addr)
+
+	// Callback for when a verified message envelope is received from a peer.
+	Inbound(client *PeerClient, msg *protobuf.Message)
+
+	// Callback for when a message envelope is sent to an address.
+	Outbound(address string, msg *protobuf.Message)
+
+	// Callback for when a message to an address is dropped, or when the address
+	// becomes or ceases to be congested.
+	Congestion(address string, state CongestionState)
 }
 
 // Plugin is an abstract class which all plugins extend.
 type Plugin struct{}
 
-func (*Plugin) Startup(net *Network)              {}
-func (*Plugin) Receive(ctx *PluginContext) error  { return nil }
-func (*Plugin) Cleanup(net *Network)              {}
-func (*Plugin) PeerConnect(client *PeerClient)    {}
-func (*Plugin) PeerDisconnect(client *PeerClient) {}
+func (*Plugin) Startup(net *Network)                                          {}
+func (*Plugin) Receive(ctx *PluginContext) error                              { return nil }
+func (*Plugin) Cleanup(net *Network)                                          {}
+func (*Plugin) PeerConnect(client *PeerClient)                                {}
+func (*Plugin) PeerDisconnect(client *PeerClient)                             {}
+func (*Plugin) PeerMigrate(client *PeerClient, from string)                   {}
+func (*Plugin) AddressChanged(client *PeerClient, from net.Addr, to net.Addr) {}
+func (*Plugin) Inbound(client *PeerClient, msg *protobuf.Message)             {}
+func (*Plugin) Outbound(address string, msg *protobuf.Message)                {}
+func (*Plugin) Congestion(address string, state CongestionState)              {}
@@ -0,0 +1,331 @@
+// Package pool pre-dials and keeps warm connections to peers anticipated to be needed
+// soon, such as the next epoch's validator set, such that the first messages sent to
+// them do not wait on a dial.
+package pool
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/backoff"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/schedule"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProtectionTag is the tag warm peers are protected on behalf of, such that they
+	// are not disconnected from or evicted while warm.
+	ProtectionTag = "pool"
+
+	defaultInterval = 10 * time.Second
+)
+
+// WarmPeer is a peer kept warm by the pool.
+type WarmPeer struct {
+	Address string `json:"address"`
+
+	// Expires is when the peer stops being kept warm.
+	Expires time.Time `json:"expires"`
+
+	// Connected is true should the peer currently be connected to.
+	Connected bool `json:"connected"`
+}
+
+type warmPeer struct {
+	expires time.Time
+
+	// id is the identity the peer is protected as. Nil until the peer identifies itself.
+	id *peer.ID
+
+	// dialing is true should the peer be being dialed, and pending should it be
+	// scheduled to be.
+	dialing bool
+	pending *schedule.Task
+
+	// backoff paces redialing the peer should dialing it keep failing, and retry is when
+	// it may next be redialed.
+	backoff *backoff.Backoff
+	retry   time.Time
+}
+
+// Plugin keeps connections to peers warm until they expire, redialing those which
+// disconnect in the meantime.
+type Plugin struct {
+	*network.Plugin
+
+	// Interval is how often warm peers are expired, and redialed should they have
+	// disconnected. Zero if the default interval.
+	Interval time.Duration
+
+	// Policy is the backoff policy warm peers failing to be dialed are redialed with,
+	// until they expire regardless of its maximum number of attempts. Nil if the default
+	// policy.
+	Policy *backoff.Backoff
+
+	net *network.Network
+
+	// peers holds warm peers by their addresses rather than as values attached to them,
+	// as they are to be redialed once disconnected.
+	mutex sync.Mutex
+	peers map[string]*warmPeer
+
+	task *schedule.Task
+}
+
+var PluginID = (*Plugin)(nil)
+
+func (p *Plugin) policy() *backoff.Backoff {
+	if p.Policy == nil {
+		return backoff.DefaultBackoff()
+	}
+	return p.Policy
+}
+
+func (p *Plugin) interval() time.Duration {
+	if p.Interval <= 0 {
+		return defaultInterval
+	}
+	return p.Interval
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.net = net
+	p.task = net.Scheduler().Repeat(schedule.Every(p.interval()), p.maintain)
+}
+
+// Cleanup implements the plugin callback
+func (p *Plugin) Cleanup(net *network.Network) {
+	if p.task != nil {
+		p.task.Cancel()
+	}
+}
+
+// PeerDisconnect implements the plugin callback
+func (p *Plugin) PeerDisconnect(client *network.PeerClient) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if state, warm := p.peers[client.Address()]; warm {
+		p.redial(client.Address(), state)
+	}
+}
+
+// PeerMigrate implements the plugin callback. Warm peers are kept by their addresses,
+// as they must be redialed once disconnected, and so follow peers whose addresses
+// change.
+func (p *Plugin) PeerMigrate(client *network.PeerClient, from string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	state, warm := p.peers[from]
+	if !warm {
+		return
+	}
+
+	delete(p.peers, from)
+
+	// Merge the peer into its entry at its new address, should it already be warm there.
+	if existing, exists := p.peers[client.Address()]; exists {
+		if state.expires.After(existing.expires) {
+			existing.expires = state.expires
+		}
+
+		if existing.id == nil {
+			existing.id = state.id
+		}
+
+		return
+	}
+
+	p.peers[client.Address()] = state
+}
+
+// Warm pre-dials peers by their addresses in the background, and keeps them connected
+// to for a lifetime. Peers already warm have their expiry extended should the lifetime
+// outlast it. It fails should the plugin not have been started by a network.
+func (p *Plugin) Warm(lifetime time.Duration, addresses ...string) error {
+	if p.net == nil {
+		return errors.New("pool has not been started by a network")
+	}
+
+	expires := clock.Or(p.net.Clock).Now().Add(lifetime)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.peers == nil {
+		p.peers = make(map[string]*warmPeer)
+	}
+
+	for _, address := range addresses {
+		state, exists := p.peers[address]
+		if !exists {
+			state = &warmPeer{expires: expires, backoff: p.policy().Clone()}
+			p.peers[address] = state
+		} else if expires.After(state.expires) {
+			state.expires = expires
+		}
+
+		p.redial(address, state)
+	}
+
+	return nil
+}
+
+// Cool stops keeping peers by their addresses warm ahead of their expiry.
+func (p *Plugin) Cool(addresses ...string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, address := range addresses {
+		p.expire(address)
+	}
+}
+
+// Peers returns all peers kept warm, sorted by their addresses.
+func (p *Plugin) Peers() []WarmPeer {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	peers := make([]WarmPeer, 0, len(p.peers))
+	for address, state := range p.peers {
+		peers = append(peers, WarmPeer{Address: address, Expires: state.expires, Connected: p.connected(address)})
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Address < peers[j].Address
+	})
+
+	return peers
+}
+
+// maintain stops keeping peers warm past their expiry, and redials those which
+// disconnected.
+func (p *Plugin) maintain() {
+	now := clock.Or(p.net.Clock).Now()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for address, state := range p.peers {
+		if !now.Before(state.expires) {
+			p.expire(address)
+			continue
+		}
+
+		if !p.connected(address) {
+			p.redial(address, state)
+			continue
+		}
+
+		if client, exists := p.net.Peers.Load(address); exists {
+			p.protect(state, client.(*network.PeerClient))
+		}
+	}
+}
+
+// redial schedules a warm peer to be dialed once its backoff elapses, should it not
+// already be being dialed or scheduled to be. It must be called with the mutex held.
+func (p *Plugin) redial(address string, state *warmPeer) {
+	if state.dialing || state.pending != nil {
+		return
+	}
+
+	delay := state.retry.Sub(clock.Or(p.net.Clock).Now())
+	if delay < 0 {
+		delay = 0
+	}
+
+	state.pending = p.net.Scheduler().After(delay, func() { p.dial(address) })
+}
+
+// expire stops keeping a peer warm, lifting its protection. It must be called with
+// the mutex held.
+func (p *Plugin) expire(address string) {
+	state, exists := p.peers[address]
+	if !exists {
+		return
+	}
+
+	if state.id != nil {
+		p.net.UnprotectPeer(*state.id, ProtectionTag)
+	}
+
+	if state.pending != nil {
+		state.pending.Cancel()
+	}
+
+	delete(p.peers, address)
+}
+
+// dial connects to and pings a warm peer should it not already be being dialed, and
+// protects it once it identified itself. Should dialing fail, the peer is only redialed
+// once its backoff elapses.
+func (p *Plugin) dial(address string) {
+	p.mutex.Lock()
+
+	state, exists := p.peers[address]
+	if !exists || state.dialing {
+		p.mutex.Unlock()
+		return
+	}
+	state.dialing = true
+	state.pending = nil
+
+	p.mutex.Unlock()
+
+	client, err := p.net.Client(address)
+	if err == nil {
+		// Ping the peer to complete the handshake ahead of time, and for it to identify
+		// itself in response.
+		_, err = client.Tell(p.net.NewPing())
+	}
+
+	// Protect the peer only once it identified itself, failing which it is protected
+	// by the next round of maintenance.
+	identified := err == nil && client.IncomingReady()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	state.dialing = false
+
+	if err != nil {
+		state.retry = clock.Or(p.net.Clock).Now().Add(state.backoff.NextDuration())
+
+		glog.Warningf("Failed to warm up connection to %s, retrying at %s [err=%s]", address, state.retry, err)
+		return
+	}
+
+	state.backoff.Reset()
+
+	// The peer may have been cooled while being dialed.
+	if identified && p.peers[address] == state {
+		p.protect(state, client)
+	}
+}
+
+// protect protects a warm peer should it have identified itself and not yet be
+// protected. It must be called with the mutex held.
+func (p *Plugin) protect(state *warmPeer, client *network.PeerClient) {
+	if state.id != nil || client.ID() == nil {
+		return
+	}
+
+	id := *client.ID()
+	state.id = &id
+
+	p.net.ProtectPeer(id, ProtectionTag)
+}
+
+// connected returns true should a peer by its address be connected to.
+func (p *Plugin) connected(address string) bool {
+	_, connected := p.net.Connections.Load(address)
+	return connected
+}
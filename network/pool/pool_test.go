@@ -0,0 +1,125 @@
+package pool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/backoff"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func buildNode(t *testing.T, port uint16, plugin *Plugin) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+
+	builder.AddPlugin(new(discovery.Plugin))
+
+	if plugin != nil {
+		builder.AddPlugin(plugin)
+	}
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func waitFor(t *testing.T, description string, condition func() bool) {
+	deadline := time.Now().Add(3 * time.Second)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", description)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWarm(t *testing.T) {
+	plugin := &Plugin{Interval: 20 * time.Millisecond}
+
+	alice := buildNode(t, 366, plugin)
+	bob := buildNode(t, 367, nil)
+	carol := buildNode(t, 368, nil)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	if err := plugin.Warm(time.Minute, bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := plugin.Warm(100*time.Millisecond, carol.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, "bob to be warmed up and protected", func() bool {
+		peers := plugin.Peers()
+		return len(peers) > 0 && peers[0].Address == bob.Address && peers[0].Connected && alice.IsProtected(bob.ID)
+	})
+
+	waitFor(t, "carol to expire", func() bool {
+		peers := plugin.Peers()
+		return len(peers) == 1 && peers[0].Address == bob.Address && !alice.IsProtected(carol.ID)
+	})
+
+	plugin.Cool(bob.Address)
+
+	if peers := plugin.Peers(); len(peers) != 0 {
+		t.Fatalf("expected no peers to be kept warm, got %+v", peers)
+	}
+
+	if alice.IsProtected(bob.ID) {
+		t.Fatal("expected bob to no longer be protected once cooled")
+	}
+}
+
+func TestWarmBeforeStartup(t *testing.T) {
+	if err := new(Plugin).Warm(time.Minute, network.FormatAddress("mem", "127.0.0.1", 391)); err == nil {
+		t.Fatal("expected warming peers before the plugin is started to fail")
+	}
+}
+
+func TestRedialBacksOff(t *testing.T) {
+	plugin := &Plugin{
+		Interval: 10 * time.Millisecond,
+		Policy:   &backoff.Backoff{MinInterval: time.Hour, MaxInterval: 2 * time.Hour},
+	}
+
+	alice := buildNode(t, 392, plugin)
+	defer alice.Close()
+
+	// Nobody listens at the address, and so dialing it fails.
+	address := network.FormatAddress("mem", "127.0.0.1", 393)
+
+	if err := plugin.Warm(time.Minute, address); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := func() int {
+		plugin.mutex.Lock()
+		defer plugin.mutex.Unlock()
+
+		return plugin.peers[address].backoff.Attempts()
+	}
+
+	waitFor(t, "dialing to fail", func() bool { return attempts() > 0 })
+
+	// Maintenance does not redial the peer until its backoff elapses.
+	time.Sleep(100 * time.Millisecond)
+
+	if n := attempts(); n != 1 {
+		t.Fatalf("expected the peer to be dialed once within its backoff, got %d attempts", n)
+	}
+}
@@ -0,0 +1,86 @@
+package network
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+)
+
+const (
+	// DefaultPriorityWorkers is the number of workers dedicated to sending priority messages by default.
+	DefaultPriorityWorkers = 2
+
+	// MaxPriorityShare is the largest share of egress bandwidth which may be reserved
+	// for priority messages, such that bulk traffic is never starved entirely.
+	MaxPriorityShare = 0.9
+)
+
+// PriorityLane sends messages of select types, such as consensus votes, ahead of all
+// others. They are sent by dedicated workers rather than being queued behind bulk
+// traffic, and are dispatched by peers as soon as they are received rather than in
+// order. A nil PriorityLane sends all messages in order.
+type PriorityLane struct {
+	// Workers send priority messages.
+	Workers *WorkerPool
+
+	// Share is the fraction of the egress bandwidth limit reserved for priority
+	// messages, which all other messages may not use. It is capped to MaxPriorityShare,
+	// and zero if no bandwidth is reserved.
+	Share float64
+
+	types map[string]struct{}
+}
+
+// NewPriorityLane creates a priority lane for messages of the types of messages, sent
+// by a number of dedicated workers.
+func NewPriorityLane(workers int, messages ...proto.Message) *PriorityLane {
+	lane := &PriorityLane{
+		Workers: NewWorkerPool(workers, nil),
+		types:   make(map[string]struct{}),
+	}
+
+	for _, message := range messages {
+		lane.Add(message)
+	}
+
+	return lane
+}
+
+// Add sends messages of the type of message over the priority lane.
+func (l *PriorityLane) Add(message proto.Message) {
+	l.types[proto.MessageName(message)] = struct{}{}
+}
+
+// Contains returns true should messages of a type, by its fully-qualified name, be
+// sent over the priority lane.
+func (l *PriorityLane) Contains(name string) bool {
+	if l == nil {
+		return false
+	}
+
+	_, exists := l.types[name]
+	return exists
+}
+
+// share returns the share of egress bandwidth reserved for priority messages.
+func (l *PriorityLane) share() float64 {
+	if l == nil || l.Share <= 0 {
+		return 0
+	}
+
+	if l.Share > MaxPriorityShare {
+		return MaxPriorityShare
+	}
+
+	return l.Share
+}
+
+// isPriority returns true should an envelope be sent over the priority lane.
+func (l *PriorityLane) isPriority(message *any.Any) bool {
+	if l == nil || message == nil {
+		return false
+	}
+
+	name, err := ptypes.AnyMessageName(message)
+	return err == nil && l.Contains(name)
+}
@@ -0,0 +1,138 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func buildPriorityNode(t *testing.T, port uint16) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", port))
+	builder.AddPlugin(new(discovery.Plugin))
+	builder.AddPriorityMessageType(new(protobuf.Datagram))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go node.Listen()
+	node.BlockUntilListening()
+
+	return node
+}
+
+func TestPriorityLane(t *testing.T) {
+	alice := buildPriorityNode(t, 13170)
+	bob := buildPriorityNode(t, 13171)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	datagrams, closeDatagrams := bob.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeDatagrams()
+
+	pings, closePings := bob.Tap(network.TapFilter{Types: []string{"protobuf.Ping"}, Inbound: true})
+	defer closePings()
+
+	alice.Bootstrap(bob.Address)
+
+	// Wait for alice to learn bob's envelope version off of his pong.
+	select {
+	case <-pings:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for bootstrap ping")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(&protobuf.Datagram{Data: []byte("vote")}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case tapped := <-datagrams:
+		if !tapped.Message.Priority || tapped.Message.MessageNonce != 0 {
+			t.Fatalf("expected datagram to be sent over the priority lane, got %+v", tapped.Message)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for priority message")
+	}
+
+	// Ordered messages should still be delivered, as priority messages carry no nonce.
+	if _, err := client.Tell(&protobuf.Ping{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case tapped := <-pings:
+		if tapped.Message.Priority {
+			t.Fatal("expected ping to be sent in order")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for ordered message")
+	}
+}
+
+func TestPriorityFlagOfOtherTypes(t *testing.T) {
+	alice := buildPriorityNode(t, 13228)
+	bob := buildNode(t, 13229, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	datagrams, closeDatagrams := bob.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeDatagrams()
+
+	pings, closePings := bob.Tap(network.TapFilter{Types: []string{"protobuf.Ping"}, Inbound: true})
+	defer closePings()
+
+	alice.Bootstrap(bob.Address)
+
+	select {
+	case <-pings:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for bootstrap ping")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob does not send datagrams over a priority lane, and thereby drops those flagged
+	// as priority messages rather than have them skip ordering.
+	if _, err := client.Tell(&protobuf.Datagram{Data: []byte("vote")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(&protobuf.Ping{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-pings:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for ordered message")
+	}
+
+	select {
+	case tapped := <-datagrams:
+		t.Fatalf("expected datagram flagged as a priority to be dropped, got %+v", tapped.Message)
+	default:
+	}
+}
@@ -0,0 +1,81 @@
+package network
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// DefaultProbeSize is the number of bytes echoed to probe the bandwidth of links by
+// default.
+const DefaultProbeSize = 64 * 1024
+
+// LinkProbe is the round-trip time and bandwidth of the link to a peer, measured by a
+// short exchange of echoes.
+type LinkProbe struct {
+	// RTT is the round-trip time of an unpadded echo.
+	RTT time.Duration
+
+	// Bandwidth is the estimated throughput of the link in bytes per second, averaged
+	// over both directions.
+	Bandwidth float64
+
+	// ProbedAt is when the link was probed.
+	ProbedAt time.Time
+}
+
+// ProbeLink measures the round-trip time and bandwidth of the link to a peer by echoing
+// an unpadded echo, followed by one padded with size bytes which the peer echoes back.
+// The bandwidth is estimated from how much longer the padded echo took, and the probe
+// is stored on the client. The round-trip time is recorded in the peer store.
+func (n *Network) ProbeLink(ctx context.Context, client *PeerClient, size int) (LinkProbe, error) {
+	sample, err := n.echo(ctx, client, nil)
+	if err != nil {
+		return LinkProbe{}, err
+	}
+
+	padded, err := n.echo(ctx, client, make([]byte, size))
+	if err != nil {
+		return LinkProbe{}, err
+	}
+
+	// Padding too small to be told apart from jitter only bounds the bandwidth from
+	// below.
+	transfer := padded.RTT - sample.RTT
+	if transfer <= 0 {
+		transfer = padded.RTT
+	}
+
+	probe := LinkProbe{
+		RTT:       sample.RTT,
+		Bandwidth: float64(2*size) / transfer.Seconds(),
+		ProbedAt:  n.clock().Now(),
+	}
+
+	client.probe.Store(probe)
+	n.Peerstore.RecordRTT(client.Address(), sample.RTT)
+
+	return probe, nil
+}
+
+// probeLink probes the link to a newly connected peer should the network probe links.
+func (n *Network) probeLink(client *PeerClient) {
+	if n.ProbeSize <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*DefaultPingTimeout)
+	defer cancel()
+
+	if _, err := n.ProbeLink(ctx, client, n.ProbeSize); err != nil {
+		glog.Warningf("Failed to probe link to %s [err=%s]", client.Address(), err)
+	}
+}
+
+// LinkProbe returns the round-trip time and bandwidth last probed of the link to the
+// peer. False if the link was never probed.
+func (c *PeerClient) LinkProbe() (LinkProbe, bool) {
+	probe, ok := c.probe.Load().(LinkProbe)
+	return probe, ok
+}
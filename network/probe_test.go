@@ -0,0 +1,74 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func buildProbingNode(t *testing.T, port uint16, probeSize int) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+	builder.SetProbeSize(probeSize)
+	builder.AddPlugin(new(discovery.Plugin))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestProbeLinkUponConnecting(t *testing.T) {
+	alice := buildProbingNode(t, 354, network.DefaultProbeSize)
+	bob := buildProbingNode(t, 355, 0)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+
+	probe, ok := client.LinkProbe()
+	for !ok {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the link to be probed")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		probe, ok = client.LinkProbe()
+	}
+
+	if probe.RTT <= 0 || probe.Bandwidth <= 0 {
+		t.Fatalf("unexpected link probe %+v", probe)
+	}
+
+	if client.RTT() != probe.RTT {
+		t.Fatalf("expected round-trip time %s to be recorded, got %s", probe.RTT, client.RTT())
+	}
+
+	if clients := (network.BandwidthSelector{K: 1}).Select(alice); len(clients) != 1 || clients[0].Address() != bob.Address {
+		t.Fatal("expected the probed peer to be selected")
+	}
+
+	// Bob does not probe links.
+	if bobClient, err := bob.Client(alice.Address); err != nil {
+		t.Fatal(err)
+	} else if _, ok := bobClient.LinkProbe(); ok {
+		t.Fatal("expected links not to be probed with probing disabled")
+	}
+}
@@ -0,0 +1,128 @@
+package protection
+
+import (
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultMaxMessageSize is the largest message allowed by the default pipeline.
+	DefaultMaxMessageSize = 1 << 20
+
+	// DefaultMessageRate is the number of messages per second allowed per peer by the
+	// default pipeline.
+	DefaultMessageRate = 100
+
+	// DefaultMessageBurst is the burst of messages allowed per peer by the default pipeline.
+	DefaultMessageBurst = 200
+
+	// DefaultReplayWindow is how long messages are remembered by the default pipeline
+	// to detect replays.
+	DefaultReplayWindow = 1 * time.Minute
+
+	// DefaultReplayCapacity is the maximum number of messages remembered by the default
+	// pipeline to detect replays, beyond which messages are dropped within the window.
+	DefaultReplayCapacity = 100000
+)
+
+// Pipeline runs incoming messages through a sequence of filters, penalizing the
+// sender of any message which is dropped, other than those dropped as suppressed.
+type Pipeline struct {
+	// Scorer tracks the reputation of peers and drops all messages from banned peers.
+	// Nil if peers should not be scored.
+	Scorer *Scorer
+
+	// Filters which incoming messages are run through in order.
+	Filters []network.MessageFilter
+}
+
+// NewPipeline creates a new pipeline scoring peers with a scorer, and running messages
+// through a sequence of filters.
+func NewPipeline(scorer *Scorer, filters ...network.MessageFilter) *Pipeline {
+	return &Pipeline{
+		Scorer:  scorer,
+		Filters: filters,
+	}
+}
+
+// Default creates a pipeline which limits the size, rate and replay of messages, and
+// bans misbehaving peers, as measured by a clock. The real clock is used should it be
+// nil.
+func Default(c clock.Clock) *Pipeline {
+	return NewPipeline(
+		NewScorer(c),
+		NewSizeLimit(DefaultMaxMessageSize),
+		NewRateLimit(DefaultMessageRate, DefaultMessageBurst, c),
+		NewReplayFilter(DefaultReplayWindow, DefaultReplayCapacity, c),
+	)
+}
+
+// FilterMessage implements network.MessageFilter.
+func (p *Pipeline) FilterMessage(client *network.PeerClient, msg *protobuf.Message) error {
+	if p.Scorer != nil {
+		if err := p.Scorer.FilterMessage(client, msg); err != nil {
+			return err
+		}
+	}
+
+	for _, filter := range p.Filters {
+		if err := filter.FilterMessage(client, msg); err != nil {
+			if p.Scorer != nil && !protected(client, msg) && penalizes(err) {
+				p.Scorer.Penalize(senderKey(msg))
+			}
+			return err
+		}
+	}
+
+	if p.Scorer != nil {
+		p.Scorer.Reward(senderKey(msg))
+	}
+
+	return nil
+}
+
+// ReplayFilter returns the replay filter of the pipeline, such as to persist the IDs of
+// seen messages with. Nil if the pipeline filters no replays.
+func (p *Pipeline) ReplayFilter() *ReplayFilter {
+	for _, filter := range p.Filters {
+		if filter, ok := filter.(*ReplayFilter); ok {
+			return filter
+		}
+	}
+	return nil
+}
+
+// Register registers the default protection pipeline onto a network builder, and
+// returns it for further configuration. Messages from banned peers are dropped before
+// their signatures are verified. The pipeline is measured by the builder's clock, and
+// so is to be registered after the clock is set.
+func Register(builder *builders.NetworkBuilder) *Pipeline {
+	pipeline := Default(builder.Clock())
+	builder.AddPreFilter(pipeline.Scorer)
+	builder.AddMessageFilter(pipeline)
+	return pipeline
+}
+
+// senderKey returns the key peers are accounted for by within the pipeline.
+func senderKey(msg *protobuf.Message) string {
+	return peer.ID(*msg.Sender).PublicKeyHex()
+}
+
+// protected returns true should the sender of a message be protected by the network
+// it was received on, exempting it from rate limits and bans.
+func protected(client *network.PeerClient, msg *protobuf.Message) bool {
+	return client != nil && client.Network != nil && client.Network.IsProtected(peer.ID(*msg.Sender))
+}
+
+// penalizes returns true should the sender of a message dropped by a filter be at fault
+// for it being dropped.
+func penalizes(err error) bool {
+	cause := errors.Cause(err)
+	return cause != ErrSuppressed && cause != ErrNotRemembered
+}
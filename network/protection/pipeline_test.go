@@ -0,0 +1,362 @@
+package protection
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/peerstore"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+func createMessage(publicKey string, id uint64, value string) *protobuf.Message {
+	sender := protobuf.ID(peer.CreateID("tcp://127.0.0.1:3000", []byte(publicKey)))
+
+	messageID := make([]byte, 8)
+	binary.LittleEndian.PutUint64(messageID, id)
+
+	return &protobuf.Message{
+		Message: &any.Any{TypeUrl: "type.googleapis.com/protobuf.Bytes", Value: []byte(value)},
+		Sender:  &sender,
+		Id:      messageID,
+	}
+}
+
+func TestSizeLimit(t *testing.T) {
+	limit := NewSizeLimit(128)
+
+	if err := limit.FilterMessage(nil, createMessage("a", 1, "small")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := limit.FilterMessage(nil, createMessage("a", 1, string(make([]byte, 256)))); err == nil {
+		t.Fatal("expected large message to be dropped")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	limit := NewRateLimit(1, 2, nil)
+
+	for i := uint64(0); i < 2; i++ {
+		if err := limit.FilterMessage(nil, createMessage("a", i, "hello")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := limit.FilterMessage(nil, createMessage("a", 3, "hello")); err == nil {
+		t.Fatal("expected message exceeding rate limit to be dropped")
+	}
+
+	if err := limit.FilterMessage(nil, createMessage("b", 1, "hello")); err != nil {
+		t.Fatalf("expected other peers to be unaffected, got %v", err)
+	}
+}
+
+func TestReplayFilter(t *testing.T) {
+	clk := clock.NewMock(time.Now())
+	filter := NewReplayFilter(100*time.Millisecond, 2, clk)
+
+	if err := filter.FilterMessage(nil, createMessage("a", 1, "hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := filter.FilterMessage(nil, createMessage("a", 1, "hello")); err == nil {
+		t.Fatal("expected replayed message to be dropped")
+	}
+
+	// Neither fresh nonces nor fresh connections make a message any less of a replay.
+	resent := createMessage("a", 1, "hello")
+	resent.MessageNonce = 7
+
+	if err := filter.FilterMessage(nil, resent); err == nil {
+		t.Fatal("expected message re-sent with a fresh nonce to be dropped")
+	}
+
+	if err := filter.FilterMessage(nil, createMessage("a", 2, "hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Messages seen within the window are never forgotten to make room for others.
+	if err := filter.FilterMessage(nil, createMessage("a", 3, "hello")); errors.Cause(err) != ErrNotRemembered {
+		t.Fatalf("expected message overflowing capacity to be dropped, got %v", err)
+	}
+
+	clk.Add(150 * time.Millisecond)
+
+	if err := filter.FilterMessage(nil, createMessage("a", 1, "hello")); err != nil {
+		t.Fatalf("expected message to be forgotten after the replay window, got %v", err)
+	}
+}
+
+func TestReplayFilterPersists(t *testing.T) {
+	backend := peerstore.NewMemoryBackend()
+
+	filter := NewReplayFilter(time.Minute, 2, nil)
+	if err := filter.Persist(backend); err != nil {
+		t.Fatal(err)
+	}
+
+	for nonce := uint64(1); nonce <= 2; nonce++ {
+		if err := filter.FilterMessage(nil, createMessage("a", nonce, "pay")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Restart, having only the backend survive.
+	restarted := NewReplayFilter(time.Minute, 2, nil)
+	if err := restarted.Persist(backend); err != nil {
+		t.Fatal(err)
+	}
+
+	for nonce := uint64(1); nonce <= 2; nonce++ {
+		if err := restarted.FilterMessage(nil, createMessage("a", nonce, "pay")); err == nil {
+			t.Fatalf("expected message %d seen before restarting to be dropped as a replay", nonce)
+		}
+	}
+
+	// Messages seen outside of the window are forgotten upon being loaded.
+	expired := NewReplayFilter(time.Nanosecond, 2, nil)
+	if err := expired.Persist(backend); err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	backend.Iterate(replayBucket, func(key string, value []byte) error {
+		count++
+		return nil
+	})
+
+	if count != 0 {
+		t.Fatalf("expected expired messages to be forgotten, %d remain", count)
+	}
+}
+
+type failingBackend struct {
+	*peerstore.MemoryBackend
+}
+
+func (b failingBackend) Put(bucket string, key string, value []byte) error {
+	return errors.New("disk full")
+}
+
+func TestReplayFilterPersistFailure(t *testing.T) {
+	filter := NewReplayFilter(time.Minute, 2, nil)
+	if err := filter.Persist(failingBackend{peerstore.NewMemoryBackend()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Messages which may not be remembered across restarts are dropped, rather than
+	// risk them being handled twice.
+	if err := filter.FilterMessage(nil, createMessage("a", 1, "pay")); errors.Cause(err) != ErrNotRemembered {
+		t.Fatalf("expected message which failed to be persisted to be dropped, got %v", err)
+	}
+
+	if n := filter.order.Len(); n != 0 {
+		t.Fatalf("expected message which failed to be persisted to not be remembered, %d are", n)
+	}
+}
+
+func TestStormFilter(t *testing.T) {
+	clk := clock.NewMock(time.Now())
+
+	filter := NewStormFilter(3, time.Minute, clk)
+	filter.Suppression = 100 * time.Millisecond
+
+	var alerts []StormAlert
+	filter.OnStorm = func(alert StormAlert) {
+		alerts = append(alerts, alert)
+	}
+
+	// The same payload relayed by different peers is not a storm.
+	for i, sender := range []string{"a", "b", "c", "d"} {
+		if err := filter.FilterMessage(nil, createMessage(sender, uint64(i), "storm")); err != nil {
+			t.Fatalf("expected payloads relayed by different peers to pass, got %v", err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := filter.FilterMessage(nil, createMessage("a", uint64(5+i), "storm")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := filter.FilterMessage(nil, createMessage("a", 7, "storm")); err == nil {
+		t.Fatal("expected a storming payload to be dropped")
+	}
+
+	if len(alerts) != 1 || alerts[0].Count != 4 || alerts[0].TypeURL != "type.googleapis.com/protobuf.Bytes" || alerts[0].Sender != senderKey(createMessage("a", 0, "")) {
+		t.Fatalf("expected a single alert for the storm, got %+v", alerts)
+	}
+
+	if err := filter.FilterMessage(nil, createMessage("a", 8, "storm")); errors.Cause(err) != ErrSuppressed {
+		t.Fatalf("expected a storming payload to stay suppressed, got %v", err)
+	}
+
+	if len(alerts) != 1 {
+		t.Fatalf("expected no further alerts while suppressed, got %+v", alerts)
+	}
+
+	// The payload is only suppressed from the peer storming it.
+	if err := filter.FilterMessage(nil, createMessage("b", 9, "storm")); err != nil {
+		t.Fatalf("expected the payload from other peers to be unaffected, got %v", err)
+	}
+
+	if err := filter.FilterMessage(nil, createMessage("a", 10, "calm")); err != nil {
+		t.Fatalf("expected other payloads to be unaffected, got %v", err)
+	}
+
+	if suppressed := filter.Suppressed(); suppressed != 1 {
+		t.Fatalf("expected 1 suppressed payload, got %d", suppressed)
+	}
+
+	clk.Add(150 * time.Millisecond)
+
+	if err := filter.FilterMessage(nil, createMessage("a", 11, "storm")); err != nil {
+		t.Fatalf("expected the suppression to be lifted, got %v", err)
+	}
+}
+
+func TestPipelineSparesSuppressed(t *testing.T) {
+	storm := NewStormFilter(1, time.Minute, nil)
+	pipeline := NewPipeline(NewScorer(nil), storm)
+
+	for i := 0; i < 2; i++ {
+		pipeline.FilterMessage(nil, createMessage("a", uint64(i), "storm"))
+	}
+
+	key := senderKey(createMessage("a", 0, ""))
+	score := pipeline.Scorer.Score(key)
+
+	// Payloads dropped while suppressed are not penalized anew.
+	for i := 2; i < 5; i++ {
+		if err := pipeline.FilterMessage(nil, createMessage("a", uint64(i), "storm")); err == nil {
+			t.Fatal("expected a suppressed payload to be dropped")
+		}
+	}
+
+	if after := pipeline.Scorer.Score(key); after != score {
+		t.Fatalf("expected suppressed payloads not to be penalized, score went from %f to %f", score, after)
+	}
+}
+
+func TestPipelineBans(t *testing.T) {
+	scorer := NewScorer(nil)
+	scorer.BanThreshold = -2
+
+	pipeline := NewPipeline(scorer, NewSizeLimit(128))
+
+	large := createMessage("a", 1, string(make([]byte, 256)))
+
+	for i := 0; i < 3; i++ {
+		if err := pipeline.FilterMessage(nil, large); err == nil {
+			t.Fatal("expected large message to be dropped")
+		}
+	}
+
+	if !scorer.Banned(senderKey(large)) {
+		t.Fatal("expected misbehaving peer to be banned")
+	}
+
+	if err := pipeline.FilterMessage(nil, createMessage("a", 2, "small")); errors.Cause(err) != network.ErrPeerBanned {
+		t.Fatalf("expected messages from banned peer to be dropped, got %v", err)
+	}
+
+	if err := pipeline.FilterMessage(nil, createMessage("b", 1, "small")); err != nil {
+		t.Fatalf("expected other peers to be unaffected, got %v", err)
+	}
+
+	if scorer.Score(senderKey(createMessage("b", 1, ""))) <= 0 {
+		t.Fatal("expected well-behaved peer to be rewarded")
+	}
+}
+
+func TestScorerDecays(t *testing.T) {
+	clk := clock.NewMock(time.Now())
+
+	scorer := NewScorer(clk)
+	scorer.HalfLife = 50 * time.Millisecond
+
+	key := senderKey(createMessage("a", 1, ""))
+
+	for i := 0; i < 4; i++ {
+		scorer.Penalize(key)
+	}
+
+	if score := scorer.Score(key); score > -3.5 {
+		t.Fatalf("expected score of about -4, got %f", score)
+	}
+
+	clk.Add(100 * time.Millisecond)
+
+	if score := scorer.Score(key); score < -1.5 || score >= 0 {
+		t.Fatalf("expected score to decay towards zero, got %f", score)
+	}
+}
+
+func TestScorerCapacity(t *testing.T) {
+	scorer := NewScorer(nil)
+	scorer.Capacity = 2
+
+	a, b, c := senderKey(createMessage("a", 1, "")), senderKey(createMessage("b", 1, "")), senderKey(createMessage("c", 1, ""))
+
+	scorer.Penalize(a)
+	scorer.Penalize(b)
+
+	// Seeing a makes b the peer least recently seen, and so the one forgotten.
+	scorer.Score(a)
+	scorer.Penalize(c)
+
+	if tracked := len(scorer.scores); tracked != 2 {
+		t.Fatalf("expected at most 2 peers to be scored, %d are", tracked)
+	}
+
+	if scorer.Score(a) >= 0 || scorer.Score(b) != 0 || scorer.Score(c) >= 0 {
+		t.Fatal("expected the peer least recently seen to be forgotten")
+	}
+}
+
+func TestTypeFilter(t *testing.T) {
+	filter := NewTypeFilter(new(protobuf.Ping))
+
+	if err := filter.PreFilterMessage(&network.Envelope{TypeURL: "type.googleapis.com/protobuf.Ping"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := filter.PreFilterMessage(&network.Envelope{TypeURL: "type.googleapis.com/protobuf.Pong"}); err == nil {
+		t.Fatal("expected message of a disallowed type to be dropped")
+	}
+}
+
+func TestScorerPreFilter(t *testing.T) {
+	scorer := NewScorer(nil)
+
+	msg := createMessage("a", 1, "hello")
+	envelope := &network.Envelope{Sender: msg.Sender, TypeURL: msg.Message.TypeUrl}
+
+	if err := scorer.PreFilterMessage(envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	// Senders claimed by envelopes yet to be verified are not tracked.
+	for i := 0; i < 100; i++ {
+		claimed := createMessage(string(rune('b'+i)), 1, "hello")
+		scorer.PreFilterMessage(&network.Envelope{Sender: claimed.Sender, TypeURL: claimed.Message.TypeUrl})
+	}
+
+	if tracked := len(scorer.scores); tracked != 0 {
+		t.Fatalf("expected unverified senders not to be scored, %d are", tracked)
+	}
+
+	for !scorer.Banned(senderKey(msg)) {
+		scorer.Penalize(senderKey(msg))
+	}
+
+	if err := scorer.PreFilterMessage(envelope); err == nil {
+		t.Fatal("expected message from banned peer to be dropped")
+	}
+}
@@ -0,0 +1,33 @@
+package protection
+
+import (
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/perlin-network/noise/types/ratelimit"
+	"github.com/pkg/errors"
+)
+
+// RateLimit drops messages from peers sending faster than a given rate.
+type RateLimit struct {
+	limiter *ratelimit.Limiter
+}
+
+// NewRateLimit creates a filter allowing each peer to send rate messages per second,
+// with bursts of up to burst messages, as measured by a clock. The real clock is used
+// should it be nil.
+func NewRateLimit(rate float64, burst float64, c clock.Clock) *RateLimit {
+	return &RateLimit{limiter: ratelimit.NewLimiter(rate, burst, c)}
+}
+
+// FilterMessage implements network.MessageFilter.
+func (l *RateLimit) FilterMessage(client *network.PeerClient, msg *protobuf.Message) error {
+	if protected(client, msg) {
+		return nil
+	}
+
+	if !l.limiter.Allow(senderKey(msg), 1) {
+		return errors.Errorf("peer %s exceeded its message rate limit", msg.Sender.Address)
+	}
+	return nil
+}
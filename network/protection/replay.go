@@ -0,0 +1,229 @@
+package protection
+
+import (
+	"container/list"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peerstore"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+const (
+	// replayBucket is the backend bucket the IDs of seen messages are persisted under.
+	replayBucket = "replay"
+
+	// forgetBatchSize is the number of expired message IDs forgotten from the backend
+	// at once.
+	forgetBatchSize = 64
+)
+
+// ErrNotRemembered is returned for messages dropped as they may not be remembered to
+// detect replays of them, which are not to be penalized as their senders are not at
+// fault.
+var ErrNotRemembered = errors.New("message may not be remembered to detect replays")
+
+type replayEntry struct {
+	id   network.MessageID
+	seen time.Time
+}
+
+// ReplayFilter drops messages whose IDs have already been seen within a window of
+// time. As message IDs are covered by their senders' signatures, a message re-sent
+// with a fresh nonce or over a fresh connection is still treated as a replay, as are
+// messages seen before a restart should the filter be persisted. Messages of peers
+// predating signed IDs are identified by their payloads instead.
+//
+// Message IDs are never forgotten within the window, and so messages are dropped
+// should capacity be reached until the IDs of others expire.
+type ReplayFilter struct {
+	mutex sync.Mutex
+
+	window   time.Duration
+	capacity int
+
+	seen  map[network.MessageID]*list.Element
+	order *list.List
+
+	clock clock.Clock
+
+	// backend persists the IDs of seen messages. Nil if they are only held in memory.
+	backend peerstore.Backend
+
+	// expired holds the IDs of expired messages yet to be forgotten by the backend.
+	expired []string
+}
+
+// NewReplayFilter creates a filter remembering at most capacity message IDs for a
+// window of time, as measured by a clock. The real clock is used should it be nil.
+func NewReplayFilter(window time.Duration, capacity int, c clock.Clock) *ReplayFilter {
+	return &ReplayFilter{
+		clock:    clock.Or(c),
+		window:   window,
+		capacity: capacity,
+		seen:     make(map[network.MessageID]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// FilterMessage implements network.MessageFilter.
+func (f *ReplayFilter) FilterMessage(client *network.PeerClient, msg *protobuf.Message) error {
+	id := network.NewMessageID(msg)
+	now := f.clock.Now()
+
+	f.mutex.Lock()
+
+	// Forget message IDs which have expired.
+	for e := f.order.Front(); e != nil && now.Sub(e.Value.(*replayEntry).seen) >= f.window; e = f.order.Front() {
+		f.forget(e)
+	}
+
+	backend, expired := f.backend, f.takeExpired(forgetBatchSize)
+
+	if _, seen := f.seen[id]; seen {
+		f.mutex.Unlock()
+		f.forgetPersisted(backend, expired)
+
+		return errors.Errorf("message %s from peer %s was replayed", id, msg.Sender.Address)
+	}
+
+	if f.order.Len() >= f.capacity {
+		f.mutex.Unlock()
+		f.forgetPersisted(backend, expired)
+
+		return errors.Wrapf(ErrNotRemembered, "too many messages were seen within the replay window to remember message %s from peer %s", id, msg.Sender.Address)
+	}
+
+	e := f.order.PushBack(&replayEntry{id: id, seen: now})
+	f.seen[id] = e
+
+	f.mutex.Unlock()
+
+	if backend == nil {
+		return nil
+	}
+
+	f.forgetPersisted(backend, expired)
+
+	// Remember the message before it is handled, such that it is handled at most once
+	// even should we restart while handling it. Should it not be remembered, it is
+	// dropped rather than risk it being handled twice.
+	if err := backend.Put(replayBucket, id.String(), encodeSeen(now)); err != nil {
+		f.mutex.Lock()
+		if f.seen[id] == e {
+			delete(f.seen, id)
+			f.order.Remove(e)
+		}
+		f.mutex.Unlock()
+
+		return errors.Wrapf(ErrNotRemembered, "failed to persist seen message %s [err=%s]", id, err)
+	}
+
+	return nil
+}
+
+// forget forgets a seen message, queueing it to be forgotten by the backend.
+func (f *ReplayFilter) forget(e *list.Element) {
+	entry := e.Value.(*replayEntry)
+
+	delete(f.seen, entry.id)
+	f.order.Remove(e)
+
+	if f.backend != nil {
+		f.expired = append(f.expired, entry.id.String())
+	}
+}
+
+// takeExpired takes the IDs of expired messages queued to be forgotten by the backend,
+// should at least a batch of them be queued.
+func (f *ReplayFilter) takeExpired(batch int) []string {
+	if len(f.expired) < batch {
+		return nil
+	}
+
+	expired := f.expired
+	f.expired = nil
+
+	return expired
+}
+
+// forgetPersisted forgets the IDs of expired messages from a backend. It is called
+// without holding the filter's lock, such that messages are not held back on I/O.
+func (f *ReplayFilter) forgetPersisted(backend peerstore.Backend, expired []string) {
+	for _, key := range expired {
+		if err := backend.Delete(replayBucket, key); err != nil {
+			glog.Warningf("Failed to forget seen message %s [err=%s]", key, err)
+		}
+	}
+}
+
+// Persist persists the IDs of seen messages to a backend, such that messages seen
+// before a restart are still dropped as replays after it, and loads those the backend
+// remembers from within the window. It is to be called before messages are filtered.
+func (f *ReplayFilter) Persist(backend peerstore.Backend) error {
+	now := f.clock.Now()
+
+	var loaded []*replayEntry
+	var expired []string
+
+	err := backend.Iterate(replayBucket, func(key string, value []byte) error {
+		raw, err := hex.DecodeString(key)
+		if err != nil || len(raw) != len(network.MessageID{}) || len(value) != 8 {
+			expired = append(expired, key)
+			return nil
+		}
+
+		seen := time.Unix(0, int64(binary.LittleEndian.Uint64(value)))
+		if now.Sub(seen) >= f.window {
+			expired = append(expired, key)
+			return nil
+		}
+
+		entry := &replayEntry{seen: seen}
+		copy(entry.id[:], raw)
+
+		loaded = append(loaded, entry)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to load seen messages")
+	}
+
+	for _, key := range expired {
+		if err := backend.Delete(replayBucket, key); err != nil {
+			return errors.Wrap(err, "failed to forget expired seen messages")
+		}
+	}
+
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].seen.Before(loaded[j].seen)
+	})
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.backend = backend
+
+	// All message IDs loaded are remembered, even should they overflow capacity, as
+	// they are all within the window.
+	for _, entry := range loaded {
+		if _, seen := f.seen[entry.id]; !seen {
+			f.seen[entry.id] = f.order.PushBack(entry)
+		}
+	}
+
+	return nil
+}
+
+func encodeSeen(seen time.Time) []byte {
+	encoded := make([]byte, 8)
+	binary.LittleEndian.PutUint64(encoded, uint64(seen.UnixNano()))
+	return encoded
+}
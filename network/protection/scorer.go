@@ -0,0 +1,199 @@
+package protection
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/perlin-network/noise/types/stats"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultPenalty      = 1.0
+	defaultReward       = 0.01
+	defaultMaxScore     = 10.0
+	defaultBanThreshold = -10.0
+	defaultBanDuration  = 1 * time.Minute
+)
+
+// DefaultScorerCapacity is the maximum number of peers scored by default.
+const DefaultScorerCapacity = 10000
+
+type score struct {
+	key         string
+	value       float64
+	updated     time.Time
+	bannedUntil time.Time
+
+	element *list.Element
+}
+
+// Scorer tracks the reputation of peers, and bans peers whose score falls below a
+// threshold for a period of time.
+type Scorer struct {
+	mutex  sync.Mutex
+	scores map[string]*score
+	order  *list.List
+	clock  clock.Clock
+
+	// Capacity is the maximum number of peers scored, beyond which the peers least
+	// recently seen are forgotten. DefaultScorerCapacity if zero.
+	Capacity int
+
+	// Amount a peers score is decreased by upon misbehaving.
+	PenaltyAmount float64
+
+	// Amount a peers score is increased by upon behaving, up to MaxScore.
+	RewardAmount float64
+	MaxScore     float64
+
+	// Score below which a peer is banned for BanDuration. Their score is reset
+	// once the ban is lifted.
+	BanThreshold float64
+	BanDuration  time.Duration
+
+	// HalfLife is how long it takes for a peers score to decay halfway towards zero,
+	// such that past behaviour is forgotten over time. Zero if scores do not decay.
+	HalfLife time.Duration
+}
+
+// NewScorer creates a new scorer with default parameters, whose bans and decay are
+// measured by a clock. The real clock is used should it be nil.
+func NewScorer(c clock.Clock) *Scorer {
+	return &Scorer{
+		scores: make(map[string]*score),
+		order:  list.New(),
+		clock:  clock.Or(c),
+
+		PenaltyAmount: defaultPenalty,
+		RewardAmount:  defaultReward,
+		MaxScore:      defaultMaxScore,
+		BanThreshold:  defaultBanThreshold,
+		BanDuration:   defaultBanDuration,
+	}
+}
+
+// get returns the score of a peer, lifting expired bans, and starts tracking the peer
+// should it not be scored yet. Must be called with the mutex held.
+func (s *Scorer) get(key string) *score {
+	entry := s.load(key)
+	if entry == nil {
+		// Forget the peers least recently seen, lest peers flooding us with fresh
+		// identities exhaust our memory.
+		for s.order.Len() >= s.capacity() {
+			delete(s.scores, s.order.Remove(s.order.Back()).(*score).key)
+		}
+
+		entry = &score{key: key, updated: s.clock.Now()}
+		entry.element = s.order.PushFront(entry)
+		s.scores[key] = entry
+	}
+
+	return entry
+}
+
+func (s *Scorer) capacity() int {
+	if s.Capacity <= 0 {
+		return DefaultScorerCapacity
+	}
+	return s.Capacity
+}
+
+// load returns the score of a peer, lifting expired bans. Nil should the peer not be
+// scored yet, in which case it is not tracked, such that peers merely looked up, such
+// as by senders claimed by envelopes which are yet to be verified, do not take up
+// memory. Must be called with the mutex held.
+func (s *Scorer) load(key string) *score {
+	entry, exists := s.scores[key]
+	if !exists {
+		return nil
+	}
+
+	s.order.MoveToFront(entry.element)
+
+	now := s.clock.Now()
+
+	if !entry.bannedUntil.IsZero() && now.After(entry.bannedUntil) {
+		entry.value = 0
+		entry.bannedUntil = time.Time{}
+	}
+
+	if !entry.updated.IsZero() {
+		entry.value = stats.Decay(entry.value, now.Sub(entry.updated), s.HalfLife)
+	}
+	entry.updated = now
+
+	return entry
+}
+
+// Score returns the score of a peer by its hex-encoded public key.
+func (s *Scorer) Score(key string) float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if entry := s.load(key); entry != nil {
+		return entry.value
+	}
+	return 0
+}
+
+// Banned returns true should a peer by its hex-encoded public key be banned.
+func (s *Scorer) Banned(key string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry := s.load(key)
+	return entry != nil && !entry.bannedUntil.IsZero()
+}
+
+// Penalize decreases the score of a peer by its hex-encoded public key, banning it
+// should its score fall below the ban threshold.
+func (s *Scorer) Penalize(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry := s.get(key)
+	entry.value -= s.PenaltyAmount
+
+	if entry.value < s.BanThreshold && entry.bannedUntil.IsZero() {
+		entry.bannedUntil = s.clock.Now().Add(s.BanDuration)
+	}
+}
+
+// Reward increases the score of a peer by its hex-encoded public key.
+func (s *Scorer) Reward(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry := s.get(key)
+
+	entry.value += s.RewardAmount
+	if entry.value > s.MaxScore {
+		entry.value = s.MaxScore
+	}
+}
+
+// FilterMessage implements network.MessageFilter by dropping messages from banned peers.
+// Protected peers are never banned.
+func (s *Scorer) FilterMessage(client *network.PeerClient, msg *protobuf.Message) error {
+	if !protected(client, msg) && s.Banned(senderKey(msg)) {
+		return errors.Wrapf(network.ErrPeerBanned, "peer %s", msg.Sender.Address)
+	}
+	return nil
+}
+
+// PreFilterMessage implements network.PreFilter by dropping messages from banned peers
+// before their signatures are verified. Protected peers are never banned.
+func (s *Scorer) PreFilterMessage(envelope *network.Envelope) error {
+	sender := peer.ID(*envelope.Sender)
+
+	if !(envelope.Network != nil && envelope.Network.IsProtected(sender)) && s.Banned(sender.PublicKeyHex()) {
+		return errors.Wrapf(network.ErrPeerBanned, "peer %s", envelope.Sender.Address)
+	}
+	return nil
+}
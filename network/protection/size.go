@@ -0,0 +1,34 @@
+package protection
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// SizeLimit drops messages larger than a maximum size in bytes.
+type SizeLimit struct {
+	max int
+}
+
+// NewSizeLimit creates a filter dropping messages larger than max bytes.
+func NewSizeLimit(max int) *SizeLimit {
+	return &SizeLimit{max: max}
+}
+
+// FilterMessage implements network.MessageFilter.
+func (l *SizeLimit) FilterMessage(client *network.PeerClient, msg *protobuf.Message) error {
+	if size := proto.Size(msg); size > l.max {
+		return errors.Errorf("message of %d bytes exceeds the limit of %d bytes", size, l.max)
+	}
+	return nil
+}
+
+// PreFilterMessage implements network.PreFilter.
+func (l *SizeLimit) PreFilterMessage(envelope *network.Envelope) error {
+	if envelope.Size > l.max {
+		return errors.Errorf("message of %d bytes exceeds the limit of %d bytes", envelope.Size, l.max)
+	}
+	return nil
+}
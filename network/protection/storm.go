@@ -0,0 +1,192 @@
+package protection
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultStormThreshold is the number of times the same payload may be received
+	// from the same peer within DefaultStormWindow before it is deemed to be storming.
+	DefaultStormThreshold = 50
+
+	// DefaultStormWindow is the window over which payloads are counted by default.
+	DefaultStormWindow = 1 * time.Second
+
+	// DefaultStormSuppression is how long storming payloads are dropped for by default.
+	DefaultStormSuppression = 1 * time.Minute
+)
+
+// ErrSuppressed is returned for payloads dropped while suppressed, which are not to be
+// penalized, unlike the payload which set off the suppression.
+var ErrSuppressed = errors.New("payload is suppressed as a broadcast storm")
+
+// StormAlert reports a payload which a peer amplified abnormally, such as by
+// misbehaving gossip logic, and which is hence being suppressed from the peer.
+type StormAlert struct {
+	// Hash is the hex-encoded SHA-256 hash of the payload, and TypeURL its type.
+	Hash    string
+	TypeURL string
+
+	// Sender is the hex-encoded public key of the peer the payload was received from.
+	Sender string
+
+	// Count is the number of times the payload was received from the peer within the
+	// window.
+	Count  int
+	Window time.Duration
+
+	// SuppressedUntil is when the payload stops being dropped.
+	SuppressedUntil time.Time
+}
+
+// stormKey identifies a payload received from a peer.
+type stormKey struct {
+	hash   [sha256.Size]byte
+	sender string
+}
+
+type stormCount struct {
+	start           time.Time
+	count           int
+	suppressedUntil time.Time
+}
+
+// StormFilter detects broadcast storms, being the same payload received from a peer
+// more than a threshold of times within a window, and temporarily drops the payload
+// from the peer. Payloads are counted per peer, such that peers may neither have
+// payloads suppressed from others by re-sending them, nor are honest peers flooding
+// a payload to many neighbours suppressed. Run within a Pipeline, the peer setting
+// off a storm is penalized, though not for the payloads dropped while suppressed.
+type StormFilter struct {
+	mutex  sync.Mutex
+	counts map[stormKey]*stormCount
+	swept  time.Time
+	clock  clock.Clock
+
+	// Threshold is the number of times a payload may be received within Window before
+	// it is suppressed for Suppression.
+	Threshold   int
+	Window      time.Duration
+	Suppression time.Duration
+
+	// OnStorm is called upon a storm being detected, such as to alert operators. Nil
+	// if storms are only logged.
+	OnStorm func(alert StormAlert)
+}
+
+// NewStormFilter creates a filter suppressing payloads received from a peer more than
+// threshold times within a window, with the default suppression, as measured by a
+// clock. The real clock is used should it be nil.
+func NewStormFilter(threshold int, window time.Duration, c clock.Clock) *StormFilter {
+	return &StormFilter{
+		counts:      make(map[stormKey]*stormCount),
+		clock:       clock.Or(c),
+		Threshold:   threshold,
+		Window:      window,
+		Suppression: DefaultStormSuppression,
+	}
+}
+
+// FilterMessage implements network.MessageFilter.
+func (f *StormFilter) FilterMessage(client *network.PeerClient, msg *protobuf.Message) error {
+	if msg.Message == nil {
+		return nil
+	}
+
+	key := stormKey{
+		hash:   sha256.Sum256(append([]byte(msg.Message.TypeUrl), msg.Message.Value...)),
+		sender: senderKey(msg),
+	}
+	now := f.clock.Now()
+
+	f.mutex.Lock()
+
+	f.sweep(now)
+
+	entry, exists := f.counts[key]
+	if !exists {
+		entry = &stormCount{start: now}
+		f.counts[key] = entry
+	}
+
+	if now.Before(entry.suppressedUntil) {
+		f.mutex.Unlock()
+		return errors.Wrapf(ErrSuppressed, "payload %x from peer %s", key.hash[:8], msg.Sender.Address)
+	}
+
+	if now.Sub(entry.start) >= f.Window {
+		entry.start, entry.count = now, 0
+	}
+
+	entry.count++
+
+	if entry.count <= f.Threshold {
+		f.mutex.Unlock()
+		return nil
+	}
+
+	entry.suppressedUntil = now.Add(f.Suppression)
+
+	alert := StormAlert{
+		Hash:            hex.EncodeToString(key.hash[:]),
+		TypeURL:         msg.Message.TypeUrl,
+		Sender:          key.sender,
+		Count:           entry.count,
+		Window:          f.Window,
+		SuppressedUntil: entry.suppressedUntil,
+	}
+
+	// Count afresh once the suppression is lifted.
+	entry.start, entry.count = entry.suppressedUntil, 0
+
+	f.mutex.Unlock()
+
+	glog.Warningf("Suppressing %s payload %s from %s until %s, having received it %d times within %s.", alert.TypeURL, alert.Hash, msg.Sender.Address, alert.SuppressedUntil, alert.Count, alert.Window)
+
+	if f.OnStorm != nil {
+		f.OnStorm(alert)
+	}
+
+	return errors.Errorf("payload %x from peer %s set off a broadcast storm", key.hash[:8], msg.Sender.Address)
+}
+
+// Suppressed returns the number of payloads being suppressed.
+func (f *StormFilter) Suppressed() (count int) {
+	now := f.clock.Now()
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, entry := range f.counts {
+		if now.Before(entry.suppressedUntil) {
+			count++
+		}
+	}
+
+	return
+}
+
+// sweep forgets payloads neither received within the window nor suppressed, at most
+// once per window. Must be called with the mutex held.
+func (f *StormFilter) sweep(now time.Time) {
+	if now.Sub(f.swept) < f.Window {
+		return
+	}
+
+	f.swept = now
+
+	for key, entry := range f.counts {
+		if now.Sub(entry.start) >= f.Window {
+			delete(f.counts, key)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package protection
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/perlin-network/noise/network"
+	"github.com/pkg/errors"
+)
+
+// TypeFilter drops messages whose contents are not of an allowed type before they
+// are unmarshalled.
+type TypeFilter struct {
+	allowed map[string]struct{}
+}
+
+// NewTypeFilter creates a pre-filter only allowing messages of the types of messages.
+func NewTypeFilter(messages ...proto.Message) *TypeFilter {
+	filter := &TypeFilter{allowed: make(map[string]struct{})}
+
+	for _, message := range messages {
+		filter.Allow(message)
+	}
+
+	return filter
+}
+
+// Allow allows messages of the type of message through the filter.
+func (f *TypeFilter) Allow(message proto.Message) {
+	f.allowed[proto.MessageName(message)] = struct{}{}
+}
+
+// PreFilterMessage implements network.PreFilter.
+func (f *TypeFilter) PreFilterMessage(envelope *network.Envelope) error {
+	name, err := ptypes.AnyMessageName(&any.Any{TypeUrl: envelope.TypeURL})
+	if err != nil {
+		return err
+	}
+
+	if _, allowed := f.allowed[name]; !allowed {
+		return errors.Errorf("message of type %s is not allowed", name)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Package mqtt bridges pubsub topics to an MQTT broker in both directions, so that
+// devices speaking MQTT may participate in a noise network.
+package mqtt
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network/pubsub"
+	"github.com/pkg/errors"
+)
+
+// echoCapacity is the number of payloads remembered per topic to recognize our own
+// publications being echoed back by the broker.
+const echoCapacity = 256
+
+// Client publishes and subscribes to topics on an MQTT broker. It is satisfied by thin
+// wrappers around any MQTT client library.
+type Client interface {
+	Publish(topic string, payload []byte) error
+	Subscribe(topic string, handler func(topic string, payload []byte)) error
+	Unsubscribe(topic string) error
+}
+
+// Bridge relays data between pubsub topics and MQTT topics.
+type Bridge struct {
+	pubsub *pubsub.Plugin
+	client Client
+
+	mutex   sync.Mutex
+	mapped  map[string]func()
+	stopped bool
+
+	echoMutex sync.Mutex
+	echoes    map[string][][sha256.Size]byte
+}
+
+// New creates a bridge relaying data between a pubsub plugin and an MQTT client.
+func New(ps *pubsub.Plugin, client Client) *Bridge {
+	return &Bridge{
+		pubsub: ps,
+		client: client,
+		mapped: make(map[string]func()),
+		echoes: make(map[string][][sha256.Size]byte),
+	}
+}
+
+// Map relays data published to a pubsub topic to an MQTT topic, and data published
+// to the MQTT topic to the pubsub topic.
+func (b *Bridge) Map(topic string, mqttTopic string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.stopped {
+		return errors.New("bridge has been stopped")
+	}
+
+	if _, exists := b.mapped[topic]; exists {
+		return errors.Errorf("topic %s is already mapped", topic)
+	}
+
+	err := b.client.Subscribe(mqttTopic, func(_ string, payload []byte) {
+		if b.isEcho(mqttTopic, payload) {
+			return
+		}
+
+		if err := b.pubsub.Publish(topic, payload); err != nil {
+			glog.Warningf("Failed to relay MQTT topic %s to topic %s [err=%s]", mqttTopic, topic, err)
+		}
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to subscribe to MQTT topic %s", mqttTopic)
+	}
+
+	messages, unsubscribe := b.pubsub.Subscribe(topic)
+
+	go func() {
+		for msg := range messages {
+			b.remember(mqttTopic, msg.Data)
+
+			if err := b.client.Publish(mqttTopic, msg.Data); err != nil {
+				glog.Warningf("Failed to relay topic %s to MQTT topic %s [err=%s]", topic, mqttTopic, err)
+			}
+		}
+	}()
+
+	b.mapped[topic] = func() {
+		unsubscribe()
+
+		if err := b.client.Unsubscribe(mqttTopic); err != nil {
+			glog.Warning(err)
+		}
+	}
+
+	return nil
+}
+
+// Stop stops relaying all mapped topics.
+func (b *Bridge) Stop() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for topic, unmap := range b.mapped {
+		unmap()
+		delete(b.mapped, topic)
+	}
+
+	b.stopped = true
+}
+
+// remember records a payload relayed to an MQTT topic, so that it is not relayed
+// back into the network should the broker echo it back to us.
+func (b *Bridge) remember(mqttTopic string, payload []byte) {
+	b.echoMutex.Lock()
+	defer b.echoMutex.Unlock()
+
+	echoes := append(b.echoes[mqttTopic], sha256.Sum256(payload))
+	if len(echoes) > echoCapacity {
+		echoes = echoes[1:]
+	}
+
+	b.echoes[mqttTopic] = echoes
+}
+
+// isEcho returns true and forgets a payload should it have been relayed to an MQTT
+// topic by us.
+func (b *Bridge) isEcho(mqttTopic string, payload []byte) bool {
+	b.echoMutex.Lock()
+	defer b.echoMutex.Unlock()
+
+	sum := sha256.Sum256(payload)
+
+	echoes := b.echoes[mqttTopic]
+	for i, echo := range echoes {
+		if echo == sum {
+			b.echoes[mqttTopic] = append(echoes[:i], echoes[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
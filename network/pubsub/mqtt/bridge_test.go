@@ -0,0 +1,103 @@
+package mqtt
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/networktest"
+	"github.com/perlin-network/noise/network/pubsub"
+)
+
+// broker is an in-memory MQTT broker which echoes publications back to subscribers,
+// including the publisher.
+type broker struct {
+	mutex     sync.Mutex
+	handlers  map[string]func(topic string, payload []byte)
+	published chan []byte
+}
+
+func (b *broker) Publish(topic string, payload []byte) error {
+	b.published <- payload
+
+	b.mutex.Lock()
+	handler := b.handlers[topic]
+	b.mutex.Unlock()
+
+	if handler != nil {
+		handler(topic, payload)
+	}
+
+	return nil
+}
+
+func (b *broker) Subscribe(topic string, handler func(topic string, payload []byte)) error {
+	b.mutex.Lock()
+	b.handlers[topic] = handler
+	b.mutex.Unlock()
+	return nil
+}
+
+func (b *broker) Unsubscribe(topic string) error {
+	b.mutex.Lock()
+	delete(b.handlers, topic)
+	b.mutex.Unlock()
+	return nil
+}
+
+func buildNode(t *testing.T) (*network.Network, *pubsub.Plugin) {
+	plugin := pubsub.New()
+	return networktest.NewNode(t, new(discovery.Plugin), plugin), plugin
+}
+
+func TestBridge(t *testing.T) {
+	alice, alicePubSub := buildNode(t)
+	bob, bobPubSub := buildNode(t)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	alice.Bootstrap(bob.Address)
+
+	mqtt := &broker{handlers: make(map[string]func(string, []byte)), published: make(chan []byte, 16)}
+
+	bridge := New(bobPubSub, mqtt)
+	defer bridge.Stop()
+
+	if err := bridge.Map("sensors", "devices/sensors"); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, unsubscribe := alicePubSub.Subscribe("sensors")
+	defer unsubscribe()
+
+	// Publications within the network are relayed to the broker, and not relayed back
+	// into the network upon being echoed by the broker.
+	if err := alicePubSub.Publish("sensors", []byte("from network")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case payload := <-mqtt.published:
+		if string(payload) != "from network" {
+			t.Fatalf("unexpected payload %q", payload)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for publication to be relayed to the broker")
+	}
+
+	// Publications to the broker are relayed into the network.
+	mqtt.Publish("devices/sensors", []byte("from device"))
+	<-mqtt.published
+
+	select {
+	case msg := <-messages:
+		if string(msg.Data) != "from device" {
+			t.Fatalf("expected only the device publication to be relayed, got %q", msg.Data)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for publication to be relayed into the network")
+	}
+}
@@ -0,0 +1,424 @@
+// Package pubsub gossips data published to topics to all peers within a network.
+package pubsub
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/lru"
+	"github.com/pkg/errors"
+)
+
+const (
+	// seenCapacity is the number of publications remembered to avoid re-flooding them.
+	seenCapacity = 65536
+
+	// subscriptionBufferSize is the number of messages a subscription buffers before
+	// dropping messages.
+	subscriptionBufferSize = 1024
+
+	// defaultBatchSize is the number of publications signed as a batch at most.
+	defaultBatchSize = 64
+)
+
+// AdaptiveFanout is the Fanout relaying publications to as many random peers as the
+// estimated size of the network calls for, rather than flooding them to all peers.
+// Publications are not repaired should they fail to reach all subscribers, and so it
+// is only suited to well-connected networks whose size is estimated reliably.
+const AdaptiveFanout = -1
+
+// Message is data published to a topic by a peer.
+type Message struct {
+	Topic string
+	Data  []byte
+
+	// Origin is the hex-encoded public key of the peer which published and signed the
+	// data.
+	Origin string
+}
+
+// pending is a publication held back to be signed as a batch, along with the addresses
+// of the peers it is to be sent to.
+type pending struct {
+	publication *protobuf.Publication
+	addresses   []string
+}
+
+type subscription struct {
+	ch chan *Message
+}
+
+// Plugin publishes and subscribes to topics, relaying publications across the network.
+type Plugin struct {
+	*network.Plugin
+
+	// Penalize is called with the hex-encoded public key of the peer which relayed
+	// each publication rejected by a validator or not signed by its origin, such as to
+	// lower its score with a protection.Scorer. Peers are only penalized upon a
+	// publication first being rejected, and not for relaying publications whose
+	// verdict is cached. Nil if peers are not penalized.
+	Penalize func(peer string)
+
+	// Fanout is the number of random peers publications are relayed to. Zero to flood
+	// all peers, and AdaptiveFanout to adapt it to the estimated size of the network.
+	Fanout int
+
+	// BatchWindow is how long publications sent are held back for to be signed as a
+	// batch, trading latency for signer CPU on high-rate topics. Zero if publications
+	// are signed one by one.
+	BatchWindow time.Duration
+
+	// BatchSize is the number of publications signed as a batch at most, beyond which
+	// the batch is sent early. Zero if the default size.
+	BatchSize int
+
+	net *network.Network
+
+	batchMutex sync.Mutex
+	batch      []pending
+
+	// seqno is the sequence number of the last publication published. It starts off
+	// at random, such that publications published after a restart are not mistaken
+	// for those seen before it.
+	seqno uint64
+	seen  *lru.Cache
+
+	mutex         sync.RWMutex
+	subscriptions map[string]map[*subscription]struct{}
+
+	validators map[string]*topicValidator
+	verdicts   *lru.Cache
+}
+
+var (
+	// PluginID to reference pubsub plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// New creates a pubsub plugin.
+func New() *Plugin {
+	return &Plugin{
+		seqno:         randomSeqno(),
+		seen:          lru.NewCache(seenCapacity),
+		subscriptions: make(map[string]map[*subscription]struct{}),
+		verdicts:      newVerdictCache(),
+	}
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.net = net
+
+	if err := net.RegisterMessages(new(protobuf.Publication)); err != nil {
+		glog.Warningf("pubsub: failed to register message types [err=%s]", err)
+	}
+}
+
+// Publish floods data published to a topic to all peers, or to as many as the fan-out
+// calls for. Local subscribers are not delivered the data.
+func (p *Plugin) Publish(topic string, data []byte) error {
+	if p.net == nil {
+		return errors.New("pubsub has not been started by a network")
+	}
+
+	publication := &protobuf.Publication{
+		Topic:  topic,
+		Data:   data,
+		Origin: p.net.ID.PublicKey,
+		Seqno:  atomic.AddUint64(&p.seqno, 1),
+	}
+
+	if err := sign(p.net, publication); err != nil {
+		return err
+	}
+
+	p.markSeen(publication)
+	p.relay(p.net, "", publication)
+
+	return nil
+}
+
+// Subscribe returns a channel of data published to a topic by peers, and a function
+// to unsubscribe.
+func (p *Plugin) Subscribe(topic string) (<-chan *Message, func()) {
+	sub := &subscription{ch: make(chan *Message, subscriptionBufferSize)}
+
+	p.mutex.Lock()
+	if p.subscriptions[topic] == nil {
+		p.subscriptions[topic] = make(map[*subscription]struct{})
+	}
+	p.subscriptions[topic][sub] = struct{}{}
+	p.mutex.Unlock()
+
+	var once sync.Once
+
+	return sub.ch, func() {
+		once.Do(func() {
+			p.mutex.Lock()
+			delete(p.subscriptions[topic], sub)
+			if len(p.subscriptions[topic]) == 0 {
+				delete(p.subscriptions, topic)
+			}
+			p.mutex.Unlock()
+
+			close(sub.ch)
+		})
+	}
+}
+
+// Receive implements the plugin callback
+func (p *Plugin) Receive(ctx *network.PluginContext) error {
+	publication, ok := ctx.Message().(*protobuf.Publication)
+	if !ok {
+		return nil
+	}
+
+	// Drop publications which have already been seen.
+	if p.seen.Contains(seenKey(publication)) {
+		return nil
+	}
+
+	net, from := ctx.Network(), ctx.Client().Address()
+
+	relayer := ctx.Client().ID()
+	if relayer == nil {
+		return nil
+	}
+
+	// Publications are only seen by the sequence numbers their origins sign, such that
+	// peers may not forge publications to have those of others dropped as seen.
+	if !net.Verify(publication.Origin, signedPublication(publication), publication.Signature) {
+		p.reject(publication, *relayer)
+		return nil
+	}
+
+	v := p.validator(publication.Topic)
+	if v == nil {
+		p.accept(net, from, publication)
+		return nil
+	}
+
+	// Validate publications in the background, dropping those beyond the topic's
+	// validation concurrency.
+	select {
+	case v.slots <- struct{}{}:
+	default:
+		glog.Warningf("Dropped publication to topic %s as too many are being validated.", publication.Topic)
+		return nil
+	}
+
+	go func() {
+		defer func() { <-v.slots }()
+
+		switch verdict, cached := p.validate(v, publication); {
+		case verdict == Accept:
+			p.accept(net, from, publication)
+		case verdict == Reject && !cached:
+			p.reject(publication, *relayer)
+		}
+	}()
+
+	return nil
+}
+
+// accept delivers a publication received from a peer by its address to subscribers,
+// and relays it onto other peers, unless it was accepted already. Publications are
+// only marked as seen once accepted, such that those dropped while being validated
+// may be received anew.
+func (p *Plugin) accept(net *network.Network, from string, publication *protobuf.Publication) {
+	if !p.markSeen(publication) {
+		return
+	}
+
+	p.deliver(publication)
+	p.relay(net, from, publication)
+}
+
+// relay sends a publication to as many random peers other than a peer by its address
+// as the fan-out calls for, selected w.r.t. the network's ZonePreference.
+func (p *Plugin) relay(net *network.Network, from string, publication *protobuf.Publication) {
+	var candidates []*network.PeerClient
+
+	net.Peers.Range(func(key, value interface{}) bool {
+		if address := key.(string); address != from {
+			candidates = append(candidates, value.(*network.PeerClient))
+		}
+		return true
+	})
+
+	fanout := p.Fanout
+	if fanout == AdaptiveFanout {
+		fanout = net.Fanout()
+	}
+
+	if fanout > 0 && len(candidates) > fanout {
+		candidates = net.SelectByZone(candidates, fanout)
+	}
+
+	var addresses []string
+	for _, client := range candidates {
+		addresses = append(addresses, client.Address())
+	}
+
+	if len(addresses) > 0 {
+		p.send(net, publication, addresses)
+	}
+}
+
+// send sends a publication to peers by their addresses, or holds it back to be signed
+// as a batch should batching be enabled.
+func (p *Plugin) send(net *network.Network, publication *protobuf.Publication, addresses []string) {
+	if p.BatchWindow <= 0 {
+		net.BroadcastByAddresses(publication, addresses...)
+		return
+	}
+
+	size := p.BatchSize
+	if size <= 0 {
+		size = defaultBatchSize
+	}
+
+	p.batchMutex.Lock()
+
+	p.batch = append(p.batch, pending{publication: publication, addresses: addresses})
+
+	if len(p.batch) == 1 {
+		net.Scheduler().After(p.BatchWindow, func() {
+			go p.flush(net)
+		})
+	}
+
+	full := len(p.batch) >= size
+
+	p.batchMutex.Unlock()
+
+	if full {
+		p.flush(net)
+	}
+}
+
+// flush signs all publications held back as a batch, and sends them.
+func (p *Plugin) flush(net *network.Network) {
+	p.batchMutex.Lock()
+	batch := p.batch
+	p.batch = nil
+	p.batchMutex.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	messages := make([]proto.Message, len(batch))
+	for i, pending := range batch {
+		messages[i] = pending.publication
+	}
+
+	signed, err := net.PrepareBatch(messages...)
+	if err != nil {
+		glog.Warningf("Failed to sign batch of %d publications [err=%s]", len(batch), err)
+		return
+	}
+
+	for i, pending := range batch {
+		for _, address := range pending.addresses {
+			if err := net.Write(address, signed[i]); err != nil {
+				glog.Warningf("Failed to send publication to %s [err=%s]", address, err)
+			}
+		}
+	}
+}
+
+// markSeen marks a publication as seen, returning false should it already have been.
+func (p *Plugin) markSeen(publication *protobuf.Publication) bool {
+	fresh := false
+
+	p.seen.Get(seenKey(publication), func() (interface{}, error) {
+		fresh = true
+		return struct{}{}, nil
+	})
+
+	return fresh
+}
+
+// randomSeqno returns a random sequence number to start publishing from.
+func randomSeqno() uint64 {
+	buf := make([]byte, 8)
+	if _, err := crand.Read(buf); err != nil {
+		return uint64(rand.Int63())
+	}
+
+	return binary.LittleEndian.Uint64(buf)
+}
+
+// sign signs a publication on behalf of its origin, being a network.
+func sign(net *network.Network, publication *protobuf.Publication) error {
+	signature, err := net.Sign(signedPublication(publication))
+	if err != nil {
+		return errors.Wrap(err, "failed to sign publication")
+	}
+
+	publication.Signature = signature
+
+	return nil
+}
+
+// signedPublication returns what the origin of a publication signs of it, being its
+// topic, data and sequence number tagged with the publication domain.
+func signedPublication(publication *protobuf.Publication) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, uint64(len(publication.Topic)))
+
+	out := append([]byte{}, buf[:n]...)
+	out = append(out, publication.Topic...)
+
+	n = binary.PutUvarint(buf, uint64(len(publication.Data)))
+	out = append(out, buf[:n]...)
+	out = append(out, publication.Data...)
+
+	seqno := make([]byte, 8)
+	binary.LittleEndian.PutUint64(seqno, publication.Seqno)
+
+	return crypto.DomainSeparate(network.DomainPublication, append(out, seqno...))
+}
+
+// seenKey identifies a publication to tell whether it was seen. Its origin and sequence
+// number are signed by the origin, and so may not be forged.
+func seenKey(publication *protobuf.Publication) string {
+	return hex.EncodeToString(publication.Origin) + ":" + strconv.FormatUint(publication.Seqno, 10)
+}
+
+// newMessage describes a publication to subscribers and validators.
+func newMessage(publication *protobuf.Publication) *Message {
+	return &Message{
+		Topic:  publication.Topic,
+		Data:   publication.Data,
+		Origin: hex.EncodeToString(publication.Origin),
+	}
+}
+
+// deliver delivers a publication to all subscribers of its topic.
+func (p *Plugin) deliver(publication *protobuf.Publication) {
+	msg := newMessage(publication)
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for sub := range p.subscriptions[publication.Topic] {
+		select {
+		case sub.ch <- msg:
+		default:
+			glog.Warningf("Dropped publication to topic %s as a subscriber is too slow.", publication.Topic)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package pubsub
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/networktest"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func buildNode(t *testing.T) (*network.Network, *Plugin) {
+	plugin := New()
+	return networktest.NewNode(t, new(discovery.Plugin), plugin), plugin
+}
+
+func TestFlood(t *testing.T) {
+	alice, alicePubSub := buildNode(t)
+	bob, _ := buildNode(t)
+	carol, carolPubSub := buildNode(t)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	// Alice and carol are only connected through bob.
+	alice.Bootstrap(bob.Address)
+	carol.Bootstrap(bob.Address)
+
+	messages, unsubscribe := carolPubSub.Subscribe("news")
+	defer unsubscribe()
+
+	if err := alicePubSub.Publish("news", []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-messages:
+		if string(msg.Data) != "hello" || msg.Origin != alice.ID.PublicKeyHex() {
+			t.Fatalf("unexpected message %+v", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for publication to be flooded")
+	}
+
+	select {
+	case msg := <-messages:
+		t.Fatalf("expected publication to be delivered once, got %+v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBatchedFlood(t *testing.T) {
+	alice, alicePubSub := buildNode(t)
+	bob, _ := buildNode(t)
+	carol, carolPubSub := buildNode(t)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	alicePubSub.BatchWindow = 20 * time.Millisecond
+
+	alice.Bootstrap(bob.Address)
+	carol.Bootstrap(bob.Address)
+
+	messages, unsubscribe := carolPubSub.Subscribe("news")
+	defer unsubscribe()
+
+	expected := map[string]bool{"one": true, "two": true, "three": true}
+	for data := range expected {
+		if err := alicePubSub.Publish("news", []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for len(expected) > 0 {
+		select {
+		case msg := <-messages:
+			if !expected[string(msg.Data)] {
+				t.Fatalf("unexpected message %+v", msg)
+			}
+			delete(expected, string(msg.Data))
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for batched publications %v to be flooded", expected)
+		}
+	}
+}
+
+func TestForgedSeqno(t *testing.T) {
+	alice, alicePubSub := buildNode(t)
+	bob, bobPubSub := buildNode(t)
+	carol, _ := buildNode(t)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	alice.Bootstrap(bob.Address)
+	carol.Bootstrap(bob.Address)
+
+	waitForPeers(t, bob, 2)
+
+	messages, unsubscribe := bobPubSub.Subscribe("news")
+	defer unsubscribe()
+
+	// Carol claims the sequence number alice publishes under next, which would have
+	// alice's publication dropped as seen were it not signed.
+	carol.BroadcastByAddresses(&protobuf.Publication{
+		Topic:  "news",
+		Data:   []byte("forged"),
+		Origin: alice.ID.PublicKey,
+		Seqno:  atomic.LoadUint64(&alicePubSub.seqno) + 1,
+	}, bob.Address)
+
+	time.Sleep(100 * time.Millisecond)
+
+	if err := alicePubSub.Publish("news", []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-messages:
+		if string(msg.Data) != "hello" {
+			t.Fatalf("expected alice's publication to be delivered, got %+v", msg)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for alice's publication")
+	}
+}
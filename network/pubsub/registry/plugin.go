@@ -0,0 +1,251 @@
+// Package registry replicates grow-only sets and counters across the network over a
+// pubsub topic. Updates are flooded as they happen, and each node periodically floods
+// its full state as anti-entropy so that nodes which missed updates, or joined late,
+// converge.
+package registry
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/pubsub"
+	"github.com/perlin-network/noise/schedule"
+	"github.com/pkg/errors"
+)
+
+// DefaultAntiEntropyInterval is how often full state is flooded by default.
+const DefaultAntiEntropyInterval = 10 * time.Second
+
+// State is the replicated state of a registry. Merging states is commutative,
+// associative and idempotent.
+type State struct {
+	// Sets are grow-only sets of elements by their names.
+	Sets map[string][]string `json:"sets,omitempty"`
+
+	// Counters are grow-only counters by their names, made up of the count of each
+	// node by its hex-encoded public key.
+	Counters map[string]map[string]uint64 `json:"counters,omitempty"`
+}
+
+// Plugin is a registry of grow-only sets and counters. The pubsub plugin must be
+// registered for updates to be replicated.
+type Plugin struct {
+	*network.Plugin
+
+	// Topic updates are published to.
+	Topic string
+
+	// AntiEntropyInterval is how often full state is flooded. Zero if the default.
+	AntiEntropyInterval time.Duration
+
+	net    *network.Network
+	pubSub *pubsub.Plugin
+
+	mutex    sync.RWMutex
+	sets     map[string]map[string]struct{}
+	counters map[string]map[string]uint64
+
+	unsubscribe func()
+	antiEntropy *schedule.Task
+}
+
+var (
+	// PluginID to reference registry plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// New creates a registry replicated over a pubsub topic.
+func New(topic string) *Plugin {
+	return &Plugin{
+		Topic:    topic,
+		sets:     make(map[string]map[string]struct{}),
+		counters: make(map[string]map[string]uint64),
+	}
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.net = net
+
+	plugin, registered := net.Plugin(pubsub.PluginID)
+	if !registered {
+		glog.Warning("registry: pubsub plugin is not registered; updates will not be replicated")
+		return
+	}
+
+	p.pubSub = plugin.(*pubsub.Plugin)
+
+	messages, unsubscribe := p.pubSub.Subscribe(p.Topic)
+	p.unsubscribe = unsubscribe
+
+	go p.receive(messages)
+
+	interval := p.AntiEntropyInterval
+	if interval <= 0 {
+		interval = DefaultAntiEntropyInterval
+	}
+
+	p.antiEntropy = net.Scheduler().Repeat(schedule.Every(interval), func() {
+		if err := p.publish(p.State()); err != nil {
+			glog.Warningf("registry: failed to publish state [err=%s]", err)
+		}
+	})
+}
+
+// Cleanup implements the plugin callback
+func (p *Plugin) Cleanup(net *network.Network) {
+	if p.antiEntropy != nil {
+		p.antiEntropy.Cancel()
+	}
+
+	if p.unsubscribe != nil {
+		p.unsubscribe()
+	}
+}
+
+func (p *Plugin) receive(messages <-chan *pubsub.Message) {
+	for msg := range messages {
+		var state State
+
+		if err := json.Unmarshal(msg.Data, &state); err != nil {
+			glog.Warningf("registry: dropped malformed update from %s [err=%s]", msg.Origin, err)
+			continue
+		}
+
+		p.Merge(&state)
+	}
+}
+
+func (p *Plugin) publish(state *State) error {
+	if p.pubSub == nil {
+		return errors.New("registry has not been started with pubsub")
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return p.pubSub.Publish(p.Topic, data)
+}
+
+// Add adds an element to a set, and floods the update.
+func (p *Plugin) Add(set string, element string) error {
+	update := &State{Sets: map[string][]string{set: {element}}}
+
+	p.Merge(update)
+
+	return p.publish(update)
+}
+
+// Increment increments a counter by delta on behalf of this node, and floods the update.
+func (p *Plugin) Increment(counter string, delta uint64) error {
+	if p.net == nil {
+		return errors.New("registry has not been started by a network")
+	}
+
+	self := p.net.ID.PublicKeyHex()
+
+	p.mutex.Lock()
+	if p.counters[counter] == nil {
+		p.counters[counter] = make(map[string]uint64)
+	}
+	p.counters[counter][self] += delta
+	count := p.counters[counter][self]
+	p.mutex.Unlock()
+
+	return p.publish(&State{Counters: map[string]map[string]uint64{counter: {self: count}}})
+}
+
+// Elements returns the sorted elements of a set.
+func (p *Plugin) Elements(set string) []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	elements := make([]string, 0, len(p.sets[set]))
+	for element := range p.sets[set] {
+		elements = append(elements, element)
+	}
+
+	sort.Strings(elements)
+
+	return elements
+}
+
+// Value returns the value of a counter summed across all nodes.
+func (p *Plugin) Value(counter string) (value uint64) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, count := range p.counters[counter] {
+		value += count
+	}
+
+	return
+}
+
+// State returns a copy of the full state of the registry.
+func (p *Plugin) State() *State {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	state := &State{
+		Sets:     make(map[string][]string, len(p.sets)),
+		Counters: make(map[string]map[string]uint64, len(p.counters)),
+	}
+
+	for name, set := range p.sets {
+		for element := range set {
+			state.Sets[name] = append(state.Sets[name], element)
+		}
+	}
+
+	for name, counts := range p.counters {
+		state.Counters[name] = make(map[string]uint64, len(counts))
+
+		for node, count := range counts {
+			state.Counters[name][node] = count
+		}
+	}
+
+	return state
+}
+
+// Merge merges a state into the registry by taking the union of sets, and the maximum
+// count of each node within counters. It returns true should the registry have changed.
+func (p *Plugin) Merge(state *State) (changed bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for name, elements := range state.Sets {
+		if p.sets[name] == nil {
+			p.sets[name] = make(map[string]struct{})
+		}
+
+		for _, element := range elements {
+			if _, exists := p.sets[name][element]; !exists {
+				p.sets[name][element] = struct{}{}
+				changed = true
+			}
+		}
+	}
+
+	for name, counts := range state.Counters {
+		if p.counters[name] == nil {
+			p.counters[name] = make(map[string]uint64)
+		}
+
+		for node, count := range counts {
+			if count > p.counters[name][node] {
+				p.counters[name][node] = count
+				changed = true
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/networktest"
+	"github.com/perlin-network/noise/network/pubsub"
+)
+
+func buildNode(t *testing.T) (*network.Network, *Plugin) {
+	plugin := New("registry")
+	plugin.AntiEntropyInterval = 100 * time.Millisecond
+
+	return networktest.NewNode(t, new(discovery.Plugin), pubsub.New(), plugin), plugin
+}
+
+// eventually polls condition until it holds, or fails the test after a timeout.
+func eventually(t *testing.T, description string, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", description)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := New("registry")
+	b := New("registry")
+
+	x := &State{
+		Sets:     map[string][]string{"nodes": {"alice"}},
+		Counters: map[string]map[string]uint64{"joins": {"alice": 2}},
+	}
+
+	y := &State{
+		Sets:     map[string][]string{"nodes": {"bob"}},
+		Counters: map[string]map[string]uint64{"joins": {"alice": 1, "bob": 3}},
+	}
+
+	a.Merge(x)
+	a.Merge(y)
+
+	b.Merge(y)
+	b.Merge(x)
+
+	if a.Merge(x) || a.Merge(y) {
+		t.Fatal("expected merging the same state twice to not change the registry")
+	}
+
+	for _, registry := range []*Plugin{a, b} {
+		if elements := registry.Elements("nodes"); !reflect.DeepEqual(elements, []string{"alice", "bob"}) {
+			t.Fatalf("unexpected elements %v", elements)
+		}
+
+		if value := registry.Value("joins"); value != 5 {
+			t.Fatalf("expected counter to be 5, got %d", value)
+		}
+	}
+}
+
+func TestReplicate(t *testing.T) {
+	alice, aliceRegistry := buildNode(t)
+	bob, bobRegistry := buildNode(t)
+	carol, carolRegistry := buildNode(t)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	// Alice and carol are only connected through bob.
+	alice.Bootstrap(bob.Address)
+	carol.Bootstrap(bob.Address)
+
+	time.Sleep(200 * time.Millisecond)
+
+	if err := aliceRegistry.Add("nodes", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := aliceRegistry.Increment("joins", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := carolRegistry.Increment("joins", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, registry := range []*Plugin{aliceRegistry, bobRegistry, carolRegistry} {
+		eventually(t, "registry to converge", func() bool {
+			return reflect.DeepEqual(registry.Elements("nodes"), []string{"alice"}) && registry.Value("joins") == 3
+		})
+	}
+}
+
+func TestAntiEntropy(t *testing.T) {
+	alice, aliceRegistry := buildNode(t)
+	defer alice.Close()
+
+	if err := aliceRegistry.Add("nodes", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob joins after the update was published, and only learns of it through anti-entropy.
+	bob, bobRegistry := buildNode(t)
+	defer bob.Close()
+
+	bob.Bootstrap(alice.Address)
+
+	eventually(t, "late joiner to converge", func() bool {
+		return reflect.DeepEqual(bobRegistry.Elements("nodes"), []string{"alice"})
+	})
+}
@@ -0,0 +1,152 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/crypto/hashing/blake2b"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/lru"
+)
+
+const (
+	// DefaultValidationConcurrency is the number of publications to a topic validated
+	// at once by default.
+	DefaultValidationConcurrency = 32
+
+	// DefaultValidationTimeout is how long validating a publication may take by default.
+	DefaultValidationTimeout = 5 * time.Second
+
+	// verdictCapacity is the number of verdicts remembered to avoid re-validating
+	// publications of the same data.
+	verdictCapacity = 8192
+)
+
+// Verdict is the outcome of validating a publication.
+type Verdict int
+
+const (
+	// Accept delivers the publication to subscribers, and floods it onto peers.
+	Accept Verdict = iota
+
+	// Ignore drops the publication without penalizing its origin, such as should it
+	// not be validatable for the time being.
+	Ignore
+
+	// Reject drops the publication, and penalizes its origin.
+	Reject
+)
+
+// Validator decides whether a publication to a topic may be delivered and flooded. It
+// may block on work such as signature checks or state lookups, and should give up
+// once ctx is done.
+type Validator func(ctx context.Context, msg *Message) Verdict
+
+// ValidatorOptions configure how publications to a topic are validated.
+type ValidatorOptions struct {
+	// Concurrency is the number of publications validated at once, beyond which
+	// publications are ignored. DefaultValidationConcurrency if zero.
+	Concurrency int
+
+	// Timeout is how long validating a publication may take before it is ignored.
+	// DefaultValidationTimeout if zero.
+	Timeout time.Duration
+}
+
+type topicValidator struct {
+	validate Validator
+	timeout  time.Duration
+	slots    chan struct{}
+}
+
+// AddValidator registers a validator for publications to a topic, replacing any
+// validator already registered for it. Publications are only delivered and flooded
+// once accepted by the validator.
+func (p *Plugin) AddValidator(topic string, validator Validator, options ValidatorOptions) {
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultValidationConcurrency
+	}
+
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultValidationTimeout
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.validators == nil {
+		p.validators = make(map[string]*topicValidator)
+	}
+
+	p.validators[topic] = &topicValidator{
+		validate: validator,
+		timeout:  options.Timeout,
+		slots:    make(chan struct{}, options.Concurrency),
+	}
+}
+
+// RemoveValidator removes the validator registered for a topic.
+func (p *Plugin) RemoveValidator(topic string) {
+	p.mutex.Lock()
+	delete(p.validators, topic)
+	p.mutex.Unlock()
+}
+
+func (p *Plugin) validator(topic string) *topicValidator {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.validators[topic]
+}
+
+// validate validates a publication, consulting the verdict cache first. The second
+// returning parameter is true should the verdict have been cached.
+func (p *Plugin) validate(v *topicValidator, publication *protobuf.Publication) (Verdict, bool) {
+	key := verdictKey(publication)
+
+	if cached, exists := p.verdicts.Load(key); exists {
+		return cached.(Verdict), true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.timeout)
+	defer cancel()
+
+	verdict := v.validate(ctx, newMessage(publication))
+
+	// Publications which timed out or were ignored may be valid later on.
+	if ctx.Err() != nil {
+		return Ignore, false
+	}
+
+	if verdict != Ignore {
+		p.verdicts.Get(key, func() (interface{}, error) { return verdict, nil })
+	}
+
+	return verdict, false
+}
+
+// reject penalizes the peer which relayed a rejected publication to us. Origins are
+// not penalized, as they are not authenticated.
+func (p *Plugin) reject(publication *protobuf.Publication, from peer.ID) {
+	relayer := hex.EncodeToString(from.PublicKey)
+
+	glog.Warningf("Rejected publication to topic %s relayed by %s.", publication.Topic, relayer)
+
+	if p.Penalize != nil {
+		p.Penalize(relayer)
+	}
+}
+
+// verdictKey identifies publications of the same data to the same topic by the same
+// origin, which are given the same verdict.
+func verdictKey(publication *protobuf.Publication) string {
+	hash := blake2b.New().HashBytes(publication.Data)
+	return publication.Topic + ":" + hex.EncodeToString(publication.Origin) + ":" + hex.EncodeToString(hash)
+}
+
+func newVerdictCache() *lru.Cache {
+	return lru.NewCache(verdictCapacity)
+}
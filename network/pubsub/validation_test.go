@@ -0,0 +1,145 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/hex"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestValidation(t *testing.T) {
+	alice, alicePubSub := buildNode(t)
+	bob, bobPubSub := buildNode(t)
+	carol, carolPubSub := buildNode(t)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	var validations, deferrals int32
+
+	bobPubSub.AddValidator("news", func(ctx context.Context, msg *Message) Verdict {
+		atomic.AddInt32(&validations, 1)
+
+		switch string(msg.Data) {
+		case "spam", "forged":
+			return Reject
+		case "later":
+			if atomic.AddInt32(&deferrals, 1) == 1 {
+				return Ignore
+			}
+		}
+		return Accept
+	}, ValidatorOptions{Concurrency: 4})
+
+	penalized := make(chan string, 1)
+	bobPubSub.Penalize = func(relayer string) { penalized <- relayer }
+
+	// Alice and carol are only connected through bob, who validates publications.
+	alice.Bootstrap(bob.Address)
+	carol.Bootstrap(bob.Address)
+
+	messages, unsubscribe := carolPubSub.Subscribe("news")
+	defer unsubscribe()
+
+	publish := func(data string) {
+		if err := alicePubSub.Publish("news", []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	receive := func(which string, data string) {
+		select {
+		case msg := <-messages:
+			if string(msg.Data) != data {
+				t.Fatalf("expected only accepted publications to be flooded, got %q", msg.Data)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for the %s accepted publication to be flooded", which)
+		}
+	}
+
+	waitForPeers(t, bob, 2)
+
+	publish("spam")
+	publish("hello")
+
+	select {
+	case relayer := <-penalized:
+		if relayer != hex.EncodeToString(alice.ID.PublicKey) {
+			t.Fatalf("expected alice to be penalized, got %s", relayer)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the relayer of the rejected publication to be penalized")
+	}
+
+	receive("first", "hello")
+
+	// Publications of the same data are given the cached verdict.
+	publish("hello")
+	receive("second", "hello")
+
+	if n := atomic.LoadInt32(&validations); n != 2 {
+		t.Fatalf("expected 2 publications to be validated, got %d", n)
+	}
+
+	// Peers relaying publications are penalized, rather than the origins they claim,
+	// should the origins not have signed them.
+	carol.BroadcastByAddresses(&protobuf.Publication{
+		Topic:  "news",
+		Data:   []byte("forged"),
+		Origin: alice.ID.PublicKey,
+		Seqno:  100,
+	}, bob.Address)
+
+	select {
+	case relayer := <-penalized:
+		if relayer != hex.EncodeToString(carol.ID.PublicKey) {
+			t.Fatalf("expected carol to be penalized, got %s", relayer)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the relayer of the forged publication to be penalized")
+	}
+
+	// Publications ignored while being validated are not marked as seen, and are
+	// accepted should they be received anew.
+	later := &protobuf.Publication{Topic: "news", Data: []byte("later"), Origin: alice.ID.PublicKey, Seqno: 101}
+	if err := sign(alice, later); err != nil {
+		t.Fatal(err)
+	}
+
+	alice.BroadcastByAddresses(later, bob.Address)
+
+	for atomic.LoadInt32(&deferrals) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	alice.BroadcastByAddresses(later, bob.Address)
+	receive("ignored", "later")
+}
+
+func waitForPeers(t *testing.T, node *network.Network, count int) {
+	deadline := time.Now().Add(3 * time.Second)
+
+	for {
+		peers := 0
+		node.Peers.Range(func(key, value interface{}) bool {
+			peers++
+			return true
+		})
+
+		if peers >= count {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d peers to connect", count)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultQuarantineBase is the default period an address is quarantined for after
+	// its first failed dial.
+	DefaultQuarantineBase = 1 * time.Second
+
+	// DefaultQuarantineMax is the default cap on the period an address is quarantined for.
+	DefaultQuarantineMax = 5 * time.Minute
+)
+
+// maxQuarantineShift caps the number of times the quarantine period is doubled.
+const maxQuarantineShift = 62
+
+// ErrQuarantined is returned when dialing an address which recently failed to be dialed.
+var ErrQuarantined = errors.New("address is quarantined after failed dials")
+
+type quarantineEntry struct {
+	failures    uint
+	lastFailure time.Time
+	until       time.Time
+}
+
+// DialQuarantine tracks failed dials per address, and quarantines addresses for periods
+// doubling with each consecutive failure up to a cap. A nil *DialQuarantine quarantines
+// nothing.
+type DialQuarantine struct {
+	mutex   sync.Mutex
+	entries map[string]*quarantineEntry
+
+	base  time.Duration
+	max   time.Duration
+	clock clock.Clock
+}
+
+// NewDialQuarantine creates a quarantine whose periods start at base and are capped at
+// max. A nil clock defaults to the system clock.
+func NewDialQuarantine(base time.Duration, max time.Duration, c clock.Clock) *DialQuarantine {
+	return &DialQuarantine{
+		entries: make(map[string]*quarantineEntry),
+		base:    base,
+		max:     max,
+		clock:   clock.Or(c),
+	}
+}
+
+// Check returns ErrQuarantined should an address be quarantined.
+func (q *DialQuarantine) Check(address string) error {
+	if q == nil {
+		return nil
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entry, exists := q.entries[address]
+	if !exists {
+		return nil
+	}
+
+	now := q.clock.Now()
+
+	if now.Before(entry.until) {
+		return errors.Wrapf(ErrQuarantined, "%s is quarantined for %s", address, entry.until.Sub(now))
+	}
+
+	// Forget addresses which have not failed in a long while.
+	if now.Sub(entry.lastFailure) > 2*q.max {
+		delete(q.entries, address)
+	}
+
+	return nil
+}
+
+// Failure records a failed dial to an address, and quarantines it.
+func (q *DialQuarantine) Failure(address string) {
+	if q == nil {
+		return
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entry, exists := q.entries[address]
+	if !exists {
+		entry = new(quarantineEntry)
+		q.entries[address] = entry
+	}
+
+	// Double the period with each failure, comparing against the cap shifted right
+	// rather than the base shifted left such that the shift may not overflow.
+	period := q.max
+	if shift := entry.failures; shift < maxQuarantineShift && q.base <= q.max>>shift {
+		period = q.base << shift
+	}
+
+	entry.failures++
+	entry.lastFailure = q.clock.Now()
+	entry.until = entry.lastFailure.Add(period)
+}
+
+// Success records a successful dial to an address, lifting its quarantine.
+func (q *DialQuarantine) Success(address string) {
+	if q == nil {
+		return
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	delete(q.entries, address)
+}
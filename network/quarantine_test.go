@@ -0,0 +1,76 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+func TestDialQuarantine(t *testing.T) {
+	mock := clock.NewMock(time.Unix(0, 0))
+	q := NewDialQuarantine(1*time.Second, 3*time.Second, mock)
+
+	if err := q.Check("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Quarantine periods should double from 1s up to a cap of 3s.
+	for _, period := range []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		q.Failure("a")
+
+		if err := q.Check("a"); errors.Cause(err) != ErrQuarantined {
+			t.Fatalf("expected address to be quarantined, got %v", err)
+		}
+
+		mock.Add(period - time.Millisecond)
+
+		if err := q.Check("a"); errors.Cause(err) != ErrQuarantined {
+			t.Fatalf("expected address to be quarantined for %s, got %v", period, err)
+		}
+
+		mock.Add(time.Millisecond)
+
+		if err := q.Check("a"); err != nil {
+			t.Fatalf("expected quarantine to be lifted after %s, got %v", period, err)
+		}
+	}
+
+	if err := q.Check("b"); err != nil {
+		t.Fatalf("expected other addresses to be unaffected, got %v", err)
+	}
+
+	q.Failure("a")
+	q.Success("a")
+
+	if err := q.Check("a"); err != nil {
+		t.Fatalf("expected successful dial to lift quarantine, got %v", err)
+	}
+}
+
+func TestDialQuarantineOverflow(t *testing.T) {
+	mock := clock.NewMock(time.Unix(0, 0))
+	q := NewDialQuarantine(time.Hour, 24*time.Hour, mock)
+
+	// Doubling the period past the cap must clamp it to the cap rather than overflow.
+	for i := 0; i < 40; i++ {
+		q.Failure("a")
+
+		if err := q.Check("a"); errors.Cause(err) != ErrQuarantined {
+			t.Fatalf("expected address to be quarantined after %d failures, got %v", i+1, err)
+		}
+	}
+
+	mock.Add(24*time.Hour - time.Millisecond)
+
+	if err := q.Check("a"); errors.Cause(err) != ErrQuarantined {
+		t.Fatalf("expected address to be quarantined for the capped period, got %v", err)
+	}
+
+	mock.Add(time.Millisecond)
+
+	if err := q.Check("a"); err != nil {
+		t.Fatalf("expected quarantine to be lifted after the capped period, got %v", err)
+	}
+}
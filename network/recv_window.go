@@ -7,6 +7,10 @@ import (
 	"github.com/pkg/errors"
 )
 
+// recvWindowSize is the number of messages buffered per connection awaiting those
+// preceding them.
+const recvWindowSize = 4096
+
 // RecvWindow represents a window that buffers and cuts off messages based on their priority.
 type RecvWindow struct {
 	sync.Mutex
@@ -49,7 +53,7 @@ func (w *RecvWindow) Update(n *Network) error {
 		select {
 		case n.RecvQueue <- msg:
 		default:
-			return errors.New("recv queue is full")
+			return errors.Wrap(ErrQueueFull, "recv queue")
 		}
 	}
 
@@ -0,0 +1,165 @@
+package network
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// builtinMessages are the message types handled by the network itself.
+var builtinMessages = []proto.Message{
+	new(protobuf.Ping),
+	new(protobuf.Pong),
+	new(protobuf.Bytes),
+	new(protobuf.Echo),
+	new(protobuf.EchoReply),
+	new(protobuf.SlowDown),
+	new(protobuf.StreamCompressionRequest),
+	new(protobuf.StreamCompressionResponse),
+	new(protobuf.IdentityChallenge),
+	new(protobuf.IdentityResponse),
+	new(protobuf.SessionTicket),
+	new(protobuf.ResumeSession),
+}
+
+// MessageType is a message type registered into a MessageRegistry.
+type MessageType struct {
+	// Name is the fully-qualified protobuf name of the type, such as protobuf.Ping.
+	Name string `json:"name"`
+
+	// GoType is the Go type messages of the type are unmarshalled into.
+	GoType string `json:"go_type"`
+}
+
+// MessageRegistry is a registry of the message types a network handles, which detects
+// types colliding by their names. It is frozen once the network is built, after which
+// registering types fails unless it is explicitly unlocked.
+//
+// The registry is bookkeeping only: messages of types not registered are still
+// dispatched should protobuf know of them.
+type MessageRegistry struct {
+	mutex  sync.RWMutex
+	types  map[string]reflect.Type
+	frozen bool
+
+	// warned holds the names of the unregistered types received that were warned of.
+	warned map[string]struct{}
+}
+
+// NewMessageRegistry creates a new, unlocked registry of the network's own message types.
+func NewMessageRegistry() *MessageRegistry {
+	registry := &MessageRegistry{types: make(map[string]reflect.Type), warned: make(map[string]struct{})}
+
+	for _, message := range builtinMessages {
+		if err := registry.Register(message); err != nil {
+			panic(err)
+		}
+	}
+
+	return registry
+}
+
+// Register registers the type of a message. Registering a type twice is a no-op. It
+// fails should the type not be registered with protobuf, should another type be
+// registered by its name, or should the registry be frozen.
+func (r *MessageRegistry) Register(message proto.Message) error {
+	return r.register(message, false)
+}
+
+// register registers the type of a message, regardless of the registry being frozen
+// should force be set.
+func (r *MessageRegistry) register(message proto.Message, force bool) error {
+	typ := reflect.TypeOf(message)
+
+	name := proto.MessageName(message)
+	if len(name) == 0 {
+		return errors.Wrapf(ErrUnknownMessageType, "%s is not registered with protobuf", typ)
+	}
+
+	// Messages are unmarshalled into whichever type protobuf resolves their name to.
+	if resolved := proto.MessageType(name); resolved != typ {
+		return errors.Wrapf(ErrMessageTypeCollision, "%s is registered with protobuf as %s, not %s", name, resolved, typ)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if registered, exists := r.types[name]; exists {
+		if registered != typ {
+			return errors.Wrapf(ErrMessageTypeCollision, "%s is registered as %s, not %s", name, registered, typ)
+		}
+		return nil
+	}
+
+	if r.frozen && !force {
+		return errors.Wrapf(ErrRegistryFrozen, "failed to register %s", name)
+	}
+
+	r.types[name] = typ
+
+	return nil
+}
+
+// Registered returns true should a message type by its fully-qualified protobuf name be
+// registered.
+func (r *MessageRegistry) Registered(name string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, exists := r.types[name]
+	return exists
+}
+
+// firstUnregistered returns true should a message type by its name not be registered,
+// and not have been reported as such before.
+func (r *MessageRegistry) firstUnregistered(name string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.types[name]; exists {
+		return false
+	}
+
+	if _, exists := r.warned[name]; exists {
+		return false
+	}
+
+	r.warned[name] = struct{}{}
+	return true
+}
+
+// Types returns all registered message types, sorted by their names.
+func (r *MessageRegistry) Types() []MessageType {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	types := make([]MessageType, 0, len(r.types))
+	for name, typ := range r.types {
+		types = append(types, MessageType{Name: name, GoType: typ.String()})
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name < types[j].Name
+	})
+
+	return types
+}
+
+// Freeze stops further message types from being registered.
+func (r *MessageRegistry) Freeze() {
+	r.mutex.Lock()
+	r.frozen = true
+	r.mutex.Unlock()
+}
+
+// Unlock allows message types to be registered after the registry was frozen, such
+// as by plugins loaded at runtime.
+func (r *MessageRegistry) Unlock() {
+	r.mutex.Lock()
+	r.frozen = false
+	r.mutex.Unlock()
+}
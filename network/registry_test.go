@@ -0,0 +1,119 @@
+package network_test
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// impostorPing claims the name of protobuf.Ping.
+type impostorPing struct {
+	protobuf.Ping
+}
+
+func (*impostorPing) XXX_MessageName() string {
+	return "protobuf.Ping"
+}
+
+// unnamedPing is not registered with protobuf.
+type unnamedPing struct {
+	protobuf.Ping
+}
+
+func TestMessageRegistry(t *testing.T) {
+	registry := network.NewMessageRegistry()
+
+	if !registry.Registered("protobuf.Ping") {
+		t.Fatal("expected the network's own message types to be registered")
+	}
+
+	if err := registry.Register(new(protobuf.Datagram)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := registry.Register(new(protobuf.Datagram)); err != nil {
+		t.Fatalf("expected registering a type twice to be a no-op, got %v", err)
+	}
+
+	if err := errors.Cause(registry.Register(new(impostorPing))); err != network.ErrMessageTypeCollision {
+		t.Fatalf("expected a type claiming the name of another to collide, got %v", err)
+	}
+
+	if err := errors.Cause(registry.Register(new(unnamedPing))); err != network.ErrUnknownMessageType {
+		t.Fatalf("expected a type unknown to protobuf to be rejected, got %v", err)
+	}
+
+	registry.Freeze()
+
+	if err := errors.Cause(registry.Register(new(protobuf.Summary))); err != network.ErrRegistryFrozen {
+		t.Fatalf("expected registering into a frozen registry to fail, got %v", err)
+	}
+
+	registry.Unlock()
+
+	if err := registry.Register(new(protobuf.Summary)); err != nil {
+		t.Fatal(err)
+	}
+
+	types := registry.Types()
+	for i := 1; i < len(types); i++ {
+		if types[i-1].Name >= types[i].Name {
+			t.Fatalf("expected types to be sorted by name, got %v", types)
+		}
+	}
+}
+
+func TestBuildFreezesMessageRegistry(t *testing.T) {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", 361))
+
+	if err := builder.RegisterMessageType(new(protobuf.Datagram)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := builder.RegisterMessageType(new(impostorPing)); err == nil {
+		t.Fatal("expected a colliding message type to be rejected by the builder")
+	}
+
+	net, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !net.Messages.Registered("protobuf.Datagram") {
+		t.Fatal("expected message types registered with the builder to be registered")
+	}
+
+	if err := errors.Cause(net.Messages.Register(new(protobuf.Summary))); err != network.ErrRegistryFrozen {
+		t.Fatalf("expected the registry to be frozen once built, got %v", err)
+	}
+}
+
+func TestPluginsRegisterIntoFrozenRegistry(t *testing.T) {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", 379))
+
+	net, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Plugins register their types as they are started, after the network is built.
+	if err := net.RegisterMessages(new(protobuf.Summary)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !net.Messages.Registered("protobuf.Summary") {
+		t.Fatal("expected types registered by plugins to be registered despite the registry being frozen")
+	}
+
+	if err := errors.Cause(net.RegisterMessages(new(impostorPing))); err != network.ErrMessageTypeCollision {
+		t.Fatalf("expected colliding types registered by plugins to be rejected, got %v", err)
+	}
+}
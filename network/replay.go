@@ -0,0 +1,325 @@
+package network
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+	"github.com/xtaci/smux"
+)
+
+// DefaultReplayDeliveryTimeout is how long each replayed envelope is waited on to be
+// handled by default before being considered dropped.
+const DefaultReplayDeliveryTimeout = 1 * time.Second
+
+// ReplayEnvelope is an inbound envelope recorded for replay.
+type ReplayEnvelope struct {
+	// Time is when the envelope was received.
+	Time time.Time
+
+	// Address is the address of the peer the envelope was received from.
+	Address string
+
+	Message *protobuf.Message
+}
+
+// ReplayOptions configure how recorded envelopes are replayed.
+type ReplayOptions struct {
+	// Speed scales the gaps between envelopes as they were received, e.g. 2 replays
+	// envelopes twice as fast. Envelopes are replayed back to back should it be zero.
+	Speed float64
+
+	// DeliveryTimeout is how long each envelope is waited on to be handled before being
+	// considered dropped. DefaultReplayDeliveryTimeout if zero.
+	DeliveryTimeout time.Duration
+}
+
+// ReplayStats counts the envelopes a replay delivered which the network finished
+// handling, and those dropped which failed to be sent or were not handled in time.
+type ReplayStats struct {
+	Delivered int
+	Dropped   int
+}
+
+// Replay delivers recorded inbound envelopes to the network in the order they were
+// received, each only once the one prior was handled by all plugins, such that bugs reported from
+// production reproduce deterministically. It is meant for fresh networks.
+//
+// Each recorded peer is stood in for by an in-memory session of its own, which is
+// served as though the network dialed the peer over it rather than accepted, such that
+// its identity is trusted without dialing back to it, and which discards all replies.
+// Envelopes are renumbered to follow on from one another, which leaves their
+// signatures intact.
+func (n *Network) Replay(ctx context.Context, envelopes []ReplayEnvelope, options ReplayOptions) (ReplayStats, error) {
+	var stats ReplayStats
+
+	timeout := options.DeliveryTimeout
+	if timeout <= 0 {
+		timeout = DefaultReplayDeliveryTimeout
+	}
+
+	peers := make(map[string]*replayPeer)
+
+	defer func() {
+		for _, peer := range peers {
+			peer.close()
+		}
+	}()
+
+	var last time.Time
+
+	for _, envelope := range envelopes {
+		if options.Speed > 0 && !last.IsZero() && envelope.Time.After(last) {
+			select {
+			case <-n.clock().After(time.Duration(float64(envelope.Time.Sub(last)) / options.Speed)):
+			case <-ctx.Done():
+				return stats, ctx.Err()
+			}
+		}
+		last = envelope.Time
+
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		msg := envelope.Message
+		if msg == nil || msg.Sender == nil {
+			stats.Dropped++
+			continue
+		}
+
+		address, err := ToUnifiedAddress(msg.Sender.Address)
+		if err != nil {
+			stats.Dropped++
+			continue
+		}
+
+		peer, exists := peers[address]
+		if !exists {
+			if peer, err = n.replayPeer(address); err != nil {
+				return stats, err
+			}
+			peers[address] = peer
+		}
+
+		if n.replay.deliver(ctx, peer, msg, timeout) {
+			stats.Delivered++
+		} else {
+			stats.Dropped++
+		}
+	}
+
+	return stats, nil
+}
+
+// SignedEnvelope returns a copy of a received message encoded in the envelope version
+// its signature was made in, such that it verifies once received anew, such as to be
+// recorded for replay. Received messages are upgraded to the current version, whereas
+// peers sign them in the version they negotiated with us.
+func (n *Network) SignedEnvelope(msg *protobuf.Message) (*protobuf.Message, error) {
+	if msg.Message == nil || msg.Sender == nil {
+		return nil, errors.Wrap(ErrInvalidMessage, "either no message or no sender")
+	}
+
+	for version := msg.Version; ; version-- {
+		if n.verifySignature(msg, version) {
+			signed, err := downgradeEnvelope(msg, version)
+			if err != nil {
+				return nil, err
+			}
+
+			if signed == msg {
+				signed = proto.Clone(msg).(*protobuf.Message)
+				signed.Version = version
+			}
+
+			// Session tickets are not covered by signatures, and are not to be replayed.
+			signed.Ticket = nil
+
+			return signed, nil
+		}
+
+		if version == MinEnvelopeVersion {
+			return nil, errors.Wrapf(ErrInvalidSignature, "message from %s", msg.Sender.Address)
+		}
+	}
+}
+
+// replayState tracks the envelopes awaited by a replay.
+type replayState struct {
+	// handled maps the signatures of envelopes awaited to channels closed once the
+	// envelopes are handled.
+	handled sync.Map
+}
+
+// awaiting returns a function to be called once an envelope was handled, which
+// notifies the replay should it be awaiting the envelope.
+func (r *replayState) awaiting(msg *protobuf.Message) func() {
+	handled, awaited := r.handled.Load(string(msg.Signature))
+	if !awaited {
+		return func() {}
+	}
+
+	r.handled.Delete(string(msg.Signature))
+
+	return func() {
+		close(handled.(chan struct{}))
+	}
+}
+
+// deliver sends an envelope on behalf of a peer, and waits for the network to have
+// handled it. Returns false should the envelope fail to be sent, or be dropped.
+func (r *replayState) deliver(ctx context.Context, peer *replayPeer, msg *protobuf.Message, timeout time.Duration) bool {
+	handled := make(chan struct{})
+
+	r.handled.Store(string(msg.Signature), handled)
+	defer r.handled.Delete(string(msg.Signature))
+
+	if err := peer.send(msg); err != nil {
+		glog.Warningf("Failed to replay envelope from %s [err=%s]", peer.address, err)
+		return false
+	}
+
+	// The timeout guards against envelopes the network drops, and hence must elapse in
+	// real time even should the network run on a simulated clock.
+	select {
+	case <-handled:
+		return true
+	case <-time.After(timeout):
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// replayPeer stands in for a recorded peer during a replay.
+type replayPeer struct {
+	network *Network
+	address string
+	client  *PeerClient
+
+	// incoming carries the peer's envelopes to the network, and outgoing the network's
+	// replies to sink, which discards them.
+	incoming *smux.Session
+	outgoing *smux.Session
+	sink     *smux.Session
+
+	messageNonce uint64
+}
+
+// replayPeer connects a stand-in for a recorded peer to the network.
+func (n *Network) replayPeer(address string) (*replayPeer, error) {
+	if address == n.Address {
+		return nil, errors.Wrapf(ErrDialSelf, "replay of envelopes from %s", address)
+	}
+
+	client, err := createPeerClient(n, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, exists := n.Peers.LoadOrStore(address, client); exists {
+		return nil, errors.Errorf("peer %s to replay envelopes from is already connected", address)
+	}
+
+	// Replies are written to a session whose other end discards them.
+	local, remote := net.Pipe()
+
+	outgoing, err := smux.Client(local, muxConfig())
+	if err != nil {
+		n.Peers.Delete(address)
+		return nil, err
+	}
+
+	sink, err := smux.Server(remote, muxConfig())
+	if err != nil {
+		outgoing.Close()
+		n.Peers.Delete(address)
+		return nil, err
+	}
+
+	n.spawn(GoroutineReplay, func() { n.discardStreams(sink) })
+
+	n.Connections.Store(address, &ConnState{session: outgoing})
+
+	close(client.outgoingReady)
+	client.Init()
+
+	peer := &replayPeer{network: n, address: address, client: client, outgoing: outgoing, sink: sink}
+
+	// Envelopes are served over a session of their own rather than accepted, such that
+	// the stand-in is neither dialed back nor issued tickets.
+	local, remote = net.Pipe()
+
+	if peer.incoming, err = smux.Client(local, muxConfig()); err != nil {
+		peer.close()
+		return nil, errors.Wrap(err, "failed to connect stand-in to network to replay into")
+	}
+
+	served, err := smux.Server(remote, muxConfig())
+	if err != nil {
+		peer.close()
+		return nil, errors.Wrap(err, "failed to connect stand-in to network to replay into")
+	}
+
+	atomic.AddInt32(&client.connections, 1)
+	n.spawn(GoroutineReplay, func() { n.serveOutgoing(client, served) })
+
+	return peer, nil
+}
+
+// send sends an envelope to the network on behalf of the peer.
+func (p *replayPeer) send(msg *protobuf.Message) error {
+	msg = proto.Clone(msg).(*protobuf.Message)
+
+	if msg.Priority {
+		msg.MessageNonce = 0
+	} else {
+		p.messageNonce++
+		msg.MessageNonce = p.messageNonce
+	}
+
+	stream, err := p.incoming.OpenStream()
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	return sendMessage(stream, msg)
+}
+
+func (p *replayPeer) close() {
+	if p.incoming != nil {
+		p.incoming.Close()
+	}
+
+	p.client.Close()
+	p.outgoing.Close()
+	p.sink.Close()
+
+	p.network.Peers.Delete(p.address)
+	p.network.Connections.Delete(p.address)
+}
+
+// discardStreams accepts and discards all streams opened over a session.
+func (n *Network) discardStreams(session *smux.Session) {
+	for {
+		stream, err := session.AcceptStream()
+		if err != nil {
+			return
+		}
+
+		n.spawn(GoroutineReplay, func() {
+			defer stream.Close()
+			io.Copy(ioutil.Discard, stream)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package network
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+// PeerSelector picks an ordered list of candidate peers from the network.
+type PeerSelector func(net *Network) []*PeerClient
+
+// RequestAny hedges a request across the candidate peers picked by a selector, and returns
+// the first successful response while cancelling all other in-flight requests.
+//
+// Candidates are requested in order, with each successive candidate being requested after
+// the request's stagger delay elapses or once an in-flight request fails. A zero stagger
+// delay requests all candidates at once.
+func (n *Network) RequestAny(ctx context.Context, req *rpc.Request, selector Selector) (proto.Message, error) {
+	candidates := selector.Select(n)
+	if len(candidates) == 0 {
+		return nil, errors.Wrap(ErrPeerNotFound, "no candidate peers to request from")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		response proto.Message
+		err      error
+	}
+
+	results := make(chan result, len(candidates))
+
+	next, pending := 0, 0
+
+	launch := func() {
+		client := candidates[next]
+
+		n.spawn(GoroutineRequest, func() {
+			response, err := client.RequestWithContext(ctx, req)
+			results <- result{response: response, err: err}
+		})
+
+		next++
+		pending++
+	}
+
+	var err error
+
+	for next < len(candidates) || pending > 0 {
+		// Request the next candidate immediately should nothing be in flight,
+		// or should requests not be staggered.
+		for next < len(candidates) && (pending == 0 || req.Stagger <= 0) {
+			launch()
+		}
+
+		var stagger <-chan time.Time
+		var timer clock.Timer
+
+		if next < len(candidates) {
+			timer = n.clock().NewTimer(req.Stagger)
+			stagger = timer.C()
+		}
+
+		select {
+		case res := <-results:
+			pending--
+
+			if res.err == nil {
+				return res.response, nil
+			}
+
+			err = res.err
+		case <-stagger:
+			launch()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+
+	return nil, errors.Wrap(err, "all candidate peers failed to respond")
+}
@@ -0,0 +1,90 @@
+package network_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func buildNode(t *testing.T, port uint16, plugin *discovery.Plugin) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", port))
+	builder.AddPlugin(plugin)
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestRequestAny(t *testing.T) {
+	requester := buildNode(t, 13000, new(discovery.Plugin))
+	silent := buildNode(t, 13001, &discovery.Plugin{DisablePing: true})
+	responsive := buildNode(t, 13002, new(discovery.Plugin))
+
+	defer requester.Close()
+	defer silent.Close()
+	defer responsive.Close()
+
+	requester.Bootstrap(silent.Address, responsive.Address)
+
+	selector := network.PeerSelector(func(net *network.Network) []*network.PeerClient {
+		var clients []*network.PeerClient
+		for _, address := range []string{silent.Address, responsive.Address} {
+			client, err := net.Client(address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clients = append(clients, client)
+		}
+		return clients
+	})
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.Ping{})
+	request.SetTimeout(3 * time.Second)
+	request.SetStagger(100 * time.Millisecond)
+
+	start := time.Now()
+
+	response, err := requester.RequestAny(context.Background(), request, selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := response.(*protobuf.Pong); !ok {
+		t.Fatalf("expected pong, got %v", response)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected hedged request to not wait on the silent peer, took %s", elapsed)
+	}
+}
+
+func TestRequestAnyNoCandidates(t *testing.T) {
+	node := buildNode(t, 13003, new(discovery.Plugin))
+	defer node.Close()
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.Ping{})
+	request.SetTimeout(1 * time.Second)
+
+	_, err := node.RequestAny(context.Background(), request, network.RandomSelector{K: 3})
+	if err == nil {
+		t.Fatal("expected request with no candidates to fail")
+	}
+}
@@ -0,0 +1,198 @@
+package network
+
+import (
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrResourceLimitExceeded is returned should reserving a resource exceed its budget.
+var ErrResourceLimitExceeded = errors.New("resource limit exceeded")
+
+// ResourceLimits denotes the global and per-peer budgets enforced by a ResourceManager.
+// Zero values denote no limit.
+type ResourceLimits struct {
+	// Maximum number of streams being concurrently read from.
+	MaxStreams        int
+	MaxStreamsPerPeer int
+
+	// Maximum number of bytes concurrently buffered from reading messages.
+	MaxBufferedBytes        int64
+	MaxBufferedBytesPerPeer int64
+
+	// Maximum number of goroutines concurrently handling messages.
+	MaxGoroutines        int
+	MaxGoroutinesPerPeer int
+}
+
+// ResourceUsage denotes an amount of resources reserved.
+type ResourceUsage struct {
+	Streams       int
+	BufferedBytes int64
+	Goroutines    int
+}
+
+func (u ResourceUsage) empty() bool {
+	return u.Streams == 0 && u.BufferedBytes == 0 && u.Goroutines == 0
+}
+
+// resourceKey returns the key the resources reserved by a peer are accounted under,
+// given either its address or the address of a connection from it. Peers are accounted
+// by host, such that a peer can neither spread its reservations across connections
+// from several ports, nor have them go unreleased by migrating between addresses.
+func resourceKey(address string) string {
+	if info, err := ParseAddress(address); err == nil {
+		return info.Host
+	}
+
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+
+	return address
+}
+
+// ResourceManager accounts for resources reserved globally and per peer, and rejects
+// reservations which exceed its limits. Peers are given either by their addresses or
+// by the addresses of connections from them. A nil ResourceManager enforces no limits.
+type ResourceManager struct {
+	mutex sync.Mutex
+
+	limits ResourceLimits
+
+	global ResourceUsage
+	peers  map[string]*ResourceUsage
+}
+
+// NewResourceManager creates a new resource manager enforcing a set of limits.
+func NewResourceManager(limits ResourceLimits) *ResourceManager {
+	return &ResourceManager{
+		limits: limits,
+		peers:  make(map[string]*ResourceUsage),
+	}
+}
+
+// Limits returns the limits enforced by the resource manager.
+func (m *ResourceManager) Limits() ResourceLimits {
+	if m == nil {
+		return ResourceLimits{}
+	}
+	return m.limits
+}
+
+// Usage returns the amount of resources reserved across all peers.
+func (m *ResourceManager) Usage() ResourceUsage {
+	if m == nil {
+		return ResourceUsage{}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.global
+}
+
+// PeerUsage returns the amount of resources reserved by a given peer.
+func (m *ResourceManager) PeerUsage(peer string) ResourceUsage {
+	if m == nil {
+		return ResourceUsage{}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if usage, exists := m.peers[resourceKey(peer)]; exists {
+		return *usage
+	}
+	return ResourceUsage{}
+}
+
+// reserve atomically applies a delta to both the global and per-peer usage should
+// neither exceed their limits.
+func (m *ResourceManager) reserve(peer string, delta ResourceUsage) error {
+	if m == nil {
+		return nil
+	}
+
+	peer = resourceKey(peer)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	usage, exists := m.peers[peer]
+	if !exists {
+		usage = new(ResourceUsage)
+	}
+
+	exceeds := func(limit, value int64) bool {
+		return limit > 0 && value > limit
+	}
+
+	switch {
+	case exceeds(int64(m.limits.MaxStreams), int64(m.global.Streams+delta.Streams)),
+		exceeds(int64(m.limits.MaxStreamsPerPeer), int64(usage.Streams+delta.Streams)):
+		return errors.Wrap(ErrResourceLimitExceeded, "too many streams")
+	case exceeds(m.limits.MaxBufferedBytes, m.global.BufferedBytes+delta.BufferedBytes),
+		exceeds(m.limits.MaxBufferedBytesPerPeer, usage.BufferedBytes+delta.BufferedBytes):
+		return errors.Wrap(ErrResourceLimitExceeded, "too many buffered bytes")
+	case exceeds(int64(m.limits.MaxGoroutines), int64(m.global.Goroutines+delta.Goroutines)),
+		exceeds(int64(m.limits.MaxGoroutinesPerPeer), int64(usage.Goroutines+delta.Goroutines)):
+		return errors.Wrap(ErrResourceLimitExceeded, "too many goroutines")
+	}
+
+	m.global.Streams += delta.Streams
+	m.global.BufferedBytes += delta.BufferedBytes
+	m.global.Goroutines += delta.Goroutines
+
+	usage.Streams += delta.Streams
+	usage.BufferedBytes += delta.BufferedBytes
+	usage.Goroutines += delta.Goroutines
+
+	if usage.empty() {
+		delete(m.peers, peer)
+	} else {
+		m.peers[peer] = usage
+	}
+
+	return nil
+}
+
+// release returns a previously reserved amount of resources.
+func (m *ResourceManager) release(peer string, delta ResourceUsage) {
+	m.reserve(peer, ResourceUsage{
+		Streams:       -delta.Streams,
+		BufferedBytes: -delta.BufferedBytes,
+		Goroutines:    -delta.Goroutines,
+	})
+}
+
+// ReserveStream reserves a stream for a peer.
+func (m *ResourceManager) ReserveStream(peer string) error {
+	return m.reserve(peer, ResourceUsage{Streams: 1})
+}
+
+// ReleaseStream releases a stream previously reserved for a peer.
+func (m *ResourceManager) ReleaseStream(peer string) {
+	m.release(peer, ResourceUsage{Streams: 1})
+}
+
+// ReserveMemory reserves a number of bytes to be buffered for a peer.
+func (m *ResourceManager) ReserveMemory(peer string, size int64) error {
+	return m.reserve(peer, ResourceUsage{BufferedBytes: size})
+}
+
+// ReleaseMemory releases a number of bytes previously reserved for a peer.
+func (m *ResourceManager) ReleaseMemory(peer string, size int64) {
+	m.release(peer, ResourceUsage{BufferedBytes: size})
+}
+
+// ReserveGoroutine reserves a goroutine to handle work for a peer.
+func (m *ResourceManager) ReserveGoroutine(peer string) error {
+	return m.reserve(peer, ResourceUsage{Goroutines: 1})
+}
+
+// ReleaseGoroutine releases a goroutine previously reserved for a peer.
+func (m *ResourceManager) ReleaseGoroutine(peer string) {
+	m.release(peer, ResourceUsage{Goroutines: 1})
+}
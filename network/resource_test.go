@@ -0,0 +1,97 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestResourceManagerPerPeer(t *testing.T) {
+	m := NewResourceManager(ResourceLimits{MaxStreamsPerPeer: 2})
+
+	for i := 0; i < 2; i++ {
+		if err := m.ReserveStream("a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.ReserveStream("a"); errors.Cause(err) != ErrResourceLimitExceeded {
+		t.Fatalf("expected per-peer stream limit to be exceeded, got %v", err)
+	}
+
+	if err := m.ReserveStream("b"); err != nil {
+		t.Fatalf("expected other peers to be unaffected, got %v", err)
+	}
+
+	m.ReleaseStream("a")
+
+	if err := m.ReserveStream("a"); err != nil {
+		t.Fatalf("expected released stream to be reservable, got %v", err)
+	}
+
+	if usage := m.Usage(); usage.Streams != 3 {
+		t.Fatalf("expected 3 streams to be reserved globally, got %d", usage.Streams)
+	}
+}
+
+func TestResourceManagerGlobal(t *testing.T) {
+	m := NewResourceManager(ResourceLimits{MaxBufferedBytes: 100, MaxGoroutines: 1})
+
+	if err := m.ReserveMemory("a", 60); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ReserveMemory("b", 60); errors.Cause(err) != ErrResourceLimitExceeded {
+		t.Fatalf("expected global memory limit to be exceeded, got %v", err)
+	}
+
+	if usage := m.PeerUsage("b"); !usage.empty() {
+		t.Fatalf("expected rejected reservation to not be accounted for, got %+v", usage)
+	}
+
+	m.ReleaseMemory("a", 60)
+
+	if err := m.ReserveMemory("b", 60); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ReserveGoroutine("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ReserveGoroutine("b"); errors.Cause(err) != ErrResourceLimitExceeded {
+		t.Fatalf("expected global goroutine limit to be exceeded, got %v", err)
+	}
+}
+
+func TestResourceManagerNil(t *testing.T) {
+	var m *ResourceManager
+
+	if err := m.ReserveStream("a"); err != nil {
+		t.Fatalf("expected nil resource manager to enforce no limits, got %v", err)
+	}
+
+	m.ReleaseStream("a")
+}
+
+func TestResourceManagerKeys(t *testing.T) {
+	m := NewResourceManager(ResourceLimits{MaxStreamsPerPeer: 1})
+
+	// A peer is accounted for the same whether given by its address or by the
+	// address of a connection from it.
+	if err := m.ReserveStream("tcp://127.0.0.1:3000"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ReserveStream("127.0.0.1:52314"); errors.Cause(err) != ErrResourceLimitExceeded {
+		t.Fatalf("expected connections from the peer's host to share its budget, got %v", err)
+	}
+
+	// The stream reserved under the peer's address is released under the address of
+	// a connection from it.
+	m.ReleaseStream("127.0.0.1:52314")
+
+	if usage := m.PeerUsage("tcp://127.0.0.1:3000"); !usage.empty() {
+		t.Fatalf("expected stream to be released, got %+v", usage)
+	}
+}
@@ -0,0 +1,106 @@
+package network
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/peer"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+)
+
+// DefaultRequestClosestPeers is the number of peers closest to a key which
+// RequestClosest requests from.
+const DefaultRequestClosestPeers = 3
+
+// Router finds the peers closest to a target within the ID space of the network. It
+// is implemented by plugins maintaining a routing table, such as discovery.
+type Router interface {
+	// Closest returns up to count peers closest to a target known to this node.
+	Closest(target peer.ID, count int) []peer.ID
+
+	// Lookup returns up to count peers closest to a target found by iteratively
+	// querying other peers.
+	Lookup(net *Network, target peer.ID, count int) []peer.ID
+}
+
+// Router returns the first registered plugin which implements Router. The second
+// returning parameter is false should there be none.
+func (n *Network) Router() (router Router, exists bool) {
+	n.Plugins.Each(func(plugin PluginInterface) {
+		if r, ok := plugin.(Router); ok && !exists {
+			router, exists = r, true
+		}
+	})
+
+	return
+}
+
+// KeyID returns the position of a key within the ID space of the network, which is
+// the hash of the key truncated to the length of the network's public keys.
+func (n *Network) KeyID(key []byte) peer.ID {
+	hash, err := blake2b.New(len(n.ID.PublicKey), nil)
+	if err != nil {
+		// Public keys longer than 64 bytes are hashed down to 64 bytes.
+		hash, _ = blake2b.New512(nil)
+	}
+	hash.Write(key)
+
+	return peer.ID{PublicKey: hash.Sum(nil)}
+}
+
+// RequestClosest requests the peers closest to a key known to this node, hedged as
+// per RequestAny, and returns the first successful response. Should none respond,
+// the peers closest to the key are looked up through the network, and those not yet
+// tried are requested instead.
+func (n *Network) RequestClosest(ctx context.Context, key []byte, req *rpc.Request) (proto.Message, error) {
+	router, exists := n.Router()
+	if !exists {
+		return nil, errors.New("requesting by key requires a plugin which implements network.Router")
+	}
+
+	target := n.KeyID(key)
+	tried := make(map[string]struct{})
+
+	paths := []func() []peer.ID{
+		func() []peer.ID { return router.Closest(target, DefaultRequestClosestPeers) },
+		func() []peer.ID { return router.Lookup(n, target, DefaultRequestClosestPeers) },
+	}
+
+	err := errors.Wrapf(ErrPeerNotFound, "no peers close to key %x", key)
+
+	for _, path := range paths {
+		var addresses []string
+
+		for _, id := range path() {
+			if id.Equals(n.ID) {
+				continue
+			}
+
+			if _, seen := tried[id.PublicKeyHex()]; seen {
+				continue
+			}
+
+			tried[id.PublicKeyHex()] = struct{}{}
+			addresses = append(addresses, id.Address)
+		}
+
+		if len(addresses) == 0 {
+			continue
+		}
+
+		var response proto.Message
+
+		response, err = n.RequestAny(ctx, req, AddressSelector(addresses))
+		if err == nil {
+			return response, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,61 @@
+package network_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestRequestClosest(t *testing.T) {
+	requester := buildNode(t, 13203, new(discovery.Plugin))
+	alice := buildNode(t, 13204, new(discovery.Plugin))
+	bob := buildNode(t, 13205, new(discovery.Plugin))
+
+	defer requester.Close()
+	defer alice.Close()
+	defer bob.Close()
+
+	if err := requester.Bootstrap(alice.Address, bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.Ping{})
+	request.SetTimeout(3 * time.Second)
+
+	response, err := requester.RequestClosest(context.Background(), []byte("key"), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := response.(*protobuf.Pong); !ok {
+		t.Fatalf("expected pong, got %v", response)
+	}
+}
+
+func TestRequestClosestWithoutRouter(t *testing.T) {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", 13206))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.Ping{})
+
+	if _, err := node.RequestClosest(context.Background(), []byte("key"), request); err == nil {
+		t.Fatal("expected requesting by key without a router to fail")
+	}
+}
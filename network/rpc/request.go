@@ -10,6 +10,9 @@ import (
 type Request struct {
 	Message proto.Message
 	Timeout time.Duration
+
+	// Delay between hedging the request to successive peers. Zero sends to all peers at once.
+	Stagger time.Duration
 }
 
 // SetMessage sets the message body contents of the request.
@@ -21,3 +24,8 @@ func (r *Request) SetMessage(message proto.Message) {
 func (r *Request) SetTimeout(timeout time.Duration) {
 	r.Timeout = timeout
 }
+
+// SetStagger sets the delay between hedging the request to successive peers.
+func (r *Request) SetStagger(stagger time.Duration) {
+	r.Stagger = stagger
+}
@@ -0,0 +1,44 @@
+package network
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/schedule"
+)
+
+// Scheduler returns the scheduler of the network, which is stopped once the network
+// is closed. Plugins should schedule periodic work onto it rather than spawning
+// goroutines which sleep.
+func (n *Network) Scheduler() *schedule.Scheduler {
+	n.schedulerOnce.Do(func() {
+		n.scheduler = schedule.NewSpawning(n.clock(), func(fn func()) { n.spawn(GoroutineTask, fn) })
+		n.spawn(GoroutineScheduler, func() { n.scheduler.Run(n.Kill) })
+	})
+
+	return n.scheduler
+}
+
+// TellAfter asynchronously sends a message to a peer by its address once delay has
+// passed, without holding back other scheduled tasks should the peer be slow to dial. The returned task may be cancelled before the message is sent.
+func (n *Network) TellAfter(address string, message proto.Message, delay time.Duration) *schedule.Task {
+	return n.Scheduler().After(delay, func() {
+		client, err := n.Client(address)
+		if err == nil {
+			_, err = client.Tell(message)
+		}
+
+		if err != nil {
+			glog.Warningf("Failed to send scheduled message to peer %s [err=%s]", address, err)
+		}
+	})
+}
+
+// BroadcastAt asynchronously broadcasts a message to all peers at a given time. The
+// returned task may be cancelled before the message is broadcasted.
+func (n *Network) BroadcastAt(message proto.Message, at time.Time) *schedule.Task {
+	return n.Scheduler().At(at, func() {
+		n.Broadcast(message)
+	})
+}
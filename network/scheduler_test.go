@@ -0,0 +1,52 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestScheduledSendsPastBlockingTask(t *testing.T) {
+	alice := buildMemoryNodeWithKeys(t, 375, ed25519.RandomKeyPair())
+	bob := buildMemoryNodeWithKeys(t, 376, ed25519.RandomKeyPair())
+
+	defer alice.Close()
+	defer bob.Close()
+
+	alice.Bootstrap(bob.Address)
+	waitForPeer(t, bob, alice.Address)
+
+	datagrams, closeTap := bob.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeTap()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	blocked := make(chan struct{})
+
+	alice.Scheduler().After(0, func() {
+		close(blocked)
+		<-release
+	})
+
+	select {
+	case <-blocked:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the blocking task to run")
+	}
+
+	// Scheduled sends are not held back by a task which blocks.
+	alice.TellAfter(bob.Address, &protobuf.Datagram{Data: []byte("later")}, 10*time.Millisecond)
+	alice.BroadcastAt(&protobuf.Datagram{Data: []byte("broadcast")}, time.Now().Add(10*time.Millisecond))
+
+	for received := 0; received < 2; received++ {
+		select {
+		case <-datagrams:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for scheduled message %d past a blocking task", received+1)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package network
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Selector picks peers from the network for a message or request to be disseminated to.
+type Selector interface {
+	Select(net *Network) []*PeerClient
+}
+
+// Select implements Selector.
+func (f PeerSelector) Select(net *Network) []*PeerClient {
+	return f(net)
+}
+
+// RandomSelector selects K peers uniformly at random.
+type RandomSelector struct {
+	K int
+}
+
+// Select implements Selector.
+func (s RandomSelector) Select(net *Network) []*PeerClient {
+	var clients []*PeerClient
+
+	net.Peers.Range(func(key, value interface{}) bool {
+		clients = append(clients, value.(*PeerClient))
+
+		// Limit total amount of clients in case we have a lot of peers.
+		return len(clients) <= s.K*3
+	})
+
+	// Flip a coin and shuffle :).
+	rand.Shuffle(len(clients), func(i, j int) {
+		clients[i], clients[j] = clients[j], clients[i]
+	})
+
+	if len(clients) > s.K {
+		clients = clients[:s.K]
+	}
+
+	return clients
+}
+
+// AddressSelector selects peers by their addresses in order, dialing those not yet
+// connected to. Addresses which fail to be dialed are skipped.
+type AddressSelector []string
+
+// Select implements Selector.
+func (s AddressSelector) Select(net *Network) (clients []*PeerClient) {
+	for _, address := range s {
+		client, err := net.Client(address)
+		if err != nil {
+			continue
+		}
+		clients = append(clients, client)
+	}
+
+	return
+}
+
+// LatencySelector selects the K connected peers with the lowest round-trip times
+// measured by Ping. Peers never measured are selected last, at random.
+type LatencySelector struct {
+	K int
+}
+
+// Select implements Selector.
+func (s LatencySelector) Select(net *Network) []*PeerClient {
+	var clients []*PeerClient
+	rtts := make(map[*PeerClient]time.Duration)
+
+	net.Peers.Range(func(key, value interface{}) bool {
+		client := value.(*PeerClient)
+
+		clients = append(clients, client)
+		rtts[client] = client.RTT()
+
+		return true
+	})
+
+	// Shuffle first so that peers with equal or unmeasured round-trip times are
+	// selected at random.
+	rand.Shuffle(len(clients), func(i, j int) {
+		clients[i], clients[j] = clients[j], clients[i]
+	})
+
+	sort.SliceStable(clients, func(i, j int) bool {
+		a, b := rtts[clients[i]], rtts[clients[j]]
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
+
+	if len(clients) > s.K {
+		clients = clients[:s.K]
+	}
+
+	return clients
+}
+
+// BandwidthSelector selects the K connected peers with the highest bandwidth probed
+// of their links, such as to pull large amounts of data from. Peers whose links were
+// never probed are selected last, at random.
+type BandwidthSelector struct {
+	K int
+}
+
+// Select implements Selector.
+func (s BandwidthSelector) Select(net *Network) []*PeerClient {
+	var clients []*PeerClient
+	bandwidths := make(map[*PeerClient]float64)
+
+	net.Peers.Range(func(key, value interface{}) bool {
+		client := value.(*PeerClient)
+
+		clients = append(clients, client)
+		if probe, ok := client.LinkProbe(); ok {
+			bandwidths[client] = probe.Bandwidth
+		}
+
+		return true
+	})
+
+	rand.Shuffle(len(clients), func(i, j int) {
+		clients[i], clients[j] = clients[j], clients[i]
+	})
+
+	sort.SliceStable(clients, func(i, j int) bool {
+		return bandwidths[clients[i]] > bandwidths[clients[j]]
+	})
+
+	if len(clients) > s.K {
+		clients = clients[:s.K]
+	}
+
+	return clients
+}
+
+// WeightedSelector selects K peers at random, with each peer's likelihood of being
+// selected being proportional to its weight. Peers with a non-positive weight are
+// never selected.
+type WeightedSelector struct {
+	K      int
+	Weight func(client *PeerClient) float64
+}
+
+// Select implements Selector.
+func (s WeightedSelector) Select(net *Network) []*PeerClient {
+	type candidate struct {
+		client *PeerClient
+		key    float64
+	}
+
+	var candidates []candidate
+
+	net.Peers.Range(func(key, value interface{}) bool {
+		client := value.(*PeerClient)
+
+		weight := s.Weight(client)
+		if weight <= 0 {
+			return true
+		}
+
+		// Weighted random sampling without replacement (Efraimidis-Spirakis).
+		candidates = append(candidates, candidate{client: client, key: math.Pow(rand.Float64(), 1/weight)})
+
+		return true
+	})
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].key > candidates[j].key
+	})
+
+	if len(candidates) > s.K {
+		candidates = candidates[:s.K]
+	}
+
+	clients := make([]*PeerClient, len(candidates))
+	for i, candidate := range candidates {
+		clients[i] = candidate.client
+	}
+
+	return clients
+}
+
+// MeshSelector selects all connected peers which are members of a mesh, such as
+// the set of peers an application gossips a particular topic to.
+type MeshSelector struct {
+	mutex   sync.RWMutex
+	members map[string]struct{}
+}
+
+// NewMeshSelector creates a new mesh selector with no members.
+func NewMeshSelector() *MeshSelector {
+	return &MeshSelector{members: make(map[string]struct{})}
+}
+
+// Join adds a peer address to the mesh.
+func (s *MeshSelector) Join(address string) {
+	s.mutex.Lock()
+	s.members[address] = struct{}{}
+	s.mutex.Unlock()
+}
+
+// Leave removes a peer address from the mesh.
+func (s *MeshSelector) Leave(address string) {
+	s.mutex.Lock()
+	delete(s.members, address)
+	s.mutex.Unlock()
+}
+
+// Members returns the addresses of all peers in the mesh.
+func (s *MeshSelector) Members() (addresses []string) {
+	s.mutex.RLock()
+	for address := range s.members {
+		addresses = append(addresses, address)
+	}
+	s.mutex.RUnlock()
+
+	return
+}
+
+// Select implements Selector.
+func (s *MeshSelector) Select(net *Network) (clients []*PeerClient) {
+	for _, address := range s.Members() {
+		if client, exists := net.Peers.Load(address); exists {
+			clients = append(clients, client.(*PeerClient))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,196 @@
+package network
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/peerstore"
+)
+
+func createSelectorTestNetwork(t *testing.T, numPeers int) *Network {
+	net := &Network{Peers: new(sync.Map)}
+
+	for i := 0; i < numPeers; i++ {
+		address := fmt.Sprintf("tcp://127.0.0.1:%d", 3000+i)
+
+		client, err := createPeerClient(net, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		net.Peers.Store(address, client)
+	}
+
+	return net
+}
+
+func TestRandomSelector(t *testing.T) {
+	net := createSelectorTestNetwork(t, 10)
+
+	clients := RandomSelector{K: 3}.Select(net)
+	if len(clients) != 3 {
+		t.Fatalf("expected 3 peers to be selected, got %d", len(clients))
+	}
+
+	visited := make(map[string]struct{})
+	for _, client := range clients {
+		if _, seen := visited[client.Address()]; seen {
+			t.Fatalf("peer %s was selected twice", client.Address())
+		}
+		visited[client.Address()] = struct{}{}
+	}
+
+	if clients := (RandomSelector{K: 20}).Select(net); len(clients) != 10 {
+		t.Fatalf("expected all 10 peers to be selected, got %d", len(clients))
+	}
+}
+
+func TestWeightedSelector(t *testing.T) {
+	net := createSelectorTestNetwork(t, 10)
+
+	favored := "tcp://127.0.0.1:3004"
+
+	selector := WeightedSelector{
+		K: 1,
+		Weight: func(client *PeerClient) float64 {
+			if client.Address() == favored {
+				return 1
+			}
+			return 0
+		},
+	}
+
+	for i := 0; i < 10; i++ {
+		clients := selector.Select(net)
+		if len(clients) != 1 || clients[0].Address() != favored {
+			t.Fatalf("expected only %s to be selected, got %v", favored, clients)
+		}
+	}
+}
+
+func TestMeshSelector(t *testing.T) {
+	net := createSelectorTestNetwork(t, 10)
+
+	mesh := NewMeshSelector()
+	mesh.Join("tcp://127.0.0.1:3001")
+	mesh.Join("tcp://127.0.0.1:3002")
+	mesh.Join("tcp://127.0.0.1:4000") // Not connected.
+
+	if clients := mesh.Select(net); len(clients) != 2 {
+		t.Fatalf("expected 2 connected mesh members to be selected, got %d", len(clients))
+	}
+
+	mesh.Leave("tcp://127.0.0.1:3001")
+
+	if clients := mesh.Select(net); len(clients) != 1 || clients[0].Address() != "tcp://127.0.0.1:3002" {
+		t.Fatalf("expected only tcp://127.0.0.1:3002 to be selected, got %v", clients)
+	}
+}
+
+func TestLatencySelector(t *testing.T) {
+	net := createSelectorTestNetwork(t, 10)
+	net.Peerstore = peerstore.New(time.Hour, nil)
+
+	rtts := map[string]time.Duration{
+		"tcp://127.0.0.1:3002": 30 * time.Millisecond,
+		"tcp://127.0.0.1:3005": 10 * time.Millisecond,
+		"tcp://127.0.0.1:3007": 20 * time.Millisecond,
+	}
+
+	for address, rtt := range rtts {
+		net.Peerstore.Connected(address)
+		net.Peerstore.RecordRTT(address, rtt)
+	}
+
+	clients := LatencySelector{K: 4}.Select(net)
+	if len(clients) != 4 {
+		t.Fatalf("expected 4 peers to be selected, got %d", len(clients))
+	}
+
+	expected := []string{"tcp://127.0.0.1:3005", "tcp://127.0.0.1:3007", "tcp://127.0.0.1:3002"}
+	for i, address := range expected {
+		if clients[i].Address() != address {
+			t.Fatalf("expected peer %d to be %s, got %s", i, address, clients[i].Address())
+		}
+	}
+
+	if _, measured := rtts[clients[3].Address()]; measured {
+		t.Fatal("expected an unmeasured peer to be selected last")
+	}
+}
+
+func TestZoneSelector(t *testing.T) {
+	net := createSelectorTestNetwork(t, 9)
+	net.Zone = "a"
+
+	zones := []string{"a", "b", "c"}
+
+	i := 0
+	net.Peers.Range(func(key, value interface{}) bool {
+		value.(*PeerClient).observeZone(zones[i%len(zones)])
+		i++
+		return true
+	})
+
+	selected := make(map[string]int)
+	for _, client := range (ZoneSelector{K: 3, Preference: ZoneDiversity}).Select(net) {
+		selected[client.Zone()]++
+	}
+	if len(selected) != 3 {
+		t.Fatalf("expected a peer from each zone to be selected, got %v", selected)
+	}
+
+	for _, client := range (ZoneSelector{K: 2, Preference: ZoneDiversity}).Select(net) {
+		if client.Zone() == net.Zone {
+			t.Fatalf("expected peers in other zones to be selected first, got %s", client.Address())
+		}
+	}
+
+	clients := ZoneSelector{K: 3, Preference: ZoneLocality}.Select(net)
+	if len(clients) != 3 {
+		t.Fatalf("expected 3 peers to be selected, got %d", len(clients))
+	}
+	for _, client := range clients {
+		if client.Zone() != net.Zone {
+			t.Fatalf("expected peers in our own zone to be selected first, got %s in zone %s", client.Address(), client.Zone())
+		}
+	}
+
+	if clients := (ZoneSelector{K: 20, Preference: ZoneLocality}).Select(net); len(clients) != 9 {
+		t.Fatalf("expected all 9 peers to be selected, got %d", len(clients))
+	}
+}
+
+func TestSelectByZone(t *testing.T) {
+	net := createSelectorTestNetwork(t, 6)
+
+	var candidates []*PeerClient
+
+	i := 0
+	net.Peers.Range(func(key, value interface{}) bool {
+		client := value.(*PeerClient)
+		client.observeZone([]string{"a", "b"}[i%2])
+		candidates = append(candidates, client)
+		i++
+		return true
+	})
+
+	if selected := net.SelectByZone(candidates, 4); len(selected) != 4 {
+		t.Fatalf("expected 4 peers to be selected while unlabelled, got %d", len(selected))
+	}
+
+	net.Zone = "a"
+	net.ZonePreference = ZoneLocality
+
+	for _, client := range net.SelectByZone(candidates[:4], 2) {
+		if client.Zone() != net.Zone {
+			t.Fatalf("expected peers in our own zone to be selected first, got %s in zone %s", client.Address(), client.Zone())
+		}
+	}
+
+	if len(candidates) != 6 {
+		t.Fatalf("expected candidates to be left as is, got %d", len(candidates))
+	}
+}
@@ -0,0 +1,171 @@
+package network
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// Domains the library signs within, such that signatures produced within one may not
+// be replayed within another.
+const (
+	// DomainMessage is the domain envelopes of messages are signed within.
+	DomainMessage = "noise/message"
+
+	// DomainHandshake is the domain envelopes of messages exchanged to handshake with
+	// and identify peers are signed within.
+	DomainHandshake = "noise/handshake"
+
+	// DomainBatch is the domain the merkle roots of batches of messages are signed within.
+	DomainBatch = "noise/batch"
+
+	// DomainRecord is the domain storage records are signed within.
+	DomainRecord = "noise/record"
+
+	// DomainPublication is the domain pubsub publications are signed within by their
+	// origins.
+	DomainPublication = "noise/publication"
+)
+
+// DomainEnvelopeVersion is the lowest envelope version whose signatures are
+// domain-separated, and which carries signed message IDs.
+const DomainEnvelopeVersion uint32 = 4
+
+// LamportEnvelopeVersion is the lowest envelope version whose signatures cover the
+// Lamport timestamps of messages, such that they may not be tampered with to reorder
+// messages.
+const LamportEnvelopeVersion uint32 = 5
+
+// EnvelopeIDSize is the size of the random IDs assigned to messages.
+const EnvelopeIDSize = 16
+
+// handshakeMessages are the names of the types of messages exchanged to handshake with
+// and identify peers.
+var handshakeMessages = map[string]struct{}{
+	proto.MessageName(new(protobuf.Ping)):              {},
+	proto.MessageName(new(protobuf.Pong)):              {},
+	proto.MessageName(new(protobuf.IdentityChallenge)): {},
+	proto.MessageName(new(protobuf.IdentityResponse)):  {},
+	proto.MessageName(new(protobuf.SessionTicket)):     {},
+	proto.MessageName(new(protobuf.ResumeSession)):     {},
+}
+
+// EnvelopeDomain returns the domain envelopes of messages of a type are signed within.
+// The type URL it is derived from is signed alongside the payload, such that a message
+// may not be passed off as one of a type of another domain.
+func EnvelopeDomain(raw *any.Any) string {
+	name, err := ptypes.AnyMessageName(raw)
+	if err != nil {
+		return DomainMessage
+	}
+
+	if _, handshake := handshakeMessages[name]; handshake {
+		return DomainHandshake
+	}
+
+	return DomainMessage
+}
+
+// envelopePayload returns what is signed of the envelope of a message sent by a sender
+// under an ID at a Lamport timestamp. Should the envelope's version separate domains,
+// the message's ID and type URL are signed alongside its payload, tagged with the
+// domain of its type.
+func (n *Network) envelopePayload(sender *protobuf.ID, id []byte, raw *any.Any, lamport uint64, version uint32) []byte {
+	if version < DomainEnvelopeVersion {
+		return n.signedMessage(sender, raw.Value)
+	}
+
+	return crypto.DomainSeparate(EnvelopeDomain(raw), n.signedMessage(sender, envelopeBody(id, raw, lamport, version)))
+}
+
+// envelopeBody serializes the ID, type URL and payload of a message, the ID and type
+// URL each prefixed with its length as a little-endian uint32. Should the envelope's
+// version cover Lamport timestamps, the message's Lamport timestamp is serialized as
+// a little-endian uint64 ahead of its payload.
+func envelopeBody(id []byte, raw *any.Any, lamport uint64, version uint32) []byte {
+	const UINT32_SIZE = 4
+	const UINT64_SIZE = 8
+
+	size := UINT32_SIZE + len(id) + UINT32_SIZE + len(raw.TypeUrl) + len(raw.Value)
+	if version >= LamportEnvelopeVersion {
+		size += UINT64_SIZE
+	}
+
+	body := make([]byte, size)
+	pos := 0
+
+	binary.LittleEndian.PutUint32(body[pos:], uint32(len(id)))
+	pos += UINT32_SIZE
+
+	pos += copy(body[pos:], id)
+
+	binary.LittleEndian.PutUint32(body[pos:], uint32(len(raw.TypeUrl)))
+	pos += UINT32_SIZE
+
+	pos += copy(body[pos:], raw.TypeUrl)
+
+	if version >= LamportEnvelopeVersion {
+		binary.LittleEndian.PutUint64(body[pos:], lamport)
+		pos += UINT64_SIZE
+	}
+
+	copy(body[pos:], raw.Value)
+
+	return body
+}
+
+// newEnvelopeID draws a random ID for a message to be sent from the network's Entropy.
+func (n *Network) newEnvelopeID() ([]byte, error) {
+	entropy := n.Entropy
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+
+	id := make([]byte, EnvelopeIDSize)
+	if _, err := io.ReadFull(entropy, id); err != nil {
+		return nil, errors.Wrap(err, "failed to draw message ID")
+	}
+
+	return id, nil
+}
+
+// isOwnMessage returns true should a message have been sent by the node, and hence be
+// able to be signed anew by it.
+func (n *Network) isOwnMessage(msg *protobuf.Message) bool {
+	return msg.Sender != nil && bytes.Equal(msg.Sender.PublicKey, n.ID.PublicKey)
+}
+
+// EnvelopeSignFunc signs the payload of an envelope, or of anything else signed within
+// a domain such as a storage record, on behalf of the node, returning a signature of
+// any format its peers' EnvelopeVerifyFunc understands.
+type EnvelopeSignFunc func(payload []byte) ([]byte, error)
+
+// EnvelopeVerifyFunc returns true should a signature by a sender over the payload of
+// an envelope, or of anything else signed within a domain, be valid.
+type EnvelopeVerifyFunc func(sender peer.ID, payload []byte, signature []byte) bool
+
+// verifyEnvelope verifies the signature of a sender over the payload of an envelope
+// with the network's VerifyEnvelope, or with its signature policy should it be nil.
+func (n *Network) verifyEnvelope(sender *protobuf.ID, payload []byte, signature []byte) bool {
+	if n.VerifyEnvelope != nil {
+		return n.VerifyEnvelope(peer.ID(*sender), payload, signature)
+	}
+
+	return crypto.Verify(n.SignaturePolicy, n.HashPolicy, sender.PublicKey, payload, signature)
+}
+
+// Verify returns true should a signature by a public key over a message, such as one
+// signed with Sign by another node, be valid. Verified with the network's
+// VerifyEnvelope, or with its signature policy should it be nil.
+func (n *Network) Verify(publicKey []byte, message []byte, signature []byte) bool {
+	return n.verifyEnvelope(&protobuf.ID{PublicKey: publicKey}, message, signature)
+}
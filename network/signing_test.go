@@ -0,0 +1,92 @@
+package network_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/peer"
+)
+
+// groupKey stands in for an external signing scheme, such as a threshold signature
+// scheme shared by a group of nodes.
+var groupKey = []byte("group key")
+
+func groupSignature(publicKey []byte, payload []byte) []byte {
+	mac := hmac.New(sha256.New, groupKey)
+	mac.Write(publicKey)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func buildDetachedNode(t *testing.T, port uint16) *network.Network {
+	publicKey := ed25519.RandomKeyPair().PublicKey
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetEnvelopeSigning(publicKey, func(payload []byte) ([]byte, error) {
+		return groupSignature(publicKey, payload), nil
+	}, func(sender peer.ID, payload []byte, signature []byte) bool {
+		return hmac.Equal(signature, groupSignature(sender.PublicKey, payload))
+	})
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+	builder.AddPlugin(new(discovery.Plugin))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestEnvelopeSigning(t *testing.T) {
+	alice := buildDetachedNode(t, 369)
+	bob := buildDetachedNode(t, 370)
+	carol := buildTicketingNode(t, 371)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	if len(alice.Keys.PrivateKey) != 0 {
+		t.Fatal("expected the network to hold no private key")
+	}
+
+	// All else the network signs is signed with the callbacks as well.
+	signature, err := alice.Sign([]byte("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bob.Verify(alice.ID.PublicKey, []byte("payload"), signature) {
+		t.Fatal("expected a payload signed with the callback to be verified")
+	}
+
+	if err := alice.Bootstrap(bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, "alice and bob to verify each other's envelopes", func() bool {
+		return alice.NeighborFingerprints()[bob.Address] != "" && bob.NeighborFingerprints()[alice.Address] != ""
+	})
+
+	// Carol signs her envelopes with her key pair, which alice does not accept.
+	if err := carol.Bootstrap(alice.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if fingerprint := alice.NeighborFingerprints()[carol.Address]; fingerprint != "" {
+		t.Fatal("expected envelopes signed with a key pair to fail verification")
+	}
+}
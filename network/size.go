@@ -0,0 +1,35 @@
+package network
+
+import "math"
+
+// SizeEstimator estimates the number of nodes within the network.
+type SizeEstimator interface {
+	// EstimateSize returns the estimated number of nodes, ourselves included.
+	EstimateSize() int
+}
+
+// EstimateSize returns the number of nodes within the network estimated by the
+// network's size estimator, ourselves included, falling back to the number of peers
+// connected to us.
+func (n *Network) EstimateSize() int {
+	connected := 1
+	n.Peers.Range(func(key, value interface{}) bool {
+		connected++
+		return true
+	})
+
+	if n.SizeEstimator != nil {
+		if size := n.SizeEstimator.EstimateSize(); size > connected {
+			return size
+		}
+	}
+
+	return connected
+}
+
+// Fanout returns the number of peers gossip is to be sent to for it to reach all
+// nodes with high probability, being proportional to the logarithm of the estimated
+// size of the network.
+func (n *Network) Fanout() int {
+	return int(math.Ceil(math.Log(float64(n.EstimateSize())))) + 1
+}
@@ -0,0 +1,30 @@
+package network
+
+import (
+	"sync"
+	"testing"
+)
+
+type fixedSize int
+
+func (s fixedSize) EstimateSize() int { return int(s) }
+
+func TestFanout(t *testing.T) {
+	n := &Network{Peers: new(sync.Map)}
+
+	if fanout := n.Fanout(); fanout != 1 {
+		t.Fatalf("expected a lone node to have a fan-out of 1, got %d", fanout)
+	}
+
+	n.SizeEstimator = fixedSize(1000)
+
+	if fanout := n.Fanout(); fanout != 8 {
+		t.Fatalf("expected a network of 1000 nodes to have a fan-out of 8, got %d", fanout)
+	}
+
+	n.SizeEstimator = fixedSize(10000)
+
+	if fanout := n.Fanout(); fanout != 11 {
+		t.Fatalf("expected a network of 10000 nodes to have a fan-out of 11, got %d", fanout)
+	}
+}
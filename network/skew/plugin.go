@@ -0,0 +1,216 @@
+package skew
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/perlin-network/noise/types/stats"
+)
+
+const (
+	// DefaultThreshold is the default skew beyond which a peers clock is warned about.
+	DefaultThreshold = 1 * time.Second
+
+	// smoothing is the weight given to new samples in the moving average of a peers skew.
+	smoothing = 0.25
+)
+
+// Estimate is the estimated offset of a peers clock relative to ours.
+type Estimate struct {
+	// Skew is positive should the peers clock be ahead of ours.
+	Skew time.Duration
+
+	// RoundTrip is the round-trip time of the most recent sample.
+	RoundTrip time.Duration
+
+	Samples int
+	Updated time.Time
+}
+
+// Plugin estimates the clock skew of peers from the timestamps carried by pings and
+// pongs, and warns when a peers skew exceeds a threshold. It estimates network time
+// should it be registered with Register.
+type Plugin struct {
+	*network.Plugin
+
+	// Threshold beyond which a peers skew is warned about.
+	Threshold time.Duration
+
+	// OnSkewExceeded is called with a peers address and estimated skew should it
+	// exceed the threshold. Nil if no callback is desired.
+	OnSkewExceeded func(address string, skew time.Duration)
+
+	// MinPeers is the number of peers whose skew must be estimated before the
+	// network time offset is estimated. Zero if a single peer suffices.
+	MinPeers int
+
+	// MaxAge is how long a peers estimate is considered for the network time offset
+	// after it was last updated. Zero if estimates never go stale.
+	MaxAge time.Duration
+
+	net   *network.Network
+	clock clock.Clock
+
+	// mutex guards the network and the estimates attached to its peers, as network
+	// time may be estimated while the plugin is being started.
+	mutex sync.RWMutex
+}
+
+var (
+	// PluginID to reference skew plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// namespace namespaces the estimates the plugin attaches to peers.
+const namespace network.Namespace = "skew"
+
+// New creates a skew plugin warning about peers whose clocks are skewed by more than
+// the default threshold.
+func New() *Plugin {
+	return &Plugin{Threshold: DefaultThreshold}
+}
+
+// Register registers a skew plugin onto a network builder as the network's time
+// estimator, and returns it for further configuration.
+func Register(builder *builders.NetworkBuilder) (*Plugin, error) {
+	plugin := New()
+
+	if err := builder.AddPlugin(plugin); err != nil {
+		return nil, err
+	}
+
+	builder.SetTimeEstimator(plugin)
+
+	return plugin, nil
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.net = net
+	p.clock = clock.Or(net.Clock)
+}
+
+// Receive implements the plugin callback
+func (p *Plugin) Receive(ctx *network.PluginContext) error {
+	pong, ok := ctx.Message().(*protobuf.Pong)
+
+	// Peers which do not timestamp their pongs are ignored.
+	if !ok || pong.PingTimestamp == 0 || pong.Timestamp == 0 {
+		return nil
+	}
+
+	p.Observe(ctx.Client(), time.Unix(0, pong.PingTimestamp), time.Unix(0, pong.Timestamp), clock.Or(ctx.Network().Clock).Now())
+
+	return nil
+}
+
+// Observe records a sample of a peers skew given the time a ping was sent, the peers
+// time upon responding, and the time its response was received.
+func (p *Plugin) Observe(client *network.PeerClient, sent time.Time, remote time.Time, received time.Time) {
+	roundTrip := received.Sub(sent)
+	if roundTrip < 0 {
+		return
+	}
+
+	// Assume the peer responded halfway through the round trip.
+	sample := remote.Sub(sent.Add(roundTrip / 2))
+
+	p.mutex.Lock()
+
+	estimate := client.Values().GetOrSet(namespace, "estimate", func() interface{} {
+		return &Estimate{Skew: sample}
+	}).(*Estimate)
+
+	if estimate.Samples > 0 {
+		estimate.Skew = time.Duration(stats.Smooth(float64(estimate.Skew), float64(sample), smoothing))
+	}
+
+	estimate.RoundTrip = roundTrip
+	estimate.Samples++
+	estimate.Updated = received
+
+	skew := estimate.Skew
+
+	p.mutex.Unlock()
+
+	if p.Threshold > 0 && (skew > p.Threshold || skew < -p.Threshold) {
+		glog.Warningf("Clock of peer %s is skewed by %s.", client.Address(), skew)
+
+		if p.OnSkewExceeded != nil {
+			p.OnSkewExceeded(client.Address(), skew)
+		}
+	}
+}
+
+// Skew returns the estimated skew of a connected peer by its address.
+func (p *Plugin) Skew(address string) (Estimate, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.net == nil {
+		return Estimate{}, false
+	}
+
+	client, connected := p.net.Peers.Load(address)
+	if !connected {
+		return Estimate{}, false
+	}
+
+	estimate, exists := client.(*network.PeerClient).Values().Get(namespace, "estimate")
+	if !exists {
+		return Estimate{}, false
+	}
+
+	return *estimate.(*Estimate), true
+}
+
+// Offset estimates the offset of network time relative to our clock as the median of
+// the skews of peers, which tolerates a minority of peers with wildly wrong clocks.
+func (p *Plugin) Offset() (time.Duration, bool) {
+	var skews []time.Duration
+
+	p.mutex.RLock()
+
+	if p.net == nil {
+		p.mutex.RUnlock()
+		return 0, false
+	}
+
+	p.net.Peers.Range(func(key, value interface{}) bool {
+		estimate, exists := value.(*network.PeerClient).Values().Get(namespace, "estimate")
+		if !exists {
+			return true
+		}
+
+		if p.MaxAge > 0 && p.clock.Since(estimate.(*Estimate).Updated) > p.MaxAge {
+			return true
+		}
+
+		skews = append(skews, estimate.(*Estimate).Skew)
+		return true
+	})
+
+	p.mutex.RUnlock()
+
+	if len(skews) == 0 || len(skews) < p.MinPeers {
+		return 0, false
+	}
+
+	sort.Slice(skews, func(i, j int) bool { return skews[i] < skews[j] })
+
+	mid := len(skews) / 2
+	if len(skews)%2 == 0 {
+		return (skews[mid-1] + skews[mid]) / 2, true
+	}
+
+	return skews[mid], true
+}
@@ -0,0 +1,170 @@
+package skew
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+)
+
+func buildNode(t *testing.T, port uint16) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+// connect connects a node to peers built at ports, returning their clients and a
+// function closing the peers.
+func connect(t *testing.T, node *network.Network, ports ...uint16) ([]*network.PeerClient, func()) {
+	var clients []*network.PeerClient
+	var peers []*network.Network
+
+	closeAll := func() {
+		for _, peer := range peers {
+			peer.Close()
+		}
+	}
+
+	for _, port := range ports {
+		peer := buildNode(t, port)
+		peers = append(peers, peer)
+
+		client, err := node.Client(peer.Address)
+		if err != nil {
+			closeAll()
+			t.Fatal(err)
+		}
+
+		clients = append(clients, client)
+	}
+
+	return clients, closeAll
+}
+
+func TestObserve(t *testing.T) {
+	node := buildNode(t, 384)
+	defer node.Close()
+
+	// The plugin is started without being registered, such that it only observes the
+	// samples fed to it.
+	plugin := New()
+	plugin.Startup(node)
+
+	var exceeded time.Duration
+	plugin.OnSkewExceeded = func(address string, skew time.Duration) {
+		exceeded = skew
+	}
+
+	clients, closeAll := connect(t, node, 385, 386)
+	defer closeAll()
+
+	sent := time.Now()
+	received := sent.Add(100 * time.Millisecond)
+
+	// Peer responded 50ms into the round trip with its clock 2s ahead.
+	plugin.Observe(clients[0], sent, sent.Add(2*time.Second+50*time.Millisecond), received)
+
+	estimate, exists := plugin.Skew(clients[0].Address())
+	if !exists {
+		t.Fatal("expected skew to be estimated")
+	}
+
+	if estimate.Skew != 2*time.Second || estimate.RoundTrip != 100*time.Millisecond {
+		t.Fatalf("unexpected estimate %+v", estimate)
+	}
+
+	if exceeded != 2*time.Second {
+		t.Fatal("expected skew exceeding threshold to be reported")
+	}
+
+	exceeded = 0
+
+	plugin.Observe(clients[1], sent, sent.Add(10*time.Millisecond), received)
+
+	if exceeded != 0 {
+		t.Fatal("expected skew within threshold to not be reported")
+	}
+
+	// Estimates are dropped once peers disconnect.
+	clients[0].Close()
+
+	if _, exists := plugin.Skew(clients[0].Address()); exists {
+		t.Fatal("expected the skew of a disconnected peer to be forgotten")
+	}
+}
+
+func TestOffset(t *testing.T) {
+	node := buildNode(t, 387)
+	defer node.Close()
+
+	plugin := New()
+	plugin.MinPeers = 3
+	plugin.Startup(node)
+
+	clients, closeAll := connect(t, node, 388, 389, 390)
+	defer closeAll()
+
+	sent := time.Now()
+
+	plugin.Observe(clients[0], sent, sent.Add(1*time.Second), sent)
+	plugin.Observe(clients[1], sent, sent.Add(2*time.Second), sent)
+
+	if _, ok := plugin.Offset(); ok {
+		t.Fatal("expected no offset with too few peers")
+	}
+
+	// A peer with a wildly wrong clock should not sway the median.
+	plugin.Observe(clients[2], sent, sent.Add(1*time.Hour), sent)
+
+	offset, ok := plugin.Offset()
+	if !ok || offset != 2*time.Second {
+		t.Fatalf("expected offset of 2s, got %s", offset)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", 394))
+
+	plugin, err := Register(builder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if node.TimeEstimator != plugin {
+		t.Fatal("expected the registered plugin to estimate network time")
+	}
+
+	// Network time may be estimated while the plugin is being started.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		node.NetworkTime()
+	}()
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Close()
+
+	<-done
+}
@@ -0,0 +1,161 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultSlowDownDelay is how long peers are asked to pause sending to us once we
+	// are too loaded to handle their messages.
+	DefaultSlowDownDelay = 1 * time.Second
+
+	// MaxSlowDownDelay caps how long sending to a peer is paused for at its request,
+	// such that peers may not stall us indefinitely.
+	MaxSlowDownDelay = 10 * time.Second
+
+	// SlowDownBacklog caps how many messages are held back while sending to a peer is
+	// paused at its request. Messages beyond it are dropped.
+	SlowDownBacklog = 256
+)
+
+// slowdown tracks the pauses to sending between us and a peer.
+type slowdown struct {
+	mutex sync.Mutex
+
+	// until is when sending to the peer may resume at the peer's request.
+	until time.Time
+
+	// hinted is when the peer was last asked to pause, and hintedFor for how long.
+	hinted    time.Time
+	hintedFor time.Duration
+
+	// held are the messages held back until sending to the peer resumes, in the order
+	// they were sent, and flushing is true while they are waited on to be sent.
+	held     []*protobuf.Message
+	flushing bool
+}
+
+// SlowDown asks the peer to pause sending to us for a delay, should we be too loaded
+// to keep up with it.
+func (c *PeerClient) SlowDown(delay time.Duration) error {
+	now := c.Network.clock().Now()
+
+	c.slowdown.mutex.Lock()
+	c.slowdown.hinted, c.slowdown.hintedFor = now, delay
+	c.slowdown.mutex.Unlock()
+
+	_, err := c.Tell(&protobuf.SlowDown{Delay: int64(delay)})
+	return err
+}
+
+// SlowedDown returns how much longer sending to the peer is paused for at its request.
+func (c *PeerClient) SlowedDown() time.Duration {
+	c.slowdown.mutex.Lock()
+	defer c.slowdown.mutex.Unlock()
+
+	if remaining := c.slowdown.until.Sub(c.Network.clock().Now()); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// honorSlowDown pauses sending to the peer for a delay it requested, capped to
+// MaxSlowDownDelay.
+func (c *PeerClient) honorSlowDown(delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+
+	if delay > MaxSlowDownDelay {
+		delay = MaxSlowDownDelay
+	}
+
+	until := c.Network.clock().Now().Add(delay)
+
+	c.slowdown.mutex.Lock()
+	if until.After(c.slowdown.until) {
+		c.slowdown.until = until
+	}
+	c.slowdown.mutex.Unlock()
+}
+
+// hintSlowDown asks the peer to pause sending to us, unless it was asked to recently.
+func (c *PeerClient) hintSlowDown(delay time.Duration) error {
+	c.slowdown.mutex.Lock()
+	recent := c.Network.clock().Since(c.slowdown.hinted) < c.slowdown.hintedFor
+	c.slowdown.mutex.Unlock()
+
+	if recent {
+		return nil
+	}
+
+	return c.SlowDown(delay)
+}
+
+// holdBack holds back a message to a peer should sending to it be paused at its
+// request, or should messages still be held back from before, such that the message
+// is sent once sending resumes without blocking the caller. Returns false should the
+// message be sent right away instead.
+func (n *Network) holdBack(client *PeerClient, message *protobuf.Message) (bool, error) {
+	client.slowdown.mutex.Lock()
+	defer client.slowdown.mutex.Unlock()
+
+	if !client.slowdown.until.After(n.clock().Now()) && len(client.slowdown.held) == 0 {
+		return false, nil
+	}
+
+	if len(client.slowdown.held) >= SlowDownBacklog {
+		return true, errors.Wrapf(ErrQueueFull, "too many messages are held back from %s, which asked us to slow down", client.Address())
+	}
+
+	// Messages may be sent to several peers, and are hence copied to be sent later.
+	client.slowdown.held = append(client.slowdown.held, proto.Clone(message).(*protobuf.Message))
+
+	if !client.slowdown.flushing {
+		client.slowdown.flushing = true
+		n.spawn(GoroutineSlowDown, func() { n.flushHeldBack(client) })
+	}
+
+	return true, nil
+}
+
+// flushHeldBack sends the messages held back from a peer in order once sending to it
+// resumes, until no messages are held back, or until the peer or network is closed.
+func (n *Network) flushHeldBack(client *PeerClient) {
+	for {
+		if wait := client.SlowedDown(); wait > 0 {
+			select {
+			case <-n.clock().After(wait):
+			case <-client.done:
+				return
+			case <-n.Kill:
+				return
+			}
+			continue
+		}
+
+		client.slowdown.mutex.Lock()
+
+		if len(client.slowdown.held) == 0 {
+			client.slowdown.flushing = false
+			client.slowdown.mutex.Unlock()
+			return
+		}
+
+		message := client.slowdown.held[0]
+		client.slowdown.held[0] = nil
+		client.slowdown.held = client.slowdown.held[1:]
+
+		client.slowdown.mutex.Unlock()
+
+		if _, err := n.sendNow(client.Address(), message); err != nil {
+			glog.Warningf("Failed to send message held back from %s [err=%s]", client.Address(), err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func waitForSlowDown(t *testing.T, client *network.PeerClient) time.Duration {
+	deadline := time.Now().Add(3 * time.Second)
+
+	for {
+		if remaining := client.SlowedDown(); remaining > 0 {
+			return remaining
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the peer to ask us to slow down")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSlowDown(t *testing.T) {
+	alice := buildMemoryNodeWithKeys(t, 330, ed25519.RandomKeyPair())
+	bob := buildMemoryNodeWithKeys(t, 331, ed25519.RandomKeyPair())
+
+	defer alice.Close()
+	defer bob.Close()
+
+	client, err := bob.Client(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(&protobuf.Datagram{Data: []byte("hello")}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := waitForPeer(t, alice, bob.Address)
+
+	delay := 300 * time.Millisecond
+
+	if err := loaded.SlowDown(delay); err != nil {
+		t.Fatal(err)
+	}
+
+	datagrams, closeTap := alice.Tap(network.TapFilter{Types: []string{"protobuf.Datagram"}, Inbound: true})
+	defer closeTap()
+
+	remaining := waitForSlowDown(t, client)
+
+	start := time.Now()
+
+	if _, err := client.Tell(&protobuf.Datagram{Data: []byte("slowly")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Messages are held back rather than blocking whoever sends them.
+	if elapsed := time.Since(start); elapsed >= remaining/2 {
+		t.Fatalf("expected the message to be held back without blocking, but sending took %s", elapsed)
+	}
+
+	select {
+	case <-datagrams:
+		if elapsed := time.Since(start); elapsed < remaining-10*time.Millisecond {
+			t.Fatalf("expected sending to be paused for %s, but sent after %s", remaining, elapsed)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the held back message to be sent")
+	}
+
+	if client.SlowedDown() != 0 {
+		t.Fatal("expected sending to resume once the delay elapsed")
+	}
+
+	// Delays are capped, such that peers may not stall us indefinitely.
+	if err := loaded.SlowDown(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := waitForSlowDown(t, client); remaining > network.MaxSlowDownDelay {
+		t.Fatalf("expected delay to be capped to %s, got %s", network.MaxSlowDownDelay, remaining)
+	}
+}
+
+type blockingPlugin struct {
+	*network.Plugin
+
+	release chan struct{}
+}
+
+func (p *blockingPlugin) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Datagram); ok {
+		<-p.release
+	}
+	return nil
+}
+
+func TestSlowDownOnceLoaded(t *testing.T) {
+	blocking := &blockingPlugin{release: make(chan struct{})}
+	defer close(blocking.release)
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", 332))
+	builder.SetResourceLimits(network.ResourceLimits{MaxGoroutinesPerPeer: 1})
+	builder.AddPlugin(blocking)
+
+	alice, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := alice.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	bob := buildMemoryNodeWithKeys(t, 333, ed25519.RandomKeyPair())
+
+	defer alice.Close()
+	defer bob.Close()
+
+	client, err := bob.Client(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first datagram occupies the only goroutine alice handles bob's messages with.
+	for i := 0; i < 2; i++ {
+		if _, err := client.Tell(&protobuf.Datagram{Data: []byte("load")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if remaining := waitForSlowDown(t, client); remaining > network.DefaultSlowDownDelay {
+		t.Fatalf("expected to be asked to slow down for at most %s, got %s", network.DefaultSlowDownDelay, remaining)
+	}
+}
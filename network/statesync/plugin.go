@@ -0,0 +1,397 @@
+// Package statesync synchronizes application state from snapshots served by peers.
+// Applications provide snapshots through a SnapshotProvider and apply them through a
+// SnapshotApplier, and the plugin handles advertising snapshots, downloading them in
+// verified chunks from multiple peers, and retrying with other snapshots should one
+// fail to download or apply. Only snapshots which a quorum of peers agree on, or whose
+// roots are trusted, are synced. The transfer plugin must be registered.
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/network/transfer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultTimeout is how long peers are given to advertise their snapshots by default.
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultQuorum is the number of peers which must advertise a snapshot identically
+	// by default for it to be synced.
+	DefaultQuorum = 2
+)
+
+// Snapshot is a snapshot of application state at a height.
+type Snapshot struct {
+	Height uint64
+
+	// Format is the application-defined encoding of the snapshot.
+	Format uint32
+
+	// Metadata is application-defined data describing the snapshot.
+	Metadata []byte
+}
+
+// SnapshotProvider provides snapshots to be served to peers.
+type SnapshotProvider interface {
+	// Snapshots returns the snapshots available to be served.
+	Snapshots() []Snapshot
+
+	// OpenSnapshot returns the content of a snapshot, and its size in bytes.
+	OpenSnapshot(snapshot Snapshot) (io.ReaderAt, int64, error)
+}
+
+// SnapshotApplier applies snapshots downloaded from peers.
+type SnapshotApplier interface {
+	// ApplySnapshot restores application state from the content of a snapshot, which
+	// has been verified to match what peers advertised. Should it error, another
+	// snapshot is tried.
+	ApplySnapshot(snapshot Snapshot, content io.ReaderAt, size int64) error
+}
+
+// Plugin serves snapshots to peers, and syncs state from snapshots served by peers.
+type Plugin struct {
+	*network.Plugin
+
+	// Provider provides snapshots to serve. Nil if no snapshots are served.
+	Provider SnapshotProvider
+
+	// Applier applies snapshots synced from peers. Nil if state is never synced.
+	Applier SnapshotApplier
+
+	// Timeout is how long peers are given to advertise snapshots. Zero if the default.
+	Timeout time.Duration
+
+	// Quorum is the number of peers which must advertise a snapshot identically for it
+	// to be synced, such that a lone peer may not have us sync forged state by
+	// advertising the highest snapshot. DefaultQuorum if zero.
+	Quorum int
+
+	// TrustedRoots are the roots of snapshots which are synced however few peers
+	// advertise them, such as those configured out of band.
+	TrustedRoots [][]byte
+
+	net      *network.Network
+	transfer *transfer.Plugin
+
+	mutex  sync.Mutex
+	served map[string]*protobuf.SnapshotInfo
+}
+
+var (
+	// PluginID to reference state sync plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// New creates a state sync plugin serving snapshots from a provider, and applying
+// snapshots with an applier. Either may be nil.
+func New(provider SnapshotProvider, applier SnapshotApplier) *Plugin {
+	return &Plugin{
+		Provider: provider,
+		Applier:  applier,
+		served:   make(map[string]*protobuf.SnapshotInfo),
+	}
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.net = net
+
+	if err := net.RegisterMessages(new(protobuf.SnapshotsRequest), new(protobuf.SnapshotsResponse), new(protobuf.SnapshotInfo)); err != nil {
+		glog.Warningf("statesync: failed to register message types [err=%s]", err)
+	}
+
+	plugin, registered := net.Plugin(transfer.PluginID)
+	if !registered {
+		glog.Warning("statesync: transfer plugin is not registered; snapshots will not be served nor synced")
+		return
+	}
+
+	p.transfer = plugin.(*transfer.Plugin)
+}
+
+// Receive implements the plugin callback
+func (p *Plugin) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.SnapshotsRequest); !ok {
+		return nil
+	}
+
+	return ctx.Reply(&protobuf.SnapshotsResponse{Snapshots: p.advertise()})
+}
+
+func snapshotName(snapshot Snapshot) string {
+	return fmt.Sprintf("statesync/%d/%d", snapshot.Height, snapshot.Format)
+}
+
+// advertise serves all snapshots available from the provider through the transfer
+// plugin, and returns their advertisements.
+func (p *Plugin) advertise() (infos []*protobuf.SnapshotInfo) {
+	if p.Provider == nil || p.transfer == nil {
+		return nil
+	}
+
+	for _, snapshot := range p.Provider.Snapshots() {
+		info, err := p.serve(snapshot)
+		if err != nil {
+			glog.Warningf("statesync: failed to serve snapshot at height %d [err=%s]", snapshot.Height, err)
+			continue
+		}
+
+		infos = append(infos, info)
+	}
+
+	return
+}
+
+func (p *Plugin) serve(snapshot Snapshot) (*protobuf.SnapshotInfo, error) {
+	name := snapshotName(snapshot)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if info, served := p.served[name]; served {
+		return info, nil
+	}
+
+	reader, size, err := p.Provider.OpenSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := p.transfer.Serve(name, reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &protobuf.SnapshotInfo{
+		Height:   snapshot.Height,
+		Format:   snapshot.Format,
+		Metadata: snapshot.Metadata,
+		Name:     name,
+		Root:     transfer.Root(manifest),
+		Size:     manifest.Size,
+	}
+
+	p.served[name] = info
+
+	return info, nil
+}
+
+// candidate is a snapshot advertised identically by a set of peers.
+type candidate struct {
+	info      *protobuf.SnapshotInfo
+	addresses []string
+}
+
+func (c *candidate) snapshot() Snapshot {
+	return Snapshot{Height: c.info.Height, Format: c.info.Format, Metadata: c.info.Metadata}
+}
+
+// discover requests for the snapshots of peers, grouping snapshots advertised with the
+// same content, and sorting them by height and then by the number of peers serving them.
+func (p *Plugin) discover(ctx context.Context, addresses []string) []*candidate {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	candidates := make(map[string]*candidate)
+
+	for _, address := range addresses {
+		wg.Add(1)
+
+		go func(address string) {
+			defer wg.Done()
+
+			client, err := p.net.Client(address)
+			if err != nil {
+				return
+			}
+
+			request := new(rpc.Request)
+			request.SetMessage(new(protobuf.SnapshotsRequest))
+			request.SetTimeout(timeout)
+
+			response, err := client.RequestWithContext(ctx, request)
+			if err != nil {
+				glog.Warningf("statesync: failed to discover snapshots of %s [err=%s]", address, err)
+				return
+			}
+
+			snapshots, ok := response.(*protobuf.SnapshotsResponse)
+			if !ok {
+				return
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			for _, info := range snapshots.Snapshots {
+				key := fmt.Sprintf("%s/%x/%x", info.Name, info.Root, info.Metadata)
+
+				if candidates[key] == nil {
+					candidates[key] = &candidate{info: info}
+				}
+
+				// Peers advertising a snapshot several times count once towards a quorum.
+				if c := candidates[key]; len(c.addresses) == 0 || c.addresses[len(c.addresses)-1] != address {
+					c.addresses = append(c.addresses, address)
+				}
+			}
+		}(address)
+	}
+
+	wg.Wait()
+
+	sorted := make([]*candidate, 0, len(candidates))
+	for _, c := range candidates {
+		sorted = append(sorted, c)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].info.Height != sorted[j].info.Height {
+			return sorted[i].info.Height > sorted[j].info.Height
+		}
+		if len(sorted[i].addresses) != len(sorted[j].addresses) {
+			return len(sorted[i].addresses) > len(sorted[j].addresses)
+		}
+		return bytes.Compare(sorted[i].info.Root, sorted[j].info.Root) < 0
+	})
+
+	return sorted
+}
+
+// trusted returns true should a snapshot be advertised by a quorum of peers, or have a
+// trusted root.
+func (p *Plugin) trusted(c *candidate) bool {
+	for _, root := range p.TrustedRoots {
+		if bytes.Equal(root, c.info.Root) {
+			return true
+		}
+	}
+
+	quorum := p.Quorum
+	if quorum <= 0 {
+		quorum = DefaultQuorum
+	}
+
+	return len(c.addresses) >= quorum
+}
+
+// connectedPeers returns the addresses of all peers the network is connected to.
+func (p *Plugin) connectedPeers() (addresses []string) {
+	p.net.Peers.Range(func(key, value interface{}) bool {
+		addresses = append(addresses, key.(string))
+		return true
+	})
+	return
+}
+
+// Sync syncs state from the highest snapshot advertised by the most peers, out of
+// peers by their addresses or all connected peers should none be given. Only snapshots
+// advertised by a quorum of peers, or with trusted roots, are synced. Snapshots are
+// downloaded from all peers advertising them, and the next best snapshot is tried
+// should one fail to download or apply. It returns the snapshot which was applied.
+func (p *Plugin) Sync(ctx context.Context, addresses ...string) (Snapshot, error) {
+	if p.Applier == nil {
+		return Snapshot{}, errors.New("no snapshot applier is set")
+	}
+
+	if p.transfer == nil {
+		return Snapshot{}, errors.New("transfer plugin is not registered")
+	}
+
+	if len(addresses) == 0 {
+		addresses = p.connectedPeers()
+	}
+
+	candidates := p.discover(ctx, addresses)
+	if len(candidates) == 0 {
+		return Snapshot{}, errors.New("no peers advertised any snapshots")
+	}
+
+	tried := 0
+
+	for _, c := range candidates {
+		if !p.trusted(c) {
+			continue
+		}
+
+		tried++
+
+		if err := p.apply(ctx, c); err != nil {
+			if ctx.Err() != nil {
+				return Snapshot{}, ctx.Err()
+			}
+
+			glog.Warningf("statesync: failed to sync snapshot at height %d [err=%s]", c.info.Height, err)
+			continue
+		}
+
+		return c.snapshot(), nil
+	}
+
+	if tried == 0 {
+		return Snapshot{}, errors.Errorf("none of %d advertised snapshots were advertised by a quorum of peers nor trusted", len(candidates))
+	}
+
+	return Snapshot{}, errors.Errorf("failed to sync any of %d trusted snapshots", tried)
+}
+
+// fastest sorts addresses by the bandwidth probed of the links to their peers, fastest
+// first, such that the manifest is fetched from the fastest peer. Peers whose links
+// were never probed are sorted last.
+func (p *Plugin) fastest(addresses []string) []string {
+	bandwidths := make(map[string]float64)
+
+	for _, address := range addresses {
+		if client, exists := p.net.Peers.Load(address); exists {
+			if probe, ok := client.(*network.PeerClient).LinkProbe(); ok {
+				bandwidths[address] = probe.Bandwidth
+			}
+		}
+	}
+
+	sorted := append([]string(nil), addresses...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return bandwidths[sorted[i]] > bandwidths[sorted[j]]
+	})
+
+	return sorted
+}
+
+// apply downloads a snapshot into a temporary file and applies it.
+func (p *Plugin) apply(ctx context.Context, c *candidate) error {
+	file, err := ioutil.TempFile("", "noise-statesync")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	download := transfer.NewDownload(p.net, c.info.Name, file)
+	download.Root = c.info.Root
+
+	if err := download.Run(ctx, p.fastest(c.addresses)...); err != nil {
+		return err
+	}
+
+	return p.Applier.ApplySnapshot(c.snapshot(), file, int64(download.Manifest().Size))
+}
@@ -0,0 +1,122 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/transfer"
+	"github.com/pkg/errors"
+)
+
+// state is an in-memory application state of snapshots by their heights.
+type state struct {
+	snapshots map[uint64][]byte
+
+	// reject are the heights of snapshots which fail to apply.
+	reject map[uint64]bool
+
+	applied []byte
+}
+
+func (s *state) Snapshots() (snapshots []Snapshot) {
+	for height := range s.snapshots {
+		snapshots = append(snapshots, Snapshot{Height: height, Format: 1})
+	}
+	return
+}
+
+func (s *state) OpenSnapshot(snapshot Snapshot) (io.ReaderAt, int64, error) {
+	content := s.snapshots[snapshot.Height]
+	return bytes.NewReader(content), int64(len(content)), nil
+}
+
+func (s *state) ApplySnapshot(snapshot Snapshot, content io.ReaderAt, size int64) error {
+	if s.reject[snapshot.Height] {
+		return errors.New("rejected")
+	}
+
+	s.applied = make([]byte, size)
+	_, err := content.ReadAt(s.applied, 0)
+	return err
+}
+
+func buildNode(t *testing.T, port uint16, s *state) (*network.Network, *Plugin) {
+	plugin := New(s, s)
+
+	chunks := transfer.New()
+	chunks.ChunkSize = 1024
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", port))
+	builder.AddPlugin(new(discovery.Plugin))
+	builder.AddPlugin(chunks)
+	builder.AddPlugin(plugin)
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go node.Listen()
+	node.BlockUntilListening()
+
+	return node, plugin
+}
+
+func TestSync(t *testing.T) {
+	latest := bytes.Repeat([]byte("latest"), 1000)
+	older := bytes.Repeat([]byte("older"), 1000)
+
+	alice, _ := buildNode(t, 13150, &state{snapshots: map[uint64][]byte{10: latest}})
+	bob, bobSync := buildNode(t, 13151, &state{snapshots: map[uint64][]byte{10: latest, 5: older}})
+
+	// Mallory alone advertises the highest snapshot.
+	mallory, _ := buildNode(t, 13238, &state{snapshots: map[uint64][]byte{20: bytes.Repeat([]byte("forged"), 1000)}})
+
+	carolState := &state{reject: map[uint64]bool{}}
+	carol, carolSync := buildNode(t, 13152, carolState)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer mallory.Close()
+	defer carol.Close()
+
+	snapshot, err := carolSync.Sync(context.Background(), alice.Address, bob.Address, mallory.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot.Height != 10 || !bytes.Equal(carolState.applied, latest) {
+		t.Fatalf("expected latest snapshot advertised by a quorum to be applied, got height %d", snapshot.Height)
+	}
+
+	// Should the latest snapshot fail to apply, the older snapshot is only synced once
+	// its root is trusted, as it is advertised by bob alone.
+	carolState.reject[10] = true
+
+	if _, err := carolSync.Sync(context.Background(), alice.Address, bob.Address, mallory.Address); err == nil {
+		t.Fatal("expected snapshots advertised by a single peer not to be synced")
+	}
+
+	for _, info := range bobSync.advertise() {
+		if info.Height == 5 {
+			carolSync.TrustedRoots = append(carolSync.TrustedRoots, info.Root)
+		}
+	}
+
+	snapshot, err = carolSync.Sync(context.Background(), alice.Address, bob.Address, mallory.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot.Height != 5 || !bytes.Equal(carolState.applied, older) {
+		t.Fatalf("expected older snapshot to be applied, got height %d", snapshot.Height)
+	}
+}
@@ -0,0 +1,51 @@
+package network
+
+import (
+	"sync/atomic"
+
+	"github.com/perlin-network/noise/peerstore"
+)
+
+// MaxStatsPeers is the number of peers whose connection history is summarized by Stats
+// at most, such that peer stores persisting many peers are not loaded all at once.
+// Peerstore.Page is to be used to page through all of them.
+const MaxStatsPeers = 1024
+
+// Stats summarizes the peers of a network.
+type Stats struct {
+	// Number of peers currently connected.
+	ConnectedPeers int `json:"connected_peers"`
+
+	// Number of accepted connections dropped for not identifying themselves in time,
+	// which are likely to have been port scanners.
+	HandshakeTimeouts uint64 `json:"handshake_timeouts"`
+
+	// Number of accepted streams reaped for not delivering their message in time, such
+	// as by peers trickling bytes.
+	StreamTimeouts uint64 `json:"stream_timeouts"`
+
+	// Number of peers which resumed their sessions by presenting a session ticket,
+	// sparing them from being challenged.
+	ResumedSessions uint64 `json:"resumed_sessions"`
+
+	// Connection history of up to MaxStatsPeers peers that have ever connected, sorted
+	// by address.
+	Peers []peerstore.PeerStats `json:"peers"`
+}
+
+// Stats returns a summary of the peers of the network, and their connection history.
+func (n *Network) Stats() Stats {
+	stats := Stats{
+		HandshakeTimeouts: atomic.LoadUint64(&n.handshakeTimeouts),
+		StreamTimeouts:    atomic.LoadUint64(&n.streamTimeouts),
+		ResumedSessions:   atomic.LoadUint64(&n.resumedSessions),
+		Peers:             n.Peerstore.Page("", MaxStatsPeers),
+	}
+
+	n.Peers.Range(func(key, value interface{}) bool {
+		stats.ConnectedPeers++
+		return true
+	})
+
+	return stats
+}
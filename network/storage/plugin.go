@@ -0,0 +1,532 @@
+package storage
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/peerstore"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/schedule"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultReplication is the default number of peers closest to a key which a
+	// record is stored on.
+	DefaultReplication = dht.BucketSize
+
+	// DefaultTTL is the default duration a record is stored for before it expires.
+	DefaultTTL = 24 * time.Hour
+
+	// DefaultRepublishInterval is the default interval at which stored records are
+	// republished to the peers closest to their keys.
+	DefaultRepublishInterval = 1 * time.Hour
+
+	// requestTimeout is how long peers are waited on to respond to storage requests.
+	requestTimeout = 3 * time.Second
+
+	// DefaultMaxRecordSize is the default limit on the size of a record's key and value.
+	DefaultMaxRecordSize = 64 * 1024
+
+	// DefaultMaxRecords is the default limit on the number of records stored.
+	DefaultMaxRecords = 65536
+
+	// maxQueries is the maximum number of peers queried when looking up a value.
+	maxQueries = 3 * dht.BucketSize
+)
+
+var (
+	// ErrNotFound is returned when no peer holds a record under a requested key.
+	ErrNotFound = errors.New("record not found")
+
+	// ErrNotPublisher is returned when a record is put under a key which another
+	// publisher stored a record under.
+	ErrNotPublisher = errors.New("record is published by another peer")
+
+	// ErrStorageFull is returned when a record is put while the maximum number of
+	// records are stored.
+	ErrStorageFull = errors.New("record storage is full")
+)
+
+// Options configure how records are replicated and kept alive.
+type Options struct {
+	// Replication is the number of peers closest to a key which a record is stored on.
+	Replication int
+
+	// TTL is how long a record is stored for before it expires. Records put by this
+	// node have their expiry renewed each time they are republished.
+	TTL time.Duration
+
+	// RepublishInterval is the interval at which all stored records are republished
+	// to the peers closest to their keys, so that records survive churn.
+	RepublishInterval time.Duration
+
+	// Backend persists stored records. Nil if records should be persisted to the
+	// backend of the network's peer store, if any.
+	Backend peerstore.Backend
+
+	// MaxRecordSize is the limit on the size of a record's key and value in bytes.
+	MaxRecordSize int
+
+	// MaxRecords is the limit on the number of records stored, past which records
+	// under keys not already stored are rejected.
+	MaxRecords int
+}
+
+// DefaultOptions returns the default storage options.
+func DefaultOptions() Options {
+	return Options{
+		Replication:       DefaultReplication,
+		TTL:               DefaultTTL,
+		RepublishInterval: DefaultRepublishInterval,
+		MaxRecordSize:     DefaultMaxRecordSize,
+		MaxRecords:        DefaultMaxRecords,
+	}
+}
+
+// Plugin stores key-value records on the peers whose IDs are closest to each key's
+// hash, as per Kademlia's STORE and FIND_VALUE RPCs. The discovery plugin must be
+// registered for peers to be found.
+type Plugin struct {
+	*network.Plugin
+
+	Options Options
+
+	net     *network.Network
+	records *recordTable
+
+	// republishing periodically republishes records. Nil before startup.
+	republishing *schedule.Task
+
+	validators      map[string]Validator
+	owners          map[string]Owner
+	validatorsMutex sync.RWMutex
+}
+
+var (
+	// PluginID to reference storage plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// New creates a storage plugin with a set of options.
+func New(options Options) *Plugin {
+	return &Plugin{Options: options}
+}
+
+// RegisterValidator registers a validator which records under keys of a namespace of
+// the form /namespace/... must pass before being accepted, in addition to being signed
+// by the owner of their key. Records under namespaces without a validator are only
+// checked for their owner.
+func (p *Plugin) RegisterValidator(namespace string, validator Validator) {
+	p.validatorsMutex.Lock()
+	defer p.validatorsMutex.Unlock()
+
+	if p.validators == nil {
+		p.validators = make(map[string]Validator)
+	}
+
+	p.validators[namespace] = validator
+}
+
+// RegisterOwner registers the rule as to which publisher owns keys of a namespace of
+// the form /namespace/..., in place of KeyOwner.
+func (p *Plugin) RegisterOwner(namespace string, owner Owner) {
+	p.validatorsMutex.Lock()
+	defer p.validatorsMutex.Unlock()
+
+	if p.owners == nil {
+		p.owners = make(map[string]Owner)
+	}
+
+	p.owners[namespace] = owner
+}
+
+// validate checks that a record is within size limits and signed by its publisher,
+// that namespaced keys are published by their owner, and runs it through the validator
+// registered for its key's namespace. Keys without a namespace are owned by whichever
+// publisher stored a record under them first.
+func (p *Plugin) validate(record *protobuf.Record) error {
+	if size := len(record.Key) + len(record.Value); size > p.maxRecordSize() {
+		return errors.Errorf("record of %d bytes exceeds the limit of %d bytes", size, p.maxRecordSize())
+	}
+
+	if err := SignedWith(p.net).Validate(record); err != nil {
+		return err
+	}
+
+	namespace := Namespace(record.Key)
+	if namespace == "" {
+		return nil
+	}
+
+	p.validatorsMutex.RLock()
+	validator, exists := p.validators[namespace]
+	owner, owned := p.owners[namespace]
+	p.validatorsMutex.RUnlock()
+
+	if !owned {
+		owner = KeyOwner
+	}
+
+	if err := OwnedBy(owner).Validate(record); err != nil {
+		return err
+	}
+
+	if !exists {
+		return nil
+	}
+
+	return validator.Validate(record)
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.net = net
+
+	if err := net.RegisterMessages(new(protobuf.Record), new(protobuf.StoreRequest), new(protobuf.StoreResponse), new(protobuf.FindValueRequest), new(protobuf.FindValueResponse)); err != nil {
+		glog.Warningf("storage: failed to register message types [err=%s]", err)
+	}
+	backend := p.Options.Backend
+	if backend == nil {
+		backend = net.Peerstore.Backend()
+	}
+
+	p.records = newRecordTable(backend, p.maxRecords())
+
+	p.republishing = net.Scheduler().Repeat(schedule.Every(p.republishInterval()), p.Republish)
+}
+
+// Cleanup implements the plugin callback
+func (p *Plugin) Cleanup(net *network.Network) {
+	p.republishing.Cancel()
+}
+
+func (p *Plugin) clock() clock.Clock {
+	return clock.Or(p.net.Clock)
+}
+
+func (p *Plugin) replication() int {
+	if p.Options.Replication <= 0 {
+		return DefaultReplication
+	}
+	return p.Options.Replication
+}
+
+func (p *Plugin) ttl() time.Duration {
+	if p.Options.TTL <= 0 {
+		return DefaultTTL
+	}
+	return p.Options.TTL
+}
+
+func (p *Plugin) maxRecordSize() int {
+	if p.Options.MaxRecordSize <= 0 {
+		return DefaultMaxRecordSize
+	}
+	return p.Options.MaxRecordSize
+}
+
+func (p *Plugin) maxRecords() int {
+	if p.Options.MaxRecords <= 0 {
+		return DefaultMaxRecords
+	}
+	return p.Options.MaxRecords
+}
+
+func (p *Plugin) routes() (*dht.RoutingTable, error) {
+	plugin, exists := p.net.Plugin(discovery.PluginID)
+	if !exists {
+		return nil, errors.New("storage requires the discovery plugin to be registered")
+	}
+	return plugin.(*discovery.Plugin).Routes, nil
+}
+
+// KeyID returns the position of a key within the ID space of a network, which is the
+// hash of the key truncated to the length of the network's public keys.
+func KeyID(net *network.Network, key []byte) peer.ID {
+	return net.KeyID(key)
+}
+
+// closestPeers returns the peers closest to a key found through an iterative lookup.
+func (p *Plugin) closestPeers(target peer.ID) []peer.ID {
+	var peers []peer.ID
+
+	for _, id := range discovery.FindNode(p.net, target, dht.BucketSize, 8) {
+		if !id.Equals(p.net.ID) {
+			peers = append(peers, id)
+		}
+	}
+
+	return peer.Closest(target, peers, p.replication())
+}
+
+// Put signs and stores a value under a key locally, and on the peers closest to the key.
+func (p *Plugin) Put(key []byte, value []byte) error {
+	now := p.clock().Now()
+
+	record := &protobuf.Record{
+		Key:       key,
+		Value:     value,
+		ExpiresAt: now.Add(p.ttl()).UnixNano(),
+		Timestamp: now.UnixNano(),
+	}
+
+	if err := Sign(p.net, record); err != nil {
+		return err
+	}
+
+	if err := p.validate(record); err != nil {
+		return errors.Wrap(err, "record failed validation")
+	}
+
+	if existing, found := p.records.get(key, now); found && !bytes.Equal(existing.Publisher, record.Publisher) {
+		return errors.Wrapf(ErrNotPublisher, "failed to put record %x", key)
+	}
+
+	if !p.records.put(record, true) {
+		return errors.Wrapf(ErrStorageFull, "failed to put record %x", key)
+	}
+
+	return p.replicate(record)
+}
+
+// replicate stores a record on the peers closest to its key. Errors should no peer
+// accept the record while there are peers to store it on.
+func (p *Plugin) replicate(record *protobuf.Record) error {
+	if _, err := p.routes(); err != nil {
+		return err
+	}
+
+	peers := p.closestPeers(KeyID(p.net, record.Key))
+	if len(peers) == 0 {
+		return nil
+	}
+
+	results := make(chan bool, len(peers))
+
+	for _, id := range peers {
+		go func(id peer.ID) {
+			results <- p.storeOn(id, record)
+		}(id)
+	}
+
+	stored := 0
+	for range peers {
+		if <-results {
+			stored++
+		}
+	}
+
+	if stored == 0 {
+		return errors.Errorf("no peer out of %d accepted the record", len(peers))
+	}
+
+	return nil
+}
+
+// storeOn sends a record to a peer to be stored, and returns true if it was stored.
+func (p *Plugin) storeOn(id peer.ID, record *protobuf.Record) bool {
+	client, err := p.net.Client(id.Address)
+	if err != nil {
+		return false
+	}
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.StoreRequest{Record: record})
+	request.SetTimeout(requestTimeout)
+
+	response, err := client.Request(request)
+	if err != nil {
+		return false
+	}
+
+	res, ok := response.(*protobuf.StoreResponse)
+	return ok && res.Stored
+}
+
+// Get looks up the value stored under a key, first locally and then by iteratively
+// querying the peers closest to the key.
+func (p *Plugin) Get(key []byte) ([]byte, error) {
+	if record, found := p.records.get(key, p.clock().Now()); found {
+		return record.Value, nil
+	}
+
+	routes, err := p.routes()
+	if err != nil {
+		return nil, err
+	}
+
+	target := KeyID(p.net, key)
+
+	visited := map[string]struct{}{p.net.ID.PublicKeyHex(): {}}
+	queue := routes.FindClosestPeers(target, p.replication())
+
+	for queries := 0; len(queue) > 0 && queries < maxQueries; queries++ {
+		id := queue[0]
+		queue = queue[1:]
+
+		if _, seen := visited[id.PublicKeyHex()]; seen {
+			continue
+		}
+		visited[id.PublicKeyHex()] = struct{}{}
+
+		response, err := p.findValueOn(id, key)
+		if err != nil {
+			continue
+		}
+
+		if response.Record != nil && !expired(response.Record, p.clock().Now()) {
+			if !bytes.Equal(response.Record.Key, key) {
+				continue
+			}
+
+			if err := p.validate(response.Record); err != nil {
+				glog.Warningf("Peer %s responded with an invalid record [err=%s]", id.Address, err)
+				continue
+			}
+
+			return response.Record.Value, nil
+		}
+
+		for _, closer := range response.Peers {
+			queue = append(queue, peer.ID(*closer))
+		}
+
+		peer.SortByDistance(target, queue)
+	}
+
+	return nil, ErrNotFound
+}
+
+// findValueOn asks a peer for the record under a key, or the peers it knows closest to the key.
+func (p *Plugin) findValueOn(id peer.ID, key []byte) (*protobuf.FindValueResponse, error) {
+	client, err := p.net.Client(id.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.FindValueRequest{Key: key})
+	request.SetTimeout(requestTimeout)
+
+	response, err := client.Request(request)
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := response.(*protobuf.FindValueResponse)
+	if !ok {
+		return nil, errors.Errorf("unexpected response %T to find value request", response)
+	}
+
+	return res, nil
+}
+
+// Receive implements the plugin callback
+func (p *Plugin) Receive(ctx *network.PluginContext) error {
+	switch msg := ctx.Message().(type) {
+	case *protobuf.StoreRequest:
+		return ctx.Reply(&protobuf.StoreResponse{Stored: p.accept(msg.Record)})
+	case *protobuf.FindValueRequest:
+		response := new(protobuf.FindValueResponse)
+
+		if record, found := p.records.get(msg.Key, p.clock().Now()); found {
+			response.Record = record
+		} else if routes, err := p.routes(); err == nil {
+			for _, id := range routes.FindClosestPeers(KeyID(p.net, msg.Key), p.replication()) {
+				if id.Equals(p.net.ID) || !routes.IsVerified(id) {
+					continue
+				}
+
+				closer := protobuf.ID(id)
+				response.Peers = append(response.Peers, &closer)
+			}
+		}
+
+		return ctx.Reply(response)
+	}
+
+	return nil
+}
+
+// accept validates and stores a record replicated by another peer, capping its expiry
+// to the TTL. Records are bound to the publisher of the record first stored under their
+// key, and records older than the one already stored are rejected.
+func (p *Plugin) accept(record *protobuf.Record) bool {
+	now := p.clock().Now()
+
+	if record == nil || len(record.Key) == 0 || expired(record, now) {
+		return false
+	}
+
+	if err := p.validate(record); err != nil {
+		glog.Warningf("Rejected record %x [err=%s]", record.Key, err)
+		return false
+	}
+
+	if existing, found := p.records.get(record.Key, now); found {
+		if !bytes.Equal(existing.Publisher, record.Publisher) {
+			glog.Warningf("Rejected record %x published by another peer", record.Key)
+			return false
+		}
+
+		if existing.Timestamp > record.Timestamp {
+			return false
+		}
+
+		// The record is already stored, such as should it be our own being republished
+		// back to us.
+		if existing.Timestamp == record.Timestamp {
+			return true
+		}
+	}
+
+	if limit := now.Add(p.ttl()).UnixNano(); record.ExpiresAt == 0 || record.ExpiresAt > limit {
+		record.ExpiresAt = limit
+	}
+
+	return p.records.put(record, false)
+}
+
+func (p *Plugin) republishInterval() time.Duration {
+	if p.Options.RepublishInterval <= 0 {
+		return DefaultRepublishInterval
+	}
+	return p.Options.RepublishInterval
+}
+
+// Republish discards expired records, and republishes all others to the peers closest
+// to their keys. Records put by this node have their expiry renewed.
+func (p *Plugin) Republish() {
+	now := p.clock().Now()
+
+	p.records.expire(now)
+
+	for _, stored := range p.records.all() {
+		record := stored.record
+
+		if stored.originated {
+			record = &protobuf.Record{
+				Key:       record.Key,
+				Value:     record.Value,
+				ExpiresAt: now.Add(p.ttl()).UnixNano(),
+				Timestamp: record.Timestamp,
+				Publisher: record.Publisher,
+				Signature: record.Signature,
+			}
+			p.records.put(record, true)
+		}
+
+		if err := p.replicate(record); err != nil {
+			glog.Warningf("failed to republish record %x: %s", record.Key, err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/networktest"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func buildNode(t *testing.T) (*network.Network, *Plugin) {
+	plugin := New(DefaultOptions())
+	return networktest.NewNode(t, new(discovery.Plugin), plugin), plugin
+}
+
+func TestPutGet(t *testing.T) {
+	var nodes []*network.Network
+	var plugins []*Plugin
+
+	for i := 0; i < 3; i++ {
+		node, plugin := buildNode(t)
+		defer node.Close()
+
+		nodes = append(nodes, node)
+		plugins = append(plugins, plugin)
+	}
+
+	for _, node := range nodes[1:] {
+		node.Bootstrap(nodes[0].Address)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	if err := plugins[1].Put([]byte("hello"), []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, plugin := range plugins {
+		value, err := plugin.Get([]byte("hello"))
+		if err != nil {
+			t.Fatalf("node %d failed to get value: %v", i, err)
+		}
+
+		if string(value) != "world" {
+			t.Fatalf("node %d got unexpected value %q", i, value)
+		}
+	}
+
+	if _, err := plugins[2].Get([]byte("missing")); err != ErrNotFound {
+		t.Fatalf("expected missing key to not be found, got %v", err)
+	}
+}
+
+func buildPublisher(t *testing.T) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", 3000))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func createRecord(t *testing.T, publisher *network.Network, key string, value string, timestamp int64) *protobuf.Record {
+	record := &protobuf.Record{Key: []byte(key), Value: []byte(value), Timestamp: timestamp}
+	if err := Sign(publisher, record); err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func TestAccept(t *testing.T) {
+	node := buildPublisher(t)
+
+	options := DefaultOptions()
+	options.MaxRecordSize = 16
+	options.MaxRecords = 2
+
+	plugin := New(options)
+	plugin.Startup(node)
+	defer plugin.Cleanup(node)
+
+	publisher, impostor := buildPublisher(t), buildPublisher(t)
+	now := time.Now().UnixNano()
+
+	if !plugin.accept(createRecord(t, publisher, "a", "first", now)) {
+		t.Fatal("expected record to be accepted")
+	}
+
+	if plugin.accept(createRecord(t, impostor, "a", "forged", now+1)) {
+		t.Fatal("expected a newer record of another publisher to be rejected")
+	}
+
+	tampered := createRecord(t, publisher, "a", "second", now+1)
+	tampered.Value = []byte("tampered")
+
+	if plugin.accept(tampered) {
+		t.Fatal("expected a record with an invalid signature to be rejected")
+	}
+
+	if plugin.accept(createRecord(t, publisher, "b", string(make([]byte, 16)), now)) {
+		t.Fatal("expected a record exceeding the size limit to be rejected")
+	}
+
+	if !plugin.accept(createRecord(t, publisher, "b", "value", now)) {
+		t.Fatal("expected record to be accepted")
+	}
+
+	if plugin.accept(createRecord(t, publisher, "c", "value", now)) {
+		t.Fatal("expected a record to be rejected once storage is full")
+	}
+
+	if !plugin.accept(createRecord(t, publisher, "a", "second", now+1)) {
+		t.Fatal("expected a newer record of the same publisher to be accepted")
+	}
+}
+
+func TestAcceptReplacesOriginated(t *testing.T) {
+	node := buildPublisher(t)
+
+	plugin := New(DefaultOptions())
+	plugin.Startup(node)
+	defer plugin.Cleanup(node)
+
+	now := time.Now().UnixNano()
+
+	plugin.records.put(createRecord(t, node, "key", "value", now), true)
+
+	if !plugin.accept(createRecord(t, node, "key", "newer", now+int64(time.Minute))) {
+		t.Fatal("expected a newer record of the publisher to be accepted")
+	}
+
+	if all := plugin.records.all(); len(all) != 1 || all[0].originated {
+		t.Fatalf("expected a replica replacing the put record to not be renewed, got %v", all)
+	}
+}
+
+func TestAcceptOwnership(t *testing.T) {
+	node := buildPublisher(t)
+
+	plugin := New(DefaultOptions())
+	plugin.Startup(node)
+	defer plugin.Cleanup(node)
+
+	publisher, impostor := buildPublisher(t), buildPublisher(t)
+	now := time.Now().UnixNano()
+
+	key := "/pk/" + hex.EncodeToString(publisher.ID.PublicKey) + "/name"
+
+	if plugin.accept(createRecord(t, impostor, key, "forged", now)) {
+		t.Fatal("expected a record under a key owned by another publisher to be rejected")
+	}
+
+	if !plugin.accept(createRecord(t, publisher, key, "value", now)) {
+		t.Fatal("expected a record of the key's owner to be accepted")
+	}
+
+	// Ownership rules may be registered per namespace.
+	plugin.RegisterOwner("names", func(key []byte) []byte {
+		return publisher.ID.PublicKey
+	})
+
+	if plugin.accept(createRecord(t, impostor, "/names/alice", "forged", now)) {
+		t.Fatal("expected a record of a publisher other than the owner to be rejected")
+	}
+
+	if !plugin.accept(createRecord(t, publisher, "/names/alice", "value", now)) {
+		t.Fatal("expected a record of the key's owner to be accepted")
+	}
+}
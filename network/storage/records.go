@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/peerstore"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+type storedRecord struct {
+	record *protobuf.Record
+
+	// originated is true should the record have been put by this node, in which case
+	// its expiry is renewed upon being republished.
+	originated bool
+}
+
+// recordTable is a concurrent-safe table of records stored by this node, which
+// are written through to a backend should one be set.
+type recordTable struct {
+	mutex   sync.RWMutex
+	records map[string]*storedRecord
+
+	// capacity is the maximum number of records stored. Zero if unbounded.
+	capacity int
+
+	backend peerstore.Backend
+}
+
+// newRecordTable creates a table holding at most capacity records, loading those
+// persisted to a backend. The backend may be nil, and capacity zero if unbounded.
+func newRecordTable(backend peerstore.Backend, capacity int) *recordTable {
+	t := &recordTable{records: make(map[string]*storedRecord), capacity: capacity, backend: backend}
+
+	if backend == nil {
+		return t
+	}
+
+	err := backend.Iterate(peerstore.RecordsBucket, func(key string, value []byte) error {
+		stored, err := decodeRecord(value)
+		if err != nil {
+			glog.Warningf("Dropped malformed record %s from the peer store backend [err=%s]", key, err)
+			return nil
+		}
+
+		t.records[string(stored.record.Key)] = stored
+		return nil
+	})
+
+	if err != nil {
+		glog.Warningf("Failed to load records from the peer store backend [err=%s]", err)
+	}
+
+	return t
+}
+
+// encodeRecord encodes a stored record as a byte marking whether it originated from
+// this node followed by the record.
+func encodeRecord(stored *storedRecord) ([]byte, error) {
+	bytes, err := proto.Marshal(stored.record)
+	if err != nil {
+		return nil, err
+	}
+
+	originated := byte(0)
+	if stored.originated {
+		originated = 1
+	}
+
+	return append([]byte{originated}, bytes...), nil
+}
+
+func decodeRecord(value []byte) (*storedRecord, error) {
+	if len(value) == 0 {
+		return nil, errors.New("record is empty")
+	}
+
+	record := new(protobuf.Record)
+	if err := proto.Unmarshal(value[1:], record); err != nil {
+		return nil, err
+	}
+
+	return &storedRecord{record: record, originated: value[0] == 1}, nil
+}
+
+// persist writes a stored record through to the backend. The table must be locked.
+func (t *recordTable) persist(stored *storedRecord) {
+	if t.backend == nil {
+		return
+	}
+
+	value, err := encodeRecord(stored)
+	if err == nil {
+		err = t.backend.Put(peerstore.RecordsBucket, hex.EncodeToString(stored.record.Key), value)
+	}
+
+	if err != nil {
+		glog.Warningf("Failed to persist record to the peer store backend [err=%s]", err)
+	}
+}
+
+// forget removes a record from the backend. The table must be locked.
+func (t *recordTable) forget(key string) {
+	if t.backend == nil {
+		return
+	}
+
+	if err := t.backend.Delete(peerstore.RecordsBucket, hex.EncodeToString([]byte(key))); err != nil {
+		glog.Warningf("Failed to remove record from the peer store backend [err=%s]", err)
+	}
+}
+
+// put stores a record, replacing any stored under the same key, such that a replica
+// replacing a record put by this node is no longer renewed. It returns false should
+// the table be full.
+func (t *recordTable) put(record *protobuf.Record, originated bool) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, exists := t.records[string(record.Key)]; !exists && t.capacity > 0 && len(t.records) >= t.capacity {
+		return false
+	}
+
+	stored := &storedRecord{record: record, originated: originated}
+
+	t.records[string(record.Key)] = stored
+	t.persist(stored)
+
+	return true
+}
+
+func (t *recordTable) get(key []byte, now time.Time) (*protobuf.Record, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	stored, exists := t.records[string(key)]
+	if !exists || expired(stored.record, now) {
+		return nil, false
+	}
+
+	return stored.record, true
+}
+
+// expire removes all expired records.
+func (t *recordTable) expire(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for key, stored := range t.records {
+		if expired(stored.record, now) {
+			delete(t.records, key)
+			t.forget(key)
+		}
+	}
+}
+
+// all returns a copy of all stored records.
+func (t *recordTable) all() []storedRecord {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	all := make([]storedRecord, 0, len(t.records))
+	for _, stored := range t.records {
+		all = append(all, *stored)
+	}
+
+	return all
+}
+
+func expired(record *protobuf.Record, now time.Time) bool {
+	return record.ExpiresAt != 0 && now.UnixNano() >= record.ExpiresAt
+}
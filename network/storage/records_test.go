@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/peerstore"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestRecordTableBackend(t *testing.T) {
+	backend := peerstore.NewMemoryBackend()
+
+	table := newRecordTable(backend, 0)
+	table.put(&protobuf.Record{Key: []byte("kept"), Value: []byte("value")}, true)
+	table.put(&protobuf.Record{Key: []byte("expired"), Value: []byte("value"), ExpiresAt: 1}, false)
+	table.expire(time.Now())
+
+	// A table created afresh loads the records persisted by the prior table.
+	restored := newRecordTable(backend, 0)
+
+	record, found := restored.get([]byte("kept"), time.Now())
+	if !found || !bytes.Equal(record.Value, []byte("value")) {
+		t.Fatalf("expected record to be restored, got %v", record)
+	}
+
+	if all := restored.all(); len(all) != 1 || !all[0].originated {
+		t.Fatalf("expected only the originated record to be restored, got %v", all)
+	}
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/pkg/errors"
+)
+
+// Validator checks whether a record may be accepted.
+type Validator interface {
+	Validate(record *protobuf.Record) error
+}
+
+// ValidatorFunc is an adapter to allow ordinary functions to be used as validators.
+type ValidatorFunc func(record *protobuf.Record) error
+
+// Validate implements Validator.
+func (f ValidatorFunc) Validate(record *protobuf.Record) error {
+	return f(record)
+}
+
+// Namespace returns the namespace of a key of the form /namespace/..., or an empty
+// string should the key not be namespaced.
+func Namespace(key []byte) string {
+	if len(key) == 0 || key[0] != '/' {
+		return ""
+	}
+
+	rest := key[1:]
+	if i := bytes.IndexByte(rest, '/'); i >= 0 {
+		return string(rest[:i])
+	}
+
+	return ""
+}
+
+// Owner returns the public key of the publisher owning a key. Nil if no publisher
+// owns the key.
+type Owner func(key []byte) []byte
+
+// KeyOwner is the owner of keys of the form /namespace/<hex public key>/..., being the
+// publisher whose public key the key embeds.
+func KeyOwner(key []byte) []byte {
+	namespace := Namespace(key)
+	if namespace == "" {
+		return nil
+	}
+
+	rest := key[len(namespace)+2:]
+	if i := bytes.IndexByte(rest, '/'); i >= 0 {
+		rest = rest[:i]
+	}
+
+	publicKey, err := hex.DecodeString(string(rest))
+	if err != nil || len(publicKey) == 0 {
+		return nil
+	}
+
+	return publicKey
+}
+
+// OwnedBy returns a validator rejecting records not published by the owner of their key.
+func OwnedBy(owner Owner) Validator {
+	return ValidatorFunc(func(record *protobuf.Record) error {
+		expected := owner(record.Key)
+		if expected == nil {
+			return errors.Errorf("key %q has no owner", record.Key)
+		}
+
+		if !bytes.Equal(record.Publisher, expected) {
+			return errors.Errorf("record under key %q is not published by its owner", record.Key)
+		}
+
+		return nil
+	})
+}
+
+// Chain returns a validator which requires a record to pass all validators in order.
+func Chain(validators ...Validator) Validator {
+	return ValidatorFunc(func(record *protobuf.Record) error {
+		for _, validator := range validators {
+			if err := validator.Validate(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MaxSize returns a validator rejecting records whose key and value are larger than
+// max bytes in total.
+func MaxSize(max int) Validator {
+	return ValidatorFunc(func(record *protobuf.Record) error {
+		if size := len(record.Key) + len(record.Value); size > max {
+			return errors.Errorf("record of %d bytes exceeds the limit of %d bytes", size, max)
+		}
+		return nil
+	})
+}
+
+// Freshness returns a validator rejecting records published more than maxAge ago, or
+// more than maxAge into the future. A nil clock defaults to the system clock.
+func Freshness(maxAge time.Duration, c clock.Clock) Validator {
+	c = clock.Or(c)
+
+	return ValidatorFunc(func(record *protobuf.Record) error {
+		age := c.Now().Sub(time.Unix(0, record.Timestamp))
+		if record.Timestamp == 0 || age > maxAge || age < -maxAge {
+			return errors.Errorf("record published %s ago is not fresh", age)
+		}
+		return nil
+	})
+}
+
+// Signed returns a validator rejecting records which are not signed by their publisher.
+func Signed(sp crypto.SignaturePolicy, hp crypto.HashPolicy) Validator {
+	return ValidatorFunc(func(record *protobuf.Record) error {
+		if len(record.Publisher) == 0 || len(record.Signature) == 0 {
+			return errors.New("record is not signed")
+		}
+
+		if !crypto.Verify(sp, hp, record.Publisher, signedRecord(record), record.Signature) {
+			return errors.New("record has an invalid signature")
+		}
+
+		return nil
+	})
+}
+
+// SignedWith returns a validator rejecting records which are not signed by their
+// publisher, as verified by a network, such as one whose envelopes are verified with
+// a VerifyEnvelope.
+func SignedWith(net *network.Network) Validator {
+	return ValidatorFunc(func(record *protobuf.Record) error {
+		if len(record.Publisher) == 0 || len(record.Signature) == 0 {
+			return errors.New("record is not signed")
+		}
+
+		if !net.Verify(record.Publisher, signedRecord(record), record.Signature) {
+			return errors.New("record has an invalid signature")
+		}
+
+		return nil
+	})
+}
+
+// Sign signs a record on behalf of a network, setting its publisher and signature.
+func Sign(net *network.Network, record *protobuf.Record) error {
+	record.Publisher = net.ID.PublicKey
+
+	signature, err := net.Sign(signedRecord(record))
+	if err != nil {
+		return errors.Wrap(err, "failed to sign record")
+	}
+
+	record.Signature = signature
+
+	return nil
+}
+
+// serializeRecord serializes the signed contents of a record, being its key, value and
+// timestamp. Expiry is left unsigned, as peers cap it to their own TTL.
+func serializeRecord(record *protobuf.Record) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, uint64(len(record.Key)))
+
+	out := append([]byte{}, buf[:n]...)
+	out = append(out, record.Key...)
+	out = append(out, record.Value...)
+
+	timestamp := make([]byte, 8)
+	binary.LittleEndian.PutUint64(timestamp, uint64(record.Timestamp))
+
+	return append(out, timestamp...)
+}
+
+// signedRecord returns what is signed of a record, being its serialized contents tagged
+// with the record domain.
+func signedRecord(record *protobuf.Record) []byte {
+	return crypto.DomainSeparate(network.DomainRecord, serializeRecord(record))
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/hashing/blake2b"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/perlin-network/noise/types/clock"
+)
+
+func TestNamespace(t *testing.T) {
+	cases := map[string]string{
+		"/pk/abc":  "pk",
+		"/pk/":     "pk",
+		"/pk":      "",
+		"pk/abc":   "",
+		"":         "",
+		"//abc":    "",
+		"/ns/a/b/": "ns",
+	}
+
+	for key, expected := range cases {
+		if namespace := Namespace([]byte(key)); namespace != expected {
+			t.Fatalf("expected namespace of %q to be %q, got %q", key, expected, namespace)
+		}
+	}
+}
+
+func TestSigned(t *testing.T) {
+	keys := ed25519.RandomKeyPair()
+
+	net := &network.Network{
+		ID:              peer.CreateID("tcp://127.0.0.1:3000", keys.PublicKey),
+		Keys:            keys,
+		SignaturePolicy: ed25519.New(),
+		HashPolicy:      blake2b.New(),
+	}
+
+	record := &protobuf.Record{Key: []byte("/pk/a"), Value: []byte("hello"), Timestamp: 1}
+	if err := Sign(net, record); err != nil {
+		t.Fatal(err)
+	}
+
+	validator := Signed(net.SignaturePolicy, net.HashPolicy)
+
+	if err := validator.Validate(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Value = []byte("tampered")
+
+	if err := validator.Validate(record); err == nil {
+		t.Fatal("expected tampered record to be rejected")
+	}
+}
+
+func TestSignedWith(t *testing.T) {
+	publicKey := ed25519.RandomKeyPair().PublicKey
+
+	// Sign with a scheme other than the signature policy, as a network signing with
+	// callbacks would.
+	net := &network.Network{
+		ID: peer.CreateID("tcp://127.0.0.1:3000", publicKey),
+		SignEnvelope: func(payload []byte) ([]byte, error) {
+			return append([]byte("signed:"), payload...), nil
+		},
+		VerifyEnvelope: func(sender peer.ID, payload []byte, signature []byte) bool {
+			return bytes.Equal(signature, append([]byte("signed:"), payload...))
+		},
+	}
+
+	record := &protobuf.Record{Key: []byte("/pk/a"), Value: []byte("hello"), Timestamp: 1}
+	if err := Sign(net, record); err != nil {
+		t.Fatal(err)
+	}
+
+	validator := SignedWith(net)
+
+	if err := validator.Validate(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Value = []byte("tampered")
+
+	if err := validator.Validate(record); err == nil {
+		t.Fatal("expected tampered record to be rejected")
+	}
+}
+
+func TestChain(t *testing.T) {
+	mock := clock.NewMock(time.Unix(1000, 0))
+
+	validator := Chain(MaxSize(16), Freshness(time.Minute, mock))
+
+	record := &protobuf.Record{Key: []byte("/ns/a"), Value: []byte("hello"), Timestamp: mock.Now().UnixNano()}
+
+	if err := validator.Validate(record); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.Add(2 * time.Minute)
+
+	if err := validator.Validate(record); err == nil {
+		t.Fatal("expected stale record to be rejected")
+	}
+
+	record.Timestamp = mock.Now().UnixNano()
+	record.Value = make([]byte, 32)
+
+	if err := validator.Validate(record); err == nil {
+		t.Fatal("expected large record to be rejected")
+	}
+}
+
+func TestOwnedBy(t *testing.T) {
+	publicKey := ed25519.RandomKeyPair().PublicKey
+	key := []byte("/pk/" + hex.EncodeToString(publicKey) + "/name")
+
+	if owner := KeyOwner(key); !bytes.Equal(owner, publicKey) {
+		t.Fatalf("expected key to be owned by %x, got %x", publicKey, owner)
+	}
+
+	for _, unowned := range []string{"name", "/pk/", "/pk/zz/name"} {
+		if owner := KeyOwner([]byte(unowned)); owner != nil {
+			t.Fatalf("expected key %q to have no owner, got %x", unowned, owner)
+		}
+	}
+
+	validator := OwnedBy(KeyOwner)
+
+	if err := validator.Validate(&protobuf.Record{Key: key, Publisher: publicKey}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := validator.Validate(&protobuf.Record{Key: key, Publisher: ed25519.RandomKeyPair().PublicKey}); err == nil {
+		t.Fatal("expected record of another publisher to be rejected")
+	}
+}
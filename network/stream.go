@@ -5,16 +5,31 @@ import (
 	"encoding/binary"
 	"io"
 	"net"
+	"sync/atomic"
 	"time"
 
+	"github.com/golang/glog"
 	"github.com/golang/protobuf/proto"
-	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/protobuf"
 	"github.com/pkg/errors"
 )
 
+const (
+	// DefaultReadTimeout is the default period a stream has to deliver its message in
+	// full before being reaped.
+	DefaultReadTimeout = 10 * time.Second
+
+	// DefaultMaxMessageSize is the default cap on the size of a received message. If a
+	// big message need be sent, consider partitioning the message into chunks.
+	DefaultMaxMessageSize = 4e+6
+
+	// DefaultMaxHeaderSize is the default cap on the size of a received message's
+	// envelope, excluding its payload.
+	DefaultMaxHeaderSize = 64 * 1024
+)
+
 // sendMessage marshals, signs and sends a message over a stream.
-func (n *Network) sendMessage(stream net.Conn, message *protobuf.Message) error {
+func sendMessage(stream net.Conn, message *protobuf.Message) error {
 	bytes, err := proto.Marshal(message)
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal message")
@@ -27,6 +42,7 @@ func (n *Network) sendMessage(stream net.Conn, message *protobuf.Message) error
 	// Prefix message with its size.
 	bytes = append(buffer, bytes...)
 
+	// Socket deadlines are enforced by the runtime, and hence must use the system clock.
 	stream.SetDeadline(time.Now().Add(3 * time.Second))
 
 	writer := bufio.NewWriter(stream)
@@ -50,13 +66,47 @@ func (n *Network) sendMessage(stream net.Conn, message *protobuf.Message) error
 	return nil
 }
 
-// receiveMessage reads, unmarshals and verifies a message from a stream.
+// maxMessageSize returns the cap on the size of received messages.
+func (n *Network) maxMessageSize() uint64 {
+	if n.MaxMessageSize > 0 {
+		return uint64(n.MaxMessageSize)
+	}
+	return DefaultMaxMessageSize
+}
+
+// maxHeaderSize returns the cap on the size of received messages' envelopes.
+func (n *Network) maxHeaderSize() int {
+	if n.MaxHeaderSize > 0 {
+		return n.MaxHeaderSize
+	}
+	return DefaultMaxHeaderSize
+}
+
+// receiveMessage reads, unmarshals and verifies a message from a stream. Streams which
+// do not deliver their message in full within the read timeout are reaped.
 func (n *Network) receiveMessage(stream net.Conn) (*protobuf.Message, error) {
+	// Socket deadlines are enforced by the runtime, and hence must use the system clock.
+	if n.ReadTimeout > 0 {
+		stream.SetReadDeadline(time.Now().Add(n.ReadTimeout))
+	}
+
+	msg, err := n.readMessage(stream)
+
+	if err, ok := errors.Cause(err).(net.Error); ok && err.Timeout() {
+		atomic.AddUint64(&n.streamTimeouts, 1)
+	}
+
+	return msg, err
+}
+
+func (n *Network) readMessage(stream net.Conn) (*protobuf.Message, error) {
 	reader := bufio.NewReader(stream)
 
+	// Messages are prefixed with a fixed-size header holding their size. Read it in
+	// full, as it may arrive over several reads.
 	buffer := make([]byte, binary.MaxVarintLen64)
 
-	_, err := reader.Read(buffer)
+	_, err := io.ReadFull(reader, buffer)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to recv message size")
 	}
@@ -65,11 +115,17 @@ func (n *Network) receiveMessage(stream net.Conn) (*protobuf.Message, error) {
 	size, read := binary.Uvarint(buffer)
 
 	// Check if unsigned varint overflows, or if protobuf message is too large.
-	// Message size at most is limited to 4MB. If a big message need be sent,
-	// consider partitioning to message into chunks of 4MB.
-	if read <= 0 || size > 4e+6 {
-		return nil, errors.New("message len is either broken or too large")
+	if read <= 0 || size > n.maxMessageSize() {
+		return nil, errors.Wrap(ErrMessageTooLarge, "message len is either broken or too large")
+	}
+
+	// Account for the message being buffered.
+	peer := stream.RemoteAddr().String()
+
+	if err := n.Resources.ReserveMemory(peer, int64(size)); err != nil {
+		return nil, err
 	}
+	defer n.Resources.ReleaseMemory(peer, int64(size))
 
 	// Read message from buffered I/O completely.
 	buffer = make([]byte, size)
@@ -91,20 +147,47 @@ func (n *Network) receiveMessage(stream net.Conn) (*protobuf.Message, error) {
 		return nil, errors.Wrap(err, "failed to unmarshal message")
 	}
 
+	// Check that the envelope besides its payload is not too large.
+	if header := proto.Size(msg) - len(msg.GetMessage().GetValue()); header > n.maxHeaderSize() {
+		return nil, errors.Wrapf(ErrMessageTooLarge, "message envelope is %d bytes", header)
+	}
+
+	// Signatures are of the envelope in the version it was encoded in.
+	version := msg.Version
+
+	// Migrate the envelope up to the version we understand.
+	if err := upgradeEnvelope(msg); err != nil {
+		return nil, err
+	}
+
 	// Check if any of the message headers are invalid or null.
 	if msg.Message == nil || msg.Sender == nil || msg.Sender.PublicKey == nil || len(msg.Sender.Address) == 0 || msg.Signature == nil {
-		return nil, errors.New("received an invalid message (either no message, no sender, or no signature) from a peer")
+		return nil, errors.Wrap(ErrInvalidMessage, "either no message, no sender, or no signature")
+	}
+
+	// Cheaply drop unwanted messages before verifying their signatures.
+	envelope := &Envelope{
+		Network:    n,
+		RemoteAddr: stream.RemoteAddr(),
+		Sender:     msg.Sender,
+		TypeURL:    msg.Message.TypeUrl,
+		Size:       int(size),
+	}
+
+	if err := n.preFilterMessage(envelope); err != nil {
+		glog.Warningf("Dropped message from %s [err=%s]", msg.Sender.Address, err)
+		return nil, err
 	}
 
 	// Verify signature of message.
-	if !crypto.Verify(
-		n.SignaturePolicy,
-		n.HashPolicy,
-		msg.Sender.PublicKey,
-		serializeMessage(msg.Sender, msg.Message.Value),
-		msg.Signature,
-	) {
-		return nil, errors.New("received message had an malformed signature")
+	if !n.verifySignature(msg, version) {
+		return nil, errors.Wrapf(ErrInvalidSignature, "message from %s", msg.Sender.Address)
+	}
+
+	// Message IDs are only signed by envelopes which separate domains. Drop those which
+	// are not, lest they be forged.
+	if version < DomainEnvelopeVersion {
+		msg.Id = nil
 	}
 
 	return msg, nil
@@ -0,0 +1,177 @@
+package network
+
+import (
+	"sync"
+)
+
+// StreamScheduler bounds the number of streams processed concurrently, both overall
+// and per peer, and grants freed up capacity round-robin across waiting peers so that
+// one chatty peer may not occupy all of it. A nil StreamScheduler is unbounded.
+type StreamScheduler struct {
+	mutex sync.Mutex
+
+	maxStreams        int
+	maxStreamsPerPeer int
+
+	active int
+	peers  map[string]*streamPeer
+
+	// ring is the round-robin order of peers with streams waiting to be processed.
+	ring []string
+}
+
+type streamPeer struct {
+	active  int
+	waiters []chan struct{}
+}
+
+// NewStreamScheduler creates a scheduler processing at most maxStreams streams at once,
+// of which at most maxStreamsPerPeer may be from a single peer. Zero values denote no limit.
+func NewStreamScheduler(maxStreams int, maxStreamsPerPeer int) *StreamScheduler {
+	return &StreamScheduler{
+		maxStreams:        maxStreams,
+		maxStreamsPerPeer: maxStreamsPerPeer,
+		peers:             make(map[string]*streamPeer),
+	}
+}
+
+func (s *StreamScheduler) peer(key string) *streamPeer {
+	p, exists := s.peers[key]
+	if !exists {
+		p = new(streamPeer)
+		s.peers[key] = p
+	}
+	return p
+}
+
+// runnable returns true should a stream from p be able to be processed now.
+func (s *StreamScheduler) runnable(p *streamPeer) bool {
+	return (s.maxStreams <= 0 || s.active < s.maxStreams) &&
+		(s.maxStreamsPerPeer <= 0 || p.active < s.maxStreamsPerPeer)
+}
+
+// Acquire blocks until a stream from a peer may be processed, and returns true. It
+// returns false should cancel be closed first. Every successful Acquire must be
+// followed by a Release.
+func (s *StreamScheduler) Acquire(peer string, cancel <-chan struct{}) bool {
+	if s == nil {
+		return true
+	}
+
+	s.mutex.Lock()
+
+	p := s.peer(peer)
+
+	if len(p.waiters) == 0 && s.runnable(p) {
+		s.active++
+		p.active++
+		s.mutex.Unlock()
+
+		return true
+	}
+
+	granted := make(chan struct{})
+
+	if len(p.waiters) == 0 {
+		s.ring = append(s.ring, peer)
+	}
+	p.waiters = append(p.waiters, granted)
+
+	s.mutex.Unlock()
+
+	select {
+	case <-granted:
+		return true
+	case <-cancel:
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, waiter := range p.waiters {
+		if waiter == granted {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+
+			if len(p.waiters) == 0 {
+				s.removeFromRing(peer)
+				s.cleanup(peer, p)
+			}
+
+			return false
+		}
+	}
+
+	// We were granted capacity while being cancelled; hand it over to someone else.
+	s.release(peer, p)
+
+	return false
+}
+
+// Release marks a stream from a peer as processed, granting its capacity to the next
+// waiting peer in round-robin order.
+func (s *StreamScheduler) Release(peer string) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if p, exists := s.peers[peer]; exists && p.active > 0 {
+		s.release(peer, p)
+	}
+}
+
+func (s *StreamScheduler) release(peer string, p *streamPeer) {
+	s.active--
+	p.active--
+
+	s.cleanup(peer, p)
+	s.grant()
+}
+
+// grant hands out capacity to waiting peers in round-robin order, moving each peer
+// granted capacity to the back of the ring.
+func (s *StreamScheduler) grant() {
+	for i := 0; i < len(s.ring); {
+		key := s.ring[i]
+		p := s.peers[key]
+
+		if s.maxStreams > 0 && s.active >= s.maxStreams {
+			return
+		}
+
+		if !s.runnable(p) {
+			i++
+			continue
+		}
+
+		close(p.waiters[0])
+		p.waiters = p.waiters[1:]
+
+		s.active++
+		p.active++
+
+		s.ring = append(s.ring[:i], s.ring[i+1:]...)
+
+		if len(p.waiters) > 0 {
+			s.ring = append(s.ring, key)
+		}
+	}
+}
+
+func (s *StreamScheduler) removeFromRing(peer string) {
+	for i, key := range s.ring {
+		if key == peer {
+			s.ring = append(s.ring[:i], s.ring[i+1:]...)
+			return
+		}
+	}
+}
+
+// cleanup forgets a peer should it have no streams being processed or waiting.
+func (s *StreamScheduler) cleanup(peer string, p *streamPeer) {
+	if p.active == 0 && len(p.waiters) == 0 {
+		delete(s.peers, peer)
+	}
+}
@@ -0,0 +1,84 @@
+package network
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamSchedulerRoundRobin(t *testing.T) {
+	s := NewStreamScheduler(1, 0)
+
+	if !s.Acquire("chatty", nil) {
+		t.Fatal("expected stream to be processed immediately")
+	}
+
+	order := make(chan string, 4)
+
+	waitFor := func(peer string) {
+		go func() {
+			if s.Acquire(peer, nil) {
+				order <- peer
+			}
+		}()
+
+		// Let the stream queue up before the next.
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	waitFor("chatty")
+	waitFor("chatty")
+	waitFor("chatty")
+	waitFor("quiet")
+
+	expected := []string{"chatty", "quiet", "chatty", "chatty"}
+
+	holder := "chatty"
+
+	for i := range expected {
+		s.Release(holder)
+
+		select {
+		case got := <-order:
+			if got != expected[i] {
+				t.Fatalf("expected stream %d to be from %s, got %s", i, expected[i], got)
+			}
+			holder = got
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for stream %d", i)
+		}
+	}
+}
+
+func TestStreamSchedulerPerPeer(t *testing.T) {
+	s := NewStreamScheduler(0, 1)
+
+	if !s.Acquire("a", nil) || !s.Acquire("b", nil) {
+		t.Fatal("expected streams from different peers to be processed concurrently")
+	}
+
+	cancel := make(chan struct{})
+	acquired := make(chan bool)
+
+	go func() {
+		acquired <- s.Acquire("a", cancel)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("expected second stream from the same peer to wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(cancel)
+
+	if <-acquired {
+		t.Fatal("expected cancelled stream to not be processed")
+	}
+
+	s.Release("a")
+	s.Release("b")
+
+	if len(s.peers) != 0 || len(s.ring) != 0 {
+		t.Fatalf("expected scheduler to forget all peers, got %d peers and %d waiting", len(s.peers), len(s.ring))
+	}
+}
@@ -0,0 +1,47 @@
+package network
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestReceiveMessageTimeout(t *testing.T) {
+	n := &Network{ReadTimeout: 50 * time.Millisecond, Peers: new(sync.Map)}
+
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+
+	// Trickle in only part of the message's header.
+	go remote.Write([]byte{1, 2, 3})
+
+	if _, err := n.receiveMessage(local); err == nil {
+		t.Fatal("expected a stream which stalls to be reaped")
+	}
+
+	if timeouts := n.Stats().StreamTimeouts; timeouts != 1 {
+		t.Fatalf("expected 1 stream timeout, got %d", timeouts)
+	}
+}
+
+func TestReceiveMessageTooLarge(t *testing.T) {
+	n := &Network{ReadTimeout: time.Second, MaxMessageSize: 64}
+
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+
+	header := make([]byte, binary.MaxVarintLen64)
+	binary.PutUvarint(header, 65)
+
+	go remote.Write(header)
+
+	if _, err := n.receiveMessage(local); errors.Cause(err) != ErrMessageTooLarge {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+}
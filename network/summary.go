@@ -0,0 +1,111 @@
+package network
+
+import (
+	"time"
+
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// DefaultSummaryInterval is how often our summary is pushed to peers in heartbeat pings
+// by default, lest their view of it go stale should they otherwise rarely ping us.
+const DefaultSummaryInterval = 30 * time.Second
+
+// SummaryFunc provides the height and head of the application's state, such as that
+// of a chain, to be summarized onto our pings and pongs.
+type SummaryFunc func() (height uint64, head []byte)
+
+// Summary is a lightweight summary of the state of a peer, piggybacked onto its pings
+// and pongs, such as to build network explorers with.
+type Summary struct {
+	// Peers is the number of peers the peer is connected to.
+	Peers int `json:"peers"`
+
+	// Height and Head are the height and head of the peer's application state. Zero
+	// and empty should its application not provide them.
+	Height uint64 `json:"height"`
+	Head   []byte `json:"head,omitempty"`
+
+	// ReceivedAt is when the summary was received.
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// summary returns the summary of our state piggybacked onto our pings and pongs. Nil
+// should we not share a summary.
+func (n *Network) summary() *protobuf.Summary {
+	if n.Summarize == nil {
+		return nil
+	}
+
+	height, head := n.Summarize()
+
+	var peers uint32
+	n.Peers.Range(func(key, value interface{}) bool {
+		peers++
+		return true
+	})
+
+	return &protobuf.Summary{Peers: peers, Height: height, Head: head}
+}
+
+func (n *Network) summaryInterval() time.Duration {
+	if n.SummaryInterval <= 0 {
+		return DefaultSummaryInterval
+	}
+	return n.SummaryInterval
+}
+
+// heartbeat pings all peers, pushing them our latest summary.
+func (n *Network) heartbeat() {
+	n.Broadcast(n.NewPing())
+}
+
+// NewPing returns a ping timestamped with our clock, carrying a summary of our state
+// should we share one, our config fingerprint, our deprecations and our zone.
+func (n *Network) NewPing() *protobuf.Ping {
+	return &protobuf.Ping{Timestamp: n.clock().Now().UnixNano(), Summary: n.summary(), ConfigFingerprint: n.ConfigFingerprint, Deprecations: n.advertisedDeprecations(), Zone: n.Zone}
+}
+
+// NewPong returns a pong responding to a ping, carrying a summary of our state should
+// we share one, our config fingerprint, our deprecations and our zone.
+func (n *Network) NewPong(ping *protobuf.Ping) *protobuf.Pong {
+	return &protobuf.Pong{PingTimestamp: ping.Timestamp, Timestamp: n.clock().Now().UnixNano(), Summary: n.summary(), ConfigFingerprint: n.ConfigFingerprint, Deprecations: n.advertisedDeprecations(), Zone: n.Zone}
+}
+
+// observeSummary records a summary the peer piggybacked onto a ping or pong.
+func (c *PeerClient) observeSummary(summary *protobuf.Summary) {
+	if summary == nil {
+		return
+	}
+
+	c.summary.Store(Summary{
+		Peers:      int(summary.Peers),
+		Height:     summary.Height,
+		Head:       summary.Head,
+		ReceivedAt: c.Network.clock().Now(),
+	})
+}
+
+// Summary returns the summary the peer last piggybacked onto its pings or pongs. The
+// second returning parameter is false should the peer have shared none.
+func (c *PeerClient) Summary() (Summary, bool) {
+	summary, ok := c.summary.Load().(Summary)
+	return summary, ok
+}
+
+// NeighborSummaries returns the summaries last shared by each connected peer which
+// shares them, by their addresses.
+func (n *Network) NeighborSummaries() map[string]Summary {
+	summaries := make(map[string]Summary)
+
+	n.Peers.Range(func(key, value interface{}) bool {
+		client := value.(*PeerClient)
+
+		if summary, ok := client.Summary(); ok {
+			summaries[client.Address()] = summary
+		}
+
+		return true
+	})
+
+	return summaries
+}
@@ -0,0 +1,117 @@
+package network_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func buildSummarizingNode(t *testing.T, port uint16, height uint64) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+	builder.SetSummaryFunc(func() (uint64, []byte) { return height, []byte("head") })
+	builder.AddPlugin(new(discovery.Plugin))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func waitForSummary(t *testing.T, node *network.Network, address string) network.Summary {
+	deadline := time.Now().Add(3 * time.Second)
+
+	for {
+		if summary, ok := node.NeighborSummaries()[address]; ok {
+			return summary
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the summary of %s", address)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	alice := buildSummarizingNode(t, 345, 7)
+	bob := buildSummarizingNode(t, 346, 9)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(alice.NewPing()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob learns of alice's summary from her ping, and alice of bob's from his pong.
+	if summary := waitForSummary(t, bob, alice.Address); summary.Height != 7 || string(summary.Head) != "head" {
+		t.Fatalf("unexpected summary of alice %+v", summary)
+	}
+
+	summary := waitForSummary(t, alice, bob.Address)
+	if summary.Height != 9 || summary.Peers != 1 {
+		t.Fatalf("unexpected summary of bob %+v", summary)
+	}
+}
+
+func TestSummaryHeartbeat(t *testing.T) {
+	var height uint64 = 1
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", 380))
+	builder.SetSummaryFunc(func() (uint64, []byte) { return atomic.LoadUint64(&height), nil })
+	builder.SetSummaryInterval(20 * time.Millisecond)
+	builder.AddPlugin(new(discovery.Plugin))
+
+	alice, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := alice.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer alice.Close()
+
+	bob := buildSummarizingNode(t, 381, 9)
+	defer bob.Close()
+
+	if _, err := alice.Client(bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForSummary(t, bob, alice.Address)
+
+	// Bob learns of alice's summary changing from her heartbeats, without her being
+	// pinged.
+	atomic.StoreUint64(&height, 2)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for bob.NeighborSummaries()[alice.Address].Height != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for alice's heartbeat to carry her latest summary")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,36 @@
+package network
+
+import "github.com/perlin-network/noise/peer"
+
+// TagPeer sets the weight of a tag on a peer, marking how important the peer is.
+// Heavier peers are preferred to be kept connected to.
+func (n *Network) TagPeer(id peer.ID, tag string, weight int) {
+	n.Peerstore.Tag(id.PublicKeyHex(), tag, weight)
+}
+
+// UntagPeer removes a tag from a peer.
+func (n *Network) UntagPeer(id peer.ID, tag string) {
+	n.Peerstore.Untag(id.PublicKeyHex(), tag)
+}
+
+// PeerWeight returns the sum of the weights of all tags on a peer.
+func (n *Network) PeerWeight(id peer.ID) int {
+	return n.Peerstore.Weight(id.PublicKeyHex())
+}
+
+// ProtectPeer protects a peer on behalf of a tag from being disconnected, evicted from
+// the routing table or rate limited.
+func (n *Network) ProtectPeer(id peer.ID, tag string) {
+	n.Peerstore.Protect(id.PublicKeyHex(), tag)
+}
+
+// UnprotectPeer removes the protection of a peer on behalf of a tag, and returns true
+// should the peer still be protected by other tags.
+func (n *Network) UnprotectPeer(id peer.ID, tag string) bool {
+	return n.Peerstore.Unprotect(id.PublicKeyHex(), tag)
+}
+
+// IsProtected returns true should a peer be protected.
+func (n *Network) IsProtected(id peer.ID) bool {
+	return n.Peerstore.Protected(id.PublicKeyHex())
+}
@@ -0,0 +1,116 @@
+package network
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// tapBufferSize is the number of messages a tap buffers before dropping messages.
+const tapBufferSize = 1024
+
+// TapFilter selects which messages are copied to a tap. Empty fields match all messages.
+type TapFilter struct {
+	// Types are the type URLs or fully-qualified names of messages to match, e.g.
+	// "protobuf.Ping" or "type.googleapis.com/protobuf.Ping".
+	Types []string
+
+	// Addresses are the addresses of remote peers whose messages are to be matched.
+	Addresses []string
+
+	// Inbound and Outbound restrict the tap to messages received or sent. Should both
+	// be false, messages in both directions are matched.
+	Inbound  bool
+	Outbound bool
+}
+
+// TappedMessage is a copy of a message received from or sent to a peer.
+type TappedMessage struct {
+	Outbound bool
+	Address  string
+	Message  *protobuf.Message
+}
+
+type tap struct {
+	filter TapFilter
+	ch     chan *TappedMessage
+}
+
+func (t *tap) matches(outbound bool, address string, msg *protobuf.Message) bool {
+	if t.filter.Inbound != t.filter.Outbound && t.filter.Outbound != outbound {
+		return false
+	}
+
+	if len(t.filter.Addresses) > 0 {
+		matched := false
+		for _, a := range t.filter.Addresses {
+			if a == address {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(t.filter.Types) > 0 {
+		if msg.Message == nil {
+			return false
+		}
+
+		matched := false
+		for _, ty := range t.filter.Types {
+			if msg.Message.TypeUrl == ty || strings.HasSuffix(msg.Message.TypeUrl, "/"+ty) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Tap returns a channel of copies of messages received and sent by the network which
+// match a filter, alongside a function which closes the tap. Messages are dropped
+// should the channel not be drained fast enough, such that taps never affect the
+// processing of messages.
+func (n *Network) Tap(filter TapFilter) (<-chan *TappedMessage, func()) {
+	t := &tap{filter: filter, ch: make(chan *TappedMessage, tapBufferSize)}
+
+	n.tapsMutex.Lock()
+	if n.taps == nil {
+		n.taps = make(map[*tap]struct{})
+	}
+	n.taps[t] = struct{}{}
+	n.tapsMutex.Unlock()
+
+	return t.ch, func() {
+		n.tapsMutex.Lock()
+		defer n.tapsMutex.Unlock()
+
+		if _, exists := n.taps[t]; exists {
+			delete(n.taps, t)
+			close(t.ch)
+		}
+	}
+}
+
+// tap copies a message to all taps whose filters match.
+func (n *Network) tap(outbound bool, address string, msg *protobuf.Message) {
+	n.tapsMutex.RLock()
+	defer n.tapsMutex.RUnlock()
+
+	for t := range n.taps {
+		if t.matches(outbound, address, msg) {
+			select {
+			case t.ch <- &TappedMessage{Outbound: outbound, Address: address, Message: proto.Clone(msg).(*protobuf.Message)}:
+			default:
+			}
+		}
+	}
+}
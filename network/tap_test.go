@@ -0,0 +1,44 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func TestTap(t *testing.T) {
+	alice := buildNode(t, 13020, new(discovery.Plugin))
+	bob := buildNode(t, 13021, new(discovery.Plugin))
+
+	defer alice.Close()
+	defer bob.Close()
+
+	pings, closePings := bob.Tap(network.TapFilter{Types: []string{"protobuf.Ping"}, Inbound: true})
+	defer closePings()
+
+	pongs, closePongs := bob.Tap(network.TapFilter{Types: []string{"protobuf.Pong"}, Outbound: true, Addresses: []string{alice.Address}})
+	defer closePongs()
+
+	alice.Bootstrap(bob.Address)
+
+	for _, tap := range []<-chan *network.TappedMessage{pings, pongs} {
+		select {
+		case tapped := <-tap:
+			if tapped.Message.Sender == nil {
+				t.Fatal("expected tapped message to have a sender")
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for tapped message")
+		}
+	}
+
+	select {
+	case tapped := <-pings:
+		if tapped.Outbound {
+			t.Fatal("expected tap to only match inbound messages")
+		}
+	default:
+	}
+}
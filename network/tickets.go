@@ -0,0 +1,163 @@
+package network
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// ticketKeySize is the size of the key session tickets are authenticated with.
+const ticketKeySize = 32
+
+// sessionTickets issues session tickets to peers whose identities were verified, and
+// holds the tickets peers issued to us.
+type sessionTickets struct {
+	once sync.Once
+	key  []byte
+	err  error
+
+	// held are the tickets peers issued to us, by the peers' addresses.
+	held sync.Map
+}
+
+// authenticate returns the code authenticating a ticket issued to a peer at an address
+// within a network, which expires at a time. The key is generated upon first use, such
+// that tickets do not survive restarts.
+func (t *sessionTickets) authenticate(networkID string, id peer.ID, expires int64) ([]byte, error) {
+	t.once.Do(func() {
+		t.key = make([]byte, ticketKeySize)
+		_, t.err = rand.Read(t.key)
+	})
+
+	if t.err != nil {
+		return nil, errors.Wrap(t.err, "failed to generate session ticket key")
+	}
+
+	const UINT64_SIZE = 8
+
+	expiry := make([]byte, UINT64_SIZE)
+	binary.LittleEndian.PutUint64(expiry, uint64(expires))
+
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write(expiry)
+	mac.Write(serializeMessage((*protobuf.ID)(&id), []byte(networkID)))
+
+	return append(expiry, mac.Sum(nil)...), nil
+}
+
+// issueTicket issues a session ticket to a peer whose identity was verified, should
+// the network issue tickets.
+func (n *Network) issueTicket(client *PeerClient) {
+	if n.TicketLifetime <= 0 {
+		return
+	}
+
+	expires := n.clock().Now().Add(n.TicketLifetime).UnixNano()
+
+	ticket, err := n.tickets.authenticate(n.NetworkID, peer.ID{Address: client.Address(), PublicKey: client.ID().PublicKey}, expires)
+	if err != nil {
+		glog.Warning(err)
+		return
+	}
+
+	if _, err := client.Tell(&protobuf.SessionTicket{Ticket: ticket, Expires: expires}); err != nil {
+		glog.Warningf("Failed to issue session ticket to %s [err=%s]", client.Address(), err)
+	}
+}
+
+// redeemTicket returns true should a message present a valid session ticket issued to
+// its sender at an address, sparing the sender from having its identity verified.
+func (n *Network) redeemTicket(address string, msg *protobuf.Message) bool {
+	if n.TicketLifetime <= 0 {
+		return false
+	}
+
+	ticket := msg.Ticket
+
+	// Peers may present their ticket in a message of its own instead.
+	if len(ticket) == 0 && ptypes.Is(msg.Message, (*protobuf.ResumeSession)(nil)) {
+		var resume protobuf.ResumeSession
+		if err := ptypes.UnmarshalAny(msg.Message, &resume); err != nil {
+			return false
+		}
+		ticket = resume.Ticket
+	}
+
+	if len(ticket) < 8 {
+		return false
+	}
+
+	expires := int64(binary.LittleEndian.Uint64(ticket))
+	if n.clock().Now().UnixNano() >= expires {
+		return false
+	}
+
+	expected, err := n.tickets.authenticate(n.NetworkID, peer.ID{Address: address, PublicKey: msg.Sender.PublicKey}, expires)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(ticket, expected)
+}
+
+// holdTicket keeps a session ticket a peer issued to us, to be presented upon
+// reconnecting to it.
+func (n *Network) holdTicket(address string, ticket *protobuf.SessionTicket) {
+	n.tickets.held.Store(address, ticket)
+
+	// Peers only issue tickets once they identified us, so stop presenting theirs.
+	if state, established := n.Connections.Load(address); established {
+		state.(*ConnState).ticket.Store((*protobuf.SessionTicket)(nil))
+	}
+}
+
+// presentTicket presents the unexpired session ticket the peer at an address issued
+// to us over a connection to it. As the peer identifies us by whichever envelope it
+// receives first, the ticket is carried by each envelope written over the connection
+// until the peer issues us a fresh one.
+func (n *Network) presentTicket(address string, state *ConnState) {
+	held, exists := n.tickets.held.Load(address)
+	if !exists {
+		return
+	}
+
+	ticket := held.(*protobuf.SessionTicket)
+
+	if n.clock().Now().UnixNano() >= ticket.Expires {
+		n.tickets.held.Delete(address)
+		return
+	}
+
+	state.ticket.Store(ticket)
+}
+
+// presentedTicket returns the session ticket presented in envelopes written over the
+// connection at a time. Nil if none is presented, or should it have expired.
+func (s *ConnState) presentedTicket(now time.Time) []byte {
+	ticket, _ := s.ticket.Load().(*protobuf.SessionTicket)
+	if ticket == nil || now.UnixNano() >= ticket.Expires {
+		return nil
+	}
+
+	return ticket.Ticket
+}
+
+// SessionTicket returns when the session ticket the peer at an address issued to us
+// expires. False if we hold no ticket from the peer.
+func (n *Network) SessionTicket(address string) (time.Time, bool) {
+	held, exists := n.tickets.held.Load(address)
+	if !exists {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, held.(*protobuf.SessionTicket).Expires), true
+}
@@ -0,0 +1,98 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func buildTicketingNode(t *testing.T, port uint16) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+	builder.SetTicketLifetime(time.Minute)
+	builder.AddPlugin(new(discovery.Plugin))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func waitFor(t *testing.T, description string, condition func() bool) {
+	deadline := time.Now().Add(3 * time.Second)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", description)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSessionTicketResume(t *testing.T) {
+	alice := buildTicketingNode(t, 356)
+	bob := buildTicketingNode(t, 357)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	client, err := bob.Client(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(alice.NewPing()); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, "tickets to be issued", func() bool {
+		_, bobHolds := bob.SessionTicket(alice.Address)
+		_, aliceHolds := alice.SessionTicket(bob.Address)
+		return bobHolds && aliceHolds
+	})
+
+	if expires, _ := bob.SessionTicket(alice.Address); expires.Before(time.Now()) {
+		t.Fatalf("expected the ticket to expire in the future, got %s", expires)
+	}
+
+	if resumed := alice.Stats().ResumedSessions; resumed != 0 {
+		t.Fatalf("expected no sessions to be resumed upon first connecting, got %d", resumed)
+	}
+
+	client.Close()
+
+	waitFor(t, "peers to disconnect", func() bool {
+		_, bobConnected := bob.Peers.Load(alice.Address)
+		_, aliceConnected := alice.Peers.Load(bob.Address)
+		return !bobConnected && !aliceConnected
+	})
+
+	if client, err = bob.Client(alice.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	// The ticket is presented by whichever envelope alice receives first.
+	if _, err := client.Tell(alice.NewPing()); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, "the session to be resumed", func() bool {
+		return alice.Stats().ResumedSessions == 1 && bob.Stats().ResumedSessions == 1
+	})
+
+	waitFor(t, "bob to be identified", func() bool {
+		peer, connected := alice.Peers.Load(bob.Address)
+		return connected && peer.(*network.PeerClient).IncomingReady()
+	})
+}
@@ -0,0 +1,24 @@
+package network
+
+import "time"
+
+// TimeEstimator estimates the offset of the time agreed upon by peers relative to our
+// own clock.
+type TimeEstimator interface {
+	// Offset returns the estimated offset, or false should there be too few samples.
+	Offset() (time.Duration, bool)
+}
+
+// NetworkTime returns the current time adjusted by the offset estimated by the
+// networks time estimator, falling back to our own clock.
+func (n *Network) NetworkTime() time.Time {
+	now := n.clock().Now()
+
+	if n.TimeEstimator != nil {
+		if offset, ok := n.TimeEstimator.Offset(); ok {
+			return now.Add(offset)
+		}
+	}
+
+	return now
+}
@@ -0,0 +1,114 @@
+package topology
+
+import (
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// Snapshot returns this nodes view of the topology: its open connections, and the
+// contents of its routing table should the discovery plugin be registered.
+func Snapshot(net *network.Network) *Graph {
+	graph := NewGraph()
+	graph.AddNode(net.ID, true)
+
+	net.Peers.Range(func(key, value interface{}) bool {
+		if client := value.(*network.PeerClient); client.ID() != nil {
+			graph.AddEdge(net.ID, *client.ID(), true)
+		}
+		return true
+	})
+
+	for _, id := range neighbors(net) {
+		graph.AddEdge(net.ID, id, false)
+	}
+
+	return graph
+}
+
+// Collect returns this nodes view of the topology alongside the neighbor lists of all
+// peers in its routing table. Neighbor lists are gossiped by asking each peer for the
+// peers closest to itself, waiting at most timeout for a response.
+func Collect(net *network.Network, timeout time.Duration) *Graph {
+	graph := Snapshot(net)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, id := range neighbors(net) {
+		wg.Add(1)
+
+		go func(id peer.ID) {
+			defer wg.Done()
+
+			peers, err := queryNeighbors(net, id, timeout)
+			if err != nil {
+				return
+			}
+
+			mutex.Lock()
+			for _, neighbor := range peers {
+				graph.AddEdge(id, neighbor, false)
+			}
+			mutex.Unlock()
+		}(id)
+	}
+
+	wg.Wait()
+
+	return graph
+}
+
+// neighbors returns the peers within this nodes routing table, excluding itself.
+func neighbors(net *network.Network) (peers []peer.ID) {
+	plugin, exists := net.Plugin(discovery.PluginID)
+	if !exists {
+		return
+	}
+
+	for _, id := range plugin.(*discovery.Plugin).Routes.GetPeers() {
+		if !id.Equals(net.ID) {
+			peers = append(peers, id)
+		}
+	}
+
+	return
+}
+
+// queryNeighbors asks a peer for the peers closest to itself.
+func queryNeighbors(net *network.Network, id peer.ID, timeout time.Duration) ([]peer.ID, error) {
+	client, err := net.Client(id.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	target := protobuf.ID(id)
+
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.LookupNodeRequest{Target: &target})
+	request.SetTimeout(timeout)
+
+	response, err := client.Request(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var peers []peer.ID
+
+	if response, ok := response.(*protobuf.LookupNodeResponse); ok {
+		for _, neighbor := range response.Peers {
+			if len(peers) == dht.BucketSize {
+				break
+			}
+			peers = append(peers, peer.ID(*neighbor))
+		}
+	}
+
+	return peers, nil
+}
@@ -0,0 +1,124 @@
+package topology
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteJSON writes the graph as JSON.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph noise {"); err != nil {
+		return err
+	}
+
+	for _, node := range g.Nodes {
+		attrs := "label=" + strconv.Quote(node.Address)
+		if node.Self {
+			attrs += ", style=filled"
+		}
+
+		if _, err := fmt.Fprintf(w, "\t%s [%s];\n", strconv.Quote(node.ID), attrs); err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range g.Edges {
+		attrs := ""
+		if !edge.Connected {
+			attrs = " [style=dashed]"
+		}
+
+		if _, err := fmt.Fprintf(w, "\t%s -> %s%s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To), attrs); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+// WriteGraphML writes the graph as GraphML.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphMLDocument{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "address", For: "node", Name: "address", Type: "string"},
+			{ID: "self", For: "node", Name: "self", Type: "boolean"},
+			{ID: "connected", For: "edge", Name: "connected", Type: "boolean"},
+		},
+	}
+
+	doc.Graph.ID = "noise"
+	doc.Graph.EdgeDefault = "directed"
+
+	for _, node := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: node.ID,
+			Data: []graphMLData{
+				{Key: "address", Value: node.Address},
+				{Key: "self", Value: strconv.FormatBool(node.Self)},
+			},
+		})
+	}
+
+	for _, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: edge.From,
+			Target: edge.To,
+			Data:   []graphMLData{{Key: "connected", Value: strconv.FormatBool(edge.Connected)}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(doc)
+}
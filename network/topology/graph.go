@@ -0,0 +1,134 @@
+package topology
+
+import (
+	"sort"
+
+	"github.com/perlin-network/noise/peer"
+)
+
+// Node is a peer within the overlay topology, identified by its base58-encoded peer ID.
+type Node struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	Self    bool   `json:"self,omitempty"`
+}
+
+// Edge denotes that a peer knows of, or is connected to another peer.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Connected is true should the edge be an open connection rather than a
+	// routing table entry.
+	Connected bool `json:"connected,omitempty"`
+}
+
+// Graph is a view of the overlay topology.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	nodes map[string]int
+	edges map[[2]string]int
+}
+
+// NewGraph creates an empty graph.
+func NewGraph() *Graph {
+	return &Graph{
+		nodes: make(map[string]int),
+		edges: make(map[[2]string]int),
+	}
+}
+
+// AddNode adds a peer to the graph should it not already exist, and returns its ID.
+func (g *Graph) AddNode(id peer.ID, self bool) string {
+	key := id.String()
+
+	if index, exists := g.nodes[key]; exists {
+		g.Nodes[index].Self = g.Nodes[index].Self || self
+		return key
+	}
+
+	g.nodes[key] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, Node{ID: key, Address: id.Address, Self: self})
+
+	return key
+}
+
+// AddEdge adds an edge between two peers to the graph, adding the peers should they
+// not already exist. Self-edges are ignored.
+func (g *Graph) AddEdge(from peer.ID, to peer.ID, connected bool) {
+	if from.Equals(to) {
+		return
+	}
+
+	key := [2]string{g.AddNode(from, false), g.AddNode(to, false)}
+
+	if index, exists := g.edges[key]; exists {
+		g.Edges[index].Connected = g.Edges[index].Connected || connected
+		return
+	}
+
+	g.edges[key] = len(g.Edges)
+	g.Edges = append(g.Edges, Edge{From: key[0], To: key[1], Connected: connected})
+}
+
+// Degrees returns the number of edges incident to each peer in the graph, which is
+// useful for spotting hubs.
+func (g *Graph) Degrees() map[string]int {
+	degrees := make(map[string]int)
+	for _, node := range g.Nodes {
+		degrees[node.ID] = 0
+	}
+
+	for _, edge := range g.Edges {
+		degrees[edge.From]++
+		degrees[edge.To]++
+	}
+
+	return degrees
+}
+
+// Components returns the IDs of peers within each connected component of the graph,
+// treating edges as undirected. More than one component indicates a partition.
+func (g *Graph) Components() [][]string {
+	adjacency := make(map[string][]string)
+	for _, edge := range g.Edges {
+		adjacency[edge.From] = append(adjacency[edge.From], edge.To)
+		adjacency[edge.To] = append(adjacency[edge.To], edge.From)
+	}
+
+	visited := make(map[string]bool)
+
+	var components [][]string
+
+	for _, node := range g.Nodes {
+		if visited[node.ID] {
+			continue
+		}
+
+		var component []string
+
+		queue := []string{node.ID}
+		visited[node.ID] = true
+
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			component = append(component, current)
+
+			for _, neighbor := range adjacency[current] {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+
+		sort.Strings(component)
+		components = append(components, component)
+	}
+
+	return components
+}
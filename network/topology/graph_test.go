@@ -0,0 +1,66 @@
+package topology
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/perlin-network/noise/peer"
+)
+
+func TestGraph(t *testing.T) {
+	a := peer.CreateID("tcp://127.0.0.1:3000", []byte("a"))
+	b := peer.CreateID("tcp://127.0.0.1:3001", []byte("b"))
+	c := peer.CreateID("tcp://127.0.0.1:3002", []byte("c"))
+	d := peer.CreateID("tcp://127.0.0.1:3003", []byte("d"))
+
+	graph := NewGraph()
+	graph.AddNode(a, true)
+	graph.AddEdge(a, b, true)
+	graph.AddEdge(a, b, false)
+	graph.AddEdge(b, c, false)
+	graph.AddEdge(c, c, false)
+	graph.AddNode(d, false)
+
+	if len(graph.Nodes) != 4 || len(graph.Edges) != 2 {
+		t.Fatalf("expected 4 nodes and 2 edges, got %d nodes and %d edges", len(graph.Nodes), len(graph.Edges))
+	}
+
+	if !graph.Edges[0].Connected {
+		t.Fatal("expected duplicate edge to remain connected")
+	}
+
+	if degrees := graph.Degrees(); degrees[b.String()] != 2 || degrees[d.String()] != 0 {
+		t.Fatalf("unexpected degrees %v", degrees)
+	}
+
+	if components := graph.Components(); len(components) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(components))
+	}
+
+	var buffer bytes.Buffer
+
+	if err := graph.WriteDOT(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buffer.String(), "[style=dashed]") {
+		t.Fatal("expected routing table edges to be dashed")
+	}
+
+	buffer.Reset()
+
+	if err := graph.WriteGraphML(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc graphMLDocument
+	if err := xml.Unmarshal(buffer.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Graph.Nodes) != 4 || len(doc.Graph.Edges) != 2 {
+		t.Fatal("GraphML does not contain all nodes and edges")
+	}
+}
@@ -0,0 +1,92 @@
+package tor
+
+import (
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HiddenService is an onion service published through a Tor control port.
+type HiddenService struct {
+	// ServiceID is the onion address of the service without its .onion suffix.
+	ServiceID string
+
+	// PrivateKey of the service, e.g. "ED25519-V3:<base64>", which may be passed to
+	// PublishHiddenService to republish the service under the same onion address.
+	PrivateKey string
+
+	conn *textproto.Conn
+}
+
+// Onion returns the onion host of the service.
+func (s *HiddenService) Onion() string {
+	return s.ServiceID + ".onion"
+}
+
+// PublishHiddenService publishes an onion service through the Tor control port at
+// control, forwarding virtualPort of the service to localPort on the loopback
+// interface. Should key be empty, a new key is generated. The service is removed once
+// closed.
+func PublishHiddenService(control string, password string, key string, virtualPort uint16, localPort uint16) (*HiddenService, error) {
+	conn, err := textproto.Dial("tcp", control)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to tor control port")
+	}
+
+	if _, err := command(conn, "AUTHENTICATE %s", strconv.Quote(password)); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to authenticate with tor control port")
+	}
+
+	if len(key) == 0 {
+		key = "NEW:ED25519-V3"
+	}
+
+	reply, err := command(conn, "ADD_ONION %s Port=%d,127.0.0.1:%d", key, virtualPort, localPort)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to publish onion service")
+	}
+
+	service := &HiddenService{PrivateKey: key, conn: conn}
+
+	for _, line := range strings.Split(reply, "\n") {
+		if strings.HasPrefix(line, "ServiceID=") {
+			service.ServiceID = strings.TrimPrefix(line, "ServiceID=")
+		} else if strings.HasPrefix(line, "PrivateKey=") {
+			service.PrivateKey = strings.TrimPrefix(line, "PrivateKey=")
+		}
+	}
+
+	if len(service.ServiceID) == 0 {
+		conn.Close()
+		return nil, errors.New("tor did not reply with the onion address of the service")
+	}
+
+	return service, nil
+}
+
+// Close removes the onion service.
+func (s *HiddenService) Close() error {
+	defer s.conn.Close()
+
+	_, err := command(s.conn, "DEL_ONION %s", s.ServiceID)
+	return err
+}
+
+// command sends a command over a Tor control connection, and returns its reply should
+// it succeed.
+func command(conn *textproto.Conn, format string, args ...interface{}) (string, error) {
+	id, err := conn.Cmd(format, args...)
+	if err != nil {
+		return "", err
+	}
+
+	conn.StartResponse(id)
+	defer conn.EndResponse(id)
+
+	_, reply, err := conn.ReadResponse(250)
+	return reply, err
+}
@@ -0,0 +1,75 @@
+package tor
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+// serveControl serves a fake Tor control port, recording commands received.
+func serveControl(t *testing.T, commands chan<- string) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		defer ln.Close()
+
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			line = strings.TrimSpace(line)
+			commands <- line
+
+			if strings.HasPrefix(line, "ADD_ONION") {
+				conn.Write([]byte("250-ServiceID=abcdefghij\r\n250-PrivateKey=ED25519-V3:key\r\n250 OK\r\n"))
+			} else {
+				conn.Write([]byte("250 OK\r\n"))
+			}
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestPublishHiddenService(t *testing.T) {
+	commands := make(chan string, 8)
+
+	service, err := PublishHiddenService(serveControl(t, commands), "secret", "", 3000, 4000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if service.Onion() != "abcdefghij.onion" || service.PrivateKey != "ED25519-V3:key" {
+		t.Fatalf("unexpected service %+v", service)
+	}
+
+	if err := service.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`AUTHENTICATE "secret"`,
+		"ADD_ONION NEW:ED25519-V3 Port=3000,127.0.0.1:4000",
+		"DEL_ONION abcdefghij",
+	}
+
+	for _, command := range expected {
+		if got := <-commands; got != command {
+			t.Fatalf("expected command %q, got %q", command, got)
+		}
+	}
+}
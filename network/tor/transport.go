@@ -0,0 +1,119 @@
+// Package tor provides a transport dialing onion services through a Tor SOCKS proxy,
+// and listening as an onion service published through a Tor control port, for
+// censorship-resistant deployments.
+//
+// Nodes use tor://<service id>.onion:<port> addresses. As onion addresses may not be
+// verified against the addresses peers are observed connecting from, discovery must
+// be configured with AllowUnverifiedAddresses.
+package tor
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/pkg/errors"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	// DefaultProxyAddress is the default address of the Tor SOCKS proxy.
+	DefaultProxyAddress = "127.0.0.1:9050"
+
+	// DefaultControlAddress is the default address of the Tor control port.
+	DefaultControlAddress = "127.0.0.1:9051"
+)
+
+// Transport makes connections over Tor.
+type Transport struct {
+	// ProxyAddress is the address of the Tor SOCKS proxy.
+	ProxyAddress string
+
+	// ControlAddress is the address of the Tor control port, and ControlPassword the
+	// password it is authenticated with. Empty if onion services are published by
+	// other means, e.g. the Tor configuration file.
+	ControlAddress  string
+	ControlPassword string
+
+	// ServiceKey is the private key of the onion service listened as, which must
+	// match the onion address of the listening node.
+	ServiceKey string
+
+	// LocalPort is the port listened on for connections forwarded by Tor. Zero if the
+	// same port as the onion service.
+	LocalPort uint16
+}
+
+// New creates a transport using the default Tor proxy and control port.
+func New(serviceKey string) *Transport {
+	return &Transport{
+		ProxyAddress:   DefaultProxyAddress,
+		ControlAddress: DefaultControlAddress,
+		ServiceKey:     serviceKey,
+	}
+}
+
+// Register registers a transport for tor:// addresses.
+func Register(transport *Transport) {
+	network.RegisterTransport("tor", transport)
+}
+
+// Dial implements network.Transport.
+func (t *Transport) Dial(addr *network.AddressInfo) (net.Conn, error) {
+	address := t.ProxyAddress
+	if len(address) == 0 {
+		address = DefaultProxyAddress
+	}
+
+	dialer, err := proxy.SOCKS5("tcp", address, nil, proxy.Direct)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to tor proxy")
+	}
+
+	return dialer.Dial("tcp", addr.HostPort())
+}
+
+// Listen implements network.Transport, listening on the loopback interface for
+// connections forwarded by Tor, and publishing the onion service should a control
+// port be configured.
+func (t *Transport) Listen(addr *network.AddressInfo) (net.Listener, error) {
+	localPort := t.LocalPort
+	if localPort == 0 {
+		localPort = addr.Port
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(localPort))))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.ControlAddress) == 0 {
+		return ln, nil
+	}
+
+	service, err := PublishHiddenService(t.ControlAddress, t.ControlPassword, t.ServiceKey, addr.Port, localPort)
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+
+	if network.IsOnionHost(addr.Host) && service.Onion() != addr.Host {
+		service.Close()
+		ln.Close()
+		return nil, errors.Errorf("onion service was published as %s, but node listens as %s", service.Onion(), addr.Host)
+	}
+
+	return &listener{Listener: ln, service: service}, nil
+}
+
+// listener removes its onion service once closed.
+type listener struct {
+	net.Listener
+
+	service *HiddenService
+}
+
+func (l *listener) Close() error {
+	l.service.Close()
+	return l.Listener.Close()
+}
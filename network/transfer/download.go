@@ -0,0 +1,412 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultConcurrency is the number of chunks requested at once from each peer by default.
+	DefaultConcurrency = 4
+
+	// DefaultTimeout is how long a peer is given to respond to a request by default.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Progress is the progress of a download.
+type Progress struct {
+	Verified int
+	Chunks   int
+
+	Bytes int64
+	Size  int64
+}
+
+// Done returns true should all chunks have been verified.
+func (p Progress) Done() bool {
+	return p.Chunks > 0 && p.Verified == p.Chunks
+}
+
+// Download downloads content by its name from peers into a writer. Should a download
+// fail, running it again resumes from the chunks which were already verified.
+type Download struct {
+	Name string
+
+	// Root is the expected hash of the content, as returned by Root. Nil if the first
+	// manifest received from a peer is trusted.
+	Root []byte
+
+	// Existing is content which was already partially downloaded, e.g. before a restart.
+	// Chunks within it which match the manifest are not downloaded again. Nil if none.
+	Existing io.ReaderAt
+
+	// Concurrency is the number of chunks requested at once from each peer, and Timeout
+	// how long a peer is given to respond. Zero if the defaults.
+	Concurrency int
+	Timeout     time.Duration
+
+	// OnProgress is called, possibly concurrently, every time a chunk is verified. Nil
+	// if unused.
+	OnProgress func(Progress)
+
+	net    *network.Network
+	writer io.WriterAt
+
+	mutex    sync.Mutex
+	manifest *protobuf.TransferManifest
+	verified []bool
+	progress Progress
+}
+
+// NewDownload creates a download of content by its name into a writer.
+func NewDownload(net *network.Network, name string, writer io.WriterAt) *Download {
+	return &Download{Name: name, net: net, writer: writer}
+}
+
+// Root returns the hash of content described by a manifest, being the SHA-256 hash of
+// the concatenation of its size and chunk hashes.
+func Root(manifest *protobuf.TransferManifest) []byte {
+	hash := sha256.New()
+
+	var header [12]byte
+	for i := uint(0); i < 8; i++ {
+		header[i] = byte(manifest.Size >> (8 * i))
+	}
+	for i := uint(0); i < 4; i++ {
+		header[8+i] = byte(manifest.ChunkSize >> (8 * i))
+	}
+	hash.Write(header[:])
+
+	for _, chunkHash := range manifest.ChunkHashes {
+		hash.Write(chunkHash)
+	}
+
+	return hash.Sum(nil)
+}
+
+// Manifest returns the manifest of the content being downloaded. Nil should it not
+// have been received yet.
+func (d *Download) Manifest() *protobuf.TransferManifest {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.manifest
+}
+
+// Progress returns the progress of the download.
+func (d *Download) Progress() Progress {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.progress
+}
+
+func (d *Download) concurrency() int {
+	if d.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return d.Concurrency
+}
+
+func (d *Download) timeout() time.Duration {
+	if d.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return d.Timeout
+}
+
+func (d *Download) request(ctx context.Context, address string, message *rpc.Request) (interface{}, error) {
+	client, err := d.net.Client(address)
+	if err != nil {
+		return nil, err
+	}
+
+	message.SetTimeout(d.timeout())
+
+	return client.RequestWithContext(ctx, message)
+}
+
+// fetchManifest fetches the manifest of the content from the first peer which serves it.
+func (d *Download) fetchManifest(ctx context.Context, addresses []string) error {
+	for _, address := range addresses {
+		request := new(rpc.Request)
+		request.SetMessage(&protobuf.TransferManifestRequest{Name: d.Name})
+
+		response, err := d.request(ctx, address, request)
+		if err != nil {
+			glog.Warningf("transfer: failed to fetch manifest of %s from %s [err=%s]", d.Name, address, err)
+			continue
+		}
+
+		manifest, ok := response.(*protobuf.TransferManifest)
+		if !ok || !manifest.Found {
+			continue
+		}
+
+		if err := validateManifest(manifest); err != nil {
+			glog.Warningf("transfer: peer %s sent an invalid manifest of %s [err=%s]", address, d.Name, err)
+			continue
+		}
+
+		if d.Root != nil && !bytes.Equal(Root(manifest), d.Root) {
+			glog.Warningf("transfer: peer %s sent a manifest of %s not matching its root hash", address, d.Name)
+			continue
+		}
+
+		d.mutex.Lock()
+		d.manifest = manifest
+		d.verified = make([]bool, len(manifest.ChunkHashes))
+		d.progress = Progress{Chunks: len(manifest.ChunkHashes), Size: int64(manifest.Size)}
+		d.mutex.Unlock()
+
+		return nil
+	}
+
+	return errors.Errorf("no peer serves %s", d.Name)
+}
+
+func validateManifest(manifest *protobuf.TransferManifest) error {
+	if manifest.ChunkSize == 0 {
+		return errors.New("chunk size is zero")
+	}
+
+	chunks := (manifest.Size + uint64(manifest.ChunkSize) - 1) / uint64(manifest.ChunkSize)
+	if uint64(len(manifest.ChunkHashes)) != chunks {
+		return errors.Errorf("expected %d chunk hashes, got %d", chunks, len(manifest.ChunkHashes))
+	}
+
+	return nil
+}
+
+// chunk returns the offset and length of a chunk by its index.
+func (d *Download) chunk(index int) (int64, int) {
+	offset := int64(index) * int64(d.manifest.ChunkSize)
+	return offset, chunkLength(int64(d.manifest.Size), int(d.manifest.ChunkSize), offset)
+}
+
+// verify marks a chunk as verified should data match its hash, and writes it to the writer.
+func (d *Download) verify(index int, data []byte, write bool) bool {
+	d.mutex.Lock()
+
+	if _, length := d.chunk(index); len(data) != length {
+		d.mutex.Unlock()
+		return false
+	}
+
+	if hash := sha256.Sum256(data); !bytes.Equal(hash[:], d.manifest.ChunkHashes[index]) {
+		d.mutex.Unlock()
+		return false
+	}
+
+	offset, _ := d.chunk(index)
+	d.mutex.Unlock()
+
+	if write {
+		if _, err := d.writer.WriteAt(data, offset); err != nil {
+			glog.Warningf("transfer: failed to write chunk %d of %s [err=%s]", index, d.Name, err)
+			return false
+		}
+	}
+
+	d.mutex.Lock()
+	if !d.verified[index] {
+		d.verified[index] = true
+		d.progress.Verified++
+		d.progress.Bytes += int64(len(data))
+	}
+	progress := d.progress
+	d.mutex.Unlock()
+
+	if d.OnProgress != nil {
+		d.OnProgress(progress)
+	}
+
+	return true
+}
+
+// verifyExisting verifies chunks which were already downloaded into Existing.
+func (d *Download) verifyExisting() {
+	for index := range d.manifest.ChunkHashes {
+		if d.verified[index] {
+			continue
+		}
+
+		offset, length := d.chunk(index)
+
+		data := make([]byte, length)
+		if _, err := d.Existing.ReadAt(data, offset); err != nil && err != io.EOF {
+			continue
+		}
+
+		d.verify(index, data, false)
+	}
+}
+
+// Run downloads all chunks which have not yet been verified from peers by their
+// addresses concurrently, dropping peers which fail to respond or respond with invalid
+// chunks. It errors should all peers be dropped before the download completes.
+func (d *Download) Run(ctx context.Context, addresses ...string) error {
+	if len(addresses) == 0 {
+		return errors.New("no peers to download from")
+	}
+
+	if d.Manifest() == nil {
+		if err := d.fetchManifest(ctx, addresses); err != nil {
+			return err
+		}
+
+		if d.Existing != nil {
+			d.verifyExisting()
+		}
+	}
+
+	queue := newChunkQueue()
+
+	d.mutex.Lock()
+	for index, verified := range d.verified {
+		if !verified {
+			queue.push(index)
+		}
+	}
+	d.mutex.Unlock()
+
+	var wg sync.WaitGroup
+
+	for _, address := range addresses {
+		failed := make(chan struct{})
+		var once sync.Once
+
+		for i := 0; i < d.concurrency(); i++ {
+			wg.Add(1)
+
+			go func(address string) {
+				defer wg.Done()
+
+				for {
+					select {
+					case <-failed:
+						return
+					default:
+					}
+
+					index, ok := queue.pop()
+					if !ok {
+						return
+					}
+
+					if err := d.fetchChunk(ctx, address, index); err != nil {
+						queue.requeue(index)
+
+						once.Do(func() {
+							glog.Warningf("transfer: dropped peer %s from download of %s [err=%s]", address, d.Name, err)
+							close(failed)
+						})
+
+						return
+					}
+
+					queue.done()
+				}
+			}(address)
+		}
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if progress := d.Progress(); !progress.Done() {
+		return errors.Errorf("download of %s stopped with %d of %d chunks verified", d.Name, progress.Verified, progress.Chunks)
+	}
+
+	return nil
+}
+
+func (d *Download) fetchChunk(ctx context.Context, address string, index int) error {
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.TransferChunkRequest{Name: d.Name, Index: uint32(index)})
+
+	response, err := d.request(ctx, address, request)
+	if err != nil {
+		return err
+	}
+
+	chunk, ok := response.(*protobuf.TransferChunk)
+	if !ok || int(chunk.Index) != index || !d.verify(index, chunk.Data, true) {
+		return errors.Errorf("invalid chunk %d", index)
+	}
+
+	return nil
+}
+
+// chunkQueue is the queue of chunks left to download. Workers wait on chunks which
+// are in flight, as they are requeued should their peers fail.
+type chunkQueue struct {
+	mutex    sync.Mutex
+	cond     *sync.Cond
+	pending  []int
+	inflight int
+}
+
+func newChunkQueue() *chunkQueue {
+	q := new(chunkQueue)
+	q.cond = sync.NewCond(&q.mutex)
+	return q
+}
+
+func (q *chunkQueue) push(index int) {
+	q.mutex.Lock()
+	q.pending = append(q.pending, index)
+	q.mutex.Unlock()
+}
+
+// pop returns the next chunk to download, waiting should there be none pending but
+// some in flight. It returns false once there are no chunks left.
+func (q *chunkQueue) pop() (int, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for len(q.pending) == 0 {
+		if q.inflight == 0 {
+			return 0, false
+		}
+		q.cond.Wait()
+	}
+
+	index := q.pending[0]
+	q.pending = q.pending[1:]
+	q.inflight++
+
+	return index, true
+}
+
+// done marks a chunk which was in flight as downloaded.
+func (q *chunkQueue) done() {
+	q.mutex.Lock()
+	q.inflight--
+	q.mutex.Unlock()
+
+	q.cond.Broadcast()
+}
+
+// requeue returns a chunk which was in flight to the queue.
+func (q *chunkQueue) requeue(index int) {
+	q.mutex.Lock()
+	q.inflight--
+	q.pending = append(q.pending, index)
+	q.mutex.Unlock()
+
+	q.cond.Broadcast()
+}
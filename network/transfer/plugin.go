@@ -0,0 +1,144 @@
+// Package transfer transfers content between peers in fixed-size chunks verified by
+// their SHA-256 hashes. Downloads fetch chunks from multiple peers concurrently, and
+// may be resumed after a disconnect from the chunks which were already verified.
+package transfer
+
+import (
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// DefaultChunkSize is the size of chunks content is served in by default.
+const DefaultChunkSize = 256 * 1024
+
+// content is content served by its name.
+type content struct {
+	reader   io.ReaderAt
+	manifest *protobuf.TransferManifest
+}
+
+// Plugin serves content to, and downloads content from peers.
+type Plugin struct {
+	*network.Plugin
+
+	// ChunkSize is the size of chunks content is served in. Zero if the default.
+	ChunkSize int
+
+	mutex    sync.RWMutex
+	contents map[string]*content
+}
+
+var (
+	// PluginID to reference transfer plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// New creates a transfer plugin.
+func New() *Plugin {
+	return &Plugin{contents: make(map[string]*content)}
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	if err := net.RegisterMessages(new(protobuf.TransferManifestRequest), new(protobuf.TransferManifest), new(protobuf.TransferChunkRequest), new(protobuf.TransferChunk)); err != nil {
+		glog.Warningf("transfer: failed to register message types [err=%s]", err)
+	}
+}
+
+func (p *Plugin) chunkSize() int {
+	if p.ChunkSize <= 0 {
+		return DefaultChunkSize
+	}
+	return p.ChunkSize
+}
+
+// Serve serves size bytes of content read from reader to peers under a name, hashing
+// each of its chunks. It returns the manifest of the content.
+func (p *Plugin) Serve(name string, reader io.ReaderAt, size int64) (*protobuf.TransferManifest, error) {
+	chunkSize := p.chunkSize()
+
+	manifest := &protobuf.TransferManifest{
+		Name:      name,
+		Size:      uint64(size),
+		ChunkSize: uint32(chunkSize),
+		Found:     true,
+	}
+
+	buf := make([]byte, chunkSize)
+
+	for offset := int64(0); offset < size; offset += int64(chunkSize) {
+		chunk := buf[:chunkLength(size, chunkSize, offset)]
+
+		if _, err := reader.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, errors.Wrapf(err, "failed to read chunk at offset %d of %s", offset, name)
+		}
+
+		hash := sha256.Sum256(chunk)
+		manifest.ChunkHashes = append(manifest.ChunkHashes, hash[:])
+	}
+
+	p.mutex.Lock()
+	p.contents[name] = &content{reader: reader, manifest: manifest}
+	p.mutex.Unlock()
+
+	return manifest, nil
+}
+
+// Unserve stops serving content by its name.
+func (p *Plugin) Unserve(name string) {
+	p.mutex.Lock()
+	delete(p.contents, name)
+	p.mutex.Unlock()
+}
+
+func (p *Plugin) content(name string) (*content, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	c, exists := p.contents[name]
+	return c, exists
+}
+
+// Receive implements the plugin callback
+func (p *Plugin) Receive(ctx *network.PluginContext) error {
+	switch msg := ctx.Message().(type) {
+	case *protobuf.TransferManifestRequest:
+		c, exists := p.content(msg.Name)
+		if !exists {
+			return ctx.Reply(&protobuf.TransferManifest{Name: msg.Name})
+		}
+
+		return ctx.Reply(c.manifest)
+	case *protobuf.TransferChunkRequest:
+		c, exists := p.content(msg.Name)
+		if !exists || int(msg.Index) >= len(c.manifest.ChunkHashes) {
+			return errors.Errorf("peer %s requested unknown chunk %d of %s", ctx.Client().Address(), msg.Index, msg.Name)
+		}
+
+		size, chunkSize := int64(c.manifest.Size), int(c.manifest.ChunkSize)
+		offset := int64(msg.Index) * int64(chunkSize)
+
+		chunk := make([]byte, chunkLength(size, chunkSize, offset))
+		if _, err := c.reader.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return errors.Wrapf(err, "failed to read chunk %d of %s", msg.Index, msg.Name)
+		}
+
+		return ctx.Reply(&protobuf.TransferChunk{Name: msg.Name, Index: msg.Index, Data: chunk})
+	}
+
+	return nil
+}
+
+// chunkLength returns the length of the chunk at an offset into content of a size.
+func chunkLength(size int64, chunkSize int, offset int64) int {
+	if remaining := size - offset; remaining < int64(chunkSize) {
+		return int(remaining)
+	}
+	return chunkSize
+}
@@ -0,0 +1,198 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func buildNode(t *testing.T, port uint16) (*network.Network, *Plugin) {
+	plugin := New()
+	plugin.ChunkSize = 1024
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", port))
+	builder.AddPlugin(new(discovery.Plugin))
+	builder.AddPlugin(plugin)
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go node.Listen()
+	node.BlockUntilListening()
+
+	return node, plugin
+}
+
+// buffer is an in-memory io.WriterAt counting the number of writes made to it.
+type buffer struct {
+	sync.Mutex
+	data   []byte
+	writes int
+}
+
+func (b *buffer) WriteAt(p []byte, off int64) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	copy(b.data[off:], p)
+	b.writes++
+
+	return len(p), nil
+}
+
+func randomContent(size int) []byte {
+	content := make([]byte, size)
+	rand.Read(content)
+	return content
+}
+
+func TestDownloadFromMultiplePeers(t *testing.T) {
+	alice, aliceTransfer := buildNode(t, 13140)
+	bob, bobTransfer := buildNode(t, 13141)
+	carol, _ := buildNode(t, 13142)
+
+	defer alice.Close()
+	defer bob.Close()
+	defer carol.Close()
+
+	content := randomContent(10*1024 + 100)
+
+	manifest, err := aliceTransfer.Serve("snapshot", bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bobTransfer.Serve("snapshot", bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &buffer{data: make([]byte, len(content))}
+
+	download := NewDownload(carol, "snapshot", out)
+	download.Root = Root(manifest)
+
+	var mutex sync.Mutex
+	var last Progress
+
+	// Progress is reported concurrently, and hence possibly out of order.
+	download.OnProgress = func(progress Progress) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if progress.Bytes >= last.Bytes {
+			last = progress
+		}
+	}
+
+	if err := download.Run(context.Background(), alice.Address, bob.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(out.data, content) {
+		t.Fatal("expected downloaded content to match served content")
+	}
+
+	if !last.Done() || last.Bytes != int64(len(content)) || last.Chunks != 11 {
+		t.Fatalf("unexpected final progress %+v", last)
+	}
+}
+
+func TestDownloadResumes(t *testing.T) {
+	alice, aliceTransfer := buildNode(t, 13143)
+	bob, _ := buildNode(t, 13144)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	content := randomContent(8 * 1024)
+
+	if _, err := aliceTransfer.Serve("snapshot", bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &buffer{data: make([]byte, len(content))}
+
+	download := NewDownload(bob, "snapshot", out)
+	download.Concurrency = 1
+	download.Timeout = 200 * time.Millisecond
+
+	// Stop serving the content half way through the download.
+	download.OnProgress = func(progress Progress) {
+		if progress.Verified == 4 {
+			aliceTransfer.Unserve("snapshot")
+		}
+	}
+
+	if err := download.Run(context.Background(), alice.Address); err == nil {
+		t.Fatal("expected download to fail once the content is no longer served")
+	}
+
+	if progress := download.Progress(); progress.Verified != 4 {
+		t.Fatalf("expected 4 chunks to have been verified, got %d", progress.Verified)
+	}
+
+	download.OnProgress = nil
+
+	if _, err := aliceTransfer.Serve("snapshot", bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := download.Run(context.Background(), alice.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(out.data, content) {
+		t.Fatal("expected resumed download to match served content")
+	}
+
+	if out.writes != 8 {
+		t.Fatalf("expected each chunk to only be downloaded once, got %d writes", out.writes)
+	}
+}
+
+func TestDownloadSkipsExistingChunks(t *testing.T) {
+	alice, aliceTransfer := buildNode(t, 13145)
+	bob, _ := buildNode(t, 13146)
+
+	defer alice.Close()
+	defer bob.Close()
+
+	content := randomContent(4 * 1024)
+
+	if _, err := aliceTransfer.Serve("snapshot", bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first half was downloaded before a restart.
+	existing := make([]byte, len(content))
+	copy(existing, content[:2048])
+
+	out := &buffer{data: existing}
+
+	download := NewDownload(bob, "snapshot", out)
+	download.Existing = bytes.NewReader(existing)
+
+	if err := download.Run(context.Background(), alice.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(out.data, content) {
+		t.Fatal("expected download to match served content")
+	}
+
+	if out.writes != 2 {
+		t.Fatalf("expected only the 2 missing chunks to be downloaded, got %d writes", out.writes)
+	}
+}
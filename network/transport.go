@@ -0,0 +1,83 @@
+package network
+
+import (
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Transport listens for and dials connections for a protocol scheme of addresses.
+type Transport interface {
+	// Listen listens for connections on an address.
+	Listen(addr *AddressInfo) (net.Listener, error)
+
+	// Dial connects to an address.
+	Dial(addr *AddressInfo) (net.Conn, error)
+}
+
+// inboundTransport is implemented by transports over which peers are not dialed back,
+// such as WebSockets, which browsers dial but may not be dialed over. Peers connecting
+// over them are instead replied to, and challenged to prove their identity, over the
+// connections they dialed.
+type inboundTransport interface {
+	repliesInbound()
+}
+
+var (
+	transports      = make(map[string]Transport)
+	transportsMutex sync.RWMutex
+)
+
+// RegisterTransport registers the transport connections are made over for addresses
+// of a protocol scheme, replacing any transport already registered for it.
+func RegisterTransport(protocol string, transport Transport) {
+	transportsMutex.Lock()
+	transports[protocol] = transport
+	transportsMutex.Unlock()
+}
+
+// transportFor returns the transport registered for the protocol of an address.
+func transportFor(addr *AddressInfo) (Transport, error) {
+	transportsMutex.RLock()
+	transport, exists := transports[addr.Protocol]
+	transportsMutex.RUnlock()
+
+	if !exists {
+		return nil, errors.New("invalid protocol: " + addr.Protocol)
+	}
+
+	return transport, nil
+}
+
+// repliesInbound returns true should peers connecting over the transport of an address
+// be replied to over the connections they dialed, rather than be dialed back.
+func repliesInbound(address string) bool {
+	addrInfo, err := ParseAddress(address)
+	if err != nil {
+		return false
+	}
+
+	transport, err := transportFor(addrInfo)
+	if err != nil {
+		return false
+	}
+
+	_, inbound := transport.(inboundTransport)
+	return inbound
+}
+
+// tcpTransport makes connections over TCP.
+type tcpTransport struct{}
+
+func (tcpTransport) Listen(addr *AddressInfo) (net.Listener, error) {
+	return net.Listen("tcp", ":"+portString(addr))
+}
+
+func (tcpTransport) Dial(addr *AddressInfo) (net.Conn, error) {
+	return net.Dial("tcp", addr.HostPort())
+}
+
+func init() {
+	RegisterTransport("tcp", tcpTransport{})
+}
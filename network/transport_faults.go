@@ -0,0 +1,123 @@
+package network
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Faults are failures injected into the connections a FaultTransport dials, for tests
+// to exercise how failures are handled. Connections accepted are left as is.
+type Faults struct {
+	// DialErr is returned by dials should it be non-nil.
+	DialErr error
+
+	// ResetAfter resets each connection once it has written this many bytes, failing
+	// the write crossing the limit with ErrConnectionReset. Zero if never.
+	ResetAfter int64
+
+	// WriteDelay delays each write, as though the connection were slow.
+	WriteDelay time.Duration
+}
+
+// FaultTransport wraps a transport, injecting faults into the connections it dials.
+// Faults may be injected and cleared at any time, and apply to connections dialed
+// before being injected.
+type FaultTransport struct {
+	Transport Transport
+
+	mutex  sync.RWMutex
+	faults Faults
+}
+
+// NewFaultTransport creates a transport injecting faults into the connections dialed
+// over a transport. No faults are injected until Inject is called.
+func NewFaultTransport(transport Transport) *FaultTransport {
+	return &FaultTransport{Transport: transport}
+}
+
+// Inject replaces the faults injected.
+func (t *FaultTransport) Inject(faults Faults) {
+	t.mutex.Lock()
+	t.faults = faults
+	t.mutex.Unlock()
+}
+
+// Clear stops injecting faults.
+func (t *FaultTransport) Clear() {
+	t.Inject(Faults{})
+}
+
+// Faults returns the faults injected.
+func (t *FaultTransport) Faults() Faults {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.faults
+}
+
+// Listen implements Transport.
+func (t *FaultTransport) Listen(addr *AddressInfo) (net.Listener, error) {
+	return t.Transport.Listen(addr)
+}
+
+// Dial implements Transport.
+func (t *FaultTransport) Dial(addr *AddressInfo) (net.Conn, error) {
+	if err := t.Faults().DialErr; err != nil {
+		return nil, errors.Wrapf(err, "dial to %s", addr.HostPort())
+	}
+
+	conn, err := t.Transport.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &faultConn{Conn: conn, transport: t}, nil
+}
+
+// faultConn is a connection dialed over a FaultTransport.
+type faultConn struct {
+	net.Conn
+
+	transport *FaultTransport
+
+	mutex   sync.Mutex
+	written int64
+	reset   bool
+}
+
+func (c *faultConn) Write(b []byte) (int, error) {
+	faults := c.transport.Faults()
+
+	if faults.WriteDelay > 0 {
+		time.Sleep(faults.WriteDelay)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.reset {
+		return 0, ErrConnectionReset
+	}
+
+	if faults.ResetAfter > 0 && c.written+int64(len(b)) > faults.ResetAfter {
+		var n int
+
+		if remaining := faults.ResetAfter - c.written; remaining > 0 {
+			n, _ = c.Conn.Write(b[:remaining])
+			c.written += int64(n)
+		}
+
+		c.reset = true
+		c.Conn.Close()
+
+		return n, ErrConnectionReset
+	}
+
+	n, err := c.Conn.Write(b)
+	c.written += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,127 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+var faults = network.NewFaultTransport(network.MemoryTransport{})
+
+func init() {
+	network.RegisterTransport("faulty", faults)
+}
+
+func buildFaultyNodes(t *testing.T, port uint16) (*network.Network, *network.Network) {
+	var nodes []*network.Network
+
+	for i := uint16(0); i < 2; i++ {
+		builder := builders.NewNetworkBuilder()
+		builder.SetKeys(ed25519.RandomKeyPair())
+		builder.SetAddress(network.FormatAddress("faulty", "127.0.0.1", port+i))
+
+		node, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := node.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes[0], nodes[1]
+}
+
+func TestInjectDialError(t *testing.T) {
+	alice, bob := buildFaultyNodes(t, 1)
+	defer alice.Close()
+	defer bob.Close()
+
+	injected := errors.New("injected dial error")
+
+	faults.Inject(network.Faults{DialErr: injected})
+	defer faults.Clear()
+
+	if _, err := alice.Client(bob.Address); errors.Cause(err) != injected {
+		t.Fatalf("expected dial to fail with the injected error, got %v", err)
+	}
+
+	if _, exists := alice.Peers.Load(bob.Address); exists {
+		t.Fatal("expected peer which failed to be dialed to not be kept")
+	}
+
+	faults.Clear()
+
+	// The failed dial quarantines the address, even once faults are cleared.
+	if _, err := alice.Client(bob.Address); errors.Cause(err) != network.ErrQuarantined {
+		t.Fatalf("expected address which failed to be dialed to be quarantined, got %v", err)
+	}
+}
+
+func TestInjectReset(t *testing.T) {
+	alice, bob := buildFaultyNodes(t, 3)
+	defer alice.Close()
+	defer bob.Close()
+	defer faults.Clear()
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Tell(&protobuf.Ping{}); err != nil {
+		t.Fatal(err)
+	}
+
+	faults.Inject(network.Faults{ResetAfter: 1})
+
+	if _, err := client.Tell(&protobuf.Ping{}); err == nil {
+		t.Fatal("expected message to fail to be sent over a reset connection")
+	}
+
+	if _, err := client.Write([]byte("data")); err == nil {
+		t.Fatal("expected stream write to fail over a reset connection")
+	}
+}
+
+func TestInjectSlowWrites(t *testing.T) {
+	alice, bob := buildFaultyNodes(t, 5)
+	defer alice.Close()
+	defer bob.Close()
+	defer faults.Clear()
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	faults.Inject(network.Faults{WriteDelay: 200 * time.Millisecond})
+
+	// Replies are slowed down just the same, and hence arrive past the timeout.
+	request := new(rpc.Request)
+	request.SetMessage(&protobuf.Echo{})
+	request.SetTimeout(50 * time.Millisecond)
+
+	if _, err := client.Request(request); errors.Cause(err) != network.ErrRequestTimeout {
+		t.Fatalf("expected slow reply to time out, got %v", err)
+	}
+
+	client.SetWriteDeadline(time.Now().Add(50 * time.Millisecond))
+
+	if _, err := client.Write([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Write([]byte("data")); errors.Cause(err) != network.ErrDeadlineExceeded {
+		t.Fatalf("expected write past its deadline after a slow write to fail, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !js
+// +build !js
+
+package network
+
+import (
+	"net"
+
+	"github.com/xtaci/kcp-go"
+)
+
+// kcpTransport makes connections over KCP, which requires UDP sockets and is hence
+// unavailable in browsers.
+type kcpTransport struct{}
+
+func (kcpTransport) Listen(addr *AddressInfo) (net.Listener, error) {
+	return kcp.ListenWithOptions(":"+portString(addr), nil, 10, 3)
+}
+
+func (kcpTransport) Dial(addr *AddressInfo) (net.Conn, error) {
+	return kcp.DialWithOptions(addr.HostPort(), nil, 10, 3)
+}
+
+func init() {
+	RegisterTransport("kcp", kcpTransport{})
+}
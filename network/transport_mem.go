@@ -0,0 +1,111 @@
+package network
+
+import (
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// MemoryTransport makes connections within the process, such that networks may be run
+// and replayed into without sockets. It is registered for addresses of the mem scheme.
+// Connections appear to come from the loopback address, as they never leave the process.
+type MemoryTransport struct{}
+
+var (
+	memListeners      = make(map[string]*memListener)
+	memListenersMutex sync.Mutex
+)
+
+type memAddr string
+
+func (a memAddr) Network() string { return "mem" }
+func (a memAddr) String() string  { return string(a) }
+
+// memConn is one end of an in-process connection.
+type memConn struct {
+	net.Conn
+	local, remote net.Addr
+}
+
+func (c *memConn) LocalAddr() net.Addr  { return c.local }
+func (c *memConn) RemoteAddr() net.Addr { return c.remote }
+
+type memListener struct {
+	addr memAddr
+
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *memListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errors.Errorf("memory listener at %s is closed", l.addr)
+	}
+}
+
+func (l *memListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+
+		memListenersMutex.Lock()
+		if memListeners[string(l.addr)] == l {
+			delete(memListeners, string(l.addr))
+		}
+		memListenersMutex.Unlock()
+	})
+	return nil
+}
+
+func (l *memListener) Addr() net.Addr {
+	return l.addr
+}
+
+func (MemoryTransport) Listen(addr *AddressInfo) (net.Listener, error) {
+	memListenersMutex.Lock()
+	defer memListenersMutex.Unlock()
+
+	if _, exists := memListeners[addr.HostPort()]; exists {
+		return nil, errors.Errorf("memory address %s is already in use", addr.HostPort())
+	}
+
+	listener := &memListener{
+		addr:   memAddr(addr.HostPort()),
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+	memListeners[addr.HostPort()] = listener
+
+	return listener, nil
+}
+
+func (MemoryTransport) Dial(addr *AddressInfo) (net.Conn, error) {
+	memListenersMutex.Lock()
+	listener, exists := memListeners[addr.HostPort()]
+	memListenersMutex.Unlock()
+
+	if !exists {
+		return nil, errors.Errorf("nothing is listening on memory address %s", addr.HostPort())
+	}
+
+	local, remote := net.Pipe()
+	dialer := memAddr(net.JoinHostPort("127.0.0.1", "0"))
+
+	select {
+	case listener.conns <- &memConn{Conn: remote, local: listener.addr, remote: dialer}:
+		return &memConn{Conn: local, local: dialer, remote: listener.addr}, nil
+	case <-listener.closed:
+		local.Close()
+		remote.Close()
+
+		return nil, errors.Errorf("memory listener at %s is closed", addr.HostPort())
+	}
+}
+
+func init() {
+	RegisterTransport("mem", MemoryTransport{})
+}
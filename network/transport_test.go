@@ -0,0 +1,130 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func TestWebSocketTransport(t *testing.T) {
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := builders.NewNetworkBuilder()
+		builder.SetKeys(ed25519.RandomKeyPair())
+		builder.SetAddress(network.FormatAddress("ws", "127.0.0.1", 13095+uint16(i)))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go node.Listen()
+		node.BlockUntilListening()
+
+		defer node.Close()
+
+		nodes = append(nodes, node)
+	}
+
+	pongs, closeTap := nodes[0].Tap(network.TapFilter{Types: []string{"protobuf.Pong"}, Inbound: true})
+	defer closeTap()
+
+	nodes[0].Bootstrap(nodes[1].Address)
+
+	select {
+	case <-pongs:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for pong over WebSocket transport")
+	}
+}
+
+func TestWebSocketTransportInbound(t *testing.T) {
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := builders.NewNetworkBuilder()
+		builder.SetKeys(ed25519.RandomKeyPair())
+		builder.SetAddress(network.FormatAddress("ws", "127.0.0.1", 13230+uint16(i)))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		// Stand in for a browser node by never being dialed back.
+		if i == 0 {
+			builder.SetConnectionGater(network.ConnectionGaterFuncs{
+				Dial: func(address string) bool { return false },
+			})
+		}
+
+		node, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go node.Listen()
+		node.BlockUntilListening()
+
+		defer node.Close()
+
+		nodes = append(nodes, node)
+	}
+
+	pongs, closeTap := nodes[1].Tap(network.TapFilter{Types: []string{"protobuf.Pong"}, Inbound: true})
+	defer closeTap()
+
+	nodes[1].Bootstrap(nodes[0].Address)
+
+	select {
+	case <-pongs:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for pong over the connection the peer dialed")
+	}
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	if err != nil {
+		t.Fatalf("expected the peer to be connected without being dialed back, got %v", err)
+	}
+
+	if id := client.ID(); id == nil || !id.Equals(nodes[1].ID) {
+		t.Fatalf("expected the peer to be identified over the connection it dialed, got %v", id)
+	}
+}
+
+func TestMemoryTransport(t *testing.T) {
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := builders.NewNetworkBuilder()
+		builder.SetKeys(ed25519.RandomKeyPair())
+		builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", 1+uint16(i)))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := node.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		defer node.Close()
+
+		nodes = append(nodes, node)
+	}
+
+	pongs, closeTap := nodes[0].Tap(network.TapFilter{Types: []string{"protobuf.Pong"}, Inbound: true})
+	defer closeTap()
+
+	nodes[0].Bootstrap(nodes[1].Address)
+
+	select {
+	case <-pongs:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for pong over memory transport")
+	}
+}
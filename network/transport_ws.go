@@ -0,0 +1,121 @@
+//go:build !js
+// +build !js
+
+package network
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+)
+
+// wsTransport makes connections over WebSockets, so that browser nodes may connect to
+// native nodes.
+type wsTransport struct{}
+
+// repliesInbound implements inboundTransport, as browsers may not be dialed back.
+func (wsTransport) repliesInbound() {}
+
+func (wsTransport) Listen(addr *AddressInfo) (net.Listener, error) {
+	ln, err := net.Listen("tcp", ":"+portString(addr))
+	if err != nil {
+		return nil, err
+	}
+
+	listener := &wsListener{
+		Listener: ln,
+		conns:    make(chan net.Conn),
+		closed:   make(chan struct{}),
+	}
+
+	listener.server = &http.Server{Handler: websocket.Server{Handler: listener.handle}}
+	go listener.server.Serve(ln)
+
+	return listener, nil
+}
+
+func (wsTransport) Dial(addr *AddressInfo) (net.Conn, error) {
+	conn, err := websocket.Dial("ws://"+addr.HostPort()+"/", "", "http://"+addr.HostPort()+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	conn.PayloadType = websocket.BinaryFrame
+
+	return conn, nil
+}
+
+// wsListener accepts connections upgraded to WebSockets by an HTTP server.
+type wsListener struct {
+	net.Listener
+
+	server *http.Server
+
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// handle hands an upgraded connection over to Accept, and keeps it open until it is
+// closed, as the HTTP server closes connections once their handler returns.
+func (l *wsListener) handle(ws *websocket.Conn) {
+	ws.PayloadType = websocket.BinaryFrame
+
+	conn := &wsConn{Conn: ws, done: make(chan struct{})}
+
+	select {
+	case l.conns <- conn:
+	case <-l.closed:
+		return
+	}
+
+	<-conn.done
+}
+
+func (l *wsListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errors.New("listener closed")
+	}
+}
+
+func (l *wsListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+	return l.server.Close()
+}
+
+// wsConn signals its handler upon being closed.
+type wsConn struct {
+	*websocket.Conn
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// RemoteAddr returns the address the connection was upgraded from, as the WebSocket
+// remote address of server-side connections is the client's origin.
+func (c *wsConn) RemoteAddr() net.Addr {
+	addr, err := net.ResolveTCPAddr("tcp", c.Request().RemoteAddr)
+	if err != nil {
+		return c.Conn.RemoteAddr()
+	}
+	return addr
+}
+
+func (c *wsConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	return c.Conn.Close()
+}
+
+func init() {
+	RegisterTransport("ws", wsTransport{})
+}
@@ -0,0 +1,226 @@
+//go:build js && wasm
+// +build js,wasm
+
+package network
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sync"
+	"syscall/js"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// wsTransport dials connections over the WebSocket API of the browser. Browsers may
+// not listen for connections.
+type wsTransport struct{}
+
+// repliesInbound implements inboundTransport, as browsers may not be dialed back.
+func (wsTransport) repliesInbound() {}
+
+// Listen returns a listener which accepts no connections, as browsers may not listen
+// for them. Peers instead reply over the connections browsers dial.
+func (wsTransport) Listen(addr *AddressInfo) (net.Listener, error) {
+	return &jsListener{addr: addr, closed: make(chan struct{})}, nil
+}
+
+// jsListener stands in for a listener within browsers, accepting no connections.
+type jsListener struct {
+	addr *AddressInfo
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *jsListener) Accept() (net.Conn, error) {
+	<-l.closed
+	return nil, errors.New("listener closed")
+}
+
+func (l *jsListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *jsListener) Addr() net.Addr {
+	return l.addr
+}
+
+func (wsTransport) Dial(addr *AddressInfo) (net.Conn, error) {
+	conn := &jsConn{
+		remote:   addr,
+		readable: make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+	}
+
+	opened := make(chan struct{})
+	failed := make(chan struct{})
+
+	var openOnce sync.Once
+
+	ws := js.Global().Get("WebSocket").New("ws://" + addr.HostPort() + "/")
+	ws.Set("binaryType", "arraybuffer")
+
+	conn.ws = ws
+
+	conn.listen("open", func(event js.Value) {
+		openOnce.Do(func() { close(opened) })
+	})
+
+	conn.listen("error", func(event js.Value) {
+		openOnce.Do(func() { close(failed) })
+	})
+
+	conn.listen("message", func(event js.Value) {
+		data := js.Global().Get("Uint8Array").New(event.Get("data"))
+
+		buf := make([]byte, data.Get("length").Int())
+		js.CopyBytesToGo(buf, data)
+
+		conn.mutex.Lock()
+		conn.buffer.Write(buf)
+		conn.mutex.Unlock()
+
+		select {
+		case conn.readable <- struct{}{}:
+		default:
+		}
+	})
+
+	conn.listen("close", func(event js.Value) {
+		openOnce.Do(func() { close(failed) })
+		conn.shutdown()
+	})
+
+	select {
+	case <-opened:
+		return conn, nil
+	case <-failed:
+		conn.Close()
+		return nil, errors.Errorf("failed to open WebSocket to %s", addr)
+	}
+}
+
+// jsConn is a connection over a browser WebSocket.
+type jsConn struct {
+	ws     js.Value
+	remote *AddressInfo
+
+	funcs []js.Func
+
+	mutex        sync.Mutex
+	buffer       bytes.Buffer
+	readDeadline time.Time
+
+	readable  chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// listen registers a callback for an event of the WebSocket.
+func (c *jsConn) listen(event string, callback func(event js.Value)) {
+	f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		callback(args[0])
+		return nil
+	})
+
+	c.funcs = append(c.funcs, f)
+	c.ws.Call("addEventListener", event, f)
+}
+
+func (c *jsConn) shutdown() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+}
+
+func (c *jsConn) Read(b []byte) (int, error) {
+	for {
+		c.mutex.Lock()
+
+		if c.buffer.Len() > 0 {
+			n, _ := c.buffer.Read(b)
+			c.mutex.Unlock()
+			return n, nil
+		}
+
+		deadline := c.readDeadline
+		c.mutex.Unlock()
+
+		var timeout <-chan time.Time
+
+		if !deadline.IsZero() {
+			timer := time.NewTimer(time.Until(deadline))
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		select {
+		case <-c.readable:
+		case <-c.closed:
+			return 0, io.EOF
+		case <-timeout:
+			return 0, errors.Wrap(ErrDeadlineExceeded, "read")
+		}
+	}
+}
+
+func (c *jsConn) Write(b []byte) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, io.ErrClosedPipe
+	default:
+	}
+
+	data := js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(data, b)
+
+	c.ws.Call("send", data)
+
+	return len(b), nil
+}
+
+func (c *jsConn) Close() error {
+	c.shutdown()
+	c.ws.Call("close")
+
+	for _, f := range c.funcs {
+		f.Release()
+	}
+	c.funcs = nil
+
+	return nil
+}
+
+func (c *jsConn) LocalAddr() net.Addr {
+	return &AddressInfo{Protocol: "ws"}
+}
+
+func (c *jsConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+func (c *jsConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+func (c *jsConn) SetReadDeadline(t time.Time) error {
+	c.mutex.Lock()
+	c.readDeadline = t
+	c.mutex.Unlock()
+	return nil
+}
+
+// SetWriteDeadline is a no-op, as writes to browser WebSockets never block.
+func (c *jsConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+func init() {
+	RegisterTransport("ws", wsTransport{})
+}
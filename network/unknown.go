@@ -0,0 +1,66 @@
+package network
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// UnknownHandler handles a message whose type is not registered with protobuf, and
+// thus has no processor. It is called from the peer's receive worker, and so must not
+// block. Replies to our requests are handed raw to whoever awaits them instead.
+type UnknownHandler func(client *PeerClient, msg *UnknownMessage)
+
+// UnknownMessage is a message of a type unknown to the network, handed raw to the
+// network's UnknownHandler such as to be forwarded, recorded or decoded by a shim.
+type UnknownMessage struct {
+	client *PeerClient
+
+	// Message is the raw message, keeping its type URL and encoded value.
+	Message *any.Any
+
+	// ID is the ID of the message, as assigned by its sender.
+	ID MessageID
+
+	// Nonce is the request nonce of the message. Zero if the sender awaits no reply.
+	Nonce uint64
+
+	// LamportTimestamp is the sender's logical clock at the time the message was sent.
+	LamportTimestamp uint64
+
+	// Signature is the sender's signature over the message envelope.
+	Signature []byte
+
+	// ReceivedAt is the time the message was received according to the network's clock.
+	ReceivedAt time.Time
+}
+
+// newUnknownMessage wraps a message of an unknown type received from a peer.
+func newUnknownMessage(client *PeerClient, msg *protobuf.Message, received time.Time) *UnknownMessage {
+	return &UnknownMessage{
+		client:           client,
+		Message:          msg.Message,
+		ID:               NewMessageID(msg),
+		Nonce:            msg.RequestNonce,
+		LamportTimestamp: msg.LamportTimestamp,
+		Signature:        msg.Signature,
+		ReceivedAt:       received,
+	}
+}
+
+// TypeURL returns the type URL of the message.
+func (m *UnknownMessage) TypeURL() string {
+	return m.Message.GetTypeUrl()
+}
+
+// IsRequest returns true should the sender be awaiting a reply to the message.
+func (m *UnknownMessage) IsRequest() bool {
+	return m.Nonce > 0
+}
+
+// Reply sends back a message in reply to the message.
+func (m *UnknownMessage) Reply(message proto.Message) error {
+	return m.client.Reply(m.Nonce, message)
+}
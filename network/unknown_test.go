@@ -0,0 +1,138 @@
+package network_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/perlin-network/noise/crypto/signing/ed25519"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/builders"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+func TestUnknownHandler(t *testing.T) {
+	received := make(chan *network.UnknownMessage, 1)
+
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", 362))
+	builder.SetUnknownHandler(func(client *network.PeerClient, msg *network.UnknownMessage) {
+		received <- msg
+	})
+	builder.AddPlugin(new(discovery.Plugin))
+
+	alice, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := alice.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer alice.Close()
+
+	bob := buildTicketingNode(t, 363)
+	defer bob.Close()
+
+	client, err := bob.Client(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := &any.Any{TypeUrl: "type.googleapis.com/example.Future", Value: []byte("from the future")}
+
+	id, err := client.TellAny(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-received:
+		if msg.TypeURL() != raw.TypeUrl {
+			t.Fatalf("expected type %s, got %s", raw.TypeUrl, msg.TypeURL())
+		}
+		if !bytes.Equal(msg.Message.Value, raw.Value) {
+			t.Fatalf("expected value %q, got %q", raw.Value, msg.Message.Value)
+		}
+		if msg.ID != id {
+			t.Fatalf("expected message ID %s, got %s", id, msg.ID)
+		}
+		if msg.IsRequest() {
+			t.Fatal("expected message not to be a request")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for message of unknown type")
+	}
+}
+
+func buildUnknownHandlingNode(t *testing.T, port uint16, handler network.UnknownHandler) *network.Network {
+	builder := builders.NewNetworkBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("mem", "127.0.0.1", port))
+	builder.SetUnknownHandler(handler)
+	builder.AddPlugin(new(discovery.Plugin))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node
+}
+
+func TestUnknownReplyIsAwaited(t *testing.T) {
+	const nonce = 42
+
+	reply := &any.Any{TypeUrl: "type.googleapis.com/example.FutureReply", Value: []byte("reply from the future")}
+
+	alice := buildUnknownHandlingNode(t, 382, func(client *network.PeerClient, msg *network.UnknownMessage) {
+		signed, err := client.Network.PrepareAny(reply)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		signed.RequestNonce = nonce
+
+		if err := client.Network.Write(client.Address(), signed); err != nil {
+			t.Error(err)
+		}
+	})
+	defer alice.Close()
+
+	unhandled := make(chan *network.UnknownMessage, 1)
+
+	bob := buildUnknownHandlingNode(t, 383, func(client *network.PeerClient, msg *network.UnknownMessage) {
+		unhandled <- msg
+	})
+	defer bob.Close()
+
+	client, err := bob.Client(alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	awaiting := make(chan proto.Message, 1)
+	client.Requests.Store(uint64(nonce), awaiting)
+
+	if _, err := client.TellAny(&any.Any{TypeUrl: "type.googleapis.com/example.Future", Value: []byte("from the future")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The reply is handed raw to whoever awaits it rather than to bob's unknown handler.
+	select {
+	case received := <-awaiting:
+		if raw, ok := received.(*any.Any); !ok || raw.TypeUrl != reply.TypeUrl || !bytes.Equal(raw.Value, reply.Value) {
+			t.Fatalf("expected the raw reply %v, got %v", reply, received)
+		}
+	case msg := <-unhandled:
+		t.Fatalf("expected the awaited reply of type %s not to be handed to the unknown handler", msg.TypeURL())
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for reply of unknown type")
+	}
+}
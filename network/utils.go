@@ -54,3 +54,23 @@ func FilterPeers(address string, peers []string) (filtered []string) {
 	}
 	return filtered
 }
+
+// signedMessage returns what is signed of a message sent by a sender, being prefixed by
+// the network ID should it be mixed into signatures.
+func (n *Network) signedMessage(id *protobuf.ID, message []byte) []byte {
+	serialized := serializeMessage(id, message)
+
+	if !n.SignNetworkID || n.NetworkID == "" {
+		return serialized
+	}
+
+	const UINT32_SIZE = 4
+
+	signed := make([]byte, UINT32_SIZE+len(n.NetworkID)+len(serialized))
+
+	binary.LittleEndian.PutUint32(signed, uint32(len(n.NetworkID)))
+	copy(signed[UINT32_SIZE:], n.NetworkID)
+	copy(signed[UINT32_SIZE+len(n.NetworkID):], serialized)
+
+	return signed
+}
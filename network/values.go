@@ -0,0 +1,97 @@
+package network
+
+import "sync"
+
+// Namespace namespaces the values plugins attach to peers. Plugins conventionally
+// name their namespace after their package, such as "skew".
+type Namespace string
+
+// Values is a thread-safe store of values attached to a peer, namespaced per plugin
+// such that plugins may not clobber each others values. Values are dropped once the
+// peer disconnects, and follow the peer should its address change.
+type Values struct {
+	mutex  sync.RWMutex
+	values map[valueKey]interface{}
+}
+
+type valueKey struct {
+	namespace Namespace
+	key       string
+}
+
+// Get returns the value set under a key within a namespace. The second returning
+// parameter is false should no value be set.
+func (v *Values) Get(namespace Namespace, key string) (interface{}, bool) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+
+	value, exists := v.values[valueKey{namespace, key}]
+	return value, exists
+}
+
+// Set sets the value under a key within a namespace.
+func (v *Values) Set(namespace Namespace, key string, value interface{}) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if v.values == nil {
+		v.values = make(map[valueKey]interface{})
+	}
+
+	v.values[valueKey{namespace, key}] = value
+}
+
+// GetOrSet returns the value under a key within a namespace, setting it to the value
+// create returns should no value be set.
+func (v *Values) GetOrSet(namespace Namespace, key string, create func() interface{}) interface{} {
+	if value, exists := v.Get(namespace, key); exists {
+		return value
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if value, exists := v.values[valueKey{namespace, key}]; exists {
+		return value
+	}
+
+	if v.values == nil {
+		v.values = make(map[valueKey]interface{})
+	}
+
+	value := create()
+	v.values[valueKey{namespace, key}] = value
+
+	return value
+}
+
+// Delete removes the value under a key within a namespace.
+func (v *Values) Delete(namespace Namespace, key string) {
+	v.mutex.Lock()
+	delete(v.values, valueKey{namespace, key})
+	v.mutex.Unlock()
+}
+
+// Clear removes all values within a namespace.
+func (v *Values) Clear(namespace Namespace) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	for k := range v.values {
+		if k.namespace == namespace {
+			delete(v.values, k)
+		}
+	}
+}
+
+// reset removes all values.
+func (v *Values) reset() {
+	v.mutex.Lock()
+	v.values = nil
+	v.mutex.Unlock()
+}
+
+// Values returns the values plugins attached to the peer.
+func (c *PeerClient) Values() *Values {
+	return &c.values
+}
@@ -0,0 +1,69 @@
+package network
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	var values Values
+
+	a, b := Namespace("a"), Namespace("b")
+
+	values.Set(a, "score", 1)
+	values.Set(b, "score", 2)
+
+	if value, exists := values.Get(a, "score"); !exists || value.(int) != 1 {
+		t.Fatalf("expected plugin a's score to be 1, got %v", value)
+	}
+
+	if value, exists := values.Get(b, "score"); !exists || value.(int) != 2 {
+		t.Fatalf("expected plugin b's score to be 2, got %v", value)
+	}
+
+	created := 0
+	create := func() interface{} {
+		created++
+		return created
+	}
+
+	if value := values.GetOrSet(a, "seen", create); value.(int) != 1 {
+		t.Fatalf("expected value to be created, got %v", value)
+	}
+
+	if value := values.GetOrSet(a, "seen", create); value.(int) != 1 || created != 1 {
+		t.Fatalf("expected value to be created once, got %v after %d creations", value, created)
+	}
+
+	values.Clear(a)
+
+	if _, exists := values.Get(a, "score"); exists {
+		t.Fatal("expected plugin a's values to be cleared")
+	}
+
+	if _, exists := values.Get(b, "score"); !exists {
+		t.Fatal("expected plugin b's values to survive plugin a's being cleared")
+	}
+
+	values.Delete(b, "score")
+
+	if _, exists := values.Get(b, "score"); exists {
+		t.Fatal("expected plugin b's score to be deleted")
+	}
+}
+
+func TestValuesDroppedOnClose(t *testing.T) {
+	n := &Network{Plugins: NewPluginList(), Peers: new(sync.Map), Connections: new(sync.Map)}
+
+	client, err := createPeerClient(n, "tcp://127.0.0.1:3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.Values().Set("a", "score", 1)
+	client.Close()
+
+	if _, exists := client.Values().Get("a", "score"); exists {
+		t.Fatal("expected values to be dropped once the peer disconnects")
+	}
+}
@@ -0,0 +1,55 @@
+package network
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// observedAddr wraps addresses stored in an atomic.Value, which requires all stored
+// values to be of the same concrete type.
+type observedAddr struct {
+	addr net.Addr
+}
+
+// ObservedAddress returns the address the peer was last observed connecting to us from,
+// or nil should it never have connected to us.
+func (c *PeerClient) ObservedAddress() net.Addr {
+	observed, _ := c.observed.Load().(observedAddr)
+	return observed.addr
+}
+
+// VerifyAddress checks that the host a peer claims to be reachable at within its ID
+// matches the host it was observed connecting to us from, so that peers may not
+// claim the addresses of others.
+func (c *PeerClient) VerifyAddress() error {
+	if c.ID() == nil {
+		return errors.New("peer has not identified itself")
+	}
+
+	observed := c.ObservedAddress()
+	if observed == nil {
+		return errors.Errorf("peer %s has not been observed connecting to us", c.ID().Address)
+	}
+
+	observedHost, _, err := net.SplitHostPort(observed.String())
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse observed address of peer %s", c.ID().Address)
+	}
+
+	claimed, err := ParseAddress(c.ID().Address)
+	if err != nil {
+		return err
+	}
+
+	claimedHost, err := ToUnifiedHost(claimed.Host)
+	if err != nil {
+		return err
+	}
+
+	if !net.ParseIP(claimedHost).Equal(net.ParseIP(observedHost)) {
+		return errors.Errorf("peer claims address %s but was observed connecting from %s", c.ID().Address, observed)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/perlin-network/noise/peer"
+)
+
+func TestVerifyAddress(t *testing.T) {
+	id := peer.CreateID("tcp://127.0.0.1:3000", []byte("key"))
+
+	client := &PeerClient{id: &id}
+
+	if err := client.VerifyAddress(); err == nil {
+		t.Fatal("expected peer which was never observed to not be verified")
+	}
+
+	client.observed.Store(observedAddr{&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 52000}})
+
+	if err := client.VerifyAddress(); err != nil {
+		t.Fatal(err)
+	}
+
+	client.observed.Store(observedAddr{&net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 52000}})
+
+	if err := client.VerifyAddress(); err == nil {
+		t.Fatal("expected peer observed from a different host to not be verified")
+	}
+}
@@ -0,0 +1,82 @@
+package network
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/perlin-network/noise/types/clock"
+)
+
+// WorkerPool is a pool of workers which send queued packets over their target
+// connections. Packets carry their own connection, so a pool may be shared by several
+// networks running in one process rather than each spawning its own workers.
+//
+// Only the sending of packets is shared. Each network still listens on, and is dialed
+// at, an address of its own, as peers are told apart by their address rather than by
+// their ID. Networks hosted in one process, such as by test harnesses, may listen on
+// addresses of the mem scheme so as not to take up a port each.
+type WorkerPool struct {
+	Queue chan *Packet
+
+	// Clock times when packets start being sent. It defaults to the system clock, and
+	// must be set before the pool is started.
+	Clock clock.Clock
+
+	size  int
+	start sync.Once
+}
+
+// NewWorkerPool creates a pool of size workers sending packets off of a queue. Should
+// queue be nil, a queue is created.
+func NewWorkerPool(size int, queue chan *Packet) *WorkerPool {
+	if size <= 0 {
+		size = 1
+	}
+
+	if queue == nil {
+		queue = make(chan *Packet, 4096)
+	}
+
+	return &WorkerPool{Queue: queue, size: size}
+}
+
+// Start spawns the pools workers. Subsequent calls are no-ops.
+func (p *WorkerPool) Start() {
+	p.start.Do(func() {
+		for i := 0; i < p.size; i++ {
+			go p.work()
+		}
+	})
+}
+
+// work sends queued packets, reporting the result of each back to its sender. Packets
+// sent successfully are reported with the time they started being sent.
+func (p *WorkerPool) work() {
+	for packet := range p.Queue {
+		if packet.bulk != nil {
+			wait(proto.Size(packet.payload), packet.bulk)
+		}
+
+		start := clock.Or(p.Clock).Now()
+
+		stream, err := packet.target.session.OpenStream()
+		if err != nil {
+			packet.result <- err
+			continue
+		}
+
+		err = sendMessage(stream, packet.payload)
+		if err != nil {
+			packet.result <- err
+			continue
+		}
+
+		err = stream.Close()
+		if err != nil {
+			packet.result <- err
+			continue
+		}
+
+		packet.result <- start
+	}
+}
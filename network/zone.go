@@ -0,0 +1,160 @@
+package network
+
+import (
+	"math/rand"
+)
+
+// ZonePreference denotes how peers are selected w.r.t. the zones they run in.
+type ZonePreference int
+
+const (
+	// ZoneDiversity spreads selected peers evenly across zones, preferring zones other
+	// than our own, such that messages survive the outage of a zone.
+	ZoneDiversity ZonePreference = iota
+
+	// ZoneLocality selects peers in our own zone first, such as to keep latency low.
+	ZoneLocality
+)
+
+func (p ZonePreference) String() string {
+	switch p {
+	case ZoneDiversity:
+		return "diversity"
+	case ZoneLocality:
+		return "locality"
+	default:
+		return "unknown"
+	}
+}
+
+// observeZone records the zone the peer labelled itself with on a ping or pong.
+func (c *PeerClient) observeZone(zone string) {
+	if len(zone) == 0 {
+		return
+	}
+	c.zone.Store(zone)
+}
+
+// Zone returns the zone the peer labelled itself with on its pings or pongs. Empty if
+// the peer is unlabelled.
+func (c *PeerClient) Zone() string {
+	zone, _ := c.zone.Load().(string)
+	return zone
+}
+
+// ZoneSelector selects K connected peers at random w.r.t. a preference for the zones
+// they run in. Unlabelled peers are grouped together as though they share a zone.
+type ZoneSelector struct {
+	K          int
+	Preference ZonePreference
+}
+
+// Select implements Selector.
+func (s ZoneSelector) Select(net *Network) []*PeerClient {
+	var candidates []*PeerClient
+
+	net.Peers.Range(func(key, value interface{}) bool {
+		candidates = append(candidates, value.(*PeerClient))
+		return true
+	})
+
+	return s.pick(net, candidates)
+}
+
+// pick selects K peers out of a set of candidates w.r.t. the selector's preference.
+func (s ZoneSelector) pick(net *Network, candidates []*PeerClient) []*PeerClient {
+	var zones []string
+	clients := make(map[string][]*PeerClient)
+
+	for _, client := range candidates {
+		zone := client.Zone()
+
+		if _, exists := clients[zone]; !exists {
+			zones = append(zones, zone)
+		}
+		clients[zone] = append(clients[zone], client)
+	}
+
+	for _, zone := range zones {
+		shuffleClients(clients[zone])
+	}
+
+	rand.Shuffle(len(zones), func(i, j int) {
+		zones[i], zones[j] = zones[j], zones[i]
+	})
+
+	var selected []*PeerClient
+
+	switch s.Preference {
+	case ZoneLocality:
+		if len(net.Zone) > 0 {
+			selected = append(selected, clients[net.Zone]...)
+		}
+
+		var others []*PeerClient
+		for _, zone := range zones {
+			if len(net.Zone) == 0 || zone != net.Zone {
+				others = append(others, clients[zone]...)
+			}
+		}
+		shuffleClients(others)
+
+		selected = append(selected, others...)
+	default:
+		// Visit our own zone last, such that other zones are selected from first.
+		for i, zone := range zones {
+			if len(net.Zone) > 0 && zone == net.Zone {
+				zones = append(append(zones[:i:i], zones[i+1:]...), zone)
+				break
+			}
+		}
+
+		// Take a peer from each zone in turn.
+		for remaining := true; remaining && len(selected) < s.K; {
+			remaining = false
+
+			for _, zone := range zones {
+				if len(clients[zone]) == 0 {
+					continue
+				}
+
+				selected = append(selected, clients[zone][0])
+				clients[zone] = clients[zone][1:]
+
+				remaining = true
+			}
+		}
+	}
+
+	if len(selected) > s.K {
+		selected = selected[:s.K]
+	}
+
+	return selected
+}
+
+// SelectByZone selects K peers out of a set of candidates w.r.t. the network's
+// ZonePreference, such that peers picked other than by BroadcastRandomly, such as
+// those publications are relayed to, respect it too. Peers are selected at random
+// should the network not be labelled with a zone. The candidates are left as is.
+func (n *Network) SelectByZone(candidates []*PeerClient, K int) []*PeerClient {
+	if len(n.Zone) > 0 {
+		return ZoneSelector{K: K, Preference: n.ZonePreference}.pick(n, candidates)
+	}
+
+	selected := append([]*PeerClient(nil), candidates...)
+	shuffleClients(selected)
+
+	if len(selected) > K {
+		selected = selected[:K]
+	}
+
+	return selected
+}
+
+// shuffleClients shuffles peer clients in place.
+func shuffleClients(clients []*PeerClient) {
+	rand.Shuffle(len(clients), func(i, j int) {
+		clients[i], clients[j] = clients[j], clients[i]
+	})
+}
@@ -0,0 +1,54 @@
+package peer
+
+import (
+	"bytes"
+	"math/bits"
+	"sort"
+)
+
+// Distance returns the XOR distance between the public keys of two peer IDs as a
+// big-endian byte slice.
+func Distance(a ID, b ID) []byte {
+	return a.Xor(b).PublicKey
+}
+
+// CompareDistance compares the XOR distances of two peer IDs to a target. Returns -1
+// should a be closer to the target than b, 1 should b be closer, and 0 otherwise.
+func CompareDistance(target ID, a ID, b ID) int {
+	return bytes.Compare(Distance(a, target), Distance(b, target))
+}
+
+// CommonPrefixLen returns the number of leading bits two peer IDs' public keys share.
+func CommonPrefixLen(a ID, b ID) int {
+	distance := Distance(a, b)
+
+	for i, x := range distance {
+		if x != 0 {
+			return i*8 + bits.LeadingZeros8(x)
+		}
+	}
+
+	return len(distance) * 8
+}
+
+// SortByDistance sorts peer IDs in place from closest to furthest from a target by XOR distance.
+func SortByDistance(target ID, ids []ID) {
+	sort.SliceStable(ids, func(i, j int) bool {
+		return CompareDistance(target, ids[i], ids[j]) < 0
+	})
+}
+
+// Closest returns the count peer IDs closest to a target by XOR distance, without
+// modifying ids.
+func Closest(target ID, ids []ID, count int) []ID {
+	sorted := make([]ID, len(ids))
+	copy(sorted, ids)
+
+	SortByDistance(target, sorted)
+
+	if len(sorted) > count {
+		sorted = sorted[:count]
+	}
+
+	return sorted
+}
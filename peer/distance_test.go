@@ -0,0 +1,47 @@
+package peer
+
+import (
+	"testing"
+)
+
+func TestCommonPrefixLen(t *testing.T) {
+	a := CreateID("a", []byte{0xff, 0x00})
+	b := CreateID("b", []byte{0xf0, 0x00})
+	c := CreateID("c", []byte{0xff, 0x01})
+
+	if n := CommonPrefixLen(a, b); n != 4 {
+		t.Fatalf("expected common prefix of 4 bits, got %d", n)
+	}
+
+	if n := CommonPrefixLen(a, c); n != 15 {
+		t.Fatalf("expected common prefix of 15 bits, got %d", n)
+	}
+
+	if n := CommonPrefixLen(a, a); n != 16 {
+		t.Fatalf("expected identical IDs to share all 16 bits, got %d", n)
+	}
+}
+
+func TestClosest(t *testing.T) {
+	target := CreateID("target", []byte{0x00})
+
+	ids := []ID{
+		CreateID("far", []byte{0x80}),
+		CreateID("near", []byte{0x01}),
+		CreateID("mid", []byte{0x10}),
+	}
+
+	closest := Closest(target, ids, 2)
+
+	if len(closest) != 2 || closest[0].Address != "near" || closest[1].Address != "mid" {
+		t.Fatalf("unexpected closest peers %v", closest)
+	}
+
+	if ids[0].Address != "far" {
+		t.Fatal("expected input to not be modified")
+	}
+
+	if CompareDistance(target, ids[1], ids[0]) != -1 {
+		t.Fatal("expected near peer to be closer than far peer")
+	}
+}
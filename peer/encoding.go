@@ -0,0 +1,279 @@
+package peer
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultHRP is the human-readable part prefixing bech32-encoded peer IDs.
+const DefaultHRP = "noise"
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var base58Indices = func() (indices [256]int) {
+	for i := range indices {
+		indices[i] = -1
+	}
+	for i, c := range base58Alphabet {
+		indices[c] = i
+	}
+	return
+}()
+
+// encodeBase58 encodes bytes in base58 using the Bitcoin alphabet, with each leading
+// zero byte being encoded as a leading '1'.
+func encodeBase58(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	// Repeatedly divide the big-endian number by 58, collecting remainders as digits.
+	digits := make([]byte, 0, len(data)*138/100+1)
+
+	for _, b := range data[zeros:] {
+		carry := int(b)
+
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	encoded := make([]byte, zeros+len(digits))
+
+	for i := 0; i < zeros; i++ {
+		encoded[i] = base58Alphabet[0]
+	}
+
+	for i, digit := range digits {
+		encoded[len(encoded)-1-i] = base58Alphabet[digit]
+	}
+
+	return string(encoded)
+}
+
+// decodeBase58 decodes a string encoded in base58 using the Bitcoin alphabet.
+func decodeBase58(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	bytes := make([]byte, 0, len(s)*733/1000+1)
+
+	for i := zeros; i < len(s); i++ {
+		carry := base58Indices[s[i]]
+		if carry < 0 {
+			return nil, errors.Errorf("invalid base58 character %q", s[i])
+		}
+
+		for j := range bytes {
+			carry += int(bytes[j]) * 58
+			bytes[j] = byte(carry)
+			carry >>= 8
+		}
+
+		for carry > 0 {
+			bytes = append(bytes, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	decoded := make([]byte, zeros+len(bytes))
+
+	for i, b := range bytes {
+		decoded[len(decoded)-1-i] = b
+	}
+
+	return decoded, nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	checksum := uint32(1)
+
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(value)
+
+		for i := uint(0); i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				checksum ^= generator[i]
+			}
+		}
+	}
+
+	return checksum
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+
+	expanded = append(expanded, 0)
+
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+
+	return expanded
+}
+
+// convertBits regroups bits from groups of a size into groups of another size.
+func convertBits(data []byte, from uint, to uint, pad bool) ([]byte, error) {
+	var converted []byte
+
+	accumulator, bits := uint32(0), uint(0)
+	max := uint32(1)<<to - 1
+
+	for _, value := range data {
+		if uint32(value)>>from != 0 {
+			return nil, errors.New("invalid data range")
+		}
+
+		accumulator = accumulator<<from | uint32(value)
+		bits += from
+
+		for bits >= to {
+			bits -= to
+			converted = append(converted, byte(accumulator>>bits&max))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			converted = append(converted, byte(accumulator<<(to-bits)&max))
+		}
+	} else if bits >= from || accumulator<<(to-bits)&max != 0 {
+		return nil, errors.New("invalid padding")
+	}
+
+	return converted, nil
+}
+
+// encodeBech32 encodes bytes in bech32 as specified by BIP-173 under a human-readable part.
+func encodeBech32(hrp string, data []byte) (string, error) {
+	if len(hrp) == 0 || strings.ToLower(hrp) != hrp || strings.Contains(hrp, "1") {
+		return "", errors.Errorf("invalid bech32 human-readable part %q", hrp)
+	}
+
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	checksummed := append(bech32ExpandHRP(hrp), values...)
+	checksummed = append(checksummed, 0, 0, 0, 0, 0, 0)
+
+	polymod := bech32Polymod(checksummed) ^ 1
+
+	for i := 0; i < 6; i++ {
+		values = append(values, byte(polymod>>uint(5*(5-i))&31))
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(hrp)
+	builder.WriteByte('1')
+
+	for _, value := range values {
+		builder.WriteByte(bech32Charset[value])
+	}
+
+	return builder.String(), nil
+}
+
+// decodeBech32 decodes a bech32 string as specified by BIP-173 into its human-readable
+// part and bytes.
+func decodeBech32(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, errors.New("bech32 string has mixed case")
+	}
+
+	s = strings.ToLower(s)
+
+	separator := strings.LastIndexByte(s, '1')
+	if separator < 1 || separator+7 > len(s) {
+		return "", nil, errors.New("bech32 string has an invalid separator position")
+	}
+
+	hrp := s[:separator]
+
+	values := make([]byte, 0, len(s)-separator-1)
+
+	for i := separator + 1; i < len(s); i++ {
+		value := strings.IndexByte(bech32Charset, s[i])
+		if value < 0 {
+			return "", nil, errors.Errorf("invalid bech32 character %q", s[i])
+		}
+		values = append(values, byte(value))
+	}
+
+	if bech32Polymod(append(bech32ExpandHRP(hrp), values...)) != 1 {
+		return "", nil, errors.New("bech32 string has an invalid checksum")
+	}
+
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return hrp, data, nil
+}
+
+// String returns the canonical encoding of the peer ID, being its public key encoded
+// in base58.
+func (id ID) String() string {
+	return encodeBase58(id.PublicKey)
+}
+
+// Bech32 encodes the peer ID's public key in bech32 under a human-readable part, such
+// as DefaultHRP.
+func (id ID) Bech32(hrp string) (string, error) {
+	return encodeBech32(hrp, id.PublicKey)
+}
+
+// Format encodes the peer ID alongside its address as <base58>@<address>.
+func (id ID) Format() string {
+	return id.String() + "@" + id.Address
+}
+
+// Parse parses a peer ID encoded either in base58 or in bech32, optionally followed
+// by its address as <id>@<address>. Bech32-encoded peer IDs are accepted under any
+// human-readable part.
+func Parse(s string) (ID, error) {
+	var address string
+
+	if separator := strings.IndexByte(s, '@'); separator >= 0 {
+		s, address = s[:separator], s[separator+1:]
+	}
+
+	if len(s) == 0 {
+		return ID{}, errors.New("peer ID is empty")
+	}
+
+	// Bech32 is checksummed, and hence is tried first as bech32 strings may happen to
+	// also be valid base58.
+	_, publicKey, err := decodeBech32(s)
+	if err != nil {
+		if publicKey, err = decodeBase58(s); err != nil {
+			return ID{}, errors.Errorf("peer ID %q is neither valid bech32 nor base58", s)
+		}
+	}
+
+	return CreateID(address, publicKey), nil
+}
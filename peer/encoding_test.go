@@ -0,0 +1,76 @@
+package peer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	for _, data := range [][]byte{{}, {0}, {0, 0, 1}, []byte("hello world"), bytes.Repeat([]byte{0xff}, 32)} {
+		decoded, err := decodeBase58(encodeBase58(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(decoded, data) {
+			t.Fatalf("expected %x to round trip, got %x", data, decoded)
+		}
+	}
+
+	// Vector taken from the Bitcoin base58 test suite.
+	if encoded := encodeBase58([]byte("hello world")); encoded != "StV1DL6CwTryKyV" {
+		t.Fatalf("unexpected base58 encoding %s", encoded)
+	}
+
+	if _, err := decodeBase58("0OIl"); err == nil {
+		t.Fatal("expected characters outside of the base58 alphabet to be rejected")
+	}
+}
+
+func TestBech32(t *testing.T) {
+	// Vectors taken from BIP-173.
+	for _, valid := range []string{"a12uel5l", "A12UEL5L", "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw"} {
+		if _, _, err := decodeBech32(valid); err != nil {
+			t.Fatalf("expected %s to be valid bech32: %v", valid, err)
+		}
+	}
+
+	for _, invalid := range []string{"a12UEL5L", "pzry9x0s0muk", "a12uel5m"} {
+		if _, _, err := decodeBech32(invalid); err == nil {
+			t.Fatalf("expected %s to be invalid bech32", invalid)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	id := CreateID("tcp://127.0.0.1:3000", []byte("12345678901234567890123456789012"))
+
+	encoded, err := id.Bech32(DefaultHRP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{id.String(), encoded} {
+		parsed, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !parsed.Equals(id) || parsed.Address != "" {
+			t.Fatalf("expected %s to parse into the peer ID", s)
+		}
+	}
+
+	parsed, err := Parse(id.Format())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !parsed.Equals(id) || parsed.Address != id.Address {
+		t.Fatalf("expected %s to parse into the peer ID and its address", id.Format())
+	}
+
+	if _, err := Parse("not a peer ID"); err == nil {
+		t.Fatal("expected an invalid peer ID to be rejected")
+	}
+}
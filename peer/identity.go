@@ -2,8 +2,8 @@ package peer
 
 import (
 	"bytes"
+	"crypto/subtle"
 	"encoding/hex"
-	"fmt"
 
 	"github.com/perlin-network/noise/protobuf"
 )
@@ -16,14 +16,10 @@ func CreateID(address string, publicKey []byte) ID {
 	return ID{PublicKey: publicKey, Address: address}
 }
 
-//
-func (id ID) String() string {
-	return fmt.Sprintf("ID{PublicKey: %v, Address: %v}", id.PublicKey, id.Address)
-}
-
-// Equals determines if two peer IDs are equal to each other based on the contents of their public keys.
+// Equals determines if two peer IDs are equal to each other based on the contents of their public keys,
+// compared in constant time.
 func (id ID) Equals(other ID) bool {
-	return bytes.Equal(id.PublicKey, other.PublicKey)
+	return subtle.ConstantTimeCompare(id.PublicKey, other.PublicKey) == 1
 }
 
 // Less determines if this peer.ID's public keys is less than the other's
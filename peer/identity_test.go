@@ -27,7 +27,7 @@ func TestID(t *testing.T) {
 	})
 
 	t.Run("String()", func(t *testing.T) {
-		if id1.String() != "ID{PublicKey: [49 50 51 52 53 54 55 56 57 48 49 50 51 52 53 54 55 56 57 48 49 50 51 52 53 54 55 56 57 48 49 50], Address: localhost:12345}" {
+		if id1.String() != "4K3NiGuqYGqKPnYp6XeGd2kdN4P9veL6rYcWkLKWXZCu" {
 			t.Fatalf("string() error: %s", id1.String())
 		}
 	})
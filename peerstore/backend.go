@@ -0,0 +1,144 @@
+package peerstore
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Buckets entries are persisted under.
+const (
+	// PeersBucket holds the connection history of peers keyed by their addresses.
+	PeersBucket = "peers"
+
+	// RecordsBucket holds DHT records keyed by their keys.
+	RecordsBucket = "records"
+
+	// RoutesBucket holds the addresses of peers within the DHT routing table keyed by
+	// their IDs.
+	RoutesBucket = "routes"
+)
+
+// ErrBackendClosed is returned when accessing a closed backend.
+var ErrBackendClosed = errors.New("peerstore backend closed")
+
+// Backend persists entries of the peer store and of DHT records under buckets, such
+// that large nodes need not hold them all in memory nor lose them upon restart.
+type Backend interface {
+	// Get returns the value under a key in a bucket. The second returning parameter
+	// is false should no such key exist.
+	Get(bucket string, key string) ([]byte, bool, error)
+
+	// Put sets the value under a key in a bucket.
+	Put(bucket string, key string, value []byte) error
+
+	// Delete removes a key from a bucket. Deleting a key that does not exist is not
+	// an error.
+	Delete(bucket string, key string) error
+
+	// Iterate calls fn for every key in a bucket until fn returns an error.
+	Iterate(bucket string, fn func(key string, value []byte) error) error
+
+	Close() error
+}
+
+// MemoryBackend holds entries in memory. It is useful for tests, and for nodes which
+// need not persist their peers.
+type MemoryBackend struct {
+	mutex   sync.RWMutex
+	buckets map[string]map[string][]byte
+	closed  bool
+}
+
+// NewMemoryBackend creates an empty in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{buckets: make(map[string]map[string][]byte)}
+}
+
+func (b *MemoryBackend) Get(bucket string, key string) ([]byte, bool, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.closed {
+		return nil, false, ErrBackendClosed
+	}
+
+	value, exists := b.buckets[bucket][key]
+	if !exists {
+		return nil, false, nil
+	}
+
+	return append([]byte(nil), value...), true, nil
+}
+
+func (b *MemoryBackend) Put(bucket string, key string, value []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return ErrBackendClosed
+	}
+
+	if b.buckets[bucket] == nil {
+		b.buckets[bucket] = make(map[string][]byte)
+	}
+
+	b.buckets[bucket][key] = append([]byte(nil), value...)
+
+	return nil
+}
+
+func (b *MemoryBackend) Delete(bucket string, key string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return ErrBackendClosed
+	}
+
+	delete(b.buckets[bucket], key)
+
+	return nil
+}
+
+// Iterate iterates over keys in ascending order.
+func (b *MemoryBackend) Iterate(bucket string, fn func(key string, value []byte) error) error {
+	b.mutex.RLock()
+
+	if b.closed {
+		b.mutex.RUnlock()
+		return ErrBackendClosed
+	}
+
+	keys := make([]string, 0, len(b.buckets[bucket]))
+	for key := range b.buckets[bucket] {
+		keys = append(keys, key)
+	}
+
+	values := make([][]byte, len(keys))
+
+	sort.Strings(keys)
+	for i, key := range keys {
+		values[i] = append([]byte(nil), b.buckets[bucket][key]...)
+	}
+
+	b.mutex.RUnlock()
+
+	for i, key := range keys {
+		if err := fn(key, values[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *MemoryBackend) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package peerstore
+
+import (
+	"strings"
+)
+
+// KV is an ordered key-value store such as BadgerDB, LevelDB or BoltDB. A thin
+// wrapper around a store's client library satisfies KV; the peer store does not
+// depend on any of them.
+type KV interface {
+	// Get returns the value under a key, or nil should it not exist.
+	Get(key []byte) ([]byte, error)
+	Set(key []byte, value []byte) error
+	Delete(key []byte) error
+
+	// IteratePrefix calls fn for every key with a prefix in ascending order until fn
+	// returns an error.
+	IteratePrefix(prefix []byte, fn func(key []byte, value []byte) error) error
+
+	Close() error
+}
+
+// KVBackend persists entries in an ordered key-value store, keying them as
+// <bucket>/<key>.
+type KVBackend struct {
+	kv KV
+}
+
+// NewKVBackend creates a backend over an ordered key-value store, such as BadgerDB
+// for nodes persisting millions of entries.
+func NewKVBackend(kv KV) *KVBackend {
+	return &KVBackend{kv: kv}
+}
+
+func kvKey(bucket string, key string) []byte {
+	return []byte(bucket + "/" + key)
+}
+
+func (b *KVBackend) Get(bucket string, key string) ([]byte, bool, error) {
+	value, err := b.kv.Get(kvKey(bucket, key))
+	if err != nil || value == nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (b *KVBackend) Put(bucket string, key string, value []byte) error {
+	return b.kv.Set(kvKey(bucket, key), value)
+}
+
+func (b *KVBackend) Delete(bucket string, key string) error {
+	return b.kv.Delete(kvKey(bucket, key))
+}
+
+func (b *KVBackend) Iterate(bucket string, fn func(key string, value []byte) error) error {
+	prefix := bucket + "/"
+
+	return b.kv.IteratePrefix([]byte(prefix), func(key []byte, value []byte) error {
+		return fn(strings.TrimPrefix(string(key), prefix), value)
+	})
+}
+
+func (b *KVBackend) Close() error {
+	return b.kv.Close()
+}
@@ -0,0 +1,242 @@
+package peerstore
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// redisTimeout bounds how long a command sent to Redis may take.
+const redisTimeout = 5 * time.Second
+
+// RedisBackend persists entries in Redis, holding each bucket in a hash under
+// <prefix><bucket>. It speaks the Redis protocol directly over a single connection,
+// which is redialed should it break.
+type RedisBackend struct {
+	mutex sync.Mutex
+
+	address string
+	prefix  string
+
+	conn   net.Conn
+	reader *bufio.Reader
+	closed bool
+}
+
+// DialRedis connects to a Redis server at a host:port address, namespacing hashes
+// with a prefix such as "noise:".
+func DialRedis(address string, prefix string) (*RedisBackend, error) {
+	b := &RedisBackend{address: address, prefix: prefix}
+
+	if err := b.dial(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (b *RedisBackend) dial() error {
+	conn, err := net.DialTimeout("tcp", b.address, redisTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to dial redis at %s", b.address)
+	}
+
+	b.conn = conn
+	b.reader = bufio.NewReader(conn)
+
+	return nil
+}
+
+// do sends a command and reads its reply.
+func (b *RedisBackend) do(args ...string) (interface{}, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return nil, ErrBackendClosed
+	}
+
+	if b.conn == nil {
+		if err := b.dial(); err != nil {
+			return nil, err
+		}
+	}
+
+	b.conn.SetDeadline(time.Now().Add(redisTimeout))
+
+	reply, err := b.roundTrip(args)
+
+	// Protocol errors leave the connection in an unknown state; redial next time.
+	if _, ok := err.(redisError); err != nil && !ok {
+		b.conn.Close()
+		b.conn = nil
+	}
+
+	return reply, err
+}
+
+func (b *RedisBackend) roundTrip(args []string) (interface{}, error) {
+	buf := make([]byte, 0, 64)
+
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+
+	if _, err := b.conn.Write(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to send redis command")
+	}
+
+	return readRedisReply(b.reader)
+}
+
+// redisError is an error replied by Redis.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// readRedisReply reads a reply encoded in the Redis protocol. Bulk strings are read
+// as []byte, with nil bulk strings being read as nil.
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read redis reply")
+	}
+
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.Errorf("malformed redis reply %q", line)
+	}
+
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "malformed redis bulk string size")
+		}
+
+		if size < 0 {
+			return nil, nil
+		}
+
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, errors.Wrap(err, "failed to read redis bulk string")
+		}
+
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "malformed redis array size")
+		}
+
+		if count < 0 {
+			return nil, nil
+		}
+
+		array := make([]interface{}, count)
+		for i := range array {
+			if array[i], err = readRedisReply(reader); err != nil {
+				return nil, err
+			}
+		}
+
+		return array, nil
+	default:
+		return nil, errors.Errorf("unknown redis reply type %q", kind)
+	}
+}
+
+func (b *RedisBackend) Get(bucket string, key string) ([]byte, bool, error) {
+	reply, err := b.do("HGET", b.prefix+bucket, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, nil
+	}
+
+	return value, true, nil
+}
+
+func (b *RedisBackend) Put(bucket string, key string, value []byte) error {
+	_, err := b.do("HSET", b.prefix+bucket, key, string(value))
+	return err
+}
+
+func (b *RedisBackend) Delete(bucket string, key string) error {
+	_, err := b.do("HDEL", b.prefix+bucket, key)
+	return err
+}
+
+// Iterate iterates over keys in no particular order by scanning the bucket's hash,
+// such that large buckets are not replied in one go.
+func (b *RedisBackend) Iterate(bucket string, fn func(key string, value []byte) error) error {
+	cursor := "0"
+
+	for {
+		reply, err := b.do("HSCAN", b.prefix+bucket, cursor, "COUNT", "1000")
+		if err != nil {
+			return err
+		}
+
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return errors.New("malformed redis HSCAN reply")
+		}
+
+		next, _ := page[0].([]byte)
+		entries, _ := page[1].([]interface{})
+
+		for i := 0; i+1 < len(entries); i += 2 {
+			key, _ := entries[i].([]byte)
+			value, _ := entries[i+1].([]byte)
+
+			if err := fn(string(key), value); err != nil {
+				return err
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+func (b *RedisBackend) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+
+	if b.conn == nil {
+		return nil
+	}
+
+	return b.conn.Close()
+}
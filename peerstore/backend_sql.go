@@ -0,0 +1,85 @@
+package peerstore
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+)
+
+// SQLBackend persists entries in a single table of a SQL database. Any driver
+// accepting SQLite's dialect may be used, such as github.com/mattn/go-sqlite3; the
+// peer store does not import one.
+type SQLBackend struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLiteBackend creates a backend over a table of a SQLite database, creating the
+// table should it not exist.
+func NewSQLiteBackend(db *sql.DB, table string) (*SQLBackend, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS "` + table + `" (
+		bucket TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value BLOB NOT NULL,
+		PRIMARY KEY (bucket, key)
+	) WITHOUT ROWID`)
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create table %s", table)
+	}
+
+	return &SQLBackend{db: db, table: table}, nil
+}
+
+func (b *SQLBackend) Get(bucket string, key string) ([]byte, bool, error) {
+	var value []byte
+
+	err := b.db.QueryRow(`SELECT value FROM "`+b.table+`" WHERE bucket = ? AND key = ?`, bucket, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (b *SQLBackend) Put(bucket string, key string, value []byte) error {
+	_, err := b.db.Exec(`INSERT OR REPLACE INTO "`+b.table+`" (bucket, key, value) VALUES (?, ?, ?)`, bucket, key, value)
+	return err
+}
+
+func (b *SQLBackend) Delete(bucket string, key string) error {
+	_, err := b.db.Exec(`DELETE FROM "`+b.table+`" WHERE bucket = ? AND key = ?`, bucket, key)
+	return err
+}
+
+// Iterate iterates over keys in ascending order.
+func (b *SQLBackend) Iterate(bucket string, fn func(key string, value []byte) error) error {
+	rows, err := b.db.Query(`SELECT key, value FROM "`+b.table+`" WHERE bucket = ? ORDER BY key`, bucket)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var value []byte
+
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (b *SQLBackend) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,310 @@
+package peerstore
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBackend(t *testing.T, backend Backend) {
+	if _, exists, err := backend.Get(PeersBucket, "a"); err != nil || exists {
+		t.Fatalf("expected no entry, got exists=%t err=%v", exists, err)
+	}
+
+	for _, key := range []string{"b", "a", "c"} {
+		if err := backend.Put(PeersBucket, key, []byte("value "+key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := backend.Put(RecordsBucket, "a", []byte("record")); err != nil {
+		t.Fatal(err)
+	}
+
+	value, exists, err := backend.Get(PeersBucket, "a")
+	if err != nil || !exists || !bytes.Equal(value, []byte("value a")) {
+		t.Fatalf("unexpected entry %q exists=%t err=%v", value, exists, err)
+	}
+
+	if err := backend.Delete(PeersBucket, "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	err = backend.Iterate(PeersBucket, func(key string, value []byte) error {
+		if !bytes.Equal(value, []byte("value "+key)) {
+			t.Fatalf("unexpected value %q under key %s", value, key)
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(keys)
+
+	if strings.Join(keys, ",") != "a,c" {
+		t.Fatalf("expected keys a,c, got %v", keys)
+	}
+
+	if err := backend.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryBackend(t *testing.T) {
+	testBackend(t, NewMemoryBackend())
+}
+
+// mapKV is an ordered key-value store over a map.
+type mapKV struct {
+	entries map[string][]byte
+}
+
+func (kv *mapKV) Get(key []byte) ([]byte, error) {
+	return kv.entries[string(key)], nil
+}
+
+func (kv *mapKV) Set(key []byte, value []byte) error {
+	kv.entries[string(key)] = value
+	return nil
+}
+
+func (kv *mapKV) Delete(key []byte) error {
+	delete(kv.entries, string(key))
+	return nil
+}
+
+func (kv *mapKV) IteratePrefix(prefix []byte, fn func(key []byte, value []byte) error) error {
+	var keys []string
+	for key := range kv.entries {
+		if strings.HasPrefix(key, string(prefix)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := fn([]byte(key), kv.entries[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kv *mapKV) Close() error {
+	return nil
+}
+
+func TestKVBackend(t *testing.T) {
+	testBackend(t, NewKVBackend(&mapKV{entries: make(map[string][]byte)}))
+}
+
+// serveRedis serves the hash commands of the Redis protocol over a listener.
+func serveRedis(listener net.Listener) {
+	hashes := make(map[string]map[string]string)
+
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	bulk := func(s string) string {
+		return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+	}
+
+	for {
+		reply, err := readRedisReply(reader)
+		if err != nil {
+			return
+		}
+
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		if hashes[args[1]] == nil {
+			hashes[args[1]] = make(map[string]string)
+		}
+		hash := hashes[args[1]]
+
+		var response string
+
+		switch args[0] {
+		case "HGET":
+			if value, exists := hash[args[2]]; exists {
+				response = bulk(value)
+			} else {
+				response = "$-1\r\n"
+			}
+		case "HSET":
+			hash[args[2]] = args[3]
+			response = ":1\r\n"
+		case "HDEL":
+			delete(hash, args[2])
+			response = ":1\r\n"
+		case "HSCAN":
+			response = "*2\r\n" + bulk("0") + "*" + strconv.Itoa(len(hash)*2) + "\r\n"
+			for key, value := range hash {
+				response += bulk(key) + bulk(value)
+			}
+		default:
+			response = "-ERR unknown command\r\n"
+		}
+
+		conn.Write([]byte(response))
+	}
+}
+
+func TestRedisBackend(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go serveRedis(listener)
+
+	backend, err := DialRedis(listener.Addr().String(), "noise:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testBackend(t, backend)
+}
+
+func TestStoreBackend(t *testing.T) {
+	backend := NewMemoryBackend()
+
+	store := New(time.Hour, nil)
+	store.SetBackend(backend)
+
+	store.Connected("a")
+	store.Connected("b")
+	store.Disconnected("b")
+
+	// A store started afresh reads histories persisted by the prior store.
+	restarted := New(time.Hour, nil)
+	restarted.SetBackend(backend)
+
+	stats, exists := restarted.Get("a")
+	if !exists {
+		t.Fatal("expected peer to be read through from the backend")
+	}
+
+	if stats.Connected || stats.Connections != 1 {
+		t.Fatalf("expected peer connected to the prior store to be disconnected, got %+v", stats)
+	}
+
+	restarted.Connected("a")
+
+	if stats, _ := restarted.Get("a"); stats.Connections != 2 {
+		t.Fatalf("expected connections to accumulate across restarts, got %d", stats.Connections)
+	}
+
+	if all := restarted.All(); len(all) != 2 {
+		t.Fatalf("expected 2 peers, got %d", len(all))
+	}
+
+	// Pages span peers held in memory and those only held by the backend.
+	for i, address := range []string{"a", "b"} {
+		after := ""
+		if i > 0 {
+			after = "a"
+		}
+
+		if page := restarted.Page(after, 1); len(page) != 1 || page[0].Address != address {
+			t.Fatalf("expected page %d to hold peer %s, got %+v", i, address, page)
+		}
+	}
+
+	if page := restarted.Page("b", 1); len(page) != 0 {
+		t.Fatalf("expected no peers past the last page, got %+v", page)
+	}
+
+	restarted.Remove("b")
+
+	if _, exists, _ := backend.Get(PeersBucket, "b"); exists {
+		t.Fatal("expected removed peer to be deleted from the backend")
+	}
+}
+
+func TestStoreBackendCapacity(t *testing.T) {
+	store := New(time.Hour, nil)
+	store.SetBackend(NewMemoryBackend())
+	store.SetCapacity(1)
+
+	store.Connected("a")
+	store.Disconnected("a")
+	store.Connected("b")
+
+	if _, held := store.peers["a"]; held {
+		t.Fatal("expected disconnected peer to be evicted from memory")
+	}
+
+	stats, exists := store.Get("a")
+	if !exists || stats.Connections != 1 {
+		t.Fatalf("expected evicted peer to be read through from the backend, got %+v", stats)
+	}
+
+	if all := store.All(); len(all) != 2 {
+		t.Fatalf("expected 2 peers, got %d", len(all))
+	}
+}
+
+type blockingBackend struct {
+	*MemoryBackend
+
+	release chan struct{}
+}
+
+func (b *blockingBackend) Put(bucket string, key string, value []byte) error {
+	<-b.release
+	return b.MemoryBackend.Put(bucket, key, value)
+}
+
+func TestStoreBackendBlocking(t *testing.T) {
+	backend := &blockingBackend{MemoryBackend: NewMemoryBackend(), release: make(chan struct{})}
+
+	store := New(time.Hour, nil)
+	store.SetBackend(backend)
+
+	go store.Connected("a")
+	defer close(backend.release)
+
+	// Peers held in memory are read while the backend is being written to.
+	deadline := time.Now().Add(3 * time.Second)
+
+	for {
+		done := make(chan bool)
+		go func() {
+			_, exists := store.Get("a")
+			done <- exists
+		}()
+
+		select {
+		case exists := <-done:
+			if exists {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected reads not to be held back by writes to the backend")
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the peer to connect")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,567 @@
+package peerstore
+
+import (
+	"container/list"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/types/clock"
+	"github.com/perlin-network/noise/types/stats"
+)
+
+// DefaultFlapWindow is the default duration under which a connection which is closed
+// is considered to have flapped.
+const DefaultFlapWindow = 30 * time.Second
+
+// DefaultCapacity is the default maximum number of peers of which connection
+// histories are held in memory.
+const DefaultCapacity = 10000
+
+// PeerStats is the connection history of a peer.
+type PeerStats struct {
+	Address string `json:"address"`
+
+	Connected bool `json:"connected"`
+
+	FirstSeen        time.Time `json:"first_seen"`
+	LastConnected    time.Time `json:"last_connected"`
+	LastDisconnected time.Time `json:"last_disconnected,omitempty"`
+
+	// Connections is the number of times the peer has connected.
+	Connections int `json:"connections"`
+
+	// Flaps is the number of connections to the peer which closed shortly after opening.
+	Flaps int `json:"flaps"`
+
+	// Uptime is the total time the peer has been connected for, including the current
+	// connection should there be one.
+	Uptime time.Duration `json:"uptime"`
+
+	// RTT is the moving average of the round-trip times measured to the peer. Zero
+	// if never measured.
+	RTT time.Duration `json:"rtt,omitempty"`
+}
+
+// UptimeRatio returns the fraction of time the peer has been connected since it was first seen.
+func (s PeerStats) UptimeRatio(now time.Time) float64 {
+	lifetime := now.Sub(s.FirstSeen)
+	if lifetime <= 0 {
+		return 0
+	}
+
+	ratio := float64(s.Uptime) / float64(lifetime)
+	if ratio > 1 {
+		ratio = 1
+	}
+
+	return ratio
+}
+
+// Store tracks the connection history of peers by their address. A nil *Store
+// records nothing.
+//
+// Should a backend be set, connection histories are persisted to and read through
+// from it, and only those of peers seen by this process are held in memory. The
+// backend is never written to or read from while holding the store's lock, such that
+// slow backends do not hold back peers of which histories are held in memory. Tags
+// and protections are never persisted.
+//
+// At most a capacity of peers are held in memory, beyond which the histories of the
+// disconnected peers least recently seen are evicted. Evicted histories are read back
+// through from the backend should one be set, and are otherwise forgotten.
+type Store struct {
+	mutex sync.RWMutex
+	peers map[string]*PeerStats
+
+	// order holds the addresses of peers held in memory, the most recently seen first.
+	order    *list.List
+	elements map[string]*list.Element
+	capacity int
+
+	backend Backend
+
+	// dirty holds the connection histories yet to be written to the backend by their
+	// addresses, being nil should they be deleted from it. flushMutex orders writes,
+	// such that older histories are never written over newer ones.
+	dirty      map[string][]byte
+	flushMutex sync.Mutex
+
+	// Tags and protections of peers by their hex-encoded public keys.
+	tags        map[string]map[string]int
+	protections map[string]map[string]struct{}
+
+	flapWindow time.Duration
+	clock      clock.Clock
+}
+
+// New creates an empty peer store, counting connections closed within flapWindow of
+// opening as flaps. A nil clock defaults to the system clock.
+func New(flapWindow time.Duration, c clock.Clock) *Store {
+	return &Store{
+		peers:      make(map[string]*PeerStats),
+		order:      list.New(),
+		elements:   make(map[string]*list.Element),
+		dirty:      make(map[string][]byte),
+		flapWindow: flapWindow,
+		clock:      clock.Or(c),
+	}
+}
+
+// SetBackend persists connection histories to a backend, such that they survive
+// restarts. Peers recorded as connected by a prior process are marked disconnected.
+func (s *Store) SetBackend(backend Backend) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.backend = backend
+}
+
+// SetCapacity sets the maximum number of peers of which connection histories are held
+// in memory. DefaultCapacity if zero.
+func (s *Store) SetCapacity(capacity int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.capacity = capacity
+	s.evict()
+}
+
+// Backend returns the backend the store persists to. Nil if none.
+func (s *Store) Backend() Backend {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.backend
+}
+
+// fetch reads the connection history of a peer through from the backend, should it
+// neither be held in memory nor be pending to be written to the backend. It is to be
+// called without holding the store's lock.
+func (s *Store) fetch(address string) *PeerStats {
+	s.mutex.RLock()
+	_, held := s.peers[address]
+	_, pending := s.dirty[address]
+	backend := s.backend
+	s.mutex.RUnlock()
+
+	if held || pending || backend == nil {
+		return nil
+	}
+
+	value, exists, err := backend.Get(PeersBucket, address)
+	if err != nil {
+		glog.Warningf("Failed to load peer %s from the peer store backend [err=%s]", address, err)
+		return nil
+	}
+
+	if !exists {
+		return nil
+	}
+
+	stats, err := decodeStats(value)
+	if err != nil {
+		glog.Warningf("Failed to decode peer %s from the peer store backend [err=%s]", address, err)
+		return nil
+	}
+
+	return stats
+}
+
+// lookup returns the connection history of a peer held in memory, or otherwise one
+// fetched from the backend, unless the peer has since been removed. The store must be
+// locked.
+func (s *Store) lookup(address string, fetched *PeerStats) (*PeerStats, bool) {
+	if stats, exists := s.peers[address]; exists {
+		return stats, true
+	}
+
+	if value, pending := s.dirty[address]; pending {
+		if value == nil {
+			return nil, false
+		}
+
+		// The peer was evicted before its history was written to the backend.
+		if stats, err := decodeStats(value); err == nil {
+			return stats, true
+		}
+	}
+
+	return fetched, fetched != nil
+}
+
+// hold keeps the connection history of a peer in memory, marking it as the most
+// recently seen, and evicts peers beyond the store's capacity. The store must be
+// locked.
+func (s *Store) hold(stats *PeerStats) {
+	s.peers[stats.Address] = stats
+
+	if element, exists := s.elements[stats.Address]; exists {
+		s.order.MoveToFront(element)
+	} else {
+		s.elements[stats.Address] = s.order.PushFront(stats.Address)
+	}
+
+	s.evict()
+}
+
+// release drops the connection history of a peer from memory. The store must be
+// locked.
+func (s *Store) release(address string) {
+	delete(s.peers, address)
+
+	if element, exists := s.elements[address]; exists {
+		s.order.Remove(element)
+		delete(s.elements, address)
+	}
+}
+
+// evict drops the connection histories of the disconnected peers least recently seen
+// from memory until at most the store's capacity of peers are held. Connected peers
+// are never evicted. The store must be locked.
+func (s *Store) evict() {
+	capacity := s.capacity
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+
+	for element := s.order.Back(); element != nil && len(s.peers) > capacity; {
+		address := element.Value.(string)
+		element = element.Prev()
+
+		if !s.peers[address].Connected {
+			s.release(address)
+		}
+	}
+}
+
+// decodeStats decodes a persisted connection history. The peer's connection at the
+// time it was persisted belonged to a prior process, and so is considered closed.
+func decodeStats(value []byte) (*PeerStats, error) {
+	stats := new(PeerStats)
+	if err := json.Unmarshal(value, stats); err != nil {
+		return nil, err
+	}
+
+	if stats.Connected {
+		stats.Connected = false
+		stats.LastDisconnected = stats.LastConnected
+	}
+
+	return stats, nil
+}
+
+// persist queues the connection history of a peer to be written through to the
+// backend. The store must be locked.
+func (s *Store) persist(stats *PeerStats) {
+	if s.backend == nil {
+		return
+	}
+
+	value, err := json.Marshal(stats)
+	if err != nil {
+		glog.Warningf("Failed to persist peer %s to the peer store backend [err=%s]", stats.Address, err)
+		return
+	}
+
+	s.dirty[stats.Address] = value
+}
+
+// unpersist queues the connection history of a peer to be deleted from the backend.
+// The store must be locked.
+func (s *Store) unpersist(address string) {
+	if s.backend != nil {
+		s.dirty[address] = nil
+	}
+}
+
+// flush writes the connection histories queued to be written to the backend. It is to
+// be called without holding the store's lock.
+func (s *Store) flush() {
+	s.flushMutex.Lock()
+	defer s.flushMutex.Unlock()
+
+	s.mutex.Lock()
+	dirty, backend := s.dirty, s.backend
+	s.dirty = make(map[string][]byte)
+	s.mutex.Unlock()
+
+	if backend == nil {
+		return
+	}
+
+	for address, value := range dirty {
+		if value == nil {
+			if err := backend.Delete(PeersBucket, address); err != nil {
+				glog.Warningf("Failed to remove peer %s from the peer store backend [err=%s]", address, err)
+			}
+			continue
+		}
+
+		if err := backend.Put(PeersBucket, address, value); err != nil {
+			glog.Warningf("Failed to persist peer %s to the peer store backend [err=%s]", address, err)
+		}
+	}
+}
+
+// Connected records that a peer has connected.
+func (s *Store) Connected(address string) {
+	if s == nil {
+		return
+	}
+
+	now := s.clock.Now()
+	fetched := s.fetch(address)
+
+	s.mutex.Lock()
+	defer s.flush()
+	defer s.mutex.Unlock()
+
+	stats, exists := s.lookup(address, fetched)
+	if !exists {
+		stats = &PeerStats{Address: address, FirstSeen: now}
+	}
+
+	if stats.Connected {
+		s.hold(stats)
+		return
+	}
+
+	stats.Connected = true
+	stats.LastConnected = now
+	stats.Connections++
+
+	s.persist(stats)
+	s.hold(stats)
+}
+
+// Disconnected records that a peer has disconnected.
+func (s *Store) Disconnected(address string) {
+	if s == nil {
+		return
+	}
+
+	now := s.clock.Now()
+
+	s.mutex.Lock()
+	defer s.flush()
+	defer s.mutex.Unlock()
+
+	stats, exists := s.peers[address]
+	if !exists || !stats.Connected {
+		return
+	}
+
+	session := now.Sub(stats.LastConnected)
+
+	stats.Connected = false
+	stats.LastDisconnected = now
+	stats.Uptime += session
+
+	if session < s.flapWindow {
+		stats.Flaps++
+	}
+
+	s.persist(stats)
+	s.hold(stats)
+}
+
+// rttSmoothing is the weight given to new samples in the moving average of a peer's
+// round-trip time.
+const rttSmoothing = 0.25
+
+// RecordRTT records a round-trip time measured to a peer, which must be known to the
+// store.
+func (s *Store) RecordRTT(address string, rtt time.Duration) {
+	if s == nil {
+		return
+	}
+
+	fetched := s.fetch(address)
+
+	s.mutex.Lock()
+	defer s.flush()
+	defer s.mutex.Unlock()
+
+	peer, exists := s.lookup(address, fetched)
+	if !exists {
+		return
+	}
+
+	if peer.RTT == 0 {
+		peer.RTT = rtt
+	} else {
+		peer.RTT = time.Duration(stats.Smooth(float64(peer.RTT), float64(rtt), rttSmoothing))
+	}
+
+	s.persist(peer)
+	s.hold(peer)
+}
+
+// snapshot copies the stats of a peer, accounting for the uptime of its current connection.
+func snapshot(stats *PeerStats, now time.Time) PeerStats {
+	copied := *stats
+	if copied.Connected {
+		copied.Uptime += now.Sub(copied.LastConnected)
+	}
+	return copied
+}
+
+// Get returns the connection history of a peer by its address.
+func (s *Store) Get(address string) (PeerStats, bool) {
+	if s == nil {
+		return PeerStats{}, false
+	}
+
+	fetched := s.fetch(address)
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stats, exists := s.lookup(address, fetched)
+	if !exists {
+		return PeerStats{}, false
+	}
+
+	return snapshot(stats, s.clock.Now()), true
+}
+
+// All returns the connection history of all peers sorted by address. It loads all
+// peers the backend holds into memory at once, and so Page is to be preferred should
+// the backend hold many peers.
+func (s *Store) All() []PeerStats {
+	return s.Page("", -1)
+}
+
+// Page returns the connection history of at most limit peers sorted by address, whose
+// addresses sort after a given address. Pages are iterated by passing the address
+// of the last peer of each page to fetch the next. A negative limit returns all peers.
+func (s *Store) Page(after string, limit int) []PeerStats {
+	if s == nil || limit == 0 {
+		return nil
+	}
+
+	now := s.clock.Now()
+
+	var page []PeerStats
+
+	// trim sorts the page and drops the peers beyond the limit. Unless forced, it only
+	// does so should there be twice as many, such that peers are not sorted for each
+	// one added while holding at most twice as many as the limit at once.
+	trim := func(force bool) {
+		if !force && (limit < 0 || len(page) < 2*limit) {
+			return
+		}
+
+		sort.Slice(page, func(i, j int) bool {
+			return page[i].Address < page[j].Address
+		})
+
+		if limit >= 0 && len(page) > limit {
+			page = page[:limit]
+		}
+	}
+
+	s.mutex.RLock()
+	skip := make(map[string]struct{}, len(s.peers))
+	for address, stats := range s.peers {
+		skip[address] = struct{}{}
+
+		if address > after {
+			page = append(page, snapshot(stats, now))
+		}
+	}
+	for address, value := range s.dirty {
+		if _, held := s.peers[address]; held {
+			continue
+		}
+		skip[address] = struct{}{}
+
+		// Include peers evicted before their histories were written to the backend.
+		if value == nil || address <= after {
+			continue
+		}
+
+		if stats, err := decodeStats(value); err == nil {
+			page = append(page, *stats)
+		}
+	}
+	backend := s.backend
+	s.mutex.RUnlock()
+
+	trim(false)
+
+	// Include peers which were only ever seen by prior processes.
+	if backend != nil {
+		err := backend.Iterate(PeersBucket, func(address string, value []byte) error {
+			if _, skipped := skip[address]; skipped || address <= after {
+				return nil
+			}
+
+			stats, err := decodeStats(value)
+			if err != nil {
+				return err
+			}
+
+			page = append(page, *stats)
+			trim(false)
+
+			return nil
+		})
+
+		if err != nil {
+			glog.Warningf("Failed to load peers from the peer store backend [err=%s]", err)
+		}
+	}
+
+	trim(true)
+
+	return page
+}
+
+// Migrate moves the connection history of a peer from an address to the address it
+// has moved to, replacing any history of the latter.
+func (s *Store) Migrate(from string, to string) {
+	if s == nil || from == to {
+		return
+	}
+
+	fetched := s.fetch(from)
+
+	s.mutex.Lock()
+	defer s.flush()
+	defer s.mutex.Unlock()
+
+	stats, exists := s.lookup(from, fetched)
+	if !exists {
+		return
+	}
+
+	s.release(from)
+	s.unpersist(from)
+
+	stats.Address = to
+
+	s.persist(stats)
+	s.hold(stats)
+}
+
+// Remove forgets the connection history of a peer.
+func (s *Store) Remove(address string) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.flush()
+	defer s.mutex.Unlock()
+
+	s.release(address)
+	s.unpersist(address)
+}
@@ -0,0 +1,145 @@
+package peerstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	store := New(time.Hour, nil)
+
+	store.Connected("a")
+	store.Connected("a")
+	time.Sleep(10 * time.Millisecond)
+	store.Disconnected("a")
+	store.Connected("a")
+
+	stats, exists := store.Get("a")
+	if !exists {
+		t.Fatal("expected peer to be tracked")
+	}
+
+	if !stats.Connected || stats.Connections != 2 || stats.Flaps != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	if stats.Uptime < 10*time.Millisecond {
+		t.Fatalf("expected uptime to be accumulated, got %s", stats.Uptime)
+	}
+
+	if ratio := stats.UptimeRatio(time.Now()); ratio <= 0 || ratio > 1 {
+		t.Fatalf("unexpected uptime ratio %f", ratio)
+	}
+
+	store.Disconnected("b")
+
+	if _, exists := store.Get("b"); exists {
+		t.Fatal("expected unknown peers disconnecting to not be tracked")
+	}
+
+	if all := store.All(); len(all) != 1 {
+		t.Fatalf("expected 1 peer, got %d", len(all))
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var store *Store
+
+	store.Connected("a")
+	store.Disconnected("a")
+
+	if _, exists := store.Get("a"); exists {
+		t.Fatal("expected nil store to track nothing")
+	}
+}
+
+func TestTags(t *testing.T) {
+	store := New(DefaultFlapWindow, nil)
+
+	store.Tag("a", "validator", 10)
+	store.Tag("a", "provider", 5)
+
+	if weight := store.Weight("a"); weight != 15 {
+		t.Fatalf("expected weight 15, got %d", weight)
+	}
+
+	store.Untag("a", "provider")
+
+	if tags := store.Tags("a"); len(tags) != 1 || tags["validator"] != 10 {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+
+	store.Protect("a", "validator")
+	store.Protect("a", "provider")
+
+	if !store.Unprotect("a", "provider") || !store.Protected("a") {
+		t.Fatal("expected peer to remain protected by its other tag")
+	}
+
+	if store.Unprotect("a", "validator") || store.Protected("a") {
+		t.Fatal("expected peer to no longer be protected")
+	}
+}
+
+func TestRecordRTT(t *testing.T) {
+	store := New(time.Hour, nil)
+
+	store.RecordRTT("a", time.Second)
+	if _, exists := store.Get("a"); exists {
+		t.Fatal("expected round-trip times of unknown peers to not be recorded")
+	}
+
+	store.Connected("a")
+	store.RecordRTT("a", 100*time.Millisecond)
+	store.RecordRTT("a", 200*time.Millisecond)
+
+	stats, _ := store.Get("a")
+	if stats.RTT != 125*time.Millisecond {
+		t.Fatalf("expected smoothed round-trip time of 125ms, got %s", stats.RTT)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	store := New(time.Hour, nil)
+
+	store.Connected("a")
+	store.Migrate("a", "b")
+
+	if _, exists := store.Get("a"); exists {
+		t.Fatal("expected history to be moved off of the previous address")
+	}
+
+	stats, exists := store.Get("b")
+	if !exists || stats.Address != "b" || !stats.Connected || stats.Connections != 1 {
+		t.Fatalf("expected history to be moved to the new address, got %+v", stats)
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	store := New(time.Hour, nil)
+	store.SetCapacity(2)
+
+	store.Connected("a")
+	store.Connected("b")
+	store.Disconnected("b")
+	store.Connected("c")
+	store.Disconnected("c")
+
+	// Peer b is the disconnected peer least recently seen.
+	if _, exists := store.Get("b"); exists {
+		t.Fatal("expected the peer least recently seen to be evicted")
+	}
+
+	store.Connected("d")
+
+	// Connected peers are held beyond the capacity.
+	for _, address := range []string{"a", "d"} {
+		if _, exists := store.Get(address); !exists {
+			t.Fatalf("expected connected peer %s to not be evicted", address)
+		}
+	}
+
+	if all := store.All(); len(all) != 2 {
+		t.Fatalf("expected 2 peers, got %d", len(all))
+	}
+}
@@ -0,0 +1,124 @@
+package peerstore
+
+// Tag sets the weight of a tag on a peer, marking how important the peer is to the
+// application. Peers are keyed by their hex-encoded public keys.
+func (s *Store) Tag(key string, tag string, weight int) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.tags == nil {
+		s.tags = make(map[string]map[string]int)
+	}
+
+	if s.tags[key] == nil {
+		s.tags[key] = make(map[string]int)
+	}
+
+	s.tags[key][tag] = weight
+}
+
+// Untag removes a tag from a peer.
+func (s *Store) Untag(key string, tag string) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.tags[key], tag)
+
+	if len(s.tags[key]) == 0 {
+		delete(s.tags, key)
+	}
+}
+
+// Tags returns the weights of all tags on a peer.
+func (s *Store) Tags(key string) map[string]int {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tags := make(map[string]int, len(s.tags[key]))
+	for tag, weight := range s.tags[key] {
+		tags[tag] = weight
+	}
+
+	return tags
+}
+
+// Weight returns the sum of the weights of all tags on a peer.
+func (s *Store) Weight(key string) (weight int) {
+	if s == nil {
+		return 0
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, w := range s.tags[key] {
+		weight += w
+	}
+
+	return
+}
+
+// Protect protects a peer from being disconnected, evicted or rate limited on behalf of
+// a tag. A peer stays protected until all of its protecting tags are removed.
+func (s *Store) Protect(key string, tag string) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.protections == nil {
+		s.protections = make(map[string]map[string]struct{})
+	}
+
+	if s.protections[key] == nil {
+		s.protections[key] = make(map[string]struct{})
+	}
+
+	s.protections[key][tag] = struct{}{}
+}
+
+// Unprotect removes the protection of a peer on behalf of a tag, and returns true
+// should the peer still be protected by other tags.
+func (s *Store) Unprotect(key string, tag string) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.protections[key], tag)
+
+	if len(s.protections[key]) == 0 {
+		delete(s.protections, key)
+		return false
+	}
+
+	return true
+}
+
+// Protected returns true should a peer be protected by any tag.
+func (s *Store) Protected(key string) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.protections[key]) > 0
+}
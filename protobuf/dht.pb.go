@@ -0,0 +1,378 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: protobuf/dht.proto
+
+package protobuf
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type LookupNodeRequest struct {
+	Target               *ID      `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LookupNodeRequest) Reset()         { *m = LookupNodeRequest{} }
+func (m *LookupNodeRequest) String() string { return proto.CompactTextString(m) }
+func (*LookupNodeRequest) ProtoMessage()    {}
+func (*LookupNodeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dht_8f51766adc4bd606, []int{0}
+}
+func (m *LookupNodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LookupNodeRequest.Unmarshal(m, b)
+}
+func (m *LookupNodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LookupNodeRequest.Marshal(b, m, deterministic)
+}
+func (dst *LookupNodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LookupNodeRequest.Merge(dst, src)
+}
+func (m *LookupNodeRequest) XXX_Size() int {
+	return xxx_messageInfo_LookupNodeRequest.Size(m)
+}
+func (m *LookupNodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LookupNodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LookupNodeRequest proto.InternalMessageInfo
+
+func (m *LookupNodeRequest) GetTarget() *ID {
+	if m != nil {
+		return m.Target
+	}
+	return nil
+}
+
+type LookupNodeResponse struct {
+	Peers                []*ID    `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LookupNodeResponse) Reset()         { *m = LookupNodeResponse{} }
+func (m *LookupNodeResponse) String() string { return proto.CompactTextString(m) }
+func (*LookupNodeResponse) ProtoMessage()    {}
+func (*LookupNodeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dht_8f51766adc4bd606, []int{1}
+}
+func (m *LookupNodeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LookupNodeResponse.Unmarshal(m, b)
+}
+func (m *LookupNodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LookupNodeResponse.Marshal(b, m, deterministic)
+}
+func (dst *LookupNodeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LookupNodeResponse.Merge(dst, src)
+}
+func (m *LookupNodeResponse) XXX_Size() int {
+	return xxx_messageInfo_LookupNodeResponse.Size(m)
+}
+func (m *LookupNodeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LookupNodeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LookupNodeResponse proto.InternalMessageInfo
+
+func (m *LookupNodeResponse) GetPeers() []*ID {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+// Record is a value stored in the DHT under a key.
+type Record struct {
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// expires_at is the time in nanoseconds since the Unix epoch after which the record is discarded.
+	ExpiresAt int64 `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// timestamp is the time in nanoseconds since the Unix epoch at which the record was published.
+	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// publisher is the public key of the peer which published the record.
+	Publisher []byte `protobuf:"bytes,5,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	// signature is the publisher's signature of the record's key, value and timestamp.
+	Signature            []byte   `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Record) Reset()         { *m = Record{} }
+func (m *Record) String() string { return proto.CompactTextString(m) }
+func (*Record) ProtoMessage()    {}
+func (*Record) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dht_8f51766adc4bd606, []int{2}
+}
+func (m *Record) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Record.Unmarshal(m, b)
+}
+func (m *Record) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Record.Marshal(b, m, deterministic)
+}
+func (dst *Record) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Record.Merge(dst, src)
+}
+func (m *Record) XXX_Size() int {
+	return xxx_messageInfo_Record.Size(m)
+}
+func (m *Record) XXX_DiscardUnknown() {
+	xxx_messageInfo_Record.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Record proto.InternalMessageInfo
+
+func (m *Record) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *Record) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Record) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *Record) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Record) GetPublisher() []byte {
+	if m != nil {
+		return m.Publisher
+	}
+	return nil
+}
+
+func (m *Record) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type StoreRequest struct {
+	Record               *Record  `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreRequest) Reset()         { *m = StoreRequest{} }
+func (m *StoreRequest) String() string { return proto.CompactTextString(m) }
+func (*StoreRequest) ProtoMessage()    {}
+func (*StoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dht_8f51766adc4bd606, []int{3}
+}
+func (m *StoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreRequest.Unmarshal(m, b)
+}
+func (m *StoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreRequest.Marshal(b, m, deterministic)
+}
+func (dst *StoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreRequest.Merge(dst, src)
+}
+func (m *StoreRequest) XXX_Size() int {
+	return xxx_messageInfo_StoreRequest.Size(m)
+}
+func (m *StoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreRequest proto.InternalMessageInfo
+
+func (m *StoreRequest) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+type StoreResponse struct {
+	Stored               bool     `protobuf:"varint,1,opt,name=stored,proto3" json:"stored,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreResponse) Reset()         { *m = StoreResponse{} }
+func (m *StoreResponse) String() string { return proto.CompactTextString(m) }
+func (*StoreResponse) ProtoMessage()    {}
+func (*StoreResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dht_8f51766adc4bd606, []int{4}
+}
+func (m *StoreResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreResponse.Unmarshal(m, b)
+}
+func (m *StoreResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreResponse.Marshal(b, m, deterministic)
+}
+func (dst *StoreResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreResponse.Merge(dst, src)
+}
+func (m *StoreResponse) XXX_Size() int {
+	return xxx_messageInfo_StoreResponse.Size(m)
+}
+func (m *StoreResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreResponse proto.InternalMessageInfo
+
+func (m *StoreResponse) GetStored() bool {
+	if m != nil {
+		return m.Stored
+	}
+	return false
+}
+
+type FindValueRequest struct {
+	Key                  []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindValueRequest) Reset()         { *m = FindValueRequest{} }
+func (m *FindValueRequest) String() string { return proto.CompactTextString(m) }
+func (*FindValueRequest) ProtoMessage()    {}
+func (*FindValueRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dht_8f51766adc4bd606, []int{5}
+}
+func (m *FindValueRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindValueRequest.Unmarshal(m, b)
+}
+func (m *FindValueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindValueRequest.Marshal(b, m, deterministic)
+}
+func (dst *FindValueRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindValueRequest.Merge(dst, src)
+}
+func (m *FindValueRequest) XXX_Size() int {
+	return xxx_messageInfo_FindValueRequest.Size(m)
+}
+func (m *FindValueRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindValueRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindValueRequest proto.InternalMessageInfo
+
+func (m *FindValueRequest) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type FindValueResponse struct {
+	// record is set should the responder hold a record under the requested key.
+	Record *Record `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	// peers closest to the requested key otherwise.
+	Peers                []*ID    `protobuf:"bytes,2,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindValueResponse) Reset()         { *m = FindValueResponse{} }
+func (m *FindValueResponse) String() string { return proto.CompactTextString(m) }
+func (*FindValueResponse) ProtoMessage()    {}
+func (*FindValueResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dht_8f51766adc4bd606, []int{6}
+}
+func (m *FindValueResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindValueResponse.Unmarshal(m, b)
+}
+func (m *FindValueResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindValueResponse.Marshal(b, m, deterministic)
+}
+func (dst *FindValueResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindValueResponse.Merge(dst, src)
+}
+func (m *FindValueResponse) XXX_Size() int {
+	return xxx_messageInfo_FindValueResponse.Size(m)
+}
+func (m *FindValueResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindValueResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindValueResponse proto.InternalMessageInfo
+
+func (m *FindValueResponse) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+func (m *FindValueResponse) GetPeers() []*ID {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*LookupNodeRequest)(nil), "protobuf.LookupNodeRequest")
+	proto.RegisterType((*LookupNodeResponse)(nil), "protobuf.LookupNodeResponse")
+	proto.RegisterType((*Record)(nil), "protobuf.Record")
+	proto.RegisterType((*StoreRequest)(nil), "protobuf.StoreRequest")
+	proto.RegisterType((*StoreResponse)(nil), "protobuf.StoreResponse")
+	proto.RegisterType((*FindValueRequest)(nil), "protobuf.FindValueRequest")
+	proto.RegisterType((*FindValueResponse)(nil), "protobuf.FindValueResponse")
+}
+
+func init() { proto.RegisterFile("protobuf/dht.proto", fileDescriptor_dht_8f51766adc4bd606) }
+
+var fileDescriptor_dht_8f51766adc4bd606 = []byte{
+	// 343 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xd1, 0x4b, 0xeb, 0x30,
+	0x14, 0xc6, 0xe9, 0x7a, 0x57, 0xb6, 0x73, 0x77, 0x61, 0x0b, 0x97, 0x19, 0x44, 0x61, 0x84, 0x81,
+	0x7b, 0xaa, 0xa0, 0x2f, 0xf3, 0xd1, 0x31, 0x44, 0x41, 0x64, 0x44, 0xf1, 0x55, 0x3a, 0x7b, 0xdc,
+	0xc2, 0xba, 0x26, 0x26, 0xe9, 0xd0, 0x3f, 0xc9, 0xff, 0x52, 0xda, 0x64, 0xdd, 0x60, 0x08, 0xbe,
+	0xe5, 0x7c, 0xbf, 0x7c, 0x49, 0xce, 0x77, 0x02, 0x44, 0x69, 0x69, 0xe5, 0xbc, 0x78, 0x3b, 0x4f,
+	0x97, 0x36, 0xae, 0x0a, 0xd2, 0xda, 0x6a, 0xc7, 0x47, 0x35, 0xc5, 0x7c, 0x83, 0x99, 0x54, 0xe8,
+	0xb6, 0xb0, 0x2b, 0xe8, 0xdd, 0x4b, 0xb9, 0x2a, 0xd4, 0x83, 0x4c, 0x91, 0xe3, 0x7b, 0x81, 0xc6,
+	0x92, 0x21, 0x44, 0x36, 0xd1, 0x0b, 0xb4, 0x34, 0x18, 0x04, 0xa3, 0xbf, 0x17, 0x9d, 0x78, 0x6b,
+	0x8f, 0xef, 0xa6, 0xdc, 0x33, 0x36, 0x06, 0xb2, 0x6f, 0x35, 0x4a, 0xe6, 0x06, 0x09, 0x83, 0xa6,
+	0x42, 0xd4, 0x86, 0x06, 0x83, 0xf0, 0xc0, 0xea, 0x10, 0xfb, 0x0a, 0x20, 0xe2, 0xf8, 0x2a, 0x75,
+	0x4a, 0xba, 0x10, 0xae, 0xf0, 0xb3, 0xba, 0xa7, 0xc3, 0xcb, 0x25, 0xf9, 0x0f, 0xcd, 0x4d, 0x92,
+	0x15, 0x48, 0x1b, 0x95, 0xe6, 0x0a, 0x72, 0x0a, 0x80, 0x1f, 0x4a, 0x68, 0x34, 0x2f, 0x89, 0xa5,
+	0xe1, 0x20, 0x18, 0x85, 0xbc, 0xed, 0x95, 0x6b, 0x4b, 0x4e, 0xa0, 0x6d, 0xc5, 0x1a, 0x8d, 0x4d,
+	0xd6, 0x8a, 0xfe, 0x71, 0xb4, 0x16, 0x4a, 0xaa, 0x8a, 0x79, 0x26, 0xcc, 0x12, 0x35, 0x6d, 0x56,
+	0xc7, 0xee, 0x84, 0x92, 0x1a, 0xb1, 0xc8, 0x13, 0x5b, 0x68, 0xa4, 0x91, 0xa3, 0xb5, 0xc0, 0xc6,
+	0xd0, 0x79, 0xb4, 0x52, 0xd7, 0xd9, 0x8c, 0x20, 0xd2, 0xd5, 0xd3, 0x7d, 0x36, 0xdd, 0x5d, 0x83,
+	0xae, 0x25, 0xee, 0x39, 0x3b, 0x83, 0x7f, 0xde, 0xe9, 0xa3, 0xe9, 0x43, 0x64, 0x4a, 0xc1, 0x59,
+	0x5b, 0xdc, 0x57, 0x6c, 0x08, 0xdd, 0x1b, 0x91, 0xa7, 0xcf, 0x65, 0xa3, 0xdb, 0x6b, 0x0e, 0x72,
+	0x61, 0x09, 0xf4, 0xf6, 0x76, 0xf9, 0x23, 0x7f, 0xfd, 0x9a, 0xdd, 0x5c, 0x1a, 0x3f, 0xce, 0x65,
+	0x32, 0x84, 0xbe, 0xd4, 0x8b, 0x58, 0xa1, 0xce, 0x44, 0x1e, 0xe7, 0x52, 0x18, 0xff, 0x4d, 0x26,
+	0xad, 0xe9, 0xed, 0xd3, 0xac, 0x5c, 0xcd, 0x82, 0x79, 0x54, 0x49, 0x97, 0xdf, 0x03, 0x00, 0xce,
+	0x96, 0x94, 0x81, 0x72, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,518 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: protobuf/envelope.proto
+
+package protobuf
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import any "github.com/golang/protobuf/ptypes/any"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Compression is the codec the data of Bytes is compressed with.
+type Compression int32
+
+const (
+	Compression_COMPRESSION_NONE    Compression = 0
+	Compression_COMPRESSION_DEFLATE Compression = 1
+)
+
+var Compression_name = map[int32]string{
+	0: "COMPRESSION_NONE",
+	1: "COMPRESSION_DEFLATE",
+}
+var Compression_value = map[string]int32{
+	"COMPRESSION_NONE":    0,
+	"COMPRESSION_DEFLATE": 1,
+}
+
+func (x Compression) String() string {
+	return proto.EnumName(Compression_name, int32(x))
+}
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_envelope_5538b08c1c5f1ceb, []int{0}
+}
+
+type ID struct {
+	PublicKey            []byte   `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ID) Reset()         { *m = ID{} }
+func (m *ID) String() string { return proto.CompactTextString(m) }
+func (*ID) ProtoMessage()    {}
+func (*ID) Descriptor() ([]byte, []int) {
+	return fileDescriptor_envelope_5538b08c1c5f1ceb, []int{0}
+}
+func (m *ID) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ID.Unmarshal(m, b)
+}
+func (m *ID) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ID.Marshal(b, m, deterministic)
+}
+func (dst *ID) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ID.Merge(dst, src)
+}
+func (m *ID) XXX_Size() int {
+	return xxx_messageInfo_ID.Size(m)
+}
+func (m *ID) XXX_DiscardUnknown() {
+	xxx_messageInfo_ID.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ID proto.InternalMessageInfo
+
+func (m *ID) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *ID) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type Message struct {
+	Message *any.Any `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// Sender's address and public key.
+	Sender *ID `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	// Sender's signature of message.
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// request_nonce is the request/response ID. Null if ID associated to a message is not a request/response.
+	RequestNonce uint64 `protobuf:"varint,4,opt,name=request_nonce,json=requestNonce,proto3" json:"request_nonce,omitempty"`
+	// message_nonce is the sequence ID.
+	MessageNonce uint64 `protobuf:"varint,5,opt,name=message_nonce,json=messageNonce,proto3" json:"message_nonce,omitempty"`
+	// lamport_timestamp is the sender's logical clock at the time of sending. Zero if the sender
+	// does not keep a logical clock. Signed since envelope version 5.
+	LamportTimestamp uint64 `protobuf:"varint,6,opt,name=lamport_timestamp,json=lamportTimestamp,proto3" json:"lamport_timestamp,omitempty"`
+	// version is the envelope version this message is encoded in. Zero if the sender
+	// predates envelope versioning.
+	Version uint32 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	// max_version is the highest envelope version the sender supports.
+	MaxVersion uint32 `protobuf:"varint,8,opt,name=max_version,json=maxVersion,proto3" json:"max_version,omitempty"`
+	// priority marks the message as sent over the priority lane, being delivered as soon
+	// as it is received rather than in order. Priority messages carry no message_nonce.
+	// Since envelope version 2.
+	Priority bool `protobuf:"varint,9,opt,name=priority,proto3" json:"priority,omitempty"`
+	// capabilities is a bitmask of the services the sender provides, such as relaying
+	// or serving state snapshots. Zero if the sender advertises none.
+	Capabilities uint64 `protobuf:"varint,10,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// batch_index and batch_proof prove the message to be included within a batch of
+	// messages whose merkle root was signed at once, in place of the message itself.
+	// Empty should the message be signed alone. Since envelope version 3.
+	BatchIndex uint32   `protobuf:"varint,11,opt,name=batch_index,json=batchIndex,proto3" json:"batch_index,omitempty"`
+	BatchProof [][]byte `protobuf:"bytes,12,rep,name=batch_proof,json=batchProof,proto3" json:"batch_proof,omitempty"`
+	// id is a random identifier the sender assigns the message, which its signature
+	// covers, such that receivers may tell messages apart regardless of the connections
+	// they were received over. Since envelope version 4.
+	Id []byte `protobuf:"bytes,13,opt,name=id,proto3" json:"id,omitempty"`
+	// ticket is the session ticket the receiver issued the sender, presented in place
+	// of having the sender's identity verified anew upon reconnecting. It is bound to
+	// the sender's key and address, and thereby not covered by the signature. Empty
+	// should the sender present none.
+	Ticket               []byte   `protobuf:"bytes,14,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Message) Reset()         { *m = Message{} }
+func (m *Message) String() string { return proto.CompactTextString(m) }
+func (*Message) ProtoMessage()    {}
+func (*Message) Descriptor() ([]byte, []int) {
+	return fileDescriptor_envelope_5538b08c1c5f1ceb, []int{1}
+}
+func (m *Message) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Message.Unmarshal(m, b)
+}
+func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+}
+func (dst *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(dst, src)
+}
+func (m *Message) XXX_Size() int {
+	return xxx_messageInfo_Message.Size(m)
+}
+func (m *Message) XXX_DiscardUnknown() {
+	xxx_messageInfo_Message.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Message proto.InternalMessageInfo
+
+func (m *Message) GetMessage() *any.Any {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (m *Message) GetSender() *ID {
+	if m != nil {
+		return m.Sender
+	}
+	return nil
+}
+
+func (m *Message) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Message) GetRequestNonce() uint64 {
+	if m != nil {
+		return m.RequestNonce
+	}
+	return 0
+}
+
+func (m *Message) GetMessageNonce() uint64 {
+	if m != nil {
+		return m.MessageNonce
+	}
+	return 0
+}
+
+func (m *Message) GetLamportTimestamp() uint64 {
+	if m != nil {
+		return m.LamportTimestamp
+	}
+	return 0
+}
+
+func (m *Message) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Message) GetMaxVersion() uint32 {
+	if m != nil {
+		return m.MaxVersion
+	}
+	return 0
+}
+
+func (m *Message) GetPriority() bool {
+	if m != nil {
+		return m.Priority
+	}
+	return false
+}
+
+func (m *Message) GetCapabilities() uint64 {
+	if m != nil {
+		return m.Capabilities
+	}
+	return 0
+}
+
+func (m *Message) GetBatchIndex() uint32 {
+	if m != nil {
+		return m.BatchIndex
+	}
+	return 0
+}
+
+func (m *Message) GetBatchProof() [][]byte {
+	if m != nil {
+		return m.BatchProof
+	}
+	return nil
+}
+
+func (m *Message) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Message) GetTicket() []byte {
+	if m != nil {
+		return m.Ticket
+	}
+	return nil
+}
+
+type Bytes struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// compression is the codec data is compressed with. Only ever set once the peer
+	// has agreed to it through a StreamCompressionRequest.
+	Compression          Compression `protobuf:"varint,2,opt,name=compression,proto3,enum=protobuf.Compression" json:"compression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Bytes) Reset()         { *m = Bytes{} }
+func (m *Bytes) String() string { return proto.CompactTextString(m) }
+func (*Bytes) ProtoMessage()    {}
+func (*Bytes) Descriptor() ([]byte, []int) {
+	return fileDescriptor_envelope_5538b08c1c5f1ceb, []int{2}
+}
+func (m *Bytes) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Bytes.Unmarshal(m, b)
+}
+func (m *Bytes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Bytes.Marshal(b, m, deterministic)
+}
+func (dst *Bytes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Bytes.Merge(dst, src)
+}
+func (m *Bytes) XXX_Size() int {
+	return xxx_messageInfo_Bytes.Size(m)
+}
+func (m *Bytes) XXX_DiscardUnknown() {
+	xxx_messageInfo_Bytes.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Bytes proto.InternalMessageInfo
+
+func (m *Bytes) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Bytes) GetCompression() Compression {
+	if m != nil {
+		return m.Compression
+	}
+	return Compression_COMPRESSION_NONE
+}
+
+// StreamCompressionRequest asks a peer to select one of the codecs it supports for
+// the data we stream to it.
+type StreamCompressionRequest struct {
+	Supported            []Compression `protobuf:"varint,1,rep,packed,name=supported,proto3,enum=protobuf.Compression" json:"supported,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *StreamCompressionRequest) Reset()         { *m = StreamCompressionRequest{} }
+func (m *StreamCompressionRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamCompressionRequest) ProtoMessage()    {}
+func (*StreamCompressionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_envelope_5538b08c1c5f1ceb, []int{3}
+}
+func (m *StreamCompressionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamCompressionRequest.Unmarshal(m, b)
+}
+func (m *StreamCompressionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamCompressionRequest.Marshal(b, m, deterministic)
+}
+func (dst *StreamCompressionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamCompressionRequest.Merge(dst, src)
+}
+func (m *StreamCompressionRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamCompressionRequest.Size(m)
+}
+func (m *StreamCompressionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamCompressionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamCompressionRequest proto.InternalMessageInfo
+
+func (m *StreamCompressionRequest) GetSupported() []Compression {
+	if m != nil {
+		return m.Supported
+	}
+	return nil
+}
+
+type StreamCompressionResponse struct {
+	Selected             Compression `protobuf:"varint,1,opt,name=selected,proto3,enum=protobuf.Compression" json:"selected,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *StreamCompressionResponse) Reset()         { *m = StreamCompressionResponse{} }
+func (m *StreamCompressionResponse) String() string { return proto.CompactTextString(m) }
+func (*StreamCompressionResponse) ProtoMessage()    {}
+func (*StreamCompressionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_envelope_5538b08c1c5f1ceb, []int{4}
+}
+func (m *StreamCompressionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamCompressionResponse.Unmarshal(m, b)
+}
+func (m *StreamCompressionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamCompressionResponse.Marshal(b, m, deterministic)
+}
+func (dst *StreamCompressionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamCompressionResponse.Merge(dst, src)
+}
+func (m *StreamCompressionResponse) XXX_Size() int {
+	return xxx_messageInfo_StreamCompressionResponse.Size(m)
+}
+func (m *StreamCompressionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamCompressionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamCompressionResponse proto.InternalMessageInfo
+
+func (m *StreamCompressionResponse) GetSelected() Compression {
+	if m != nil {
+		return m.Selected
+	}
+	return Compression_COMPRESSION_NONE
+}
+
+// Datagram is opaque application data delivered to plugins as a whole, unlike Bytes
+// which are buffered into a peer's stream.
+type Datagram struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Datagram) Reset()         { *m = Datagram{} }
+func (m *Datagram) String() string { return proto.CompactTextString(m) }
+func (*Datagram) ProtoMessage()    {}
+func (*Datagram) Descriptor() ([]byte, []int) {
+	return fileDescriptor_envelope_5538b08c1c5f1ceb, []int{5}
+}
+func (m *Datagram) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Datagram.Unmarshal(m, b)
+}
+func (m *Datagram) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Datagram.Marshal(b, m, deterministic)
+}
+func (dst *Datagram) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Datagram.Merge(dst, src)
+}
+func (m *Datagram) XXX_Size() int {
+	return xxx_messageInfo_Datagram.Size(m)
+}
+func (m *Datagram) XXX_DiscardUnknown() {
+	xxx_messageInfo_Datagram.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Datagram proto.InternalMessageInfo
+
+func (m *Datagram) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// VectorClock is the wire encoding of a vector clock keyed by peer public key (hex-encoded).
+type VectorClock struct {
+	Clock                map[string]uint64 `protobuf:"bytes,1,rep,name=clock,proto3" json:"clock,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *VectorClock) Reset()         { *m = VectorClock{} }
+func (m *VectorClock) String() string { return proto.CompactTextString(m) }
+func (*VectorClock) ProtoMessage()    {}
+func (*VectorClock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_envelope_5538b08c1c5f1ceb, []int{6}
+}
+func (m *VectorClock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VectorClock.Unmarshal(m, b)
+}
+func (m *VectorClock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VectorClock.Marshal(b, m, deterministic)
+}
+func (dst *VectorClock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VectorClock.Merge(dst, src)
+}
+func (m *VectorClock) XXX_Size() int {
+	return xxx_messageInfo_VectorClock.Size(m)
+}
+func (m *VectorClock) XXX_DiscardUnknown() {
+	xxx_messageInfo_VectorClock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VectorClock proto.InternalMessageInfo
+
+func (m *VectorClock) GetClock() map[string]uint64 {
+	if m != nil {
+		return m.Clock
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ID)(nil), "protobuf.ID")
+	proto.RegisterType((*Message)(nil), "protobuf.Message")
+	proto.RegisterType((*Bytes)(nil), "protobuf.Bytes")
+	proto.RegisterType((*StreamCompressionRequest)(nil), "protobuf.StreamCompressionRequest")
+	proto.RegisterType((*StreamCompressionResponse)(nil), "protobuf.StreamCompressionResponse")
+	proto.RegisterType((*Datagram)(nil), "protobuf.Datagram")
+	proto.RegisterType((*VectorClock)(nil), "protobuf.VectorClock")
+	proto.RegisterMapType((map[string]uint64)(nil), "protobuf.VectorClock.ClockEntry")
+	proto.RegisterEnum("protobuf.Compression", Compression_name, Compression_value)
+}
+
+func init() { proto.RegisterFile("protobuf/envelope.proto", fileDescriptor_envelope_5538b08c1c5f1ceb) }
+
+var fileDescriptor_envelope_5538b08c1c5f1ceb = []byte{
+	// 620 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x94, 0x4f, 0x6f, 0xd3, 0x4a,
+	0x10, 0xc0, 0x9f, 0xf3, 0x3f, 0xe3, 0xa4, 0xca, 0xdb, 0xd7, 0xd7, 0x6e, 0xab, 0xf7, 0xc0, 0x32,
+	0x1c, 0x02, 0x48, 0xae, 0x48, 0x25, 0xa8, 0x10, 0x1c, 0xda, 0x26, 0x48, 0x11, 0x34, 0x89, 0xdc,
+	0xaa, 0xd7, 0x68, 0x63, 0x4f, 0xc3, 0xaa, 0xb6, 0xd7, 0xec, 0x6e, 0xaa, 0xfa, 0xc4, 0xe7, 0xe4,
+	0xdb, 0x20, 0xff, 0x8b, 0x73, 0x28, 0x5c, 0xa2, 0x9d, 0xdf, 0xfc, 0x34, 0x33, 0x9e, 0xb5, 0x03,
+	0x87, 0xb1, 0x14, 0x5a, 0xac, 0x36, 0x77, 0x27, 0x18, 0x3d, 0x60, 0x20, 0x62, 0x74, 0x32, 0x42,
+	0x3a, 0x65, 0xe2, 0xf8, 0x68, 0x2d, 0xc4, 0x3a, 0xc0, 0x93, 0xad, 0xc9, 0xa2, 0x24, 0x97, 0xec,
+	0x4f, 0x50, 0x9b, 0x8e, 0xc9, 0xff, 0x00, 0xf1, 0x66, 0x15, 0x70, 0x6f, 0x79, 0x8f, 0x09, 0x35,
+	0x2c, 0x63, 0xd8, 0x73, 0xbb, 0x39, 0xf9, 0x82, 0x09, 0xa1, 0xd0, 0x66, 0xbe, 0x2f, 0x51, 0x29,
+	0x5a, 0xb3, 0x8c, 0x61, 0xd7, 0x2d, 0x43, 0xfb, 0x67, 0x1d, 0xda, 0x57, 0xa8, 0x14, 0x5b, 0x23,
+	0x71, 0xa0, 0x1d, 0xe6, 0xc7, 0xac, 0x82, 0x39, 0xda, 0x77, 0xf2, 0xbe, 0x4e, 0xd9, 0xd7, 0x39,
+	0x8f, 0x12, 0xb7, 0x94, 0xc8, 0x4b, 0x68, 0x29, 0x8c, 0x7c, 0x94, 0x59, 0x51, 0x73, 0xd4, 0xab,
+	0xbc, 0xe9, 0xd8, 0x2d, 0x72, 0xe4, 0x3f, 0xe8, 0x2a, 0xbe, 0x8e, 0x98, 0xde, 0x48, 0xa4, 0xf5,
+	0x7c, 0xb2, 0x2d, 0x20, 0x2f, 0xa0, 0x2f, 0xf1, 0xfb, 0x06, 0x95, 0x5e, 0x46, 0x22, 0xf2, 0x90,
+	0x36, 0x2c, 0x63, 0xd8, 0x70, 0x7b, 0x05, 0x9c, 0xa5, 0x2c, 0x95, 0x8a, 0x9e, 0x85, 0xd4, 0xcc,
+	0xa5, 0x02, 0xe6, 0xd2, 0x1b, 0xf8, 0x3b, 0x60, 0x61, 0x2c, 0xa4, 0x5e, 0x6a, 0x1e, 0xa2, 0xd2,
+	0x2c, 0x8c, 0x69, 0x2b, 0x13, 0x07, 0x45, 0xe2, 0xa6, 0xe4, 0xe9, 0x42, 0x1e, 0x50, 0x2a, 0x2e,
+	0x22, 0xda, 0xb6, 0x8c, 0x61, 0xdf, 0x2d, 0x43, 0xf2, 0x1c, 0xcc, 0x90, 0x3d, 0x2e, 0xcb, 0x6c,
+	0x27, 0xcb, 0x42, 0xc8, 0x1e, 0x6f, 0x0b, 0xe1, 0x18, 0x3a, 0xb1, 0xe4, 0x42, 0x72, 0x9d, 0xd0,
+	0xae, 0x65, 0x0c, 0x3b, 0xee, 0x36, 0x26, 0x36, 0xf4, 0x3c, 0x16, 0xb3, 0x15, 0x0f, 0xb8, 0xe6,
+	0xa8, 0x28, 0xe4, 0x73, 0xee, 0xb2, 0xb4, 0xc1, 0x8a, 0x69, 0xef, 0xdb, 0x92, 0x47, 0x3e, 0x3e,
+	0x52, 0x33, 0x6f, 0x90, 0xa1, 0x69, 0x4a, 0x2a, 0x21, 0x96, 0x42, 0xdc, 0xd1, 0x9e, 0x55, 0x1f,
+	0xf6, 0x0a, 0x61, 0x91, 0x12, 0xb2, 0x07, 0x35, 0xee, 0xd3, 0x7e, 0xb6, 0xca, 0x1a, 0xf7, 0xc9,
+	0x01, 0xb4, 0x34, 0xf7, 0xee, 0x51, 0xd3, 0xbd, 0x8c, 0x15, 0x91, 0x7d, 0x03, 0xcd, 0x8b, 0x44,
+	0xa3, 0x22, 0x04, 0x1a, 0x3e, 0xd3, 0xac, 0x78, 0x2f, 0xb2, 0x33, 0x79, 0x0f, 0xa6, 0x27, 0xc2,
+	0x38, 0x7d, 0x09, 0xd2, 0xe7, 0x4c, 0x6f, 0x70, 0x6f, 0xf4, 0x6f, 0x75, 0x83, 0x97, 0x55, 0xd2,
+	0xdd, 0x35, 0xed, 0x39, 0xd0, 0x6b, 0x2d, 0x91, 0x85, 0xbb, 0x46, 0x7e, 0x5b, 0xe4, 0x14, 0xba,
+	0x6a, 0x13, 0xa7, 0xab, 0x46, 0x9f, 0x1a, 0x56, 0xfd, 0xf7, 0x25, 0x2b, 0xcf, 0x9e, 0xc1, 0xd1,
+	0x13, 0x05, 0x55, 0x2c, 0x22, 0x85, 0xe4, 0x2d, 0x74, 0x14, 0x06, 0xe8, 0xe5, 0x05, 0xff, 0x30,
+	0xe3, 0x56, 0xb3, 0x9f, 0x41, 0x67, 0xcc, 0x34, 0x5b, 0x4b, 0x16, 0x3e, 0xf5, 0xe4, 0xf6, 0x0f,
+	0x30, 0x6f, 0xd1, 0xd3, 0x42, 0x5e, 0x06, 0xc2, 0xbb, 0x27, 0xef, 0xa0, 0xe9, 0xa5, 0x87, 0x6c,
+	0x5e, 0x73, 0x64, 0x55, 0xe5, 0x77, 0x2c, 0x27, 0xfb, 0x9d, 0x44, 0x5a, 0x26, 0x6e, 0xae, 0x1f,
+	0x9f, 0x01, 0x54, 0x90, 0x0c, 0xa0, 0x5e, 0x7e, 0x79, 0x5d, 0x37, 0x3d, 0x92, 0x7d, 0x68, 0x3e,
+	0xb0, 0x60, 0x83, 0xd9, 0x6a, 0x1b, 0x6e, 0x1e, 0x7c, 0xa8, 0x9d, 0x19, 0xaf, 0x3f, 0x82, 0xb9,
+	0x33, 0x39, 0xd9, 0x87, 0xc1, 0xe5, 0xfc, 0x6a, 0xe1, 0x4e, 0xae, 0xaf, 0xa7, 0xf3, 0xd9, 0x72,
+	0x36, 0x9f, 0x4d, 0x06, 0x7f, 0x91, 0x43, 0xf8, 0x67, 0x97, 0x8e, 0x27, 0x9f, 0xbf, 0x9e, 0xdf,
+	0x4c, 0x06, 0xc6, 0xc5, 0x2b, 0x38, 0x10, 0x72, 0xed, 0xc4, 0x28, 0x03, 0x1e, 0x39, 0x91, 0xe0,
+	0xaa, 0xf8, 0x3e, 0x2f, 0xfa, 0x93, 0xe2, 0xff, 0x63, 0x91, 0x86, 0x0b, 0x63, 0xd5, 0xca, 0xf8,
+	0xe9, 0xaf, 0x01, 0x00, 0xe1, 0x13, 0x89, 0x7b, 0x62, 0x04, 0x00, 0x00,
+}
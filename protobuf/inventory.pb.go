@@ -0,0 +1,160 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: protobuf/inventory.proto
+
+package protobuf
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// InventoryAnnouncement announces the hashes of items a peer has.
+type InventoryAnnouncement struct {
+	Hashes               [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InventoryAnnouncement) Reset()         { *m = InventoryAnnouncement{} }
+func (m *InventoryAnnouncement) String() string { return proto.CompactTextString(m) }
+func (*InventoryAnnouncement) ProtoMessage()    {}
+func (*InventoryAnnouncement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_inventory_d813a4b98f8c2fea, []int{0}
+}
+func (m *InventoryAnnouncement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InventoryAnnouncement.Unmarshal(m, b)
+}
+func (m *InventoryAnnouncement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InventoryAnnouncement.Marshal(b, m, deterministic)
+}
+func (dst *InventoryAnnouncement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InventoryAnnouncement.Merge(dst, src)
+}
+func (m *InventoryAnnouncement) XXX_Size() int {
+	return xxx_messageInfo_InventoryAnnouncement.Size(m)
+}
+func (m *InventoryAnnouncement) XXX_DiscardUnknown() {
+	xxx_messageInfo_InventoryAnnouncement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InventoryAnnouncement proto.InternalMessageInfo
+
+func (m *InventoryAnnouncement) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+// InventoryRequest requests for items by their hashes.
+type InventoryRequest struct {
+	Hashes               [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InventoryRequest) Reset()         { *m = InventoryRequest{} }
+func (m *InventoryRequest) String() string { return proto.CompactTextString(m) }
+func (*InventoryRequest) ProtoMessage()    {}
+func (*InventoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_inventory_d813a4b98f8c2fea, []int{1}
+}
+func (m *InventoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InventoryRequest.Unmarshal(m, b)
+}
+func (m *InventoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InventoryRequest.Marshal(b, m, deterministic)
+}
+func (dst *InventoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InventoryRequest.Merge(dst, src)
+}
+func (m *InventoryRequest) XXX_Size() int {
+	return xxx_messageInfo_InventoryRequest.Size(m)
+}
+func (m *InventoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InventoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InventoryRequest proto.InternalMessageInfo
+
+func (m *InventoryRequest) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+// InventoryItems are the items a peer has out of those requested.
+type InventoryItems struct {
+	Items                [][]byte `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InventoryItems) Reset()         { *m = InventoryItems{} }
+func (m *InventoryItems) String() string { return proto.CompactTextString(m) }
+func (*InventoryItems) ProtoMessage()    {}
+func (*InventoryItems) Descriptor() ([]byte, []int) {
+	return fileDescriptor_inventory_d813a4b98f8c2fea, []int{2}
+}
+func (m *InventoryItems) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InventoryItems.Unmarshal(m, b)
+}
+func (m *InventoryItems) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InventoryItems.Marshal(b, m, deterministic)
+}
+func (dst *InventoryItems) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InventoryItems.Merge(dst, src)
+}
+func (m *InventoryItems) XXX_Size() int {
+	return xxx_messageInfo_InventoryItems.Size(m)
+}
+func (m *InventoryItems) XXX_DiscardUnknown() {
+	xxx_messageInfo_InventoryItems.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InventoryItems proto.InternalMessageInfo
+
+func (m *InventoryItems) GetItems() [][]byte {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*InventoryAnnouncement)(nil), "protobuf.InventoryAnnouncement")
+	proto.RegisterType((*InventoryRequest)(nil), "protobuf.InventoryRequest")
+	proto.RegisterType((*InventoryItems)(nil), "protobuf.InventoryItems")
+}
+
+func init() {
+	proto.RegisterFile("protobuf/inventory.proto", fileDescriptor_inventory_d813a4b98f8c2fea)
+}
+
+var fileDescriptor_inventory_d813a4b98f8c2fea = []byte{
+	// 155 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x28, 0x28, 0xca, 0x2f,
+	0xc9, 0x4f, 0x2a, 0x4d, 0xd3, 0xcf, 0xcc, 0x2b, 0x4b, 0xcd, 0x2b, 0xc9, 0x2f, 0xaa, 0xd4, 0x03,
+	0x0b, 0x09, 0x71, 0xc0, 0x64, 0x94, 0xf4, 0xb9, 0x44, 0x3d, 0x61, 0x92, 0x8e, 0x79, 0x79, 0xf9,
+	0xa5, 0x79, 0xc9, 0xa9, 0xb9, 0xa9, 0x79, 0x25, 0x42, 0x62, 0x5c, 0x6c, 0x19, 0x89, 0xc5, 0x19,
+	0xa9, 0xc5, 0x12, 0x8c, 0x0a, 0xcc, 0x1a, 0x3c, 0x41, 0x50, 0x9e, 0x92, 0x16, 0x97, 0x00, 0x5c,
+	0x43, 0x50, 0x6a, 0x61, 0x69, 0x6a, 0x31, 0x6e, 0xb5, 0x6a, 0x5c, 0x7c, 0x70, 0xb5, 0x9e, 0x25,
+	0xa9, 0xb9, 0xc5, 0x42, 0x22, 0x5c, 0xac, 0x99, 0x20, 0x06, 0x54, 0x21, 0x84, 0xe3, 0xa4, 0xc5,
+	0x25, 0x96, 0x5f, 0x94, 0xae, 0x57, 0x90, 0x5a, 0x94, 0x93, 0x99, 0xa7, 0x97, 0x97, 0x9f, 0x59,
+	0x9c, 0x0a, 0x71, 0xa8, 0x13, 0x42, 0x7f, 0x00, 0x88, 0x1f, 0xc0, 0x98, 0xc4, 0x06, 0x96, 0x30,
+	0x06, 0x0c, 0x00, 0x84, 0xc9, 0x35, 0x85, 0xdd, 0x00, 0x00, 0x00,
+}
@@ -0,0 +1,695 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: protobuf/ping.proto
+
+package protobuf
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Ping struct {
+	// timestamp is the sender's wall clock in nanoseconds since the Unix epoch at the time of sending.
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// summary summarizes the sender's state. Null if the sender shares no summary.
+	Summary *Summary `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	// config_fingerprint is the fingerprint of the sender's configuration. Empty if unshared.
+	ConfigFingerprint []byte `protobuf:"bytes,3,opt,name=config_fingerprint,json=configFingerprint,proto3" json:"config_fingerprint,omitempty"`
+	// deprecations are the message types the sender deprecated. Empty if none.
+	Deprecations []*Deprecation `protobuf:"bytes,4,rep,name=deprecations,proto3" json:"deprecations,omitempty"`
+	// zone labels the datacenter or availability zone the sender runs in. Empty if unlabelled.
+	Zone                 string   `protobuf:"bytes,5,opt,name=zone,proto3" json:"zone,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Ping) Reset()         { *m = Ping{} }
+func (m *Ping) String() string { return proto.CompactTextString(m) }
+func (*Ping) ProtoMessage()    {}
+func (*Ping) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{0}
+}
+func (m *Ping) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Ping.Unmarshal(m, b)
+}
+func (m *Ping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Ping.Marshal(b, m, deterministic)
+}
+func (dst *Ping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ping.Merge(dst, src)
+}
+func (m *Ping) XXX_Size() int {
+	return xxx_messageInfo_Ping.Size(m)
+}
+func (m *Ping) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ping.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Ping proto.InternalMessageInfo
+
+func (m *Ping) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Ping) GetSummary() *Summary {
+	if m != nil {
+		return m.Summary
+	}
+	return nil
+}
+
+func (m *Ping) GetConfigFingerprint() []byte {
+	if m != nil {
+		return m.ConfigFingerprint
+	}
+	return nil
+}
+
+func (m *Ping) GetDeprecations() []*Deprecation {
+	if m != nil {
+		return m.Deprecations
+	}
+	return nil
+}
+
+func (m *Ping) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
+type Pong struct {
+	// ping_timestamp echoes the timestamp of the ping being responded to.
+	PingTimestamp int64 `protobuf:"varint,1,opt,name=ping_timestamp,json=pingTimestamp,proto3" json:"ping_timestamp,omitempty"`
+	// timestamp is the responder's wall clock in nanoseconds since the Unix epoch at the time of responding.
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// summary summarizes the responder's state. Null if the responder shares no summary.
+	Summary *Summary `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	// config_fingerprint is the fingerprint of the responder's configuration. Empty if unshared.
+	ConfigFingerprint []byte `protobuf:"bytes,4,opt,name=config_fingerprint,json=configFingerprint,proto3" json:"config_fingerprint,omitempty"`
+	// deprecations are the message types the responder deprecated. Empty if none.
+	Deprecations []*Deprecation `protobuf:"bytes,5,rep,name=deprecations,proto3" json:"deprecations,omitempty"`
+	// zone labels the datacenter or availability zone the responder runs in. Empty if unlabelled.
+	Zone                 string   `protobuf:"bytes,6,opt,name=zone,proto3" json:"zone,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Pong) Reset()         { *m = Pong{} }
+func (m *Pong) String() string { return proto.CompactTextString(m) }
+func (*Pong) ProtoMessage()    {}
+func (*Pong) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{1}
+}
+func (m *Pong) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Pong.Unmarshal(m, b)
+}
+func (m *Pong) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Pong.Marshal(b, m, deterministic)
+}
+func (dst *Pong) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Pong.Merge(dst, src)
+}
+func (m *Pong) XXX_Size() int {
+	return xxx_messageInfo_Pong.Size(m)
+}
+func (m *Pong) XXX_DiscardUnknown() {
+	xxx_messageInfo_Pong.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Pong proto.InternalMessageInfo
+
+func (m *Pong) GetPingTimestamp() int64 {
+	if m != nil {
+		return m.PingTimestamp
+	}
+	return 0
+}
+
+func (m *Pong) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Pong) GetSummary() *Summary {
+	if m != nil {
+		return m.Summary
+	}
+	return nil
+}
+
+func (m *Pong) GetConfigFingerprint() []byte {
+	if m != nil {
+		return m.ConfigFingerprint
+	}
+	return nil
+}
+
+func (m *Pong) GetDeprecations() []*Deprecation {
+	if m != nil {
+		return m.Deprecations
+	}
+	return nil
+}
+
+func (m *Pong) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
+// Deprecation advertises that a message type is deprecated, such that operators may
+// plan to stop sending it before it is removed.
+type Deprecation struct {
+	// type is the fully-qualified protobuf name of the message type.
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// after is the version of the application after which the type is deprecated.
+	After string `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	// reason explains the deprecation, such as naming the type replacing it.
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Deprecation) Reset()         { *m = Deprecation{} }
+func (m *Deprecation) String() string { return proto.CompactTextString(m) }
+func (*Deprecation) ProtoMessage()    {}
+func (*Deprecation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{2}
+}
+func (m *Deprecation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Deprecation.Unmarshal(m, b)
+}
+func (m *Deprecation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Deprecation.Marshal(b, m, deterministic)
+}
+func (dst *Deprecation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Deprecation.Merge(dst, src)
+}
+func (m *Deprecation) XXX_Size() int {
+	return xxx_messageInfo_Deprecation.Size(m)
+}
+func (m *Deprecation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Deprecation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Deprecation proto.InternalMessageInfo
+
+func (m *Deprecation) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Deprecation) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *Deprecation) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// Summary is a lightweight summary of the state of a node, piggybacked onto its pings
+// and pongs.
+type Summary struct {
+	// peers is the number of peers the node is connected to.
+	Peers uint32 `protobuf:"varint,1,opt,name=peers,proto3" json:"peers,omitempty"`
+	// height and head are the height and head of the node's application state, such as
+	// that of a chain. Zero and empty should the application not provide them.
+	Height               uint64   `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Head                 []byte   `protobuf:"bytes,3,opt,name=head,proto3" json:"head,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Summary) Reset()         { *m = Summary{} }
+func (m *Summary) String() string { return proto.CompactTextString(m) }
+func (*Summary) ProtoMessage()    {}
+func (*Summary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{3}
+}
+func (m *Summary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Summary.Unmarshal(m, b)
+}
+func (m *Summary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Summary.Marshal(b, m, deterministic)
+}
+func (dst *Summary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Summary.Merge(dst, src)
+}
+func (m *Summary) XXX_Size() int {
+	return xxx_messageInfo_Summary.Size(m)
+}
+func (m *Summary) XXX_DiscardUnknown() {
+	xxx_messageInfo_Summary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Summary proto.InternalMessageInfo
+
+func (m *Summary) GetPeers() uint32 {
+	if m != nil {
+		return m.Peers
+	}
+	return 0
+}
+
+func (m *Summary) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Summary) GetHead() []byte {
+	if m != nil {
+		return m.Head
+	}
+	return nil
+}
+
+// IdentityChallenge is sent to a peer dialed back to, which must answer with an
+// IdentityResponse signed by the private key of the peer it claims to be.
+type IdentityChallenge struct {
+	Nonce []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// network_id is the ID of the network the challenger belongs to, which peers of
+	// other networks refuse to answer. Empty if unnamespaced.
+	NetworkId            string   `protobuf:"bytes,2,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IdentityChallenge) Reset()         { *m = IdentityChallenge{} }
+func (m *IdentityChallenge) String() string { return proto.CompactTextString(m) }
+func (*IdentityChallenge) ProtoMessage()    {}
+func (*IdentityChallenge) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{4}
+}
+func (m *IdentityChallenge) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IdentityChallenge.Unmarshal(m, b)
+}
+func (m *IdentityChallenge) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IdentityChallenge.Marshal(b, m, deterministic)
+}
+func (dst *IdentityChallenge) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IdentityChallenge.Merge(dst, src)
+}
+func (m *IdentityChallenge) XXX_Size() int {
+	return xxx_messageInfo_IdentityChallenge.Size(m)
+}
+func (m *IdentityChallenge) XXX_DiscardUnknown() {
+	xxx_messageInfo_IdentityChallenge.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IdentityChallenge proto.InternalMessageInfo
+
+func (m *IdentityChallenge) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *IdentityChallenge) GetNetworkId() string {
+	if m != nil {
+		return m.NetworkId
+	}
+	return ""
+}
+
+type IdentityResponse struct {
+	// nonce echoes the nonce of the challenge being answered.
+	Nonce []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// network_id is the ID of the network the answerer belongs to. Empty if
+	// unnamespaced.
+	NetworkId            string   `protobuf:"bytes,2,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IdentityResponse) Reset()         { *m = IdentityResponse{} }
+func (m *IdentityResponse) String() string { return proto.CompactTextString(m) }
+func (*IdentityResponse) ProtoMessage()    {}
+func (*IdentityResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{5}
+}
+func (m *IdentityResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IdentityResponse.Unmarshal(m, b)
+}
+func (m *IdentityResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IdentityResponse.Marshal(b, m, deterministic)
+}
+func (dst *IdentityResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IdentityResponse.Merge(dst, src)
+}
+func (m *IdentityResponse) XXX_Size() int {
+	return xxx_messageInfo_IdentityResponse.Size(m)
+}
+func (m *IdentityResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IdentityResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IdentityResponse proto.InternalMessageInfo
+
+func (m *IdentityResponse) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *IdentityResponse) GetNetworkId() string {
+	if m != nil {
+		return m.NetworkId
+	}
+	return ""
+}
+
+// SessionTicket is issued to a peer whose identity was verified, which it may present
+// upon reconnecting to resume its session without being challenged again.
+type SessionTicket struct {
+	Ticket []byte `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	// expires is when the ticket expires in nanoseconds since the Unix epoch.
+	Expires              int64    `protobuf:"varint,2,opt,name=expires,proto3" json:"expires,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionTicket) Reset()         { *m = SessionTicket{} }
+func (m *SessionTicket) String() string { return proto.CompactTextString(m) }
+func (*SessionTicket) ProtoMessage()    {}
+func (*SessionTicket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{6}
+}
+func (m *SessionTicket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionTicket.Unmarshal(m, b)
+}
+func (m *SessionTicket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionTicket.Marshal(b, m, deterministic)
+}
+func (dst *SessionTicket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionTicket.Merge(dst, src)
+}
+func (m *SessionTicket) XXX_Size() int {
+	return xxx_messageInfo_SessionTicket.Size(m)
+}
+func (m *SessionTicket) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionTicket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionTicket proto.InternalMessageInfo
+
+func (m *SessionTicket) GetTicket() []byte {
+	if m != nil {
+		return m.Ticket
+	}
+	return nil
+}
+
+func (m *SessionTicket) GetExpires() int64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+// ResumeSession presents a SessionTicket to the peer which issued it, as the first
+// message sent upon reconnecting to it. Superseded by the ticket field of envelopes,
+// though still redeemed.
+type ResumeSession struct {
+	Ticket               []byte   `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResumeSession) Reset()         { *m = ResumeSession{} }
+func (m *ResumeSession) String() string { return proto.CompactTextString(m) }
+func (*ResumeSession) ProtoMessage()    {}
+func (*ResumeSession) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{7}
+}
+func (m *ResumeSession) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeSession.Unmarshal(m, b)
+}
+func (m *ResumeSession) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeSession.Marshal(b, m, deterministic)
+}
+func (dst *ResumeSession) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeSession.Merge(dst, src)
+}
+func (m *ResumeSession) XXX_Size() int {
+	return xxx_messageInfo_ResumeSession.Size(m)
+}
+func (m *ResumeSession) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeSession.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResumeSession proto.InternalMessageInfo
+
+func (m *ResumeSession) GetTicket() []byte {
+	if m != nil {
+		return m.Ticket
+	}
+	return nil
+}
+
+// Echo is answered by the network itself with an EchoReply, to measure the latency
+// to a peer.
+type Echo struct {
+	// timestamp is the sender's clock in nanoseconds since the Unix epoch at the time of sending.
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// padding is echoed back by the responder, such as to probe the bandwidth of the link.
+	Padding              []byte   `protobuf:"bytes,2,opt,name=padding,proto3" json:"padding,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Echo) Reset()         { *m = Echo{} }
+func (m *Echo) String() string { return proto.CompactTextString(m) }
+func (*Echo) ProtoMessage()    {}
+func (*Echo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{8}
+}
+func (m *Echo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Echo.Unmarshal(m, b)
+}
+func (m *Echo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Echo.Marshal(b, m, deterministic)
+}
+func (dst *Echo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Echo.Merge(dst, src)
+}
+func (m *Echo) XXX_Size() int {
+	return xxx_messageInfo_Echo.Size(m)
+}
+func (m *Echo) XXX_DiscardUnknown() {
+	xxx_messageInfo_Echo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Echo proto.InternalMessageInfo
+
+func (m *Echo) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Echo) GetPadding() []byte {
+	if m != nil {
+		return m.Padding
+	}
+	return nil
+}
+
+type EchoReply struct {
+	// echo_timestamp echoes the timestamp of the echo being replied to.
+	EchoTimestamp int64 `protobuf:"varint,1,opt,name=echo_timestamp,json=echoTimestamp,proto3" json:"echo_timestamp,omitempty"`
+	// received_timestamp is the responder's clock at the time the echo was received.
+	ReceivedTimestamp int64 `protobuf:"varint,2,opt,name=received_timestamp,json=receivedTimestamp,proto3" json:"received_timestamp,omitempty"`
+	// timestamp is the responder's clock at the time of replying.
+	Timestamp int64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// padding echoes the padding of the echo being replied to.
+	Padding              []byte   `protobuf:"bytes,4,opt,name=padding,proto3" json:"padding,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EchoReply) Reset()         { *m = EchoReply{} }
+func (m *EchoReply) String() string { return proto.CompactTextString(m) }
+func (*EchoReply) ProtoMessage()    {}
+func (*EchoReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{9}
+}
+func (m *EchoReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EchoReply.Unmarshal(m, b)
+}
+func (m *EchoReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EchoReply.Marshal(b, m, deterministic)
+}
+func (dst *EchoReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EchoReply.Merge(dst, src)
+}
+func (m *EchoReply) XXX_Size() int {
+	return xxx_messageInfo_EchoReply.Size(m)
+}
+func (m *EchoReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_EchoReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EchoReply proto.InternalMessageInfo
+
+func (m *EchoReply) GetEchoTimestamp() int64 {
+	if m != nil {
+		return m.EchoTimestamp
+	}
+	return 0
+}
+
+func (m *EchoReply) GetReceivedTimestamp() int64 {
+	if m != nil {
+		return m.ReceivedTimestamp
+	}
+	return 0
+}
+
+func (m *EchoReply) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *EchoReply) GetPadding() []byte {
+	if m != nil {
+		return m.Padding
+	}
+	return nil
+}
+
+// SlowDown is sent by an overloaded peer, asking the peer receiving it to pause
+// sending it messages for a delay.
+type SlowDown struct {
+	// delay is how long to pause for in nanoseconds.
+	Delay                int64    `protobuf:"varint,1,opt,name=delay,proto3" json:"delay,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SlowDown) Reset()         { *m = SlowDown{} }
+func (m *SlowDown) String() string { return proto.CompactTextString(m) }
+func (*SlowDown) ProtoMessage()    {}
+func (*SlowDown) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ping_ba27964101b6ef1e, []int{10}
+}
+func (m *SlowDown) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SlowDown.Unmarshal(m, b)
+}
+func (m *SlowDown) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SlowDown.Marshal(b, m, deterministic)
+}
+func (dst *SlowDown) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SlowDown.Merge(dst, src)
+}
+func (m *SlowDown) XXX_Size() int {
+	return xxx_messageInfo_SlowDown.Size(m)
+}
+func (m *SlowDown) XXX_DiscardUnknown() {
+	xxx_messageInfo_SlowDown.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SlowDown proto.InternalMessageInfo
+
+func (m *SlowDown) GetDelay() int64 {
+	if m != nil {
+		return m.Delay
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Ping)(nil), "protobuf.Ping")
+	proto.RegisterType((*Pong)(nil), "protobuf.Pong")
+	proto.RegisterType((*Deprecation)(nil), "protobuf.Deprecation")
+	proto.RegisterType((*Summary)(nil), "protobuf.Summary")
+	proto.RegisterType((*IdentityChallenge)(nil), "protobuf.IdentityChallenge")
+	proto.RegisterType((*IdentityResponse)(nil), "protobuf.IdentityResponse")
+	proto.RegisterType((*SessionTicket)(nil), "protobuf.SessionTicket")
+	proto.RegisterType((*ResumeSession)(nil), "protobuf.ResumeSession")
+	proto.RegisterType((*Echo)(nil), "protobuf.Echo")
+	proto.RegisterType((*EchoReply)(nil), "protobuf.EchoReply")
+	proto.RegisterType((*SlowDown)(nil), "protobuf.SlowDown")
+}
+
+func init() { proto.RegisterFile("protobuf/ping.proto", fileDescriptor_ping_ba27964101b6ef1e) }
+
+var fileDescriptor_ping_ba27964101b6ef1e = []byte{
+	// 527 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0x5f, 0x6f, 0xd3, 0x3c,
+	0x14, 0xc6, 0x95, 0x35, 0x6b, 0x97, 0xd3, 0xf6, 0xd5, 0x5b, 0x03, 0x53, 0x2e, 0x40, 0x8a, 0x22,
+	0x4d, 0x54, 0x42, 0x2b, 0xd2, 0xb8, 0xe2, 0x06, 0x89, 0x31, 0xfe, 0x4c, 0x5c, 0x50, 0xb9, 0xbb,
+	0xaf, 0xb2, 0xe4, 0x34, 0xb1, 0x96, 0xda, 0x96, 0xed, 0x52, 0xca, 0x37, 0xe1, 0x53, 0xf1, 0x6d,
+	0xb8, 0x46, 0x76, 0x1c, 0xba, 0x0e, 0x6d, 0xc0, 0xee, 0xce, 0x73, 0x8e, 0xfd, 0x9c, 0xf3, 0x53,
+	0x8e, 0x03, 0x0f, 0xa4, 0x12, 0x46, 0x5c, 0xae, 0x16, 0xcf, 0x25, 0xe3, 0xe5, 0xc4, 0x29, 0x72,
+	0xd0, 0x26, 0xd3, 0xef, 0x01, 0x84, 0x53, 0xc6, 0x4b, 0xf2, 0x18, 0x22, 0xc3, 0x96, 0xa8, 0x4d,
+	0xb6, 0x94, 0x71, 0x90, 0x04, 0xe3, 0x0e, 0xdd, 0x26, 0xc8, 0x33, 0xe8, 0xe9, 0xd5, 0x72, 0x99,
+	0xa9, 0x4d, 0xbc, 0x97, 0x04, 0xe3, 0xfe, 0xc9, 0x68, 0xd2, 0x5a, 0x4c, 0x66, 0x4d, 0x81, 0xb6,
+	0x27, 0xc8, 0x31, 0x90, 0x5c, 0xf0, 0x05, 0x2b, 0xe7, 0x0b, 0xc6, 0x4b, 0x54, 0x52, 0x31, 0x6e,
+	0xe2, 0x4e, 0x12, 0x8c, 0x07, 0x74, 0xd4, 0x54, 0xde, 0x6d, 0x0b, 0xe4, 0x25, 0x0c, 0x0a, 0x94,
+	0x0a, 0xf3, 0xcc, 0x30, 0xc1, 0x75, 0x1c, 0x26, 0x9d, 0x71, 0xff, 0xe4, 0xd1, 0xb6, 0xc1, 0xd9,
+	0xb6, 0x4a, 0x77, 0x8e, 0x12, 0x02, 0xe1, 0x57, 0xc1, 0x31, 0xde, 0x4f, 0x82, 0x71, 0x44, 0x5d,
+	0x9c, 0xfe, 0xb0, 0x44, 0x82, 0x97, 0xe4, 0x08, 0xfe, 0xb3, 0xc8, 0xf3, 0x9b, 0x58, 0x43, 0x9b,
+	0xbd, 0xf8, 0x85, 0xb6, 0x03, 0xbe, 0x77, 0x07, 0x78, 0xe7, 0x9e, 0xe0, 0xe1, 0xdf, 0x82, 0xef,
+	0xff, 0x3b, 0x78, 0xf7, 0x1a, 0xf8, 0x27, 0xe8, 0x5f, 0xbb, 0x60, 0x8f, 0x98, 0x8d, 0x44, 0x07,
+	0x1d, 0x51, 0x17, 0x93, 0x87, 0xb0, 0x9f, 0x2d, 0x0c, 0x2a, 0xc7, 0x19, 0xd1, 0x46, 0x90, 0x43,
+	0xe8, 0x2a, 0xcc, 0xb4, 0xe0, 0x0e, 0x31, 0xa2, 0x5e, 0xa5, 0x1f, 0xa1, 0xe7, 0x11, 0xed, 0x45,
+	0x89, 0xa8, 0xb4, 0x73, 0x1b, 0xd2, 0x46, 0xd8, 0x8b, 0x15, 0xb2, 0xb2, 0x32, 0xce, 0x2f, 0xa4,
+	0x5e, 0xd9, 0xd6, 0x15, 0x66, 0x85, 0xff, 0xe4, 0x2e, 0x4e, 0x3f, 0xc0, 0xe8, 0xbc, 0x40, 0x6e,
+	0x98, 0xd9, 0xbc, 0xa9, 0xb2, 0xba, 0x46, 0x5e, 0xba, 0x79, 0xb8, 0xe0, 0x79, 0x33, 0xe4, 0x80,
+	0x36, 0x82, 0x3c, 0x01, 0xe0, 0x68, 0xd6, 0x42, 0x5d, 0xcd, 0x59, 0xe1, 0x47, 0x8d, 0x7c, 0xe6,
+	0xbc, 0x48, 0xdf, 0xc3, 0xff, 0xad, 0x13, 0x45, 0x2d, 0x05, 0xd7, 0xf7, 0x34, 0x7a, 0x0d, 0xc3,
+	0x19, 0x6a, 0xcd, 0x04, 0xbf, 0x60, 0xf9, 0x15, 0x1a, 0xcb, 0x63, 0x5c, 0xe4, 0x6d, 0xbc, 0x22,
+	0x31, 0xf4, 0xf0, 0x8b, 0x64, 0x0a, 0xb5, 0x5f, 0x90, 0x56, 0xa6, 0x4f, 0x61, 0x48, 0x51, 0xaf,
+	0x96, 0xe8, 0x8d, 0x6e, 0xb3, 0x48, 0x5f, 0x41, 0xf8, 0x36, 0xaf, 0xc4, 0x1f, 0x9e, 0x59, 0x0c,
+	0x3d, 0x99, 0x15, 0x05, 0xe3, 0xa5, 0x6b, 0x34, 0xa0, 0xad, 0x4c, 0xbf, 0x05, 0x10, 0x59, 0x03,
+	0x8a, 0xb2, 0xde, 0xd8, 0xd5, 0xc6, 0xbc, 0x12, 0xbf, 0xaf, 0xb6, 0xcd, 0x6e, 0x57, 0xfb, 0x18,
+	0x88, 0xc2, 0x1c, 0xd9, 0x67, 0x2c, 0xe6, 0x37, 0x77, 0x7c, 0xd4, 0x56, 0x6e, 0x79, 0x09, 0x9d,
+	0x3b, 0x66, 0x0b, 0x77, 0x67, 0x4b, 0xe0, 0x60, 0x56, 0x8b, 0xf5, 0x99, 0x58, 0x73, 0xfb, 0x21,
+	0x0a, 0xac, 0xb3, 0x8d, 0x1f, 0xa8, 0x11, 0xa7, 0x47, 0x70, 0x28, 0x54, 0x39, 0x91, 0xa8, 0x6a,
+	0xc6, 0x27, 0x5c, 0x30, 0x8d, 0xcd, 0x96, 0x9f, 0x46, 0xf6, 0xe7, 0x33, 0xb5, 0xe1, 0x34, 0xb8,
+	0xec, 0xba, 0xdc, 0x8b, 0x9f, 0x03, 0x00, 0x54, 0x6e, 0xad, 0x78, 0xb4, 0x04, 0x00, 0x00,
+}
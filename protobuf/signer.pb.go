@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: protobuf/signer.proto
+
+package protobuf
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// SignerHello is sent by a remote signer upon connecting, announcing the public key
+// it signs on behalf of.
+type SignerHello struct {
+	PublicKey            []byte   `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignerHello) Reset()         { *m = SignerHello{} }
+func (m *SignerHello) String() string { return proto.CompactTextString(m) }
+func (*SignerHello) ProtoMessage()    {}
+func (*SignerHello) Descriptor() ([]byte, []int) {
+	return fileDescriptor_signer_1b75e1c2cfe116c6, []int{0}
+}
+func (m *SignerHello) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignerHello.Unmarshal(m, b)
+}
+func (m *SignerHello) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignerHello.Marshal(b, m, deterministic)
+}
+func (dst *SignerHello) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignerHello.Merge(dst, src)
+}
+func (m *SignerHello) XXX_Size() int {
+	return xxx_messageInfo_SignerHello.Size(m)
+}
+func (m *SignerHello) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignerHello.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignerHello proto.InternalMessageInfo
+
+func (m *SignerHello) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+// SignRequest requests for a remote signer to sign a message.
+type SignRequest struct {
+	Nonce                uint64   `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Message              []byte   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignRequest) Reset()         { *m = SignRequest{} }
+func (m *SignRequest) String() string { return proto.CompactTextString(m) }
+func (*SignRequest) ProtoMessage()    {}
+func (*SignRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_signer_1b75e1c2cfe116c6, []int{1}
+}
+func (m *SignRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignRequest.Unmarshal(m, b)
+}
+func (m *SignRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignRequest.Marshal(b, m, deterministic)
+}
+func (dst *SignRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignRequest.Merge(dst, src)
+}
+func (m *SignRequest) XXX_Size() int {
+	return xxx_messageInfo_SignRequest.Size(m)
+}
+func (m *SignRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignRequest proto.InternalMessageInfo
+
+func (m *SignRequest) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *SignRequest) GetMessage() []byte {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+// SignResponse is the signature of a message, or the reason a remote signer refused
+// to sign it.
+type SignResponse struct {
+	Nonce                uint64   `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignResponse) Reset()         { *m = SignResponse{} }
+func (m *SignResponse) String() string { return proto.CompactTextString(m) }
+func (*SignResponse) ProtoMessage()    {}
+func (*SignResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_signer_1b75e1c2cfe116c6, []int{2}
+}
+func (m *SignResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignResponse.Unmarshal(m, b)
+}
+func (m *SignResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignResponse.Marshal(b, m, deterministic)
+}
+func (dst *SignResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignResponse.Merge(dst, src)
+}
+func (m *SignResponse) XXX_Size() int {
+	return xxx_messageInfo_SignResponse.Size(m)
+}
+func (m *SignResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignResponse proto.InternalMessageInfo
+
+func (m *SignResponse) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *SignResponse) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SignResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*SignerHello)(nil), "protobuf.SignerHello")
+	proto.RegisterType((*SignRequest)(nil), "protobuf.SignRequest")
+	proto.RegisterType((*SignResponse)(nil), "protobuf.SignResponse")
+}
+
+func init() { proto.RegisterFile("protobuf/signer.proto", fileDescriptor_signer_1b75e1c2cfe116c6) }
+
+var fileDescriptor_signer_1b75e1c2cfe116c6 = []byte{
+	// 201 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x8f, 0xbf, 0x4b, 0xc7, 0x30,
+	0x10, 0xc5, 0x89, 0xbf, 0x7b, 0x76, 0x0a, 0x2a, 0x19, 0x14, 0x4a, 0x17, 0x3b, 0x48, 0x1d, 0x9c,
+	0x5d, 0x3a, 0x09, 0x2e, 0x25, 0x2e, 0x6e, 0xd2, 0x96, 0xb3, 0x04, 0x63, 0x2e, 0x5e, 0x9a, 0xa1,
+	0xff, 0xbd, 0x34, 0xb1, 0x38, 0x7d, 0xc7, 0xcf, 0x27, 0xf7, 0x1e, 0x79, 0x70, 0xed, 0x99, 0x16,
+	0x1a, 0xe3, 0xe7, 0x63, 0x30, 0xb3, 0x43, 0x6e, 0x13, 0xcb, 0x8b, 0x5d, 0xd7, 0x0f, 0x70, 0xf9,
+	0x96, 0x5e, 0x5e, 0xd0, 0x5a, 0x92, 0x77, 0x00, 0x3e, 0x8e, 0xd6, 0x4c, 0x1f, 0x5f, 0xb8, 0x2a,
+	0x51, 0x89, 0xa6, 0xd4, 0x45, 0x36, 0xaf, 0xb8, 0xd6, 0xcf, 0xf9, 0x5a, 0xe3, 0x4f, 0xc4, 0xb0,
+	0xc8, 0x2b, 0x38, 0x75, 0xe4, 0x26, 0x4c, 0x87, 0x27, 0x3a, 0x83, 0x54, 0x70, 0xfe, 0x8d, 0x21,
+	0x0c, 0x33, 0xaa, 0xa3, 0x54, 0xb0, 0x63, 0xfd, 0x0e, 0x65, 0x8e, 0x07, 0x4f, 0x2e, 0xe0, 0x81,
+	0xfc, 0x2d, 0x14, 0xdb, 0x67, 0x87, 0x25, 0xf2, 0xde, 0xf0, 0x2f, 0xb6, 0x0c, 0x32, 0x13, 0xab,
+	0xe3, 0x4a, 0x34, 0x85, 0xce, 0xd0, 0xdd, 0xc3, 0x0d, 0xf1, 0xdc, 0x7a, 0x64, 0x6b, 0x5c, 0xeb,
+	0xc8, 0x04, 0xcc, 0x53, 0xbb, 0xbf, 0x79, 0xfd, 0x06, 0xbd, 0x18, 0xcf, 0x92, 0x7d, 0xfa, 0x1d,
+	0x00, 0x30, 0xe0, 0x1d, 0xed, 0x19, 0x01, 0x00, 0x00,
+}
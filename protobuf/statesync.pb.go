@@ -0,0 +1,198 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: protobuf/statesync.proto
+
+package protobuf
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// SnapshotsRequest requests for the snapshots a peer serves.
+type SnapshotsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotsRequest) Reset()         { *m = SnapshotsRequest{} }
+func (m *SnapshotsRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotsRequest) ProtoMessage()    {}
+func (*SnapshotsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_statesync_c9335ed3e24a41f8, []int{0}
+}
+func (m *SnapshotsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotsRequest.Unmarshal(m, b)
+}
+func (m *SnapshotsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotsRequest.Marshal(b, m, deterministic)
+}
+func (dst *SnapshotsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotsRequest.Merge(dst, src)
+}
+func (m *SnapshotsRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotsRequest.Size(m)
+}
+func (m *SnapshotsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotsRequest proto.InternalMessageInfo
+
+type SnapshotsResponse struct {
+	Snapshots            []*SnapshotInfo `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *SnapshotsResponse) Reset()         { *m = SnapshotsResponse{} }
+func (m *SnapshotsResponse) String() string { return proto.CompactTextString(m) }
+func (*SnapshotsResponse) ProtoMessage()    {}
+func (*SnapshotsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_statesync_c9335ed3e24a41f8, []int{1}
+}
+func (m *SnapshotsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotsResponse.Unmarshal(m, b)
+}
+func (m *SnapshotsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotsResponse.Marshal(b, m, deterministic)
+}
+func (dst *SnapshotsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotsResponse.Merge(dst, src)
+}
+func (m *SnapshotsResponse) XXX_Size() int {
+	return xxx_messageInfo_SnapshotsResponse.Size(m)
+}
+func (m *SnapshotsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotsResponse proto.InternalMessageInfo
+
+func (m *SnapshotsResponse) GetSnapshots() []*SnapshotInfo {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+// SnapshotInfo advertises a snapshot of application state, downloadable through the
+// transfer protocol under name.
+type SnapshotInfo struct {
+	Height   uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Format   uint32 `protobuf:"varint,2,opt,name=format,proto3" json:"format,omitempty"`
+	Metadata []byte `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Name     string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	// root is the transfer root hash of the snapshot's content.
+	Root                 []byte   `protobuf:"bytes,5,opt,name=root,proto3" json:"root,omitempty"`
+	Size                 uint64   `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotInfo) Reset()         { *m = SnapshotInfo{} }
+func (m *SnapshotInfo) String() string { return proto.CompactTextString(m) }
+func (*SnapshotInfo) ProtoMessage()    {}
+func (*SnapshotInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_statesync_c9335ed3e24a41f8, []int{2}
+}
+func (m *SnapshotInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotInfo.Unmarshal(m, b)
+}
+func (m *SnapshotInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotInfo.Marshal(b, m, deterministic)
+}
+func (dst *SnapshotInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotInfo.Merge(dst, src)
+}
+func (m *SnapshotInfo) XXX_Size() int {
+	return xxx_messageInfo_SnapshotInfo.Size(m)
+}
+func (m *SnapshotInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotInfo proto.InternalMessageInfo
+
+func (m *SnapshotInfo) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *SnapshotInfo) GetFormat() uint32 {
+	if m != nil {
+		return m.Format
+	}
+	return 0
+}
+
+func (m *SnapshotInfo) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *SnapshotInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SnapshotInfo) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+func (m *SnapshotInfo) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*SnapshotsRequest)(nil), "protobuf.SnapshotsRequest")
+	proto.RegisterType((*SnapshotsResponse)(nil), "protobuf.SnapshotsResponse")
+	proto.RegisterType((*SnapshotInfo)(nil), "protobuf.SnapshotInfo")
+}
+
+func init() {
+	proto.RegisterFile("protobuf/statesync.proto", fileDescriptor_statesync_c9335ed3e24a41f8)
+}
+
+var fileDescriptor_statesync_c9335ed3e24a41f8 = []byte{
+	// 238 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xb1, 0x4e, 0xc3, 0x30,
+	0x14, 0x45, 0x65, 0x5a, 0xa2, 0xd6, 0x14, 0x04, 0x1e, 0x22, 0x8b, 0x29, 0xca, 0x64, 0x31, 0x18,
+	0x09, 0xf8, 0x82, 0x6e, 0xdd, 0x2a, 0xe7, 0x0b, 0xdc, 0xf2, 0xd2, 0x44, 0x22, 0x7e, 0xc1, 0xef,
+	0x75, 0x28, 0x1f, 0xc2, 0xf7, 0x22, 0x9b, 0x06, 0xba, 0xdd, 0x73, 0xee, 0x95, 0x2d, 0x5b, 0xea,
+	0x31, 0x22, 0xe3, 0xee, 0xd8, 0x3e, 0x13, 0x7b, 0x06, 0x3a, 0x85, 0xbd, 0xcd, 0x4a, 0x2d, 0xa6,
+	0xa6, 0x56, 0xf2, 0xbe, 0x09, 0x7e, 0xa4, 0x0e, 0x99, 0x1c, 0x7c, 0x1e, 0x81, 0xb8, 0xde, 0xc8,
+	0x87, 0x0b, 0x47, 0x23, 0x06, 0x02, 0xf5, 0x26, 0x97, 0x34, 0x49, 0x2d, 0xaa, 0x99, 0xb9, 0x79,
+	0x29, 0xed, 0x74, 0x8c, 0x9d, 0xf6, 0x9b, 0xd0, 0xa2, 0xfb, 0x1f, 0xd6, 0xdf, 0x42, 0xae, 0x2e,
+	0x3b, 0x55, 0xca, 0xa2, 0x83, 0xfe, 0xd0, 0xb1, 0x16, 0x95, 0x30, 0x73, 0x77, 0xa6, 0xe4, 0x5b,
+	0x8c, 0x83, 0x67, 0x7d, 0x55, 0x09, 0x73, 0xeb, 0xce, 0xa4, 0x1e, 0xe5, 0x62, 0x00, 0xf6, 0xef,
+	0x9e, 0xbd, 0x9e, 0x55, 0xc2, 0xac, 0xdc, 0x1f, 0x2b, 0x25, 0xe7, 0xc1, 0x0f, 0xa0, 0xe7, 0x95,
+	0x30, 0x4b, 0x97, 0x73, 0x72, 0x11, 0x91, 0xf5, 0x75, 0xde, 0xe6, 0x9c, 0x1c, 0xf5, 0x5f, 0xa0,
+	0x8b, 0x7c, 0x63, 0xce, 0xeb, 0x27, 0x59, 0x62, 0x3c, 0xd8, 0x11, 0xe2, 0x47, 0x1f, 0x6c, 0xc0,
+	0x9e, 0xe0, 0xf7, 0x35, 0xeb, 0xbb, 0x26, 0x7d, 0x56, 0x73, 0x0a, 0xfb, 0x6d, 0xe2, 0xad, 0xd8,
+	0x15, 0xb9, 0x78, 0xfd, 0x19, 0x00, 0xc7, 0x56, 0xe6, 0x1b, 0x50, 0x01, 0x00, 0x00,
+}
@@ -1,352 +0,0 @@
-// Code generated by protoc-gen-go. DO NOT EDIT.
-// source: protobuf/stream.proto
-
-package protobuf
-
-import proto "github.com/golang/protobuf/proto"
-import fmt "fmt"
-import math "math"
-import any "github.com/golang/protobuf/ptypes/any"
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
-
-type ID struct {
-	PublicKey            []byte   `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
-	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *ID) Reset()         { *m = ID{} }
-func (m *ID) String() string { return proto.CompactTextString(m) }
-func (*ID) ProtoMessage()    {}
-func (*ID) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_bc585796027993bb, []int{0}
-}
-func (m *ID) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ID.Unmarshal(m, b)
-}
-func (m *ID) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ID.Marshal(b, m, deterministic)
-}
-func (dst *ID) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ID.Merge(dst, src)
-}
-func (m *ID) XXX_Size() int {
-	return xxx_messageInfo_ID.Size(m)
-}
-func (m *ID) XXX_DiscardUnknown() {
-	xxx_messageInfo_ID.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_ID proto.InternalMessageInfo
-
-func (m *ID) GetPublicKey() []byte {
-	if m != nil {
-		return m.PublicKey
-	}
-	return nil
-}
-
-func (m *ID) GetAddress() string {
-	if m != nil {
-		return m.Address
-	}
-	return ""
-}
-
-type Message struct {
-	Message *any.Any `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	// Sender's address and public key.
-	Sender *ID `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
-	// Sender's signature of message.
-	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
-	// request_nonce is the request/response ID. Null if ID associated to a message is not a request/response.
-	RequestNonce uint64 `protobuf:"varint,4,opt,name=request_nonce,json=requestNonce,proto3" json:"request_nonce,omitempty"`
-	// message_nonce is the sequence ID.
-	MessageNonce         uint64   `protobuf:"varint,5,opt,name=message_nonce,json=messageNonce,proto3" json:"message_nonce,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *Message) Reset()         { *m = Message{} }
-func (m *Message) String() string { return proto.CompactTextString(m) }
-func (*Message) ProtoMessage()    {}
-func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_bc585796027993bb, []int{1}
-}
-func (m *Message) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Message.Unmarshal(m, b)
-}
-func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
-}
-func (dst *Message) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Message.Merge(dst, src)
-}
-func (m *Message) XXX_Size() int {
-	return xxx_messageInfo_Message.Size(m)
-}
-func (m *Message) XXX_DiscardUnknown() {
-	xxx_messageInfo_Message.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_Message proto.InternalMessageInfo
-
-func (m *Message) GetMessage() *any.Any {
-	if m != nil {
-		return m.Message
-	}
-	return nil
-}
-
-func (m *Message) GetSender() *ID {
-	if m != nil {
-		return m.Sender
-	}
-	return nil
-}
-
-func (m *Message) GetSignature() []byte {
-	if m != nil {
-		return m.Signature
-	}
-	return nil
-}
-
-func (m *Message) GetRequestNonce() uint64 {
-	if m != nil {
-		return m.RequestNonce
-	}
-	return 0
-}
-
-func (m *Message) GetMessageNonce() uint64 {
-	if m != nil {
-		return m.MessageNonce
-	}
-	return 0
-}
-
-type Ping struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *Ping) Reset()         { *m = Ping{} }
-func (m *Ping) String() string { return proto.CompactTextString(m) }
-func (*Ping) ProtoMessage()    {}
-func (*Ping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_bc585796027993bb, []int{2}
-}
-func (m *Ping) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Ping.Unmarshal(m, b)
-}
-func (m *Ping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Ping.Marshal(b, m, deterministic)
-}
-func (dst *Ping) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Ping.Merge(dst, src)
-}
-func (m *Ping) XXX_Size() int {
-	return xxx_messageInfo_Ping.Size(m)
-}
-func (m *Ping) XXX_DiscardUnknown() {
-	xxx_messageInfo_Ping.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_Ping proto.InternalMessageInfo
-
-type Pong struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *Pong) Reset()         { *m = Pong{} }
-func (m *Pong) String() string { return proto.CompactTextString(m) }
-func (*Pong) ProtoMessage()    {}
-func (*Pong) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_bc585796027993bb, []int{3}
-}
-func (m *Pong) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Pong.Unmarshal(m, b)
-}
-func (m *Pong) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Pong.Marshal(b, m, deterministic)
-}
-func (dst *Pong) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Pong.Merge(dst, src)
-}
-func (m *Pong) XXX_Size() int {
-	return xxx_messageInfo_Pong.Size(m)
-}
-func (m *Pong) XXX_DiscardUnknown() {
-	xxx_messageInfo_Pong.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_Pong proto.InternalMessageInfo
-
-type LookupNodeRequest struct {
-	Target               *ID      `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *LookupNodeRequest) Reset()         { *m = LookupNodeRequest{} }
-func (m *LookupNodeRequest) String() string { return proto.CompactTextString(m) }
-func (*LookupNodeRequest) ProtoMessage()    {}
-func (*LookupNodeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_bc585796027993bb, []int{4}
-}
-func (m *LookupNodeRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LookupNodeRequest.Unmarshal(m, b)
-}
-func (m *LookupNodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LookupNodeRequest.Marshal(b, m, deterministic)
-}
-func (dst *LookupNodeRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LookupNodeRequest.Merge(dst, src)
-}
-func (m *LookupNodeRequest) XXX_Size() int {
-	return xxx_messageInfo_LookupNodeRequest.Size(m)
-}
-func (m *LookupNodeRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_LookupNodeRequest.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_LookupNodeRequest proto.InternalMessageInfo
-
-func (m *LookupNodeRequest) GetTarget() *ID {
-	if m != nil {
-		return m.Target
-	}
-	return nil
-}
-
-type LookupNodeResponse struct {
-	Peers                []*ID    `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *LookupNodeResponse) Reset()         { *m = LookupNodeResponse{} }
-func (m *LookupNodeResponse) String() string { return proto.CompactTextString(m) }
-func (*LookupNodeResponse) ProtoMessage()    {}
-func (*LookupNodeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_bc585796027993bb, []int{5}
-}
-func (m *LookupNodeResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LookupNodeResponse.Unmarshal(m, b)
-}
-func (m *LookupNodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LookupNodeResponse.Marshal(b, m, deterministic)
-}
-func (dst *LookupNodeResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LookupNodeResponse.Merge(dst, src)
-}
-func (m *LookupNodeResponse) XXX_Size() int {
-	return xxx_messageInfo_LookupNodeResponse.Size(m)
-}
-func (m *LookupNodeResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_LookupNodeResponse.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_LookupNodeResponse proto.InternalMessageInfo
-
-func (m *LookupNodeResponse) GetPeers() []*ID {
-	if m != nil {
-		return m.Peers
-	}
-	return nil
-}
-
-type Bytes struct {
-	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *Bytes) Reset()         { *m = Bytes{} }
-func (m *Bytes) String() string { return proto.CompactTextString(m) }
-func (*Bytes) ProtoMessage()    {}
-func (*Bytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_bc585796027993bb, []int{6}
-}
-func (m *Bytes) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Bytes.Unmarshal(m, b)
-}
-func (m *Bytes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Bytes.Marshal(b, m, deterministic)
-}
-func (dst *Bytes) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Bytes.Merge(dst, src)
-}
-func (m *Bytes) XXX_Size() int {
-	return xxx_messageInfo_Bytes.Size(m)
-}
-func (m *Bytes) XXX_DiscardUnknown() {
-	xxx_messageInfo_Bytes.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_Bytes proto.InternalMessageInfo
-
-func (m *Bytes) GetData() []byte {
-	if m != nil {
-		return m.Data
-	}
-	return nil
-}
-
-func init() {
-	proto.RegisterType((*ID)(nil), "protobuf.ID")
-	proto.RegisterType((*Message)(nil), "protobuf.Message")
-	proto.RegisterType((*Ping)(nil), "protobuf.Ping")
-	proto.RegisterType((*Pong)(nil), "protobuf.Pong")
-	proto.RegisterType((*LookupNodeRequest)(nil), "protobuf.LookupNodeRequest")
-	proto.RegisterType((*LookupNodeResponse)(nil), "protobuf.LookupNodeResponse")
-	proto.RegisterType((*Bytes)(nil), "protobuf.Bytes")
-}
-
-func init() { proto.RegisterFile("protobuf/stream.proto", fileDescriptor_stream_bc585796027993bb) }
-
-var fileDescriptor_stream_bc585796027993bb = []byte{
-	// 337 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xcd, 0x4e, 0xc2, 0x40,
-	0x10, 0x80, 0x53, 0x28, 0x20, 0x03, 0x1e, 0xdc, 0xa8, 0xa9, 0x7f, 0x49, 0x53, 0x8d, 0xe1, 0xb4,
-	0x24, 0x78, 0xd1, 0x83, 0x07, 0x09, 0x17, 0xa2, 0x12, 0xd2, 0x17, 0x20, 0x0b, 0x1d, 0x37, 0x0d,
-	0xb0, 0x5b, 0x77, 0xb7, 0x87, 0x3e, 0xa1, 0xaf, 0x65, 0xba, 0xbb, 0x45, 0xa3, 0xa7, 0xce, 0x7c,
-	0xf3, 0xcd, 0x4f, 0x5b, 0x38, 0x2b, 0x94, 0x34, 0x72, 0x5d, 0x7e, 0x8c, 0xb5, 0x51, 0xc8, 0xf6,
-	0xd4, 0xe6, 0xe4, 0xa8, 0xc1, 0x97, 0x17, 0x5c, 0x4a, 0xbe, 0xc3, 0xf1, 0xc1, 0x63, 0xa2, 0x72,
-	0x52, 0xf2, 0x0c, 0xad, 0xf9, 0x8c, 0xdc, 0x00, 0x14, 0xe5, 0x7a, 0x97, 0x6f, 0x56, 0x5b, 0xac,
-	0xa2, 0x20, 0x0e, 0x46, 0xc3, 0xb4, 0xef, 0xc8, 0x2b, 0x56, 0x24, 0x82, 0x1e, 0xcb, 0x32, 0x85,
-	0x5a, 0x47, 0xad, 0x38, 0x18, 0xf5, 0xd3, 0x26, 0x4d, 0xbe, 0x02, 0xe8, 0xbd, 0xa3, 0xd6, 0x8c,
-	0x23, 0xa1, 0xd0, 0xdb, 0xbb, 0xd0, 0x4e, 0x18, 0x4c, 0x4e, 0xa9, 0xdb, 0x4b, 0x9b, 0xbd, 0xf4,
-	0x45, 0x54, 0x69, 0x23, 0x91, 0x3b, 0xe8, 0x6a, 0x14, 0x19, 0x2a, 0x3b, 0x74, 0x30, 0x19, 0xfe,
-	0x78, 0xf3, 0x59, 0xea, 0x6b, 0xe4, 0x1a, 0xfa, 0x3a, 0xe7, 0x82, 0x99, 0x52, 0x61, 0xd4, 0x76,
-	0x97, 0x1d, 0x00, 0xb9, 0x85, 0x63, 0x85, 0x9f, 0x25, 0x6a, 0xb3, 0x12, 0x52, 0x6c, 0x30, 0x0a,
-	0xe3, 0x60, 0x14, 0xa6, 0x43, 0x0f, 0x17, 0x35, 0xab, 0x25, 0xbf, 0xd3, 0x4b, 0x1d, 0x27, 0x79,
-	0x68, 0xa5, 0xa4, 0x0b, 0xe1, 0x32, 0x17, 0xdc, 0x3e, 0xa5, 0xe0, 0xc9, 0x13, 0x9c, 0xbc, 0x49,
-	0xb9, 0x2d, 0x8b, 0x85, 0xcc, 0x30, 0x75, 0xe3, 0xea, 0x93, 0x0d, 0x53, 0x1c, 0x8d, 0x7f, 0xc3,
-	0x3f, 0x27, 0xbb, 0x5a, 0xf2, 0x08, 0xe4, 0x77, 0xab, 0x2e, 0xa4, 0xd0, 0x48, 0x12, 0xe8, 0x14,
-	0x88, 0x4a, 0x47, 0x41, 0xdc, 0xfe, 0xd7, 0xea, 0x4a, 0xc9, 0x15, 0x74, 0xa6, 0x95, 0x41, 0x4d,
-	0x08, 0x84, 0x19, 0x33, 0xcc, 0xff, 0x0a, 0x1b, 0x4f, 0xef, 0xe1, 0x5c, 0x2a, 0x4e, 0x0b, 0x54,
-	0xbb, 0x5c, 0x50, 0x21, 0x73, 0xed, 0xbf, 0xec, 0x14, 0x16, 0x75, 0xb2, 0xac, 0xe3, 0x65, 0xb0,
-	0xee, 0x5a, 0xf8, 0xf0, 0x1d, 0x00, 0x00, 0xff, 0xff, 0x44, 0xb9, 0x79, 0x2a, 0x17, 0x02, 0x00,
-	0x00,
-}
@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: protobuf/transfer.proto
+
+package protobuf
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// TransferManifestRequest requests for the manifest of content by its name.
+type TransferManifestRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferManifestRequest) Reset()         { *m = TransferManifestRequest{} }
+func (m *TransferManifestRequest) String() string { return proto.CompactTextString(m) }
+func (*TransferManifestRequest) ProtoMessage()    {}
+func (*TransferManifestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_transfer_89806cc6579a136e, []int{0}
+}
+func (m *TransferManifestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferManifestRequest.Unmarshal(m, b)
+}
+func (m *TransferManifestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferManifestRequest.Marshal(b, m, deterministic)
+}
+func (dst *TransferManifestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferManifestRequest.Merge(dst, src)
+}
+func (m *TransferManifestRequest) XXX_Size() int {
+	return xxx_messageInfo_TransferManifestRequest.Size(m)
+}
+func (m *TransferManifestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferManifestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferManifestRequest proto.InternalMessageInfo
+
+func (m *TransferManifestRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// TransferManifest describes content split into fixed-size chunks by their SHA-256 hashes.
+type TransferManifest struct {
+	Name        string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size        uint64   `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	ChunkSize   uint32   `protobuf:"varint,3,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	ChunkHashes [][]byte `protobuf:"bytes,4,rep,name=chunk_hashes,json=chunkHashes,proto3" json:"chunk_hashes,omitempty"`
+	// found is false should the peer not serve the content.
+	Found                bool     `protobuf:"varint,5,opt,name=found,proto3" json:"found,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferManifest) Reset()         { *m = TransferManifest{} }
+func (m *TransferManifest) String() string { return proto.CompactTextString(m) }
+func (*TransferManifest) ProtoMessage()    {}
+func (*TransferManifest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_transfer_89806cc6579a136e, []int{1}
+}
+func (m *TransferManifest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferManifest.Unmarshal(m, b)
+}
+func (m *TransferManifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferManifest.Marshal(b, m, deterministic)
+}
+func (dst *TransferManifest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferManifest.Merge(dst, src)
+}
+func (m *TransferManifest) XXX_Size() int {
+	return xxx_messageInfo_TransferManifest.Size(m)
+}
+func (m *TransferManifest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferManifest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferManifest proto.InternalMessageInfo
+
+func (m *TransferManifest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TransferManifest) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *TransferManifest) GetChunkSize() uint32 {
+	if m != nil {
+		return m.ChunkSize
+	}
+	return 0
+}
+
+func (m *TransferManifest) GetChunkHashes() [][]byte {
+	if m != nil {
+		return m.ChunkHashes
+	}
+	return nil
+}
+
+func (m *TransferManifest) GetFound() bool {
+	if m != nil {
+		return m.Found
+	}
+	return false
+}
+
+// TransferChunkRequest requests for a single chunk of content.
+type TransferChunkRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Index                uint32   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferChunkRequest) Reset()         { *m = TransferChunkRequest{} }
+func (m *TransferChunkRequest) String() string { return proto.CompactTextString(m) }
+func (*TransferChunkRequest) ProtoMessage()    {}
+func (*TransferChunkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_transfer_89806cc6579a136e, []int{2}
+}
+func (m *TransferChunkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferChunkRequest.Unmarshal(m, b)
+}
+func (m *TransferChunkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferChunkRequest.Marshal(b, m, deterministic)
+}
+func (dst *TransferChunkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferChunkRequest.Merge(dst, src)
+}
+func (m *TransferChunkRequest) XXX_Size() int {
+	return xxx_messageInfo_TransferChunkRequest.Size(m)
+}
+func (m *TransferChunkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferChunkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferChunkRequest proto.InternalMessageInfo
+
+func (m *TransferChunkRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TransferChunkRequest) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type TransferChunk struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Index                uint32   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferChunk) Reset()         { *m = TransferChunk{} }
+func (m *TransferChunk) String() string { return proto.CompactTextString(m) }
+func (*TransferChunk) ProtoMessage()    {}
+func (*TransferChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_transfer_89806cc6579a136e, []int{3}
+}
+func (m *TransferChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferChunk.Unmarshal(m, b)
+}
+func (m *TransferChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferChunk.Marshal(b, m, deterministic)
+}
+func (dst *TransferChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferChunk.Merge(dst, src)
+}
+func (m *TransferChunk) XXX_Size() int {
+	return xxx_messageInfo_TransferChunk.Size(m)
+}
+func (m *TransferChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferChunk proto.InternalMessageInfo
+
+func (m *TransferChunk) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TransferChunk) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *TransferChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*TransferManifestRequest)(nil), "protobuf.TransferManifestRequest")
+	proto.RegisterType((*TransferManifest)(nil), "protobuf.TransferManifest")
+	proto.RegisterType((*TransferChunkRequest)(nil), "protobuf.TransferChunkRequest")
+	proto.RegisterType((*TransferChunk)(nil), "protobuf.TransferChunk")
+}
+
+func init() { proto.RegisterFile("protobuf/transfer.proto", fileDescriptor_transfer_89806cc6579a136e) }
+
+var fileDescriptor_transfer_89806cc6579a136e = []byte{
+	// 251 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x90, 0x41, 0x4b, 0xc4, 0x30,
+	0x10, 0x85, 0x89, 0xdb, 0x95, 0xdd, 0xb1, 0x05, 0x09, 0x8b, 0xdb, 0x8b, 0x10, 0x7b, 0x8a, 0x07,
+	0xeb, 0xc1, 0x3f, 0x20, 0xeb, 0xc5, 0xcb, 0xc2, 0x12, 0xbd, 0x4b, 0xd6, 0x4e, 0x6d, 0x50, 0x93,
+	0x35, 0x49, 0x41, 0xfc, 0x19, 0xfe, 0x62, 0xc9, 0xd4, 0x0a, 0x8a, 0x88, 0xb7, 0xf7, 0xbe, 0xf7,
+	0x3a, 0x9d, 0x09, 0x2c, 0x77, 0xde, 0x45, 0xb7, 0xed, 0xdb, 0xf3, 0xe8, 0xb5, 0x0d, 0x2d, 0xfa,
+	0x9a, 0x08, 0x9f, 0x8d, 0x41, 0x75, 0x06, 0xcb, 0xdb, 0xcf, 0x6c, 0xad, 0xad, 0x69, 0x31, 0x44,
+	0x85, 0x2f, 0x3d, 0x86, 0xc8, 0x39, 0x64, 0x56, 0x3f, 0x63, 0xc9, 0x04, 0x93, 0x73, 0x45, 0xba,
+	0x7a, 0x67, 0x70, 0xf8, 0xb3, 0xff, 0x5b, 0x31, 0xb1, 0x60, 0xde, 0xb0, 0xdc, 0x13, 0x4c, 0x66,
+	0x8a, 0x34, 0x3f, 0x06, 0xb8, 0xef, 0x7a, 0xfb, 0x78, 0x47, 0xc9, 0x44, 0x30, 0x59, 0xa8, 0x39,
+	0x91, 0x9b, 0x14, 0x9f, 0x40, 0x3e, 0xc4, 0x9d, 0x0e, 0x1d, 0x86, 0x32, 0x13, 0x13, 0x99, 0xab,
+	0x03, 0x62, 0xd7, 0x84, 0xf8, 0x02, 0xa6, 0xad, 0xeb, 0x6d, 0x53, 0x4e, 0x05, 0x93, 0x33, 0x35,
+	0x98, 0xea, 0x12, 0x16, 0xe3, 0x4e, 0x57, 0xa9, 0xfc, 0xc7, 0x01, 0x69, 0x82, 0xb1, 0x0d, 0xbe,
+	0xd2, 0x62, 0x85, 0x1a, 0x4c, 0xb5, 0x86, 0xe2, 0xdb, 0x84, 0xff, 0x7f, 0x9a, 0x9a, 0x8d, 0x8e,
+	0x9a, 0xce, 0xc9, 0x15, 0xe9, 0xd5, 0x29, 0x1c, 0x39, 0xff, 0x50, 0xef, 0xd0, 0x3f, 0x19, 0x5b,
+	0x5b, 0x67, 0x02, 0x0e, 0x0f, 0xbf, 0xfa, 0xfa, 0xcd, 0x26, 0xd9, 0x0d, 0xdb, 0xee, 0x13, 0xbf,
+	0xf8, 0x18, 0x00, 0x00, 0x13, 0x17, 0x9f, 0xab, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Cron is a schedule parsed from a standard five-field cron expression.
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+
+	// Whether the day of month and day of week fields are unrestricted.
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// ParseCron parses a cron expression of the form "minute hour day-of-month month
+// day-of-week". Fields may be *, a value, a range a-b, a list of them separated by
+// commas, and any of them followed by a step /n. Days of the week start at Sunday as 0.
+func ParseCron(spec string) (*Cron, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, errors.Errorf("cron expression %q must have %d fields", spec, len(cronFields))
+	}
+
+	var bits [5]uint64
+
+	for i, field := range fields {
+		var err error
+
+		if bits[i], err = parseCronField(field, cronFields[i]); err != nil {
+			return nil, errors.Wrapf(err, "invalid cron expression %q", spec)
+		}
+	}
+
+	return &Cron{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses a single field into a bitset of the values it matches.
+func parseCronField(field string, bounds cronField) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step in %s field %q", bounds.name, field)
+			}
+			part = part[:i]
+		}
+
+		low, high := bounds.min, bounds.max
+
+		if part != "*" {
+			values := strings.SplitN(part, "-", 2)
+
+			if low, err = strconv.Atoi(values[0]); err != nil {
+				return 0, errors.Errorf("invalid value in %s field %q", bounds.name, field)
+			}
+
+			high = low
+
+			if len(values) == 2 {
+				if high, err = strconv.Atoi(values[1]); err != nil {
+					return 0, errors.Errorf("invalid range in %s field %q", bounds.name, field)
+				}
+			}
+		}
+
+		if low < bounds.min || high > bounds.max || low > high {
+			return 0, errors.Errorf("%s field %q is out of range [%d, %d]", bounds.name, field, bounds.min, bounds.max)
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func matches(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
+
+// matchesDay returns true should t fall on a day matched by the cron expression. As
+// with cron, should both day fields be restricted, either may match.
+func (c *Cron) matchesDay(t time.Time) bool {
+	dom := matches(c.dom, t.Day())
+	dow := matches(c.dow, int(t.Weekday()))
+
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+// Next implements Schedule. The zero time is returned should the expression never
+// match within the next five years.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !matches(c.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !matches(c.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !matches(c.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,41 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron(t *testing.T) {
+	start := time.Date(2018, time.July, 30, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2018, time.July, 30, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2018, time.July, 30, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * *", time.Date(2018, time.July, 30, 11, 0, 0, 0, time.UTC)},
+		{"30 2 1 * *", time.Date(2018, time.August, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2018, time.August, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		cron, err := ParseCron(test.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if next := cron.Next(start); !next.Equal(test.next) {
+			t.Fatalf("expected %q to next fire at %s, got %s", test.spec, test.next, next)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Fatalf("expected %q to fail to parse", spec)
+		}
+	}
+}
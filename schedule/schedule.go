@@ -0,0 +1,26 @@
+package schedule
+
+import "time"
+
+// Schedule describes when a recurring task is due.
+type Schedule interface {
+	// Next returns the next time the task is due strictly after t.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a schedule recurring every interval. Intervals below a millisecond
+// are rounded up to a millisecond.
+func Every(interval time.Duration) Schedule {
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+
+	return every(interval)
+}
+
+type every time.Duration
+
+// Next implements Schedule.
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
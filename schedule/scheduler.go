@@ -0,0 +1,250 @@
+// Package schedule runs one-off and recurring tasks off of a single timer goroutine, so
+// that delayed and periodic work need not each spawn a goroutine which sleeps.
+package schedule
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+)
+
+// Task is a handle to a function scheduled to run at a later time.
+type Task struct {
+	at time.Time
+	fn func()
+
+	// every is when the task recurs. Nil if the task runs once.
+	every Schedule
+
+	// index of the task within its schedulers queue, or -1 should it not be queued.
+	index     int
+	cancelled bool
+
+	// running is whether the task is being run, such that a recurring task which runs
+	// for longer than it recurs is not run over itself.
+	running bool
+
+	scheduler *Scheduler
+}
+
+// Cancel stops the task from running again. It returns false should the task have
+// already run once-off or been cancelled.
+func (t *Task) Cancel() bool {
+	s := t.scheduler
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if t.cancelled || (t.index < 0 && t.every == nil) {
+		return false
+	}
+
+	t.cancelled = true
+
+	if t.index >= 0 {
+		heap.Remove(&s.queue, t.index)
+	}
+
+	return true
+}
+
+// taskQueue is a min-heap of tasks ordered by when they are due.
+type taskQueue []*Task
+
+func (q taskQueue) Len() int           { return len(q) }
+func (q taskQueue) Less(i, j int) bool { return q[i].at.Before(q[j].at) }
+
+func (q taskQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *taskQueue) Push(x interface{}) {
+	task := x.(*Task)
+	task.index = len(*q)
+	*q = append(*q, task)
+}
+
+func (q *taskQueue) Pop() interface{} {
+	old := *q
+	task := old[len(old)-1]
+	old[len(old)-1] = nil
+	task.index = -1
+	*q = old[:len(old)-1]
+	return task
+}
+
+// Scheduler runs tasks once they are due.
+type Scheduler struct {
+	mutex sync.Mutex
+	queue taskQueue
+
+	// wake is signalled should the earliest due task change.
+	wake chan struct{}
+
+	clock clock.Clock
+
+	// spawn runs a due task in a goroutine of its own.
+	spawn func(fn func())
+}
+
+// New creates a scheduler reading time off of a clock. A nil clock defaults to the
+// system clock.
+func New(c clock.Clock) *Scheduler {
+	return NewSpawning(c, nil)
+}
+
+// NewSpawning creates a scheduler reading time off of a clock, which runs due tasks
+// in goroutines started by spawn. A nil spawn starts them with the go statement.
+func NewSpawning(c clock.Clock, spawn func(fn func())) *Scheduler {
+	if spawn == nil {
+		spawn = func(fn func()) { go fn() }
+	}
+
+	return &Scheduler{
+		wake:  make(chan struct{}, 1),
+		clock: clock.Or(c),
+		spawn: spawn,
+	}
+}
+
+// push queues a task, waking the scheduler should it now be the earliest due.
+func (s *Scheduler) push(task *Task) {
+	s.mutex.Lock()
+	earliest := s.pushLocked(task)
+	s.mutex.Unlock()
+
+	if earliest {
+		s.signal()
+	}
+}
+
+// pushLocked queues a task, returning true should it now be the earliest due.
+func (s *Scheduler) pushLocked(task *Task) bool {
+	heap.Push(&s.queue, task)
+	return task.index == 0
+}
+
+// signal wakes the scheduler to recheck when the earliest task is due.
+func (s *Scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// At schedules fn to run once at a given time.
+func (s *Scheduler) At(at time.Time, fn func()) *Task {
+	task := &Task{at: at, fn: fn, scheduler: s}
+	s.push(task)
+
+	return task
+}
+
+// After schedules fn to run once after a delay.
+func (s *Scheduler) After(delay time.Duration, fn func()) *Task {
+	return s.At(s.clock.Now().Add(delay), fn)
+}
+
+// Repeat schedules fn to run every time a schedule next fires, until cancelled or
+// the schedule returns the zero time.
+func (s *Scheduler) Repeat(every Schedule, fn func()) *Task {
+	task := &Task{at: every.Next(s.clock.Now()), fn: fn, every: every, index: -1, scheduler: s}
+
+	if !task.at.IsZero() {
+		s.push(task)
+	}
+
+	return task
+}
+
+// next pops all tasks which are due, and otherwise returns how long until the next
+// task is due. Zero if there are no tasks scheduled.
+func (s *Scheduler) next() (due []*Task, wait time.Duration) {
+	now := s.clock.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for len(s.queue) > 0 {
+		if wait = s.queue[0].at.Sub(now); wait > 0 {
+			return
+		}
+
+		due = append(due, heap.Pop(&s.queue).(*Task))
+	}
+
+	return due, 0
+}
+
+// dispatch queues a recurring task for the next time it is due, unless it has been
+// cancelled, and runs the task in a goroutine of its own. A recurring task still
+// running from the last time it was due is skipped.
+func (s *Scheduler) dispatch(task *Task) {
+	s.mutex.Lock()
+
+	if task.every != nil && !task.cancelled {
+		if task.at = task.every.Next(s.clock.Now()); !task.at.IsZero() {
+			s.pushLocked(task)
+		}
+	}
+
+	if task.running {
+		s.mutex.Unlock()
+		return
+	}
+
+	task.running = true
+	s.mutex.Unlock()
+
+	s.spawn(func() {
+		defer func() {
+			s.mutex.Lock()
+			task.running = false
+			s.mutex.Unlock()
+		}()
+
+		task.fn()
+	})
+}
+
+// Run runs tasks as they become due until kill is closed. Each task is run in a
+// goroutine of its own, such that tasks which block do not hold back others.
+func (s *Scheduler) Run(kill <-chan struct{}) {
+	for {
+		due, wait := s.next()
+
+		for _, task := range due {
+			s.dispatch(task)
+		}
+
+		if len(due) > 0 {
+			continue
+		}
+
+		var timer clock.Timer
+		var timeout <-chan time.Time
+
+		if wait > 0 {
+			timer = s.clock.NewTimer(wait)
+			timeout = timer.C()
+		}
+
+		select {
+		case <-kill:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-s.wake:
+		case <-timeout:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+)
+
+// advance lets the scheduler wait upon its next task before advancing the clock.
+func advance(mock *clock.Mock, d time.Duration) {
+	time.Sleep(50 * time.Millisecond)
+	mock.Add(d)
+}
+
+func TestScheduler(t *testing.T) {
+	mock := clock.NewMock(time.Unix(0, 0))
+
+	s := New(mock)
+
+	kill := make(chan struct{})
+	defer close(kill)
+
+	go s.Run(kill)
+
+	ran := make(chan int, 3)
+
+	s.After(2*time.Second, func() { ran <- 2 })
+	s.After(1*time.Second, func() { ran <- 1 })
+	cancelled := s.After(1*time.Second, func() { ran <- 3 })
+
+	if !cancelled.Cancel() {
+		t.Fatal("expected pending task to be cancelled")
+	}
+
+	if cancelled.Cancel() {
+		t.Fatal("expected cancelled task to not be cancelled twice")
+	}
+
+	for i := 1; i <= 2; i++ {
+		advance(mock, time.Second)
+
+		select {
+		case order := <-ran:
+			if order != i {
+				t.Fatalf("expected task %d to run, got task %d", i, order)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for task %d", i)
+		}
+	}
+
+	select {
+	case order := <-ran:
+		t.Fatalf("expected cancelled task to never run, got task %d", order)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	mock := clock.NewMock(time.Unix(0, 0))
+
+	s := New(mock)
+
+	kill := make(chan struct{})
+	defer close(kill)
+
+	go s.Run(kill)
+
+	ran := make(chan struct{}, 8)
+
+	task := s.Repeat(Every(time.Second), func() { ran <- struct{}{} })
+
+	for i := 0; i < 3; i++ {
+		advance(mock, time.Second)
+
+		select {
+		case <-ran:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for run %d", i)
+		}
+	}
+
+	if !task.Cancel() {
+		t.Fatal("expected recurring task to be cancelled")
+	}
+
+	advance(mock, time.Second)
+
+	select {
+	case <-ran:
+		t.Fatal("expected cancelled recurring task to stop running")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBlockingTask(t *testing.T) {
+	mock := clock.NewMock(time.Unix(0, 0))
+
+	s := New(mock)
+
+	kill := make(chan struct{})
+	defer close(kill)
+
+	go s.Run(kill)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	blocked := make(chan struct{}, 8)
+	ran := make(chan struct{}, 1)
+
+	s.Repeat(Every(time.Second), func() {
+		blocked <- struct{}{}
+		<-release
+	})
+	s.After(2*time.Second, func() { ran <- struct{}{} })
+
+	advance(mock, time.Second)
+	advance(mock, time.Second)
+
+	// The task due after the blocking one still runs, and the blocking one is not run
+	// over itself while it blocks.
+	select {
+	case <-ran:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for task to run past a blocking task")
+	}
+
+	if len(blocked) != 1 {
+		t.Fatalf("expected blocking recurring task to run once, ran %d times", len(blocked))
+	}
+}
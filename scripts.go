@@ -1,3 +1,3 @@
-//go:generate protoc --go_out=. protobuf/stream.proto
+//go:generate protoc --go_out=. protobuf/envelope.proto protobuf/ping.proto protobuf/dht.proto protobuf/pubsub.proto protobuf/transfer.proto protobuf/statesync.proto protobuf/inventory.proto protobuf/signer.proto
 
 package main
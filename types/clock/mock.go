@@ -0,0 +1,125 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Mock is a clock which only advances when told to. Timers fire once the clock is
+// advanced past their deadline.
+type Mock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*mockTimer
+}
+
+type mockTimer struct {
+	mock     *Mock
+	deadline time.Time
+	c        chan time.Time
+}
+
+// NewMock creates a mock clock starting at a given time.
+func NewMock(start time.Time) *Mock {
+	return &Mock{now: start}
+}
+
+// Now implements Clock.
+func (m *Mock) Now() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.now
+}
+
+// Since implements Clock.
+func (m *Mock) Since(t time.Time) time.Duration {
+	return m.Now().Sub(t)
+}
+
+// After implements Clock.
+func (m *Mock) After(d time.Duration) <-chan time.Time {
+	return m.NewTimer(d).C()
+}
+
+// Sleep implements Clock by blocking until the clock is advanced by d.
+func (m *Mock) Sleep(d time.Duration) {
+	<-m.After(d)
+}
+
+// NewTimer implements Clock.
+func (m *Mock) NewTimer(d time.Duration) Timer {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	timer := &mockTimer{mock: m, deadline: m.now.Add(d), c: make(chan time.Time, 1)}
+
+	if d <= 0 {
+		timer.c <- m.now
+	} else {
+		m.timers = append(m.timers, timer)
+	}
+
+	return timer
+}
+
+// Add advances the clock by d, firing all timers whose deadlines have passed in order.
+func (m *Mock) Add(d time.Duration) {
+	m.mutex.Lock()
+	m.now = m.now.Add(d)
+	m.fire()
+	m.mutex.Unlock()
+}
+
+// Set sets the clock to t, firing all timers whose deadlines have passed in order.
+func (m *Mock) Set(t time.Time) {
+	m.mutex.Lock()
+	m.now = t
+	m.fire()
+	m.mutex.Unlock()
+}
+
+// Pending returns the number of timers which have yet to fire.
+func (m *Mock) Pending() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.timers)
+}
+
+// fire fires all expired timers. Must be called with the mutex held.
+func (m *Mock) fire() {
+	sort.SliceStable(m.timers, func(i, j int) bool {
+		return m.timers[i].deadline.Before(m.timers[j].deadline)
+	})
+
+	fired := 0
+	for _, timer := range m.timers {
+		if timer.deadline.After(m.now) {
+			break
+		}
+		timer.c <- m.now
+		fired++
+	}
+
+	m.timers = m.timers[fired:]
+}
+
+func (t *mockTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *mockTimer) Stop() bool {
+	t.mock.mutex.Lock()
+	defer t.mock.mutex.Unlock()
+
+	for i, timer := range t.mock.timers {
+		if timer == t {
+			t.mock.timers = append(t.mock.timers[:i], t.mock.timers[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
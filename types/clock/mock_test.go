@@ -0,0 +1,54 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMock(t *testing.T) {
+	start := time.Unix(0, 0)
+	mock := NewMock(start)
+
+	first := mock.NewTimer(1 * time.Second)
+	second := mock.After(2 * time.Second)
+	stopped := mock.NewTimer(1 * time.Second)
+
+	if !stopped.Stop() {
+		t.Fatal("expected pending timer to be stopped")
+	}
+
+	mock.Add(1 * time.Second)
+
+	select {
+	case now := <-first.C():
+		if !now.Equal(start.Add(1 * time.Second)) {
+			t.Fatalf("timer fired at the wrong time %s", now)
+		}
+	default:
+		t.Fatal("expected timer to have fired")
+	}
+
+	select {
+	case <-second:
+		t.Fatal("expected timer to not have fired yet")
+	case <-stopped.C():
+		t.Fatal("expected stopped timer to not fire")
+	default:
+	}
+
+	mock.Add(1 * time.Second)
+
+	select {
+	case <-second:
+	default:
+		t.Fatal("expected timer to have fired")
+	}
+
+	if mock.Pending() != 0 {
+		t.Fatal("expected no pending timers")
+	}
+
+	if mock.Since(start) != 2*time.Second {
+		t.Fatal("expected clock to have advanced by 2s")
+	}
+}
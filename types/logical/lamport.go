@@ -0,0 +1,42 @@
+package logical
+
+import "sync/atomic"
+
+// Lamport is a concurrent-safe Lamport logical clock.
+type Lamport struct {
+	time uint64
+}
+
+// NewLamport instantiates a new Lamport clock starting at time zero.
+func NewLamport() *Lamport {
+	return &Lamport{}
+}
+
+// Time returns the current time of the clock.
+func (l *Lamport) Time() uint64 {
+	return atomic.LoadUint64(&l.time)
+}
+
+// Increment advances the clock by one tick for a local event, and returns
+// the new time.
+func (l *Lamport) Increment() uint64 {
+	return atomic.AddUint64(&l.time, 1)
+}
+
+// Witness advances the clock past a time observed from a remote event, and
+// returns the new time.
+func (l *Lamport) Witness(remote uint64) uint64 {
+	for {
+		local := atomic.LoadUint64(&l.time)
+
+		next := local
+		if remote > next {
+			next = remote
+		}
+		next++
+
+		if atomic.CompareAndSwapUint64(&l.time, local, next) {
+			return next
+		}
+	}
+}
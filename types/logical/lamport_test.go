@@ -0,0 +1,49 @@
+package logical
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLamportIncrement(t *testing.T) {
+	clock := NewLamport()
+
+	if clock.Time() != 0 {
+		t.Fatalf("expected new clock to start at 0, got %d", clock.Time())
+	}
+
+	if clock.Increment() != 1 || clock.Increment() != 2 {
+		t.Fatalf("expected increments to yield 1 then 2, clock is at %d", clock.Time())
+	}
+}
+
+func TestLamportWitness(t *testing.T) {
+	clock := NewLamport()
+	clock.Increment()
+
+	if time := clock.Witness(10); time != 11 {
+		t.Fatalf("expected witnessing a later time to yield 11, got %d", time)
+	}
+
+	if time := clock.Witness(3); time != 12 {
+		t.Fatalf("expected witnessing an earlier time to yield 12, got %d", time)
+	}
+}
+
+func TestLamportConcurrent(t *testing.T) {
+	clock := NewLamport()
+
+	var wait sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			clock.Increment()
+		}()
+	}
+	wait.Wait()
+
+	if clock.Time() != 100 {
+		t.Fatalf("expected clock to be at 100, got %d", clock.Time())
+	}
+}
@@ -0,0 +1,113 @@
+package logical
+
+import (
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// Ordering describes how two vector clocks relate causally to each other.
+type Ordering int
+
+const (
+	// Equal denotes two vector clocks which are identical.
+	Equal Ordering = iota
+
+	// Before denotes a vector clock which happened before another.
+	Before
+
+	// After denotes a vector clock which happened after another.
+	After
+
+	// Concurrent denotes two vector clocks which are causally unrelated.
+	Concurrent
+)
+
+func (o Ordering) String() string {
+	switch o {
+	case Equal:
+		return "equal"
+	case Before:
+		return "before"
+	case After:
+		return "after"
+	default:
+		return "concurrent"
+	}
+}
+
+// Vector is a vector clock keyed by a peer's hex-encoded public key. It is not
+// concurrent-safe.
+type Vector map[string]uint64
+
+// NewVector instantiates a new, empty vector clock.
+func NewVector() Vector {
+	return make(Vector)
+}
+
+// Increment advances the entry of a given key by one tick, and returns
+// the new value.
+func (v Vector) Increment(key string) uint64 {
+	v[key]++
+	return v[key]
+}
+
+// Merge folds another vector clock into this one by taking the maximum of
+// each entry.
+func (v Vector) Merge(other Vector) {
+	for key, value := range other {
+		if value > v[key] {
+			v[key] = value
+		}
+	}
+}
+
+// Compare determines the causal ordering of this vector clock w.r.t. another.
+func (v Vector) Compare(other Vector) Ordering {
+	before, after := false, false
+
+	for key, value := range v {
+		if value > other[key] {
+			after = true
+		} else if value < other[key] {
+			before = true
+		}
+	}
+
+	for key, value := range other {
+		if _, exists := v[key]; !exists && value > 0 {
+			before = true
+		}
+	}
+
+	switch {
+	case before && after:
+		return Concurrent
+	case before:
+		return Before
+	case after:
+		return After
+	default:
+		return Equal
+	}
+}
+
+// Copy returns a deep copy of the vector clock.
+func (v Vector) Copy() Vector {
+	copied := make(Vector, len(v))
+	for key, value := range v {
+		copied[key] = value
+	}
+	return copied
+}
+
+// Proto encodes the vector clock into its protobuf representation.
+func (v Vector) Proto() *protobuf.VectorClock {
+	return &protobuf.VectorClock{Clock: v.Copy()}
+}
+
+// VectorFromProto decodes a vector clock from its protobuf representation.
+func VectorFromProto(msg *protobuf.VectorClock) Vector {
+	if msg == nil {
+		return NewVector()
+	}
+	return Vector(msg.Clock).Copy()
+}
@@ -0,0 +1,59 @@
+package logical
+
+import (
+	"testing"
+)
+
+func TestVectorCompare(t *testing.T) {
+	a := NewVector()
+	b := NewVector()
+
+	if a.Compare(b) != Equal {
+		t.Fatalf("expected empty clocks to be equal, got %s", a.Compare(b))
+	}
+
+	a.Increment("a")
+
+	if a.Compare(b) != After || b.Compare(a) != Before {
+		t.Fatalf("expected a after b, got %s and %s", a.Compare(b), b.Compare(a))
+	}
+
+	b.Increment("b")
+
+	if a.Compare(b) != Concurrent {
+		t.Fatalf("expected a and b to be concurrent, got %s", a.Compare(b))
+	}
+
+	b.Merge(a)
+	b.Increment("b")
+
+	if a.Compare(b) != Before {
+		t.Fatalf("expected a before b after merging, got %s", a.Compare(b))
+	}
+}
+
+func TestVectorMerge(t *testing.T) {
+	a := Vector{"a": 3, "b": 1}
+	b := Vector{"b": 4, "c": 2}
+
+	a.Merge(b)
+
+	expected := Vector{"a": 3, "b": 4, "c": 2}
+	if a.Compare(expected) != Equal {
+		t.Fatalf("expected %v, got %v", expected, a)
+	}
+}
+
+func TestVectorProto(t *testing.T) {
+	a := Vector{"a": 3, "b": 1}
+
+	decoded := VectorFromProto(a.Proto())
+	if a.Compare(decoded) != Equal {
+		t.Fatalf("expected %v, got %v", a, decoded)
+	}
+
+	decoded.Increment("a")
+	if a["a"] != 3 {
+		t.Fatal("expected decoded vector clock to be a copy")
+	}
+}
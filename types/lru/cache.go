@@ -39,13 +39,6 @@ func (c *Cache) Get(key string, init func() (interface{}, error)) (interface{},
 		return item.value, nil
 	}
 
-	// Evict least recently used.
-	if c.order.Len() >= c.limit {
-		// Pop last element.
-		item := c.order.Remove(c.order.Back()).(*cacheItem)
-		delete(c.items, item.key)
-	}
-
 	// If key does not exist, push it to the front.
 	value, err := init()
 	if err != nil {
@@ -53,6 +46,13 @@ func (c *Cache) Get(key string, init func() (interface{}, error)) (interface{},
 		return nil, err
 	}
 
+	// Evict least recently used.
+	if c.order.Len() >= c.limit {
+		// Pop last element.
+		item := c.order.Remove(c.order.Back()).(*cacheItem)
+		delete(c.items, item.key)
+	}
+
 	item := &cacheItem{key: key, value: value}
 	item.element = c.order.PushFront(item)
 	c.items[key] = item
@@ -60,3 +60,27 @@ func (c *Cache) Get(key string, init func() (interface{}, error)) (interface{},
 	c.mutex.Unlock()
 	return item.value, nil
 }
+
+// Load returns the value cached for a key, marking it as used. The second returning
+// parameter is false should the key not be cached, in which case nothing is evicted.
+func (c *Cache) Load(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, exists := c.items[key]
+	if !exists {
+		return nil, false
+	}
+
+	c.order.MoveToFront(item.element)
+	return item.value, true
+}
+
+// Contains returns true should a key be cached, without marking it as used.
+func (c *Cache) Contains(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	_, exists := c.items[key]
+	return exists
+}
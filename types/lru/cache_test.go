@@ -77,3 +77,31 @@ func TestUnusedEntryDeleting(t *testing.T) {
 		t.Fatalf("deleting error")
 	}
 }
+
+func TestContains(t *testing.T) {
+	cache := NewCache(1)
+
+	if cache.Contains("mykey") {
+		t.Fatal("expected empty cache to not contain key")
+	}
+
+	cache.Get("mykey", emptyFunc)
+
+	if !cache.Contains("mykey") {
+		t.Fatal("expected cache to contain key")
+	}
+}
+func TestLoad(t *testing.T) {
+	cache := NewCache(1)
+	cache.Get("mykey1", func() (interface{}, error) {
+		return "mydata1", nil
+	})
+
+	if _, exists := cache.Load("mykey2"); exists {
+		t.Fatalf("expected missing key to not be loaded")
+	}
+
+	if data, exists := cache.Load("mykey1"); !exists || data != "mydata1" {
+		t.Fatalf("expected a miss to not evict other keys, got %v", data)
+	}
+}
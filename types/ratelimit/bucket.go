@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+)
+
+// Bucket is a concurrent-safe token bucket which refills at a constant rate up to
+// a maximum burst size.
+type Bucket struct {
+	mutex sync.Mutex
+
+	rate  float64
+	burst float64
+
+	tokens  float64
+	updated time.Time
+
+	clock clock.Clock
+}
+
+// NewBucket creates a new, full token bucket refilling at rate tokens per second
+// and holding at most burst tokens, as measured by a clock. The real clock is used
+// should it be nil.
+func NewBucket(rate float64, burst float64, c clock.Clock) *Bucket {
+	c = clock.Or(c)
+
+	return &Bucket{
+		rate:    rate,
+		burst:   burst,
+		tokens:  burst,
+		updated: c.Now(),
+		clock:   c,
+	}
+}
+
+// refill tops up the bucket w.r.t. the time elapsed since it was last refilled.
+func (b *Bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.updated = now
+}
+
+// Allow takes n tokens from the bucket should there be enough, and returns true
+// if the tokens were taken.
+func (b *Bucket) Allow(n float64) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill(b.clock.Now())
+
+	if b.tokens < n {
+		return false
+	}
+
+	b.tokens -= n
+	return true
+}
+
+// Reserve takes n tokens from the bucket regardless of whether there are enough,
+// and returns how long the caller should wait until the tokens are paid off.
+func (b *Bucket) Reserve(n float64) time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill(b.clock.Now())
+
+	b.tokens -= n
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait blocks until n tokens may be taken from the bucket, and takes them.
+func (b *Bucket) Wait(n float64) {
+	if delay := b.Reserve(n); delay > 0 {
+		b.clock.Sleep(delay)
+	}
+}
+
+// Full returns true should the bucket be filled up to its burst size.
+func (b *Bucket) Full() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill(b.clock.Now())
+
+	return b.tokens >= b.burst
+}
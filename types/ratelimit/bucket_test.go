@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+)
+
+func TestBucketAllow(t *testing.T) {
+	clk := clock.NewMock(time.Now())
+	bucket := NewBucket(10, 2, clk)
+
+	if !bucket.Allow(1) || !bucket.Allow(1) {
+		t.Fatal("expected a full bucket to allow its burst")
+	}
+
+	if bucket.Allow(1) {
+		t.Fatal("expected an empty bucket to disallow tokens being taken")
+	}
+
+	clk.Add(150 * time.Millisecond)
+
+	if !bucket.Allow(1) {
+		t.Fatal("expected bucket to have refilled")
+	}
+}
+
+func TestBucketReserve(t *testing.T) {
+	bucket := NewBucket(10, 1, nil)
+
+	if delay := bucket.Reserve(1); delay != 0 {
+		t.Fatalf("expected no delay from a full bucket, got %s", delay)
+	}
+
+	if delay := bucket.Reserve(1); delay < 90*time.Millisecond || delay > 100*time.Millisecond {
+		t.Fatalf("expected a delay of ~100ms, got %s", delay)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(1, 1, nil)
+
+	if !limiter.Allow("a", 1) || limiter.Allow("a", 1) {
+		t.Fatal("expected key a to be limited to a single token")
+	}
+
+	if !limiter.Allow("b", 1) {
+		t.Fatal("expected key b to be unaffected by key a")
+	}
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"sync"
+
+	"github.com/perlin-network/noise/types/clock"
+)
+
+// Limiter is a concurrent-safe set of token buckets keyed by a string, such as
+// a peer's public key. Buckets which have fully refilled are evicted to bound
+// memory usage.
+type Limiter struct {
+	mutex sync.Mutex
+
+	rate  float64
+	burst float64
+
+	buckets map[string]*Bucket
+
+	// Number of buckets to hold before evicting fully refilled buckets.
+	limit int
+
+	clock clock.Clock
+}
+
+// NewLimiter creates a new keyed limiter whose buckets refill at rate tokens per
+// second and hold at most burst tokens, as measured by a clock. The real clock is
+// used should it be nil.
+func NewLimiter(rate float64, burst float64, c clock.Clock) *Limiter {
+	return &Limiter{
+		rate:    rate,
+		burst:   burst,
+		buckets: make(map[string]*Bucket),
+		limit:   1024,
+		clock:   c,
+	}
+}
+
+// Bucket returns the token bucket for a given key, creating it should it not exist.
+func (l *Limiter) Bucket(key string) *Bucket {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	bucket, exists := l.buckets[key]
+	if !exists {
+		if len(l.buckets) >= l.limit {
+			l.evict()
+		}
+
+		bucket = NewBucket(l.rate, l.burst, l.clock)
+		l.buckets[key] = bucket
+	}
+
+	return bucket
+}
+
+// Allow takes n tokens from the bucket of a given key should there be enough,
+// and returns true if the tokens were taken.
+func (l *Limiter) Allow(key string, n float64) bool {
+	return l.Bucket(key).Allow(n)
+}
+
+// evict removes all buckets which have fully refilled, as they are indistinguishable
+// from newly created buckets. Doubles the eviction threshold should nothing be evicted.
+func (l *Limiter) evict() {
+	for key, bucket := range l.buckets {
+		if bucket.Full() {
+			delete(l.buckets, key)
+		}
+	}
+
+	if len(l.buckets) >= l.limit {
+		l.limit *= 2
+	}
+}
@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+)
+
+// Decay returns a value decayed over elapsed time, such that it halves every half-life.
+func Decay(value float64, elapsed, halfLife time.Duration) float64 {
+	if elapsed <= 0 || halfLife <= 0 {
+		return value
+	}
+	return value * math.Exp2(-float64(elapsed)/float64(halfLife))
+}
+
+// DecayingCounter is a concurrent-safe counter whose value halves every half-life,
+// such as a score which misbehaviour is forgiven from over time.
+type DecayingCounter struct {
+	mutex sync.Mutex
+	clock clock.Clock
+
+	halfLife time.Duration
+
+	value   float64
+	updated time.Time
+}
+
+// NewDecayingCounter creates a new counter of zero halving every half-life, as
+// measured by a clock. The real clock is used should it be nil.
+func NewDecayingCounter(halfLife time.Duration, c clock.Clock) *DecayingCounter {
+	c = clock.Or(c)
+
+	return &DecayingCounter{
+		clock:    c,
+		halfLife: halfLife,
+		updated:  c.Now(),
+	}
+}
+
+// decay decays the value w.r.t. the time elapsed since it was last decayed. Must be
+// called with the mutex held.
+func (d *DecayingCounter) decay() {
+	now := d.clock.Now()
+
+	d.value = Decay(d.value, now.Sub(d.updated), d.halfLife)
+	d.updated = now
+}
+
+// Add adds n to the counter, and returns its value.
+func (d *DecayingCounter) Add(n float64) float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.decay()
+	d.value += n
+
+	return d.value
+}
+
+// Value returns the value of the counter.
+func (d *DecayingCounter) Value() float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.decay()
+
+	return d.value
+}
+
+// Reset resets the counter to zero.
+func (d *DecayingCounter) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.value = 0
+	d.updated = d.clock.Now()
+}
+
+// Meter is a concurrent-safe estimate of the rate of events per second, such as of
+// bytes sent or messages received, averaged such that events a half-life ago weigh
+// half as much as events now.
+type Meter struct {
+	counter *DecayingCounter
+}
+
+// NewMeter creates a new meter averaging rates over a half-life, as measured by a
+// clock. The real clock is used should it be nil.
+func NewMeter(halfLife time.Duration, c clock.Clock) *Meter {
+	return &Meter{counter: NewDecayingCounter(halfLife, c)}
+}
+
+// Mark records n events.
+func (m *Meter) Mark(n float64) {
+	m.counter.Add(n)
+}
+
+// Rate returns the estimated rate of events per second. A steady rate of events is
+// converged to within a few half-lives.
+func (m *Meter) Rate() float64 {
+	// Events marked at a steady rate accumulate to the rate times the mean lifetime
+	// of the counter's decay.
+	return m.counter.Value() * math.Ln2 / m.counter.halfLife.Seconds()
+}
@@ -0,0 +1,79 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/types/clock"
+)
+
+func TestDecay(t *testing.T) {
+	if value := Decay(8, 2*time.Second, time.Second); value != 2 {
+		t.Fatalf("expected value to halve twice, got %f", value)
+	}
+
+	if value := Decay(8, -time.Second, time.Second); value != 8 {
+		t.Fatalf("expected value not to decay backwards in time, got %f", value)
+	}
+
+	if value := Decay(8, time.Second, 0); value != 8 {
+		t.Fatalf("expected value not to decay without a half-life, got %f", value)
+	}
+}
+
+func TestDecayingCounter(t *testing.T) {
+	c := clock.NewMock(time.Unix(0, 0))
+	counter := NewDecayingCounter(time.Minute, c)
+
+	if value := counter.Add(10); value != 10 {
+		t.Fatalf("expected value of 10, got %f", value)
+	}
+
+	c.Add(time.Minute)
+
+	if value := counter.Value(); value != 5 {
+		t.Fatalf("expected value to halve after a half-life, got %f", value)
+	}
+
+	if value := counter.Add(-1); value != 4 {
+		t.Fatalf("expected value of 4, got %f", value)
+	}
+
+	c.Add(30 * time.Second)
+
+	if value := counter.Value(); math.Abs(value-4/math.Sqrt2) > 1e-9 {
+		t.Fatalf("expected value to decay continuously, got %f", value)
+	}
+
+	counter.Reset()
+
+	if value := counter.Value(); value != 0 {
+		t.Fatalf("expected value to be reset, got %f", value)
+	}
+}
+
+func TestMeter(t *testing.T) {
+	c := clock.NewMock(time.Unix(0, 0))
+	meter := NewMeter(time.Second, c)
+
+	if rate := meter.Rate(); rate != 0 {
+		t.Fatalf("expected no rate without events, got %f", rate)
+	}
+
+	// Mark 100 events per second for long enough to converge.
+	for i := 0; i < 2000; i++ {
+		c.Add(10 * time.Millisecond)
+		meter.Mark(1)
+	}
+
+	if rate := meter.Rate(); math.Abs(rate-100) > 1 {
+		t.Fatalf("expected rate of 100 per second, got %f", rate)
+	}
+
+	c.Add(time.Second)
+
+	if rate := meter.Rate(); math.Abs(rate-50) > 1 {
+		t.Fatalf("expected rate to halve after a half-life without events, got %f", rate)
+	}
+}
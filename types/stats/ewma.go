@@ -0,0 +1,57 @@
+// Package stats provides exponentially decaying statistics, such as moving averages of
+// round-trip times, decaying scores and rates of traffic, such that subsystems
+// estimating them do so consistently.
+package stats
+
+import "sync"
+
+// Smooth returns the exponentially weighted moving average of a series updated with
+// a sample, the sample being weighted by alpha within (0, 1].
+func Smooth(average, sample, alpha float64) float64 {
+	return average + alpha*(sample-average)
+}
+
+// EWMA is a concurrent-safe exponentially weighted moving average of samples. The
+// first sample seeds the average.
+type EWMA struct {
+	mutex sync.Mutex
+
+	alpha  float64
+	value  float64
+	seeded bool
+}
+
+// NewEWMA creates a new moving average weighting each sample by alpha within (0, 1],
+// such that higher alphas discount older samples faster.
+func NewEWMA(alpha float64) *EWMA {
+	return &EWMA{alpha: alpha}
+}
+
+// Add updates the average with a sample.
+func (e *EWMA) Add(sample float64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if !e.seeded {
+		e.value, e.seeded = sample, true
+		return
+	}
+
+	e.value = Smooth(e.value, sample, e.alpha)
+}
+
+// Value returns the average. Zero if no samples were added.
+func (e *EWMA) Value() float64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.value
+}
+
+// Seeded returns true should any samples have been added.
+func (e *EWMA) Seeded() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.seeded
+}
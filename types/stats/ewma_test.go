@@ -0,0 +1,45 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSmooth(t *testing.T) {
+	if average := Smooth(10, 20, 0.25); average != 12.5 {
+		t.Fatalf("expected average of 12.5, got %f", average)
+	}
+
+	if average := Smooth(10, 20, 1); average != 20 {
+		t.Fatalf("expected an alpha of 1 to take the sample, got %f", average)
+	}
+}
+
+func TestEWMA(t *testing.T) {
+	ewma := NewEWMA(0.5)
+
+	if ewma.Seeded() || ewma.Value() != 0 {
+		t.Fatal("expected a new average to be zero and unseeded")
+	}
+
+	ewma.Add(8)
+
+	if !ewma.Seeded() || ewma.Value() != 8 {
+		t.Fatalf("expected the first sample to seed the average, got %f", ewma.Value())
+	}
+
+	ewma.Add(4)
+
+	if ewma.Value() != 6 {
+		t.Fatalf("expected average of 6, got %f", ewma.Value())
+	}
+
+	// A constant series is converged to.
+	for i := 0; i < 64; i++ {
+		ewma.Add(100)
+	}
+
+	if math.Abs(ewma.Value()-100) > 1e-9 {
+		t.Fatalf("expected average to converge to 100, got %f", ewma.Value())
+	}
+}